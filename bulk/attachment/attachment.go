@@ -0,0 +1,302 @@
+// Package attachment assembles the zip batches of the bulk API 1.0 binary
+// attachment loads.  A zip batch has a request.txt manifest, which has a record
+// for each attachment, and the binary files that the records refer to.  The
+// bulk API 1.0 jobs are created with CreateJob, which can PK chunk the query
+// jobs.
+package attachment
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/namely/go-sfdc/v3/bulk"
+)
+
+// MaxZipBytes is the limit of a zipped batch.
+const MaxZipBytes = 10 * 1024 * 1024
+
+// ManifestName is the name of the manifest in the zip batch.
+const ManifestName = "request.txt"
+
+// BodyField is the field of the manifest record that refers to the binary
+// file, with the file name prefixed by #.
+const BodyField = "Body"
+
+// Format is the format of the manifest.
+type Format string
+
+const (
+	// CSV is the CSV manifest of a zip/csv job.
+	CSV Format = "CSV"
+	// JSON is the JSON manifest of a zip/json job.
+	JSON Format = "JSON"
+)
+
+// ContentType returns the content type of the zip batch request for the
+// format, zip/csv or zip/json.
+func (f Format) ContentType() string {
+	return "zip/" + strings.ToLower(string(f))
+}
+
+// FormatOf returns the manifest format of the job's content type, like the
+// content type of a bulk 1.0 job in the job listing.  ZIP_CSV and ZIP_JSON are
+// the formats of the zip batches.  ZIP_XML and the content types that are not
+// zipped are an error.
+func FormatOf(contentType bulk.ContentType) (Format, error) {
+	switch contentType {
+	case bulk.ZipCSV:
+		return CSV, nil
+	case bulk.ZipJSON:
+		return JSON, nil
+	case bulk.ZipXML:
+		return "", fmt.Errorf("attachment job: content type %s is not supported, the manifest must be CSV or JSON", contentType)
+	default:
+		return "", fmt.Errorf("attachment job: content type %q is not a zip content type", contentType)
+	}
+}
+
+// SizeLimitError is returned when the zip batch is larger than the limit.
+//
+// Limit is the limit of the zip batch.
+//
+// File is the name of the file that was being written when the limit was
+// reached.  It is empty when the limit was reached by the zip's directory.
+type SizeLimitError struct {
+	Limit int64
+	File  string
+}
+
+func (e *SizeLimitError) Error() string {
+	if e.File == "" {
+		return fmt.Sprintf("zip batch: zip directory does not fit in the %d byte limit", e.Limit)
+	}
+	return fmt.Sprintf("zip batch: %s does not fit in the %d byte limit", e.File, e.Limit)
+}
+
+// Option is an option for the zip batch builder.
+type Option func(*ZipBatchBuilder)
+
+// WithMaxBytes will limit the zip batch to the bytes instead of MaxZipBytes.
+func WithMaxBytes(maxBytes int64) Option {
+	return func(b *ZipBatchBuilder) {
+		b.maxBytes = maxBytes
+	}
+}
+
+type file struct {
+	name   string
+	body   io.Reader
+	record map[string]string
+}
+
+// ZipBatchBuilder builds a zip batch from the files.  The files are read when
+// the zip is written, so they are not held in memory.
+type ZipBatchBuilder struct {
+	format   Format
+	maxBytes int64
+	files    []file
+	names    map[string]struct{}
+}
+
+// NewZipBatchBuilder creates a zip batch builder with the manifest format.
+func NewZipBatchBuilder(format Format, options ...Option) (*ZipBatchBuilder, error) {
+	switch format {
+	case CSV, JSON:
+	default:
+		return nil, fmt.Errorf("zip batch: format %q is not supported", format)
+	}
+	builder := &ZipBatchBuilder{
+		format:   format,
+		maxBytes: MaxZipBytes,
+		names:    make(map[string]struct{}),
+	}
+	for _, option := range options {
+		option(builder)
+	}
+	if builder.maxBytes <= 0 {
+		return nil, errors.New("zip batch: max bytes must be greater than zero")
+	}
+	return builder, nil
+}
+
+// ContentType returns the content type of the zip batch request.
+func (b *ZipBatchBuilder) ContentType() string {
+	return b.format.ContentType()
+}
+
+// AddFile will add the file and its manifest record, like the Name and ParentId
+// of the attachment.  The record's Body refers to the file.
+func (b *ZipBatchBuilder) AddFile(name string, body io.Reader, record map[string]string) error {
+	if name == "" {
+		return errors.New("zip batch: file name can not be empty")
+	}
+	if name == ManifestName {
+		return fmt.Errorf("zip batch: file name can not be %s", ManifestName)
+	}
+	if body == nil {
+		return errors.New("zip batch: file body can not be nil")
+	}
+	if _, has := record[BodyField]; has {
+		return fmt.Errorf("zip batch: record can not have the %s field", BodyField)
+	}
+	if _, has := b.names[name]; has {
+		return fmt.Errorf("zip batch: file %s is already added", name)
+	}
+	b.names[name] = struct{}{}
+	b.files = append(b.files, file{
+		name:   name,
+		body:   body,
+		record: record,
+	})
+	return nil
+}
+
+// Manifest returns the request.txt manifest of the files.
+func (b *ZipBatchBuilder) Manifest() ([]byte, error) {
+	if b.format == JSON {
+		records := make([]map[string]string, 0, len(b.files))
+		for _, f := range b.files {
+			records = append(records, f.manifestRecord())
+		}
+		return json.Marshal(records)
+	}
+
+	fieldSet := map[string]struct{}{}
+	for _, f := range b.files {
+		for field := range f.record {
+			fieldSet[field] = struct{}{}
+		}
+	}
+	fields := make([]string, 0, len(fieldSet)+1)
+	for field := range fieldSet {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	fields = append(fields, BodyField)
+
+	var manifest bytes.Buffer
+	writer := csv.NewWriter(&manifest)
+	if err := writer.Write(fields); err != nil {
+		return nil, err
+	}
+	for _, f := range b.files {
+		record := f.manifestRecord()
+		values := make([]string, len(fields))
+		for i, field := range fields {
+			values[i] = record[field]
+		}
+		if err := writer.Write(values); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	return manifest.Bytes(), writer.Error()
+}
+
+func (f file) manifestRecord() map[string]string {
+	record := make(map[string]string, len(f.record)+1)
+	for field, value := range f.record {
+		record[field] = value
+	}
+	record[BodyField] = "#" + f.name
+	return record
+}
+
+// WriteTo will write the zip batch, the manifest and then the files, to the
+// writer.  The files are streamed into the zip.  If the zip is larger than the
+// limit, a SizeLimitError is returned.  The writing stops when the context is
+// done.
+func (b *ZipBatchBuilder) WriteTo(ctx context.Context, w io.Writer) (int64, error) {
+	if len(b.files) == 0 {
+		return 0, errors.New("zip batch: there are no files")
+	}
+	manifest, err := b.Manifest()
+	if err != nil {
+		return 0, err
+	}
+
+	limited := &limitWriter{
+		w:     w,
+		limit: b.maxBytes,
+	}
+	archive := zip.NewWriter(limited)
+	write := func(name string, body io.Reader) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		limited.file = name
+		entry, err := archive.Create(name)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(entry, &contextReader{ctx: ctx, r: body})
+		return err
+	}
+
+	if err := write(ManifestName, bytes.NewReader(manifest)); err != nil {
+		return limited.n, err
+	}
+	for _, f := range b.files {
+		if err := write(f.name, f.body); err != nil {
+			return limited.n, err
+		}
+	}
+	limited.file = ""
+	if err := archive.Close(); err != nil {
+		return limited.n, err
+	}
+	return limited.n, nil
+}
+
+// Body returns the zip batch as a request body, which is written as it is read.
+// The errors of WriteTo are returned by the body's Read.
+func (b *ZipBatchBuilder) Body(ctx context.Context) io.ReadCloser {
+	reader, writer := io.Pipe()
+	go func() {
+		_, err := b.WriteTo(ctx, writer)
+		writer.CloseWithError(err)
+	}()
+	return reader
+}
+
+// limitWriter counts the bytes written and returns a SizeLimitError when they
+// are over the limit.
+type limitWriter struct {
+	w     io.Writer
+	limit int64
+	n     int64
+	file  string
+}
+
+func (l *limitWriter) Write(p []byte) (int, error) {
+	if l.n+int64(len(p)) > l.limit {
+		return 0, &SizeLimitError{
+			Limit: l.limit,
+			File:  l.file,
+		}
+	}
+	n, err := l.w.Write(p)
+	l.n += int64(n)
+	return n, err
+}
+
+// contextReader stops reading when the context is done.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c *contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
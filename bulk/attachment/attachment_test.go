@@ -0,0 +1,471 @@
+package attachment
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/namely/go-sfdc/v3/bulk"
+)
+
+type roundTripFunc func(request *http.Request) *http.Response
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req), nil
+}
+
+func mockHTTPClient(fn roundTripFunc) *http.Client {
+	return &http.Client{
+		Transport: roundTripFunc(fn),
+	}
+}
+
+func twoFileBuilder(t *testing.T, format Format, options ...Option) *ZipBatchBuilder {
+	t.Helper()
+	builder, err := NewZipBatchBuilder(format, options...)
+	if err != nil {
+		t.Fatalf("NewZipBatchBuilder() error = %v", err)
+	}
+	files := []struct {
+		name   string
+		body   string
+		record map[string]string
+	}{
+		{
+			name: "logo.png",
+			body: "png bytes",
+			record: map[string]string{
+				"Name":     "logo.png",
+				"ParentId": "001D000000ISUr3IAH",
+			},
+		},
+		{
+			name: "contract.pdf",
+			body: "pdf bytes",
+			record: map[string]string{
+				"Name":     "contract.pdf",
+				"ParentId": "001D000000ISUr4IAH",
+			},
+		},
+	}
+	for _, f := range files {
+		if err := builder.AddFile(f.name, strings.NewReader(f.body), f.record); err != nil {
+			t.Fatalf("AddFile() error = %v", err)
+		}
+	}
+	return builder
+}
+
+func readZip(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("zip.NewReader() error = %v", err)
+	}
+	entries := map[string]string{}
+	for _, f := range reader.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("Open() error = %v", err)
+		}
+		body, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatalf("ReadAll() error = %v", err)
+		}
+		entries[f.Name] = string(body)
+	}
+	return entries
+}
+
+func TestZipBatchBuilder_WriteTo(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		want   map[string]string
+	}{
+		{
+			name:   "csv manifest",
+			format: CSV,
+			want: map[string]string{
+				ManifestName: "Name,ParentId,Body\n" +
+					"logo.png,001D000000ISUr3IAH,#logo.png\n" +
+					"contract.pdf,001D000000ISUr4IAH,#contract.pdf\n",
+				"logo.png":     "png bytes",
+				"contract.pdf": "pdf bytes",
+			},
+		},
+		{
+			name:   "json manifest",
+			format: JSON,
+			want: map[string]string{
+				ManifestName: `[{"Body":"#logo.png","Name":"logo.png","ParentId":"001D000000ISUr3IAH"},` +
+					`{"Body":"#contract.pdf","Name":"contract.pdf","ParentId":"001D000000ISUr4IAH"}]`,
+				"logo.png":     "png bytes",
+				"contract.pdf": "pdf bytes",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := twoFileBuilder(t, tt.format)
+			var buf bytes.Buffer
+			n, err := builder.WriteTo(context.Background(), &buf)
+			if err != nil {
+				t.Fatalf("ZipBatchBuilder.WriteTo() error = %v", err)
+			}
+			if n != int64(buf.Len()) {
+				t.Errorf("ZipBatchBuilder.WriteTo() = %d, want %d", n, buf.Len())
+			}
+			if got := readZip(t, buf.Bytes()); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ZipBatchBuilder.WriteTo() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestZipBatchBuilder_SizeLimit(t *testing.T) {
+	builder := twoFileBuilder(t, CSV, WithMaxBytes(100))
+	_, err := builder.WriteTo(context.Background(), io.Discard)
+	var limitErr *SizeLimitError
+	if errors.As(err, &limitErr) == false {
+		t.Fatalf("ZipBatchBuilder.WriteTo() error = %v, want a SizeLimitError", err)
+	}
+	if limitErr.Limit != 100 {
+		t.Errorf("SizeLimitError.Limit = %d, want 100", limitErr.Limit)
+	}
+}
+
+func TestZipBatchBuilder_AddFile(t *testing.T) {
+	builder, err := NewZipBatchBuilder(CSV)
+	if err != nil {
+		t.Fatalf("NewZipBatchBuilder() error = %v", err)
+	}
+	if err := builder.AddFile("a.txt", strings.NewReader("a"), nil); err != nil {
+		t.Fatalf("AddFile() error = %v", err)
+	}
+	tests := []struct {
+		name   string
+		file   string
+		body   io.Reader
+		record map[string]string
+	}{
+		{name: "empty name", file: "", body: strings.NewReader("b")},
+		{name: "manifest name", file: ManifestName, body: strings.NewReader("b")},
+		{name: "nil body", file: "b.txt"},
+		{name: "body field", file: "b.txt", body: strings.NewReader("b"), record: map[string]string{BodyField: "b"}},
+		{name: "duplicate", file: "a.txt", body: strings.NewReader("b")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := builder.AddFile(tt.file, tt.body, tt.record); err == nil {
+				t.Error("ZipBatchBuilder.AddFile() expected an error")
+			}
+		})
+	}
+}
+
+func TestJob_UploadBinaryBatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		format      Format
+		options     []Option
+		contentType string
+		response    string
+		status      int
+		want        *Batch
+		wantErr     bool
+	}{
+		{
+			name:        "json batch",
+			format:      JSON,
+			contentType: "application/json",
+			response:    `{"id":"751D0000000004rIAA","jobId":"750D0000000002lIAA","state":"Queued"}`,
+			status:      http.StatusCreated,
+			want: &Batch{
+				ID:    "751D0000000004rIAA",
+				JobID: "750D0000000002lIAA",
+				State: "Queued",
+			},
+		},
+		{
+			name:        "csv batch",
+			format:      CSV,
+			contentType: "application/xml",
+			response: `<?xml version="1.0" encoding="UTF-8"?>` +
+				`<batchInfo xmlns="http://www.force.com/2009/06/asyncapi/dataload">` +
+				`<id>751D0000000004rIAA</id><jobId>750D0000000002lIAA</jobId><state>Queued</state>` +
+				`</batchInfo>`,
+			status: http.StatusCreated,
+			want: &Batch{
+				ID:    "751D0000000004rIAA",
+				JobID: "750D0000000002lIAA",
+				State: "Queued",
+			},
+		},
+		{
+			name:        "error",
+			format:      JSON,
+			contentType: "application/json",
+			response:    `[{"errorCode":"InvalidBatch","message":"bad zip"}]`,
+			status:      http.StatusBadRequest,
+			wantErr:     true,
+		},
+		{
+			name:    "size limit",
+			format:  JSON,
+			options: []Option{WithMaxBytes(100)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var entries map[string]string
+			client := mockHTTPClient(func(req *http.Request) *http.Response {
+				data, err := io.ReadAll(req.Body)
+				if err != nil {
+					return &http.Response{
+						StatusCode: http.StatusInternalServerError,
+						Body:       io.NopCloser(strings.NewReader(err.Error())),
+						Header:     make(http.Header),
+					}
+				}
+				if req.URL.String() != "https://test.salesforce.com/services/async/42.0/job/750D0000000002lIAA/batch" {
+					return &http.Response{
+						StatusCode: http.StatusNotFound,
+						Body:       io.NopCloser(strings.NewReader(req.URL.String())),
+						Header:     make(http.Header),
+					}
+				}
+				if req.Header.Get("Content-Type") != tt.format.ContentType() {
+					return &http.Response{
+						StatusCode: http.StatusUnsupportedMediaType,
+						Body:       io.NopCloser(strings.NewReader(req.Header.Get("Content-Type"))),
+						Header:     make(http.Header),
+					}
+				}
+				entries = readZip(t, data)
+				header := make(http.Header)
+				header.Set("Content-Type", tt.contentType)
+				return &http.Response{
+					StatusCode: tt.status,
+					Body:       io.NopCloser(strings.NewReader(tt.response)),
+					Header:     header,
+				}
+			})
+			job, err := NewJob("750D0000000002lIAA", tt.format, &mockSessionFormatter{
+				url:    "https://test.salesforce.com",
+				client: client,
+			})
+			if err != nil {
+				t.Fatalf("NewJob() error = %v", err)
+			}
+			got, err := job.UploadBinaryBatch(context.Background(), twoFileBuilder(t, tt.format, tt.options...))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Job.UploadBinaryBatch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got.XMLName = tt.want.XMLName
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Job.UploadBinaryBatch() = %v, want %v", got, tt.want)
+			}
+			if entries["logo.png"] != "png bytes" || entries["contract.pdf"] != "pdf bytes" {
+				t.Errorf("Job.UploadBinaryBatch() uploaded %v", entries)
+			}
+		})
+	}
+}
+
+func TestJob_UploadBinaryBatch_FormatMismatch(t *testing.T) {
+	job, err := NewJob("750D0000000002lIAA", CSV, &mockSessionFormatter{
+		url: "https://test.salesforce.com",
+	})
+	if err != nil {
+		t.Fatalf("NewJob() error = %v", err)
+	}
+	if _, err := job.UploadBinaryBatch(context.Background(), twoFileBuilder(t, JSON)); err == nil {
+		t.Error("Job.UploadBinaryBatch() expected an error")
+	}
+}
+
+func TestFormatOf(t *testing.T) {
+	tests := []struct {
+		contentType bulk.ContentType
+		want        Format
+		wantErr     bool
+	}{
+		{contentType: bulk.ZipCSV, want: CSV},
+		{contentType: bulk.ZipJSON, want: JSON},
+		{contentType: bulk.ZipXML, wantErr: true},
+		{contentType: bulk.CSV, wantErr: true},
+		{contentType: bulk.XML, wantErr: true},
+		{contentType: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.contentType), func(t *testing.T) {
+			got, err := FormatOf(tt.contentType)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FormatOf() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("FormatOf() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJob_WaitForBatches(t *testing.T) {
+	policy := batchPollPolicy
+	batchPollPolicy.InitialInterval = time.Millisecond
+	defer func() { batchPollPolicy = policy }()
+
+	const jobURL = "https://test.salesforce.com/services/async/42.0/job/750D0000000002lIAA"
+	tests := []struct {
+		name        string
+		format      Format
+		contentType string
+		jobState    string
+		polls       []string
+		wantClose   string
+		want        []Batch
+	}{
+		{
+			name:        "open json job",
+			format:      JSON,
+			contentType: "application/json",
+			jobState:    `{"id":"750D0000000002lIAA","state":"Open"}`,
+			polls: []string{
+				`{"batchInfo":[{"id":"751A","state":"Completed","numberRecordsProcessed":10},{"id":"751B","state":"InProgress"}]}`,
+				`{"batchInfo":[{"id":"751A","state":"Completed","numberRecordsProcessed":10},{"id":"751B","state":"Queued"}]}`,
+				`{"batchInfo":[{"id":"751A","state":"Completed","numberRecordsProcessed":10},{"id":"751B","state":"Failed","numberRecordsProcessed":5,"numberRecordsFailed":3}]}`,
+			},
+			wantClose: `{"state":"Closed"}`,
+			want: []Batch{
+				{ID: "751A", State: BatchCompleted, NumberRecordsProcessed: 10},
+				{ID: "751B", State: BatchFailed, NumberRecordsProcessed: 5, NumberRecordsFailed: 3},
+			},
+		},
+		{
+			name:        "closed xml job",
+			format:      CSV,
+			contentType: "application/xml",
+			jobState: `<?xml version="1.0" encoding="UTF-8"?>` +
+				`<jobInfo xmlns="http://www.force.com/2009/06/asyncapi/dataload"><id>750D0000000002lIAA</id><state>Closed</state></jobInfo>`,
+			polls: []string{
+				`<?xml version="1.0" encoding="UTF-8"?>` +
+					`<batchInfoList xmlns="http://www.force.com/2009/06/asyncapi/dataload">` +
+					`<batchInfo><id>751A</id><state>Completed</state></batchInfo>` +
+					`<batchInfo><id>751B</id><state>NotProcessed</state></batchInfo>` +
+					`</batchInfoList>`,
+			},
+			want: []Batch{
+				{ID: "751A", State: BatchCompleted},
+				{ID: "751B", State: BatchNotProcessed},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var closed string
+			polls := 0
+			client := mockHTTPClient(func(req *http.Request) *http.Response {
+				var body string
+				switch {
+				case req.Method == http.MethodGet && req.URL.String() == jobURL:
+					body = tt.jobState
+				case req.Method == http.MethodPost && req.URL.String() == jobURL:
+					data, _ := io.ReadAll(req.Body)
+					closed = string(data)
+					body = tt.jobState
+				case req.Method == http.MethodGet && req.URL.String() == jobURL+"/batch":
+					body = tt.polls[polls]
+					if polls < len(tt.polls)-1 {
+						polls++
+					}
+				default:
+					t.Errorf("unexpected request %s %s", req.Method, req.URL)
+				}
+				header := make(http.Header)
+				header.Set("Content-Type", tt.contentType)
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(body)),
+					Header:     header,
+				}
+			})
+			job, err := NewJob("750D0000000002lIAA", tt.format, &mockSessionFormatter{
+				url:    "https://test.salesforce.com",
+				client: client,
+			})
+			if err != nil {
+				t.Fatalf("NewJob() error = %v", err)
+			}
+
+			got, err := job.WaitForBatches(context.Background())
+			if err != nil {
+				t.Fatalf("Job.WaitForBatches() error = %v", err)
+			}
+			for idx := range got {
+				got[idx].XMLName = xml.Name{}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Job.WaitForBatches() = %+v, want %+v", got, tt.want)
+			}
+			if closed != tt.wantClose {
+				t.Errorf("Job.WaitForBatches() closed the job with %q, want %q", closed, tt.wantClose)
+			}
+			if polls != len(tt.polls)-1 {
+				t.Errorf("Job.WaitForBatches() polled %d times, want %d", polls+1, len(tt.polls))
+			}
+		})
+	}
+}
+
+func TestJob_WaitForBatches_canceled(t *testing.T) {
+	policy := batchPollPolicy
+	batchPollPolicy.InitialInterval = time.Millisecond
+	defer func() { batchPollPolicy = policy }()
+
+	client := mockHTTPClient(func(req *http.Request) *http.Response {
+		body := `{"batchInfo":[{"id":"751A","state":"InProgress"}]}`
+		switch {
+		case strings.HasSuffix(req.URL.Path, "/batch"):
+		case req.Method == http.MethodGet:
+			body = `{"id":"750D0000000002lIAA","state":"Closed"}`
+		default:
+			t.Errorf("unexpected request %s %s", req.Method, req.URL)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Header:     make(http.Header),
+		}
+	})
+	job, err := NewJob("750D0000000002lIAA", JSON, &mockSessionFormatter{
+		url:    "https://test.salesforce.com",
+		client: client,
+	})
+	if err != nil {
+		t.Fatalf("NewJob() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	got, err := job.WaitForBatches(ctx)
+	if errors.Is(err, context.DeadlineExceeded) == false {
+		t.Fatalf("Job.WaitForBatches() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if len(got) != 1 || got[0].State != BatchInProgress {
+		t.Errorf("Job.WaitForBatches() = %+v, want the last polled batches", got)
+	}
+}
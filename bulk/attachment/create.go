@@ -0,0 +1,163 @@
+package attachment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/namely/go-sfdc/v3"
+	"github.com/namely/go-sfdc/v3/session"
+)
+
+// Operation is the operation of a bulk 1.0 job.
+type Operation string
+
+const (
+	// Insert is the operation for inserting records.
+	Insert Operation = "insert"
+	// Update is the operation for updating records.
+	Update Operation = "update"
+	// Upsert is the operation for upserting records.
+	Upsert Operation = "upsert"
+	// Delete is the operation for deleting records.
+	Delete Operation = "delete"
+	// Query is the operation for querying records.
+	Query Operation = "query"
+	// QueryAll is the operation for querying records, including the deleted
+	// and archived records.
+	QueryAll Operation = "queryAll"
+)
+
+// MaxPKChunkSize is the largest chunk size of PK chunking.
+const MaxPKChunkSize = 250000
+
+// PKChunking splits a bulk 1.0 query job into batches of the record ID ranges,
+// with the Sforce-Enable-PKChunking header.  The batch of the query is not
+// processed, and Salesforce adds a batch for each chunk, which are returned by
+// Batches and waited on by WaitForBatches.
+//
+// ChunkSize is the number of records of each chunk, up to MaxPKChunkSize.
+// Salesforce uses 100,000 when it is zero.
+//
+// Parent is the parent object of the chunks when the query is of a sharing
+// object or of a history object, like Account for AccountShare.
+//
+// StartRow is the 15 or 18 character record ID of the first chunk's lower
+// bound.
+type PKChunking struct {
+	ChunkSize int
+	Parent    string
+	StartRow  string
+}
+
+// header returns the Sforce-Enable-PKChunking header value, which is true when
+// none of the fields are set.
+func (p PKChunking) header() (string, error) {
+	if p.ChunkSize < 0 || p.ChunkSize > MaxPKChunkSize {
+		return "", fmt.Errorf("attachment job: pk chunk size %d must be between 1 and %d", p.ChunkSize, MaxPKChunkSize)
+	}
+	if p.StartRow != "" && sfdc.ValidID(p.StartRow) == false {
+		return "", fmt.Errorf("attachment job: pk chunking start row %q is not a valid id", p.StartRow)
+	}
+	var fields []string
+	if p.ChunkSize > 0 {
+		fields = append(fields, fmt.Sprintf("chunkSize=%d", p.ChunkSize))
+	}
+	if p.Parent != "" {
+		fields = append(fields, "parent="+p.Parent)
+	}
+	if p.StartRow != "" {
+		fields = append(fields, "startRow="+p.StartRow)
+	}
+	if len(fields) == 0 {
+		return "true", nil
+	}
+	return strings.Join(fields, "; "), nil
+}
+
+// Options are the options of a new bulk 1.0 job.
+//
+// Object is the object of the job's records.  This field is required.
+//
+// Operation is the operation of the job.  This field is required.
+//
+// ExternalIDField is the external ID field of an upsert.
+//
+// Format is the format of the job's data.  The query jobs have CSV or JSON
+// results, and the other jobs have the zip batches of the format.
+//
+// PKChunking splits a query or queryAll job into chunks.  It is an error for
+// the other operations.
+type Options struct {
+	Object          string
+	Operation       Operation
+	ExternalIDField string
+	Format          Format
+	PKChunking      *PKChunking
+}
+
+// CreateJob will create a bulk 1.0 job with the options.  The job's requests
+// are in the JSON format for a JSON job and in XML for a CSV job.
+func CreateJob(ctx context.Context, session session.ServiceFormatter, options Options) (*Job, error) {
+	if options.Object == "" {
+		return nil, errors.New("attachment job: object can not be empty")
+	}
+	if options.Operation == "" {
+		return nil, errors.New("attachment job: operation can not be empty")
+	}
+	if options.Operation == Upsert && options.ExternalIDField == "" {
+		return nil, errors.New("attachment job: upsert needs an external id field")
+	}
+	query := options.Operation == Query || options.Operation == QueryAll
+	var chunking string
+	if options.PKChunking != nil {
+		if query == false {
+			return nil, fmt.Errorf("attachment job: pk chunking is only for query jobs, not %s", options.Operation)
+		}
+		var err error
+		if chunking, err = options.PKChunking.header(); err != nil {
+			return nil, err
+		}
+	}
+	switch options.Format {
+	case CSV, JSON:
+	default:
+		return nil, fmt.Errorf("attachment job: format %q is not supported", options.Format)
+	}
+	contentType := "ZIP_" + string(options.Format)
+	if query {
+		contentType = string(options.Format)
+	}
+	if session == nil {
+		return nil, errors.New("attachment job: session can not be nil")
+	}
+	job := &Job{
+		format:  options.Format,
+		session: session,
+	}
+
+	url, err := job.jobsURL(ctx)
+	if err != nil {
+		return nil, err
+	}
+	info := jobInfo{
+		Operation:       string(options.Operation),
+		Object:          options.Object,
+		ExternalIDField: options.ExternalIDField,
+		ContentType:     contentType,
+	}
+	header := make(http.Header)
+	if chunking != "" {
+		header.Set("Sforce-Enable-PKChunking", chunking)
+	}
+	if err := job.do(ctx, http.MethodPost, url, header, &info, http.StatusCreated, &info); err != nil {
+		return nil, err
+	}
+	if info.ID == "" {
+		return nil, errors.New("attachment job: the created job does not have an id")
+	}
+	job.id = info.ID
+	return job, nil
+}
@@ -0,0 +1,185 @@
+package attachment
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestPKChunking_header(t *testing.T) {
+	tests := []struct {
+		name     string
+		chunking PKChunking
+		want     string
+		wantErr  bool
+	}{
+		{
+			name: "default",
+			want: "true",
+		},
+		{
+			name:     "chunk size",
+			chunking: PKChunking{ChunkSize: 50000},
+			want:     "chunkSize=50000",
+		},
+		{
+			name:     "all fields",
+			chunking: PKChunking{ChunkSize: 250000, Parent: "Account", StartRow: "001D000000ISUr3IAH"},
+			want:     "chunkSize=250000; parent=Account; startRow=001D000000ISUr3IAH",
+		},
+		{
+			name:     "chunk size too large",
+			chunking: PKChunking{ChunkSize: MaxPKChunkSize + 1},
+			wantErr:  true,
+		},
+		{
+			name:     "negative chunk size",
+			chunking: PKChunking{ChunkSize: -1},
+			wantErr:  true,
+		},
+		{
+			name:     "invalid start row",
+			chunking: PKChunking{StartRow: "001"},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.chunking.header()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PKChunking.header() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("PKChunking.header() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateJob(t *testing.T) {
+	tests := []struct {
+		name       string
+		options    Options
+		response   string
+		mediaType  string
+		wantBody   string
+		wantHeader string
+		wantID     string
+	}{
+		{
+			name: "pk chunked query",
+			options: Options{
+				Object:     "Account",
+				Operation:  Query,
+				Format:     CSV,
+				PKChunking: &PKChunking{ChunkSize: 100000, Parent: "Account"},
+			},
+			mediaType: "application/xml",
+			response: `<?xml version="1.0" encoding="UTF-8"?>` +
+				`<jobInfo xmlns="http://www.force.com/2009/06/asyncapi/dataload"><id>750D0000000002lIAA</id><state>Open</state></jobInfo>`,
+			wantBody: `<jobInfo xmlns="http://www.force.com/2009/06/asyncapi/dataload">` +
+				`<operation>query</operation><object>Account</object><contentType>CSV</contentType></jobInfo>`,
+			wantHeader: "chunkSize=100000; parent=Account",
+			wantID:     "750D0000000002lIAA",
+		},
+		{
+			name: "attachment insert",
+			options: Options{
+				Object:    "Attachment",
+				Operation: Insert,
+				Format:    JSON,
+			},
+			mediaType: "application/json",
+			response:  `{"id":"750D0000000002mIAA","state":"Open"}`,
+			wantBody:  `{"operation":"insert","object":"Attachment","contentType":"ZIP_JSON"}`,
+			wantID:    "750D0000000002mIAA",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body, header, path string
+			session := &mockSessionFormatter{
+				url: "https://test.salesforce.com",
+				client: mockHTTPClient(func(req *http.Request) *http.Response {
+					payload, _ := io.ReadAll(req.Body)
+					body = string(payload)
+					header = req.Header.Get("Sforce-Enable-PKChunking")
+					path = req.Method + " " + req.URL.Path
+					return &http.Response{
+						StatusCode: http.StatusCreated,
+						Body:       io.NopCloser(strings.NewReader(tt.response)),
+						Header:     http.Header{"Content-Type": []string{tt.mediaType}},
+					}
+				}),
+			}
+			job, err := CreateJob(context.Background(), session, tt.options)
+			if err != nil {
+				t.Fatalf("CreateJob() error = %v", err)
+			}
+			if job.ID() != tt.wantID {
+				t.Errorf("CreateJob() id = %s, want %s", job.ID(), tt.wantID)
+			}
+			if want := "POST /services/async/42.0/job"; path != want {
+				t.Errorf("CreateJob() request = %s, want %s", path, want)
+			}
+			if body != tt.wantBody {
+				t.Errorf("CreateJob() body = %s, want %s", body, tt.wantBody)
+			}
+			if header != tt.wantHeader {
+				t.Errorf("CreateJob() pk chunking header = %q, want %q", header, tt.wantHeader)
+			}
+		})
+	}
+}
+
+func TestCreateJob_invalid(t *testing.T) {
+	session := &mockSessionFormatter{
+		url: "https://test.salesforce.com",
+		client: mockHTTPClient(func(req *http.Request) *http.Response {
+			t.Errorf("CreateJob() sent a request for invalid options")
+			return nil
+		}),
+	}
+	tests := []struct {
+		name    string
+		options Options
+	}{
+		{
+			name:    "pk chunking of an insert",
+			options: Options{Object: "Attachment", Operation: Insert, Format: CSV, PKChunking: &PKChunking{}},
+		},
+		{
+			name:    "pk chunking of a delete",
+			options: Options{Object: "Account", Operation: Delete, Format: CSV, PKChunking: &PKChunking{ChunkSize: 1000}},
+		},
+		{
+			name:    "invalid pk chunking",
+			options: Options{Object: "Account", Operation: QueryAll, Format: CSV, PKChunking: &PKChunking{ChunkSize: MaxPKChunkSize + 1}},
+		},
+		{
+			name:    "no object",
+			options: Options{Operation: Query, Format: CSV},
+		},
+		{
+			name:    "no operation",
+			options: Options{Object: "Account", Format: CSV},
+		},
+		{
+			name:    "upsert without an external id",
+			options: Options{Object: "Account", Operation: Upsert, Format: CSV},
+		},
+		{
+			name:    "unknown format",
+			options: Options{Object: "Account", Operation: Query, Format: "XML"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := CreateJob(context.Background(), session, tt.options); err == nil {
+				t.Errorf("CreateJob() expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,319 @@
+package attachment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/namely/go-sfdc/v3"
+	"github.com/namely/go-sfdc/v3/session"
+)
+
+const asyncEndpoint = "/services/async"
+
+// The states of a bulk 1.0 job.
+const (
+	JobOpen    = "Open"
+	JobClosed  = "Closed"
+	JobAborted = "Aborted"
+	JobFailed  = "Failed"
+)
+
+// The states of a bulk 1.0 batch.  Completed, Failed and NotProcessed are
+// final.
+const (
+	BatchQueued       = "Queued"
+	BatchInProgress   = "InProgress"
+	BatchCompleted    = "Completed"
+	BatchFailed       = "Failed"
+	BatchNotProcessed = "NotProcessed"
+)
+
+// batchPollPolicy is the back off of the batch polls of WaitForBatches.
+var batchPollPolicy = sfdc.RetryPolicy{
+	InitialInterval: time.Second,
+	Multiplier:      1.5,
+	MaxInterval:     30 * time.Second,
+	Jitter:          0.25,
+}
+
+// Batch is the bulk 1.0 batch information returned when the batch is created.
+type Batch struct {
+	XMLName                 xml.Name `json:"-" xml:"batchInfo"`
+	ID                      string   `json:"id" xml:"id"`
+	JobID                   string   `json:"jobId" xml:"jobId"`
+	State                   string   `json:"state" xml:"state"`
+	StateMessage            string   `json:"stateMessage" xml:"stateMessage"`
+	CreatedDate             string   `json:"createdDate" xml:"createdDate"`
+	SystemModstamp          string   `json:"systemModstamp" xml:"systemModstamp"`
+	NumberRecordsProcessed  int      `json:"numberRecordsProcessed" xml:"numberRecordsProcessed"`
+	NumberRecordsFailed     int      `json:"numberRecordsFailed" xml:"numberRecordsFailed"`
+	TotalProcessingTime     int      `json:"totalProcessingTime" xml:"totalProcessingTime"`
+	APIActiveProcessingTime int      `json:"apiActiveProcessingTime" xml:"apiActiveProcessingTime"`
+	ApexProcessingTime      int      `json:"apexProcessingTime" xml:"apexProcessingTime"`
+}
+
+// Job is a bulk 1.0 job that loads binary attachments.  The job is created
+// with the zip/csv or zip/json content type, which is the format of its
+// manifest.  A job is not changed after it is created, so it is safe for
+// concurrent use.
+type Job struct {
+	id      string
+	format  Format
+	session session.ServiceFormatter
+}
+
+// NewJob returns the bulk 1.0 job with the ID and the manifest format.
+func NewJob(id string, format Format, session session.ServiceFormatter) (*Job, error) {
+	if id == "" {
+		return nil, errors.New("attachment job: id can not be empty")
+	}
+	switch format {
+	case CSV, JSON:
+	default:
+		return nil, fmt.Errorf("attachment job: format %q is not supported", format)
+	}
+	if session == nil {
+		return nil, errors.New("attachment job: session can not be nil")
+	}
+	return &Job{
+		id:      id,
+		format:  format,
+		session: session,
+	}, nil
+}
+
+// ID returns the job's ID.
+func (j *Job) ID() string {
+	return j.id
+}
+
+// UploadBinaryBatch will create a batch from the zip batch.  The zip is
+// streamed into the request body, so the files are not held in memory.  The
+// builder's format must be the job's format.  The upload stops when the
+// context is done.
+func (j *Job) UploadBinaryBatch(ctx context.Context, builder *ZipBatchBuilder) (*Batch, error) {
+	if builder == nil {
+		return nil, errors.New("attachment job: builder can not be nil")
+	}
+	if builder.format != j.format {
+		return nil, fmt.Errorf("attachment job: builder format %s does not match the job format %s", builder.format, j.format)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	url, err := j.batchURL(ctx)
+	if err != nil {
+		return nil, err
+	}
+	body := builder.Body(ctx)
+	defer body.Close()
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Add("Content-Type", builder.ContentType())
+	j.session.AuthorizationHeader(request)
+
+	response, err := j.session.Client().Do(request)
+	if err != nil {
+		var limitErr *SizeLimitError
+		if errors.As(err, &limitErr) {
+			return nil, limitErr
+		}
+		return nil, err
+	}
+	defer sfdc.CloseResponse(response)
+
+	if response.StatusCode != http.StatusCreated {
+		return nil, sfdc.HandleError(response)
+	}
+
+	var batch Batch
+	if err := decodeResponse(response, &batch); err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+// jobInfo is the bulk 1.0 job information that is sent to create the job, and
+// read and changed to close it.
+type jobInfo struct {
+	XMLName         xml.Name `json:"-" xml:"http://www.force.com/2009/06/asyncapi/dataload jobInfo"`
+	ID              string   `json:"id,omitempty" xml:"id,omitempty"`
+	Operation       string   `json:"operation,omitempty" xml:"operation,omitempty"`
+	Object          string   `json:"object,omitempty" xml:"object,omitempty"`
+	ExternalIDField string   `json:"externalIdFieldName,omitempty" xml:"externalIdFieldName,omitempty"`
+	ContentType     string   `json:"contentType,omitempty" xml:"contentType,omitempty"`
+	State           string   `json:"state,omitempty" xml:"state,omitempty"`
+}
+
+// batchList is the bulk 1.0 list of the job's batches.
+type batchList struct {
+	XMLName xml.Name `json:"-" xml:"batchInfoList"`
+	Batches []Batch  `json:"batchInfo" xml:"batchInfo"`
+}
+
+// Batches returns the information of the job's batches.
+func (j *Job) Batches(ctx context.Context) ([]Batch, error) {
+	url, err := j.batchURL(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var list batchList
+	if err := j.do(ctx, http.MethodGet, url, nil, nil, http.StatusOK, &list); err != nil {
+		return nil, err
+	}
+	return list.Batches, nil
+}
+
+// WaitForBatches will close the job if it is open, and poll its batches with a
+// back off until every batch is Completed, Failed or NotProcessed.  The final
+// information of every batch is returned.  A failed batch is not an error, so
+// the failures are read from the batches' NumberRecordsFailed.
+//
+// The polling stops when the context is done, and the batches of the last poll
+// are returned with the context's error.  Unlike the bulk 2.0 jobs, the job is
+// not aborted, so its batches are still processed.
+func (j *Job) WaitForBatches(ctx context.Context) ([]Batch, error) {
+	if err := j.closeOpen(ctx); err != nil {
+		return nil, err
+	}
+
+	backOff := batchPollPolicy.BackOff()
+	var batches []Batch
+	for {
+		polled, err := j.Batches(ctx)
+		switch {
+		case ctx.Err() != nil:
+			return batches, ctx.Err()
+		case err != nil:
+			return nil, err
+		}
+		batches = polled
+		if batchesDone(batches) {
+			return batches, nil
+		}
+
+		timer := time.NewTimer(backOff.NextBackOff())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return batches, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// closeOpen closes the job if it is open, so no more batches are added and the
+// batches are processed to the end.
+func (j *Job) closeOpen(ctx context.Context) error {
+	url, err := j.jobURL(ctx)
+	if err != nil {
+		return err
+	}
+	var info jobInfo
+	if err := j.do(ctx, http.MethodGet, url, nil, nil, http.StatusOK, &info); err != nil {
+		return err
+	}
+	if info.State != JobOpen {
+		return nil
+	}
+	return j.do(ctx, http.MethodPost, url, nil, &jobInfo{State: JobClosed}, http.StatusOK, &info)
+}
+
+// batchesDone returns whether every batch is in a final state.
+func batchesDone(batches []Batch) bool {
+	for _, batch := range batches {
+		switch batch.State {
+		case BatchCompleted, BatchFailed, BatchNotProcessed:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// do sends the request, in the job's format and with the header, and decodes
+// the response into the value.
+func (j *Job) do(ctx context.Context, method, url string, header http.Header, body interface{}, status int, value interface{}) error {
+	contentType := "application/xml; charset=UTF-8"
+	var payload []byte
+	if j.format == JSON {
+		contentType = "application/json; charset=UTF-8"
+	}
+	if body != nil {
+		var err error
+		if j.format == JSON {
+			payload, err = json.Marshal(body)
+		} else {
+			payload, err = xml.Marshal(body)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	request, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	for key, values := range header {
+		request.Header[key] = values
+	}
+	request.Header.Add("Accept", strings.TrimSuffix(contentType, "; charset=UTF-8"))
+	if body != nil {
+		request.Header.Add("Content-Type", contentType)
+	}
+	j.session.AuthorizationHeader(request)
+
+	response, err := j.session.Client().Do(request)
+	if err != nil {
+		return err
+	}
+	defer sfdc.CloseResponse(response)
+
+	if response.StatusCode != status {
+		return sfdc.HandleError(response)
+	}
+	return decodeResponse(response, value)
+}
+
+// decodeResponse decodes the response as XML or JSON, by its content type.
+func decodeResponse(response *http.Response, value interface{}) error {
+	if strings.Contains(response.Header.Get("Content-Type"), "xml") {
+		return xml.NewDecoder(response.Body).Decode(value)
+	}
+	return json.NewDecoder(response.Body).Decode(value)
+}
+
+func (j *Job) jobsURL(ctx context.Context) (string, error) {
+	version, ok := sfdc.APIVersion(ctx)
+	if ok == false {
+		version = j.session.Version()
+	} else if _, err := session.ServiceURLContext(ctx, j.session); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s%s/%d.0/job", strings.TrimRight(j.session.InstanceURL(), "/"), asyncEndpoint, version), nil
+}
+
+func (j *Job) jobURL(ctx context.Context) (string, error) {
+	url, err := j.jobsURL(ctx)
+	if err != nil {
+		return "", err
+	}
+	return url + "/" + j.id, nil
+}
+
+func (j *Job) batchURL(ctx context.Context) (string, error) {
+	url, err := j.jobURL(ctx)
+	if err != nil {
+		return "", err
+	}
+	return url + "/batch", nil
+}
@@ -0,0 +1,122 @@
+package bulk
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+)
+
+// UploadOption is an option for the job's upload.
+type UploadOption func(*uploadOptions)
+
+type uploadOptions struct {
+	audit   io.Writer
+	warning func(error)
+}
+
+// WithAuditWriter will write the uploaded data to the writer as it is sent,
+// and record its size and SHA-256 checksum, which are returned by the job's
+// UploadAudit.  The data is not buffered.  By default, a failed write to the
+// writer fails the upload with an AuditError.
+func WithAuditWriter(w io.Writer) UploadOption {
+	return func(opts *uploadOptions) {
+		opts.audit = w
+	}
+}
+
+// WithAuditWarning will call the warning function with an AuditError when a
+// write to the audit writer fails, instead of failing the upload.  The rest of
+// the data is not written to the audit writer, but is still uploaded and
+// included in the size and checksum.
+func WithAuditWarning(warning func(error)) UploadOption {
+	return func(opts *uploadOptions) {
+		opts.warning = warning
+	}
+}
+
+// AuditError is returned, or passed to the audit warning, when a write to the
+// audit writer fails.
+//
+// JobID is the ID of the job the data was uploaded to.
+//
+// Err is the error of the audit writer.
+type AuditError struct {
+	JobID string
+	Err   error
+}
+
+func (e *AuditError) Error() string {
+	return fmt.Sprintf("bulk job: job %s audit write: %s", e.JobID, e.Err.Error())
+}
+
+// Unwrap returns the error of the audit writer.
+func (e *AuditError) Unwrap() error {
+	return e.Err
+}
+
+// UploadAudit is the audit of the job's uploaded data.
+//
+// Bytes is the number of bytes that were sent.
+//
+// SHA256 is the hex encoded SHA-256 checksum of the bytes that were sent.
+//
+// Complete is false when a write to the audit writer failed, so the writer
+// does not have all of the bytes.
+type UploadAudit struct {
+	Bytes    int64
+	SHA256   string
+	Complete bool
+}
+
+// auditReader tees the data that is read to the audit writer, counting and
+// hashing it.
+type auditReader struct {
+	body    io.Reader
+	jobID   string
+	writer  io.Writer
+	warning func(error)
+	hash    hash.Hash
+	bytes   int64
+	err     *AuditError
+}
+
+func newAuditReader(body io.Reader, jobID string, opts uploadOptions) *auditReader {
+	return &auditReader{
+		body:    body,
+		jobID:   jobID,
+		writer:  opts.audit,
+		warning: opts.warning,
+		hash:    sha256.New(),
+	}
+}
+
+func (r *auditReader) Read(p []byte) (int, error) {
+	if r.err != nil && r.warning == nil {
+		return 0, r.err
+	}
+	n, err := r.body.Read(p)
+	if n > 0 {
+		r.hash.Write(p[:n])
+		r.bytes += int64(n)
+		if r.err == nil {
+			if _, werr := r.writer.Write(p[:n]); werr != nil {
+				r.err = &AuditError{JobID: r.jobID, Err: werr}
+				if r.warning == nil {
+					return n, r.err
+				}
+				r.warning(r.err)
+			}
+		}
+	}
+	return n, err
+}
+
+func (r *auditReader) audit() UploadAudit {
+	return UploadAudit{
+		Bytes:    r.bytes,
+		SHA256:   hex.EncodeToString(r.hash.Sum(nil)),
+		Complete: r.err == nil,
+	}
+}
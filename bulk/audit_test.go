@@ -0,0 +1,191 @@
+package bulk
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type failingWriter struct {
+	after   int
+	written int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.written+len(p) > w.after {
+		return 0, errors.New("disk full")
+	}
+	w.written += len(p)
+	return len(p), nil
+}
+
+func auditJob(received *bytes.Buffer, contentLength *int64) *Job {
+	return &Job{
+		info: Response{
+			ID: "7505fEXAMPLE4C2AAM",
+		},
+		session: &mockSessionFormatter{
+			url: "https://test.salesforce.com",
+			client: mockHTTPClient(func(req *http.Request) *http.Response {
+				*contentLength = req.ContentLength
+				received.Reset()
+				if _, err := io.Copy(received, req.Body); err != nil {
+					return &http.Response{
+						StatusCode: http.StatusBadRequest,
+						Body:       io.NopCloser(strings.NewReader(err.Error())),
+						Header:     make(http.Header),
+					}
+				}
+				return &http.Response{
+					StatusCode: http.StatusCreated,
+					Body:       io.NopCloser(strings.NewReader("")),
+					Header:     make(http.Header),
+				}
+			}),
+		},
+	}
+}
+
+func TestJob_Upload_AuditWriter(t *testing.T) {
+	var received bytes.Buffer
+	var contentLength int64
+	job := auditJob(&received, &contentLength)
+	if _, has := job.UploadAudit(); has {
+		t.Errorf("Job.UploadAudit() before an audited upload")
+	}
+
+	body := strings.Repeat("Name,Industry\nAcme,Manufacturing\n", 1000)
+	var audit bytes.Buffer
+	if err := job.Upload(strings.NewReader(body), WithAuditWriter(&audit)); err != nil {
+		t.Fatalf("Job.Upload() error = %v", err)
+	}
+	if audit.String() != body || received.String() != body {
+		t.Errorf("Job.Upload() audit = %d bytes, received = %d bytes, want %d", audit.Len(), received.Len(), len(body))
+	}
+	if contentLength != int64(len(body)) {
+		t.Errorf("Job.Upload() content length = %d, want %d", contentLength, len(body))
+	}
+	sum := sha256.Sum256(received.Bytes())
+	got, has := job.UploadAudit()
+	want := UploadAudit{
+		Bytes:    int64(received.Len()),
+		SHA256:   hex.EncodeToString(sum[:]),
+		Complete: true,
+	}
+	if has == false || got != want {
+		t.Errorf("Job.UploadAudit() = %+v, %v, want %+v", got, has, want)
+	}
+}
+
+func TestJob_Upload_AuditWriterFatal(t *testing.T) {
+	var received bytes.Buffer
+	var contentLength int64
+	job := auditJob(&received, &contentLength)
+
+	body := strings.Repeat("Name\nAcme\n", 10000)
+	err := job.Upload(strings.NewReader(body), WithAuditWriter(&failingWriter{after: 10}))
+	var auditErr *AuditError
+	if errors.As(err, &auditErr) == false {
+		t.Fatalf("Job.Upload() error = %v, want an AuditError", err)
+	}
+	if auditErr.JobID != "7505fEXAMPLE4C2AAM" || auditErr.Err.Error() != "disk full" {
+		t.Errorf("Job.Upload() error = %v", auditErr)
+	}
+	if received.Len() >= len(body) {
+		t.Errorf("Job.Upload() sent %d bytes after the audit failed", received.Len())
+	}
+	if _, has := job.UploadAudit(); has {
+		t.Errorf("Job.UploadAudit() after a failed upload")
+	}
+}
+
+func TestJob_Upload_AuditWriterWarning(t *testing.T) {
+	var received bytes.Buffer
+	var contentLength int64
+	job := auditJob(&received, &contentLength)
+
+	body := strings.Repeat("Name\nAcme\n", 10000)
+	var warnings []error
+	writer := &failingWriter{after: 10}
+	err := job.Upload(strings.NewReader(body), WithAuditWriter(writer), WithAuditWarning(func(err error) {
+		warnings = append(warnings, err)
+	}))
+	if err != nil {
+		t.Fatalf("Job.Upload() error = %v", err)
+	}
+	if len(warnings) != 1 {
+		t.Fatalf("Job.Upload() warnings = %v, want one", warnings)
+	}
+	var auditErr *AuditError
+	if errors.As(warnings[0], &auditErr) == false || auditErr.JobID != "7505fEXAMPLE4C2AAM" {
+		t.Errorf("Job.Upload() warning = %v, want an AuditError", warnings[0])
+	}
+	if received.String() != body {
+		t.Errorf("Job.Upload() received %d bytes, want %d", received.Len(), len(body))
+	}
+	sum := sha256.Sum256([]byte(body))
+	got, _ := job.UploadAudit()
+	if got.Bytes != int64(len(body)) || got.SHA256 != hex.EncodeToString(sum[:]) || got.Complete {
+		t.Errorf("Job.UploadAudit() = %+v", got)
+	}
+}
+
+func TestResource_CreateChunkedJobs_AuditWriter(t *testing.T) {
+	var uploads []string
+	r, err := NewResource(&mockSessionFormatter{
+		url: "https://test.salesforce.com",
+		client: mockHTTPClient(func(req *http.Request) *http.Response {
+			switch req.Method {
+			case http.MethodPost:
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(`{"id": "7505fEXAMPLE4C2AAM", "state": "Open"}`)),
+					Header:     make(http.Header),
+				}
+			case http.MethodPut:
+				body, _ := io.ReadAll(req.Body)
+				uploads = append(uploads, string(body))
+				return &http.Response{
+					StatusCode: http.StatusCreated,
+					Body:       io.NopCloser(strings.NewReader("")),
+					Header:     make(http.Header),
+				}
+			default:
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(`{"state": "UploadComplete"}`)),
+					Header:     make(http.Header),
+				}
+			}
+		}),
+	})
+	if err != nil {
+		t.Fatalf("NewResource() error = %v", err)
+	}
+	options := Options{
+		Object:    "Account",
+		Operation: Insert,
+	}
+
+	var audit bytes.Buffer
+	jobs, err := r.CreateChunkedJobs(context.Background(), options, strings.NewReader("Name\nAcme\nGlobex\n"),
+		WithMaxChunkRecords(1), WithChunkUploadOptions(WithAuditWriter(&audit)))
+	if err != nil {
+		t.Fatalf("Resource.CreateChunkedJobs() error = %v", err)
+	}
+	if want := strings.Join(uploads, ""); audit.String() != want {
+		t.Errorf("Resource.CreateChunkedJobs() audit = %q, want %q", audit.String(), want)
+	}
+	for idx, job := range jobs {
+		got, has := job.UploadAudit()
+		if has == false || got.Bytes != int64(len(uploads[idx])) {
+			t.Errorf("Job.UploadAudit() chunk %d = %+v", idx, got)
+		}
+	}
+}
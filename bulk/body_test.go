@@ -0,0 +1,32 @@
+package bulk
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestJob_DeleteClosesBody(t *testing.T) {
+	body := newTrackingBody(`[{"errorCode": "INVALIDJOBSTATE", "message": "Job is open"}]`)
+	job := &Job{
+		session: &mockSessionFormatter{
+			url: "https://test.salesforce.com",
+			client: mockHTTPClient(func(req *http.Request) *http.Response {
+				return &http.Response{
+					StatusCode: http.StatusBadRequest,
+					Status:     "400 Bad Request",
+					Body:       body,
+					Header:     make(http.Header),
+				}
+			}),
+		},
+		info: Response{
+			ID: "1234",
+		},
+	}
+	if err := job.Delete(); err == nil {
+		t.Fatalf("Job.Delete() error = nil, want error")
+	}
+	if body.closed == false {
+		t.Errorf("Job.Delete() response body was not closed")
+	}
+}
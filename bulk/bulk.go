@@ -1,6 +1,9 @@
 package bulk
 
 import (
+	"context"
+
+	"github.com/namely/go-sfdc/v3"
 	"github.com/namely/go-sfdc/v3/session"
 	"github.com/pkg/errors"
 )
@@ -9,49 +12,104 @@ const bulk2Endpoint = "/jobs/ingest"
 
 // Resource is the structure that can be used to create bulk 2.0 jobs.
 type Resource struct {
-	session session.ServiceFormatter
+	session  session.ServiceFormatter
+	registry JobRegistry
 }
 
 // NewResource creates a new bulk 2.0 REST resource.  If the session is nil
-// an error will be returned.
-func NewResource(session session.ServiceFormatter) (*Resource, error) {
-	if session == nil {
+// an error will be returned.  The options are optional.
+func NewResource(formatter session.ServiceFormatter, options ...ResourceOption) (*Resource, error) {
+	if formatter == nil {
 		return nil, errors.New("bulk: session can not be nil")
 	}
 
-	err := session.Refresh()
+	err := session.RefreshResource(formatter)
 	if err != nil {
 		return nil, errors.Wrap(err, "session refresh")
 	}
 
-	return &Resource{
-		session: session,
-	}, nil
+	resource := &Resource{
+		session:  formatter,
+		registry: NewMemoryRegistry(),
+	}
+	for _, option := range options {
+		option(resource)
+	}
+	return resource, nil
 }
 
 // CreateJob will create a new bulk 2.0 job from the options that where passed.
 // The Job that is returned can be used to upload object data to the Salesforce org.
 func (r *Resource) CreateJob(options Options) (*Job, error) {
+	return r.CreateJobContext(context.Background(), options)
+}
+
+// CreateJobContext will create a new bulk 2.0 job using the context.  The job's
+// API calls share the correlation ID of the context, or a new one if the context
+// does not have one.  If the job is created but can not be registered with its
+// correlation key, the job is returned with the error.
+func (r *Resource) CreateJobContext(ctx context.Context, options Options, jobOpts ...JobOption) (*Job, error) {
+	opts := jobOptions{}
+	for _, option := range jobOpts {
+		option(&opts)
+	}
+	if opts.autoDelimiter && options.ColumnDelimiter == "" {
+		options.ColumnDelimiter = SuggestDelimiter(opts.sample)
+	}
+	if opts.checkCapabilities {
+		if err := r.CheckCapabilities(ctx, options.Operation); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx, end := session.StartSpan(ctx, r.session, "bulk.job.create", func() map[string]interface{} {
+		return map[string]interface{}{
+			"object":    options.Object,
+			"operation": string(options.Operation),
+		}
+	})
 	job := &Job{
-		session: r.session,
+		session:       r.session,
+		correlationID: correlationID(ctx),
+		version:       apiVersion(ctx),
 	}
-	if err := job.create(options); err != nil {
+	err := job.create(job.context(ctx), options)
+	end(err)
+	if err != nil {
 		return nil, err
 	}
 
+	if opts.correlationKey != "" {
+		if err := r.register(ctx, opts.correlationKey, job, options); err != nil {
+			return job, errors.Wrap(err, "bulk job: register")
+		}
+	}
+
 	return job, nil
 }
 
 // GetJob will retrieve an existing bulk 2.0 job using the provided ID.
 func (r *Resource) GetJob(id string) (*Job, error) {
+	return r.getJob(context.Background(), id)
+}
+
+func (r *Resource) getJob(ctx context.Context, id string) (*Job, error) {
+	ctx, end := session.StartSpan(ctx, r.session, "bulk.job.info", func() map[string]interface{} {
+		return map[string]interface{}{
+			"job.id": id,
+		}
+	})
 	job := &Job{
-		session: r.session,
+		session:       r.session,
+		correlationID: correlationID(ctx),
+		version:       apiVersion(ctx),
 	}
-	info, err := job.fetchInfo(id)
+	info, err := job.fetchInfo(job.context(ctx), id)
+	end(err)
 	if err != nil {
 		return nil, err
 	}
-	job.info = info.Response
+	job.setResponse(info.Response)
 
 	return job, nil
 }
@@ -64,3 +122,42 @@ func (r *Resource) AllJobs(parameters Parameters) (*Jobs, error) {
 	}
 	return jobs, nil
 }
+
+// CountJobs will return the number of the jobs with the parameters that match
+// the filter and are in one of the states.  Without states, the jobs in any
+// state are counted.  The listing is walked like Jobs.Count, so the filter's
+// CreatedAfter bounds the walk.
+func (r *Resource) CountJobs(ctx context.Context, parameters Parameters, filter JobFilter, states []State) (int, error) {
+	jobs, err := newJobsContext(ctx, r.session, parameters)
+	if err != nil {
+		return 0, err
+	}
+	var predicate func(Response) bool
+	if len(states) > 0 {
+		predicate = func(record Response) bool {
+			for _, state := range states {
+				if record.State == state {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	return jobs.Count(ctx, filter, predicate)
+}
+
+// correlationID returns the correlation ID of the context, or a new one if the
+// context does not have one.
+func correlationID(ctx context.Context) string {
+	if id, ok := sfdc.CorrelationID(ctx); ok {
+		return id
+	}
+	return sfdc.NewCorrelationID()
+}
+
+// apiVersion returns the API version of the context, or zero to use the
+// session's version.
+func apiVersion(ctx context.Context) int {
+	version, _ := sfdc.APIVersion(ctx)
+	return version
+}
@@ -1,7 +1,8 @@
 package bulk
 
 import (
-	"io/ioutil"
+	"context"
+	"io"
 	"net/http"
 	"reflect"
 	"strings"
@@ -26,7 +27,8 @@ func TestNewResource(t *testing.T) {
 				session: &mockSessionFormatter{},
 			},
 			want: &Resource{
-				session: &mockSessionFormatter{},
+				session:  &mockSessionFormatter{},
+				registry: NewMemoryRegistry(),
 			},
 			wantErr: false,
 		},
@@ -74,7 +76,7 @@ func TestResource_CreateJob(t *testing.T) {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "Invalid URL",
-								Body:       ioutil.NopCloser(strings.NewReader(req.URL.String())),
+								Body:       io.NopCloser(strings.NewReader(req.URL.String())),
 								Header:     make(http.Header),
 							}
 						}
@@ -99,7 +101,7 @@ func TestResource_CreateJob(t *testing.T) {
 						return &http.Response{
 							StatusCode: http.StatusOK,
 							Status:     "Good",
-							Body:       ioutil.NopCloser(strings.NewReader(resp)),
+							Body:       io.NopCloser(strings.NewReader(resp)),
 							Header:     make(http.Header),
 						}
 
@@ -152,7 +154,7 @@ func TestResource_GetJob(t *testing.T) {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "Invalid URL",
-								Body:       ioutil.NopCloser(strings.NewReader(req.URL.String())),
+								Body:       io.NopCloser(strings.NewReader(req.URL.String())),
 								Header:     make(http.Header),
 							}
 						}
@@ -177,7 +179,7 @@ func TestResource_GetJob(t *testing.T) {
 						return &http.Response{
 							StatusCode: http.StatusOK,
 							Status:     "Good",
-							Body:       ioutil.NopCloser(strings.NewReader(resp)),
+							Body:       io.NopCloser(strings.NewReader(resp)),
 							Header:     make(http.Header),
 						}
 
@@ -208,7 +210,7 @@ func TestResource_AllJobs(t *testing.T) {
 				return &http.Response{
 					StatusCode: 500,
 					Status:     "Invalid URL",
-					Body:       ioutil.NopCloser(strings.NewReader(req.URL.String())),
+					Body:       io.NopCloser(strings.NewReader(req.URL.String())),
 					Header:     make(http.Header),
 				}
 			}
@@ -238,7 +240,7 @@ func TestResource_AllJobs(t *testing.T) {
 			return &http.Response{
 				StatusCode: http.StatusOK,
 				Status:     "Good",
-				Body:       ioutil.NopCloser(strings.NewReader(resp)),
+				Body:       io.NopCloser(strings.NewReader(resp)),
 				Header:     make(http.Header),
 			}
 		}),
@@ -311,3 +313,56 @@ func TestResource_AllJobs(t *testing.T) {
 		})
 	}
 }
+
+func TestResource_CountJobs(t *testing.T) {
+	pages := map[string]string{
+		"/jobs/ingest": `{
+			"done": false,
+			"nextRecordsUrl": "https://test.salesforce.com/jobs/ingest/page2",
+			"records": [
+				{"id": "job1", "state": "InProgress"},
+				{"id": "job2", "state": "Open"}
+			]
+		}`,
+		"/jobs/ingest/page2": `{
+			"done": true,
+			"records": [
+				{"id": "job3", "state": "InProgress"},
+				{"id": "job4", "state": "JobComplete"}
+			]
+		}`,
+	}
+	r := &Resource{session: testJobsPages(pages)}
+
+	tests := []struct {
+		name   string
+		states []State
+		want   int
+	}{
+		{
+			name: "any state",
+			want: 4,
+		},
+		{
+			name:   "in progress",
+			states: []State{InProgress},
+			want:   2,
+		},
+		{
+			name:   "open or complete",
+			states: []State{Open, JobComplete},
+			want:   2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.CountJobs(context.Background(), Parameters{JobType: V2Ingest}, JobFilter{}, tt.states)
+			if err != nil {
+				t.Fatalf("Resource.CountJobs() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Resource.CountJobs() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package bulk
+
+import (
+	"context"
+
+	sfdc "github.com/namely/go-sfdc/v3"
+)
+
+// WithCapabilityCheck will check that the org and the user have the Bulk 2.0
+// ingest jobs, and the hard delete permission for a HardDelete job, before the
+// job is created.  A missing capability is a *sfdc.NotPermittedError.  The
+// capabilities of a session that keeps them, like session.Session, are not
+// probed again.
+func WithCapabilityCheck() JobOption {
+	return func(opts *jobOptions) {
+		opts.checkCapabilities = true
+	}
+}
+
+// CheckCapabilities returns a *sfdc.NotPermittedError when the session can not
+// create an ingest job with the operation, like WithCapabilityCheck, so a
+// caller can fail before it prepares the job's data.
+func (r *Resource) CheckCapabilities(ctx context.Context, operation Operation) error {
+	capabilities := []sfdc.Capability{sfdc.CapabilityBulkV2Ingest}
+	if operation == HardDelete {
+		capabilities = append(capabilities, sfdc.CapabilityHardDelete)
+	}
+	return sfdc.RequireCapabilities(ctx, r.session, capabilities...)
+}
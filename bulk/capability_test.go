@@ -0,0 +1,83 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	sfdc "github.com/namely/go-sfdc/v3"
+)
+
+// capabilityResource answers the capability probes with the hard delete
+// permission, and the job creation, and records the requests' paths.
+func capabilityResource(hardDelete bool, requests *[]string) *Resource {
+	client := mockHTTPClient(func(req *http.Request) *http.Response {
+		*requests = append(*requests, req.Method+" "+req.URL.Path)
+		var body string
+		switch {
+		case req.URL.Path == "/services/data/":
+			body = `[{"version": "42.0"}]`
+		case strings.HasSuffix(req.URL.Path, "/query/"):
+			body = fmt.Sprintf(`{"done": true, "totalSize": 1, "records": [{"PermissionsBulkApiHardDelete": %t}]}`, hardDelete)
+		default:
+			body = `{"id": "750D00000004SkVIAU", "operation": "hardDelete", "object": "Account", "state": "Open"}`
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Status:     "200 OK",
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Header:     make(http.Header),
+		}
+	})
+	return &Resource{
+		session: &mockSessionFormatter{
+			url:    "https://test.salesforce.com",
+			client: client,
+		},
+	}
+}
+
+func TestResource_CreateJobContext_WithCapabilityCheck(t *testing.T) {
+	options := Options{
+		ColumnDelimiter: Comma,
+		ContentType:     CSV,
+		LineEnding:      Linefeed,
+		Object:          "Account",
+		Operation:       HardDelete,
+	}
+
+	var requests []string
+	resource := capabilityResource(true, &requests)
+	job, err := resource.CreateJobContext(context.Background(), options, WithCapabilityCheck())
+	if err != nil {
+		t.Fatalf("Resource.CreateJobContext() error = %v", err)
+	}
+	if job.info.ID != "750D00000004SkVIAU" {
+		t.Errorf("Resource.CreateJobContext() job = %+v", job.info)
+	}
+	want := []string{"GET /services/data/", "GET /services/data/v42.0/query/", "POST /jobs/ingest"}
+	if strings.Join(requests, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Resource.CreateJobContext() requests = %v, want %v", requests, want)
+	}
+
+	requests = nil
+	resource = capabilityResource(false, &requests)
+	_, err = resource.CreateJobContext(context.Background(), options, WithCapabilityCheck())
+	var notPermitted *sfdc.NotPermittedError
+	if errors.As(err, &notPermitted) == false || notPermitted.Capability != sfdc.CapabilityHardDelete {
+		t.Fatalf("Resource.CreateJobContext() error = %v, want the hard delete capability", err)
+	}
+	if len(requests) != 2 {
+		t.Errorf("Resource.CreateJobContext() requests = %v, want only the probes", requests)
+	}
+
+	requests = nil
+	options.Operation = Insert
+	if _, err := resource.CreateJobContext(context.Background(), options, WithCapabilityCheck()); err != nil {
+		t.Errorf("Resource.CreateJobContext() error = %v, want an insert without the permission", err)
+	}
+}
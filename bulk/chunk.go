@@ -0,0 +1,249 @@
+package bulk
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/namely/go-sfdc/v3"
+)
+
+// ChunkOption is an option for splitting the CSV data into chunks.
+type ChunkOption func(*chunkOptions)
+
+type chunkOptions struct {
+	maxBytes   int
+	maxRecords int
+	retry      *sfdc.RetryPolicy
+	upload     []UploadOption
+}
+
+// WithMaxChunkBytes will limit the size of each chunk, including its header.
+// The default is sfdc.BulkMaxChunkBytes, the most data whose base64 encoding
+// fits in the Bulk 2.0 upload limit.
+func WithMaxChunkBytes(maxBytes int) ChunkOption {
+	return func(opts *chunkOptions) {
+		opts.maxBytes = maxBytes
+	}
+}
+
+// WithMaxChunkRecords will limit the number of records of each chunk.  There is
+// no limit by default.
+func WithMaxChunkRecords(maxRecords int) ChunkOption {
+	return func(opts *chunkOptions) {
+		opts.maxRecords = maxRecords
+	}
+}
+
+// WithChunkRetry will retry the creation, upload and close of each chunk's job
+// with the retry policy.  The chunks are not retried by default.
+func WithChunkRetry(policy sfdc.RetryPolicy) ChunkOption {
+	return func(opts *chunkOptions) {
+		opts.retry = &policy
+	}
+}
+
+// WithChunkUploadOptions will upload each chunk with the upload options, like
+// WithAuditWriter.  A chunk's data is audited again when its upload is retried.
+func WithChunkUploadOptions(options ...UploadOption) ChunkOption {
+	return func(opts *chunkOptions) {
+		opts.upload = options
+	}
+}
+
+func newChunkOptions(options []ChunkOption) chunkOptions {
+	opts := chunkOptions{
+		maxBytes: sfdc.BulkMaxChunkBytes,
+	}
+	for _, option := range options {
+		option(&opts)
+	}
+	return opts
+}
+
+// do calls the function, retrying it with the retry policy if there is one.
+func (opts chunkOptions) do(ctx context.Context, fn func(context.Context) error) error {
+	if opts.retry == nil {
+		return fn(ctx)
+	}
+	return sfdc.Retry(ctx, *opts.retry, fn)
+}
+
+// Chunk is a part of the CSV data, which has the header and whole records.
+//
+// Index is the position of the chunk, starting at zero.
+//
+// Records is the number of records in the chunk.
+//
+// Data is the CSV data of the chunk, including the header.
+type Chunk struct {
+	Index   int
+	Records int
+	Data    []byte
+}
+
+// ChunkError is returned when one of the chunked jobs fails.
+//
+// Chunk is the index of the chunk that failed.
+//
+// Jobs are the jobs that were created, in chunk order.  The last job is the
+// failed chunk's job when the job was created but its upload or close failed.
+//
+// Err is the error of the chunk.
+type ChunkError struct {
+	Chunk int
+	Jobs  []*Job
+	Err   error
+}
+
+func (e *ChunkError) Error() string {
+	return fmt.Sprintf("bulk chunked jobs: chunk %d (%d jobs created): %s", e.Chunk, len(e.Jobs), e.Err.Error())
+}
+
+// Unwrap returns the error of the chunk.
+func (e *ChunkError) Unwrap() error {
+	return e.Err
+}
+
+// SplitCSV will split the CSV data into chunks on the record boundaries, calling
+// the function with each chunk.  The header is the first record and is repeated
+// at the start of each chunk.  A record that does not fit in a chunk by itself is
+// an error.
+func SplitCSV(body io.Reader, fn func(Chunk) error, options ...ChunkOption) error {
+	if body == nil {
+		return errors.New("bulk split csv: body can not be nil")
+	}
+	if fn == nil {
+		return errors.New("bulk split csv: function can not be nil")
+	}
+	opts := newChunkOptions(options)
+	if opts.maxBytes <= 0 {
+		return errors.New("bulk split csv: max bytes must be greater than zero")
+	}
+	if opts.maxRecords < 0 {
+		return errors.New("bulk split csv: max records can not be negative")
+	}
+
+	reader := bufio.NewReader(body)
+	header, err := readCSVRecord(reader)
+	if err == io.EOF {
+		return errors.New("bulk split csv: body is empty")
+	}
+	if err != nil {
+		return err
+	}
+	if len(header) >= opts.maxBytes {
+		return fmt.Errorf("bulk split csv: header of %d bytes does not fit in %d bytes", len(header), opts.maxBytes)
+	}
+
+	chunk := Chunk{}
+	var data bytes.Buffer
+	data.Write(header)
+	row := 0
+	for {
+		record, err := readCSVRecord(reader)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		row++
+		if len(header)+len(record) > opts.maxBytes {
+			return fmt.Errorf("bulk split csv: row %d of %d bytes does not fit in %d bytes with the header", row, len(record), opts.maxBytes)
+		}
+		full := chunk.Records > 0 &&
+			(data.Len()+len(record) > opts.maxBytes || (opts.maxRecords > 0 && chunk.Records == opts.maxRecords))
+		if full {
+			chunk.Data = data.Bytes()
+			if err := fn(chunk); err != nil {
+				return err
+			}
+			chunk = Chunk{
+				Index: chunk.Index + 1,
+			}
+			data = bytes.Buffer{}
+			data.Write(header)
+		}
+		data.Write(record)
+		chunk.Records++
+	}
+	if chunk.Records == 0 {
+		return nil
+	}
+	chunk.Data = data.Bytes()
+	return fn(chunk)
+}
+
+// readCSVRecord reads the next record, including its line ending.  A line ending
+// in a quoted value is part of the record.  A last record without a line ending
+// is given one.
+func readCSVRecord(reader *bufio.Reader) ([]byte, error) {
+	var record []byte
+	quoted := false
+	for {
+		line, err := reader.ReadBytes('\n')
+		record = append(record, line...)
+		quoted = quoted != (bytes.Count(line, []byte{'"'})%2 == 1)
+		if err == io.EOF {
+			if len(record) == 0 {
+				return nil, io.EOF
+			}
+			if quoted {
+				return nil, errors.New("bulk split csv: quoted value is not terminated")
+			}
+			return append(record, '\n'), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if quoted == false {
+			return record, nil
+		}
+	}
+}
+
+// CreateChunkedJobs will split the CSV data into chunks and create a job for each
+// chunk from the options.  Each job's data is uploaded and the job is closed, so
+// that the jobs are processed.  The jobs are returned in chunk order.  If a chunk
+// fails, a ChunkError is returned with the jobs that were created.
+func (r *Resource) CreateChunkedJobs(ctx context.Context, options Options, body io.Reader, chunkOpts ...ChunkOption) ([]*Job, error) {
+	opts := newChunkOptions(chunkOpts)
+	var jobs []*Job
+	err := SplitCSV(body, func(chunk Chunk) error {
+		if err := ctx.Err(); err != nil {
+			return &ChunkError{Chunk: chunk.Index, Jobs: jobs, Err: err}
+		}
+		var job *Job
+		err := opts.do(ctx, func(ctx context.Context) error {
+			var err error
+			job, err = r.CreateJobContext(ctx, options)
+			return err
+		})
+		if err != nil {
+			return &ChunkError{Chunk: chunk.Index, Jobs: jobs, Err: err}
+		}
+		jobs = append(jobs, job)
+		err = opts.do(ctx, func(context.Context) error {
+			return job.Upload(bytes.NewReader(chunk.Data), opts.upload...)
+		})
+		if err != nil {
+			return &ChunkError{Chunk: chunk.Index, Jobs: jobs, Err: err}
+		}
+		err = opts.do(ctx, func(context.Context) error {
+			_, err := job.Close()
+			return err
+		})
+		if err != nil {
+			return &ChunkError{Chunk: chunk.Index, Jobs: jobs, Err: err}
+		}
+		return nil
+	}, chunkOpts...)
+	if err != nil {
+		return jobs, err
+	}
+	return jobs, nil
+}
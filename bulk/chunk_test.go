@@ -0,0 +1,235 @@
+package bulk
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/namely/go-sfdc/v3"
+)
+
+func TestSplitCSV(t *testing.T) {
+	body := "Name,Site\n" +
+		"Acme,East\n" +
+		"\"Globex, Inc\",\"West\nCoast\"\n" +
+		"Initech,North\n" +
+		"Umbrella,South"
+	tests := []struct {
+		name    string
+		options []ChunkOption
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "default limits",
+			want: []string{
+				"Name,Site\nAcme,East\n\"Globex, Inc\",\"West\nCoast\"\nInitech,North\nUmbrella,South\n",
+			},
+		},
+		{
+			name:    "record limit",
+			options: []ChunkOption{WithMaxChunkRecords(3)},
+			want: []string{
+				"Name,Site\nAcme,East\n\"Globex, Inc\",\"West\nCoast\"\nInitech,North\n",
+				"Name,Site\nUmbrella,South\n",
+			},
+		},
+		{
+			name:    "byte limit",
+			options: []ChunkOption{WithMaxChunkBytes(40)},
+			want: []string{
+				"Name,Site\nAcme,East\n",
+				"Name,Site\n\"Globex, Inc\",\"West\nCoast\"\n",
+				"Name,Site\nInitech,North\nUmbrella,South\n",
+			},
+		},
+		{
+			name:    "record too large",
+			options: []ChunkOption{WithMaxChunkBytes(20)},
+			wantErr: true,
+		},
+		{
+			name:    "invalid byte limit",
+			options: []ChunkOption{WithMaxChunkBytes(0)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			err := SplitCSV(strings.NewReader(body), func(chunk Chunk) error {
+				if chunk.Index != len(got) {
+					t.Errorf("Chunk.Index = %d, want %d", chunk.Index, len(got))
+				}
+				got = append(got, string(chunk.Data))
+				return nil
+			}, tt.options...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SplitCSV() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if reflect.DeepEqual(got, tt.want) == false {
+				t.Errorf("SplitCSV() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitCSV_unterminatedQuote(t *testing.T) {
+	err := SplitCSV(strings.NewReader("Name\n\"Acme\n"), func(Chunk) error { return nil })
+	if err == nil {
+		t.Errorf("SplitCSV() error = nil for an unterminated quote")
+	}
+}
+
+func TestResource_CreateChunkedJobs(t *testing.T) {
+	var uploads []string
+	created := 0
+	failUpload := 0
+	r, err := NewResource(&mockSessionFormatter{
+		url: "https://test.salesforce.com",
+		client: mockHTTPClient(func(req *http.Request) *http.Response {
+			switch req.Method {
+			case http.MethodPost:
+				created++
+				resp := fmt.Sprintf(`{"id": "7505fEXAMPLE%dAAM", "state": "Open"}`, created)
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(resp)),
+					Header:     make(http.Header),
+				}
+			case http.MethodPut:
+				body, _ := io.ReadAll(req.Body)
+				uploads = append(uploads, string(body))
+				if len(uploads) == failUpload {
+					return &http.Response{
+						StatusCode: http.StatusBadRequest,
+						Status:     "400 Bad Request",
+						Body:       io.NopCloser(strings.NewReader(`[{"errorCode": "INVALIDJOBSTATE", "message": "Job is not open"}]`)),
+						Header:     make(http.Header),
+					}
+				}
+				return &http.Response{
+					StatusCode: http.StatusCreated,
+					Body:       io.NopCloser(strings.NewReader("")),
+					Header:     make(http.Header),
+				}
+			default:
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(`{"state": "UploadComplete"}`)),
+					Header:     make(http.Header),
+				}
+			}
+		}),
+	})
+	if err != nil {
+		t.Fatalf("NewResource() error = %v", err)
+	}
+	options := Options{
+		Object:    "Account",
+		Operation: Insert,
+	}
+	body := "Name\nAcme\nGlobex\nInitech\n"
+
+	jobs, err := r.CreateChunkedJobs(context.Background(), options, strings.NewReader(body), WithMaxChunkRecords(2))
+	if err != nil {
+		t.Fatalf("Resource.CreateChunkedJobs() error = %v", err)
+	}
+	if len(jobs) != 2 || jobs[0].info.ID != "7505fEXAMPLE1AAM" || jobs[1].info.ID != "7505fEXAMPLE2AAM" {
+		t.Errorf("Resource.CreateChunkedJobs() jobs = %v", jobs)
+	}
+	want := []string{"Name\nAcme\nGlobex\n", "Name\nInitech\n"}
+	if reflect.DeepEqual(uploads, want) == false {
+		t.Errorf("Resource.CreateChunkedJobs() uploads = %q, want %q", uploads, want)
+	}
+
+	uploads = nil
+	created = 0
+	failUpload = 2
+	jobs, err = r.CreateChunkedJobs(context.Background(), options, strings.NewReader(body), WithMaxChunkRecords(1))
+	var chunkErr *ChunkError
+	if errors.As(err, &chunkErr) == false {
+		t.Fatalf("Resource.CreateChunkedJobs() error = %v, want a ChunkError", err)
+	}
+	if chunkErr.Chunk != 1 || len(chunkErr.Jobs) != 2 || len(jobs) != 2 {
+		t.Errorf("ChunkError = %v, jobs = %d", chunkErr, len(jobs))
+	}
+	if created != 2 {
+		t.Errorf("Resource.CreateChunkedJobs() created %d jobs after the failure, want 2", created)
+	}
+}
+
+func TestResource_CreateChunkedJobs_retry(t *testing.T) {
+	uploads := 0
+	r, err := NewResource(&mockSessionFormatter{
+		url: "https://test.salesforce.com",
+		client: mockHTTPClient(func(req *http.Request) *http.Response {
+			switch req.Method {
+			case http.MethodPost:
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(`{"id": "7505fEXAMPLE4C2AAM", "state": "Open"}`)),
+					Header:     make(http.Header),
+				}
+			case http.MethodPut:
+				uploads++
+				if uploads == 1 {
+					return &http.Response{
+						StatusCode: http.StatusServiceUnavailable,
+						Status:     "503 Service Unavailable",
+						Body:       io.NopCloser(strings.NewReader(`[{"errorCode": "SERVER_UNAVAILABLE", "message": "Server is unavailable"}]`)),
+						Header:     make(http.Header),
+					}
+				}
+				return &http.Response{
+					StatusCode: http.StatusCreated,
+					Body:       io.NopCloser(strings.NewReader("")),
+					Header:     make(http.Header),
+				}
+			default:
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(`{"state": "UploadComplete"}`)),
+					Header:     make(http.Header),
+				}
+			}
+		}),
+	})
+	if err != nil {
+		t.Fatalf("NewResource() error = %v", err)
+	}
+	policy := sfdc.NewRetryPolicy()
+	policy.InitialInterval = time.Millisecond
+
+	options := Options{
+		Object:    "Account",
+		Operation: Insert,
+	}
+	jobs, err := r.CreateChunkedJobs(context.Background(), options, strings.NewReader("Name\nAcme\n"), WithChunkRetry(policy))
+	if err != nil {
+		t.Fatalf("Resource.CreateChunkedJobs() error = %v", err)
+	}
+	if len(jobs) != 1 || uploads != 2 {
+		t.Errorf("Resource.CreateChunkedJobs() = %d jobs after %d uploads, want 1 job after 2 uploads", len(jobs), uploads)
+	}
+}
+
+func TestNewChunkOptions_base64Limit(t *testing.T) {
+	opts := newChunkOptions(nil)
+	if encoded := base64.StdEncoding.EncodedLen(opts.maxBytes); encoded > sfdc.BulkMaxUploadBytes {
+		t.Errorf("newChunkOptions() max bytes = %d, base64 encoded %d over the upload limit %d", opts.maxBytes, encoded, sfdc.BulkMaxUploadBytes)
+	}
+	if opts.maxBytes != sfdc.BulkMaxChunkBytes {
+		t.Errorf("newChunkOptions() max bytes = %d, want %d", opts.maxBytes, sfdc.BulkMaxChunkBytes)
+	}
+}
@@ -0,0 +1,166 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/namely/go-sfdc/v3/session"
+)
+
+// CleanupAction is the action taken on a stale job.
+type CleanupAction string
+
+const (
+	// CleanupAbort the open job is aborted.
+	CleanupAbort CleanupAction = "Abort"
+	// CleanupDelete the finished job is deleted.
+	CleanupDelete CleanupAction = "Delete"
+)
+
+// CleanedJob is a stale job that was, or in a dry run would be, cleaned up.
+//
+// Job is the job's information from the listing.
+//
+// Action is the action taken on the job.
+//
+// Err is the error of the action.  It is nil in a dry run.
+type CleanedJob struct {
+	Job    Response
+	Action CleanupAction
+	Err    error
+}
+
+// CleanupReport is the report of the stale job cleanup.
+//
+// DryRun is true when no action was taken.
+//
+// Jobs are the stale jobs, newest first.
+//
+// Failed is the number of jobs whose action failed.
+type CleanupReport struct {
+	DryRun bool
+	Jobs   []CleanedJob
+	Failed int
+}
+
+// CleanupOption is an option for the stale job cleanup.
+type CleanupOption func(*cleanupOptions)
+
+type cleanupOptions struct {
+	allUsers bool
+}
+
+// WithOtherUsersJobs will also clean up the stale jobs created by the other
+// users of the org.
+func WithOtherUsersJobs() CleanupOption {
+	return func(opts *cleanupOptions) {
+		opts.allUsers = true
+	}
+}
+
+type identifier interface {
+	Identity() (session.Identity, error)
+}
+
+// CleanupStaleJobs will abort or delete the jobs in the states that were
+// created more than olderThan ago, like the jobs left open by a worker that
+// crashed.  The Open and UploadComplete jobs are aborted and the JobComplete,
+// Failed and Aborted jobs are deleted.  The states default to Open and
+// UploadComplete.  Only the jobs created by the session's user are cleaned up,
+// unless WithOtherUsersJobs is used, so the session must have an identity.
+// A dry run reports the jobs without taking any action.  A failed action is in
+// the report and does not stop the cleanup.
+func (r *Resource) CleanupStaleJobs(ctx context.Context, olderThan time.Duration, states []State, dryRun bool, options ...CleanupOption) (_ CleanupReport, err error) {
+	if olderThan < 0 {
+		return CleanupReport{}, errors.New("bulk cleanup: older than can not be negative")
+	}
+	opts := cleanupOptions{}
+	for _, option := range options {
+		option(&opts)
+	}
+	if len(states) == 0 {
+		states = []State{Open, UpdateComplete}
+	}
+	actions := make(map[State]CleanupAction, len(states))
+	for _, state := range states {
+		switch state {
+		case Open, UpdateComplete:
+			actions[state] = CleanupAbort
+		case JobComplete, Failed, Aborted:
+			actions[state] = CleanupDelete
+		default:
+			return CleanupReport{}, fmt.Errorf("bulk cleanup: state %s can not be cleaned up", state)
+		}
+	}
+
+	filter := JobFilter{
+		CreatedBefore: time.Now().Add(-olderThan),
+	}
+	if opts.allUsers == false {
+		identity, ok := r.session.(identifier)
+		if ok == false {
+			return CleanupReport{}, errors.New("bulk cleanup: the session has no identity to find its user's jobs")
+		}
+		user, err := identity.Identity()
+		if err != nil {
+			return CleanupReport{}, err
+		}
+		filter.CreatedByID = user.UserID
+	}
+
+	ctx, end := session.StartSpan(ctx, r.session, "bulk.job.cleanup", func() map[string]interface{} {
+		return map[string]interface{}{
+			"dry_run": dryRun,
+		}
+	})
+	defer func() { end(err) }()
+
+	jobs, err := newJobsContext(ctx, r.session, Parameters{
+		JobType: V2Ingest,
+	})
+	if err != nil {
+		return CleanupReport{}, err
+	}
+	var stale []Response
+	err = jobs.Each(filter, func(record Response) bool {
+		if _, has := actions[record.State]; has {
+			stale = append(stale, record)
+		}
+		return true
+	})
+	if err != nil {
+		return CleanupReport{}, err
+	}
+
+	report := CleanupReport{
+		DryRun: dryRun,
+	}
+	for _, record := range stale {
+		cleaned := CleanedJob{
+			Job:    record,
+			Action: actions[record.State],
+		}
+		if dryRun == false {
+			if err := ctx.Err(); err != nil {
+				return report, err
+			}
+			job := &Job{
+				session: r.session,
+				info:    record,
+			}
+			switch cleaned.Action {
+			case CleanupAbort:
+				_, cleaned.Err = job.setState(ctx, Aborted)
+			case CleanupDelete:
+				cleaned.Err = job.delete(ctx)
+			}
+			if cleaned.Err != nil {
+				report.Failed++
+			}
+		}
+		report.Jobs = append(report.Jobs, cleaned)
+	}
+	return report, nil
+}
@@ -0,0 +1,150 @@
+package bulk
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/namely/go-sfdc/v3/session"
+)
+
+type mockIdentitySession struct {
+	mockSessionFormatter
+	userID string
+}
+
+func (mock *mockIdentitySession) Identity() (session.Identity, error) {
+	return session.Identity{UserID: mock.userID}, nil
+}
+
+func cleanupResource(t *testing.T, requests *[]string) *Resource {
+	t.Helper()
+	old := time.Now().Add(-48 * time.Hour).UTC().Format("2006-01-02T15:04:05.000+0000")
+	recent := time.Now().Add(-time.Minute).UTC().Format("2006-01-02T15:04:05.000+0000")
+	job := func(id, createdBy, created string, state State) string {
+		return fmt.Sprintf(`{"id": "%s", "createdById": "%s", "createdDate": "%s", "state": "%s"}`, id, createdBy, created, state)
+	}
+	listing := `{"done": true, "records": [` + strings.Join([]string{
+		job("750MINENEWOPEN", "005ME", recent, Open),
+		job("750MINEOPEN", "005ME", old, Open),
+		job("750MINEUPLOADED", "005ME", old, UpdateComplete),
+		job("750MINECOMPLETE", "005ME", old, JobComplete),
+		job("750OTHEROPEN", "005OTHER", old, Open),
+	}, ",") + `]}`
+	return &Resource{
+		session: &mockIdentitySession{
+			mockSessionFormatter: mockSessionFormatter{
+				url: "https://test.salesforce.com",
+				client: mockHTTPClient(func(req *http.Request) *http.Response {
+					switch req.Method {
+					case http.MethodGet:
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Body:       io.NopCloser(strings.NewReader(listing)),
+							Header:     make(http.Header),
+						}
+					case http.MethodDelete:
+						*requests = append(*requests, "DELETE "+req.URL.Path)
+						return &http.Response{
+							StatusCode: http.StatusNoContent,
+							Body:       io.NopCloser(strings.NewReader("")),
+							Header:     make(http.Header),
+						}
+					default:
+						body, _ := io.ReadAll(req.Body)
+						*requests = append(*requests, req.Method+" "+req.URL.Path+" "+string(body))
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Body:       io.NopCloser(strings.NewReader(`{"state": "Aborted"}`)),
+							Header:     make(http.Header),
+						}
+					}
+				}),
+			},
+			userID: "005ME",
+		},
+	}
+}
+
+func cleanedJobs(report CleanupReport) []string {
+	var jobs []string
+	for _, job := range report.Jobs {
+		jobs = append(jobs, string(job.Action)+" "+job.Job.ID)
+	}
+	return jobs
+}
+
+func TestResource_CleanupStaleJobs_DryRun(t *testing.T) {
+	var requests []string
+	r := cleanupResource(t, &requests)
+	report, err := r.CleanupStaleJobs(context.Background(), 24*time.Hour, []State{Open, UpdateComplete, JobComplete}, true)
+	if err != nil {
+		t.Fatalf("Resource.CleanupStaleJobs() error = %v", err)
+	}
+	want := []string{"Abort 750MINEOPEN", "Abort 750MINEUPLOADED", "Delete 750MINECOMPLETE"}
+	if got := cleanedJobs(report); !reflect.DeepEqual(got, want) {
+		t.Errorf("Resource.CleanupStaleJobs() jobs = %v, want %v", got, want)
+	}
+	if report.DryRun == false || len(requests) != 0 {
+		t.Errorf("Resource.CleanupStaleJobs() dry run = %v, requests = %v", report.DryRun, requests)
+	}
+}
+
+func TestResource_CleanupStaleJobs(t *testing.T) {
+	var requests []string
+	r := cleanupResource(t, &requests)
+	report, err := r.CleanupStaleJobs(context.Background(), 24*time.Hour, []State{Open, JobComplete}, false)
+	if err != nil {
+		t.Fatalf("Resource.CleanupStaleJobs() error = %v", err)
+	}
+	want := []string{"Abort 750MINEOPEN", "Delete 750MINECOMPLETE"}
+	if got := cleanedJobs(report); !reflect.DeepEqual(got, want) {
+		t.Errorf("Resource.CleanupStaleJobs() jobs = %v, want %v", got, want)
+	}
+	wantRequests := []string{
+		`PATCH /jobs/ingest/750MINEOPEN {"state":"Aborted"}`,
+		"DELETE /jobs/ingest/750MINECOMPLETE",
+	}
+	if !reflect.DeepEqual(requests, wantRequests) {
+		t.Errorf("Resource.CleanupStaleJobs() requests = %v, want %v", requests, wantRequests)
+	}
+	if report.DryRun || report.Failed != 0 {
+		t.Errorf("Resource.CleanupStaleJobs() report = %+v", report)
+	}
+}
+
+func TestResource_CleanupStaleJobs_OtherUsers(t *testing.T) {
+	var requests []string
+	r := cleanupResource(t, &requests)
+	report, err := r.CleanupStaleJobs(context.Background(), 24*time.Hour, nil, true)
+	if err != nil {
+		t.Fatalf("Resource.CleanupStaleJobs() error = %v", err)
+	}
+	want := []string{"Abort 750MINEOPEN", "Abort 750MINEUPLOADED"}
+	if got := cleanedJobs(report); !reflect.DeepEqual(got, want) {
+		t.Errorf("Resource.CleanupStaleJobs() jobs = %v, want %v", got, want)
+	}
+
+	report, err = r.CleanupStaleJobs(context.Background(), 24*time.Hour, nil, true, WithOtherUsersJobs())
+	if err != nil {
+		t.Fatalf("Resource.CleanupStaleJobs() error = %v", err)
+	}
+	want = []string{"Abort 750MINEOPEN", "Abort 750MINEUPLOADED", "Abort 750OTHEROPEN"}
+	if got := cleanedJobs(report); !reflect.DeepEqual(got, want) {
+		t.Errorf("Resource.CleanupStaleJobs() jobs = %v, want %v", got, want)
+	}
+
+	// the other users' jobs can not be excluded without the session's identity
+	r.session = &r.session.(*mockIdentitySession).mockSessionFormatter
+	if _, err := r.CleanupStaleJobs(context.Background(), 24*time.Hour, nil, true); err == nil {
+		t.Error("Resource.CleanupStaleJobs() expected an error without an identity")
+	}
+	if _, err := r.CleanupStaleJobs(context.Background(), 24*time.Hour, []State{"InProgress"}, true); err == nil {
+		t.Error("Resource.CleanupStaleJobs() expected an error for an unsupported state")
+	}
+}
@@ -0,0 +1,351 @@
+// Package columnar reads the bulk 2.0 query results into column-oriented
+// batches of typed slices.
+package columnar
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/namely/go-sfdc/v3"
+	"github.com/namely/go-sfdc/v3/bulk"
+)
+
+// DefaultBatchSize is the number of rows in a batch when the batch size is not set.
+const DefaultBatchSize = 10000
+
+// Type is the type of a column.
+type Type string
+
+const (
+	// String is a string column.  This is the type of the columns that are
+	// not in the schema.
+	String Type = "string"
+	// Int64 is an integer column.
+	Int64 Type = "int64"
+	// Float64 is a floating point column.
+	Float64 Type = "float64"
+	// Bool is a boolean column.
+	Bool Type = "bool"
+	// Time is a date or date time column.
+	Time Type = "time"
+)
+
+// Schema is the column name to type hint.  Columns that are not in the
+// schema are String columns.
+type Schema map[string]Type
+
+// Column is a column of a batch.  Only the slice of the column's type is
+// populated.
+//
+// Name is the column name.
+//
+// Type is the column type.
+//
+// Valid is the null bitmap, where a false value is a null row.  The value of
+// a null row is the zero value of the type.
+type Column struct {
+	Name     string
+	Type     Type
+	Strings  []string
+	Int64s   []int64
+	Float64s []float64
+	Bools    []bool
+	Times    []time.Time
+	Valid    []bool
+}
+
+// IsNull returns whether the row of the column is null.
+func (c *Column) IsNull(row int) bool {
+	return c.Valid[row] == false
+}
+
+// cell is a value that is parsed as the type of its column.  A cell that is
+// not valid is null.
+type cell struct {
+	valid bool
+	str   string
+	int   int64
+	float float64
+	bool  bool
+	time  time.Time
+}
+
+// parse returns the cell of the value, without appending it to the column.
+func (c *Column) parse(value string) (cell, error) {
+	if value == "" {
+		return cell{}, nil
+	}
+	v := cell{
+		valid: true,
+	}
+	var err error
+	switch c.Type {
+	case Int64:
+		v.int, err = strconv.ParseInt(value, 10, 64)
+	case Float64:
+		v.float, err = strconv.ParseFloat(value, 64)
+	case Bool:
+		v.bool, err = strconv.ParseBool(value)
+	case Time:
+		v.time, err = sfdc.ParseTime(value)
+	default:
+		v.str = value
+	}
+	return v, err
+}
+
+// append appends the cell, which is the zero value of the type when it is
+// null.
+func (c *Column) append(v cell) {
+	switch c.Type {
+	case Int64:
+		c.Int64s = append(c.Int64s, v.int)
+	case Float64:
+		c.Float64s = append(c.Float64s, v.float)
+	case Bool:
+		c.Bools = append(c.Bools, v.bool)
+	case Time:
+		c.Times = append(c.Times, v.time)
+	default:
+		c.Strings = append(c.Strings, v.str)
+	}
+	c.Valid = append(c.Valid, v.valid)
+}
+
+// Batch is a set of rows stored by column.
+//
+// Columns are the columns in the order of the fields.
+//
+// Rows is the number of rows in the batch.
+type Batch struct {
+	Columns []*Column
+	Rows    int
+}
+
+// Column returns the column by name, or nil if the batch does not have it.
+func (b *Batch) Column(name string) *Column {
+	for _, column := range b.Columns {
+		if column.Name == name {
+			return column
+		}
+	}
+	return nil
+}
+
+// Batcher accumulates rows into batches and passes each full batch to the
+// callback.  Empty values are null.
+type Batcher struct {
+	fields    []string
+	schema    Schema
+	batchSize int
+	callback  func(*Batch) error
+	batch     *Batch
+	cells     []cell
+	row       int
+}
+
+// NewBatcher creates a batcher for the fields.  If the batch size is zero or
+// less, DefaultBatchSize is used.
+func NewBatcher(fields []string, schema Schema, batchSize int, callback func(*Batch) error) (*Batcher, error) {
+	if len(fields) == 0 {
+		return nil, errors.New("bulk columnar: fields can not be empty")
+	}
+	if callback == nil {
+		return nil, errors.New("bulk columnar: callback can not be nil")
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	return &Batcher{
+		fields:    fields,
+		schema:    schema,
+		batchSize: batchSize,
+		callback:  callback,
+		cells:     make([]cell, len(fields)),
+	}, nil
+}
+
+// Add adds a row with the values in the order of the fields.  The values are
+// parsed before any of them are added, so a row that can not be read is not
+// added and the columns stay aligned.  The batch is passed to the callback
+// when it is full.
+func (b *Batcher) Add(values []string) error {
+	if len(values) != len(b.fields) {
+		return fmt.Errorf("bulk columnar: row %d has %d values, expected %d", b.row+1, len(values), len(b.fields))
+	}
+	if b.batch == nil {
+		b.batch = b.newBatch()
+	}
+	for idx, value := range values {
+		column := b.batch.Columns[idx]
+		v, err := column.parse(value)
+		if err != nil {
+			return fmt.Errorf("bulk columnar: row %d column %s can not be read as %s: %w", b.row+1, column.Name, column.Type, err)
+		}
+		b.cells[idx] = v
+	}
+	for idx, column := range b.batch.Columns {
+		column.append(b.cells[idx])
+	}
+	b.batch.Rows++
+	b.row++
+
+	if b.batch.Rows >= b.batchSize {
+		return b.Flush()
+	}
+	return nil
+}
+
+// AddRecord adds a row from the record.  Fields that are not in the record
+// are null.
+func (b *Batcher) AddRecord(record map[string]string) error {
+	values := make([]string, len(b.fields))
+	for idx, field := range b.fields {
+		values[idx] = record[field]
+	}
+	return b.Add(values)
+}
+
+// Flush passes the current batch to the callback if it has rows.
+func (b *Batcher) Flush() error {
+	if b.batch == nil || b.batch.Rows == 0 {
+		return nil
+	}
+	batch := b.batch
+	b.batch = nil
+	return b.callback(batch)
+}
+
+func (b *Batcher) newBatch() *Batch {
+	batch := &Batch{
+		Columns: make([]*Column, len(b.fields)),
+	}
+	for idx, field := range b.fields {
+		columnType, has := b.schema[field]
+		if has == false {
+			columnType = String
+		}
+		batch.Columns[idx] = &Column{
+			Name: field,
+			Type: columnType,
+		}
+	}
+	return batch
+}
+
+// ReadCSV reads the CSV query results, with the header as the first row, into
+// batches.  The columns are in the order of the header.
+func ReadCSV(reader io.Reader, delimiter rune, schema Schema, batchSize int, callback func(*Batch) error) error {
+	batcher, err := readRows(reader, delimiter, nil, func(fields []string) (*Batcher, error) {
+		return NewBatcher(fields, schema, batchSize, callback)
+	})
+	if err != nil || batcher == nil {
+		return err
+	}
+	return batcher.Flush()
+}
+
+// readRows reads the CSV rows into the batcher.  When the batcher is nil, it
+// is created with the fields of the header, and otherwise the header must be
+// the batcher's fields.  The batcher is nil when there is no header.
+func readRows(reader io.Reader, delimiter rune, batcher *Batcher, newBatcher func([]string) (*Batcher, error)) (*Batcher, error) {
+	csvReader := csv.NewReader(reader)
+	if delimiter != 0 {
+		csvReader.Comma = delimiter
+	}
+	csvReader.ReuseRecord = true
+
+	header, err := csvReader.Read()
+	if err == io.EOF {
+		return batcher, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if batcher == nil {
+		fields := make([]string, len(header))
+		copy(fields, header)
+		if batcher, err = newBatcher(fields); err != nil {
+			return nil, err
+		}
+	} else if sameFields(batcher.fields, header) == false {
+		return nil, fmt.Errorf("bulk columnar: header %v is not the fields %v", header, batcher.fields)
+	}
+	for {
+		values, err := csvReader.Read()
+		if err == io.EOF {
+			return batcher, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := batcher.Add(values); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func sameFields(fields, header []string) bool {
+	if len(fields) != len(header) {
+		return false
+	}
+	for idx, field := range fields {
+		if header[idx] != field {
+			return false
+		}
+	}
+	return true
+}
+
+// Pager returns the body of a page of the query results.  It is implemented
+// by bulk.QueryJob.
+type Pager interface {
+	Response() bulk.Response
+	ResultsRaw(ctx context.Context, locator string, maxRecords int) (io.ReadCloser, *bulk.ResultsPageInfo, error)
+}
+
+var _ Pager = (*bulk.QueryJob)(nil)
+
+// ReadQuery reads all of the pages of the query results into batches.  The
+// CSV rows of the pages are read straight into the columns, which are in the
+// order of the header, with the query job's column delimiter.  The pages have
+// at most bulk.DefaultQueryResultPageSize records.  A query job whose content
+// type is not CSV is an error.
+func ReadQuery(ctx context.Context, pager Pager, schema Schema, batchSize int, callback func(*Batch) error) error {
+	if pager == nil {
+		return errors.New("bulk columnar: pager can not be nil")
+	}
+	info := pager.Response()
+	if info.ContentType != "" && info.ContentType != bulk.CSV {
+		return fmt.Errorf("bulk columnar: query job content type %s is not %s", info.ContentType, bulk.CSV)
+	}
+
+	var batcher *Batcher
+	locator := ""
+	for {
+		body, page, err := pager.ResultsRaw(ctx, locator, bulk.DefaultQueryResultPageSize)
+		if err != nil {
+			return err
+		}
+		batcher, err = readRows(body, info.ColumnDelimiter.Rune(), batcher, func(fields []string) (*Batcher, error) {
+			return NewBatcher(fields, schema, batchSize, callback)
+		})
+		body.Close()
+		if err != nil {
+			return err
+		}
+		if page.Locator == "" {
+			break
+		}
+		locator = page.Locator
+	}
+	if batcher == nil {
+		return nil
+	}
+	return batcher.Flush()
+}
@@ -0,0 +1,315 @@
+package columnar
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/namely/go-sfdc/v3/bulk"
+)
+
+func TestReadCSV(t *testing.T) {
+	body := strings.Join([]string{
+		`"Id","Name","NumberOfEmployees","AnnualRevenue","IsDeleted","CreatedDate"`,
+		`"001","Acme","10","1.5","false","2020-01-02T03:04:05.000+0000"`,
+		`"002","","","","true",""`,
+		`"003","Global","30","","",""`,
+	}, "\n")
+	schema := Schema{
+		"NumberOfEmployees": Int64,
+		"AnnualRevenue":     Float64,
+		"IsDeleted":         Bool,
+		"CreatedDate":       Time,
+	}
+
+	var batches []*Batch
+	err := ReadCSV(strings.NewReader(body), ',', schema, 2, func(batch *Batch) error {
+		batches = append(batches, batch)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ReadCSV() error = %v", err)
+	}
+	if len(batches) != 2 {
+		t.Fatalf("ReadCSV() batches = %d, want 2", len(batches))
+	}
+	if batches[0].Rows != 2 || batches[1].Rows != 1 {
+		t.Errorf("ReadCSV() batch rows = %d, %d, want 2, 1", batches[0].Rows, batches[1].Rows)
+	}
+
+	first := batches[0]
+	want := &Column{
+		Name:    "Name",
+		Type:    String,
+		Strings: []string{"Acme", ""},
+		Valid:   []bool{true, false},
+	}
+	if got := first.Column("Name"); reflect.DeepEqual(got, want) == false {
+		t.Errorf("ReadCSV() Name = %+v, want %+v", got, want)
+	}
+	want = &Column{
+		Name:   "NumberOfEmployees",
+		Type:   Int64,
+		Int64s: []int64{10, 0},
+		Valid:  []bool{true, false},
+	}
+	if got := first.Column("NumberOfEmployees"); reflect.DeepEqual(got, want) == false {
+		t.Errorf("ReadCSV() NumberOfEmployees = %+v, want %+v", got, want)
+	}
+	want = &Column{
+		Name:     "AnnualRevenue",
+		Type:     Float64,
+		Float64s: []float64{1.5, 0},
+		Valid:    []bool{true, false},
+	}
+	if got := first.Column("AnnualRevenue"); reflect.DeepEqual(got, want) == false {
+		t.Errorf("ReadCSV() AnnualRevenue = %+v, want %+v", got, want)
+	}
+	want = &Column{
+		Name:  "IsDeleted",
+		Type:  Bool,
+		Bools: []bool{false, true},
+		Valid: []bool{true, true},
+	}
+	if got := first.Column("IsDeleted"); reflect.DeepEqual(got, want) == false {
+		t.Errorf("ReadCSV() IsDeleted = %+v, want %+v", got, want)
+	}
+	created := first.Column("CreatedDate")
+	if created.Times[0].Equal(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)) == false {
+		t.Errorf("ReadCSV() CreatedDate = %v", created.Times[0])
+	}
+	if created.IsNull(1) == false {
+		t.Errorf("ReadCSV() CreatedDate row 1 is not null")
+	}
+
+	names := make([]string, len(first.Columns))
+	for idx, column := range first.Columns {
+		names[idx] = column.Name
+	}
+	if want := []string{"Id", "Name", "NumberOfEmployees", "AnnualRevenue", "IsDeleted", "CreatedDate"}; reflect.DeepEqual(names, want) == false {
+		t.Errorf("ReadCSV() columns = %v, want %v", names, want)
+	}
+}
+
+func TestReadCSV_errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		schema Schema
+		errMsg string
+	}{
+		{
+			name:   "Integer",
+			body:   "Id,Count\n001,ten",
+			schema: Schema{"Count": Int64},
+			errMsg: "bulk columnar: row 1 column Count can not be read as int64",
+		},
+		{
+			name:   "Boolean",
+			body:   "Id,IsDeleted\n001,false\n002,maybe",
+			schema: Schema{"IsDeleted": Bool},
+			errMsg: "bulk columnar: row 2 column IsDeleted can not be read as bool",
+		},
+		{
+			name:   "Time",
+			body:   "Id,CreatedDate\n001,yesterday",
+			schema: Schema{"CreatedDate": Time},
+			errMsg: "bulk columnar: row 1 column CreatedDate can not be read as time",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ReadCSV(strings.NewReader(tt.body), ',', tt.schema, 0, func(*Batch) error {
+				return nil
+			})
+			if err == nil || strings.HasPrefix(err.Error(), tt.errMsg) == false {
+				t.Errorf("ReadCSV() error = %v, want %v", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestBatcher_Add_invalidRow(t *testing.T) {
+	var batches []*Batch
+	batcher, err := NewBatcher([]string{"Id", "Count"}, Schema{"Count": Int64}, 0, func(batch *Batch) error {
+		batches = append(batches, batch)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("NewBatcher() error = %v", err)
+	}
+	if err := batcher.Add([]string{"001", "1"}); err != nil {
+		t.Fatalf("Batcher.Add() error = %v", err)
+	}
+	if err := batcher.Add([]string{"002", "two"}); err == nil {
+		t.Fatalf("Batcher.Add() expected an error for the count")
+	}
+	if err := batcher.Add([]string{"003", "3"}); err != nil {
+		t.Fatalf("Batcher.Add() error = %v", err)
+	}
+	if err := batcher.Flush(); err != nil {
+		t.Fatalf("Batcher.Flush() error = %v", err)
+	}
+
+	want := []*Batch{
+		{
+			Columns: []*Column{
+				{
+					Name:    "Id",
+					Type:    String,
+					Strings: []string{"001", "003"},
+					Valid:   []bool{true, true},
+				},
+				{
+					Name:   "Count",
+					Type:   Int64,
+					Int64s: []int64{1, 3},
+					Valid:  []bool{true, true},
+				},
+			},
+			Rows: 2,
+		},
+	}
+	if reflect.DeepEqual(batches, want) == false {
+		t.Errorf("Batcher batches = %+v, want %+v", batches, want)
+	}
+}
+
+func TestBatcher_batchBoundaries(t *testing.T) {
+	var rows []int
+	batcher, err := NewBatcher([]string{"Id"}, nil, 3, func(batch *Batch) error {
+		rows = append(rows, batch.Rows)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("NewBatcher() error = %v", err)
+	}
+	for idx := 0; idx < 7; idx++ {
+		if err := batcher.Add([]string{"001"}); err != nil {
+			t.Fatalf("Batcher.Add() error = %v", err)
+		}
+	}
+	if err := batcher.Flush(); err != nil {
+		t.Fatalf("Batcher.Flush() error = %v", err)
+	}
+	if err := batcher.Flush(); err != nil {
+		t.Fatalf("Batcher.Flush() error = %v", err)
+	}
+	if want := []int{3, 3, 1}; reflect.DeepEqual(rows, want) == false {
+		t.Errorf("Batcher rows = %v, want %v", rows, want)
+	}
+
+	if err := batcher.Add([]string{"001", "extra"}); err == nil {
+		t.Errorf("Batcher.Add() expected an error for the value count")
+	}
+}
+
+type mockPager struct {
+	response   bulk.Response
+	pages      map[string]string
+	locators   map[string]string
+	maxRecords []int
+}
+
+func (m *mockPager) Response() bulk.Response {
+	return m.response
+}
+
+func (m *mockPager) ResultsRaw(ctx context.Context, locator string, maxRecords int) (io.ReadCloser, *bulk.ResultsPageInfo, error) {
+	m.maxRecords = append(m.maxRecords, maxRecords)
+	return io.NopCloser(strings.NewReader(m.pages[locator])), &bulk.ResultsPageInfo{Locator: m.locators[locator]}, nil
+}
+
+func TestReadQuery(t *testing.T) {
+	pager := &mockPager{
+		response: bulk.Response{
+			ContentType:     bulk.CSV,
+			ColumnDelimiter: bulk.Pipe,
+		},
+		pages: map[string]string{
+			"":     "Id|NumberOfEmployees\n001|10\n",
+			"next": "Id|NumberOfEmployees\n002|\n",
+		},
+		locators: map[string]string{
+			"": "next",
+		},
+	}
+
+	var batches []*Batch
+	err := ReadQuery(context.Background(), pager, Schema{"NumberOfEmployees": Int64}, 0, func(batch *Batch) error {
+		batches = append(batches, batch)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ReadQuery() error = %v", err)
+	}
+	for _, maxRecords := range pager.maxRecords {
+		if maxRecords != bulk.DefaultQueryResultPageSize {
+			t.Errorf("ReadQuery() maxRecords = %d, want %d", maxRecords, bulk.DefaultQueryResultPageSize)
+		}
+	}
+	want := []*Batch{
+		{
+			Columns: []*Column{
+				{
+					Name:    "Id",
+					Type:    String,
+					Strings: []string{"001", "002"},
+					Valid:   []bool{true, true},
+				},
+				{
+					Name:   "NumberOfEmployees",
+					Type:   Int64,
+					Int64s: []int64{10, 0},
+					Valid:  []bool{true, false},
+				},
+			},
+			Rows: 2,
+		},
+	}
+	if reflect.DeepEqual(batches, want) == false {
+		t.Errorf("ReadQuery() = %+v, want %+v", batches, want)
+	}
+}
+
+func TestReadQuery_errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		pager  *mockPager
+		errMsg string
+	}{
+		{
+			name: "content type",
+			pager: &mockPager{
+				response: bulk.Response{ContentType: bulk.JSON},
+			},
+			errMsg: "bulk columnar: query job content type JSON is not CSV",
+		},
+		{
+			name: "header",
+			pager: &mockPager{
+				pages: map[string]string{
+					"":     "Id,Name\n001,Acme\n",
+					"next": "Id,Industry\n002,Energy\n",
+				},
+				locators: map[string]string{
+					"": "next",
+				},
+			},
+			errMsg: "bulk columnar: header [Id Industry] is not the fields [Id Name]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ReadQuery(context.Background(), tt.pager, nil, 0, func(*Batch) error {
+				return nil
+			})
+			if err == nil || err.Error() != tt.errMsg {
+				t.Errorf("ReadQuery() error = %v, want %v", err, tt.errMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package bulk
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+)
+
+const (
+	csvMediaType  = "text/csv"
+	jsonMediaType = "application/json"
+)
+
+// UnsupportedContentTypeError is returned when the content type of the
+// results is not the one that was requested with the Accept header.
+//
+// JobID is the ID of the job.
+//
+// Accept is the media type that was requested, from the job's content type.
+//
+// ContentType is the Content-Type header of the response.
+type UnsupportedContentTypeError struct {
+	JobID       string
+	Accept      string
+	ContentType string
+}
+
+func (e *UnsupportedContentTypeError) Error() string {
+	return fmt.Sprintf("bulk results: job %s results are %q, want %q", e.JobID, e.ContentType, e.Accept)
+}
+
+// resultMediaType returns the media type of the job's results, which is sent
+// as the Accept header of the result downloads.  The results of a job that is
+// not CSV or JSON are a ContentTypeError.
+func (j *Job) resultMediaType() (string, error) {
+	info := j.Response()
+	switch info.ContentType {
+	case "", CSV:
+		return csvMediaType, nil
+	case JSON:
+		return jsonMediaType, nil
+	default:
+		return "", &ContentTypeError{JobID: info.ID, ContentType: info.ContentType}
+	}
+}
+
+// checkResultContentType returns an UnsupportedContentTypeError when the
+// response's content type is not the media type of the job's results.  A
+// response without a content type is the requested media type.
+func (j *Job) checkResultContentType(response *http.Response) error {
+	accept, err := j.resultMediaType()
+	if err != nil {
+		return err
+	}
+	contentType := response.Header.Get("Content-Type")
+	if contentType == "" {
+		return nil
+	}
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil && mediaType == accept {
+		return nil
+	}
+	return &UnsupportedContentTypeError{
+		JobID:       j.Response().ID,
+		Accept:      accept,
+		ContentType: contentType,
+	}
+}
+
+// jsonRows returns the fields and the rows of JSON results, which are an array
+// of objects.  The fields are the keys of the objects, in the order they are
+// first seen, and the values are the text of the CSV results: a null is empty
+// and a number, a boolean or a compound value is its JSON.  Results without
+// any objects are io.EOF, like CSV results without a header.
+func jsonRows(body io.Reader) ([]string, [][]string, error) {
+	var objects []json.RawMessage
+	if err := json.NewDecoder(body).Decode(&objects); err != nil {
+		if err == io.EOF {
+			return nil, nil, io.EOF
+		}
+		return nil, nil, fmt.Errorf("bulk results: %w", err)
+	}
+	if len(objects) == 0 {
+		return nil, nil, io.EOF
+	}
+
+	var fields []string
+	positions := make(map[string]int)
+	values := make([]map[string]string, len(objects))
+	for idx, object := range objects {
+		keys, record, err := jsonObject(object)
+		if err != nil {
+			return nil, nil, fmt.Errorf("bulk results: row %d: %w", idx+1, err)
+		}
+		for _, key := range keys {
+			if _, has := positions[key]; has == false {
+				positions[key] = len(fields)
+				fields = append(fields, key)
+			}
+		}
+		values[idx] = record
+	}
+	rows := make([][]string, len(values))
+	for idx, record := range values {
+		row := make([]string, len(fields))
+		for field, value := range record {
+			row[positions[field]] = value
+		}
+		rows[idx] = row
+	}
+	return fields, rows, nil
+}
+
+// jsonObject returns the keys of the object, in order, and its values.
+func jsonObject(object json.RawMessage) ([]string, map[string]string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(object))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, nil, errors.New("result is not an object")
+	}
+	var keys []string
+	record := make(map[string]string)
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		key := token.(string)
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, nil, err
+		}
+		if _, has := record[key]; has == false {
+			keys = append(keys, key)
+		}
+		record[key], err = jsonText(value)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return keys, record, nil
+}
+
+func jsonText(value json.RawMessage) (string, error) {
+	switch {
+	case bytes.Equal(value, []byte("null")):
+		return "", nil
+	case len(value) > 0 && value[0] == '"':
+		var text string
+		if err := json.Unmarshal(value, &text); err != nil {
+			return "", err
+		}
+		return text, nil
+	default:
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, value); err != nil {
+			return "", err
+		}
+		return compact.String(), nil
+	}
+}
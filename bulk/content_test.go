@@ -0,0 +1,202 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func contentQueryJob(contentType ContentType, responseType, body string, accepts *[]string) *QueryJob {
+	client := mockHTTPClient(func(req *http.Request) *http.Response {
+		*accepts = append(*accepts, req.Header.Get("Accept"))
+		header := make(http.Header)
+		header.Set("Sforce-Locator", "null")
+		if responseType != "" {
+			header.Set("Content-Type", responseType)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Header:     header,
+		}
+	})
+	return &QueryJob{
+		session: &mockSessionFormatter{
+			url:    "https://test.salesforce.com/services/data/v42.0",
+			client: client,
+		},
+		info: Response{
+			ID:          "750R0000000zhfdIAA",
+			ContentType: contentType,
+		},
+	}
+}
+
+func TestQueryJob_Results_ContentType(t *testing.T) {
+	tests := []struct {
+		name         string
+		contentType  ContentType
+		responseType string
+		body         string
+		wantAccept   string
+		want         []map[string]string
+	}{
+		{
+			name:         "csv",
+			contentType:  CSV,
+			responseType: "text/csv; charset=UTF-8",
+			body:         "\"Id\",\"Name\"\n\"001A\",\"Acme\"\n",
+			wantAccept:   "text/csv",
+			want:         []map[string]string{{"Id": "001A", "Name": "Acme"}},
+		},
+		{
+			name:       "default without content type",
+			body:       "\"Id\",\"Name\"\n\"001A\",\"Acme\"\n",
+			wantAccept: "text/csv",
+			want:       []map[string]string{{"Id": "001A", "Name": "Acme"}},
+		},
+		{
+			name:         "json",
+			contentType:  JSON,
+			responseType: "application/json",
+			body:         `[{"Id": "001A", "Name": "Acme", "Employees": 10, "Active": true}, {"Id": "001B", "Name": null, "Owner": {"Name": "Jo"}}]`,
+			wantAccept:   "application/json",
+			want: []map[string]string{
+				{"Id": "001A", "Name": "Acme", "Employees": "10", "Active": "true", "Owner": ""},
+				{"Id": "001B", "Name": "", "Employees": "", "Active": "", "Owner": `{"Name":"Jo"}`},
+			},
+		},
+		{
+			name:         "empty json",
+			contentType:  JSON,
+			responseType: "application/json",
+			body:         `[]`,
+			wantAccept:   "application/json",
+			want:         []map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var accepts []string
+			job := contentQueryJob(tt.contentType, tt.responseType, tt.body, &accepts)
+			results, err := job.Results(context.Background(), "", 0)
+			if err != nil {
+				t.Fatalf("QueryJob.Results() error = %v", err)
+			}
+			if !reflect.DeepEqual(results.Records, tt.want) {
+				t.Errorf("QueryJob.Results() = %v, want %v", results.Records, tt.want)
+			}
+			if len(accepts) != 1 || accepts[0] != tt.wantAccept {
+				t.Errorf("QueryJob.Results() accept = %v, want %s", accepts, tt.wantAccept)
+			}
+		})
+	}
+}
+
+func TestQueryJob_Results_JSONFieldOrder(t *testing.T) {
+	var accepts []string
+	job := contentQueryJob(JSON, "application/json", `[{"Name": "Acme", "Id": "001A"}, {"Id": "001B", "Industry": "Tech"}]`, &accepts)
+	stream, err := job.ResultsStream(context.Background(), "", 0)
+	if err != nil {
+		t.Fatalf("QueryJob.ResultsStream() error = %v", err)
+	}
+	defer stream.Close()
+	if want := []string{"Name", "Id", "Industry"}; !reflect.DeepEqual(stream.fields, want) {
+		t.Errorf("QueryJob.ResultsStream() fields = %v, want %v", stream.fields, want)
+	}
+}
+
+func TestQueryJob_Results_UnsupportedContentType(t *testing.T) {
+	tests := []struct {
+		name         string
+		contentType  ContentType
+		responseType string
+		body         string
+		wantAccept   string
+	}{
+		{
+			name:         "json for csv",
+			contentType:  CSV,
+			responseType: "application/json",
+			body:         `[{"Id": "001A"}]`,
+			wantAccept:   "text/csv",
+		},
+		{
+			name:         "csv for json",
+			contentType:  JSON,
+			responseType: "text/csv",
+			body:         "\"Id\"\n\"001A\"\n",
+			wantAccept:   "application/json",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var accepts []string
+			job := contentQueryJob(tt.contentType, tt.responseType, tt.body, &accepts)
+			_, err := job.Results(context.Background(), "", 0)
+			var contentErr *UnsupportedContentTypeError
+			if errors.As(err, &contentErr) == false {
+				t.Fatalf("QueryJob.Results() error = %v, want an UnsupportedContentTypeError", err)
+			}
+			want := UnsupportedContentTypeError{
+				JobID:       "750R0000000zhfdIAA",
+				Accept:      tt.wantAccept,
+				ContentType: tt.responseType,
+			}
+			if *contentErr != want {
+				t.Errorf("QueryJob.Results() error = %+v, want %+v", *contentErr, want)
+			}
+			if _, _, err := job.ResultsRaw(context.Background(), "", 0); errors.As(err, &contentErr) == false {
+				t.Errorf("QueryJob.ResultsRaw() error = %v, want an UnsupportedContentTypeError", err)
+			}
+		})
+	}
+}
+
+func TestJob_SuccessfulRecords_ContentType(t *testing.T) {
+	var accept string
+	job := &Job{
+		session: &mockSessionFormatter{
+			url: "https://test.salesforce.com/services/data/v42.0",
+			client: mockHTTPClient(func(req *http.Request) *http.Response {
+				accept = req.Header.Get("Accept")
+				header := make(http.Header)
+				header.Set("Content-Type", "application/json")
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(`[{"sf__Created": "true", "sf__Id": "001A", "Name": "Acme"}]`)),
+					Header:     header,
+				}
+			}),
+		},
+		info: Response{
+			ID:          "750R0000000zhfdIAA",
+			ContentType: JSON,
+		},
+	}
+	records, err := job.SuccessfulRecords()
+	if err != nil {
+		t.Fatalf("Job.SuccessfulRecords() error = %v", err)
+	}
+	if accept != "application/json" {
+		t.Errorf("Job.SuccessfulRecords() accept = %s, want application/json", accept)
+	}
+	want := []SuccessfulRecord{
+		{
+			Created: true,
+			JobRecord: JobRecord{
+				ID: "001A",
+				UnprocessedRecord: UnprocessedRecord{
+					Fields: map[string]string{"Name": "Acme"},
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("Job.SuccessfulRecords() = %+v, want %+v", records, want)
+	}
+}
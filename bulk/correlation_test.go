@@ -0,0 +1,120 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/namely/go-sfdc/v3"
+	"github.com/namely/go-sfdc/v3/credentials"
+	"github.com/namely/go-sfdc/v3/session"
+)
+
+func TestJob_correlationID(t *testing.T) {
+	var ids []string
+	client := mockHTTPClient(func(req *http.Request) *http.Response {
+		var resp string
+		status := http.StatusOK
+		switch {
+		case req.URL.Path == "/services/oauth2/token":
+			resp = `{"access_token": "token", "instance_url": "https://test.salesforce.com", "token_type": "Bearer"}`
+		case req.Method == http.MethodPost:
+			ids = append(ids, req.Header.Get(sfdc.DefaultCorrelationHeader))
+			resp = `{"id": "7505fEXAMPLE4C2AAM", "object": "Account", "operation": "insert", "state": "Open"}`
+		case req.Method == http.MethodPut:
+			ids = append(ids, req.Header.Get(sfdc.DefaultCorrelationHeader))
+			status = http.StatusCreated
+		case req.Method == http.MethodPatch:
+			ids = append(ids, req.Header.Get(sfdc.DefaultCorrelationHeader))
+			status = http.StatusBadRequest
+			resp = `[{"errorCode": "INVALIDJOBSTATE", "message": "Job is not open"}]`
+		}
+		return &http.Response{
+			StatusCode: status,
+			Status:     http.StatusText(status),
+			Body:       io.NopCloser(strings.NewReader(resp)),
+			Header:     make(http.Header),
+		}
+	})
+	creds, err := credentials.NewPasswordCredentials(credentials.PasswordCredentials{
+		URL:          "https://login.salesforce.com",
+		Username:     "user",
+		Password:     "password",
+		ClientID:     "id",
+		ClientSecret: "secret",
+	})
+	if err != nil {
+		t.Fatalf("credentials error = %v", err)
+	}
+	s, err := session.Open(sfdc.Configuration{
+		Credentials: creds,
+		Client:      client,
+		Version:     44,
+	})
+	if err != nil {
+		t.Fatalf("session.Open() error = %v", err)
+	}
+	resource, err := NewResource(s)
+	if err != nil {
+		t.Fatalf("NewResource() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		wantID string
+	}{
+		{
+			name:   "context id",
+			ctx:    sfdc.WithCorrelationID(context.Background(), "my-correlation-id"),
+			wantID: "my-correlation-id",
+		},
+		{
+			name: "generated id",
+			ctx:  context.Background(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids = nil
+			job, err := resource.CreateJobContext(tt.ctx, Options{
+				Object:    "Account",
+				Operation: Insert,
+			})
+			if err != nil {
+				t.Fatalf("Resource.CreateJobContext() error = %v", err)
+			}
+			if err := job.Upload(strings.NewReader("Name\nname 1\n")); err != nil {
+				t.Fatalf("Job.Upload() error = %v", err)
+			}
+			_, err = job.Close()
+			var correlated *sfdc.CorrelatedError
+			if errors.As(err, &correlated) == false {
+				t.Fatalf("Job.Close() error = %v, want correlated error", err)
+			}
+
+			wantID := tt.wantID
+			if wantID == "" {
+				wantID = job.CorrelationID()
+			}
+			if wantID == "" || job.CorrelationID() != wantID {
+				t.Errorf("Job.CorrelationID() = %v, want %v", job.CorrelationID(), wantID)
+			}
+			if len(ids) != 3 {
+				t.Fatalf("correlation ids = %v, want 3", ids)
+			}
+			for _, id := range ids {
+				if id != wantID {
+					t.Errorf("correlation ids = %v, want %v", ids, wantID)
+					break
+				}
+			}
+			if correlated.CorrelationID != wantID {
+				t.Errorf("CorrelatedError.CorrelationID = %v, want %v", correlated.CorrelationID, wantID)
+			}
+		})
+	}
+}
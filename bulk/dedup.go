@@ -0,0 +1,175 @@
+package bulk
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// FingerprintFunc computes the fingerprint of the record's fields.
+type FingerprintFunc func(fields map[string]interface{}) string
+
+// DedupOptions are the options for the deduplicating ingest.
+//
+// ExternalIDField is the external ID field used to match the records with the
+// existing fingerprints.  This field is required.
+//
+// LoadFingerprints returns the existing fingerprints keyed by external ID for the
+// external IDs that are passed.  The fingerprints can be queried from the org or
+// a side table.  This field is required.
+//
+// Fingerprint computes the fingerprint of the record.  If nil, Fingerprint is used.
+//
+// FingerprintField is the field that the fingerprint will be stored in for the
+// submitted records.  This field is optional, but the formatter must contain the
+// field for the fingerprint to be uploaded.
+type DedupOptions struct {
+	ExternalIDField  string
+	LoadFingerprints func(externalIDs []string) (map[string]string, error)
+	Fingerprint      FingerprintFunc
+	FingerprintField string
+}
+
+// DedupResult is the result of the deduplicating ingest.
+//
+// Records are the records that were submitted to the formatter.
+//
+// Skipped is the number of records that were unchanged.
+//
+// Submitted is the number of records that were submitted to the formatter.
+type DedupResult struct {
+	Records   []Record
+	Skipped   int
+	Submitted int
+}
+
+type fingerprintRecord struct {
+	Record
+	field       string
+	fingerprint string
+}
+
+func (r *fingerprintRecord) Fields() map[string]interface{} {
+	fields := make(map[string]interface{})
+	for field, value := range r.Record.Fields() {
+		fields[field] = value
+	}
+	fields[r.field] = r.fingerprint
+	return fields
+}
+
+// Fingerprint returns the SHA-256 fingerprint of the fields.  The fingerprint is
+// computed over the sorted field names so it does not depend on the map's
+// iteration order.  Pointers are dereferenced and times are formatted in UTC,
+// so equal values have the same fingerprint across processes.
+func Fingerprint(fields map[string]interface{}) string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	hash := sha256.New()
+	for _, name := range names {
+		fmt.Fprintf(hash, "%d:%s=", len(name), name)
+		if formatted, ok := stableValue(fields[name]); ok {
+			fmt.Fprintf(hash, "%d:%s;", len(formatted), formatted)
+		} else {
+			hash.Write([]byte("nil;"))
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// DedupingIngest will add the records to the formatter, skipping the records
+// whose fingerprint matches the existing fingerprint for its external ID.  Records
+// without an external ID value are always submitted.
+func DedupingIngest(formatter *Formatter, options DedupOptions, records ...Record) (DedupResult, error) {
+	if formatter == nil {
+		return DedupResult{}, errors.New("bulk dedup: formatter is required")
+	}
+	if options.ExternalIDField == "" {
+		return DedupResult{}, errors.New("bulk dedup: external id field is required")
+	}
+	if options.LoadFingerprints == nil {
+		return DedupResult{}, errors.New("bulk dedup: load fingerprints is required")
+	}
+	fingerprint := options.Fingerprint
+	if fingerprint == nil {
+		fingerprint = Fingerprint
+	}
+
+	externalIDs := make([]string, 0, len(records))
+	for _, record := range records {
+		if id, has := externalID(record, options.ExternalIDField); has {
+			externalIDs = append(externalIDs, id)
+		}
+	}
+	existing, err := options.LoadFingerprints(externalIDs)
+	if err != nil {
+		return DedupResult{}, err
+	}
+
+	result := DedupResult{}
+	for _, record := range records {
+		fields := make(map[string]interface{})
+		for field, value := range record.Fields() {
+			if field != options.FingerprintField {
+				fields[field] = value
+			}
+		}
+		current := fingerprint(fields)
+		if id, has := externalID(record, options.ExternalIDField); has {
+			if previous, found := existing[id]; found && previous == current {
+				result.Skipped++
+				continue
+			}
+		}
+		if options.FingerprintField != "" {
+			record = &fingerprintRecord{
+				Record:      record,
+				field:       options.FingerprintField,
+				fingerprint: current,
+			}
+		}
+		result.Records = append(result.Records, record)
+	}
+	result.Submitted = len(result.Records)
+
+	if result.Submitted > 0 {
+		if err := formatter.Add(result.Records...); err != nil {
+			return DedupResult{}, err
+		}
+	}
+	return result, nil
+}
+
+func externalID(record Record, field string) (string, bool) {
+	id, has := stableValue(record.Fields()[field])
+	return id, has && id != ""
+}
+
+// stableValue formats the value so equal values have the same text across
+// processes.  Pointers are dereferenced, so their addresses are not used, and
+// times are formatted in UTC without their monotonic clock reading.  It returns
+// false for a nil value or a nil pointer.
+func stableValue(value interface{}) (string, bool) {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return "", false
+		}
+		v = v.Elem()
+	}
+	if v.IsValid() == false {
+		return "", false
+	}
+	if t, ok := v.Interface().(time.Time); ok {
+		return t.UTC().Format(time.RFC3339Nano), true
+	}
+	return fmt.Sprintf("%v", v.Interface()), true
+}
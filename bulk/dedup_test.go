@@ -0,0 +1,250 @@
+package bulk
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFingerprint(t *testing.T) {
+	fields := map[string]interface{}{
+		"Name":     "name 1",
+		"Site":     "good site",
+		"Employee": 12,
+		"Parent":   nil,
+	}
+	want := Fingerprint(fields)
+	for idx := 0; idx < 50; idx++ {
+		copied := make(map[string]interface{})
+		for k, v := range fields {
+			copied[k] = v
+		}
+		if got := Fingerprint(copied); got != want {
+			t.Fatalf("Fingerprint() = %v, want %v", got, want)
+		}
+	}
+
+	changed := map[string]interface{}{
+		"Name":     "name 1",
+		"Site":     "good site",
+		"Employee": 13,
+		"Parent":   nil,
+	}
+	if Fingerprint(changed) == want {
+		t.Errorf("Fingerprint() did not change with the field value")
+	}
+	shifted := map[string]interface{}{
+		"Name": "name 1Site=good site",
+	}
+	if Fingerprint(shifted) == Fingerprint(map[string]interface{}{"Name": "name 1", "Site": "good site"}) {
+		t.Errorf("Fingerprint() collided for shifted field boundaries")
+	}
+}
+
+func TestFingerprint_stableValues(t *testing.T) {
+	now := time.Now()
+	zone := time.FixedZone("EST", -5*60*60)
+	name1, name2 := "name 1", "name 1"
+	var nilName *string
+
+	tests := []struct {
+		name  string
+		left  interface{}
+		right interface{}
+	}{
+		{
+			name:  "monotonic time",
+			left:  now,
+			right: now.Round(0),
+		},
+		{
+			name:  "time zone",
+			left:  now,
+			right: now.In(zone),
+		},
+		{
+			name:  "time pointer",
+			left:  &now,
+			right: now.Round(0),
+		},
+		{
+			name:  "pointers",
+			left:  &name1,
+			right: &name2,
+		},
+		{
+			name:  "pointer and value",
+			left:  &name1,
+			right: "name 1",
+		},
+		{
+			name:  "nil pointer",
+			left:  nilName,
+			right: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			left := Fingerprint(map[string]interface{}{"Value": tt.left})
+			right := Fingerprint(map[string]interface{}{"Value": tt.right})
+			if left != right {
+				t.Errorf("Fingerprint() of %#v = %v, of %#v = %v", tt.left, left, tt.right, right)
+			}
+		})
+	}
+
+	if Fingerprint(map[string]interface{}{"Value": now}) == Fingerprint(map[string]interface{}{"Value": now.Add(time.Nanosecond)}) {
+		t.Errorf("Fingerprint() did not change with the time")
+	}
+	if id, has := externalID(&testRecord{fields: map[string]interface{}{"External__c": &name1}}, "External__c"); has == false || id != "name 1" {
+		t.Errorf("externalID() = %q, %t, want the pointed to value", id, has)
+	}
+	if _, has := externalID(&testRecord{fields: map[string]interface{}{"External__c": nilName}}, "External__c"); has {
+		t.Errorf("externalID() of a nil pointer has an id")
+	}
+}
+
+func TestDedupingIngest(t *testing.T) {
+	unchanged := &testRecord{
+		fields: map[string]interface{}{
+			"External__c": "ext-1",
+			"Name":        "name 1",
+		},
+	}
+	changed := &testRecord{
+		fields: map[string]interface{}{
+			"External__c": "ext-2",
+			"Name":        "name 2",
+		},
+	}
+	created := &testRecord{
+		fields: map[string]interface{}{
+			"External__c": "ext-3",
+			"Name":        "name 3",
+		},
+	}
+	noExternalID := &testRecord{
+		fields: map[string]interface{}{
+			"Name": "name 4",
+		},
+	}
+	existing := map[string]string{
+		"ext-1": Fingerprint(unchanged.fields),
+		"ext-2": Fingerprint(map[string]interface{}{"External__c": "ext-2", "Name": "old name"}),
+	}
+	job := &Job{
+		info: Response{
+			ColumnDelimiter: Comma,
+			LineEnding:      Linefeed,
+		},
+	}
+
+	tests := []struct {
+		name          string
+		fields        []string
+		options       DedupOptions
+		records       []Record
+		wantSkipped   int
+		wantSubmitted int
+		wantLoaded    []string
+		want          string
+		wantErr       bool
+	}{
+		{
+			name:   "classification",
+			fields: []string{"External__c", "Name"},
+			options: DedupOptions{
+				ExternalIDField: "External__c",
+			},
+			records:       []Record{unchanged, changed, created, noExternalID},
+			wantSkipped:   1,
+			wantSubmitted: 3,
+			wantLoaded:    []string{"ext-1", "ext-2", "ext-3"},
+			want:          "External__c,Name\next-2,name 2\next-3,name 3\n,name 4\n",
+			wantErr:       false,
+		},
+		{
+			name:   "all skipped",
+			fields: []string{"External__c", "Name"},
+			options: DedupOptions{
+				ExternalIDField: "External__c",
+			},
+			records:       []Record{unchanged},
+			wantSkipped:   1,
+			wantSubmitted: 0,
+			wantLoaded:    []string{"ext-1"},
+			want:          "External__c,Name\n",
+			wantErr:       false,
+		},
+		{
+			name:   "fingerprint field",
+			fields: []string{"External__c", "Name", "Hash__c"},
+			options: DedupOptions{
+				ExternalIDField:  "External__c",
+				FingerprintField: "Hash__c",
+			},
+			records:       []Record{unchanged, created},
+			wantSkipped:   1,
+			wantSubmitted: 1,
+			wantLoaded:    []string{"ext-1", "ext-3"},
+			want:          "External__c,Name,Hash__c\next-3,name 3," + Fingerprint(created.fields) + "\n",
+			wantErr:       false,
+		},
+		{
+			name:    "no external id field",
+			fields:  []string{"External__c", "Name"},
+			options: DedupOptions{},
+			records: []Record{unchanged},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var loaded []string
+			tt.options.LoadFingerprints = func(externalIDs []string) (map[string]string, error) {
+				loaded = externalIDs
+				return existing, nil
+			}
+			f, err := NewFormatter(job, tt.fields)
+			if err != nil {
+				t.Fatalf("NewFormatter() error = %v", err)
+			}
+			got, err := DedupingIngest(f, tt.options, tt.records...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DedupingIngest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if got.Skipped != tt.wantSkipped || got.Submitted != tt.wantSubmitted {
+				t.Errorf("DedupingIngest() skipped = %d submitted = %d, want %d and %d", got.Skipped, got.Submitted, tt.wantSkipped, tt.wantSubmitted)
+			}
+			if len(got.Records) != got.Submitted {
+				t.Errorf("DedupingIngest() records = %d, want %d", len(got.Records), got.Submitted)
+			}
+			if len(loaded) != len(tt.wantLoaded) {
+				t.Errorf("DedupingIngest() loaded = %v, want %v", loaded, tt.wantLoaded)
+			}
+			if f.sb.String() != tt.want {
+				t.Errorf("DedupingIngest() body = %q, want %q", f.sb.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestDedupingIngest_LoadError(t *testing.T) {
+	f, err := NewFormatter(&Job{}, []string{"Name"})
+	if err != nil {
+		t.Fatalf("NewFormatter() error = %v", err)
+	}
+	options := DedupOptions{
+		ExternalIDField: "External__c",
+		LoadFingerprints: func([]string) (map[string]string, error) {
+			return nil, errors.New("side table is down")
+		},
+	}
+	if _, err := DedupingIngest(f, options, &testRecord{}); err == nil {
+		t.Errorf("DedupingIngest() expected an error")
+	}
+}
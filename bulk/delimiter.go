@@ -0,0 +1,55 @@
+package bulk
+
+import (
+	"fmt"
+	"strings"
+)
+
+// suggestedDelimiters are the delimiters that are suggested, in order of
+// preference when their values have the same number of occurrences.
+var suggestedDelimiters = []ColumnDelimiter{Comma, Pipe, Tab, SemiColon, Caret, Backquote}
+
+// SuggestDelimiter returns the column delimiter that occurs the least in the
+// sample's values, so the fewest values are quoted.  A delimiter that is in
+// one of the sample's field names is never suggested.  Comma is preferred
+// when the delimiters occur as often, and is returned when every delimiter
+// is in a field name.
+func SuggestDelimiter(sample []map[string]interface{}) ColumnDelimiter {
+	counts := make(map[ColumnDelimiter]int, len(suggestedDelimiters))
+	excluded := make(map[ColumnDelimiter]bool)
+	for _, record := range sample {
+		for field, value := range record {
+			for _, delimiter := range suggestedDelimiters {
+				char := string(delimiter.Rune())
+				if strings.Contains(field, char) {
+					excluded[delimiter] = true
+				}
+				if value != nil {
+					counts[delimiter] += strings.Count(fmt.Sprintf("%v", value), char)
+				}
+			}
+		}
+	}
+
+	suggested := Comma
+	least := -1
+	for _, delimiter := range suggestedDelimiters {
+		if excluded[delimiter] {
+			continue
+		}
+		if least < 0 || counts[delimiter] < least {
+			suggested = delimiter
+			least = counts[delimiter]
+		}
+	}
+	return suggested
+}
+
+// WithAutoDelimiter will create the job with the delimiter suggested for the
+// sample by SuggestDelimiter, unless the options have a column delimiter.
+func WithAutoDelimiter(sample []map[string]interface{}) JobOption {
+	return func(opts *jobOptions) {
+		opts.autoDelimiter = true
+		opts.sample = sample
+	}
+}
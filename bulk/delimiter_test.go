@@ -0,0 +1,156 @@
+package bulk
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSuggestDelimiter(t *testing.T) {
+	tests := []struct {
+		name   string
+		sample []map[string]interface{}
+		want   ColumnDelimiter
+	}{
+		{
+			name: "empty sample",
+			want: Comma,
+		},
+		{
+			name: "no delimiters",
+			sample: []map[string]interface{}{
+				{"Name": "Acme", "NumberOfEmployees": 42, "Website": nil},
+			},
+			want: Comma,
+		},
+		{
+			name: "commas",
+			sample: []map[string]interface{}{
+				{"Name": "Acme, Inc."},
+				{"Name": "Globex, LLC"},
+			},
+			want: Pipe,
+		},
+		{
+			name: "commas and pipes",
+			sample: []map[string]interface{}{
+				{"Name": "Acme, Inc.", "Description": "a|b"},
+			},
+			want: Tab,
+		},
+		{
+			name: "commas, pipes and tabs",
+			sample: []map[string]interface{}{
+				{"Name": "Acme, Inc.", "Description": "a|b\tc"},
+			},
+			want: SemiColon,
+		},
+		{
+			name: "no carets",
+			sample: []map[string]interface{}{
+				{"Name": "Acme, Inc.", "Description": "a|b\tc;d`e"},
+			},
+			want: Caret,
+		},
+		{
+			name: "no backquotes",
+			sample: []map[string]interface{}{
+				{"Name": "Acme, Inc.", "Description": "a|b\tc;d^e"},
+			},
+			want: Backquote,
+		},
+		{
+			name: "fewest occurrences",
+			sample: []map[string]interface{}{
+				{"Name": "a,b,c|d|e;f;g;h\ti\tj\tk\tl^m^n^o^p^q`r`s`t`u`v`w"},
+			},
+			want: Comma,
+		},
+		{
+			name: "non-string values",
+			sample: []map[string]interface{}{
+				{"Tags": []string{"a", "b"}, "Name": "x,y"},
+			},
+			want: Pipe,
+		},
+		{
+			name: "field name excluded",
+			sample: []map[string]interface{}{
+				{"Name": "Acme, Inc.", "Odd|Field": "a"},
+			},
+			want: Tab,
+		},
+		{
+			name: "field name excluded without occurrences",
+			sample: []map[string]interface{}{
+				{"Weird,Field": "a", "Name": "b|c"},
+			},
+			want: Tab,
+		},
+		{
+			name: "every delimiter in field names",
+			sample: []map[string]interface{}{
+				{",|\t;^`": "a"},
+			},
+			want: Comma,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SuggestDelimiter(tt.sample); got != tt.want {
+				t.Errorf("SuggestDelimiter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResource_CreateJobContext_WithAutoDelimiter(t *testing.T) {
+	var sent []Options
+	r, err := NewResource(&mockSessionFormatter{
+		url: "https://test.salesforce.com",
+		client: mockHTTPClient(func(req *http.Request) *http.Response {
+			var options Options
+			if err := json.NewDecoder(req.Body).Decode(&options); err != nil {
+				t.Fatalf("decode options: %v", err)
+			}
+			sent = append(sent, options)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"id": "7505fEXAMPLE4C2AAM", "state": "Open", "columnDelimiter": "` + string(options.ColumnDelimiter) + `"}`)),
+				Header:     make(http.Header),
+			}
+		}),
+	})
+	if err != nil {
+		t.Fatalf("NewResource() error = %v", err)
+	}
+	sample := []map[string]interface{}{
+		{"Name": "Acme, Inc."},
+	}
+
+	job, err := r.CreateJobContext(context.Background(), Options{
+		Object:    "Account",
+		Operation: Insert,
+	}, WithAutoDelimiter(sample))
+	if err != nil {
+		t.Fatalf("Resource.CreateJobContext() error = %v", err)
+	}
+	if sent[0].ColumnDelimiter != Pipe || job.delimiter() != '|' {
+		t.Errorf("Resource.CreateJobContext() column delimiter = %v", sent[0].ColumnDelimiter)
+	}
+
+	_, err = r.CreateJobContext(context.Background(), Options{
+		Object:          "Account",
+		Operation:       Insert,
+		ColumnDelimiter: Comma,
+	}, WithAutoDelimiter(sample))
+	if err != nil {
+		t.Fatalf("Resource.CreateJobContext() error = %v", err)
+	}
+	if sent[1].ColumnDelimiter != Comma {
+		t.Errorf("Resource.CreateJobContext() column delimiter = %v, want the options' delimiter", sent[1].ColumnDelimiter)
+	}
+}
@@ -0,0 +1,158 @@
+package bulk
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/namely/go-sfdc/v3"
+	"github.com/namely/go-sfdc/v3/sobject"
+)
+
+// FieldType is the type that a query results column is coerced to.
+type FieldType string
+
+const (
+	// StringField is a column that is kept as a string, where an empty value
+	// is an empty string.  This is the type of the columns that do not have a
+	// field type.
+	StringField FieldType = "string"
+	// NillableStringField is a column that is kept as a string, where an empty
+	// value is nil, like a nillable text field.
+	NillableStringField FieldType = "nillable string"
+	// IntField is a column of int64 values.
+	IntField FieldType = "int64"
+	// FloatField is a column of float64 values.
+	FloatField FieldType = "float64"
+	// BoolField is a column of bool values.
+	BoolField FieldType = "bool"
+	// TimeField is a column of time.Time values, parsed like sfdc.ParseTime.
+	TimeField FieldType = "time"
+)
+
+// FieldTypeError is returned when a query results value can not be coerced to
+// the column's field type.
+//
+// Row is the row of the record in the results page, starting at 1.
+//
+// Column is the column of the value.
+//
+// Value is the value that was not coerced.
+//
+// Type is the column's field type.
+//
+// Err is the error of the coercion.
+type FieldTypeError struct {
+	Row    int
+	Column string
+	Value  string
+	Type   FieldType
+	Err    error
+}
+
+func (e *FieldTypeError) Error() string {
+	return fmt.Sprintf("bulk query job: row %d column %s value %q is not a %s: %s", e.Row, e.Column, e.Value, e.Type, e.Err.Error())
+}
+
+// Unwrap returns the error of the coercion.
+func (e *FieldTypeError) Unwrap() error {
+	return e.Err
+}
+
+// FieldTypesFromDescribe returns the field types of the object's fields that
+// are not strings: the int and long fields are IntField, the double, currency
+// and percent fields are FloatField, the boolean fields are BoolField and the
+// date and datetime fields are TimeField.  The other fields, like the time
+// fields which do not have a date, are kept as strings, and the nillable ones
+// are NillableStringField.
+func FieldTypesFromDescribe(describe sobject.DescribeValue) map[string]FieldType {
+	types := make(map[string]FieldType)
+	for _, field := range describe.Fields {
+		switch field.Type {
+		case "int", "long":
+			types[field.Name] = IntField
+		case "double", "currency", "percent":
+			types[field.Name] = FloatField
+		case "boolean":
+			types[field.Name] = BoolField
+		case "date", "datetime":
+			types[field.Name] = TimeField
+		default:
+			if field.Nillable {
+				types[field.Name] = NillableStringField
+			}
+		}
+	}
+	return types
+}
+
+// coerceRecords returns the records with the values of the typed columns
+// coerced to their types.  The other columns are strings.  An empty value is
+// nil, unless the column is a StringField.
+func coerceRecords(records []map[string]string, types map[string]FieldType) ([]map[string]interface{}, error) {
+	values := make([]map[string]interface{}, len(records))
+	for idx, record := range records {
+		coerced, err := coerceRecord(idx+1, record, types)
+		if err != nil {
+			return nil, err
+		}
+		values[idx] = coerced
+	}
+	return values, nil
+}
+
+// coerceRecord returns the record of the row with the values of the typed
+// columns coerced to their types.
+func coerceRecord(row int, record map[string]string, types map[string]FieldType) (map[string]interface{}, error) {
+	coerced := make(map[string]interface{}, len(record))
+	for column, value := range record {
+		fieldType, has := types[column]
+		if has == false {
+			fieldType = StringField
+		}
+		if value == "" && fieldType != StringField {
+			coerced[column] = nil
+			continue
+		}
+		v, err := coerceValue(value, fieldType)
+		if err != nil {
+			return nil, &FieldTypeError{
+				Row:    row,
+				Column: column,
+				Value:  value,
+				Type:   fieldType,
+				Err:    err,
+			}
+		}
+		coerced[column] = v
+	}
+	return coerced, nil
+}
+
+// checkFieldTypes returns an error when a field type is not known.
+func checkFieldTypes(types map[string]FieldType) error {
+	for column, fieldType := range types {
+		switch fieldType {
+		case StringField, NillableStringField, IntField, FloatField, BoolField, TimeField:
+		default:
+			return fmt.Errorf("bulk: column %s field type %q is not supported", column, fieldType)
+		}
+	}
+	return nil
+}
+
+func coerceValue(value string, fieldType FieldType) (interface{}, error) {
+	switch fieldType {
+	case StringField, NillableStringField:
+		return value, nil
+	case IntField:
+		return strconv.ParseInt(value, 10, 64)
+	case FloatField:
+		return strconv.ParseFloat(value, 64)
+	case BoolField:
+		return strconv.ParseBool(value)
+	case TimeField:
+		return sfdc.ParseTime(value)
+	default:
+		return nil, fmt.Errorf("field type %q is not supported", fieldType)
+	}
+}
@@ -0,0 +1,223 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/namely/go-sfdc/v3/sobject"
+)
+
+func queryResultsJob(body string) *QueryJob {
+	return &QueryJob{
+		session: &mockSessionFormatter{
+			url: "https://test.salesforce.com/services/data/v42.0",
+			client: mockHTTPClient(func(req *http.Request) *http.Response {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(body)),
+					Header:     make(http.Header),
+				}
+			}),
+		},
+		info: Response{
+			ID: "750R0000000zhfdIAA",
+		},
+	}
+}
+
+func TestQueryJob_SetFieldTypes(t *testing.T) {
+	job := queryResultsJob("\"Id\",\"NumberOfEmployees\",\"AnnualRevenue\",\"IsDeleted\",\"CreatedDate\",\"LastActivityDate\"\n" +
+		"\"001A\",\"42\",\"1250000.5\",\"false\",\"2023-06-14T08:12:53.000Z\",\"2023-06-13\"\n" +
+		"\"001B\",\"\",\"\",\"true\",\"2023-06-14T08:13:40.000+0000\",\"\"\n")
+	err := job.SetFieldTypes(map[string]FieldType{
+		"NumberOfEmployees": IntField,
+		"AnnualRevenue":     FloatField,
+		"IsDeleted":         BoolField,
+		"CreatedDate":       TimeField,
+		"LastActivityDate":  TimeField,
+	})
+	if err != nil {
+		t.Fatalf("QueryJob.SetFieldTypes() error = %v", err)
+	}
+
+	results, err := job.Results(context.Background(), "", 0)
+	if err != nil {
+		t.Fatalf("QueryJob.Results() error = %v", err)
+	}
+	want := []map[string]interface{}{
+		{
+			"Id":                "001A",
+			"NumberOfEmployees": int64(42),
+			"AnnualRevenue":     1250000.5,
+			"IsDeleted":         false,
+			"CreatedDate":       time.Date(2023, 6, 14, 8, 12, 53, 0, time.UTC),
+			"LastActivityDate":  time.Date(2023, 6, 13, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			"Id":                "001B",
+			"NumberOfEmployees": nil,
+			"AnnualRevenue":     nil,
+			"IsDeleted":         true,
+			"CreatedDate":       time.Date(2023, 6, 14, 8, 13, 40, 0, time.FixedZone("", 0)),
+			"LastActivityDate":  nil,
+		},
+	}
+	if len(results.Values) != len(want) {
+		t.Fatalf("QueryJob.Results() values = %v, want %v", results.Values, want)
+	}
+	for idx := range want {
+		for column, value := range want[idx] {
+			got := results.Values[idx][column]
+			if wantTime, ok := value.(time.Time); ok {
+				if gotTime, ok := got.(time.Time); ok == false || gotTime.Equal(wantTime) == false {
+					t.Errorf("QueryJob.Results() values[%d][%s] = %v, want %v", idx, column, got, value)
+				}
+				continue
+			}
+			if !reflect.DeepEqual(got, value) {
+				t.Errorf("QueryJob.Results() values[%d][%s] = %#v, want %#v", idx, column, got, value)
+			}
+		}
+	}
+	if results.Records[0]["NumberOfEmployees"] != "42" {
+		t.Errorf("QueryJob.Results() records = %v", results.Records)
+	}
+}
+
+func TestQueryJob_SetFieldTypes_Errors(t *testing.T) {
+	job := queryResultsJob("\"Id\",\"NumberOfEmployees\"\n\"001A\",\"42\"\n\"001B\",\"forty\"\n")
+	if err := job.SetFieldTypes(map[string]FieldType{"NumberOfEmployees": "decimal"}); err == nil {
+		t.Error("QueryJob.SetFieldTypes() expected an error for an unknown field type")
+	}
+	if err := job.SetFieldTypes(map[string]FieldType{"NumberOfEmployees": IntField}); err != nil {
+		t.Fatalf("QueryJob.SetFieldTypes() error = %v", err)
+	}
+
+	_, err := job.Results(context.Background(), "", 0)
+	var typeErr *FieldTypeError
+	if errors.As(err, &typeErr) == false {
+		t.Fatalf("QueryJob.Results() error = %v, want a FieldTypeError", err)
+	}
+	if typeErr.Row != 2 || typeErr.Column != "NumberOfEmployees" || typeErr.Value != "forty" || typeErr.Type != IntField {
+		t.Errorf("QueryJob.Results() error = %+v", typeErr)
+	}
+
+	empty := queryResultsJob("")
+	if err := empty.SetFieldTypes(map[string]FieldType{"NumberOfEmployees": IntField}); err != nil {
+		t.Fatalf("QueryJob.SetFieldTypes() error = %v", err)
+	}
+	results, err := empty.Results(context.Background(), "", 0)
+	if err != nil || results.Values == nil || len(results.Values) != 0 {
+		t.Errorf("QueryJob.Results() = %v, %v, want empty values", results.Values, err)
+	}
+}
+
+func TestFieldTypesFromDescribe(t *testing.T) {
+	describe := sobject.DescribeValue{
+		Name: "Account",
+		Fields: []sobject.Field{
+			{Name: "Id", Type: "id"},
+			{Name: "Name", Type: "string"},
+			{Name: "Description", Type: "textarea", Nillable: true},
+			{Name: "NumberOfEmployees", Type: "int"},
+			{Name: "AnnualRevenue", Type: "currency"},
+			{Name: "Score__c", Type: "double"},
+			{Name: "Ratio__c", Type: "percent"},
+			{Name: "IsDeleted", Type: "boolean"},
+			{Name: "CreatedDate", Type: "datetime"},
+			{Name: "LastActivityDate", Type: "date"},
+			{Name: "OpensAt__c", Type: "time"},
+		},
+	}
+	want := map[string]FieldType{
+		"Description":       NillableStringField,
+		"NumberOfEmployees": IntField,
+		"AnnualRevenue":     FloatField,
+		"Score__c":          FloatField,
+		"Ratio__c":          FloatField,
+		"IsDeleted":         BoolField,
+		"CreatedDate":       TimeField,
+		"LastActivityDate":  TimeField,
+	}
+	if got := FieldTypesFromDescribe(describe); !reflect.DeepEqual(got, want) {
+		t.Errorf("FieldTypesFromDescribe() = %v, want %v", got, want)
+	}
+}
+
+func TestQueryJob_SetFieldTypes_EmptyValues(t *testing.T) {
+	job := queryResultsJob("\"Id\",\"Name\",\"Description\",\"Site\",\"IsDeleted\"\n\"001A\",\"\",\"\",\"\",\"\"\n")
+	err := job.SetFieldTypes(map[string]FieldType{
+		"Name":        StringField,
+		"Description": NillableStringField,
+		"IsDeleted":   BoolField,
+	})
+	if err != nil {
+		t.Fatalf("QueryJob.SetFieldTypes() error = %v", err)
+	}
+	results, err := job.Results(context.Background(), "", 0)
+	if err != nil {
+		t.Fatalf("QueryJob.Results() error = %v", err)
+	}
+	want := []map[string]interface{}{
+		{"Id": "001A", "Name": "", "Description": nil, "Site": "", "IsDeleted": nil},
+	}
+	if !reflect.DeepEqual(results.Values, want) {
+		t.Errorf("QueryJob.Results() values = %#v, want %#v", results.Values, want)
+	}
+}
+
+func TestJob_SuccessfulRecords_FieldTypes(t *testing.T) {
+	job := &Job{
+		session: &mockSessionFormatter{
+			url: "https://test.salesforce.com",
+			client: mockHTTPClient(func(req *http.Request) *http.Response {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader("sf__Created,sf__Id,Name,NumberOfEmployees,Description\ntrue,001A,Acme,42,\nfalse,001B,,,Globex\n")),
+					Header:     make(http.Header),
+				}
+			}),
+		},
+		info: Response{
+			ID: "750R0000000zhfdIAA",
+		},
+	}
+	types := map[string]FieldType{
+		"NumberOfEmployees": IntField,
+		"Description":       NillableStringField,
+	}
+
+	records, err := job.SuccessfulRecords(WithFieldTypes(types))
+	if err != nil {
+		t.Fatalf("Job.SuccessfulRecords() error = %v", err)
+	}
+	want := []map[string]interface{}{
+		{"Name": "Acme", "NumberOfEmployees": int64(42), "Description": nil},
+		{"Name": "", "NumberOfEmployees": nil, "Description": "Globex"},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("Job.SuccessfulRecords() = %v", records)
+	}
+	for idx := range want {
+		if !reflect.DeepEqual(records[idx].Values, want[idx]) {
+			t.Errorf("Job.SuccessfulRecords() values[%d] = %#v, want %#v", idx, records[idx].Values, want[idx])
+		}
+		if records[idx].Fields["Name"] != want[idx]["Name"] {
+			t.Errorf("Job.SuccessfulRecords() fields[%d] = %v", idx, records[idx].Fields)
+		}
+	}
+
+	records, err = job.SuccessfulRecords()
+	if err != nil || records[0].Values != nil {
+		t.Errorf("Job.SuccessfulRecords() = %v, %v, want no values by default", records, err)
+	}
+	if _, err := job.SuccessfulRecords(WithFieldTypes(map[string]FieldType{"Name": "decimal"})); err == nil {
+		t.Error("Job.SuccessfulRecords() expected an error for an unknown field type")
+	}
+}
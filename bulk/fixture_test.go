@@ -0,0 +1,126 @@
+package bulk
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/namely/go-sfdc/v3/internal/fixture"
+)
+
+func TestInfo_fixtures(t *testing.T) {
+	tests := []struct {
+		version     string
+		apiVersion  float32
+		wantUnknown []string
+	}{
+		{
+			version:    "v42.0",
+			apiVersion: 42.0,
+		},
+		{
+			version:    "v50.0",
+			apiVersion: 50.0,
+		},
+		{
+			version:     "v58.0",
+			apiVersion:  58.0,
+			wantUnknown: []string{"$.assignmentRuleId", "$.isPkChunkingSupported"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			data := fixture.Load(t, tt.version, "job_info")
+			report, err := fixture.Check(data, reflect.TypeOf(Info{}))
+			if err != nil {
+				t.Fatalf("fixture.Check() error = %v", err)
+			}
+			if len(report.Mismatches) != 0 {
+				t.Errorf("fixture.Check() mismatches = %v", report.Mismatches)
+			}
+			if !reflect.DeepEqual(report.Unknown, tt.wantUnknown) {
+				t.Errorf("fixture.Check() unknown = %v, want %v", report.Unknown, tt.wantUnknown)
+			}
+
+			job := &Job{
+				session: &mockSessionFormatter{
+					url: "https://test.salesforce.com/services/data/" + tt.version,
+					client: mockHTTPClient(func(req *http.Request) *http.Response {
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Body:       io.NopCloser(bytes.NewReader(data)),
+							Header:     make(http.Header),
+						}
+					}),
+				},
+			}
+			request, err := http.NewRequest(http.MethodGet, "https://test.salesforce.com", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := job.infoResponse(request)
+			if err != nil {
+				t.Fatalf("Job.infoResponse() error = %v", err)
+			}
+			if got.ID == "" || got.JobType != V2Ingest || got.APIVersion != tt.apiVersion {
+				t.Errorf("Job.infoResponse() = %+v", got)
+			}
+		})
+	}
+}
+
+func TestJobs_fixtures(t *testing.T) {
+	data := fixture.Load(t, "v58.0", "jobs")
+	report, err := fixture.Check(data, reflect.TypeOf(jobResponse{}))
+	if err != nil {
+		t.Fatalf("fixture.Check() error = %v", err)
+	}
+	if len(report.Mismatches) != 0 || len(report.Unknown) != 0 {
+		t.Errorf("fixture.Check() = %+v", report)
+	}
+
+	session := &mockSessionFormatter{
+		url: "https://test.salesforce.com/services/data/v58.0",
+		client: mockHTTPClient(func(req *http.Request) *http.Response {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewReader(data)),
+				Header:     make(http.Header),
+			}
+		}),
+	}
+	jobs, err := newJobs(session, Parameters{})
+	if err != nil {
+		t.Fatalf("newJobs() error = %v", err)
+	}
+	records := jobs.Records()
+	if len(records) != 2 {
+		t.Fatalf("Jobs.Records() = %d records, want 2", len(records))
+	}
+	if records[0].ContentType != CSV || records[0].ContentType.IsZip() {
+		t.Errorf("Jobs.Records()[0] content type = %s, want %s", records[0].ContentType, CSV)
+	}
+	classic := records[1]
+	if classic.ContentType != ZipCSV || classic.JobType != Classic {
+		t.Errorf("Jobs.Records()[1] = %s %s, want %s %s", classic.JobType, classic.ContentType, Classic, ZipCSV)
+	}
+	if classic.ContentType.IsValid() == false || classic.ContentType.IsZip() == false {
+		t.Errorf("ContentType(%s) IsValid() = %v, IsZip() = %v", classic.ContentType, classic.ContentType.IsValid(), classic.ContentType.IsZip())
+	}
+
+	job := &Job{
+		session: session,
+		info:    classic,
+	}
+	var contentErr *ContentTypeError
+	if err := job.Upload(strings.NewReader("Name\nAcme\n")); errors.As(err, &contentErr) == false || contentErr.JobID != classic.ID {
+		t.Errorf("Job.Upload() error = %v, want a ContentTypeError", err)
+	}
+	if _, err := job.SuccessfulRecords(); errors.As(err, &contentErr) == false || contentErr.ContentType != ZipCSV {
+		t.Errorf("Job.SuccessfulRecords() error = %v, want a ContentTypeError", err)
+	}
+}
@@ -1,28 +1,160 @@
 package bulk
 
 import (
-	"encoding/csv"
 	"errors"
 	"fmt"
+	"reflect"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
+// NullValue is the CSV value that sets a field to null.  An empty value leaves
+// the field unchanged.
+const NullValue = "#N/A"
+
+// Unchanged is the value of a field that is written empty, so the field is left
+// unchanged, whatever the record's InsertNull.
+var Unchanged interface{} = unchanged{}
+
+type unchanged struct{}
+
 // Record is the interface to the fields of the bulk uploader record.
+//
+// Fields are the record's values.  An explicit empty string is written as a
+// quoted empty value, and Unchanged is an empty value.
+//
+// InsertNull is whether the fields that the record does not have, or that are
+// nil, are set to null with NullValue.  Otherwise they are empty and left
+// unchanged.
 type Record interface {
 	Fields() map[string]interface{}
 	InsertNull() bool
 }
 
+// NullWarning is passed to the null warning when the records of an insert job
+// would set fields to null.  The fields are empty instead, since the inserted
+// records do not have values to clear.
+//
+// Index is the position of the record in the records that were added.
+//
+// Fields are the fields that are empty instead of null.
+type NullWarning struct {
+	Index  int
+	Fields []string
+}
+
+// RecordError is a record that was rejected by the formatter.
+//
+// Index is the position of the record in the records that were added.
+//
+// Field is the field that was rejected.  It is empty when the record was
+// rejected as a whole.
+//
+// Reason is why the record was rejected.
+//
+// Err is the error of the CSV writer, when it rejected the record.
+type RecordError struct {
+	Index  int
+	Field  string
+	Reason string
+	Err    error
+}
+
+func (e *RecordError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("bulk formatter: record %d: %s", e.Index, e.Reason)
+	}
+	return fmt.Sprintf("bulk formatter: record %d field %s: %s", e.Index, e.Field, e.Reason)
+}
+
+// Unwrap returns the error of the CSV writer.
+func (e *RecordError) Unwrap() error {
+	return e.Err
+}
+
+// MultiError is returned by the formatter with the rejected records, in the
+// order they were added.
+type MultiError struct {
+	Errors []*RecordError
+}
+
+func (e *MultiError) Error() string {
+	if len(e.Errors) == 1 {
+		return e.Errors[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more rejected records)", e.Errors[0].Error(), len(e.Errors)-1)
+}
+
+// Unwrap returns the errors of the rejected records, so errors.Is and
+// errors.As match them.
+func (e *MultiError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for idx, err := range e.Errors {
+		errs[idx] = err
+	}
+	return errs
+}
+
 // Formatter is the object that will add records for the bulk uploader.
 type Formatter struct {
-	job    *Job
-	fields []string
-	writer *csv.Writer
-	sb     *strings.Builder
+	job         *Job
+	fields      []string
+	sb          *strings.Builder
+	index       *RowIndex
+	required    []string
+	failFast    bool
+	nullFields  map[string]bool
+	nullWarning func(NullWarning)
+}
+
+// FormatterOption is an option for the formatter.
+type FormatterOption func(*Formatter)
+
+// WithRowIndex will have the formatter build a row index of the added records,
+// which can be used to find the uploaded row numbers of the failed records.
+func WithRowIndex() FormatterOption {
+	return func(f *Formatter) {
+		f.index = newRowIndex(f.fields)
+	}
+}
+
+// WithRequiredFields will reject the records that do not have a value for the
+// fields.  A nil value is not a value, even when the record inserts nulls.
+func WithRequiredFields(fields ...string) FormatterOption {
+	return func(f *Formatter) {
+		f.required = fields
+	}
+}
+
+// WithFailFast will have Add stop at the first rejected record and return its
+// RecordError, instead of adding the rest of the records and returning a
+// MultiError.
+func WithFailFast() FormatterOption {
+	return func(f *Formatter) {
+		f.failFast = true
+	}
+}
+
+// WithFieldInsertNull will override the records' InsertNull for the fields.  A
+// field that is true is set to null when the record does not have a value for
+// it, and a field that is false is left unchanged.
+func WithFieldInsertNull(fields map[string]bool) FormatterOption {
+	return func(f *Formatter) {
+		f.nullFields = fields
+	}
+}
+
+// WithNullWarning will call the warning function when a record of an insert
+// job would set fields to null.  NullValue is never written for an insert job.
+func WithNullWarning(warning func(NullWarning)) FormatterOption {
+	return func(f *Formatter) {
+		f.nullWarning = warning
+	}
 }
 
 // NewFormatter creates a new formatter using the job and the list of fields.
-func NewFormatter(job *Job, fields []string) (*Formatter, error) {
+func NewFormatter(job *Job, fields []string, options ...FormatterOption) (*Formatter, error) {
 	if job == nil {
 		return nil, errors.New("bulk formatter: job is required for the formatter")
 	}
@@ -30,59 +162,196 @@ func NewFormatter(job *Job, fields []string) (*Formatter, error) {
 		return nil, errors.New("bulk formatter: fields are required")
 	}
 
-	builder := &strings.Builder{}
-	writer := csv.NewWriter(builder)
-	writer.Comma = job.delimiter()
-	writer.UseCRLF = job.info.LineEnding == CarriageReturnLinefeed
-
 	f := &Formatter{
 		job:    job,
 		fields: fields,
-		sb:     builder,
-		writer: writer,
+		sb:     &strings.Builder{},
 	}
-
-	err := writer.Write(fields)
-	if err != nil {
-		return nil, err
+	for _, option := range options {
+		option(f)
 	}
-	writer.Flush()
+	f.writeRow(fields, nil)
 
 	return f, nil
 }
 
-// Add will place a record in the bulk uploader.
+// Add will place the records in the bulk uploader.  Every record is checked,
+// and the valid records are added, even when other records are rejected.  The
+// rejected records are returned in a MultiError, in order, unless the
+// formatter was created with WithFailFast.  A record is rejected when it is
+// nil, is missing a required field or has a value that can not be written to
+// the CSV data, like a map or a slice.
+//
+// A field that the record does not have, or that is nil, is NullValue when the
+// record inserts nulls and empty otherwise, unless the formatter has an
+// override for the field.  A field of an insert job is never NullValue.  An
+// explicit empty string is a quoted empty value, which is not null.
 func (f *Formatter) Add(records ...Record) error {
 	if records == nil {
 		return errors.New("bulk formatter: record interface can not be nil")
 	}
 
-	for _, record := range records {
-		recFields := record.Fields()
-		values := make([]string, len(f.fields))
-		insertNull := record.InsertNull()
-		for idx, field := range f.fields {
-			if insertNull {
-				values[idx] = "#N/A"
-			} else {
-				values[idx] = ""
+	var errs []*RecordError
+	for idx, record := range records {
+		values, quoted, err := f.values(idx, record)
+		if err != nil {
+			var recordErr *RecordError
+			if errors.As(err, &recordErr) == false {
+				recordErr = &RecordError{Index: idx, Reason: err.Error(), Err: err}
 			}
-			if value, ok := recFields[field]; ok {
-				if value != nil {
-					values[idx] = fmt.Sprintf("%v", value)
-				}
+			if f.failFast {
+				return recordErr
 			}
+			errs = append(errs, recordErr)
+			continue
 		}
-		err := f.writer.Write(values)
-		if err != nil {
-			return err
+		f.writeRow(values, quoted)
+		if f.index != nil {
+			f.index.add(values)
 		}
 	}
-	f.writer.Flush()
-
+	if len(errs) > 0 {
+		return &MultiError{Errors: errs}
+	}
 	return nil
 }
 
+// values returns the record's values and whether each of them is an explicit
+// empty string.
+func (f *Formatter) values(idx int, record Record) ([]string, []bool, error) {
+	if isNilRecord(record) {
+		return nil, nil, &RecordError{Index: idx, Reason: "record is nil"}
+	}
+	recFields := record.Fields()
+	for _, field := range f.required {
+		if value, ok := recFields[field]; ok == false || value == nil {
+			return nil, nil, &RecordError{Index: idx, Field: field, Reason: "required field is missing"}
+		}
+	}
+
+	values := make([]string, len(f.fields))
+	quoted := make([]bool, len(f.fields))
+	insertNull := record.InsertNull()
+	var dropped []string
+	for pos, field := range f.fields {
+		value, ok := recFields[field]
+		if _, is := value.(unchanged); is {
+			continue
+		}
+		if ok && value != nil {
+			if serializable(value) == false {
+				return nil, nil, &RecordError{Index: idx, Field: field, Reason: fmt.Sprintf("%T value can not be written", value)}
+			}
+			values[pos] = fmt.Sprintf("%v", value)
+			quoted[pos] = values[pos] == ""
+			continue
+		}
+		if f.insertNull(field, insertNull) == false {
+			continue
+		}
+		if f.job.Response().Operation == Insert {
+			dropped = append(dropped, field)
+			continue
+		}
+		values[pos] = NullValue
+	}
+	if len(dropped) > 0 && f.nullWarning != nil {
+		f.nullWarning(NullWarning{
+			Index:  idx,
+			Fields: dropped,
+		})
+	}
+	return values, quoted, nil
+}
+
+// isNilRecord returns whether the record is nil or a nil pointer.
+func isNilRecord(record Record) bool {
+	return record == nil || reflect.ValueOf(record).Kind() == reflect.Ptr && reflect.ValueOf(record).IsNil()
+}
+
+// insertNull returns whether the field is set to null when it does not have a
+// value.
+func (f *Formatter) insertNull(field string, insertNull bool) bool {
+	if override, has := f.nullFields[field]; has {
+		return override
+	}
+	return insertNull
+}
+
+// writeRow writes the row like a csv.Writer with the job's delimiter and line
+// ending, except that the quoted values are always quoted, so an explicit empty
+// string is not an empty value.
+func (f *Formatter) writeRow(values []string, quoted []bool) {
+	comma := f.job.delimiter()
+	crlf := f.job.Response().LineEnding == CarriageReturnLinefeed
+	for pos, value := range values {
+		if pos > 0 {
+			f.sb.WriteRune(comma)
+		}
+		if (quoted == nil || quoted[pos] == false) && needsQuotes(value, comma) == false {
+			f.sb.WriteString(value)
+			continue
+		}
+		f.sb.WriteByte('"')
+		for _, r := range value {
+			switch r {
+			case '"':
+				f.sb.WriteString(`""`)
+			case '\r':
+				if crlf == false {
+					f.sb.WriteRune(r)
+				}
+			case '\n':
+				if crlf {
+					f.sb.WriteString("\r\n")
+				} else {
+					f.sb.WriteRune(r)
+				}
+			default:
+				f.sb.WriteRune(r)
+			}
+		}
+		f.sb.WriteByte('"')
+	}
+	if crlf {
+		f.sb.WriteString("\r\n")
+	} else {
+		f.sb.WriteString("\n")
+	}
+}
+
+// needsQuotes reports whether the value is quoted by a csv.Writer.
+func needsQuotes(value string, comma rune) bool {
+	if value == "" {
+		return false
+	}
+	if value == `\.` || strings.ContainsRune(value, comma) || strings.ContainsAny(value, "\"\r\n") {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(value)
+	return unicode.IsSpace(r)
+}
+
+// serializable returns false for the values that fmt does not write as a
+// single CSV value, unless they are a fmt.Stringer.
+func serializable(value interface{}) bool {
+	if _, ok := value.(fmt.Stringer); ok {
+		return true
+	}
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return false
+	default:
+		return true
+	}
+}
+
+// RowIndex will return the row index of the added records.  The index is nil
+// unless the formatter was created with WithRowIndex.
+func (f *Formatter) RowIndex() *RowIndex {
+	return f.index
+}
+
 // Reader will return a reader of the bulk uploader field record body.
 func (f *Formatter) Reader() *strings.Reader {
 	return strings.NewReader(f.sb.String())
@@ -1,6 +1,9 @@
 package bulk
 
 import (
+	"errors"
+	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -195,3 +198,239 @@ func TestFormatter_Add(t *testing.T) {
 		})
 	}
 }
+
+func multiErrorRecords() []Record {
+	records := make([]Record, 10)
+	for idx := range records {
+		records[idx] = &testRecord{
+			fields: map[string]interface{}{
+				"Name": fmt.Sprintf("name %d", idx),
+				"Site": idx,
+			},
+		}
+	}
+	records[3] = &testRecord{
+		fields: map[string]interface{}{
+			"Site": "no name",
+		},
+	}
+	records[7] = &testRecord{
+		fields: map[string]interface{}{
+			"Name": "name 7",
+			"Site": []string{"a", "b"},
+		},
+	}
+	return records
+}
+
+func TestFormatter_Add_MultiError(t *testing.T) {
+	job := &Job{
+		info: Response{
+			ColumnDelimiter: Comma,
+			LineEnding:      Linefeed,
+		},
+	}
+	f, err := NewFormatter(job, []string{"Name", "Site"}, WithRequiredFields("Name"), WithRowIndex())
+	if err != nil {
+		t.Fatalf("NewFormatter() error = %v", err)
+	}
+	err = f.Add(multiErrorRecords()...)
+	var multiErr *MultiError
+	if errors.As(err, &multiErr) == false {
+		t.Fatalf("Formatter.Add() error = %v, want a MultiError", err)
+	}
+	want := []*RecordError{
+		{Index: 3, Field: "Name", Reason: "required field is missing"},
+		{Index: 7, Field: "Site", Reason: "[]string value can not be written"},
+	}
+	if !reflect.DeepEqual(multiErr.Errors, want) {
+		t.Errorf("MultiError.Errors = %v, want %v", multiErr.Errors, want)
+	}
+	var recordErr *RecordError
+	if errors.As(err, &recordErr) == false || recordErr.Index != 3 {
+		t.Errorf("errors.As() = %v, want the first record error", recordErr)
+	}
+	if errors.Is(err, multiErr.Errors[1]) == false {
+		t.Errorf("errors.Is() = false, want the second record error")
+	}
+	if err.Error() != "bulk formatter: record 3 field Name: required field is missing (and 1 more rejected records)" {
+		t.Errorf("MultiError.Error() = %s", err.Error())
+	}
+
+	lines := strings.Split(strings.TrimSpace(f.sb.String()), "\n")
+	if len(lines) != 9 || lines[4] != "name 4,4" || lines[7] != "name 8,8" {
+		t.Errorf("Formatter.Add() = %q, want the header and 8 valid records", lines)
+	}
+	if rows := f.RowIndex(); rows.count != 8 {
+		t.Errorf("Formatter.RowIndex() rows = %d, want 8", rows.count)
+	}
+}
+
+func TestFormatter_Add_FailFast(t *testing.T) {
+	job := &Job{
+		info: Response{
+			ColumnDelimiter: Comma,
+			LineEnding:      Linefeed,
+		},
+	}
+	f, err := NewFormatter(job, []string{"Name", "Site"}, WithRequiredFields("Name"), WithFailFast())
+	if err != nil {
+		t.Fatalf("NewFormatter() error = %v", err)
+	}
+	err = f.Add(multiErrorRecords()...)
+	var recordErr *RecordError
+	if errors.As(err, &recordErr) == false {
+		t.Fatalf("Formatter.Add() error = %v, want a RecordError", err)
+	}
+	var multiErr *MultiError
+	if errors.As(err, &multiErr) {
+		t.Errorf("Formatter.Add() error = %v, want only the first record error", err)
+	}
+	if recordErr.Index != 3 || recordErr.Field != "Name" {
+		t.Errorf("Formatter.Add() error = %+v", recordErr)
+	}
+	if lines := strings.Split(strings.TrimSpace(f.sb.String()), "\n"); len(lines) != 4 {
+		t.Errorf("Formatter.Add() = %q, want the header and the 3 records before the rejected record", lines)
+	}
+}
+
+func TestFormatter_Add_NilRecord(t *testing.T) {
+	job := &Job{}
+	f, err := NewFormatter(job, []string{"Name"})
+	if err != nil {
+		t.Fatalf("NewFormatter() error = %v", err)
+	}
+	var nilRecord *testRecord
+	err = f.Add(&testRecord{fields: map[string]interface{}{"Name": "a"}}, nil, nilRecord)
+	var multiErr *MultiError
+	if errors.As(err, &multiErr) == false || len(multiErr.Errors) != 2 || multiErr.Errors[1].Index != 2 {
+		t.Fatalf("Formatter.Add() error = %v", err)
+	}
+	if f.sb.String() != "Name\na\n" {
+		t.Errorf("Formatter.Add() = %q", f.sb.String())
+	}
+}
+
+func TestFormatter_Add_Nulls(t *testing.T) {
+	records := func(insertNull bool) []Record {
+		return []Record{
+			&testRecord{
+				fields: map[string]interface{}{
+					"Name":  "Acme",
+					"Site":  nil,
+					"Phone": "",
+				},
+				insertNull: insertNull,
+			},
+			&testRecord{
+				fields: map[string]interface{}{
+					"Name": "Globex, Inc.",
+					"Site": Unchanged,
+				},
+				insertNull: insertNull,
+			},
+		}
+	}
+	tests := []struct {
+		name         string
+		operation    Operation
+		insertNull   bool
+		options      []FormatterOption
+		want         string
+		wantWarnings []NullWarning
+	}{
+		{
+			name:      "update",
+			operation: Update,
+			want:      "Name,Site,Phone,Fax\nAcme,,\"\",\n\"Globex, Inc.\",,,\n",
+		},
+		{
+			name:       "update inserting nulls",
+			operation:  Update,
+			insertNull: true,
+			want:       "Name,Site,Phone,Fax\nAcme,#N/A,\"\",#N/A\n\"Globex, Inc.\",,#N/A,#N/A\n",
+		},
+		{
+			name:       "upsert with field overrides",
+			operation:  Upsert,
+			insertNull: true,
+			options:    []FormatterOption{WithFieldInsertNull(map[string]bool{"Fax": false})},
+			want:       "Name,Site,Phone,Fax\nAcme,#N/A,\"\",\n\"Globex, Inc.\",,#N/A,\n",
+		},
+		{
+			name:      "update with a null field override",
+			operation: Update,
+			options:   []FormatterOption{WithFieldInsertNull(map[string]bool{"Fax": true})},
+			want:      "Name,Site,Phone,Fax\nAcme,,\"\",#N/A\n\"Globex, Inc.\",,,#N/A\n",
+		},
+		{
+			name:       "insert",
+			operation:  Insert,
+			insertNull: true,
+			want:       "Name,Site,Phone,Fax\nAcme,,\"\",\n\"Globex, Inc.\",,,\n",
+			wantWarnings: []NullWarning{
+				{Index: 0, Fields: []string{"Site", "Fax"}},
+				{Index: 1, Fields: []string{"Phone", "Fax"}},
+			},
+		},
+		{
+			name:      "insert without nulls",
+			operation: Insert,
+			want:      "Name,Site,Phone,Fax\nAcme,,\"\",\n\"Globex, Inc.\",,,\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := &Job{
+				info: Response{
+					Operation:       tt.operation,
+					ColumnDelimiter: Comma,
+					LineEnding:      Linefeed,
+				},
+			}
+			var warnings []NullWarning
+			options := append(tt.options, WithNullWarning(func(warning NullWarning) {
+				warnings = append(warnings, warning)
+			}))
+			f, err := NewFormatter(job, []string{"Name", "Site", "Phone", "Fax"}, options...)
+			if err != nil {
+				t.Fatalf("NewFormatter() error = %v", err)
+			}
+			if err := f.Add(records(tt.insertNull)...); err != nil {
+				t.Fatalf("Formatter.Add() error = %v", err)
+			}
+			if got := f.sb.String(); got != tt.want {
+				t.Errorf("Formatter.Add() = %q, want %q", got, tt.want)
+			}
+			if !reflect.DeepEqual(warnings, tt.wantWarnings) {
+				t.Errorf("Formatter.Add() warnings = %v, want %v", warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestFormatter_Add_Quoting(t *testing.T) {
+	job := &Job{
+		info: Response{
+			ColumnDelimiter: Pipe,
+			LineEnding:      CarriageReturnLinefeed,
+		},
+	}
+	f, err := NewFormatter(job, []string{"Name", "Description"})
+	if err != nil {
+		t.Fatalf("NewFormatter() error = %v", err)
+	}
+	err = f.Add(&testRecord{
+		fields: map[string]interface{}{
+			"Name":        " Acme | \"West\"",
+			"Description": "line 1\nline 2",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Formatter.Add() error = %v", err)
+	}
+	want := "Name|Description\r\n\" Acme | \"\"West\"\"\"|\"line 1\r\nline 2\"\r\n"
+	if got := f.sb.String(); got != want {
+		t.Errorf("Formatter.Add() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,174 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	sfdc "github.com/namely/go-sfdc/v3"
+)
+
+// idAlphabet is the base62 alphabet of Salesforce IDs, in the order that
+// Salesforce sorts them.
+const idAlphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// IDRange is a sub-range of the Id split query.  From is exclusive and To is
+// inclusive, so the ranges do not overlap or leave gaps.  An empty From or To
+// leaves that end of the range unbounded, so the first and last ranges also
+// include the records created outside of the sampled IDs.
+type IDRange struct {
+	From string
+	To   string
+}
+
+// SplitIDRange will split the IDs from min to max, inclusive, into at most
+// count evenly spaced ranges.  The IDs must be 15 or 18 character Salesforce
+// IDs and the range boundaries are returned as 18 character IDs.  The first
+// range has no lower bound and the last range has no upper bound.  The soql
+// resource's IDRangePartitions samples the min and max IDs of an object and
+// splits them with SplitIDRange, since this package can not query with the
+// soql resource.
+func SplitIDRange(min, max string, count int) ([]IDRange, error) {
+	if count < 1 {
+		return nil, errors.New("bulk split query: count must be greater than zero")
+	}
+	low, err := idValue(min)
+	if err != nil {
+		return nil, err
+	}
+	high, err := idValue(max)
+	if err != nil {
+		return nil, err
+	}
+	span := new(big.Int).Sub(high, low)
+	if span.Sign() < 0 {
+		return nil, errors.New("bulk split query: min id must not be after max id")
+	}
+	if span.IsInt64() && span.Int64() < int64(count) {
+		count = int(span.Int64())
+		if count == 0 {
+			count = 1
+		}
+	}
+
+	ranges := make([]IDRange, count)
+	from := ""
+	for idx := range ranges {
+		to := ""
+		if idx < count-1 {
+			boundary := new(big.Int).Mul(span, big.NewInt(int64(idx+1)))
+			boundary.Div(boundary, big.NewInt(int64(count)))
+			to = idFromValue(boundary.Add(boundary, low))
+		}
+		ranges[idx] = IDRange{
+			From: from,
+			To:   to,
+		}
+		from = to
+	}
+	return ranges, nil
+}
+
+// IDRangeQuery will add the Id range condition to the query.  If the query has
+// a WHERE clause, the existing condition is kept and combined with the Id range.
+// A range with no bounds returns the query unchanged.
+func IDRangeQuery(baseQuery string, idRange IDRange) (string, error) {
+	if baseQuery == "" {
+		return "", errors.New("bulk split query: query is required")
+	}
+
+	var conditions []string
+	if idRange.From != "" {
+		if _, err := idValue(idRange.From); err != nil {
+			return "", err
+		}
+		conditions = append(conditions, fmt.Sprintf("Id > '%s'", idRange.From))
+	}
+	if idRange.To != "" {
+		if _, err := idValue(idRange.To); err != nil {
+			return "", err
+		}
+		conditions = append(conditions, fmt.Sprintf("Id <= '%s'", idRange.To))
+	}
+	if len(conditions) == 0 {
+		return baseQuery, nil
+	}
+	return addCondition(baseQuery, strings.Join(conditions, " AND "))
+}
+
+// SplitQueryByIDRange will run a query job for each of the Id ranges, with the
+// range condition added to the query.  The query jobs are run with bounded
+// concurrency and the returned pages iterate over the results of all of the
+// jobs, in the order of the ranges, once they complete.  The query jobs share
+// the correlation ID of the context, or a new one if the context does not have
+// one.
+func SplitQueryByIDRange(ctx context.Context, resource *Resource, baseQuery string, ranges []IDRange) (*QueryPages, error) {
+	if resource == nil {
+		return nil, errors.New("bulk split query: resource can not be nil")
+	}
+	if len(ranges) == 0 {
+		return nil, errors.New("bulk split query: ranges are required")
+	}
+	queries := make([]string, len(ranges))
+	for idx, idRange := range ranges {
+		var err error
+		queries[idx], err = IDRangeQuery(baseQuery, idRange)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ctx = sfdc.WithCorrelationID(ctx, correlationID(ctx))
+	jobs, err := runQueryJobs(ctx, resource, queries, func(idx int) string {
+		return fmt.Sprintf("id range %q to %q", ranges[idx].From, ranges[idx].To)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &QueryPages{
+		ctx:      ctx,
+		jobs:     jobs,
+		idRanges: ranges,
+	}, nil
+}
+
+// idValue returns the base62 value of the 15 character form of the ID.
+func idValue(id string) (*big.Int, error) {
+	if len(id) != 15 && len(id) != 18 {
+		return nil, fmt.Errorf("bulk split query: %q is not a valid id", id)
+	}
+	value := new(big.Int)
+	base := big.NewInt(int64(len(idAlphabet)))
+	for _, char := range id[:15] {
+		digit := strings.IndexRune(idAlphabet, char)
+		if digit < 0 {
+			return nil, fmt.Errorf("bulk split query: %q is not a valid id", id)
+		}
+		value.Mul(value, base)
+		value.Add(value, big.NewInt(int64(digit)))
+	}
+	if len(id) == 18 {
+		if _, err := sfdc.NormalizeID(id); err != nil {
+			return nil, fmt.Errorf("bulk split query: %w", err)
+		}
+	}
+	return value, nil
+}
+
+// idFromValue returns the 18 character ID of the base62 value.
+func idFromValue(value *big.Int) string {
+	digits := make([]byte, 15)
+	base := big.NewInt(int64(len(idAlphabet)))
+	value = new(big.Int).Set(value)
+	digit := new(big.Int)
+	for idx := len(digits) - 1; idx >= 0; idx-- {
+		value.DivMod(value, base, digit)
+		digits[idx] = idAlphabet[digit.Int64()]
+	}
+	// the digits are always a valid 15 character ID
+	id, _ := sfdc.NormalizeID(string(digits))
+	return id
+}
@@ -0,0 +1,309 @@
+package bulk
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/namely/go-sfdc/v3"
+)
+
+func TestIDValue(t *testing.T) {
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{id: "001A0000006Vm9r", want: "001A0000006Vm9rIAC"},
+		{id: "003000000000001", want: "003000000000001AAA"},
+		{id: "a0B5e00000ABCde", want: "a0B5e00000ABCdeEAH"},
+		{id: "ZZZZZZZZZZZZZZZ", want: "ZZZZZZZZZZZZZZZ555"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			value, err := idValue(tt.id)
+			if err != nil {
+				t.Fatalf("idValue() error = %v", err)
+			}
+			if got := idFromValue(value); got != tt.want {
+				t.Errorf("idFromValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitIDRange(t *testing.T) {
+	tests := []struct {
+		name    string
+		min     string
+		max     string
+		count   int
+		want    []IDRange
+		wantErr bool
+	}{
+		{
+			name:  "even split",
+			min:   "001000000000000",
+			max:   "001000000000030",
+			count: 3,
+			want: []IDRange{
+				{To: "001000000000010AAA"},
+				{From: "001000000000010AAA", To: "001000000000020AAA"},
+				{From: "001000000000020AAA"},
+			},
+		},
+		{
+			name:  "18 character ids",
+			min:   "001A0000006Vm9rIAC",
+			max:   "001A0000006Vm9tIAC",
+			count: 2,
+			want: []IDRange{
+				{To: "001A0000006Vm9sIAC"},
+				{From: "001A0000006Vm9sIAC"},
+			},
+		},
+		{
+			name:  "fewer ids than partitions",
+			min:   "001000000000000",
+			max:   "001000000000002",
+			count: 5,
+			want: []IDRange{
+				{To: "001000000000001AAA"},
+				{From: "001000000000001AAA"},
+			},
+		},
+		{
+			name:  "single partition",
+			min:   "001000000000000",
+			max:   "001zzzzzzzzzzzz",
+			count: 1,
+			want:  []IDRange{{}},
+		},
+		{
+			name:  "single id",
+			min:   "001000000000000",
+			max:   "001000000000000",
+			count: 3,
+			want:  []IDRange{{}},
+		},
+		{
+			name:    "min after max",
+			min:     "001000000000030",
+			max:     "001000000000000",
+			count:   3,
+			wantErr: true,
+		},
+		{
+			name:    "invalid id",
+			min:     "001-00000000000",
+			max:     "001000000000030",
+			count:   3,
+			wantErr: true,
+		},
+		{
+			name:    "invalid checksum",
+			min:     "001A0000006Vm9rAAA",
+			max:     "001A0000006Vm9tIAC",
+			count:   3,
+			wantErr: true,
+		},
+		{
+			name:    "no partitions",
+			min:     "001000000000000",
+			max:     "001000000000030",
+			count:   0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SplitIDRange(tt.min, tt.max, tt.count)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SplitIDRange() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitIDRange() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitIDRange_coverage(t *testing.T) {
+	min, max := "001A0000006Vm00", "001A0000006Vmzz"
+	ranges, err := SplitIDRange(min, max, 7)
+	if err != nil {
+		t.Fatalf("SplitIDRange() error = %v", err)
+	}
+	if len(ranges) != 7 {
+		t.Fatalf("SplitIDRange() ranges = %d, want 7", len(ranges))
+	}
+	if ranges[0].From != "" || ranges[len(ranges)-1].To != "" {
+		t.Errorf("SplitIDRange() = %v, want unbounded ends", ranges)
+	}
+
+	low, _ := idValue(min)
+	high, _ := idValue(max)
+	for value := new(big.Int).Sub(low, big.NewInt(1)); value.Cmp(high) <= 0; value.Add(value, big.NewInt(1)) {
+		id := idFromValue(value)
+		matches := 0
+		for _, idRange := range ranges {
+			if (idRange.From == "" || id > idRange.From) && (idRange.To == "" || id <= idRange.To) {
+				matches++
+			}
+		}
+		if matches != 1 {
+			t.Fatalf("SplitIDRange() id %s is in %d ranges, want 1", id, matches)
+		}
+	}
+}
+
+func TestIDRangeQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		idRange IDRange
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "bounded",
+			query:   "SELECT Id FROM Account",
+			idRange: IDRange{From: "001000000000010AAA", To: "001000000000020AAA"},
+			want:    "SELECT Id FROM Account WHERE Id > '001000000000010AAA' AND Id <= '001000000000020AAA'",
+		},
+		{
+			name:    "existing where and order",
+			query:   "SELECT Id FROM Account WHERE Name = 'a' OR Name = 'b' ORDER BY Name",
+			idRange: IDRange{To: "001000000000020AAA"},
+			want:    "SELECT Id FROM Account WHERE (Name = 'a' OR Name = 'b') AND Id <= '001000000000020AAA' ORDER BY Name",
+		},
+		{
+			name:    "sub-query and literal",
+			query:   "SELECT Id, (SELECT Id FROM Contacts WHERE Email != null) FROM Account WHERE Name != 'GROUP BY' LIMIT 100",
+			idRange: IDRange{From: "001000000000010AAA"},
+			want:    "SELECT Id, (SELECT Id FROM Contacts WHERE Email != null) FROM Account WHERE (Name != 'GROUP BY') AND Id > '001000000000010AAA' LIMIT 100",
+		},
+		{
+			name:    "unbounded",
+			query:   "SELECT Id FROM Account",
+			idRange: IDRange{},
+			want:    "SELECT Id FROM Account",
+		},
+		{
+			name:    "invalid id",
+			query:   "SELECT Id FROM Account",
+			idRange: IDRange{From: "' OR Name != '"},
+			wantErr: true,
+		},
+		{
+			name:    "no query",
+			idRange: IDRange{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := IDRangeQuery(tt.query, tt.idRange)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("IDRangeQuery() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("IDRangeQuery() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitQueryByIDRange(t *testing.T) {
+	policy := queryPollPolicy
+	queryPollPolicy = sfdc.RetryPolicy{InitialInterval: time.Millisecond, Multiplier: 1}
+	defer func() { queryPollPolicy = policy }()
+
+	ranges, err := SplitIDRange("001000000000000", "001000000000030", 3)
+	if err != nil {
+		t.Fatalf("SplitIDRange() error = %v", err)
+	}
+
+	var mu sync.Mutex
+	var queries []string
+	polls := make(map[string]int)
+	client := mockHTTPClient(func(req *http.Request) *http.Response {
+		mu.Lock()
+		defer mu.Unlock()
+
+		var resp string
+		path := strings.TrimPrefix(req.URL.Path, bulk2QueryEndpoint)
+		switch {
+		case req.Method == http.MethodPost:
+			var options QueryOptions
+			if err := json.NewDecoder(req.Body).Decode(&options); err != nil {
+				t.Errorf("query options error = %v", err)
+			}
+			queries = append(queries, options.Query)
+			id := "7502"
+			switch {
+			case strings.Contains(options.Query, "Id > ") == false:
+				id = "7500"
+			case strings.Contains(options.Query, "Id <= "):
+				id = "7501"
+			}
+			resp = `{"id": "` + id + `", "operation": "query", "state": "UploadComplete"}`
+		case strings.HasSuffix(path, "/results"):
+			id := strings.TrimSuffix(strings.TrimPrefix(path, "/"), "/results")
+			resp = "\"Id\"\n\"" + id + "-1\"\n"
+		default:
+			id := strings.TrimPrefix(path, "/")
+			polls[id]++
+			state := UpdateComplete
+			if polls[id] > 1 {
+				state = JobComplete
+			}
+			resp = `{"id": "` + id + `", "state": "` + string(state) + `"}`
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Status:     "Good",
+			Body:       io.NopCloser(strings.NewReader(resp)),
+			Header:     make(http.Header),
+		}
+	})
+	resource := &Resource{
+		session: &mockSessionFormatter{
+			client: client,
+		},
+	}
+
+	pages, err := SplitQueryByIDRange(context.Background(), resource, "SELECT Id FROM Account", ranges)
+	if err != nil {
+		t.Fatalf("SplitQueryByIDRange() error = %v", err)
+	}
+	if len(queries) != 3 {
+		t.Fatalf("SplitQueryByIDRange() queries = %v, want 3", queries)
+	}
+
+	var got []string
+	var gotRanges []IDRange
+	for pages.Next() {
+		page := pages.Page()
+		for _, record := range page.Records {
+			got = append(got, record["Id"])
+		}
+		gotRanges = append(gotRanges, page.IDRange)
+	}
+	if err := pages.Err(); err != nil {
+		t.Fatalf("QueryPages.Err() = %v", err)
+	}
+	want := []string{"7500-1", "7501-1", "7502-1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("QueryPages records = %v, want %v", got, want)
+	}
+	if !reflect.DeepEqual(gotRanges, ranges) {
+		t.Errorf("QueryPages ranges = %v, want %v", gotRanges, ranges)
+	}
+}
@@ -0,0 +1,78 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"sort"
+)
+
+// IngestAll will format the records and create, upload and close the jobs of
+// their CSV data, split like CreateChunkedJobs.  The information of each job
+// is returned in chunk order.
+//
+// The fields are all of the records' fields, sorted, so every chunk has the
+// same header.  The records are formatted like Add, and a rejected record is
+// returned before any job is created.  If a chunk fails, or a job's
+// information can not be retrieved, a ChunkError is returned with the jobs
+// that were created.
+func (r *Resource) IngestAll(ctx context.Context, options Options, records []Record, chunkOpts ...ChunkOption) ([]Info, error) {
+	if len(records) == 0 {
+		return nil, errors.New("bulk ingest: records can not be empty")
+	}
+	if err := checkContentType("", options.ContentType); err != nil {
+		return nil, err
+	}
+
+	fields := ingestFields(records)
+	if len(fields) == 0 {
+		return nil, errors.New("bulk ingest: records do not have any fields")
+	}
+	// the formatter only uses the options of the jobs that are created
+	formatter, err := NewFormatter(&Job{
+		info: Response{
+			ColumnDelimiter: options.ColumnDelimiter,
+			LineEnding:      options.LineEnding,
+			Operation:       options.Operation,
+		},
+	}, fields)
+	if err != nil {
+		return nil, err
+	}
+	if err := formatter.Add(records...); err != nil {
+		return nil, err
+	}
+
+	jobs, err := r.CreateChunkedJobs(ctx, options, formatter.Reader(), chunkOpts...)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]Info, len(jobs))
+	for idx, job := range jobs {
+		jobCtx, end := job.startSpan(ctx, "bulk.job.info")
+		infos[idx], err = job.fetchInfo(jobCtx, job.Response().ID)
+		end(err)
+		if err != nil {
+			return nil, &ChunkError{Chunk: idx, Jobs: jobs, Err: err}
+		}
+	}
+	return infos, nil
+}
+
+// ingestFields returns the sorted fields of the records.
+func ingestFields(records []Record) []string {
+	seen := make(map[string]bool)
+	var fields []string
+	for _, record := range records {
+		if isNilRecord(record) {
+			continue
+		}
+		for field := range record.Fields() {
+			if seen[field] == false {
+				seen[field] = true
+				fields = append(fields, field)
+			}
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
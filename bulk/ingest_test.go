@@ -0,0 +1,104 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestResource_IngestAll(t *testing.T) {
+	var uploads []string
+	created := 0
+	r, err := NewResource(&mockSessionFormatter{
+		url: "https://test.salesforce.com",
+		client: mockHTTPClient(func(req *http.Request) *http.Response {
+			switch req.Method {
+			case http.MethodPost:
+				created++
+				resp := fmt.Sprintf(`{"id": "7505fEXAMPLE%dAAM", "state": "Open"}`, created)
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(resp)),
+					Header:     make(http.Header),
+				}
+			case http.MethodPut:
+				body, _ := io.ReadAll(req.Body)
+				uploads = append(uploads, string(body))
+				return &http.Response{
+					StatusCode: http.StatusCreated,
+					Body:       io.NopCloser(strings.NewReader("")),
+					Header:     make(http.Header),
+				}
+			case http.MethodGet:
+				id := req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:]
+				resp := fmt.Sprintf(`{"id": %q, "state": "UploadComplete", "numberRecordsProcessed": 0}`, id)
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(resp)),
+					Header:     make(http.Header),
+				}
+			default:
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(`{"state": "UploadComplete"}`)),
+					Header:     make(http.Header),
+				}
+			}
+		}),
+	})
+	if err != nil {
+		t.Fatalf("NewResource() error = %v", err)
+	}
+	options := Options{
+		Object:    "Account",
+		Operation: Update,
+	}
+	records := []Record{
+		&testRecord{fields: map[string]interface{}{"Name": "Acme", "Id": "001A"}},
+		&testRecord{fields: map[string]interface{}{"Name": "Globex, Inc", "Site": "West", "Id": "001B"}},
+		&testRecord{fields: map[string]interface{}{"Id": "001C", "Site": "North"}, insertNull: true},
+	}
+
+	infos, err := r.IngestAll(context.Background(), options, records, WithMaxChunkBytes(40))
+	if err != nil {
+		t.Fatalf("Resource.IngestAll() error = %v", err)
+	}
+	want := []string{
+		"Id,Name,Site\n001A,Acme,\n",
+		"Id,Name,Site\n001B,\"Globex, Inc\",West\n",
+		"Id,Name,Site\n001C,#N/A,North\n",
+	}
+	if reflect.DeepEqual(uploads, want) == false {
+		t.Errorf("Resource.IngestAll() uploads = %q, want %q", uploads, want)
+	}
+	if len(infos) != 3 {
+		t.Fatalf("Resource.IngestAll() infos = %v", infos)
+	}
+	for idx, info := range infos {
+		if id := fmt.Sprintf("7505fEXAMPLE%dAAM", idx+1); info.ID != id || info.State != UpdateComplete {
+			t.Errorf("Resource.IngestAll() info %d = %+v, want job %s", idx, info, id)
+		}
+	}
+
+	uploads = nil
+	_, err = r.IngestAll(context.Background(), options, []Record{
+		&testRecord{fields: map[string]interface{}{"Name": "Acme"}},
+		nil,
+	})
+	var multiErr *MultiError
+	if errors.As(err, &multiErr) == false {
+		t.Errorf("Resource.IngestAll() error = %v, want MultiError", err)
+	}
+	if len(uploads) != 0 {
+		t.Errorf("Resource.IngestAll() uploads = %q, want none for rejected records", uploads)
+	}
+
+	if _, err := r.IngestAll(context.Background(), options, nil); err == nil {
+		t.Errorf("Resource.IngestAll() error = nil, want error for no records")
+	}
+}
@@ -0,0 +1,155 @@
+package bulk
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/namely/go-sfdc/v3"
+	"github.com/namely/go-sfdc/v3/credentials"
+	"github.com/namely/go-sfdc/v3/session"
+)
+
+func TestJob_instanceChange(t *testing.T) {
+	instanceURL := "https://na1.salesforce.com"
+	var hosts []string
+	client := mockHTTPClient(func(req *http.Request) *http.Response {
+		if req.URL.Path == "/services/oauth2/token" {
+			resp := fmt.Sprintf(`{"access_token": "token", "instance_url": %q, "token_type": "Bearer"}`, instanceURL)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(resp)),
+				Header:     make(http.Header),
+			}
+		}
+		hosts = append(hosts, req.URL.Host)
+		switch req.Method {
+		case http.MethodPut:
+			return &http.Response{
+				StatusCode: http.StatusCreated,
+				Body:       io.NopCloser(strings.NewReader("")),
+				Header:     make(http.Header),
+			}
+		case http.MethodPost:
+			resp := `{
+				"id": "7505fEXAMPLE4C2AAM",
+				"contentUrl": "https://na1.salesforce.com/services/data/v45.0/jobs/ingest/7505fEXAMPLE4C2AAM/batches",
+				"state": "Open"
+			}`
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(resp)),
+				Header:     make(http.Header),
+			}
+		default:
+			resp := `{"id": "7505fEXAMPLE4C2AAM", "state": "Open"}`
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(resp)),
+				Header:     make(http.Header),
+			}
+		}
+	})
+	creds, err := credentials.NewPasswordCredentials(credentials.PasswordCredentials{
+		URL:          "https://login.salesforce.com",
+		Username:     "myusername",
+		Password:     "12345",
+		ClientID:     "some client id",
+		ClientSecret: "shhhh its a secret",
+	})
+	if err != nil {
+		t.Fatalf("credentials.NewPasswordCredentials() error = %v", err)
+	}
+	sess, err := session.Open(sfdc.Configuration{
+		Credentials: creds,
+		Client:      client,
+		Version:     45,
+	})
+	if err != nil {
+		t.Fatalf("session.Open() error = %v", err)
+	}
+
+	resource, err := NewResource(sess)
+	if err != nil {
+		t.Fatalf("NewResource() error = %v", err)
+	}
+	job, err := resource.CreateJob(Options{
+		Object:    "Account",
+		Operation: Insert,
+	})
+	if err != nil {
+		t.Fatalf("Resource.CreateJob() error = %v", err)
+	}
+
+	instanceURL = "https://na2.salesforce.com"
+	if err := sess.ForceRefresh(context.Background()); err != nil {
+		t.Fatalf("Session.ForceRefresh() error = %v", err)
+	}
+
+	if _, err := job.Info(); err != nil {
+		t.Fatalf("Job.Info() error = %v", err)
+	}
+	if err := job.Upload(strings.NewReader("Name\nAcme\n")); err != nil {
+		t.Fatalf("Job.Upload() error = %v", err)
+	}
+
+	want := []string{"na1.salesforce.com", "na2.salesforce.com", "na2.salesforce.com"}
+	if strings.Join(hosts, ",") != strings.Join(want, ",") {
+		t.Errorf("hosts = %v, want %v", hosts, want)
+	}
+}
+
+func TestJob_Upload_userAgent(t *testing.T) {
+	var userAgent string
+	client := mockHTTPClient(func(req *http.Request) *http.Response {
+		if req.URL.Path == "/services/oauth2/token" {
+			resp := `{"access_token": "token", "instance_url": "https://na1.salesforce.com", "token_type": "Bearer"}`
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(resp)),
+				Header:     make(http.Header),
+			}
+		}
+		userAgent = req.Header.Get("User-Agent")
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       io.NopCloser(strings.NewReader("")),
+			Header:     make(http.Header),
+		}
+	})
+	creds, err := credentials.NewPasswordCredentials(credentials.PasswordCredentials{
+		URL:          "https://login.salesforce.com",
+		Username:     "myusername",
+		Password:     "12345",
+		ClientID:     "some client id",
+		ClientSecret: "shhhh its a secret",
+	})
+	if err != nil {
+		t.Fatalf("credentials.NewPasswordCredentials() error = %v", err)
+	}
+	sess, err := session.Open(sfdc.Configuration{
+		Credentials: creds,
+		Client:      client,
+		Version:     45,
+	})
+	if err != nil {
+		t.Fatalf("session.Open() error = %v", err)
+	}
+
+	job := &Job{
+		session: sess,
+		info: Response{
+			ID: "7505fEXAMPLE4C2AAM",
+		},
+	}
+	if err := job.Upload(strings.NewReader("Name\nAcme\n")); err != nil {
+		t.Fatalf("Job.Upload() error = %v", err)
+	}
+
+	if want := sfdc.UserAgent(""); userAgent != want {
+		t.Errorf("User-Agent = %v, want %v", userAgent, want)
+	}
+}
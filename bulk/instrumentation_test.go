@@ -0,0 +1,155 @@
+package bulk
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/namely/go-sfdc/v3"
+	"github.com/namely/go-sfdc/v3/credentials"
+	"github.com/namely/go-sfdc/v3/session"
+)
+
+type spanKey struct{}
+
+type recordedSpan struct {
+	op     string
+	parent string
+	attrs  map[string]interface{}
+	ended  bool
+	err    error
+}
+
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+func (r *recordingTracer) StartSpan(ctx context.Context, op string, attrs map[string]interface{}) (context.Context, func(error)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	span := &recordedSpan{
+		op:    op,
+		attrs: attrs,
+	}
+	if parent, ok := ctx.Value(spanKey{}).(*recordedSpan); ok {
+		span.parent = parent.op
+	}
+	r.spans = append(r.spans, span)
+	return context.WithValue(ctx, spanKey{}, span), func(err error) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		span.ended = true
+		span.err = err
+	}
+}
+
+func TestInstrumentation_jobFlow(t *testing.T) {
+	client := mockHTTPClient(func(req *http.Request) *http.Response {
+		var resp string
+		status := http.StatusOK
+		switch {
+		case req.URL.Path == "/services/oauth2/token":
+			resp = `{"access_token": "token", "instance_url": "https://test.salesforce.com", "token_type": "Bearer"}`
+		case req.Method == http.MethodPost:
+			resp = `{"id": "7505fEXAMPLE4C2AAM", "object": "Account", "operation": "insert", "state": "Open"}`
+		case req.Method == http.MethodPut:
+			status = http.StatusCreated
+		case req.Method == http.MethodPatch:
+			resp = `{"id": "7505fEXAMPLE4C2AAM", "object": "Account", "operation": "insert", "state": "UploadComplete"}`
+		}
+		return &http.Response{
+			StatusCode: status,
+			Status:     http.StatusText(status),
+			Body:       io.NopCloser(strings.NewReader(resp)),
+			Header:     make(http.Header),
+		}
+	})
+	creds, err := credentials.NewPasswordCredentials(credentials.PasswordCredentials{
+		URL:          "https://login.salesforce.com",
+		Username:     "user",
+		Password:     "password",
+		ClientID:     "id",
+		ClientSecret: "secret",
+	})
+	if err != nil {
+		t.Fatalf("credentials error = %v", err)
+	}
+	tracer := &recordingTracer{}
+	s, err := session.Open(sfdc.Configuration{
+		Credentials:     creds,
+		Client:          client,
+		Version:         44,
+		Instrumentation: tracer,
+	})
+	if err != nil {
+		t.Fatalf("session.Open() error = %v", err)
+	}
+
+	resource, err := NewResource(s)
+	if err != nil {
+		t.Fatalf("NewResource() error = %v", err)
+	}
+	job, err := resource.CreateJob(Options{
+		Object:    "Account",
+		Operation: Insert,
+	})
+	if err != nil {
+		t.Fatalf("Resource.CreateJob() error = %v", err)
+	}
+	if err := job.Upload(strings.NewReader("Name\nname 1\n")); err != nil {
+		t.Fatalf("Job.Upload() error = %v", err)
+	}
+	if _, err := job.Close(); err != nil {
+		t.Fatalf("Job.Close() error = %v", err)
+	}
+
+	type span struct {
+		op     string
+		parent string
+	}
+	want := []span{
+		{op: "bulk.job.create"},
+		{op: "http.request", parent: "bulk.job.create"},
+		{op: "bulk.job.upload"},
+		{op: "http.request", parent: "bulk.job.upload"},
+		{op: "bulk.job.close"},
+		{op: "http.request", parent: "bulk.job.close"},
+	}
+	got := make([]span, len(tracer.spans))
+	for idx, recorded := range tracer.spans {
+		got[idx] = span{op: recorded.op, parent: recorded.parent}
+		if recorded.ended == false {
+			t.Errorf("span %s was not ended", recorded.op)
+		}
+		if recorded.err != nil {
+			t.Errorf("span %s error = %v", recorded.op, recorded.err)
+		}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("spans = %v, want %v", got, want)
+	}
+	if attrs := tracer.spans[2].attrs; attrs["job.id"] != "7505fEXAMPLE4C2AAM" || attrs["object"] != "Account" {
+		t.Errorf("upload span attributes = %v", attrs)
+	}
+}
+
+func TestInstrumentation_none(t *testing.T) {
+	called := false
+	ctx, end := session.StartSpan(context.Background(), &mockSessionFormatter{}, "bulk.job.create", func() map[string]interface{} {
+		called = true
+		return nil
+	})
+	end(nil)
+	if called {
+		t.Errorf("attributes were built without instrumentation")
+	}
+	if ctx != context.Background() {
+		t.Errorf("context was changed without instrumentation")
+	}
+}
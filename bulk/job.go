@@ -2,14 +2,20 @@ package bulk
 
 import (
 	"bytes"
+	"context"
 	"encoding/csv"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/namely/go-sfdc/v3"
+	"github.com/namely/go-sfdc/v3/bulk/results"
 	"github.com/namely/go-sfdc/v3/session"
 )
 
@@ -46,8 +52,75 @@ const (
 // ContentType is the format of the data being processed.
 type ContentType string
 
-// CSV is the supported content data type.
-const CSV ContentType = "CSV"
+const (
+	// CSV is the content data type of the bulk 2.0 jobs.
+	CSV ContentType = "CSV"
+	// JSON is the content data type of the bulk 1.0 JSON jobs.
+	JSON ContentType = "JSON"
+	// XML is the content data type of the bulk 1.0 XML jobs.
+	XML ContentType = "XML"
+	// ZipCSV is the content data type of the bulk 1.0 jobs with zipped CSV batches.
+	ZipCSV ContentType = "ZIP_CSV"
+	// ZipJSON is the content data type of the bulk 1.0 jobs with zipped JSON batches.
+	ZipJSON ContentType = "ZIP_JSON"
+	// ZipXML is the content data type of the bulk 1.0 jobs with zipped XML batches.
+	ZipXML ContentType = "ZIP_XML"
+)
+
+// IsValid returns whether the content type is a known content type.
+func (c ContentType) IsValid() bool {
+	switch c {
+	case CSV, JSON, XML, ZipCSV, ZipJSON, ZipXML:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsZip returns whether the content type is zipped batches, which the bulk 1.0
+// jobs use for binary attachments.
+func (c ContentType) IsZip() bool {
+	switch c {
+	case ZipCSV, ZipJSON, ZipXML:
+		return true
+	default:
+		return false
+	}
+}
+
+// ContentTypeError is returned when the content type can not be used by the
+// bulk 2.0 jobs, which only have CSV data.  The job listing can have the bulk
+// 1.0 jobs, with the other content types.
+//
+// JobID is the ID of the job, which is empty when the job is being created.
+//
+// ContentType is the content type of the job.
+type ContentTypeError struct {
+	JobID       string
+	ContentType ContentType
+}
+
+func (e *ContentTypeError) Error() string {
+	reason := "is not supported by bulk 2.0 jobs"
+	if e.ContentType.IsValid() == false {
+		reason = "is not a known content type"
+	}
+	if e.JobID == "" {
+		return fmt.Sprintf("bulk job: content type %q %s", e.ContentType, reason)
+	}
+	return fmt.Sprintf("bulk job: job %s content type %q %s", e.JobID, e.ContentType, reason)
+}
+
+// checkContentType returns a ContentTypeError when the content type is not
+// CSV.  An empty content type is CSV.
+func checkContentType(id string, contentType ContentType) error {
+	switch contentType {
+	case "", CSV:
+		return nil
+	default:
+		return &ContentTypeError{JobID: id, ContentType: contentType}
+	}
+}
 
 // LineEnding is the line ending used for the CSV job data.
 type LineEnding string
@@ -67,12 +140,26 @@ const (
 	Insert Operation = "insert"
 	// Delete is the object operation for deleting records.
 	Delete Operation = "delete"
+	// HardDelete is the object operation for deleting records without moving
+	// them to the recycle bin.  The user needs the Bulk API Hard Delete
+	// permission.
+	HardDelete Operation = "hardDelete"
 	// Update is the object operation for updating records.
 	Update Operation = "update"
 	// Upsert is the object operation for upserting records.
 	Upsert Operation = "upsert"
 )
 
+// IsValid returns whether the operation is a known ingest operation.
+func (o Operation) IsValid() bool {
+	switch o {
+	case Insert, Delete, HardDelete, Update, Upsert:
+		return true
+	default:
+		return false
+	}
+}
+
 // State is the current state of processing for the job.
 type State string
 
@@ -81,6 +168,8 @@ const (
 	Open State = "Open"
 	// UpdateComplete all data for the job has been uploaded and the job is ready to be queued and processed.
 	UpdateComplete State = "UploadComplete"
+	// InProgress the job is being processed by Salesforce.
+	InProgress State = "InProgress"
 	// Aborted the job has been aborted.
 	Aborted State = "Aborted"
 	// JobComplete the job was processed by Salesforce.
@@ -100,9 +189,12 @@ const (
 	sfCreated = "sf__Created"
 )
 
-// UnprocessedRecord is the unprocessed records from the job.
+// UnprocessedRecord is the unprocessed records from the job.  Values are the
+// fields coerced to their field types, when the records are downloaded with
+// WithFieldTypes.
 type UnprocessedRecord struct {
 	Fields map[string]string
+	Values map[string]interface{}
 }
 
 // JobRecord is the record for the job.  Includes the Salesforce ID along with the fields.
@@ -117,12 +209,35 @@ type SuccessfulRecord struct {
 	JobRecord
 }
 
+// AsOutcome returns the record as a record outcome.
+func (r SuccessfulRecord) AsOutcome() results.RecordOutcome {
+	return results.RecordOutcome{
+		ID:      r.ID,
+		Created: r.Created,
+		Success: true,
+		Fields:  results.CopyFields(r.Fields),
+	}
+}
+
 // FailedRecord indicates why the record failed and the data of the record.
 type FailedRecord struct {
 	Error string
 	JobRecord
 }
 
+// AsOutcome returns the record as a record outcome.  The error is parsed into
+// its status code and message.
+func (r FailedRecord) AsOutcome() results.RecordOutcome {
+	outcome := results.RecordOutcome{
+		ID:     r.ID,
+		Fields: results.CopyFields(r.Fields),
+	}
+	if r.Error != "" {
+		outcome.Errors = []results.Error{results.ParseError(r.Error)}
+	}
+	return outcome
+}
+
 // Options are the options for the job.
 //
 // ColumnDelimiter is the delimiter used for the CSV job.  This field is optional.
@@ -130,13 +245,18 @@ type FailedRecord struct {
 // ContentType is the content type for the job.  This field is optional.
 //
 // ExternalIDFieldName is the external ID field in the object being updated.  Only needed for
-// upsert operations.  This field is required for upsert operations.
+// upsert operations.  This field is required for upsert operations.  If it is the Id field,
+// the upsert is passed to Salesforce as-is unless UpsertByIDAsUpdate is set.
 //
 // LineEnding is the line ending used for the CSV job data.  This field is optional.
 //
 // Object is the object type for the data bneing processed. This field is required.
 //
 // Operation is the processing operation for the job. This field is required.
+//
+// UpsertByIDAsUpdate changes an upsert with the Id field as the external ID field into an
+// update, since an Id can only match an existing record.  This field is optional and is not
+// sent to Salesforce.
 type Options struct {
 	ColumnDelimiter     ColumnDelimiter `json:"columnDelimiter"`
 	ContentType         ContentType     `json:"contentType"`
@@ -144,6 +264,7 @@ type Options struct {
 	LineEnding          LineEnding      `json:"lineEnding"`
 	Object              string          `json:"object"`
 	Operation           Operation       `json:"operation"`
+	UpsertByIDAsUpdate  bool            `json:"-"`
 }
 
 // Response is the response to job APIs.
@@ -151,7 +272,7 @@ type Response struct {
 	APIVersion          float32         `json:"apiVersion"`
 	ColumnDelimiter     ColumnDelimiter `json:"columnDelimiter"`
 	ConcurrencyMode     string          `json:"concurrencyMode"`
-	ContentType         string          `json:"contentType"`
+	ContentType         ContentType     `json:"contentType"`
 	ContentURL          string          `json:"contentUrl"`
 	CreatedByID         string          `json:"createdById"`
 	CreatedDate         string          `json:"createdDate"`
@@ -177,22 +298,44 @@ type Info struct {
 	ErrorMessage            string `json:"errorMessage"`
 }
 
-// Job is the bulk job.
+// JobFailedError is returned when a job is in the failed state.
+//
+// Info is the job information, with the error message of why the job failed.
+type JobFailedError struct {
+	Info Info
+}
+
+func (e *JobFailedError) Error() string {
+	if e.Info.ErrorMessage == "" {
+		return fmt.Sprintf("bulk job: job %s failed", e.Info.ID)
+	}
+	return fmt.Sprintf("bulk job: job %s failed: %s", e.Info.ID, e.Info.ErrorMessage)
+}
+
+// Job is the bulk job.  A job is safe for concurrent use, so it can be shared
+// by the goroutine that uploads its data and the one that polls its
+// information.  Its response and upload audit are copied when they are
+// returned.
 type Job struct {
-	session session.ServiceFormatter
-	info    Response
+	session       session.ServiceFormatter
+	correlationID string
+	version       int
+
+	mu    sync.RWMutex
+	info  Response
+	audit *UploadAudit
 }
 
-func (j *Job) create(options Options) error {
+func (j *Job) create(ctx context.Context, options Options) error {
 	err := j.formatOptions(&options)
 	if err != nil {
 		return err
 	}
-	j.info, err = j.createCallout(options)
+	info, err := j.createCallout(ctx, options)
 	if err != nil {
 		return err
 	}
-
+	j.setResponse(info)
 	return nil
 }
 
@@ -200,10 +343,17 @@ func (j *Job) formatOptions(options *Options) error {
 	if options.Operation == "" {
 		return errors.New("bulk job: operation is required")
 	}
+	if options.Operation.IsValid() == false {
+		return fmt.Errorf("bulk job: operation %s is not valid", options.Operation)
+	}
 	if options.Operation == Upsert {
 		if options.ExternalIDFieldName == "" {
 			return errors.New("bulk job: external id field name is required for upsert operation")
 		}
+		if options.UpsertByIDAsUpdate && strings.EqualFold(options.ExternalIDFieldName, "Id") {
+			options.Operation = Update
+			options.ExternalIDFieldName = ""
+		}
 	}
 	if options.Object == "" {
 		return errors.New("bulk job: object is required")
@@ -211,6 +361,9 @@ func (j *Job) formatOptions(options *Options) error {
 	if options.LineEnding == "" {
 		options.LineEnding = Linefeed
 	}
+	if err := checkContentType("", options.ContentType); err != nil {
+		return err
+	}
 	if options.ContentType == "" {
 		options.ContentType = CSV
 	}
@@ -220,13 +373,17 @@ func (j *Job) formatOptions(options *Options) error {
 	return nil
 }
 
-func (j *Job) createCallout(options Options) (Response, error) {
-	url := j.session.ServiceURL() + bulk2Endpoint
+func (j *Job) createCallout(ctx context.Context, options Options) (Response, error) {
+	serviceURL, err := session.ServiceURLContext(ctx, j.session)
+	if err != nil {
+		return Response{}, err
+	}
+	url := serviceURL + bulk2Endpoint
 	body, err := json.Marshal(options)
 	if err != nil {
 		return Response{}, err
 	}
-	request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
 	if err != nil {
 		return Response{}, err
 	}
@@ -244,7 +401,7 @@ func (j *Job) response(request *http.Request) (Response, error) {
 	}
 
 	decoder := json.NewDecoder(response.Body)
-	defer response.Body.Close()
+	defer sfdc.CloseResponse(response)
 
 	if response.StatusCode != http.StatusOK {
 		return Response{}, sfdc.HandleError(response)
@@ -258,14 +415,41 @@ func (j *Job) response(request *http.Request) (Response, error) {
 	return value, nil
 }
 
+// Response returns the job's response from when it was created, with the state
+// that it was last changed to.
+func (j *Job) Response() Response {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.info
+}
+
+func (j *Job) setResponse(info Response) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.info = info
+}
+
 // Info returns the current job information.
 func (j *Job) Info() (Info, error) {
-	return j.fetchInfo(j.info.ID)
+	return j.InfoContext(context.Background())
 }
 
-func (j *Job) fetchInfo(id string) (Info, error) {
-	url := j.session.ServiceURL() + bulk2Endpoint + "/" + id
-	request, err := http.NewRequest(http.MethodGet, url, nil)
+// InfoContext returns the current job information, and can be canceled with the
+// context.
+func (j *Job) InfoContext(ctx context.Context) (Info, error) {
+	ctx, end := j.startSpan(ctx, "bulk.job.info")
+	info, err := j.fetchInfo(ctx, j.Response().ID)
+	end(err)
+	return info, err
+}
+
+func (j *Job) fetchInfo(ctx context.Context, id string) (Info, error) {
+	serviceURL, err := session.ServiceURLContext(ctx, j.session)
+	if err != nil {
+		return Info{}, err
+	}
+	url := serviceURL + bulk2Endpoint + "/" + id
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return Info{}, err
 	}
@@ -281,7 +465,7 @@ func (j *Job) infoResponse(request *http.Request) (Info, error) {
 	if err != nil {
 		return Info{}, err
 	}
-	defer response.Body.Close()
+	defer sfdc.CloseResponse(response)
 
 	if response.StatusCode != http.StatusOK {
 		err := sfdc.HandleError(response)
@@ -297,8 +481,12 @@ func (j *Job) infoResponse(request *http.Request) (Info, error) {
 	return value, nil
 }
 
-func (j *Job) setState(state State) (Response, error) {
-	url := j.session.ServiceURL() + bulk2Endpoint + "/" + j.info.ID
+func (j *Job) setState(ctx context.Context, state State) (Response, error) {
+	serviceURL, err := session.ServiceURLContext(ctx, j.session)
+	if err != nil {
+		return Response{}, err
+	}
+	url := serviceURL + bulk2Endpoint + "/" + j.Response().ID
 	jobState := struct {
 		State string `json:"state"`
 	}{
@@ -308,7 +496,7 @@ func (j *Job) setState(state State) (Response, error) {
 	if err != nil {
 		return Response{}, err
 	}
-	request, err := http.NewRequest(http.MethodPatch, url, bytes.NewReader(body))
+	request, err := http.NewRequestWithContext(ctx, http.MethodPatch, url, bytes.NewReader(body))
 	if err != nil {
 		return Response{}, err
 	}
@@ -316,23 +504,66 @@ func (j *Job) setState(state State) (Response, error) {
 	request.Header.Add("Content-Type", "application/json")
 	j.session.AuthorizationHeader(request)
 
-	return j.response(request)
+	info, err := j.infoResponse(request)
+	if err != nil {
+		return Response{}, err
+	}
+	j.mu.Lock()
+	j.info.State = info.State
+	j.mu.Unlock()
+	if info.State == Failed {
+		return info.Response, j.failed(ctx, info)
+	}
+	return info.Response, nil
 }
 
-// Close will close the current job.
+// failed returns the job failed error of the failed job.  If the information
+// does not have the error message, it is fetched.  If the fetch fails, the
+// information that is known is used.
+func (j *Job) failed(ctx context.Context, info Info) error {
+	if info.ErrorMessage == "" {
+		if fetched, err := j.fetchInfo(ctx, info.ID); err == nil {
+			info = fetched
+		}
+	}
+	return &JobFailedError{
+		Info: info,
+	}
+}
+
+// Close will close the current job.  If the job failed, a JobFailedError is
+// returned with the response.
 func (j *Job) Close() (Response, error) {
-	return j.setState(UpdateComplete)
+	ctx, end := j.startSpan(context.Background(), "bulk.job.close")
+	response, err := j.setState(ctx, UpdateComplete)
+	end(err)
+	return response, err
 }
 
-// Abort will abort the current job.
+// Abort will abort the current job.  If the job failed, a JobFailedError is
+// returned with the response.
 func (j *Job) Abort() (Response, error) {
-	return j.setState(Aborted)
+	ctx, end := j.startSpan(context.Background(), "bulk.job.abort")
+	response, err := j.setState(ctx, Aborted)
+	end(err)
+	return response, err
 }
 
 // Delete will delete the current job.
-func (j *Job) Delete() error {
-	url := j.session.ServiceURL() + bulk2Endpoint + "/" + j.info.ID
-	request, err := http.NewRequest(http.MethodDelete, url, nil)
+func (j *Job) Delete() (err error) {
+	ctx, end := j.startSpan(context.Background(), "bulk.job.delete")
+	defer func() { end(err) }()
+
+	return j.delete(ctx)
+}
+
+func (j *Job) delete(ctx context.Context) error {
+	serviceURL, err := session.ServiceURLContext(ctx, j.session)
+	if err != nil {
+		return err
+	}
+	url := serviceURL + bulk2Endpoint + "/" + j.Response().ID
+	request, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
 	if err != nil {
 		return err
 	}
@@ -342,6 +573,7 @@ func (j *Job) Delete() error {
 	if err != nil {
 		return err
 	}
+	defer sfdc.CloseResponse(response)
 
 	if response.StatusCode != http.StatusNoContent {
 		return errors.New("job error: unable to delete job")
@@ -349,56 +581,143 @@ func (j *Job) Delete() error {
 	return nil
 }
 
-// Upload will upload data to processing.
-func (j *Job) Upload(body io.Reader) error {
-	url := j.session.ServiceURL() + bulk2Endpoint + "/" + j.info.ID + "/batches"
-	request, err := http.NewRequest(http.MethodPut, url, body)
+// Upload will upload data to processing.  The data is uploaded to the job's
+// content URL when it has one.  The options can be used to audit the data that
+// is sent.  A job that is not CSV, like a bulk 1.0 job of the job listing, is a
+// ContentTypeError.
+func (j *Job) Upload(body io.Reader, options ...UploadOption) (err error) {
+	info := j.Response()
+	if err := checkContentType(info.ID, info.ContentType); err != nil {
+		return err
+	}
+	var opts uploadOptions
+	for _, option := range options {
+		option(&opts)
+	}
+	ctx, end := j.startSpan(context.Background(), "bulk.job.upload")
+	defer func() { end(err) }()
+
+	serviceURL, err := session.ServiceURLContext(ctx, j.session)
+	if err != nil {
+		return err
+	}
+	url := serviceURL + bulk2Endpoint + "/" + info.ID + "/batches"
+	if info.ContentURL != "" {
+		url, err = sfdc.RebaseServicePath(j.session.InstanceURL(), info.ContentURL)
+		if err != nil {
+			return err
+		}
+		url, err = session.ServicePathContext(ctx, j.session, url)
+		if err != nil {
+			return err
+		}
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPut, url, body)
 	if err != nil {
 		return err
 	}
 	request.Header.Add("Content-Type", "text/csv")
 	j.session.AuthorizationHeader(request)
 
+	var audit *auditReader
+	if opts.audit != nil {
+		// the content length of the body is kept, but the body can not be
+		// read again without the audit
+		audit = newAuditReader(body, info.ID, opts)
+		request.Body = io.NopCloser(audit)
+		request.GetBody = nil
+	}
+
 	response, err := j.session.Client().Do(request)
+	if audit != nil && audit.err != nil && audit.warning == nil {
+		if err == nil {
+			sfdc.CloseResponse(response)
+		}
+		return audit.err
+	}
 	if err != nil {
 		return err
 	}
-	defer response.Body.Close()
+	defer sfdc.CloseResponse(response)
 
 	if response.StatusCode != http.StatusCreated {
 		return sfdc.HandleError(response)
 	}
+	if audit != nil {
+		result := audit.audit()
+		j.mu.Lock()
+		j.audit = &result
+		j.mu.Unlock()
+	}
 	return nil
 }
 
-// SuccessfulRecords returns the successful records for the job.
-func (j *Job) SuccessfulRecords() ([]SuccessfulRecord, error) {
-	url := j.session.ServiceURL() + bulk2Endpoint + "/" + j.info.ID + "/successfulResults/"
-	request, err := http.NewRequest(http.MethodGet, url, nil)
+// UploadAudit returns the audit of the job's last upload with an audit
+// writer.  It is false when there is no audited upload.
+func (j *Job) UploadAudit() (UploadAudit, bool) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	if j.audit == nil {
+		return UploadAudit{}, false
+	}
+	return *j.audit, true
+}
+
+// SuccessfulRecords returns the successful records for the job.  The options
+// can be used to set the download's context and deadline.  An empty or
+// header-only body is an empty, non-nil slice, so an error is always a
+// download or parse failure.
+func (j *Job) SuccessfulRecords(options ...ResultOption) (_ []SuccessfulRecord, err error) {
+	opts := newResultOptions(options)
+	if err := checkFieldTypes(opts.fieldTypes); err != nil {
+		return nil, err
+	}
+	ctx, cancel := opts.context()
+	defer cancel()
+	defer opts.warnSlow("SuccessfulRecords", j.Response().ID, time.Now())
+	ctx, end := j.startSpan(ctx, "bulk.job.successful_records")
+	defer func() { end(err) }()
+
+	serviceURL, err := session.ServiceURLContext(ctx, j.session)
 	if err != nil {
 		return nil, err
 	}
-	request.Header.Add("Accept", "text/csv")
+	url := serviceURL + bulk2Endpoint + "/" + j.Response().ID + successfulResultsPath
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	accept, err := j.resultMediaType()
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Add("Accept", accept)
 	j.session.AuthorizationHeader(request)
 
-	response, err := j.session.Client().Do(request)
+	response, err := j.doResults(request)
 	if err != nil {
 		return nil, err
 	}
-	defer response.Body.Close()
+	defer sfdc.CloseResponse(response)
 
 	if response.StatusCode != http.StatusOK {
 		return nil, sfdc.HandleError(response)
 	}
 
-	reader := csv.NewReader(response.Body)
-	reader.Comma = j.delimiter()
-
-	var records []SuccessfulRecord
-	fields, err := reader.Read()
+	reader, fields, err := j.resultReader(response, opts.strictRowLength)
+	if err == io.EOF {
+		return []SuccessfulRecord{}, nil
+	}
 	if err != nil {
 		return nil, err
 	}
+	createdPosition := j.headerPosition(sfCreated, fields)
+	idPosition := j.headerPosition(sfID, fields)
+	if createdPosition < 0 || idPosition < 0 {
+		return nil, fmt.Errorf("bulk job: successful results header must have %s and %s", sfCreated, sfID)
+	}
+
+	records := []SuccessfulRecord{}
 	for {
 		values, err := reader.Read()
 		if err == io.EOF {
@@ -408,47 +727,78 @@ func (j *Job) SuccessfulRecords() ([]SuccessfulRecord, error) {
 			return nil, err
 		}
 		var record SuccessfulRecord
-		created, err := strconv.ParseBool(values[j.headerPosition(sfCreated, fields)])
+		created, err := strconv.ParseBool(values[createdPosition])
 		if err != nil {
 			return nil, err
 		}
 		record.Created = created
-		record.ID = values[j.headerPosition(sfID, fields)]
+		record.ID = values[idPosition]
 		record.Fields = j.record(fields[2:], values[2:])
+		record.Values, err = opts.values(len(records)+1, record.Fields)
+		if err != nil {
+			return nil, err
+		}
 		records = append(records, record)
 	}
 
 	return records, nil
 }
 
-// FailedRecords returns the failed records for the job.
-func (j *Job) FailedRecords() ([]FailedRecord, error) {
-	url := j.session.ServiceURL() + bulk2Endpoint + "/" + j.info.ID + "/failedResults/"
-	request, err := http.NewRequest(http.MethodGet, url, nil)
+// FailedRecords returns the failed records for the job.  The options
+// can be used to set the download's context and deadline.  An empty or
+// header-only body is an empty, non-nil slice, so an error is always a
+// download or parse failure.
+func (j *Job) FailedRecords(options ...ResultOption) (_ []FailedRecord, err error) {
+	opts := newResultOptions(options)
+	if err := checkFieldTypes(opts.fieldTypes); err != nil {
+		return nil, err
+	}
+	ctx, cancel := opts.context()
+	defer cancel()
+	defer opts.warnSlow("FailedRecords", j.Response().ID, time.Now())
+	ctx, end := j.startSpan(ctx, "bulk.job.failed_records")
+	defer func() { end(err) }()
+
+	serviceURL, err := session.ServiceURLContext(ctx, j.session)
 	if err != nil {
 		return nil, err
 	}
-	request.Header.Add("Accept", "text/csv")
+	url := serviceURL + bulk2Endpoint + "/" + j.Response().ID + failedResultsPath
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	accept, err := j.resultMediaType()
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Add("Accept", accept)
 	j.session.AuthorizationHeader(request)
 
-	response, err := j.session.Client().Do(request)
+	response, err := j.doResults(request)
 	if err != nil {
 		return nil, err
 	}
-	defer response.Body.Close()
+	defer sfdc.CloseResponse(response)
 
 	if response.StatusCode != http.StatusOK {
 		return nil, sfdc.HandleError(response)
 	}
 
-	reader := csv.NewReader(response.Body)
-	reader.Comma = j.delimiter()
-
-	var records []FailedRecord
-	fields, err := reader.Read()
+	reader, fields, err := j.resultReader(response, opts.strictRowLength)
+	if err == io.EOF {
+		return []FailedRecord{}, nil
+	}
 	if err != nil {
 		return nil, err
 	}
+	errorPosition := j.headerPosition(sfError, fields)
+	idPosition := j.headerPosition(sfID, fields)
+	if errorPosition < 0 || idPosition < 0 {
+		return nil, fmt.Errorf("bulk job: failed results header must have %s and %s", sfError, sfID)
+	}
+
+	records := []FailedRecord{}
 	for {
 		values, err := reader.Read()
 		if err == io.EOF {
@@ -458,43 +808,69 @@ func (j *Job) FailedRecords() ([]FailedRecord, error) {
 			return nil, err
 		}
 		var record FailedRecord
-		record.Error = values[j.headerPosition(sfError, fields)]
-		record.ID = values[j.headerPosition(sfID, fields)]
+		record.Error = values[errorPosition]
+		record.ID = values[idPosition]
 		record.Fields = j.record(fields[2:], values[2:])
+		record.Values, err = opts.values(len(records)+1, record.Fields)
+		if err != nil {
+			return nil, err
+		}
 		records = append(records, record)
 	}
 
 	return records, nil
 }
 
-// UnprocessedRecords returns the unprocessed records for the job.
-func (j *Job) UnprocessedRecords() ([]UnprocessedRecord, error) {
-	url := j.session.ServiceURL() + bulk2Endpoint + "/" + j.info.ID + "/unprocessedrecords/"
-	request, err := http.NewRequest(http.MethodGet, url, nil)
+// UnprocessedRecords returns the unprocessed records for the job.  The options
+// can be used to set the download's context and deadline.  An empty or
+// header-only body is an empty, non-nil slice, so an error is always a
+// download or parse failure.
+func (j *Job) UnprocessedRecords(options ...ResultOption) (_ []UnprocessedRecord, err error) {
+	opts := newResultOptions(options)
+	if err := checkFieldTypes(opts.fieldTypes); err != nil {
+		return nil, err
+	}
+	ctx, cancel := opts.context()
+	defer cancel()
+	defer opts.warnSlow("UnprocessedRecords", j.Response().ID, time.Now())
+	ctx, end := j.startSpan(ctx, "bulk.job.unprocessed_records")
+	defer func() { end(err) }()
+
+	serviceURL, err := session.ServiceURLContext(ctx, j.session)
+	if err != nil {
+		return nil, err
+	}
+	url := serviceURL + bulk2Endpoint + "/" + j.Response().ID + unprocessedRecordsPath
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
 	}
-	request.Header.Add("Accept", "text/csv")
+	accept, err := j.resultMediaType()
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Add("Accept", accept)
 	j.session.AuthorizationHeader(request)
 
-	response, err := j.session.Client().Do(request)
+	response, err := j.doResults(request)
 	if err != nil {
 		return nil, err
 	}
-	defer response.Body.Close()
+	defer sfdc.CloseResponse(response)
 
 	if response.StatusCode != http.StatusOK {
 		return nil, sfdc.HandleError(response)
 	}
 
-	reader := csv.NewReader(response.Body)
-	reader.Comma = j.delimiter()
-
-	var records []UnprocessedRecord
-	fields, err := reader.Read()
+	reader, fields, err := j.resultReader(response, opts.strictRowLength)
+	if err == io.EOF {
+		return []UnprocessedRecord{}, nil
+	}
 	if err != nil {
 		return nil, err
 	}
+
+	records := []UnprocessedRecord{}
 	for {
 		values, err := reader.Read()
 		if err == io.EOF {
@@ -505,12 +881,45 @@ func (j *Job) UnprocessedRecords() ([]UnprocessedRecord, error) {
 		}
 		var record UnprocessedRecord
 		record.Fields = j.record(fields, values)
+		record.Values, err = opts.values(len(records)+1, record.Fields)
+		if err != nil {
+			return nil, err
+		}
 		records = append(records, record)
 	}
 
 	return records, nil
 }
 
+// CorrelationID returns the correlation ID that is sent with the job's API calls.
+func (j *Job) CorrelationID() string {
+	return j.correlationID
+}
+
+// context returns the context with the job's correlation ID and API version,
+// unless the context already has them.
+func (j *Job) context(ctx context.Context) context.Context {
+	if _, ok := sfdc.CorrelationID(ctx); ok == false && j.correlationID != "" {
+		ctx = sfdc.WithCorrelationID(ctx, j.correlationID)
+	}
+	if _, ok := sfdc.APIVersion(ctx); ok == false && j.version != 0 {
+		ctx = sfdc.WithAPIVersion(ctx, j.version)
+	}
+	return ctx
+}
+
+func (j *Job) startSpan(ctx context.Context, op string) (context.Context, func(error)) {
+	ctx = j.context(ctx)
+	return session.StartSpan(ctx, j.session, op, func() map[string]interface{} {
+		info := j.Response()
+		return map[string]interface{}{
+			"job.id":    info.ID,
+			"object":    info.Object,
+			"operation": string(info.Operation),
+		}
+	})
+}
+
 func (j *Job) headerPosition(column string, header []string) int {
 	for idx, col := range header {
 		if col == column {
@@ -526,16 +935,66 @@ func (j *Job) fields(header []string, offset int) []string {
 	return fields
 }
 
+// resultReader returns the row reader of the results and a copy of the header.
+// The reader reuses the record slice, so the slice returned by Read is only
+// valid until the next Read.  The values are strings that are not shared with
+// the slice, so they can be retained, like in the record maps.  The results of
+// a job that is not CSV or JSON are a ContentTypeError, and a response whose
+// content type is not the one of the job is an UnsupportedContentTypeError.
+func (j *Job) resultReader(response *http.Response, strict bool) (*rowReader, []string, error) {
+	if err := j.checkResultContentType(response); err != nil {
+		return nil, nil, err
+	}
+	info := j.Response()
+	if info.ContentType == JSON {
+		fields, rows, err := jsonRows(response.Body)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &rowReader{
+			rows:   rows,
+			fields: len(fields),
+			strict: strict,
+		}, fields, nil
+	}
+	reader := csv.NewReader(response.Body)
+	reader.Comma = info.ColumnDelimiter.Rune()
+	reader.ReuseRecord = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, err
+	}
+	fields := make([]string, len(header))
+	copy(fields, header)
+	return &rowReader{
+		reader: reader,
+		fields: len(fields),
+		strict: strict,
+	}, fields, nil
+}
+
 func (j *Job) record(fields, values []string) map[string]string {
-	record := make(map[string]string)
+	record := make(map[string]string, len(fields))
 	for idx, field := range fields {
-		record[field] = values[idx]
+		if idx < len(values) {
+			record[field] = values[idx]
+		} else {
+			record[field] = ""
+		}
 	}
 	return record
 }
 
 func (j *Job) delimiter() rune {
-	switch ColumnDelimiter(j.info.ColumnDelimiter) {
+	return j.Response().ColumnDelimiter.Rune()
+}
+
+// Rune returns the delimiter's character.  An empty or unknown delimiter is a
+// comma.
+func (d ColumnDelimiter) Rune() rune {
+	switch d {
 	case Tab:
 		return '\t'
 	case SemiColon:
@@ -1,13 +1,18 @@
 package bulk
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"io"
-	"io/ioutil"
 	"net/http"
 	"reflect"
+	"runtime"
 	"strings"
+	"sync"
 	"testing"
 
+	"github.com/namely/go-sfdc/v3/bulk/results"
 	"github.com/namely/go-sfdc/v3/session"
 )
 
@@ -69,6 +74,47 @@ func TestJob_formatOptions(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:   "upsert by id",
+			fields: fields{},
+			args: args{
+				options: &Options{
+					ExternalIDFieldName: "Id",
+					Object:              "Account",
+					Operation:           Upsert,
+				},
+			},
+			want: &Options{
+				ColumnDelimiter:     Comma,
+				ContentType:         CSV,
+				ExternalIDFieldName: "Id",
+				LineEnding:          Linefeed,
+				Object:              "Account",
+				Operation:           Upsert,
+			},
+			wantErr: false,
+		},
+		{
+			name:   "upsert by id as update",
+			fields: fields{},
+			args: args{
+				options: &Options{
+					ExternalIDFieldName: "Id",
+					Object:              "Account",
+					Operation:           Upsert,
+					UpsertByIDAsUpdate:  true,
+				},
+			},
+			want: &Options{
+				ColumnDelimiter:    Comma,
+				ContentType:        CSV,
+				LineEnding:         Linefeed,
+				Object:             "Account",
+				Operation:          Update,
+				UpsertByIDAsUpdate: true,
+			},
+			wantErr: false,
+		},
 		{
 			name:   "no object",
 			fields: fields{},
@@ -93,6 +139,32 @@ func TestJob_formatOptions(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 		},
+		{
+			name:   "zip content type",
+			fields: fields{},
+			args: args{
+				options: &Options{
+					ContentType: ZipCSV,
+					Object:      "Attachment",
+					Operation:   Insert,
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:   "unknown content type",
+			fields: fields{},
+			args: args{
+				options: &Options{
+					ContentType: "PARQUET",
+					Object:      "Account",
+					Operation:   Insert,
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
 		{
 			name:   "no external fields",
 			fields: fields{},
@@ -339,7 +411,7 @@ func TestJob_response(t *testing.T) {
 						return &http.Response{
 							StatusCode: http.StatusOK,
 							Status:     "Good",
-							Body:       ioutil.NopCloser(strings.NewReader(resp)),
+							Body:       io.NopCloser(strings.NewReader(resp)),
 							Header:     make(http.Header),
 						}
 
@@ -383,7 +455,7 @@ func TestJob_response(t *testing.T) {
 						return &http.Response{
 							StatusCode: http.StatusBadRequest,
 							Status:     "Bad",
-							Body:       ioutil.NopCloser(strings.NewReader(resp)),
+							Body:       io.NopCloser(strings.NewReader(resp)),
 							Header:     make(http.Header),
 						}
 					}),
@@ -439,7 +511,7 @@ func TestJob_createCallout(t *testing.T) {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "Invalid URL",
-								Body:       ioutil.NopCloser(strings.NewReader(req.URL.String())),
+								Body:       io.NopCloser(strings.NewReader(req.URL.String())),
 								Header:     make(http.Header),
 							}
 						}
@@ -464,7 +536,7 @@ func TestJob_createCallout(t *testing.T) {
 						return &http.Response{
 							StatusCode: http.StatusOK,
 							Status:     "Good",
-							Body:       ioutil.NopCloser(strings.NewReader(resp)),
+							Body:       io.NopCloser(strings.NewReader(resp)),
 							Header:     make(http.Header),
 						}
 
@@ -507,7 +579,7 @@ func TestJob_createCallout(t *testing.T) {
 				session: tt.fields.session,
 				info:    tt.fields.info,
 			}
-			got, err := j.createCallout(tt.args.options)
+			got, err := j.createCallout(context.Background(), tt.args.options)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Job.createCallout() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -543,7 +615,7 @@ func TestJob_create(t *testing.T) {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "Invalid URL",
-								Body:       ioutil.NopCloser(strings.NewReader(req.URL.String())),
+								Body:       io.NopCloser(strings.NewReader(req.URL.String())),
 								Header:     make(http.Header),
 							}
 						}
@@ -568,7 +640,7 @@ func TestJob_create(t *testing.T) {
 						return &http.Response{
 							StatusCode: http.StatusOK,
 							Status:     "Good",
-							Body:       ioutil.NopCloser(strings.NewReader(resp)),
+							Body:       io.NopCloser(strings.NewReader(resp)),
 							Header:     make(http.Header),
 						}
 
@@ -594,7 +666,7 @@ func TestJob_create(t *testing.T) {
 				session: tt.fields.session,
 				info:    tt.fields.info,
 			}
-			if err := j.create(tt.args.options); (err != nil) != tt.wantErr {
+			if err := j.create(context.Background(), tt.args.options); (err != nil) != tt.wantErr {
 				t.Errorf("Job.create() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -629,7 +701,7 @@ func TestJob_setState(t *testing.T) {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "Invalid URL",
-								Body:       ioutil.NopCloser(strings.NewReader(req.URL.String())),
+								Body:       io.NopCloser(strings.NewReader(req.URL.String())),
 								Header:     make(http.Header),
 							}
 						}
@@ -638,7 +710,7 @@ func TestJob_setState(t *testing.T) {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "Invalid Method",
-								Body:       ioutil.NopCloser(strings.NewReader(req.Method)),
+								Body:       io.NopCloser(strings.NewReader(req.Method)),
 								Header:     make(http.Header),
 							}
 						}
@@ -662,7 +734,7 @@ func TestJob_setState(t *testing.T) {
 						return &http.Response{
 							StatusCode: http.StatusOK,
 							Status:     "Good",
-							Body:       ioutil.NopCloser(strings.NewReader(resp)),
+							Body:       io.NopCloser(strings.NewReader(resp)),
 							Header:     make(http.Header),
 						}
 
@@ -704,7 +776,7 @@ func TestJob_setState(t *testing.T) {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "Invalid URL",
-								Body:       ioutil.NopCloser(strings.NewReader(req.URL.String())),
+								Body:       io.NopCloser(strings.NewReader(req.URL.String())),
 								Header:     make(http.Header),
 							}
 						}
@@ -713,7 +785,7 @@ func TestJob_setState(t *testing.T) {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "Invalid Method",
-								Body:       ioutil.NopCloser(strings.NewReader(req.Method)),
+								Body:       io.NopCloser(strings.NewReader(req.Method)),
 								Header:     make(http.Header),
 							}
 						}
@@ -727,7 +799,7 @@ func TestJob_setState(t *testing.T) {
 						return &http.Response{
 							StatusCode: http.StatusBadRequest,
 							Status:     "Bad",
-							Body:       ioutil.NopCloser(strings.NewReader(resp)),
+							Body:       io.NopCloser(strings.NewReader(resp)),
 							Header:     make(http.Header),
 						}
 					}),
@@ -746,7 +818,7 @@ func TestJob_setState(t *testing.T) {
 				session: tt.fields.session,
 				info:    tt.fields.info,
 			}
-			got, err := j.setState(tt.args.state)
+			got, err := j.setState(context.Background(), tt.args.state)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Job.setState() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -805,7 +877,7 @@ func TestJob_infoResponse(t *testing.T) {
 						return &http.Response{
 							StatusCode: http.StatusOK,
 							Status:     "Good",
-							Body:       ioutil.NopCloser(strings.NewReader(resp)),
+							Body:       io.NopCloser(strings.NewReader(resp)),
 							Header:     make(http.Header),
 						}
 
@@ -858,7 +930,7 @@ func TestJob_infoResponse(t *testing.T) {
 						return &http.Response{
 							StatusCode: http.StatusBadRequest,
 							Status:     "Bad",
-							Body:       ioutil.NopCloser(strings.NewReader(resp)),
+							Body:       io.NopCloser(strings.NewReader(resp)),
 							Header:     make(http.Header),
 						}
 					}),
@@ -913,7 +985,7 @@ func TestJob_Info(t *testing.T) {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "Invalid URL",
-								Body:       ioutil.NopCloser(strings.NewReader(req.URL.String())),
+								Body:       io.NopCloser(strings.NewReader(req.URL.String())),
 								Header:     make(http.Header),
 							}
 						}
@@ -922,7 +994,7 @@ func TestJob_Info(t *testing.T) {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "Invalid Method",
-								Body:       ioutil.NopCloser(strings.NewReader(req.Method)),
+								Body:       io.NopCloser(strings.NewReader(req.Method)),
 								Header:     make(http.Header),
 							}
 						}
@@ -954,7 +1026,7 @@ func TestJob_Info(t *testing.T) {
 						return &http.Response{
 							StatusCode: http.StatusOK,
 							Status:     "Good",
-							Body:       ioutil.NopCloser(strings.NewReader(resp)),
+							Body:       io.NopCloser(strings.NewReader(resp)),
 							Header:     make(http.Header),
 						}
 
@@ -1031,7 +1103,7 @@ func TestJob_Delete(t *testing.T) {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "Invalid URL",
-								Body:       ioutil.NopCloser(strings.NewReader(req.URL.String())),
+								Body:       io.NopCloser(strings.NewReader(req.URL.String())),
 								Header:     make(http.Header),
 							}
 						}
@@ -1040,7 +1112,7 @@ func TestJob_Delete(t *testing.T) {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "Invalid Method",
-								Body:       ioutil.NopCloser(strings.NewReader(req.Method)),
+								Body:       io.NopCloser(strings.NewReader(req.Method)),
 								Header:     make(http.Header),
 							}
 						}
@@ -1048,7 +1120,7 @@ func TestJob_Delete(t *testing.T) {
 						return &http.Response{
 							StatusCode: http.StatusNoContent,
 							Status:     "Good",
-							Body:       ioutil.NopCloser(strings.NewReader("")),
+							Body:       io.NopCloser(strings.NewReader("")),
 							Header:     make(http.Header),
 						}
 
@@ -1070,7 +1142,7 @@ func TestJob_Delete(t *testing.T) {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "Invalid URL",
-								Body:       ioutil.NopCloser(strings.NewReader(req.URL.String())),
+								Body:       io.NopCloser(strings.NewReader(req.URL.String())),
 								Header:     make(http.Header),
 							}
 						}
@@ -1079,7 +1151,7 @@ func TestJob_Delete(t *testing.T) {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "Invalid Method",
-								Body:       ioutil.NopCloser(strings.NewReader(req.Method)),
+								Body:       io.NopCloser(strings.NewReader(req.Method)),
 								Header:     make(http.Header),
 							}
 						}
@@ -1087,7 +1159,7 @@ func TestJob_Delete(t *testing.T) {
 						return &http.Response{
 							StatusCode: http.StatusBadRequest,
 							Status:     "Good",
-							Body:       ioutil.NopCloser(strings.NewReader("")),
+							Body:       io.NopCloser(strings.NewReader("")),
 							Header:     make(http.Header),
 						}
 
@@ -1137,7 +1209,7 @@ func TestJob_Upload(t *testing.T) {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "Invalid URL",
-								Body:       ioutil.NopCloser(strings.NewReader(req.URL.String())),
+								Body:       io.NopCloser(strings.NewReader(req.URL.String())),
 								Header:     make(http.Header),
 							}
 						}
@@ -1146,7 +1218,41 @@ func TestJob_Upload(t *testing.T) {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "Invalid Method",
-								Body:       ioutil.NopCloser(strings.NewReader(req.Method)),
+								Body:       io.NopCloser(strings.NewReader(req.Method)),
+								Header:     make(http.Header),
+							}
+						}
+
+						return &http.Response{
+							StatusCode: http.StatusCreated,
+							Status:     "Good",
+							Body:       io.NopCloser(strings.NewReader("")),
+							Header:     make(http.Header),
+						}
+
+					}),
+				},
+			},
+			args: args{
+				body: strings.NewReader("some reader"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "Content URL",
+			fields: fields{
+				info: Response{
+					ID:         "1234",
+					ContentURL: "services/data/v44.0/jobs/ingest/1234/batches",
+				},
+				session: &mockSessionFormatter{
+					url: "https://test.salesforce.com",
+					client: mockHTTPClient(func(req *http.Request) *http.Response {
+						if req.URL.String() != "https://test.salesforce.com/services/data/v44.0/jobs/ingest/1234/batches" {
+							return &http.Response{
+								StatusCode: 500,
+								Status:     "Invalid URL",
+								Body:       io.NopCloser(strings.NewReader(req.URL.String())),
 								Header:     make(http.Header),
 							}
 						}
@@ -1154,7 +1260,7 @@ func TestJob_Upload(t *testing.T) {
 						return &http.Response{
 							StatusCode: http.StatusCreated,
 							Status:     "Good",
-							Body:       ioutil.NopCloser(strings.NewReader("")),
+							Body:       io.NopCloser(strings.NewReader("")),
 							Header:     make(http.Header),
 						}
 
@@ -1166,6 +1272,22 @@ func TestJob_Upload(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Malformed Content URL",
+			fields: fields{
+				info: Response{
+					ID:         "1234",
+					ContentURL: "jobs/ingest/1234/batches",
+				},
+				session: &mockSessionFormatter{
+					url: "https://test.salesforce.com",
+				},
+			},
+			args: args{
+				body: strings.NewReader("some reader"),
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1202,11 +1324,11 @@ func TestJob_SuccessfulRecords(t *testing.T) {
 				session: &mockSessionFormatter{
 					url: "https://test.salesforce.com",
 					client: mockHTTPClient(func(req *http.Request) *http.Response {
-						if req.URL.String() != "https://test.salesforce.com/jobs/ingest/1234/successfulResults/" {
+						if req.URL.String() != "https://test.salesforce.com/jobs/ingest/1234/successfulResults" {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "Invalid URL",
-								Body:       ioutil.NopCloser(strings.NewReader(req.URL.String())),
+								Body:       io.NopCloser(strings.NewReader(req.URL.String())),
 								Header:     make(http.Header),
 							}
 						}
@@ -1215,7 +1337,7 @@ func TestJob_SuccessfulRecords(t *testing.T) {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "Invalid Method",
-								Body:       ioutil.NopCloser(strings.NewReader(req.Method)),
+								Body:       io.NopCloser(strings.NewReader(req.Method)),
 								Header:     make(http.Header),
 							}
 						}
@@ -1224,7 +1346,7 @@ func TestJob_SuccessfulRecords(t *testing.T) {
 						return &http.Response{
 							StatusCode: http.StatusOK,
 							Status:     "Good",
-							Body:       ioutil.NopCloser(strings.NewReader(resp)),
+							Body:       io.NopCloser(strings.NewReader(resp)),
 							Header:     make(http.Header),
 						}
 
@@ -1302,11 +1424,11 @@ func TestJob_FailedRecords(t *testing.T) {
 				session: &mockSessionFormatter{
 					url: "https://test.salesforce.com",
 					client: mockHTTPClient(func(req *http.Request) *http.Response {
-						if req.URL.String() != "https://test.salesforce.com/jobs/ingest/1234/failedResults/" {
+						if req.URL.String() != "https://test.salesforce.com/jobs/ingest/1234/failedResults" {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "Invalid URL",
-								Body:       ioutil.NopCloser(strings.NewReader(req.URL.String())),
+								Body:       io.NopCloser(strings.NewReader(req.URL.String())),
 								Header:     make(http.Header),
 							}
 						}
@@ -1315,7 +1437,7 @@ func TestJob_FailedRecords(t *testing.T) {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "Invalid Method",
-								Body:       ioutil.NopCloser(strings.NewReader(req.Method)),
+								Body:       io.NopCloser(strings.NewReader(req.Method)),
 								Header:     make(http.Header),
 							}
 						}
@@ -1324,7 +1446,7 @@ func TestJob_FailedRecords(t *testing.T) {
 						return &http.Response{
 							StatusCode: http.StatusOK,
 							Status:     "Good",
-							Body:       ioutil.NopCloser(strings.NewReader(resp)),
+							Body:       io.NopCloser(strings.NewReader(resp)),
 							Header:     make(http.Header),
 						}
 
@@ -1400,11 +1522,11 @@ func TestJob_UnprocessedRecords(t *testing.T) {
 				session: &mockSessionFormatter{
 					url: "https://test.salesforce.com",
 					client: mockHTTPClient(func(req *http.Request) *http.Response {
-						if req.URL.String() != "https://test.salesforce.com/jobs/ingest/1234/unprocessedrecords/" {
+						if req.URL.String() != "https://test.salesforce.com/jobs/ingest/1234/unprocessedrecords" {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "Invalid URL",
-								Body:       ioutil.NopCloser(strings.NewReader(req.URL.String())),
+								Body:       io.NopCloser(strings.NewReader(req.URL.String())),
 								Header:     make(http.Header),
 							}
 						}
@@ -1413,7 +1535,7 @@ func TestJob_UnprocessedRecords(t *testing.T) {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "Invalid Method",
-								Body:       ioutil.NopCloser(strings.NewReader(req.Method)),
+								Body:       io.NopCloser(strings.NewReader(req.Method)),
 								Header:     make(http.Header),
 							}
 						}
@@ -1422,7 +1544,7 @@ func TestJob_UnprocessedRecords(t *testing.T) {
 						return &http.Response{
 							StatusCode: http.StatusOK,
 							Status:     "Good",
-							Body:       ioutil.NopCloser(strings.NewReader(resp)),
+							Body:       io.NopCloser(strings.NewReader(resp)),
 							Header:     make(http.Header),
 						}
 
@@ -1465,3 +1587,395 @@ func TestJob_UnprocessedRecords(t *testing.T) {
 		})
 	}
 }
+
+func TestInfo_decode(t *testing.T) {
+	// job info payload as returned by the Salesforce org
+	payload := `{
+		"id": "7505fEXAMPLE4C2AAM",
+		"operation": "upsert",
+		"object": "Account",
+		"createdById": "0055fEXAMPLEtG4AAI",
+		"createdDate": "2022-01-02T21:33:43.000+0000",
+		"systemModstamp": "2022-01-02T21:33:51.000+0000",
+		"state": "JobComplete",
+		"externalIdFieldName": "External_Id__c",
+		"concurrencyMode": "Parallel",
+		"contentType": "CSV",
+		"apiVersion": 53.0,
+		"jobType": "V2Ingest",
+		"contentUrl": "services/data/v53.0/jobs/ingest/7505fEXAMPLE4C2AAM/batches",
+		"lineEnding": "LF",
+		"columnDelimiter": "COMMA",
+		"numberRecordsProcessed": 12,
+		"numberRecordsFailed": 2,
+		"retries": 1,
+		"totalProcessingTime": 367,
+		"apiActiveProcessingTime": 231,
+		"apexProcessingTime": 19,
+		"errorMessage": "InvalidBatch : Field name not found : Bogus__c"
+	}`
+	want := Info{
+		Response: Response{
+			APIVersion:          53.0,
+			ColumnDelimiter:     Comma,
+			ConcurrencyMode:     "Parallel",
+			ContentType:         "CSV",
+			ContentURL:          "services/data/v53.0/jobs/ingest/7505fEXAMPLE4C2AAM/batches",
+			CreatedByID:         "0055fEXAMPLEtG4AAI",
+			CreatedDate:         "2022-01-02T21:33:43.000+0000",
+			ExternalIDFieldName: "External_Id__c",
+			ID:                  "7505fEXAMPLE4C2AAM",
+			JobType:             V2Ingest,
+			LineEnding:          Linefeed,
+			Object:              "Account",
+			Operation:           Upsert,
+			State:               JobComplete,
+			SystemModstamp:      "2022-01-02T21:33:51.000+0000",
+		},
+		ApexProcessingTime:      19,
+		APIActiveProcessingTime: 231,
+		NumberRecordsFailed:     2,
+		NumberRecordsProcessed:  12,
+		Retries:                 1,
+		TotalProcessingTime:     367,
+		ErrorMessage:            "InvalidBatch : Field name not found : Bogus__c",
+	}
+
+	var got Info
+	if err := json.Unmarshal([]byte(payload), &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Info = %+v, want %+v", got, want)
+	}
+
+	// every field of the payload must be populated
+	assertPopulated(t, reflect.ValueOf(got))
+}
+
+func assertPopulated(t *testing.T, value reflect.Value) {
+	t.Helper()
+	for idx := 0; idx < value.NumField(); idx++ {
+		field := value.Field(idx)
+		name := value.Type().Field(idx).Name
+		if field.Kind() == reflect.Struct {
+			assertPopulated(t, field)
+			continue
+		}
+		if field.IsZero() {
+			t.Errorf("%s was not decoded", name)
+		}
+	}
+}
+
+func TestRecord_AsOutcome(t *testing.T) {
+	successful := SuccessfulRecord{
+		Created: true,
+		JobRecord: JobRecord{
+			ID: "001D000000ISUr3IAH",
+			UnprocessedRecord: UnprocessedRecord{
+				Fields: map[string]string{
+					"Name": "Acme",
+				},
+			},
+		},
+	}
+	want := results.RecordOutcome{
+		ID:      "001D000000ISUr3IAH",
+		Created: true,
+		Success: true,
+		Fields: map[string]string{
+			"Name": "Acme",
+		},
+	}
+	if got := successful.AsOutcome(); reflect.DeepEqual(got, want) == false {
+		t.Errorf("SuccessfulRecord.AsOutcome() = %+v, want %+v", got, want)
+	}
+
+	failed := FailedRecord{
+		Error: "REQUIRED_FIELD_MISSING:Required fields are missing: [Name]:Name --",
+		JobRecord: JobRecord{
+			UnprocessedRecord: UnprocessedRecord{
+				Fields: map[string]string{
+					"Name": "",
+				},
+			},
+		},
+	}
+	want = results.RecordOutcome{
+		Errors: []results.Error{
+			{
+				StatusCode: "REQUIRED_FIELD_MISSING",
+				Message:    "Required fields are missing: [Name]:Name --",
+			},
+		},
+		Fields: map[string]string{
+			"Name": "",
+		},
+	}
+	got := failed.AsOutcome()
+	if reflect.DeepEqual(got, want) == false {
+		t.Errorf("FailedRecord.AsOutcome() = %+v, want %+v", got, want)
+	}
+	if got.ErrorMessage() != failed.Error {
+		t.Errorf("RecordOutcome.ErrorMessage() = %v, want %v", got.ErrorMessage(), failed.Error)
+	}
+
+	got.Fields["Name"] = "changed"
+	if failed.Fields["Name"] != "" {
+		t.Errorf("FailedRecord.AsOutcome() shares the fields with the record")
+	}
+}
+
+func TestJob_Close_failed(t *testing.T) {
+	tests := []struct {
+		name        string
+		patch       string
+		get         string
+		wantMessage string
+		wantGets    int
+	}{
+		{
+			name:        "fetched",
+			patch:       `{"id":"1234","state":"Failed"}`,
+			get:         `{"id":"1234","state":"Failed","numberRecordsFailed":2,"errorMessage":"InvalidBatch : Field name not found : Bogus__c"}`,
+			wantMessage: "InvalidBatch : Field name not found : Bogus__c",
+			wantGets:    1,
+		},
+		{
+			name:        "in response",
+			patch:       `{"id":"1234","state":"Failed","errorMessage":"InvalidBatch : Field name not found : Bogus__c"}`,
+			wantMessage: "InvalidBatch : Field name not found : Bogus__c",
+		},
+		{
+			name:     "fetch fails",
+			patch:    `{"id":"1234","state":"Failed"}`,
+			get:      `[{"errorCode":"NOT_FOUND","message":"Job not found"}]`,
+			wantGets: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gets := 0
+			j := &Job{
+				info: Response{
+					ID: "1234",
+				},
+				session: &mockSessionFormatter{
+					url: "https://test.salesforce.com",
+					client: mockHTTPClient(func(req *http.Request) *http.Response {
+						body := tt.patch
+						statusCode := http.StatusOK
+						if req.Method == http.MethodGet {
+							gets++
+							body = tt.get
+							if strings.HasPrefix(body, "[") {
+								statusCode = http.StatusNotFound
+							}
+						}
+						return &http.Response{
+							StatusCode: statusCode,
+							Status:     http.StatusText(statusCode),
+							Body:       io.NopCloser(strings.NewReader(body)),
+							Header:     make(http.Header),
+						}
+					}),
+				},
+			}
+
+			response, err := j.Close()
+			var failedErr *JobFailedError
+			if errors.As(err, &failedErr) == false {
+				t.Fatalf("Job.Close() error = %v, want JobFailedError", err)
+			}
+			if response.State != Failed {
+				t.Errorf("Job.Close() state = %v, want %v", response.State, Failed)
+			}
+			if failedErr.Info.ErrorMessage != tt.wantMessage {
+				t.Errorf("JobFailedError.Info.ErrorMessage = %v, want %v", failedErr.Info.ErrorMessage, tt.wantMessage)
+			}
+			if tt.wantMessage != "" && strings.Contains(err.Error(), tt.wantMessage) == false {
+				t.Errorf("Job.Close() error = %v, want %v", err, tt.wantMessage)
+			}
+			if gets != tt.wantGets {
+				t.Errorf("Job.Close() fetched info %d times, want %d", gets, tt.wantGets)
+			}
+		})
+	}
+}
+
+func TestJob_Abort_notFailed(t *testing.T) {
+	j := &Job{
+		info: Response{
+			ID: "1234",
+		},
+		session: &mockSessionFormatter{
+			url: "https://test.salesforce.com",
+			client: mockHTTPClient(func(req *http.Request) *http.Response {
+				if req.Method != http.MethodPatch {
+					t.Errorf("Job.Abort() method = %v, want %v", req.Method, http.MethodPatch)
+				}
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Status:     "200 OK",
+					Body:       io.NopCloser(strings.NewReader(`{"id":"1234","state":"Aborted"}`)),
+					Header:     make(http.Header),
+				}
+			}),
+		},
+	}
+
+	response, err := j.Abort()
+	if err != nil {
+		t.Fatalf("Job.Abort() error = %v", err)
+	}
+	if response.State != Aborted {
+		t.Errorf("Job.Abort() state = %v, want %v", response.State, Aborted)
+	}
+}
+
+func TestJob_concurrentUse(t *testing.T) {
+	j := &Job{
+		info: Response{
+			ID:    "1234",
+			State: Open,
+		},
+		session: &mockSessionFormatter{
+			url: "https://test.salesforce.com",
+			client: mockHTTPClient(func(req *http.Request) *http.Response {
+				switch req.Method {
+				case http.MethodPut:
+					io.Copy(io.Discard, req.Body)
+					return &http.Response{
+						StatusCode: http.StatusCreated,
+						Body:       io.NopCloser(strings.NewReader("")),
+						Header:     make(http.Header),
+					}
+				case http.MethodPatch:
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`{"id":"1234","state":"UploadComplete"}`)),
+						Header:     make(http.Header),
+					}
+				default:
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`{"id":"1234","state":"Open"}`)),
+						Header:     make(http.Header),
+					}
+				}
+			}),
+		},
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	poll := func(fn func()) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				fn()
+			}
+		}()
+	}
+	poll(func() {
+		if _, err := j.Info(); err != nil {
+			t.Errorf("Job.Info() error = %v", err)
+		}
+	})
+	poll(func() {
+		if response := j.Response(); response.ID != "1234" || response.State == "" {
+			t.Errorf("Job.Response() = %+v, want the 1234 job with its state", response)
+		}
+	})
+	poll(func() {
+		if audit, ok := j.UploadAudit(); ok && audit.Bytes != 10 {
+			t.Errorf("Job.UploadAudit() = %+v, want 10 bytes", audit)
+		}
+	})
+
+	// the pollers run between the uploads and state changes, even with one
+	// processor
+	for i := 0; i < 10; i++ {
+		var audit strings.Builder
+		if err := j.Upload(strings.NewReader("Name\nAcme\n"), WithAuditWriter(&audit)); err != nil {
+			t.Fatalf("Job.Upload() error = %v", err)
+		}
+		runtime.Gosched()
+		if _, err := j.Close(); err != nil {
+			t.Fatalf("Job.Close() error = %v", err)
+		}
+		runtime.Gosched()
+	}
+	close(done)
+	wg.Wait()
+
+	if got := j.Response(); got.ID != "1234" || got.State != UpdateComplete {
+		t.Errorf("Job.Response() = %+v, want the 1234 job in the %v state", got, UpdateComplete)
+	}
+	if _, ok := j.UploadAudit(); ok == false {
+		t.Errorf("Job.UploadAudit() = false, want the last upload's audit")
+	}
+}
+
+func TestOperation_IsValid(t *testing.T) {
+	for _, operation := range []Operation{Insert, Delete, HardDelete, Update, Upsert} {
+		if operation.IsValid() == false {
+			t.Errorf("Operation(%s).IsValid() = false, want true", operation)
+		}
+	}
+	for _, operation := range []Operation{"", "query", "hard_delete"} {
+		if operation.IsValid() {
+			t.Errorf("Operation(%s).IsValid() = true, want false", operation)
+		}
+	}
+}
+
+func TestJob_createCallout_hardDelete(t *testing.T) {
+	var body map[string]interface{}
+	job := &Job{
+		session: &mockSessionFormatter{
+			url: "https://test.salesforce.com",
+			client: mockHTTPClient(func(req *http.Request) *http.Response {
+				if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+					t.Errorf("request body error = %v", err)
+				}
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(`{"id": "750D00000004SkVIAU", "object": "Account", "operation": "hardDelete", "state": "Open"}`)),
+					Header:     make(http.Header),
+				}
+			}),
+		},
+	}
+	options := Options{
+		Object:    "Account",
+		Operation: HardDelete,
+	}
+	if err := job.formatOptions(&options); err != nil {
+		t.Fatalf("Job.formatOptions() error = %v", err)
+	}
+	response, err := job.createCallout(context.Background(), options)
+	if err != nil {
+		t.Fatalf("Job.createCallout() error = %v", err)
+	}
+	if body["operation"] != "hardDelete" {
+		t.Errorf("Job.createCallout() operation = %v, want hardDelete", body["operation"])
+	}
+	if body["externalIdFieldName"] != "" {
+		t.Errorf("Job.createCallout() external id field = %v, want none", body["externalIdFieldName"])
+	}
+	if response.Operation != "hardDelete" {
+		t.Errorf("Job.createCallout() = %+v, want the hardDelete job", response)
+	}
+
+	if err := job.formatOptions(&Options{Operation: HardDelete}); err == nil {
+		t.Error("Job.formatOptions() error = nil, want the object to be required")
+	}
+}
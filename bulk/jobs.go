@@ -1,11 +1,13 @@
 package bulk
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/namely/go-sfdc/v3"
 	"github.com/namely/go-sfdc/v3/session"
@@ -21,6 +23,21 @@ type Parameters struct {
 	JobType             JobType
 }
 
+// JobFilter is the client side filter applied when walking the job pages.
+//
+// CreatedByID will filter the jobs created by the user.  The session's user ID
+// can be retrieved from the session's identity.
+//
+// CreatedAfter will filter the jobs created after the time.  Since the jobs are
+// listed newest first, no more pages are retrieved once an older job is reached.
+//
+// CreatedBefore will filter the jobs created before the time.
+type JobFilter struct {
+	CreatedByID   string
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+}
+
 type jobResponse struct {
 	Done           bool       `json:"done"`
 	Records        []Response `json:"records"`
@@ -34,11 +51,15 @@ type Jobs struct {
 }
 
 func newJobs(session session.ServiceFormatter, parameters Parameters) (*Jobs, error) {
+	return newJobsContext(context.Background(), session, parameters)
+}
+
+func newJobsContext(ctx context.Context, session session.ServiceFormatter, parameters Parameters) (*Jobs, error) {
 	j := &Jobs{
 		session: session,
 	}
 	url := session.ServiceURL() + bulk2Endpoint
-	request, err := j.request(url)
+	request, err := j.request(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -67,10 +88,18 @@ func (j *Jobs) Records() []Response {
 
 // Next will retrieve the next batch of job information.
 func (j *Jobs) Next() (*Jobs, error) {
+	return j.next(context.Background())
+}
+
+func (j *Jobs) next(ctx context.Context) (*Jobs, error) {
 	if j.Done() == true {
 		return nil, errors.New("jobs: there is no more records")
 	}
-	request, err := j.request(j.response.NextRecordsURL)
+	url, err := sfdc.RebaseServicePath(j.session.InstanceURL(), j.response.NextRecordsURL)
+	if err != nil {
+		return nil, err
+	}
+	request, err := j.request(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -83,8 +112,99 @@ func (j *Jobs) Next() (*Jobs, error) {
 		response: response,
 	}, nil
 }
-func (j *Jobs) request(url string) (*http.Request, error) {
-	request, err := http.NewRequest(http.MethodGet, url, nil)
+
+// Each will call the function for each of the jobs that match the filter, retrieving
+// the next pages as needed.  If the function returns false, no more jobs are walked.
+func (j *Jobs) Each(filter JobFilter, fn func(Response) bool) error {
+	if fn == nil {
+		return errors.New("jobs: each function can not be nil")
+	}
+	return j.each(context.Background(), filter, fn)
+}
+
+// Count will return the number of the jobs that match the filter and the
+// predicate, retrieving the next pages as needed without keeping the jobs.  A
+// nil predicate counts all of the jobs that match the filter.  Like Each, no
+// more pages are retrieved once a job created before the filter's CreatedAfter
+// is reached, so a time window bounds the walk of the listing.
+func (j *Jobs) Count(ctx context.Context, filter JobFilter, predicate func(Response) bool) (int, error) {
+	count := 0
+	err := j.each(ctx, filter, func(record Response) bool {
+		if predicate == nil || predicate(record) {
+			count++
+		}
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (j *Jobs) each(ctx context.Context, filter JobFilter, fn func(Response) bool) error {
+	jobs := j
+	for {
+		for _, record := range jobs.Records() {
+			match, stop, err := filter.match(record)
+			if err != nil {
+				return err
+			}
+			if stop {
+				return nil
+			}
+			if match && fn(record) == false {
+				return nil
+			}
+		}
+		if jobs.Done() {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		next, err := jobs.next(ctx)
+		if err != nil {
+			return err
+		}
+		jobs = next
+	}
+}
+
+// All will return all of the jobs that match the filter, retrieving the next pages
+// as needed.
+func (j *Jobs) All(filter JobFilter) ([]Response, error) {
+	var records []Response
+	err := j.Each(filter, func(record Response) bool {
+		records = append(records, record)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func (f JobFilter) match(record Response) (match bool, stop bool, err error) {
+	if f.CreatedAfter.IsZero() == false || f.CreatedBefore.IsZero() == false {
+		created, err := sfdc.ParseTime(record.CreatedDate)
+		if err != nil {
+			return false, false, fmt.Errorf("jobs: job %s created date: %w", record.ID, err)
+		}
+		if f.CreatedAfter.IsZero() == false && created.Before(f.CreatedAfter) {
+			return false, true, nil
+		}
+		if f.CreatedBefore.IsZero() == false && created.After(f.CreatedBefore) {
+			return false, false, nil
+		}
+	}
+	if f.CreatedByID != "" && record.CreatedByID != f.CreatedByID {
+		return false, false, nil
+	}
+	return true, false, nil
+}
+
+func (j *Jobs) request(ctx context.Context, url string) (*http.Request, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -99,7 +219,7 @@ func (j *Jobs) do(request *http.Request) (jobResponse, error) {
 	}
 
 	decoder := json.NewDecoder(response.Body)
-	defer response.Body.Close()
+	defer sfdc.CloseResponse(response)
 
 	if response.StatusCode != http.StatusOK {
 		var jobsErrs []sfdc.Error
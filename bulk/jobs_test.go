@@ -1,11 +1,13 @@
 package bulk
 
 import (
-	"io/ioutil"
+	"context"
+	"io"
 	"net/http"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/namely/go-sfdc/v3/session"
 )
@@ -55,7 +57,7 @@ func TestJobs_do(t *testing.T) {
 						return &http.Response{
 							StatusCode: http.StatusOK,
 							Status:     "Good",
-							Body:       ioutil.NopCloser(strings.NewReader(resp)),
+							Body:       io.NopCloser(strings.NewReader(resp)),
 							Header:     make(http.Header),
 						}
 
@@ -104,7 +106,7 @@ func TestJobs_do(t *testing.T) {
 						return &http.Response{
 							StatusCode: http.StatusBadRequest,
 							Status:     "Bad",
-							Body:       ioutil.NopCloser(strings.NewReader(resp)),
+							Body:       io.NopCloser(strings.NewReader(resp)),
 							Header:     make(http.Header),
 						}
 					}),
@@ -144,7 +146,7 @@ func Test_newJobs(t *testing.T) {
 				return &http.Response{
 					StatusCode: 500,
 					Status:     "Invalid URL",
-					Body:       ioutil.NopCloser(strings.NewReader(req.URL.String())),
+					Body:       io.NopCloser(strings.NewReader(req.URL.String())),
 					Header:     make(http.Header),
 				}
 			}
@@ -174,7 +176,7 @@ func Test_newJobs(t *testing.T) {
 			return &http.Response{
 				StatusCode: http.StatusOK,
 				Status:     "Good",
-				Body:       ioutil.NopCloser(strings.NewReader(resp)),
+				Body:       io.NopCloser(strings.NewReader(resp)),
 				Header:     make(http.Header),
 			}
 		}),
@@ -350,7 +352,7 @@ func TestJobs_Next(t *testing.T) {
 				return &http.Response{
 					StatusCode: 500,
 					Status:     "Invalid URL",
-					Body:       ioutil.NopCloser(strings.NewReader(req.URL.String())),
+					Body:       io.NopCloser(strings.NewReader(req.URL.String())),
 					Header:     make(http.Header),
 				}
 			}
@@ -380,7 +382,7 @@ func TestJobs_Next(t *testing.T) {
 			return &http.Response{
 				StatusCode: http.StatusOK,
 				Status:     "Good",
-				Body:       ioutil.NopCloser(strings.NewReader(resp)),
+				Body:       io.NopCloser(strings.NewReader(resp)),
 				Header:     make(http.Header),
 			}
 		}),
@@ -449,3 +451,255 @@ func TestJobs_Next(t *testing.T) {
 		})
 	}
 }
+
+func testJobsPages(pages map[string]string) *mockSessionFormatter {
+	return &mockSessionFormatter{
+		url: "https://test.salesforce.com",
+		client: mockHTTPClient(func(req *http.Request) *http.Response {
+			resp, has := pages[req.URL.Path]
+			if has == false {
+				return &http.Response{
+					StatusCode: 500,
+					Status:     "Invalid URL",
+					Body:       io.NopCloser(strings.NewReader(req.URL.String())),
+					Header:     make(http.Header),
+				}
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Status:     "Good",
+				Body:       io.NopCloser(strings.NewReader(resp)),
+				Header:     make(http.Header),
+			}
+		}),
+	}
+}
+
+func TestJobs_All(t *testing.T) {
+	pages := map[string]string{
+		"/jobs/ingest/page2": `{
+			"done": false,
+			"nextRecordsUrl": "https://test.salesforce.com/jobs/ingest/page3",
+			"records": [
+				{"id": "job3", "createdById": "user1", "createdDate": "2020-06-02T10:00:00.000+0000"},
+				{"id": "job4", "createdById": "user1", "createdDate": "2020-05-30T10:00:00.000+0000"}
+			]
+		}`,
+		"/jobs/ingest/page3": `{
+			"done": true,
+			"records": [
+				{"id": "job5", "createdById": "user1", "createdDate": "2020-05-01T10:00:00.000+0000"}
+			]
+		}`,
+	}
+	first := jobResponse{
+		Done:           false,
+		NextRecordsURL: "https://test.salesforce.com/jobs/ingest/page2",
+		Records: []Response{
+			{ID: "job1", CreatedByID: "user1", CreatedDate: "2020-06-04T10:00:00.000+0000"},
+			{ID: "job2", CreatedByID: "user2", CreatedDate: "2020-06-03T10:00:00.000+0000"},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		filter    JobFilter
+		want      []string
+		wantPages []string
+		wantErr   bool
+	}{
+		{
+			name:      "no filter",
+			filter:    JobFilter{},
+			want:      []string{"job1", "job2", "job3", "job4", "job5"},
+			wantPages: []string{"/jobs/ingest/page2", "/jobs/ingest/page3"},
+			wantErr:   false,
+		},
+		{
+			name: "created by",
+			filter: JobFilter{
+				CreatedByID: "user2",
+			},
+			want:      []string{"job2"},
+			wantPages: []string{"/jobs/ingest/page2", "/jobs/ingest/page3"},
+			wantErr:   false,
+		},
+		{
+			name: "created after stops early",
+			filter: JobFilter{
+				CreatedAfter: time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC),
+			},
+			want:      []string{"job1", "job2", "job3"},
+			wantPages: []string{"/jobs/ingest/page2"},
+			wantErr:   false,
+		},
+		{
+			name: "created range and user",
+			filter: JobFilter{
+				CreatedByID:   "user1",
+				CreatedAfter:  time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC),
+				CreatedBefore: time.Date(2020, 6, 3, 0, 0, 0, 0, time.UTC),
+			},
+			want:      []string{"job3"},
+			wantPages: []string{"/jobs/ingest/page2"},
+			wantErr:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requested []string
+			mock := testJobsPages(pages)
+			transport := mock.client.Transport
+			mock.client.Transport = roundTripFunc(func(req *http.Request) *http.Response {
+				requested = append(requested, req.URL.Path)
+				response, _ := transport.RoundTrip(req)
+				return response
+			})
+			j := &Jobs{
+				session:  mock,
+				response: first,
+			}
+			got, err := j.All(tt.filter)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Jobs.All() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			ids := make([]string, len(got))
+			for idx, record := range got {
+				ids[idx] = record.ID
+			}
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("Jobs.All() = %v, want %v", ids, tt.want)
+			}
+			if !reflect.DeepEqual(requested, tt.wantPages) {
+				t.Errorf("Jobs.All() pages = %v, want %v", requested, tt.wantPages)
+			}
+		})
+	}
+}
+
+func TestJobs_Each(t *testing.T) {
+	j := &Jobs{
+		session: testJobsPages(map[string]string{}),
+		response: jobResponse{
+			Done:           false,
+			NextRecordsURL: "https://test.salesforce.com/jobs/ingest/page2",
+			Records: []Response{
+				{ID: "job1", CreatedDate: "2020-06-04T10:00:00.000+0000"},
+				{ID: "job2", CreatedDate: "2020-06-03T10:00:00.000+0000"},
+			},
+		},
+	}
+	var ids []string
+	err := j.Each(JobFilter{}, func(record Response) bool {
+		ids = append(ids, record.ID)
+		return false
+	})
+	if err != nil {
+		t.Errorf("Jobs.Each() error = %v", err)
+	}
+	if !reflect.DeepEqual(ids, []string{"job1"}) {
+		t.Errorf("Jobs.Each() = %v, want [job1]", ids)
+	}
+
+	j.response.Records[0].CreatedDate = "not a date"
+	err = j.Each(JobFilter{CreatedAfter: time.Now()}, func(Response) bool { return true })
+	if err == nil {
+		t.Errorf("Jobs.Each() expected a created date error")
+	}
+}
+
+func TestJobs_Count(t *testing.T) {
+	pages := map[string]string{
+		"/jobs/ingest/page2": `{
+			"done": false,
+			"nextRecordsUrl": "https://test.salesforce.com/jobs/ingest/page3",
+			"records": [
+				{"id": "job3", "state": "InProgress", "createdDate": "2020-06-02T10:00:00.000+0000"},
+				{"id": "job4", "state": "JobComplete", "createdDate": "2020-05-30T10:00:00.000+0000"}
+			]
+		}`,
+		"/jobs/ingest/page3": `{
+			"done": true,
+			"records": [
+				{"id": "job5", "state": "InProgress", "createdDate": "2020-05-01T10:00:00.000+0000"}
+			]
+		}`,
+	}
+	inProgress := func(record Response) bool {
+		return record.State == InProgress
+	}
+
+	tests := []struct {
+		name      string
+		filter    JobFilter
+		predicate func(Response) bool
+		want      int
+		wantPages []string
+	}{
+		{
+			name:      "all pages",
+			want:      5,
+			wantPages: []string{"/jobs/ingest/page2", "/jobs/ingest/page3"},
+		},
+		{
+			name:      "predicate",
+			predicate: inProgress,
+			want:      3,
+			wantPages: []string{"/jobs/ingest/page2", "/jobs/ingest/page3"},
+		},
+		{
+			name:      "stops after the window",
+			filter:    JobFilter{CreatedAfter: time.Date(2020, time.June, 1, 0, 0, 0, 0, time.UTC)},
+			predicate: inProgress,
+			want:      2,
+			wantPages: []string{"/jobs/ingest/page2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := testJobsPages(pages)
+			var requested []string
+			client := session.client
+			session.client = mockHTTPClient(func(req *http.Request) *http.Response {
+				requested = append(requested, req.URL.Path)
+				response, _ := client.Transport.RoundTrip(req)
+				return response
+			})
+			j := &Jobs{
+				session: session,
+				response: jobResponse{
+					NextRecordsURL: "https://test.salesforce.com/jobs/ingest/page2",
+					Records: []Response{
+						{ID: "job1", State: InProgress, CreatedDate: "2020-06-04T10:00:00.000+0000"},
+						{ID: "job2", State: Aborted, CreatedDate: "2020-06-03T10:00:00.000+0000"},
+					},
+				},
+			}
+			got, err := j.Count(context.Background(), tt.filter, tt.predicate)
+			if err != nil {
+				t.Fatalf("Jobs.Count() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Jobs.Count() = %d, want %d", got, tt.want)
+			}
+			if !reflect.DeepEqual(requested, tt.wantPages) {
+				t.Errorf("Jobs.Count() pages = %v, want %v", requested, tt.wantPages)
+			}
+		})
+	}
+
+	t.Run("canceled", func(t *testing.T) {
+		j := &Jobs{
+			session: testJobsPages(pages),
+			response: jobResponse{
+				NextRecordsURL: "https://test.salesforce.com/jobs/ingest/page2",
+			},
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := j.Count(ctx, JobFilter{}, nil); err != context.Canceled {
+			t.Errorf("Jobs.Count() error = %v, want %v", err, context.Canceled)
+		}
+	})
+}
@@ -0,0 +1,305 @@
+// Package massdelete deletes the records of an object that match a SOQL
+// filter with a bulk 2.0 delete job.  The records' IDs are queried with the
+// REST query API when there are few of them, and with a bulk query job when
+// there are many.
+package massdelete
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/namely/go-sfdc/v3"
+	"github.com/namely/go-sfdc/v3/bulk"
+	"github.com/namely/go-sfdc/v3/soql"
+)
+
+// DefaultThreshold is the largest number of records whose IDs are queried
+// with the REST query API when the threshold is not set.
+const DefaultThreshold = 10000
+
+// pollPolicy is the back off of the delete jobs' polls.
+var pollPolicy = sfdc.RetryPolicy{
+	InitialInterval: time.Second,
+	Multiplier:      1.5,
+	MaxInterval:     30 * time.Second,
+	Jitter:          0.25,
+}
+
+// Option is an option for the mass delete.
+type Option func(*options)
+
+type options struct {
+	threshold         int
+	hardDelete        bool
+	dryRun            bool
+	checkCapabilities bool
+	chunkOptions      []bulk.ChunkOption
+}
+
+// WithThreshold will set the largest number of records whose IDs are queried
+// with the REST query API.  The IDs of more records are queried with a bulk
+// query job.
+func WithThreshold(threshold int) Option {
+	return func(opts *options) {
+		opts.threshold = threshold
+	}
+}
+
+// WithHardDelete will delete the records with a hardDelete job, so they are
+// not moved to the recycle bin.  The user needs the Bulk API Hard Delete
+// permission.
+func WithHardDelete() Option {
+	return func(opts *options) {
+		opts.hardDelete = true
+	}
+}
+
+// WithCapabilityCheck will check that the session can create the delete job,
+// including the hard delete permission with WithHardDelete, before the
+// records are counted.  A missing capability is a *sfdc.NotPermittedError.
+func WithCapabilityCheck() Option {
+	return func(opts *options) {
+		opts.checkCapabilities = true
+	}
+}
+
+// WithChunkOptions will split the IDs into delete jobs with the chunk options,
+// like bulk.WithMaxChunkRecords.  The IDs are split at the upload limit
+// without the option.
+func WithChunkOptions(chunkOptions ...bulk.ChunkOption) Option {
+	return func(opts *options) {
+		opts.chunkOptions = append(opts.chunkOptions, chunkOptions...)
+	}
+}
+
+// WithDryRun will only count the records that match the filter, without
+// deleting them.
+func WithDryRun() Option {
+	return func(opts *options) {
+		opts.dryRun = true
+	}
+}
+
+// Result is the result of the mass delete.
+//
+// Count is the number of records that matched the filter.
+//
+// Jobs are the delete jobs, in the order of the IDs, which have the failed
+// records.  They are empty for a dry run or when no records matched.
+//
+// Infos are the final information of the delete jobs that were waited on, in
+// the order of the jobs.
+type Result struct {
+	Count int
+	Jobs  []*bulk.Job
+	Infos []bulk.Info
+}
+
+// DeleteByQuery will delete the records of the object that match the where
+// clause.  The records are counted and their IDs are queried, with the REST
+// query API when the count is at most the threshold and with a bulk query job
+// otherwise.  The IDs are streamed into delete jobs as they are queried, like
+// bulk.Resource.CreateChunkedJobs, so they are not held in memory, and the jobs
+// are waited on until they are complete.  A job that failed is a
+// bulk.JobFailedError with the result.  The where clause can be nil to delete
+// all of the records.
+//
+// When the IDs can not be queried after some of the jobs were created, those
+// jobs are processed and returned in the result with the error.
+func DeleteByQuery(ctx context.Context, resource *bulk.Resource, soqlResource *soql.Resource, objectType string, where soql.WhereClauser, opts ...Option) (Result, error) {
+	if resource == nil {
+		return Result{}, errors.New("mass delete: bulk resource can not be nil")
+	}
+	if soqlResource == nil {
+		return Result{}, errors.New("mass delete: soql resource can not be nil")
+	}
+	options := options{
+		threshold: DefaultThreshold,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	operation := bulk.Delete
+	if options.hardDelete {
+		operation = bulk.HardDelete
+	}
+	if options.checkCapabilities {
+		if err := resource.CheckCapabilities(ctx, operation); err != nil {
+			return Result{}, err
+		}
+	}
+
+	count, err := countRecords(ctx, soqlResource, objectType, where)
+	if err != nil {
+		return Result{}, err
+	}
+	result := Result{
+		Count: count,
+	}
+	if options.dryRun || count == 0 {
+		return result, nil
+	}
+
+	queryCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	reader, writer := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		writer.CloseWithError(writeIDs(queryCtx, writer, resource, soqlResource, objectType, where, count <= options.threshold))
+	}()
+	result.Jobs, err = resource.CreateChunkedJobs(ctx, bulk.Options{
+		Object:    objectType,
+		Operation: operation,
+	}, reader, options.chunkOptions...)
+	reader.CloseWithError(errors.New("mass delete: the delete jobs stopped reading the ids"))
+	cancel()
+	<-done
+	if err != nil {
+		return result, err
+	}
+
+	for _, job := range result.Jobs {
+		info, err := wait(ctx, job)
+		result.Infos = append(result.Infos, info)
+		if err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// writeIDs writes the CSV data of the records' IDs as they are queried, with
+// the REST query API or a bulk query job.
+func writeIDs(ctx context.Context, writer io.Writer, resource *bulk.Resource, soqlResource *soql.Resource, objectType string, where soql.WhereClauser, rest bool) error {
+	if _, err := io.WriteString(writer, "Id\n"); err != nil {
+		return err
+	}
+	// the IDs do not need to be quoted
+	write := func(id string) error {
+		_, err := io.WriteString(writer, id+"\n")
+		return err
+	}
+	if rest {
+		return restIDs(ctx, soqlResource, objectType, where, write)
+	}
+	return bulkIDs(ctx, resource, objectType, where, write)
+}
+
+func countRecords(ctx context.Context, resource *soql.Resource, objectType string, where soql.WhereClauser) (int, error) {
+	count, err := soql.Count("")
+	if err != nil {
+		return 0, err
+	}
+	query, err := soql.NewQuery(soql.QueryInput{
+		ObjectType: objectType,
+		Fields:     []soql.Field{count},
+		Where:      where,
+	})
+	if err != nil {
+		return 0, err
+	}
+	result, err := resource.QueryContext(ctx, query, false)
+	if err != nil {
+		return 0, err
+	}
+	return result.TotalSize(), nil
+}
+
+func idQuery(objectType string, where soql.WhereClauser) (*soql.Query, error) {
+	return soql.NewQuery(soql.QueryInput{
+		ObjectType: objectType,
+		FieldList:  []string{"Id"},
+		Where:      where,
+	})
+}
+
+func restIDs(ctx context.Context, resource *soql.Resource, objectType string, where soql.WhereClauser, fn func(string) error) error {
+	query, err := idQuery(objectType, where)
+	if err != nil {
+		return err
+	}
+	result, err := resource.QueryContext(ctx, query, false)
+	if err != nil {
+		return err
+	}
+	for {
+		for _, record := range result.Records() {
+			id, has := record.Record().FieldValue("Id")
+			if has == false {
+				return fmt.Errorf("mass delete: %s record does not have an Id", objectType)
+			}
+			if err := fn(fmt.Sprint(id)); err != nil {
+				return err
+			}
+		}
+		if result.MoreRecords() == false {
+			return nil
+		}
+		result, err = result.Next()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func bulkIDs(ctx context.Context, resource *bulk.Resource, objectType string, where soql.WhereClauser, fn func(string) error) error {
+	query, err := idQuery(objectType, where)
+	if err != nil {
+		return err
+	}
+	stmt, err := query.Format()
+	if err != nil {
+		return err
+	}
+	job, err := resource.CreateQueryJob(ctx, bulk.QueryOptions{
+		Query: stmt,
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := job.Complete(ctx); err != nil {
+		// the job is aborted so it does not keep running after the error
+		_, _ = job.Abort(context.Background())
+		return err
+	}
+	return job.AllResults(ctx, bulk.DefaultQueryResultPageSize, func(page *bulk.QueryResults) error {
+		for _, record := range page.Records {
+			if err := fn(record["Id"]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// wait will wait for the delete job to be processed, polling it with the back
+// off of the poll policy until the context is done.
+func wait(ctx context.Context, job *bulk.Job) (bulk.Info, error) {
+	backOff := pollPolicy.BackOff()
+	for {
+		info, err := job.InfoContext(ctx)
+		if err != nil {
+			return bulk.Info{}, err
+		}
+		switch info.State {
+		case bulk.JobComplete:
+			return info, nil
+		case bulk.Failed:
+			return info, &bulk.JobFailedError{Info: info}
+		case bulk.Aborted:
+			return info, fmt.Errorf("mass delete: job %s is %s", info.ID, info.State)
+		}
+
+		timer := time.NewTimer(backOff.NextBackOff())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return info, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
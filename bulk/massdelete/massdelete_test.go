@@ -0,0 +1,333 @@
+package massdelete
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/namely/go-sfdc/v3"
+	"github.com/namely/go-sfdc/v3/bulk"
+	"github.com/namely/go-sfdc/v3/soql"
+)
+
+type roundTripFunc func(request *http.Request) *http.Response
+
+// RoundTrip fails with the request's context error like the http.Transport.
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+	return f(req), nil
+}
+
+func mockHTTPClient(fn roundTripFunc) *http.Client {
+	return &http.Client{
+		Transport: roundTripFunc(fn),
+	}
+}
+
+func response(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Header:     make(http.Header),
+	}
+}
+
+// mockOrg is a Salesforce org with the Ids of the matching records.
+type mockOrg struct {
+	ids       []string
+	requests  []string
+	operation string
+	uploaded  []string
+	// hardDelete is the user's Bulk API Hard Delete permission
+	hardDelete bool
+	// queryState is the state of the query job, which is JobComplete if empty
+	queryState bulk.State
+}
+
+func (org *mockOrg) roundTrip(req *http.Request) *http.Response {
+	path := strings.TrimPrefix(req.URL.Path, "/services/data/v42.0")
+	org.requests = append(org.requests, req.Method+" "+path)
+	switch {
+	case path == "/services/data/":
+		return response(http.StatusOK, `[{"version": "42.0"}]`)
+	case path == "/query/" && strings.Contains(req.URL.Query().Get("q"), "UserPermissionAccess"):
+		return response(http.StatusOK, fmt.Sprintf(`{"done": true, "totalSize": 1, "records": [{"PermissionsBulkApiHardDelete": %t}]}`, org.hardDelete))
+	case path == "/query/" && strings.Contains(req.URL.Query().Get("q"), "COUNT()"):
+		return response(http.StatusOK, fmt.Sprintf(`{"done": true, "totalSize": %d, "records": []}`, len(org.ids)))
+	case path == "/query/":
+		return org.queryPage(org.ids[:2], false)
+	case path == "/query/01gNEXT-2000":
+		return org.queryPage(org.ids[2:], true)
+	case req.Method == http.MethodPost && path == "/jobs/query":
+		return response(http.StatusOK, `{"id": "750QUERY", "operation": "query", "object": "Account", "state": "UploadComplete"}`)
+	case path == "/jobs/query/750QUERY":
+		state := org.queryState
+		if state == "" || req.Method == http.MethodPatch {
+			state = bulk.JobComplete
+		}
+		return response(http.StatusOK, fmt.Sprintf(`{"id": "750QUERY", "operation": "query", "object": "Account", "state": "%s"}`, state))
+	case path == "/jobs/query/750QUERY/results":
+		resp := response(http.StatusOK, "\"Id\"\n\""+strings.Join(org.ids, "\"\n\"")+"\"\n")
+		resp.Header.Set("Sforce-Locator", "null")
+		return resp
+	case req.Method == http.MethodPost && path == "/jobs/ingest":
+		body, _ := io.ReadAll(req.Body)
+		org.operation = string(body)
+		return response(http.StatusOK, `{"id": "750INGEST", "object": "Account", "state": "Open"}`)
+	case req.Method == http.MethodPut && path == "/jobs/ingest/750INGEST/batches":
+		body, _ := io.ReadAll(req.Body)
+		org.uploaded = append(org.uploaded, string(body))
+		return response(http.StatusCreated, "")
+	case req.Method == http.MethodPatch && path == "/jobs/ingest/750INGEST":
+		return response(http.StatusOK, `{"id": "750INGEST", "object": "Account", "state": "UploadComplete"}`)
+	case req.Method == http.MethodGet && path == "/jobs/ingest/750INGEST":
+		return response(http.StatusOK, fmt.Sprintf(`{"id": "750INGEST", "object": "Account", "state": "JobComplete", "numberRecordsProcessed": %d}`, len(org.ids)))
+	default:
+		return response(http.StatusNotFound, `[{"errorCode": "NOT_FOUND", "message": "The requested resource does not exist"}]`)
+	}
+}
+
+func (org *mockOrg) queryPage(ids []string, done bool) *http.Response {
+	records := make([]string, len(ids))
+	for idx, id := range ids {
+		records[idx] = fmt.Sprintf(`{"attributes": {"type": "Account", "url": "/services/data/v42.0/sobjects/Account/%s"}, "Id": "%s"}`, id, id)
+	}
+	next := ""
+	if done == false {
+		next = `, "nextRecordsUrl": "/services/data/v42.0/query/01gNEXT-2000"`
+	}
+	return response(http.StatusOK, fmt.Sprintf(`{"done": %t, "totalSize": %d, "records": [%s]%s}`, done, len(org.ids), strings.Join(records, ","), next))
+}
+
+func (org *mockOrg) resources(t *testing.T) (*bulk.Resource, *soql.Resource) {
+	t.Helper()
+	session := &mockSessionFormatter{
+		url:      "https://test.salesforce.com/services/data/v42.0",
+		instance: "https://test.salesforce.com",
+		client:   mockHTTPClient(org.roundTrip),
+	}
+	bulkResource, err := bulk.NewResource(session)
+	if err != nil {
+		t.Fatal(err)
+	}
+	soqlResource, err := soql.NewResource(session)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bulkResource, soqlResource
+}
+
+func where(t *testing.T) soql.WhereClauser {
+	t.Helper()
+	clause, err := soql.WhereLike("Name", "Test%")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return clause
+}
+
+func TestDeleteByQuery(t *testing.T) {
+	tests := []struct {
+		name          string
+		options       []Option
+		wantOperation string
+		wantUploaded  []string
+		wantRequests  []string
+	}{
+		{
+			name:          "rest ids",
+			wantOperation: `"operation":"delete"`,
+			wantUploaded:  []string{"Id\n001A\n001B\n001C\n"},
+			wantRequests: []string{
+				"GET /query/",
+				"GET /query/",
+				"GET /query/01gNEXT-2000",
+				"POST /jobs/ingest",
+				"PUT /jobs/ingest/750INGEST/batches",
+				"PATCH /jobs/ingest/750INGEST",
+				"GET /jobs/ingest/750INGEST",
+			},
+		},
+		{
+			name:          "bulk ids",
+			options:       []Option{WithThreshold(2), WithHardDelete()},
+			wantOperation: `"operation":"hardDelete"`,
+			wantUploaded:  []string{"Id\n001A\n001B\n001C\n"},
+			wantRequests: []string{
+				"GET /query/",
+				"POST /jobs/query",
+				"GET /jobs/query/750QUERY",
+				"GET /jobs/query/750QUERY/results",
+				"POST /jobs/ingest",
+				"PUT /jobs/ingest/750INGEST/batches",
+				"PATCH /jobs/ingest/750INGEST",
+				"GET /jobs/ingest/750INGEST",
+			},
+		},
+		{
+			name:          "chunked ids",
+			options:       []Option{WithChunkOptions(bulk.WithMaxChunkRecords(2))},
+			wantOperation: `"operation":"delete"`,
+			wantUploaded:  []string{"Id\n001A\n001B\n", "Id\n001C\n"},
+			wantRequests: []string{
+				"GET /query/",
+				"GET /query/",
+				"GET /query/01gNEXT-2000",
+				"POST /jobs/ingest",
+				"PUT /jobs/ingest/750INGEST/batches",
+				"PATCH /jobs/ingest/750INGEST",
+				"POST /jobs/ingest",
+				"PUT /jobs/ingest/750INGEST/batches",
+				"PATCH /jobs/ingest/750INGEST",
+				"GET /jobs/ingest/750INGEST",
+				"GET /jobs/ingest/750INGEST",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			org := &mockOrg{
+				ids: []string{"001A", "001B", "001C"},
+			}
+			bulkResource, soqlResource := org.resources(t)
+
+			result, err := DeleteByQuery(context.Background(), bulkResource, soqlResource, "Account", where(t), tt.options...)
+			if err != nil {
+				t.Fatalf("DeleteByQuery() error = %v", err)
+			}
+			if result.Count != 3 || len(result.Jobs) != len(tt.wantUploaded) || len(result.Infos) != len(result.Jobs) {
+				t.Fatalf("DeleteByQuery() = %+v", result)
+			}
+			for _, info := range result.Infos {
+				if info.State != bulk.JobComplete {
+					t.Errorf("DeleteByQuery() info = %+v", info)
+				}
+			}
+			if strings.Contains(org.operation, tt.wantOperation) == false {
+				t.Errorf("DeleteByQuery() job options = %s, want %s", org.operation, tt.wantOperation)
+			}
+			if strings.Join(org.uploaded, "|") != strings.Join(tt.wantUploaded, "|") {
+				t.Errorf("DeleteByQuery() uploaded = %q, want %q", org.uploaded, tt.wantUploaded)
+			}
+			if strings.Join(org.requests, "\n") != strings.Join(tt.wantRequests, "\n") {
+				t.Errorf("DeleteByQuery() requests = %v, want %v", org.requests, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestDeleteByQuery_DryRun(t *testing.T) {
+	org := &mockOrg{
+		ids: []string{"001A", "001B", "001C"},
+	}
+	bulkResource, soqlResource := org.resources(t)
+
+	result, err := DeleteByQuery(context.Background(), bulkResource, soqlResource, "Account", where(t), WithDryRun())
+	if err != nil {
+		t.Fatalf("DeleteByQuery() error = %v", err)
+	}
+	if result.Count != 3 || result.Jobs != nil {
+		t.Errorf("DeleteByQuery() = %+v, want only the count", result)
+	}
+	if len(org.requests) != 1 {
+		t.Errorf("DeleteByQuery() requests = %v, want only the count", org.requests)
+	}
+	if query := org.requests[0]; query != "GET /query/" {
+		t.Errorf("DeleteByQuery() request = %s", query)
+	}
+}
+
+func TestDeleteByQuery_NoRecords(t *testing.T) {
+	org := &mockOrg{}
+	bulkResource, soqlResource := org.resources(t)
+
+	result, err := DeleteByQuery(context.Background(), bulkResource, soqlResource, "Account", nil)
+	if err != nil {
+		t.Fatalf("DeleteByQuery() error = %v", err)
+	}
+	if result.Count != 0 || result.Jobs != nil || len(org.requests) != 1 {
+		t.Errorf("DeleteByQuery() = %+v, requests %v", result, org.requests)
+	}
+	if _, err := DeleteByQuery(context.Background(), nil, soqlResource, "Account", nil); err == nil {
+		t.Error("DeleteByQuery() expected an error for a nil bulk resource")
+	}
+}
+
+func TestDeleteByQuery_CapabilityCheck(t *testing.T) {
+	org := &mockOrg{
+		ids: []string{"001A", "001B", "001C"},
+	}
+	bulkResource, soqlResource := org.resources(t)
+
+	_, err := DeleteByQuery(context.Background(), bulkResource, soqlResource, "Account", where(t), WithHardDelete(), WithCapabilityCheck())
+	var notPermitted *sfdc.NotPermittedError
+	if errors.As(err, &notPermitted) == false || notPermitted.Capability != sfdc.CapabilityHardDelete {
+		t.Fatalf("DeleteByQuery() error = %v, want the hard delete capability", err)
+	}
+	if want := []string{"GET /services/data/", "GET /query/"}; strings.Join(org.requests, "\n") != strings.Join(want, "\n") {
+		t.Errorf("DeleteByQuery() requests = %v, want only the probes %v", org.requests, want)
+	}
+
+	org.hardDelete = true
+	org.requests = nil
+	result, err := DeleteByQuery(context.Background(), bulkResource, soqlResource, "Account", where(t), WithHardDelete(), WithCapabilityCheck())
+	if err != nil {
+		t.Fatalf("DeleteByQuery() error = %v", err)
+	}
+	if result.Count != 3 || len(result.Infos) != 1 || result.Infos[0].State != bulk.JobComplete {
+		t.Errorf("DeleteByQuery() = %+v", result)
+	}
+	if strings.Contains(org.operation, `"operation":"hardDelete"`) == false {
+		t.Errorf("DeleteByQuery() job options = %s, want a hard delete", org.operation)
+	}
+}
+
+func TestDeleteByQuery_abortsQueryJob(t *testing.T) {
+	org := &mockOrg{
+		ids:        []string{"001A", "001B", "001C"},
+		queryState: bulk.InProgress,
+	}
+	bulkResource, soqlResource := org.resources(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := DeleteByQuery(ctx, bulkResource, soqlResource, "Account", where(t), WithThreshold(2))
+	if errors.Is(err, context.DeadlineExceeded) == false {
+		t.Fatalf("DeleteByQuery() error = %v, want the deadline", err)
+	}
+	want := []string{
+		"GET /query/",
+		"POST /jobs/query",
+		"GET /jobs/query/750QUERY",
+		"PATCH /jobs/query/750QUERY",
+	}
+	if strings.Join(org.requests, "\n") != strings.Join(want, "\n") {
+		t.Errorf("DeleteByQuery() requests = %v, want the query job aborted %v", org.requests, want)
+	}
+}
+
+func TestWait_canceled(t *testing.T) {
+	org := &mockOrg{}
+	bulkResource, _ := org.resources(t)
+	job, err := bulkResource.GetJob("750INGEST")
+	if err != nil {
+		t.Fatal(err)
+	}
+	org.requests = nil
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := wait(ctx, job); errors.Is(err, context.Canceled) == false {
+		t.Errorf("wait() error = %v, want the context's error", err)
+	}
+	if len(org.requests) != 0 {
+		t.Errorf("wait() requests = %v, want none with a canceled context", org.requests)
+	}
+}
@@ -0,0 +1,35 @@
+package massdelete
+
+import "net/http"
+
+type mockSessionFormatter struct {
+	url        string
+	instance   string
+	client     *http.Client
+	refreshErr error
+}
+
+func (mock *mockSessionFormatter) ServiceURL() string {
+	return mock.url
+}
+
+func (mock *mockSessionFormatter) Version() int {
+	return 42
+}
+
+func (mock *mockSessionFormatter) AuthorizationHeader(*http.Request) {}
+
+func (mock *mockSessionFormatter) Client() *http.Client {
+	return mock.client
+}
+
+func (mock *mockSessionFormatter) InstanceURL() string {
+	if mock.instance != "" {
+		return mock.instance
+	}
+	return mock.url
+}
+
+func (mock *mockSessionFormatter) Refresh() error {
+	return mock.refreshErr
+}
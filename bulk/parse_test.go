@@ -0,0 +1,103 @@
+package bulk
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func resultsBody(rows int) string {
+	var body strings.Builder
+	body.WriteString(`"sf__Id","sf__Created","Name","Industry","NumberOfEmployees"` + "\n")
+	for idx := 0; idx < rows; idx++ {
+		fmt.Fprintf(&body, "\"001D%014d\",\"true\",\"Account %d\",\"Industry %d\",\"%d\"\n", idx, idx, idx%10, idx)
+	}
+	return body.String()
+}
+
+func resultsJob(body string) *Job {
+	return &Job{
+		session: &mockSessionFormatter{
+			url: "https://test.salesforce.com",
+			client: mockHTTPClient(func(req *http.Request) *http.Response {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(body)),
+					Header:     make(http.Header),
+				}
+			}),
+		},
+		info: Response{
+			ID: "7505fEXAMPLE4C2AAM",
+		},
+	}
+}
+
+func TestJob_SuccessfulRecords_retainedValues(t *testing.T) {
+	records, err := resultsJob(resultsBody(3)).SuccessfulRecords()
+	if err != nil {
+		t.Fatalf("Job.SuccessfulRecords() error = %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("Job.SuccessfulRecords() = %d records, want 3", len(records))
+	}
+	for idx, record := range records {
+		if want := fmt.Sprintf("001D%014d", idx); record.ID != want {
+			t.Errorf("record %d ID = %v, want %v", idx, record.ID, want)
+		}
+		if want := fmt.Sprintf("Account %d", idx); record.Fields["Name"] != want {
+			t.Errorf("record %d Name = %v, want %v", idx, record.Fields["Name"], want)
+		}
+		if len(record.Fields) != 3 {
+			t.Errorf("record %d fields = %v", idx, record.Fields)
+		}
+	}
+}
+
+func TestJob_results_concurrentPages(t *testing.T) {
+	const pages = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, pages)
+	for page := 0; page < pages; page++ {
+		wg.Add(1)
+		go func(rows int) {
+			defer wg.Done()
+			records, err := resultsJob(resultsBody(rows)).SuccessfulRecords()
+			if err != nil {
+				errs <- err
+				return
+			}
+			if len(records) != rows {
+				errs <- fmt.Errorf("%d records, want %d", len(records), rows)
+				return
+			}
+			last := records[rows-1]
+			if want := fmt.Sprintf("Account %d", rows-1); last.Fields["Name"] != want {
+				errs <- fmt.Errorf("last Name = %v, want %v", last.Fields["Name"], want)
+			}
+		}(100 + page)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func BenchmarkResultsParsing(b *testing.B) {
+	body := resultsBody(100000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		records, err := resultsJob(body).SuccessfulRecords()
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(records) != 100000 {
+			b.Fatalf("%d records", len(records))
+		}
+	}
+}
@@ -0,0 +1,512 @@
+package bulk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/namely/go-sfdc/v3"
+	"github.com/namely/go-sfdc/v3/session"
+)
+
+const bulk2QueryEndpoint = "/jobs/query"
+
+const (
+	// Query is the operation for querying records.
+	Query Operation = "query"
+	// QueryAll is the operation for querying records, including the deleted
+	// and archived records.
+	QueryAll Operation = "queryAll"
+)
+
+// DefaultQueryResultPageSize is the maxRecords of the query results pages
+// when the helpers, like the QueryPages of a split query, are not given a page
+// size.
+const DefaultQueryResultPageSize = 50000
+
+// MaxQueryResultPageSize is the largest maxRecords that is recommended for a
+// page of the query results.  Larger pages can time out while they are being
+// downloaded.
+const MaxQueryResultPageSize = 100000
+
+// queryPollPolicy is the back off of the query job state checks, which starts
+// short for the quick queries and grows for the long ones.
+var queryPollPolicy = sfdc.RetryPolicy{
+	InitialInterval: time.Second,
+	Multiplier:      1.5,
+	MaxInterval:     30 * time.Second,
+	Jitter:          0.25,
+}
+
+// QueryOptions are the options for the query job.
+//
+// ColumnDelimiter is the delimiter used for the CSV results.  This field is optional.
+//
+// LineEnding is the line ending used for the CSV results.  This field is optional.
+//
+// Operation is the query operation for the job.  This field is optional and
+// defaults to Query.
+//
+// Query is the SOQL query.  This field is required.
+type QueryOptions struct {
+	ColumnDelimiter ColumnDelimiter `json:"columnDelimiter"`
+	LineEnding      LineEnding      `json:"lineEnding"`
+	Operation       Operation       `json:"operation"`
+	Query           string          `json:"query"`
+}
+
+// QueryResults is a page of the query job results.
+//
+// Records are the records of the page.
+//
+// Locator is used to retrieve the next page.  It is empty when there are
+// no more pages.
+//
+// NumberOfRecords is the number of records of the page that Salesforce
+// returned with the Sforce-NumberOfRecords header, or the number of records
+// when the header is missing.  A page with fewer records than the maxRecords
+// is a short page.
+//
+// Values are the records with the columns coerced to their field types, when
+// the query job has field types.  The columns without a field type are strings.
+// An empty value is nil, unless the column is a StringField.
+type QueryResults struct {
+	Records         []map[string]string
+	Locator         string
+	NumberOfRecords int
+	Values          []map[string]interface{}
+}
+
+// PageSizeWarning is passed to the page size warning when the maxRecords of
+// the query results is over MaxQueryResultPageSize.
+//
+// JobID is the ID of the query job.
+//
+// MaxRecords is the maxRecords of the query results.
+type PageSizeWarning struct {
+	JobID      string
+	MaxRecords int
+}
+
+// ProgressFunc is called with the progress of the query results download.
+//
+// Fetched is the number of records of the pages that were downloaded.
+//
+// Total is the number of records the query job processed.  It is zero when the
+// total is not known.
+//
+// Pct is the percentage of the records that were downloaded, or -1 when the
+// total is not known or is zero.
+type ProgressFunc func(fetched, total int, pct float64)
+
+// QueryJob is the bulk query job.
+type QueryJob struct {
+	session       session.ServiceFormatter
+	info          Response
+	correlationID string
+	version       int
+	warning       func(PageSizeWarning)
+	progress      ProgressFunc
+	fetched       int
+	total         int
+	complete      bool
+	fieldTypes    map[string]FieldType
+}
+
+// CreateQueryJob will create a new bulk 2.0 query job from the options that where passed.
+// The query job's API calls share the correlation ID of the context, or a new one if the
+// context does not have one.
+func (r *Resource) CreateQueryJob(ctx context.Context, options QueryOptions) (*QueryJob, error) {
+	ctx, end := session.StartSpan(ctx, r.session, "bulk.query.create", func() map[string]interface{} {
+		return map[string]interface{}{
+			"operation": string(options.Operation),
+		}
+	})
+	job := &QueryJob{
+		session:       r.session,
+		correlationID: correlationID(ctx),
+		version:       apiVersion(ctx),
+	}
+	err := job.create(job.context(ctx), options)
+	end(err)
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+func (j *QueryJob) create(ctx context.Context, options QueryOptions) error {
+	if options.Query == "" {
+		return errors.New("bulk query job: query is required")
+	}
+	if options.Operation == "" {
+		options.Operation = Query
+	}
+	if options.LineEnding == "" {
+		options.LineEnding = Linefeed
+	}
+	if options.ColumnDelimiter == "" {
+		options.ColumnDelimiter = Comma
+	}
+
+	serviceURL, err := session.ServiceURLContext(ctx, j.session)
+	if err != nil {
+		return err
+	}
+	url := serviceURL + bulk2QueryEndpoint
+	body, err := json.Marshal(options)
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Add("Accept", "application/json")
+	request.Header.Add("Content-Type", "application/json")
+	j.session.AuthorizationHeader(request)
+
+	info, err := j.infoResponse(request)
+	if err != nil {
+		return err
+	}
+	j.info = info.Response
+	return nil
+}
+
+// Response returns the job response from when the query job was created.
+func (j *QueryJob) Response() Response {
+	return j.info
+}
+
+// CorrelationID returns the correlation ID that is sent with the query job's API calls.
+func (j *QueryJob) CorrelationID() string {
+	return j.correlationID
+}
+
+func (j *QueryJob) context(ctx context.Context) context.Context {
+	if _, ok := sfdc.CorrelationID(ctx); ok == false && j.correlationID != "" {
+		ctx = sfdc.WithCorrelationID(ctx, j.correlationID)
+	}
+	if _, ok := sfdc.APIVersion(ctx); ok == false && j.version != 0 {
+		ctx = sfdc.WithAPIVersion(ctx, j.version)
+	}
+	return ctx
+}
+
+// Info returns the current query job information.
+func (j *QueryJob) Info(ctx context.Context) (Info, error) {
+	ctx = j.context(ctx)
+	serviceURL, err := session.ServiceURLContext(ctx, j.session)
+	if err != nil {
+		return Info{}, err
+	}
+	url := serviceURL + bulk2QueryEndpoint + "/" + j.info.ID
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return Info{}, err
+	}
+	request.Header.Add("Accept", "application/json")
+	j.session.AuthorizationHeader(request)
+
+	return j.infoResponse(request)
+}
+
+func (j *QueryJob) infoResponse(request *http.Request) (Info, error) {
+	response, err := j.session.Client().Do(request)
+	if err != nil {
+		return Info{}, err
+	}
+	defer sfdc.CloseResponse(response)
+
+	if response.StatusCode != http.StatusOK {
+		return Info{}, sfdc.HandleError(response)
+	}
+
+	var value Info
+	err = json.NewDecoder(response.Body).Decode(&value)
+	if err != nil {
+		return Info{}, err
+	}
+	return value, nil
+}
+
+// Complete will wait for the query job to complete, checking its state with a
+// growing back off.  An error is returned if the query job was aborted, and a
+// JobFailedError if it failed.
+func (j *QueryJob) Complete(ctx context.Context) (Info, error) {
+	backOff := queryPollPolicy.BackOff()
+	for {
+		info, err := j.Info(ctx)
+		if err != nil {
+			return Info{}, err
+		}
+		switch info.State {
+		case JobComplete:
+			return info, nil
+		case Failed:
+			return info, &JobFailedError{Info: info}
+		case Aborted:
+			return info, fmt.Errorf("bulk query job: job %s is %s: %s", info.ID, info.State, info.ErrorMessage)
+		}
+
+		timer := time.NewTimer(backOff.NextBackOff())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return Info{}, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// SetPageSizeWarning will call the warning function when the maxRecords of the
+// query results is over MaxQueryResultPageSize.
+func (j *QueryJob) SetPageSizeWarning(warning func(PageSizeWarning)) {
+	j.warning = warning
+}
+
+// SetProgress will call the progress function after each page of the query
+// results is downloaded.  The number of records processed by the query job is
+// taken from the job information the first time it is complete.  The fetched
+// records start again from zero when the first page is downloaded again.
+func (j *QueryJob) SetProgress(progress ProgressFunc) {
+	j.progress = progress
+}
+
+// SetFieldTypes will coerce the query results columns to the field types, in
+// the Values of the results.  A value that can not be coerced fails the
+// results with a FieldTypeError.  FieldTypesFromDescribe returns the field
+// types of an object's fields.  A field type that is not known is an error.
+func (j *QueryJob) SetFieldTypes(types map[string]FieldType) error {
+	if err := checkFieldTypes(types); err != nil {
+		return err
+	}
+	j.fieldTypes = types
+	return nil
+}
+
+// reportProgress adds the page's records to the fetched records and calls the
+// progress function.
+func (j *QueryJob) reportProgress(ctx context.Context, locator string, results QueryResults) {
+	if locator == "" {
+		j.fetched = 0
+	}
+	j.fetched += results.NumberOfRecords
+	if j.complete == false {
+		if info, err := j.Info(ctx); err == nil && info.State == JobComplete {
+			j.total = info.NumberRecordsProcessed
+			j.complete = true
+		}
+	}
+	j.progress(j.fetched, j.total, progressPercent(j.fetched, j.total))
+}
+
+func progressPercent(fetched, total int) float64 {
+	if total <= 0 {
+		return -1
+	}
+	return float64(fetched) * 100 / float64(total)
+}
+
+// Results returns a page of the query job results.  The locator is empty for
+// the first page and maxRecords is optional, with zero letting Salesforce pick
+// the page size.  A negative maxRecords is an error.  An empty or header-only
+// body has an empty, non-nil slice of records.
+func (j *QueryJob) Results(ctx context.Context, locator string, maxRecords int) (QueryResults, error) {
+	ctx = j.context(ctx)
+	response, err := j.resultsResponse(ctx, locator, maxRecords)
+	if err != nil {
+		return QueryResults{}, err
+	}
+	defer sfdc.CloseResponse(response)
+
+	job := &Job{
+		info: j.info,
+	}
+	results := QueryResults{
+		Records: []map[string]string{},
+	}
+	results.Locator, results.NumberOfRecords = resultsHeaders(response)
+	reader, fields, err := job.resultReader(response, false)
+	if err == io.EOF {
+		if results.NumberOfRecords < 0 {
+			results.NumberOfRecords = 0
+		}
+		if j.fieldTypes != nil {
+			results.Values = []map[string]interface{}{}
+		}
+		if j.progress != nil {
+			j.reportProgress(ctx, locator, results)
+		}
+		return results, nil
+	}
+	if err != nil {
+		return QueryResults{}, err
+	}
+	for {
+		values, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return QueryResults{}, err
+		}
+		results.Records = append(results.Records, job.record(fields, values))
+	}
+	if results.NumberOfRecords < 0 {
+		results.NumberOfRecords = len(results.Records)
+	}
+	if j.fieldTypes != nil {
+		results.Values, err = coerceRecords(results.Records, j.fieldTypes)
+		if err != nil {
+			return QueryResults{}, err
+		}
+	}
+	if j.progress != nil {
+		j.reportProgress(ctx, locator, results)
+	}
+
+	return results, nil
+}
+
+// AllResults will call the function with each page of the query job results,
+// following the locators from the first page until there are no more pages.
+// The maxRecords is the same as the Results'.  The error of the function is
+// returned as is, and stops the pages, and the context's error is returned
+// when it is done before the next page.
+func (j *QueryJob) AllResults(ctx context.Context, maxRecords int, fn func(page *QueryResults) error) error {
+	if fn == nil {
+		return errors.New("bulk query job: results function can not be nil")
+	}
+	locator := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		results, err := j.Results(ctx, locator, maxRecords)
+		if err != nil {
+			return err
+		}
+		if err := fn(&results); err != nil {
+			return err
+		}
+		if results.Locator == "" {
+			return nil
+		}
+		locator = results.Locator
+	}
+}
+
+// resultsResponse requests a page of the query job results.  The response's
+// body is closed by the caller.
+func (j *QueryJob) resultsResponse(ctx context.Context, locator string, maxRecords int) (*http.Response, error) {
+	if maxRecords < 0 {
+		return nil, fmt.Errorf("bulk query job: max records %d can not be negative", maxRecords)
+	}
+	if maxRecords > MaxQueryResultPageSize && j.warning != nil {
+		j.warning(PageSizeWarning{
+			JobID:      j.info.ID,
+			MaxRecords: maxRecords,
+		})
+	}
+
+	parameters := url.Values{}
+	if locator != "" {
+		parameters.Add("locator", locator)
+	}
+	if maxRecords > 0 {
+		parameters.Add("maxRecords", strconv.Itoa(maxRecords))
+	}
+	serviceURL, err := session.ServiceURLContext(ctx, j.session)
+	if err != nil {
+		return nil, err
+	}
+	url := serviceURL + bulk2QueryEndpoint + "/" + j.info.ID + "/results"
+	if len(parameters) > 0 {
+		url += "?" + parameters.Encode()
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	accept, err := (&Job{info: j.info}).resultMediaType()
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Add("Accept", accept)
+	j.session.AuthorizationHeader(request)
+
+	response, err := j.session.Client().Do(request)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusOK {
+		defer sfdc.CloseResponse(response)
+		return nil, sfdc.HandleError(response)
+	}
+	return response, nil
+}
+
+// resultsHeaders returns the locator of the next page, which is empty when
+// there are no more pages, and the number of records of the page, which is -1
+// when the header is missing.
+func resultsHeaders(response *http.Response) (string, int) {
+	locator := ""
+	if next := response.Header.Get("Sforce-Locator"); next != "null" {
+		locator = next
+	}
+	count, err := strconv.Atoi(response.Header.Get("Sforce-NumberOfRecords"))
+	if err != nil {
+		count = -1
+	}
+	return locator, count
+}
+
+// Abort will abort the query job.  If the query job failed, a JobFailedError
+// is returned with the response.
+func (j *QueryJob) Abort(ctx context.Context) (Response, error) {
+	ctx = j.context(ctx)
+	serviceURL, err := session.ServiceURLContext(ctx, j.session)
+	if err != nil {
+		return Response{}, err
+	}
+	url := serviceURL + bulk2QueryEndpoint + "/" + j.info.ID
+	body, err := json.Marshal(struct {
+		State string `json:"state"`
+	}{
+		State: string(Aborted),
+	})
+	if err != nil {
+		return Response{}, err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPatch, url, bytes.NewReader(body))
+	if err != nil {
+		return Response{}, err
+	}
+	request.Header.Add("Accept", "application/json")
+	request.Header.Add("Content-Type", "application/json")
+	j.session.AuthorizationHeader(request)
+
+	info, err := j.infoResponse(request)
+	if err != nil {
+		return Response{}, err
+	}
+	if info.State == Failed {
+		if info.ErrorMessage == "" {
+			if fetched, err := j.Info(ctx); err == nil {
+				info = fetched
+			}
+		}
+		return info.Response, &JobFailedError{Info: info}
+	}
+	return info.Response, nil
+}
@@ -0,0 +1,332 @@
+package bulk
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestQueryJob_Results_MaxRecords(t *testing.T) {
+	var maxRecords []string
+	client := mockHTTPClient(func(req *http.Request) *http.Response {
+		maxRecords = append(maxRecords, req.URL.Query().Get("maxRecords"))
+		header := make(http.Header)
+		header.Set("Sforce-Locator", "null")
+		header.Set("Sforce-NumberOfRecords", "2")
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader("\"Id\"\n\"001A\"\n\"001B\"\n")),
+			Header:     header,
+		}
+	})
+	job := &QueryJob{
+		session: &mockSessionFormatter{
+			url:    "https://test.salesforce.com/services/data/v42.0",
+			client: client,
+		},
+		info: Response{
+			ID: "750R0000000zhfdIAA",
+		},
+	}
+
+	if _, err := job.Results(context.Background(), "", -1); err == nil {
+		t.Error("QueryJob.Results() expected an error for negative max records")
+	}
+	if len(maxRecords) != 0 {
+		t.Errorf("QueryJob.Results() sent a request for negative max records")
+	}
+
+	var warnings []PageSizeWarning
+	job.SetPageSizeWarning(func(warning PageSizeWarning) {
+		warnings = append(warnings, warning)
+	})
+	results, err := job.Results(context.Background(), "", MaxQueryResultPageSize+1)
+	if err != nil {
+		t.Fatalf("QueryJob.Results() error = %v", err)
+	}
+	if len(warnings) != 1 || warnings[0].JobID != "750R0000000zhfdIAA" || warnings[0].MaxRecords != MaxQueryResultPageSize+1 {
+		t.Errorf("QueryJob.Results() warnings = %v", warnings)
+	}
+	if results.NumberOfRecords != 2 {
+		t.Errorf("QueryJob.Results() number of records = %d, want 2", results.NumberOfRecords)
+	}
+
+	warnings = nil
+	if _, err := job.Results(context.Background(), "", 10); err != nil {
+		t.Fatalf("QueryJob.Results() error = %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("QueryJob.Results() warnings = %v, want none", warnings)
+	}
+	if maxRecords[len(maxRecords)-1] != "10" {
+		t.Errorf("QueryJob.Results() maxRecords = %s, want 10", maxRecords[len(maxRecords)-1])
+	}
+}
+
+func TestQueryJob_Results_NumberOfRecordsWithoutHeader(t *testing.T) {
+	job := &QueryJob{
+		session: &mockSessionFormatter{
+			url: "https://test.salesforce.com/services/data/v42.0",
+			client: mockHTTPClient(func(req *http.Request) *http.Response {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader("\"Id\"\n\"001A\"\n\"001B\"\n\"001C\"\n")),
+					Header:     make(http.Header),
+				}
+			}),
+		},
+	}
+	results, err := job.Results(context.Background(), "", 0)
+	if err != nil {
+		t.Fatalf("QueryJob.Results() error = %v", err)
+	}
+	if results.NumberOfRecords != 3 {
+		t.Errorf("QueryJob.Results() number of records = %d, want 3", results.NumberOfRecords)
+	}
+}
+
+func TestQueryPages_PageSize(t *testing.T) {
+	var maxRecords []string
+	job := &QueryJob{
+		session: &mockSessionFormatter{
+			url: "https://test.salesforce.com/services/data/v42.0",
+			client: mockHTTPClient(func(req *http.Request) *http.Response {
+				maxRecords = append(maxRecords, req.URL.Query().Get("maxRecords"))
+				header := make(http.Header)
+				header.Set("Sforce-Locator", "null")
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader("\"Id\"\n\"001A\"\n")),
+					Header:     header,
+				}
+			}),
+		},
+	}
+	pages := &QueryPages{
+		ctx:    context.Background(),
+		jobs:   []*QueryJob{job, job},
+		ranges: make([]DateRange, 2),
+	}
+	if pages.Next() == false {
+		t.Fatalf("QueryPages.Next() = false, error = %v", pages.Err())
+	}
+	if err := pages.SetPageSize(-1); err == nil {
+		t.Error("QueryPages.SetPageSize() expected an error")
+	}
+	if err := pages.SetPageSize(500); err != nil {
+		t.Fatalf("QueryPages.SetPageSize() error = %v", err)
+	}
+	if pages.Next() == false {
+		t.Fatalf("QueryPages.Next() = false, error = %v", pages.Err())
+	}
+	if want := []string{"50000", "500"}; strings.Join(maxRecords, ",") != strings.Join(want, ",") {
+		t.Errorf("QueryPages maxRecords = %v, want %v", maxRecords, want)
+	}
+}
+
+func progressJob(state State, processed int) (*QueryJob, *int) {
+	infoCalls := 0
+	pages := map[string]struct {
+		next  string
+		count string
+		body  string
+	}{
+		"":   {next: "L2", count: "2", body: "\"Id\"\n\"001A\"\n\"001B\"\n"},
+		"L2": {next: "L3", count: "2", body: "\"Id\"\n\"001C\"\n\"001D\"\n"},
+		"L3": {next: "null", count: "1", body: "\"Id\"\n\"001E\"\n"},
+	}
+	job := &QueryJob{
+		session: &mockSessionFormatter{
+			url: "https://test.salesforce.com/services/data/v42.0",
+			client: mockHTTPClient(func(req *http.Request) *http.Response {
+				if strings.HasSuffix(req.URL.Path, "/results") == false {
+					infoCalls++
+					body := fmt.Sprintf(`{"id": "750R0000000zhfdIAA", "state": "%s", "numberRecordsProcessed": %d}`, state, processed)
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(body)),
+						Header:     make(http.Header),
+					}
+				}
+				page := pages[req.URL.Query().Get("locator")]
+				header := make(http.Header)
+				header.Set("Sforce-Locator", page.next)
+				header.Set("Sforce-NumberOfRecords", page.count)
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(page.body)),
+					Header:     header,
+				}
+			}),
+		},
+		info: Response{
+			ID: "750R0000000zhfdIAA",
+		},
+	}
+	return job, &infoCalls
+}
+
+type progressCall struct {
+	fetched int
+	total   int
+	pct     float64
+}
+
+func resultsProgress(t *testing.T, job *QueryJob) []progressCall {
+	t.Helper()
+	var calls []progressCall
+	job.SetProgress(func(fetched, total int, pct float64) {
+		calls = append(calls, progressCall{fetched: fetched, total: total, pct: pct})
+	})
+	locator := ""
+	for {
+		results, err := job.Results(context.Background(), locator, 2)
+		if err != nil {
+			t.Fatalf("QueryJob.Results() error = %v", err)
+		}
+		locator = results.Locator
+		if locator == "" {
+			return calls
+		}
+	}
+}
+
+func TestQueryJob_SetProgress(t *testing.T) {
+	job, infoCalls := progressJob(JobComplete, 5)
+	calls := resultsProgress(t, job)
+	want := []progressCall{
+		{fetched: 2, total: 5, pct: 40},
+		{fetched: 4, total: 5, pct: 80},
+		{fetched: 5, total: 5, pct: 100},
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("QueryJob progress = %v, want %v", calls, want)
+	}
+	if *infoCalls != 1 {
+		t.Errorf("QueryJob progress info calls = %d, want 1", *infoCalls)
+	}
+
+	// downloading the first page again starts the fetched records again
+	calls = resultsProgress(t, job)
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("QueryJob progress = %v, want %v", calls, want)
+	}
+	if *infoCalls != 1 {
+		t.Errorf("QueryJob progress info calls = %d, want 1", *infoCalls)
+	}
+}
+
+func TestQueryJob_SetProgress_UnknownTotal(t *testing.T) {
+	job, _ := progressJob(UpdateComplete, 0)
+	calls := resultsProgress(t, job)
+	want := []progressCall{
+		{fetched: 2, total: 0, pct: -1},
+		{fetched: 4, total: 0, pct: -1},
+		{fetched: 5, total: 0, pct: -1},
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("QueryJob progress = %v, want %v", calls, want)
+	}
+
+	job, _ = progressJob(JobComplete, 0)
+	calls = resultsProgress(t, job)
+	if calls[2].total != 0 || calls[2].pct != -1 {
+		t.Errorf("QueryJob progress = %v, want an unknown percentage", calls)
+	}
+}
+
+func TestQueryPages_SetProgress(t *testing.T) {
+	job, _ := progressJob(JobComplete, 5)
+	pages := &QueryPages{
+		ctx:    context.Background(),
+		jobs:   []*QueryJob{job},
+		ranges: make([]DateRange, 1),
+	}
+	var calls []progressCall
+	pages.SetProgress(func(fetched, total int, pct float64) {
+		calls = append(calls, progressCall{fetched: fetched, total: total, pct: pct})
+	})
+	if err := pages.SetPageSize(2); err != nil {
+		t.Fatalf("QueryPages.SetPageSize() error = %v", err)
+	}
+	for pages.Next() {
+	}
+	if err := pages.Err(); err != nil {
+		t.Fatalf("QueryPages.Err() = %v", err)
+	}
+	want := []progressCall{
+		{fetched: 2, total: 5, pct: 40},
+		{fetched: 4, total: 5, pct: 80},
+		{fetched: 5, total: 5, pct: 100},
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("QueryPages progress = %v, want %v", calls, want)
+	}
+}
+
+func TestQueryJob_AllResults(t *testing.T) {
+	var locators []string
+	client := mockHTTPClient(func(req *http.Request) *http.Response {
+		locator := req.URL.Query().Get("locator")
+		locators = append(locators, locator)
+		header := make(http.Header)
+		switch locator {
+		case "":
+			header.Set("Sforce-Locator", "MTAwMDA")
+		case "MTAwMDA":
+			header.Set("Sforce-Locator", "MjAwMDA")
+		default:
+			header.Set("Sforce-Locator", "null")
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(fmt.Sprintf("\"Id\"\n\"%s\"\n", locator))),
+			Header:     header,
+		}
+	})
+	job := &QueryJob{
+		session: &mockSessionFormatter{
+			url:    "https://test.salesforce.com/services/data/v42.0",
+			client: client,
+		},
+		info: Response{
+			ID: "750R0000000zhfdIAA",
+		},
+	}
+
+	var pages []string
+	err := job.AllResults(context.Background(), 10, func(page *QueryResults) error {
+		pages = append(pages, page.Records[0]["Id"])
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("QueryJob.AllResults() error = %v", err)
+	}
+	if want := []string{"", "MTAwMDA", "MjAwMDA"}; !reflect.DeepEqual(locators, want) || !reflect.DeepEqual(pages, want) {
+		t.Errorf("QueryJob.AllResults() locators = %v, pages = %v, want %v", locators, pages, want)
+	}
+
+	errStop := fmt.Errorf("stop")
+	locators = nil
+	err = job.AllResults(context.Background(), 10, func(page *QueryResults) error {
+		return errStop
+	})
+	if err != errStop || len(locators) != 1 {
+		t.Errorf("QueryJob.AllResults() error = %v after %d pages, want %v after 1", err, len(locators), errStop)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	locators = nil
+	err = job.AllResults(ctx, 10, func(page *QueryResults) error {
+		cancel()
+		return nil
+	})
+	if err != context.Canceled || len(locators) != 1 {
+		t.Errorf("QueryJob.AllResults() error = %v after %d pages, want %v after 1", err, len(locators), context.Canceled)
+	}
+}
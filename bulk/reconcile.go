@@ -0,0 +1,319 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/namely/go-sfdc/v3"
+	"github.com/namely/go-sfdc/v3/bulk/results"
+	"github.com/namely/go-sfdc/v3/session"
+)
+
+// ReconcileStatus is the final status of an input record of an upsert job.
+type ReconcileStatus string
+
+const (
+	// ReconcileCreated the record was created.
+	ReconcileCreated ReconcileStatus = "Created"
+	// ReconcileUpdated the record was updated.
+	ReconcileUpdated ReconcileStatus = "Updated"
+	// ReconcileFailed the record failed.
+	ReconcileFailed ReconcileStatus = "Failed"
+	// ReconcileUnprocessed the record was not processed.
+	ReconcileUnprocessed ReconcileStatus = "Unprocessed"
+	// ReconcileMissing the input record is in none of the job's results.
+	ReconcileMissing ReconcileStatus = "Missing"
+)
+
+// ReconciledRecord is the final status of an input record.
+//
+// ExternalID is the value of the record's external ID field.
+//
+// Status is the record's final status.
+//
+// Outcome is the record's outcome, with the errors of a failed record.  It is
+// empty for a missing record.
+//
+// Collision is true when the external ID is in more than one of the results,
+// so the record can not be matched to one input record.
+type ReconciledRecord struct {
+	ExternalID string
+	Status     ReconcileStatus
+	Outcome    results.RecordOutcome
+	Collision  bool
+}
+
+// ReconciliationReport is the per-input-record report of an upsert job.
+//
+// Created, Updated, Failed, Unprocessed and Missing are the number of records
+// with each status.
+//
+// Collisions are the sorted external IDs that are in more than one of the
+// results.  The collisions between the successful records are not found when
+// the report only counts the successful records.
+type ReconciliationReport struct {
+	Created     int
+	Updated     int
+	Failed      int
+	Unprocessed int
+	Missing     int
+	Collisions  []string
+	records     []ReconciledRecord
+}
+
+// Records returns an iterator over the reconciled records.  The failed and
+// unprocessed records are first, followed by the successful records, unless
+// the report only counts them, and the missing records.
+func (r ReconciliationReport) Records() *ReconciledRecords {
+	return &ReconciledRecords{
+		records: r.records,
+		idx:     -1,
+	}
+}
+
+// ReconciledRecords iterates over the records of a reconciliation report.
+type ReconciledRecords struct {
+	records []ReconciledRecord
+	idx     int
+}
+
+// Next will move to the next record.  It returns false when there are no more
+// records.
+func (it *ReconciledRecords) Next() bool {
+	if it.idx+1 >= len(it.records) {
+		it.idx = len(it.records)
+		return false
+	}
+	it.idx++
+	return true
+}
+
+// Record returns the current record.
+func (it *ReconciledRecords) Record() ReconciledRecord {
+	return it.records[it.idx]
+}
+
+// ReconcileOption is an option for the reconciliation of the job's results.
+type ReconcileOption func(*reconcileOptions)
+
+type reconcileOptions struct {
+	countSuccesses bool
+	input          []string
+}
+
+// WithSuccessCounts will only count the successful records, so the memory used
+// is proportional to the failed and unprocessed records.
+func WithSuccessCounts() ReconcileOption {
+	return func(opts *reconcileOptions) {
+		opts.countSuccesses = true
+	}
+}
+
+// WithInputExternalIDs will report the input records that are in none of the
+// job's results as missing.
+func WithInputExternalIDs(externalIDs []string) ReconcileOption {
+	return func(opts *reconcileOptions) {
+		opts.input = externalIDs
+	}
+}
+
+// Reconcile will stream the failed, unprocessed and successful results of the
+// job, key them by the external ID field and report the final status of each
+// input record.
+func (j *Job) Reconcile(ctx context.Context, externalIDField string, options ...ReconcileOption) (_ ReconciliationReport, err error) {
+	if externalIDField == "" {
+		return ReconciliationReport{}, errors.New("bulk job: external ID field is required")
+	}
+	opts := &reconcileOptions{}
+	for _, option := range options {
+		option(opts)
+	}
+	ctx, end := j.startSpan(ctx, "bulk.job.reconcile")
+	defer func() { end(err) }()
+
+	var report ReconciliationReport
+	counts := make(map[string]int)
+	input := make(map[string]bool, len(opts.input))
+	for _, externalID := range opts.input {
+		input[externalID] = false
+	}
+	see := func(externalID string) {
+		if externalID == "" {
+			return
+		}
+		if _, has := input[externalID]; has {
+			input[externalID] = true
+		}
+	}
+	add := func(record ReconciledRecord) {
+		see(record.ExternalID)
+		if record.ExternalID != "" {
+			counts[record.ExternalID]++
+		}
+		report.records = append(report.records, record)
+	}
+
+	err = j.streamResults(ctx, failedResultsPath, func(header, values []string) error {
+		errorPosition := j.headerPosition(sfError, header)
+		idPosition := j.headerPosition(sfID, header)
+		externalIDPosition := j.headerPosition(externalIDField, header)
+		if errorPosition < 0 || idPosition < 0 || externalIDPosition < 0 {
+			return fmt.Errorf("bulk job: failed results header must have %s, %s and %s", sfError, sfID, externalIDField)
+		}
+		var record FailedRecord
+		record.Error = values[errorPosition]
+		record.ID = values[idPosition]
+		record.Fields = j.record(header[2:], values[2:])
+		report.Failed++
+		add(ReconciledRecord{
+			ExternalID: values[externalIDPosition],
+			Status:     ReconcileFailed,
+			Outcome:    record.AsOutcome(),
+		})
+		return nil
+	})
+	if err != nil {
+		return ReconciliationReport{}, err
+	}
+
+	err = j.streamResults(ctx, unprocessedRecordsPath, func(header, values []string) error {
+		externalIDPosition := j.headerPosition(externalIDField, header)
+		if externalIDPosition < 0 {
+			return fmt.Errorf("bulk job: unprocessed results header must have %s", externalIDField)
+		}
+		report.Unprocessed++
+		add(ReconciledRecord{
+			ExternalID: values[externalIDPosition],
+			Status:     ReconcileUnprocessed,
+			Outcome: results.RecordOutcome{
+				Fields: j.record(header, values),
+			},
+		})
+		return nil
+	})
+	if err != nil {
+		return ReconciliationReport{}, err
+	}
+
+	err = j.streamResults(ctx, successfulResultsPath, func(header, values []string) error {
+		createdPosition := j.headerPosition(sfCreated, header)
+		idPosition := j.headerPosition(sfID, header)
+		externalIDPosition := j.headerPosition(externalIDField, header)
+		if createdPosition < 0 || idPosition < 0 || externalIDPosition < 0 {
+			return fmt.Errorf("bulk job: successful results header must have %s, %s and %s", sfCreated, sfID, externalIDField)
+		}
+		created, err := strconv.ParseBool(values[createdPosition])
+		if err != nil {
+			return err
+		}
+		status := ReconcileUpdated
+		if created {
+			status = ReconcileCreated
+			report.Created++
+		} else {
+			report.Updated++
+		}
+		externalID := values[externalIDPosition]
+		if opts.countSuccesses {
+			see(externalID)
+			if _, has := counts[externalID]; has {
+				counts[externalID]++
+			}
+			return nil
+		}
+		var record SuccessfulRecord
+		record.Created = created
+		record.ID = values[idPosition]
+		record.Fields = j.record(header[2:], values[2:])
+		add(ReconciledRecord{
+			ExternalID: externalID,
+			Status:     status,
+			Outcome:    record.AsOutcome(),
+		})
+		return nil
+	})
+	if err != nil {
+		return ReconciliationReport{}, err
+	}
+
+	for _, externalID := range opts.input {
+		if input[externalID] {
+			continue
+		}
+		// the input can have the same external ID more than once
+		input[externalID] = true
+		report.Missing++
+		report.records = append(report.records, ReconciledRecord{
+			ExternalID: externalID,
+			Status:     ReconcileMissing,
+		})
+	}
+
+	for externalID, count := range counts {
+		if count > 1 {
+			report.Collisions = append(report.Collisions, externalID)
+		}
+	}
+	sort.Strings(report.Collisions)
+	for idx := range report.records {
+		record := &report.records[idx]
+		record.Collision = record.ExternalID != "" && counts[record.ExternalID] > 1
+	}
+	return report, nil
+}
+
+// streamResults will call the function with each row of the results.  The
+// values are only valid until the function returns.  Results without a header
+// have no rows.
+func (j *Job) streamResults(ctx context.Context, path string, fn func(header, values []string) error) error {
+	serviceURL, err := session.ServiceURLContext(ctx, j.session)
+	if err != nil {
+		return err
+	}
+	url := serviceURL + bulk2Endpoint + "/" + j.Response().ID + path
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	accept, err := j.resultMediaType()
+	if err != nil {
+		return err
+	}
+	request.Header.Add("Accept", accept)
+	j.session.AuthorizationHeader(request)
+
+	response, err := j.doResults(request)
+	if err != nil {
+		return err
+	}
+	defer sfdc.CloseResponse(response)
+
+	if response.StatusCode != http.StatusOK {
+		return sfdc.HandleError(response)
+	}
+
+	reader, header, err := j.resultReader(response, false)
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for {
+		values, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(header, values); err != nil {
+			return err
+		}
+	}
+}
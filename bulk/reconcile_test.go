@@ -0,0 +1,139 @@
+package bulk
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func reconcileJob(t *testing.T) *Job {
+	t.Helper()
+	bodies := map[string]string{
+		"/services/data/v42.0/jobs/ingest/750D00000004SkVIAU/successfulResults": "\"sf__Id\",\"sf__Created\",\"Ext__c\",\"Name\"\n" +
+			"\"001A\",\"true\",\"E1\",\"Created One\"\n" +
+			"\"001B\",\"false\",\"E2\",\"Updated One\"\n" +
+			"\"001C\",\"false\",\"E5\",\"Collided One\"\n",
+		"/services/data/v42.0/jobs/ingest/750D00000004SkVIAU/failedResults": "\"sf__Id\",\"sf__Error\",\"Ext__c\",\"Name\"\n" +
+			"\"\",\"REQUIRED_FIELD_MISSING:Required fields are missing: [Name]\",\"E3\",\"\"\n" +
+			"\"\",\"DUPLICATE_VALUE:duplicate value found\",\"E5\",\"Collided Two\"\n",
+		"/services/data/v42.0/jobs/ingest/750D00000004SkVIAU/unprocessedrecords": "\"Ext__c\",\"Name\"\n" +
+			"\"E4\",\"Unprocessed One\"\n",
+	}
+	return &Job{
+		session: &mockSessionFormatter{
+			url: "https://test.salesforce.com/services/data/v42.0",
+			client: mockHTTPClient(func(req *http.Request) *http.Response {
+				body, has := bodies[req.URL.Path]
+				if has == false {
+					return &http.Response{
+						StatusCode: http.StatusNotFound,
+						Body:       io.NopCloser(strings.NewReader(req.URL.Path)),
+						Header:     make(http.Header),
+					}
+				}
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(body)),
+					Header:     make(http.Header),
+				}
+			}),
+		},
+		info: Response{
+			ID:                  "750D00000004SkVIAU",
+			ExternalIDFieldName: "Ext__c",
+			Operation:           Upsert,
+		},
+	}
+}
+
+func reconciledStatuses(report ReconciliationReport) map[string][]ReconciledRecord {
+	records := make(map[string][]ReconciledRecord)
+	for it := report.Records(); it.Next(); {
+		record := it.Record()
+		records[record.ExternalID] = append(records[record.ExternalID], record)
+	}
+	return records
+}
+
+func TestJob_Reconcile(t *testing.T) {
+	job := reconcileJob(t)
+	report, err := job.Reconcile(context.Background(), "Ext__c", WithInputExternalIDs([]string{"E1", "E2", "E3", "E4", "E5", "E6"}))
+	if err != nil {
+		t.Fatalf("Job.Reconcile() error = %v", err)
+	}
+	counts := []int{report.Created, report.Updated, report.Failed, report.Unprocessed, report.Missing}
+	if want := []int{1, 2, 2, 1, 1}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("Job.Reconcile() counts = %v, want %v", counts, want)
+	}
+	if want := []string{"E5"}; !reflect.DeepEqual(report.Collisions, want) {
+		t.Errorf("Job.Reconcile() collisions = %v, want %v", report.Collisions, want)
+	}
+
+	records := reconciledStatuses(report)
+	want := map[string][]ReconcileStatus{
+		"E1": {ReconcileCreated},
+		"E2": {ReconcileUpdated},
+		"E3": {ReconcileFailed},
+		"E4": {ReconcileUnprocessed},
+		"E5": {ReconcileFailed, ReconcileUpdated},
+		"E6": {ReconcileMissing},
+	}
+	for externalID, statuses := range want {
+		got := records[externalID]
+		if len(got) != len(statuses) {
+			t.Fatalf("Job.Reconcile() %s = %v, want %v", externalID, got, statuses)
+		}
+		for idx, status := range statuses {
+			if got[idx].Status != status {
+				t.Errorf("Job.Reconcile() %s status = %s, want %s", externalID, got[idx].Status, status)
+			}
+			if got[idx].Collision != (externalID == "E5") {
+				t.Errorf("Job.Reconcile() %s collision = %v", externalID, got[idx].Collision)
+			}
+		}
+	}
+	if failed := records["E3"][0].Outcome; failed.Errors[0].StatusCode != "REQUIRED_FIELD_MISSING" || failed.Success {
+		t.Errorf("Job.Reconcile() E3 outcome = %+v", failed)
+	}
+	if created := records["E1"][0].Outcome; created.ID != "001A" || created.Created == false || created.Fields["Name"] != "Created One" {
+		t.Errorf("Job.Reconcile() E1 outcome = %+v", created)
+	}
+	if unprocessed := records["E4"][0].Outcome; unprocessed.Fields["Name"] != "Unprocessed One" {
+		t.Errorf("Job.Reconcile() E4 outcome = %+v", unprocessed)
+	}
+}
+
+func TestJob_Reconcile_SuccessCounts(t *testing.T) {
+	job := reconcileJob(t)
+	report, err := job.Reconcile(context.Background(), "Ext__c", WithSuccessCounts(), WithInputExternalIDs([]string{"E1", "E6"}))
+	if err != nil {
+		t.Fatalf("Job.Reconcile() error = %v", err)
+	}
+	counts := []int{report.Created, report.Updated, report.Failed, report.Unprocessed, report.Missing}
+	if want := []int{1, 2, 2, 1, 1}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("Job.Reconcile() counts = %v, want %v", counts, want)
+	}
+	if want := []string{"E5"}; !reflect.DeepEqual(report.Collisions, want) {
+		t.Errorf("Job.Reconcile() collisions = %v, want %v", report.Collisions, want)
+	}
+	records := reconciledStatuses(report)
+	if _, has := records["E1"]; has {
+		t.Errorf("Job.Reconcile() kept the successful record E1")
+	}
+	if len(records) != 4 {
+		t.Errorf("Job.Reconcile() records = %v, want E3, E4, E5 and E6", records)
+	}
+}
+
+func TestJob_Reconcile_MissingExternalIDColumn(t *testing.T) {
+	job := reconcileJob(t)
+	if _, err := job.Reconcile(context.Background(), "Other__c"); err == nil {
+		t.Error("Job.Reconcile() expected an error")
+	}
+	if _, err := job.Reconcile(context.Background(), ""); err == nil {
+		t.Error("Job.Reconcile() expected an error")
+	}
+}
@@ -0,0 +1,66 @@
+package bulk
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	sfdc "github.com/namely/go-sfdc/v3"
+)
+
+// The paths of the job's record results, in their documented form without a
+// trailing slash, which some pods redirect.
+const (
+	successfulResultsPath  = "/successfulResults"
+	failedResultsPath      = "/failedResults"
+	unprocessedRecordsPath = "/unprocessedrecords"
+)
+
+// maxResultRedirects is the most redirects of a result download that are
+// followed, like the default client's limit.
+const maxResultRedirects = 10
+
+// doResults sends the result download request.  The redirects are not left to
+// the client's policy, which can drop the authorization header: a redirect to
+// the same origin is sent again with the session's authorization header, and
+// a redirect to another origin is an error, since the results should not leave
+// the instance.
+func (j *Job) doResults(request *http.Request) (*http.Response, error) {
+	client := *j.session.Client()
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	for redirects := 0; ; redirects++ {
+		response, err := client.Do(request)
+		if err != nil {
+			return nil, err
+		}
+		switch response.StatusCode {
+		case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+			http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		default:
+			return response, nil
+		}
+		location := response.Header.Get("Location")
+		sfdc.CloseResponse(response)
+		if location == "" {
+			return nil, fmt.Errorf("bulk results: %s redirect without a location", response.Status)
+		}
+		next, err := request.URL.Parse(location)
+		if err != nil {
+			return nil, fmt.Errorf("bulk results: redirect location: %w", err)
+		}
+		if sfdc.SameOrigin(next, request.URL) == false {
+			return nil, fmt.Errorf("bulk results: redirect to %s is not the same origin", next.Redacted())
+		}
+		if redirects == maxResultRedirects {
+			return nil, errors.New("bulk results: too many redirects")
+		}
+		redirected := request.Clone(request.Context())
+		redirected.URL = next
+		redirected.Host = ""
+		redirected.Header.Del("Authorization")
+		j.session.AuthorizationHeader(redirected)
+		request = redirected
+	}
+}
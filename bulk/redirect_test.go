@@ -0,0 +1,136 @@
+package bulk
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// authSessionFormatter is the mock session with an authorization header.
+type authSessionFormatter struct {
+	mockSessionFormatter
+}
+
+func (mock *authSessionFormatter) AuthorizationHeader(request *http.Request) {
+	request.Header.Add("Authorization", "Bearer token")
+}
+
+// redirectJob returns a job whose result downloads are redirected to the
+// location, and records the requests' URLs and authorization headers.
+func redirectJob(location string, requests *[]string) *Job {
+	client := mockHTTPClient(func(req *http.Request) *http.Response {
+		*requests = append(*requests, req.URL.String()+" "+req.Header.Get("Authorization"))
+		if strings.HasSuffix(req.URL.Path, "/") == false {
+			header := make(http.Header)
+			header.Set("Location", location)
+			return &http.Response{
+				StatusCode: http.StatusMovedPermanently,
+				Status:     "301 Moved Permanently",
+				Body:       io.NopCloser(strings.NewReader("")),
+				Header:     header,
+			}
+		}
+		var body string
+		switch {
+		case strings.HasSuffix(req.URL.Path, successfulResultsPath+"/"):
+			body = "\"sf__Id\",\"sf__Created\",\"Name\"\n\"001A\",\"true\",\"Acme\"\n"
+		case strings.HasSuffix(req.URL.Path, failedResultsPath+"/"):
+			body = "\"sf__Id\",\"sf__Error\",\"Name\"\n\"\",\"REQUIRED_FIELD_MISSING:Name\",\"\"\n"
+		default:
+			body = "\"Name\"\n\"Globex\"\n"
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Header:     make(http.Header),
+		}
+	})
+	return &Job{
+		session: &authSessionFormatter{
+			mockSessionFormatter: mockSessionFormatter{
+				url:    "https://test.salesforce.com",
+				client: client,
+			},
+		},
+		info: Response{ID: "750D00000004SkVIAU"},
+	}
+}
+
+func TestJob_results_redirect(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		download func(*Job) (int, error)
+	}{
+		{
+			name: "successful",
+			path: successfulResultsPath,
+			download: func(job *Job) (int, error) {
+				records, err := job.SuccessfulRecords()
+				return len(records), err
+			},
+		},
+		{
+			name: "failed",
+			path: failedResultsPath,
+			download: func(job *Job) (int, error) {
+				records, err := job.FailedRecords()
+				return len(records), err
+			},
+		},
+		{
+			name: "unprocessed",
+			path: unprocessedRecordsPath,
+			download: func(job *Job) (int, error) {
+				records, err := job.UnprocessedRecords()
+				return len(records), err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := "https://test.salesforce.com/jobs/ingest/750D00000004SkVIAU" + tt.path
+			var requests []string
+			job := redirectJob("/jobs/ingest/750D00000004SkVIAU"+tt.path+"/", &requests)
+
+			count, err := tt.download(job)
+			if err != nil {
+				t.Fatalf("download error = %v", err)
+			}
+			if count != 1 {
+				t.Errorf("download = %d records, want 1", count)
+			}
+			want := []string{url + " Bearer token", url + "/ Bearer token"}
+			if strings.Join(requests, "\n") != strings.Join(want, "\n") {
+				t.Errorf("requests = %v, want %v", requests, want)
+			}
+		})
+	}
+}
+
+func TestJob_results_redirectOtherOrigin(t *testing.T) {
+	var requests []string
+	job := redirectJob("https://proxy.example.com/jobs/ingest/750D00000004SkVIAU/successfulResults/", &requests)
+
+	_, err := job.SuccessfulRecords()
+	if err == nil || strings.Contains(err.Error(), "not the same origin") == false {
+		t.Errorf("Job.SuccessfulRecords() error = %v, want a same origin error", err)
+	}
+	if len(requests) != 1 {
+		t.Errorf("requests = %v, want the redirect not to be followed", requests)
+	}
+}
+
+func TestJob_results_tooManyRedirects(t *testing.T) {
+	var requests []string
+	job := redirectJob(successfulResultsPath, &requests)
+
+	_, err := job.SuccessfulRecords()
+	if err == nil || strings.Contains(err.Error(), "too many redirects") == false {
+		t.Errorf("Job.SuccessfulRecords() error = %v, want a too many redirects error", err)
+	}
+	if len(requests) != maxResultRedirects+1 {
+		t.Errorf("requests = %d, want %d", len(requests), maxResultRedirects+1)
+	}
+}
@@ -0,0 +1,194 @@
+package bulk
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/namely/go-sfdc/v3"
+)
+
+// ErrJobNotFound is returned when there is no job for a correlation key, or
+// the registered job no longer exists.
+var ErrJobNotFound = errors.New("bulk: job not found")
+
+// RegistryEntry is the registration of a job with a correlation key.
+//
+// JobID is the Salesforce ID of the job.
+//
+// Key is the correlation key, like batch-2024-06-01/customer-42.
+//
+// CreatedAt is when the job was registered.
+//
+// OptionsDigest is the SHA-256 digest of the job's options, with their defaults,
+// which can be used to check that a job for the key was created with the same options.
+type RegistryEntry struct {
+	JobID         string
+	Key           string
+	CreatedAt     time.Time
+	OptionsDigest string
+}
+
+// JobRegistry persists the jobs by their correlation keys, since Salesforce jobs
+// do not have a metadata field.  A key has at most one entry, and registering a
+// key again replaces its entry.
+//
+// Register will persist the entry.
+//
+// Lookup will return the entry for the key, and whether there is one.
+//
+// Remove will remove the entry for the key.
+type JobRegistry interface {
+	Register(ctx context.Context, entry RegistryEntry) error
+	Lookup(ctx context.Context, key string) (RegistryEntry, bool, error)
+	Remove(ctx context.Context, key string) error
+}
+
+// MemoryRegistry is an in-memory job registry.  It is the default registry of
+// the resource.
+type MemoryRegistry struct {
+	mu      sync.RWMutex
+	entries map[string]RegistryEntry
+}
+
+// NewMemoryRegistry creates an empty in-memory job registry.
+func NewMemoryRegistry() *MemoryRegistry {
+	return &MemoryRegistry{
+		entries: make(map[string]RegistryEntry),
+	}
+}
+
+// Register will persist the entry.
+func (m *MemoryRegistry) Register(ctx context.Context, entry RegistryEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries[entry.Key] = entry
+	return nil
+}
+
+// Lookup will return the entry for the key, and whether there is one.
+func (m *MemoryRegistry) Lookup(ctx context.Context, key string) (RegistryEntry, bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	entry, has := m.entries[key]
+	return entry, has, nil
+}
+
+// Remove will remove the entry for the key.
+func (m *MemoryRegistry) Remove(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.entries, key)
+	return nil
+}
+
+// ResourceOption is an option for the bulk resource.
+type ResourceOption func(*Resource)
+
+// WithJobRegistry will use the registry for the jobs created with a correlation
+// key, instead of an in-memory registry.
+func WithJobRegistry(registry JobRegistry) ResourceOption {
+	return func(r *Resource) {
+		r.registry = registry
+	}
+}
+
+// JobOption is an option for creating a job.
+type JobOption func(*jobOptions)
+
+type jobOptions struct {
+	correlationKey    string
+	autoDelimiter     bool
+	sample            []map[string]interface{}
+	checkCapabilities bool
+}
+
+// WithCorrelationKey will register the job with the key in the resource's job
+// registry, so it can be found with FindJobByKey.
+func WithCorrelationKey(key string) JobOption {
+	return func(opts *jobOptions) {
+		opts.correlationKey = key
+	}
+}
+
+// FindJobByKey will return the job registered with the correlation key.  The job
+// is retrieved from Salesforce to check that it still exists.  If there is no
+// entry for the key, or the job was deleted, ErrJobNotFound is returned and a
+// stale entry is removed.
+func (r *Resource) FindJobByKey(ctx context.Context, key string) (*Job, error) {
+	if key == "" {
+		return nil, errors.New("bulk: correlation key can not be empty")
+	}
+	if r.registry == nil {
+		return nil, ErrJobNotFound
+	}
+
+	entry, has, err := r.registry.Lookup(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if has == false {
+		return nil, ErrJobNotFound
+	}
+
+	job, err := r.getJob(ctx, entry.JobID)
+	if isNotFound(err) {
+		if err := r.registry.Remove(ctx, key); err != nil {
+			return nil, err
+		}
+		return nil, ErrJobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (r *Resource) register(ctx context.Context, key string, job *Job, options Options) error {
+	if r.registry == nil {
+		return errors.New("bulk: job registry is not set")
+	}
+	if err := job.formatOptions(&options); err != nil {
+		return err
+	}
+	digest, err := optionsDigest(options)
+	if err != nil {
+		return err
+	}
+	return r.registry.Register(ctx, RegistryEntry{
+		JobID:         job.Response().ID,
+		Key:           key,
+		CreatedAt:     time.Now().UTC(),
+		OptionsDigest: digest,
+	})
+}
+
+func optionsDigest(options Options) (string, error) {
+	body, err := json.Marshal(options)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// isNotFound returns whether the error is the Salesforce NOT_FOUND error.
+func isNotFound(err error) bool {
+	var errs sfdc.Errors
+	if errors.As(err, &errs) == false {
+		return false
+	}
+	for _, e := range errs {
+		if e.ErrorCode == "NOT_FOUND" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestResource_FindJobByKey(t *testing.T) {
+	deleted := false
+	r, err := NewResource(&mockSessionFormatter{
+		url: "https://test.salesforce.com",
+		client: mockHTTPClient(func(req *http.Request) *http.Response {
+			switch {
+			case req.Method == http.MethodPost:
+				resp := `{"id": "7505fEXAMPLE4C2AAM", "object": "Account", "operation": "insert", "state": "Open"}`
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(resp)),
+					Header:     make(http.Header),
+				}
+			case deleted:
+				resp := `[{"errorCode": "NOT_FOUND", "message": "The requested resource does not exist"}]`
+				return &http.Response{
+					StatusCode: http.StatusNotFound,
+					Status:     "404 Not Found",
+					Body:       io.NopCloser(strings.NewReader(resp)),
+					Header:     make(http.Header),
+				}
+			default:
+				resp := `{"id": "7505fEXAMPLE4C2AAM", "object": "Account", "operation": "insert", "state": "UploadComplete"}`
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(resp)),
+					Header:     make(http.Header),
+				}
+			}
+		}),
+	})
+	if err != nil {
+		t.Fatalf("NewResource() error = %v", err)
+	}
+	ctx := context.Background()
+	const key = "batch-2024-06-01/customer-42"
+
+	if _, err := r.FindJobByKey(ctx, key); errors.Is(err, ErrJobNotFound) == false {
+		t.Errorf("Resource.FindJobByKey() error = %v, want %v", err, ErrJobNotFound)
+	}
+
+	options := Options{
+		Object:    "Account",
+		Operation: Insert,
+	}
+	if _, err := r.CreateJobContext(ctx, options, WithCorrelationKey(key)); err != nil {
+		t.Fatalf("Resource.CreateJobContext() error = %v", err)
+	}
+	entry, has, err := r.registry.Lookup(ctx, key)
+	if err != nil || has == false {
+		t.Fatalf("JobRegistry.Lookup() = %v, %v", has, err)
+	}
+	if entry.JobID != "7505fEXAMPLE4C2AAM" || entry.Key != key || entry.CreatedAt.IsZero() {
+		t.Errorf("JobRegistry.Lookup() = %+v", entry)
+	}
+	defaults := options
+	if err := (&Job{}).formatOptions(&defaults); err != nil {
+		t.Fatalf("Job.formatOptions() error = %v", err)
+	}
+	digest, err := optionsDigest(defaults)
+	if err != nil {
+		t.Fatalf("optionsDigest() error = %v", err)
+	}
+	if entry.OptionsDigest != digest {
+		t.Errorf("JobRegistry.Lookup() digest = %v, want %v", entry.OptionsDigest, digest)
+	}
+
+	job, err := r.FindJobByKey(ctx, key)
+	if err != nil {
+		t.Fatalf("Resource.FindJobByKey() error = %v", err)
+	}
+	if job.info.State != UpdateComplete {
+		t.Errorf("Resource.FindJobByKey() state = %v, want %v", job.info.State, UpdateComplete)
+	}
+
+	deleted = true
+	if _, err := r.FindJobByKey(ctx, key); errors.Is(err, ErrJobNotFound) == false {
+		t.Errorf("Resource.FindJobByKey() error = %v, want %v", err, ErrJobNotFound)
+	}
+	if _, has, _ := r.registry.Lookup(ctx, key); has {
+		t.Errorf("Resource.FindJobByKey() did not remove the stale entry")
+	}
+}
+
+func TestResource_CreateJob_withoutKey(t *testing.T) {
+	registry := NewMemoryRegistry()
+	r, err := NewResource(&mockSessionFormatter{
+		url: "https://test.salesforce.com",
+		client: mockHTTPClient(func(req *http.Request) *http.Response {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"id": "7505fEXAMPLE4C2AAM"}`)),
+				Header:     make(http.Header),
+			}
+		}),
+	}, WithJobRegistry(registry))
+	if err != nil {
+		t.Fatalf("NewResource() error = %v", err)
+	}
+	if _, err := r.CreateJob(Options{Object: "Account", Operation: Insert}); err != nil {
+		t.Fatalf("Resource.CreateJob() error = %v", err)
+	}
+	if len(registry.entries) != 0 {
+		t.Errorf("Resource.CreateJob() registered %v", registry.entries)
+	}
+}
@@ -0,0 +1,197 @@
+package bulk
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"time"
+)
+
+// SlowCall is passed to the slow call warning when a result download
+// exceeds the threshold.
+//
+// Call is the name of the job method, like SuccessfulRecords.
+//
+// JobID is the ID of the job the results were downloaded for.
+//
+// Elapsed is the time the call took.
+//
+// Threshold is the threshold the call exceeded.
+type SlowCall struct {
+	Call      string
+	JobID     string
+	Elapsed   time.Duration
+	Threshold time.Duration
+}
+
+// RowLengthError is returned when a row of the results does not have a value
+// for each of the header's fields.
+//
+// Row is the number of the row, starting at one for the first row after the
+// header.
+//
+// Line is the line of the row in the results.
+//
+// Fields is the number of fields in the header.
+//
+// Values is the number of values in the row.
+type RowLengthError struct {
+	Row    int
+	Line   int
+	Fields int
+	Values int
+}
+
+func (e *RowLengthError) Error() string {
+	return fmt.Sprintf("bulk results: row %d (line %d) has %d values, want %d", e.Row, e.Line, e.Values, e.Fields)
+}
+
+// ResultOption is an option for the job result downloads.
+type ResultOption func(*resultOptions)
+
+type resultOptions struct {
+	ctx             context.Context
+	timeout         time.Duration
+	threshold       time.Duration
+	warning         func(SlowCall)
+	strictRowLength bool
+	fieldTypes      map[string]FieldType
+}
+
+// WithContext will use the context for the result download request.
+func WithContext(ctx context.Context) ResultOption {
+	return func(opts *resultOptions) {
+		opts.ctx = ctx
+	}
+}
+
+// WithTimeout will set the deadline of the result download.  The deadline is
+// only applied when the context does not already have one.  The download is
+// still limited by the Timeout of the session's HTTP client, which covers
+// reading the body, so the deadline can only shorten it.  A download that takes
+// longer than the client's timeout needs a session whose client has a longer
+// timeout, or none.
+func WithTimeout(timeout time.Duration) ResultOption {
+	return func(opts *resultOptions) {
+		opts.timeout = timeout
+	}
+}
+
+// WithSlowCallWarning will call the warning function when the result download
+// takes longer than the threshold.
+func WithSlowCallWarning(threshold time.Duration, warning func(SlowCall)) ResultOption {
+	return func(opts *resultOptions) {
+		opts.threshold = threshold
+		opts.warning = warning
+	}
+}
+
+// WithStrictRowLength will return a RowLengthError for the rows with fewer values
+// than the header's fields.  By default, the missing values are empty.  The rows
+// with more values than the header's fields are always a RowLengthError.
+func WithStrictRowLength() ResultOption {
+	return func(opts *resultOptions) {
+		opts.strictRowLength = true
+	}
+}
+
+// WithFieldTypes will coerce the records' fields to the field types, in the
+// Values of the records, like the SetFieldTypes of a query job.  A value that
+// can not be coerced fails the download with a FieldTypeError, and a field type
+// that is not known is an error.  By default, the records only have the fields'
+// strings.
+func WithFieldTypes(types map[string]FieldType) ResultOption {
+	return func(opts *resultOptions) {
+		opts.fieldTypes = types
+	}
+}
+
+func newResultOptions(options []ResultOption) *resultOptions {
+	opts := &resultOptions{
+		ctx: context.Background(),
+	}
+	for _, option := range options {
+		option(opts)
+	}
+	if opts.ctx == nil {
+		opts.ctx = context.Background()
+	}
+	return opts
+}
+
+// values returns the fields coerced to the field types of the options, or nil
+// when the options do not have field types.
+func (opts *resultOptions) values(row int, fields map[string]string) (map[string]interface{}, error) {
+	if opts.fieldTypes == nil {
+		return nil, nil
+	}
+	return coerceRecord(row, fields, opts.fieldTypes)
+}
+
+func (opts *resultOptions) context() (context.Context, context.CancelFunc) {
+	if opts.timeout <= 0 {
+		return opts.ctx, func() {}
+	}
+	if _, has := opts.ctx.Deadline(); has {
+		return opts.ctx, func() {}
+	}
+	return context.WithTimeout(opts.ctx, opts.timeout)
+}
+
+func (opts *resultOptions) warnSlow(call, jobID string, start time.Time) {
+	if opts.warning == nil || opts.threshold <= 0 {
+		return
+	}
+	if elapsed := time.Since(start); elapsed > opts.threshold {
+		opts.warning(SlowCall{
+			Call:      call,
+			JobID:     jobID,
+			Elapsed:   elapsed,
+			Threshold: opts.threshold,
+		})
+	}
+}
+
+// rowReader reads the rows of the results, checking the number of values of
+// each row against the number of fields of the header.  The rows of JSON
+// results are decoded with the header, so the reader only returns them.
+type rowReader struct {
+	reader *csv.Reader
+	rows   [][]string
+	fields int
+	strict bool
+	row    int
+}
+
+// Read returns the next row.  A row with fewer values than fields has empty
+// values for the missing fields, unless the reader is strict.
+func (r *rowReader) Read() ([]string, error) {
+	if r.reader == nil {
+		if len(r.rows) == 0 {
+			return nil, io.EOF
+		}
+		values := r.rows[0]
+		r.rows = r.rows[1:]
+		r.row++
+		return values, nil
+	}
+	values, err := r.reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	r.row++
+	if len(values) == r.fields {
+		return values, nil
+	}
+	if len(values) > r.fields || r.strict {
+		line, _ := r.reader.FieldPos(0)
+		return nil, &RowLengthError{
+			Row:    r.row,
+			Line:   line,
+			Fields: r.fields,
+			Values: len(values),
+		}
+	}
+	return append(values, make([]string, r.fields-len(values))...), nil
+}
@@ -0,0 +1,315 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJob_resultOptions(t *testing.T) {
+	parent, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	tests := []struct {
+		name         string
+		options      []ResultOption
+		wantDeadline bool
+		wantMin      time.Duration
+		wantMax      time.Duration
+	}{
+		{
+			name:         "no options",
+			options:      nil,
+			wantDeadline: false,
+		},
+		{
+			name: "timeout without parent deadline",
+			options: []ResultOption{
+				WithTimeout(time.Hour),
+			},
+			wantDeadline: true,
+			wantMin:      59 * time.Minute,
+			wantMax:      time.Hour,
+		},
+		{
+			name: "timeout with parent deadline",
+			options: []ResultOption{
+				WithContext(parent),
+				WithTimeout(time.Hour),
+			},
+			wantDeadline: true,
+			wantMin:      0,
+			wantMax:      time.Minute,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deadline time.Time
+			var has bool
+			job := &Job{
+				session: &mockSessionFormatter{
+					url: "https://test.salesforce.com",
+					client: mockHTTPClient(func(req *http.Request) *http.Response {
+						deadline, has = req.Context().Deadline()
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Status:     "Good",
+							Body:       io.NopCloser(strings.NewReader("sf__Id,sf__Error,Name\n")),
+							Header:     make(http.Header),
+						}
+					}),
+				},
+				info: Response{
+					ID: "1234",
+				},
+			}
+			if _, err := job.FailedRecords(tt.options...); err != nil {
+				t.Fatalf("Job.FailedRecords() error = %v", err)
+			}
+			if has != tt.wantDeadline {
+				t.Fatalf("request deadline = %v, want %v", has, tt.wantDeadline)
+			}
+			if has {
+				remaining := time.Until(deadline)
+				if remaining < tt.wantMin || remaining > tt.wantMax {
+					t.Errorf("request deadline in %v, want between %v and %v", remaining, tt.wantMin, tt.wantMax)
+				}
+			}
+		})
+	}
+}
+
+func TestJob_resultSlowCallWarning(t *testing.T) {
+	tests := []struct {
+		name      string
+		delay     time.Duration
+		threshold time.Duration
+		wantWarn  bool
+	}{
+		{
+			name:      "slow",
+			delay:     20 * time.Millisecond,
+			threshold: time.Millisecond,
+			wantWarn:  true,
+		},
+		{
+			name:      "fast",
+			delay:     0,
+			threshold: time.Minute,
+			wantWarn:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := &Job{
+				session: &mockSessionFormatter{
+					url: "https://test.salesforce.com",
+					client: mockHTTPClient(func(req *http.Request) *http.Response {
+						time.Sleep(tt.delay)
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Status:     "Good",
+							Body:       io.NopCloser(strings.NewReader("sf__Created,sf__Id,Name\ntrue,2345,name\n")),
+							Header:     make(http.Header),
+						}
+					}),
+				},
+				info: Response{
+					ID: "1234",
+				},
+			}
+			var calls []SlowCall
+			_, err := job.SuccessfulRecords(WithSlowCallWarning(tt.threshold, func(call SlowCall) {
+				calls = append(calls, call)
+			}))
+			if err != nil {
+				t.Fatalf("Job.SuccessfulRecords() error = %v", err)
+			}
+			if (len(calls) == 1) != tt.wantWarn {
+				t.Fatalf("slow call warnings = %v, want warning %v", calls, tt.wantWarn)
+			}
+			if tt.wantWarn {
+				call := calls[0]
+				if call.Call != "SuccessfulRecords" || call.JobID != "1234" || call.Elapsed < tt.delay || call.Threshold != tt.threshold {
+					t.Errorf("slow call warning = %+v", call)
+				}
+			}
+		})
+	}
+}
+
+func TestJob_resultRowLength(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		options []ResultOption
+		want    []map[string]string
+		wantErr *RowLengthError
+	}{
+		{
+			name: "short row",
+			body: "\"sf__Id\",\"sf__Error\",\"Name\",\"Site\"\n" +
+				"\"001D000000IRFmaIAH\",\"REQUIRED_FIELD_MISSING\",\"Acme\"\n",
+			want: []map[string]string{
+				{"Name": "Acme", "Site": ""},
+			},
+		},
+		{
+			name: "short row strict",
+			body: "\"sf__Id\",\"sf__Error\",\"Name\",\"Site\"\n" +
+				"\"001D000000IRFmaIAH\",\"REQUIRED_FIELD_MISSING\",\"Acme\",\"East\"\n" +
+				"\"001D000000IRFmaIAI\",\"REQUIRED_FIELD_MISSING\"\n",
+			options: []ResultOption{WithStrictRowLength()},
+			wantErr: &RowLengthError{Row: 2, Line: 3, Fields: 4, Values: 2},
+		},
+		{
+			name: "long row",
+			body: "\"sf__Id\",\"sf__Error\",\"Name\"\n" +
+				"\"001D000000IRFmaIAH\",\"REQUIRED_FIELD_MISSING\",\"Acme\",\"East\"\n",
+			wantErr: &RowLengthError{Row: 1, Line: 2, Fields: 3, Values: 4},
+		},
+		{
+			name: "empty lines",
+			body: "\"sf__Id\",\"sf__Error\",\"Name\"\n" +
+				"\n" +
+				"\"001D000000IRFmaIAH\",\"REQUIRED_FIELD_MISSING\",\"Acme\"\n" +
+				"\n\n" +
+				"\"001D000000IRFmaIAI\",\"REQUIRED_FIELD_MISSING\",\"Globex\"\n",
+			want: []map[string]string{
+				{"Name": "Acme"},
+				{"Name": "Globex"},
+			},
+		},
+		{
+			name: "header only",
+			body: "\"sf__Id\",\"sf__Error\",\"Name\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := resultsJob(tt.body).FailedRecords(tt.options...)
+			if tt.wantErr != nil {
+				var rowErr *RowLengthError
+				if errors.As(err, &rowErr) == false {
+					t.Fatalf("Job.FailedRecords() error = %v, want %v", err, tt.wantErr)
+				}
+				if reflect.DeepEqual(rowErr, tt.wantErr) == false {
+					t.Errorf("Job.FailedRecords() error = %+v, want %+v", rowErr, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Job.FailedRecords() error = %v", err)
+			}
+			var got []map[string]string
+			for _, record := range records {
+				got = append(got, record.Fields)
+			}
+			if reflect.DeepEqual(got, tt.want) == false {
+				t.Errorf("Job.FailedRecords() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJob_resultRowLength_allResults(t *testing.T) {
+	short := "\"sf__Id\",\"sf__Created\",\"Name\",\"Site\"\n\"001D000000IRFmaIAH\",\"true\"\n"
+	successful, err := resultsJob(short).SuccessfulRecords()
+	if err != nil {
+		t.Fatalf("Job.SuccessfulRecords() error = %v", err)
+	}
+	if want := map[string]string{"Name": "", "Site": ""}; reflect.DeepEqual(successful[0].Fields, want) == false {
+		t.Errorf("Job.SuccessfulRecords() = %v, want %v", successful[0].Fields, want)
+	}
+
+	unprocessed, err := resultsJob("\"Name\",\"Site\"\n\"Acme\"\n").UnprocessedRecords()
+	if err != nil {
+		t.Fatalf("Job.UnprocessedRecords() error = %v", err)
+	}
+	if want := map[string]string{"Name": "Acme", "Site": ""}; reflect.DeepEqual(unprocessed[0].Fields, want) == false {
+		t.Errorf("Job.UnprocessedRecords() = %v, want %v", unprocessed[0].Fields, want)
+	}
+
+	if _, err := resultsJob("\"Name\"\n\"Acme\"\n").SuccessfulRecords(); err == nil {
+		t.Errorf("Job.SuccessfulRecords() error = nil for a header without %s", sfCreated)
+	}
+
+	query := &QueryJob{
+		session: resultsJob("\"Id\",\"Name\"\n\"001D000000IRFmaIAH\",\"Acme\",\"East\"\n").session,
+		info:    Response{ID: "750R0000000zlh9IAA"},
+	}
+	_, err = query.Results(context.Background(), "", 0)
+	var rowErr *RowLengthError
+	if errors.As(err, &rowErr) == false {
+		t.Errorf("QueryJob.Results() error = %v, want a RowLengthError", err)
+	}
+}
+
+func TestJob_emptyResults(t *testing.T) {
+	bodies := []struct {
+		name        string
+		successful  string
+		failed      string
+		unprocessed string
+		query       string
+		want        int
+	}{
+		{
+			name: "empty body",
+		},
+		{
+			name:        "header only",
+			successful:  "\"sf__Id\",\"sf__Created\",\"Name\"\n",
+			failed:      "\"sf__Id\",\"sf__Error\",\"Name\"\n",
+			unprocessed: "\"Name\"\n",
+			query:       "\"Id\",\"Name\"\n",
+		},
+		{
+			name:        "header and rows",
+			successful:  "\"sf__Id\",\"sf__Created\",\"Name\"\n\"001D000000IRFmaIAH\",\"true\",\"Acme\"\n",
+			failed:      "\"sf__Id\",\"sf__Error\",\"Name\"\n\"\",\"REQUIRED_FIELD_MISSING:Name\",\"\"\n",
+			unprocessed: "\"Name\"\n\"Acme\"\n",
+			query:       "\"Id\",\"Name\"\n\"001D000000IRFmaIAH\",\"Acme\"\n",
+			want:        1,
+		},
+	}
+	for _, tt := range bodies {
+		t.Run(tt.name, func(t *testing.T) {
+			successful, err := resultsJob(tt.successful).SuccessfulRecords()
+			if err != nil || successful == nil || len(successful) != tt.want {
+				t.Errorf("Job.SuccessfulRecords() = %#v, %v, want %d records", successful, err, tt.want)
+			}
+			failed, err := resultsJob(tt.failed).FailedRecords()
+			if err != nil || failed == nil || len(failed) != tt.want {
+				t.Errorf("Job.FailedRecords() = %#v, %v, want %d records", failed, err, tt.want)
+			}
+			unprocessed, err := resultsJob(tt.unprocessed).UnprocessedRecords()
+			if err != nil || unprocessed == nil || len(unprocessed) != tt.want {
+				t.Errorf("Job.UnprocessedRecords() = %#v, %v, want %d records", unprocessed, err, tt.want)
+			}
+			query := &QueryJob{
+				session: resultsJob(tt.query).session,
+				info:    Response{ID: "750R0000000zlh9IAA"},
+			}
+			results, err := query.Results(context.Background(), "", 0)
+			if err != nil || results.Records == nil || len(results.Records) != tt.want {
+				t.Errorf("QueryJob.Results() = %#v, %v, want %d records", results.Records, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestJob_resultsParseFailure(t *testing.T) {
+	if _, err := resultsJob("\"sf__Id\",\"sf__Created\",\"Name\"\n\"001D000000IRFmaIAH\",\"maybe\",\"Acme\"\n").SuccessfulRecords(); err == nil {
+		t.Error("Job.SuccessfulRecords() error = nil for an invalid created flag")
+	}
+	if _, err := resultsJob("\"sf__Id\",\"sf__Error\",\"Name\"\n\"unterminated\n").FailedRecords(); err == nil {
+		t.Error("Job.FailedRecords() error = nil for an unterminated quote")
+	}
+}
@@ -0,0 +1,139 @@
+// Package results provides a record outcome that is shared by the bulk API
+// versions, so the per-record results can be handled with one type.
+package results
+
+import (
+	"encoding/json"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// ErrorSeparator joins the messages of a record outcome with more than one error.
+const ErrorSeparator = "; "
+
+var statusCodePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+// Error is an error of a record.
+//
+// StatusCode is the Salesforce status code, like REQUIRED_FIELD_MISSING.
+//
+// Message is the error message.
+//
+// Fields are the fields that caused the error, if they are known.
+type Error struct {
+	StatusCode string
+	Message    string
+	Fields     []string
+}
+
+// String returns the error as "STATUS_CODE:message", which is the format
+// of the bulk 2.0 error column.
+func (e Error) String() string {
+	if e.StatusCode == "" {
+		return e.Message
+	}
+	return e.StatusCode + ":" + e.Message
+}
+
+// ParseError parses a bulk 2.0 error column value.  The status code is the
+// text before the first colon, if it is a status code, and the rest is the
+// message, so the error's String is the value.
+func ParseError(value string) Error {
+	if idx := strings.Index(value, ":"); idx > 0 && statusCodePattern.MatchString(value[:idx]) {
+		return Error{
+			StatusCode: value[:idx],
+			Message:    value[idx+1:],
+		}
+	}
+	return Error{
+		Message: value,
+	}
+}
+
+// RecordOutcome is the outcome of a record of a bulk job.
+//
+// ID is the Salesforce ID of the record.  It is empty if the record failed to
+// be created.
+//
+// Created is whether the record was created.
+//
+// Success is whether the record was processed successfully.
+//
+// Errors are the errors of a failed record.
+//
+// Fields are the fields of the record that was uploaded, if they are known.
+type RecordOutcome struct {
+	ID      string
+	Created bool
+	Success bool
+	Errors  []Error
+	Fields  map[string]string
+}
+
+// ErrorMessage returns the errors of the record joined with the ErrorSeparator.
+func (o RecordOutcome) ErrorMessage() string {
+	messages := make([]string, len(o.Errors))
+	for idx, err := range o.Errors {
+		messages[idx] = err.String()
+	}
+	return strings.Join(messages, ErrorSeparator)
+}
+
+// CopyFields returns a copy of the fields, or nil if there are none.
+func CopyFields(fields map[string]string) map[string]string {
+	if fields == nil {
+		return nil
+	}
+	copied := make(map[string]string, len(fields))
+	for field, value := range fields {
+		copied[field] = value
+	}
+	return copied
+}
+
+// V1Error is an error of a bulk 1.0 result record.
+type V1Error struct {
+	StatusCode string   `json:"statusCode"`
+	Message    string   `json:"message"`
+	Fields     []string `json:"fields"`
+}
+
+// V1ResultRecord is a bulk 1.0 batch result record in the JSON format.
+type V1ResultRecord struct {
+	ID      string    `json:"id"`
+	Success bool      `json:"success"`
+	Created bool      `json:"created"`
+	Errors  []V1Error `json:"errors"`
+}
+
+// AsOutcome returns the result record as a record outcome.  The bulk 1.0
+// results do not have the fields of the record.
+func (r V1ResultRecord) AsOutcome() RecordOutcome {
+	outcome := RecordOutcome{
+		ID:      r.ID,
+		Created: r.Created,
+		Success: r.Success,
+	}
+	for _, err := range r.Errors {
+		outcome.Errors = append(outcome.Errors, Error{
+			StatusCode: err.StatusCode,
+			Message:    err.Message,
+			Fields:     err.Fields,
+		})
+	}
+	return outcome
+}
+
+// DecodeV1 decodes the bulk 1.0 JSON batch results into record outcomes.
+func DecodeV1(reader io.Reader) ([]RecordOutcome, error) {
+	var records []V1ResultRecord
+	if err := json.NewDecoder(reader).Decode(&records); err != nil {
+		return nil, err
+	}
+	outcomes := make([]RecordOutcome, len(records))
+	for idx, record := range records {
+		outcomes[idx] = record.AsOutcome()
+	}
+	return outcomes, nil
+}
@@ -0,0 +1,118 @@
+package results
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  Error
+	}{
+		{
+			name:  "status code",
+			value: "REQUIRED_FIELD_MISSING:Required fields are missing: [Name]:Name --",
+			want: Error{
+				StatusCode: "REQUIRED_FIELD_MISSING",
+				Message:    "Required fields are missing: [Name]:Name --",
+			},
+		},
+		{
+			name:  "no status code",
+			value: "Some error: with a colon",
+			want: Error{
+				Message: "Some error: with a colon",
+			},
+		},
+		{
+			name:  "no colon",
+			value: "Some error",
+			want: Error{
+				Message: "Some error",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseError(tt.value)
+			if reflect.DeepEqual(got, tt.want) == false {
+				t.Errorf("ParseError() = %+v, want %+v", got, tt.want)
+			}
+			if got.String() != tt.value {
+				t.Errorf("Error.String() = %v, want %v", got.String(), tt.value)
+			}
+		})
+	}
+}
+
+func TestDecodeV1(t *testing.T) {
+	body := `[
+		{
+			"id": "001D000000ISUr3IAH",
+			"success": true,
+			"created": true,
+			"errors": []
+		},
+		{
+			"id": null,
+			"success": false,
+			"created": false,
+			"errors": [
+				{
+					"statusCode": "REQUIRED_FIELD_MISSING",
+					"message": "Required fields are missing: [Name]",
+					"fields": ["Name"]
+				},
+				{
+					"statusCode": "INVALID_EMAIL_ADDRESS",
+					"message": "Email: invalid email address",
+					"fields": ["Email"]
+				}
+			]
+		}
+	]`
+
+	got, err := DecodeV1(strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("DecodeV1() error = %v", err)
+	}
+	want := []RecordOutcome{
+		{
+			ID:      "001D000000ISUr3IAH",
+			Created: true,
+			Success: true,
+		},
+		{
+			Errors: []Error{
+				{
+					StatusCode: "REQUIRED_FIELD_MISSING",
+					Message:    "Required fields are missing: [Name]",
+					Fields:     []string{"Name"},
+				},
+				{
+					StatusCode: "INVALID_EMAIL_ADDRESS",
+					Message:    "Email: invalid email address",
+					Fields:     []string{"Email"},
+				},
+			},
+		},
+	}
+	if reflect.DeepEqual(got, want) == false {
+		t.Errorf("DecodeV1() = %+v, want %+v", got, want)
+	}
+
+	wantMessage := "REQUIRED_FIELD_MISSING:Required fields are missing: [Name]; INVALID_EMAIL_ADDRESS:Email: invalid email address"
+	if message := got[1].ErrorMessage(); message != wantMessage {
+		t.Errorf("RecordOutcome.ErrorMessage() = %v, want %v", message, wantMessage)
+	}
+	if message := got[0].ErrorMessage(); message != "" {
+		t.Errorf("RecordOutcome.ErrorMessage() = %v, want empty", message)
+	}
+
+	if _, err := DecodeV1(strings.NewReader(`{`)); err == nil {
+		t.Errorf("DecodeV1() expected an error")
+	}
+}
@@ -0,0 +1,124 @@
+package bulk
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+)
+
+// RowIndex maps the field values of the uploaded records to their row numbers.
+// The row numbers are 1-based and do not count the header, so the first record
+// after the header is row 1.
+type RowIndex struct {
+	fields []string
+	rows   map[string][]int
+	count  int
+}
+
+// FailedRecordRow is the failed record with the row numbers of the uploaded
+// records that it matches.
+//
+// Row is the row number of the uploaded record.  It is zero when no row or
+// more than one row matched.
+//
+// Rows are all of the row numbers that matched.  More than one row means that
+// the upload had duplicate rows and the failed record is ambiguous.
+type FailedRecordRow struct {
+	FailedRecord
+	Row  int
+	Rows []int
+}
+
+// Ambiguous returns true when the failed record matched more than one row.
+func (r FailedRecordRow) Ambiguous() bool {
+	return len(r.Rows) > 1
+}
+
+func newRowIndex(fields []string) *RowIndex {
+	return &RowIndex{
+		fields: fields,
+		rows:   make(map[string][]int),
+	}
+}
+
+// NewRowIndex will build the row index from the uploaded CSV.  The first
+// line of the CSV must be the header.
+func NewRowIndex(upload io.Reader, delimiter ColumnDelimiter) (*RowIndex, error) {
+	if upload == nil {
+		return nil, errors.New("bulk row index: upload can not be nil")
+	}
+
+	job := &Job{
+		info: Response{
+			ColumnDelimiter: delimiter,
+		},
+	}
+	reader := csv.NewReader(upload)
+	reader.Comma = job.delimiter()
+
+	fields, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	index := newRowIndex(fields)
+	for {
+		values, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		index.add(values)
+	}
+	return index, nil
+}
+
+// Rows returns the row numbers of the uploaded records with the field values.
+func (index *RowIndex) Rows(fields map[string]string) []int {
+	values := make([]string, len(index.fields))
+	for idx, field := range index.fields {
+		values[idx] = fields[field]
+	}
+	return index.rows[rowKey(values)]
+}
+
+func (index *RowIndex) add(values []string) {
+	index.count++
+	key := rowKey(values)
+	index.rows[key] = append(index.rows[key], index.count)
+}
+
+func rowKey(values []string) string {
+	return strings.Join(values, "\x00")
+}
+
+// FailedRecordsWithRowNumbers returns the failed records for the job with the
+// row numbers of the uploaded records.  The failed records are matched to the
+// uploaded rows by their field values, so duplicate rows in the upload are
+// reported as ambiguous.  The index is built by the formatter with WithRowIndex
+// or from the uploaded CSV with NewRowIndex.
+func (j *Job) FailedRecordsWithRowNumbers(ctx context.Context, index *RowIndex) ([]FailedRecordRow, error) {
+	if index == nil {
+		return nil, errors.New("bulk job: row index is not enabled")
+	}
+
+	records, err := j.FailedRecords(WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]FailedRecordRow, len(records))
+	for idx, record := range records {
+		rows[idx] = FailedRecordRow{
+			FailedRecord: record,
+			Rows:         index.Rows(record.Fields),
+		}
+		if len(rows[idx].Rows) == 1 {
+			rows[idx].Row = rows[idx].Rows[0]
+		}
+	}
+	return rows, nil
+}
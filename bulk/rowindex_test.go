@@ -0,0 +1,133 @@
+package bulk
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJob_FailedRecordsWithRowNumbers(t *testing.T) {
+	job := &Job{
+		session: &mockSessionFormatter{
+			url: "https://test.salesforce.com",
+			client: mockHTTPClient(func(req *http.Request) *http.Response {
+				resp := "\"sf__Id\",\"sf__Error\",\"Name\",\"Site\"\n" +
+					"\"\",\"REQUIRED_FIELD_MISSING\",\"name 2\",\"\"\n" +
+					"\"\",\"DUPLICATE_VALUE\",\"name 1\",\"good site\"\n" +
+					"\"\",\"INVALID_FIELD\",\"name 9\",\"bad site\"\n"
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Status:     "Good",
+					Body:       io.NopCloser(strings.NewReader(resp)),
+					Header:     make(http.Header),
+				}
+			}),
+		},
+		info: Response{
+			ID: "1234",
+		},
+	}
+
+	formatter, err := NewFormatter(job, []string{"Name", "Site"}, WithRowIndex())
+	if err != nil {
+		t.Fatalf("NewFormatter() error = %v", err)
+	}
+	err = formatter.Add(
+		&testRecord{fields: map[string]interface{}{"Name": "name 1", "Site": "good site"}},
+		&testRecord{fields: map[string]interface{}{"Name": "name 2"}},
+		&testRecord{fields: map[string]interface{}{"Name": "name 1", "Site": "good site"}},
+	)
+	if err != nil {
+		t.Fatalf("Formatter.Add() error = %v", err)
+	}
+	uploaded, err := NewRowIndex(formatter.Reader(), Comma)
+	if err != nil {
+		t.Fatalf("NewRowIndex() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		index    *RowIndex
+		wantRows []FailedRecordRow
+		wantErr  bool
+	}{
+		{
+			name:  "formatter index",
+			index: formatter.RowIndex(),
+			wantRows: []FailedRecordRow{
+				{
+					FailedRecord: FailedRecord{
+						Error: "REQUIRED_FIELD_MISSING",
+						JobRecord: JobRecord{
+							UnprocessedRecord: UnprocessedRecord{
+								Fields: map[string]string{"Name": "name 2", "Site": ""},
+							},
+						},
+					},
+					Row:  2,
+					Rows: []int{2},
+				},
+				{
+					FailedRecord: FailedRecord{
+						Error: "DUPLICATE_VALUE",
+						JobRecord: JobRecord{
+							UnprocessedRecord: UnprocessedRecord{
+								Fields: map[string]string{"Name": "name 1", "Site": "good site"},
+							},
+						},
+					},
+					Row:  0,
+					Rows: []int{1, 3},
+				},
+				{
+					FailedRecord: FailedRecord{
+						Error: "INVALID_FIELD",
+						JobRecord: JobRecord{
+							UnprocessedRecord: UnprocessedRecord{
+								Fields: map[string]string{"Name": "name 9", "Site": "bad site"},
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "disabled index",
+			index:   nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := job.FailedRecordsWithRowNumbers(context.Background(), tt.index)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Job.FailedRecordsWithRowNumbers() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.wantRows) {
+				t.Errorf("Job.FailedRecordsWithRowNumbers() = %v, want %v", got, tt.wantRows)
+			}
+		})
+	}
+
+	if !reflect.DeepEqual(uploaded.rows, formatter.RowIndex().rows) {
+		t.Errorf("NewRowIndex() rows = %v, want %v", uploaded.rows, formatter.RowIndex().rows)
+	}
+	if got := (FailedRecordRow{Rows: []int{1, 3}}).Ambiguous(); got == false {
+		t.Errorf("FailedRecordRow.Ambiguous() = %v, want true", got)
+	}
+}
+
+func TestFormatter_RowIndexDisabled(t *testing.T) {
+	formatter, err := NewFormatter(&Job{}, []string{"Name"})
+	if err != nil {
+		t.Fatalf("NewFormatter() error = %v", err)
+	}
+	if index := formatter.RowIndex(); index != nil {
+		t.Errorf("Formatter.RowIndex() = %v, want nil", index)
+	}
+}
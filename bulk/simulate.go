@@ -0,0 +1,322 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/namely/go-sfdc/v3/sobject"
+)
+
+// simulationNullRate is the rate of records without a value for a required
+// field above which the simulation warns.
+const simulationNullRate = 0.1
+
+// SimulationReport is the outcome of a simulated ingest.  It can be marshaled
+// to JSON, so it can gate a CI pipeline.
+//
+// Records is the number of records that were simulated.
+//
+// Rejected is the number of records that the formatter rejected, which are
+// not uploaded.
+//
+// Bytes is the size of the CSV data of the records that were not rejected,
+// including the header.
+//
+// Chunks is the plan of the jobs that the CSV data is split into.
+//
+// Issues are the problems of the records, in record order.
+//
+// FieldIssues are the problems of the fields, which fail every record.
+//
+// Warnings are the problems that do not fail the records by themselves.
+type SimulationReport struct {
+	Records     int                 `json:"records"`
+	Rejected    int                 `json:"rejected"`
+	Bytes       int                 `json:"bytes"`
+	Chunks      []ChunkPlan         `json:"chunks"`
+	Issues      []RecordIssue       `json:"issues"`
+	FieldIssues []FieldIssue        `json:"fieldIssues"`
+	Warnings    []SimulationWarning `json:"warnings"`
+}
+
+// Valid returns whether the simulation did not find any record or field
+// issues.
+func (r SimulationReport) Valid() bool {
+	return len(r.Issues) == 0 && len(r.FieldIssues) == 0
+}
+
+// ChunkPlan is a job of the simulated ingest.
+//
+// Index is the position of the chunk, starting at zero.
+//
+// Records is the number of records in the chunk.
+//
+// Bytes is the size of the chunk's CSV data, including the header.
+type ChunkPlan struct {
+	Index   int `json:"index"`
+	Records int `json:"records"`
+	Bytes   int `json:"bytes"`
+}
+
+// RecordIssue is a problem of a record.
+//
+// Index is the position of the record in the records.
+//
+// Field is the field of the problem.  It is empty when the problem is of the
+// record as a whole.
+//
+// Reason is the problem.
+type RecordIssue struct {
+	Index  int    `json:"index"`
+	Field  string `json:"field,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// FieldIssue is a problem of a field, like a field that the object does not
+// have.
+type FieldIssue struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+// SimulationWarning is a problem that does not fail the records by itself,
+// like a high rate of records without a value for a required field.
+type SimulationWarning struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// Simulate will check an ingest of the records without creating a job.  The
+// options are checked like CreateJob, the records are formatted like Add and
+// the CSV data is split like IngestAll, so the report has the size of the data
+// and the plan of the jobs.  If the fields are empty, they are all of the
+// records' fields, sorted, like IngestAll.
+//
+// With the object's describe, the fields are checked against the object for
+// the operation, and each record's values against the required fields, the
+// field lengths and the restricted picklists.  The describe is optional, and
+// no API calls are made.
+func Simulate(ctx context.Context, options Options, fields []string, records []Record, describe *sobject.DescribeValue, chunkOpts ...ChunkOption) (SimulationReport, error) {
+	if err := ctx.Err(); err != nil {
+		return SimulationReport{}, err
+	}
+	if err := (&Job{}).formatOptions(&options); err != nil {
+		return SimulationReport{}, err
+	}
+	if len(records) == 0 {
+		return SimulationReport{}, errors.New("bulk simulate: records can not be empty")
+	}
+	if len(fields) == 0 {
+		fields = ingestFields(records)
+	}
+	if len(fields) == 0 {
+		return SimulationReport{}, errors.New("bulk simulate: records do not have any fields")
+	}
+	if describe != nil && describe.Name != "" && strings.EqualFold(describe.Name, options.Object) == false {
+		return SimulationReport{}, fmt.Errorf("bulk simulate: describe of %s is not of the job's object %s", describe.Name, options.Object)
+	}
+
+	report := SimulationReport{
+		Records: len(records),
+	}
+	formatter, err := NewFormatter(&Job{
+		info: Response{
+			ColumnDelimiter: options.ColumnDelimiter,
+			LineEnding:      options.LineEnding,
+			Operation:       options.Operation,
+		},
+	}, fields)
+	if err != nil {
+		return SimulationReport{}, err
+	}
+	err = formatter.Add(records...)
+	var multiErr *MultiError
+	switch {
+	case errors.As(err, &multiErr):
+		for _, recordErr := range multiErr.Errors {
+			report.Rejected++
+			report.Issues = append(report.Issues, RecordIssue{
+				Index:  recordErr.Index,
+				Field:  recordErr.Field,
+				Reason: recordErr.Reason,
+			})
+		}
+	case err != nil:
+		return SimulationReport{}, err
+	}
+
+	if describe != nil {
+		simulateDescribe(&report, options, fields, records, *describe)
+	}
+
+	data := formatter.Reader()
+	report.Bytes = int(data.Size())
+	err = SplitCSV(data, func(chunk Chunk) error {
+		report.Chunks = append(report.Chunks, ChunkPlan{
+			Index:   chunk.Index,
+			Records: chunk.Records,
+			Bytes:   len(chunk.Data),
+		})
+		return nil
+	}, chunkOpts...)
+	if err != nil {
+		return SimulationReport{}, err
+	}
+	sortRecordIssues(report.Issues)
+	return report, nil
+}
+
+// simulateDescribe adds the issues and warnings of the fields and the records
+// against the object's describe.
+func simulateDescribe(report *SimulationReport, options Options, fields []string, records []Record, describe sobject.DescribeValue) {
+	byName := make(map[string]sobject.Field, len(describe.Fields))
+	relationships := make(map[string]bool)
+	for _, field := range describe.Fields {
+		byName[strings.ToLower(field.Name)] = field
+		if field.RelationshipName != "" {
+			relationships[strings.ToLower(field.RelationshipName)] = true
+		}
+	}
+
+	var checked []sobject.Field
+	inFields := make(map[string]bool, len(fields))
+	for _, name := range fields {
+		if relationship, _, ok := strings.Cut(name, "."); ok {
+			if relationships[strings.ToLower(relationship)] == false {
+				report.FieldIssues = append(report.FieldIssues, FieldIssue{Field: name, Reason: "relationship is not a field of the object"})
+			}
+			continue
+		}
+		field, ok := byName[strings.ToLower(name)]
+		if ok == false {
+			report.FieldIssues = append(report.FieldIssues, FieldIssue{Field: name, Reason: "field is not a field of the object"})
+			continue
+		}
+		inFields[strings.ToLower(field.Name)] = true
+		if reason := fieldAccessIssue(options, field); reason != "" {
+			report.FieldIssues = append(report.FieldIssues, FieldIssue{Field: name, Reason: reason})
+			continue
+		}
+		checked = append(checked, field)
+	}
+	if options.Operation == Insert {
+		for _, field := range describe.Fields {
+			if requiredOnInsert(field) && inFields[strings.ToLower(field.Name)] == false {
+				report.FieldIssues = append(report.FieldIssues, FieldIssue{Field: field.Name, Reason: "required field is not in the fields"})
+			}
+		}
+	}
+
+	nulls := make(map[string]int)
+	for idx, record := range records {
+		if isNilRecord(record) {
+			continue
+		}
+		values := lowerCaseFields(record.Fields())
+		for _, field := range checked {
+			value, ok := values[strings.ToLower(field.Name)]
+			if _, is := value.(unchanged); is {
+				continue
+			}
+			if ok == false || value == nil {
+				nulls[field.Name]++
+				if options.Operation == Insert && requiredOnInsert(field) {
+					report.Issues = append(report.Issues, RecordIssue{Index: idx, Field: field.Name, Reason: "required field is missing"})
+				}
+				continue
+			}
+			if reason := valueIssue(field, value); reason != "" {
+				report.Issues = append(report.Issues, RecordIssue{Index: idx, Field: field.Name, Reason: reason})
+			}
+		}
+	}
+	for _, field := range checked {
+		if field.Nillable || field.Type == "boolean" || nulls[field.Name] == 0 {
+			continue
+		}
+		if rate := float64(nulls[field.Name]) / float64(len(records)); rate > simulationNullRate {
+			report.Warnings = append(report.Warnings, SimulationWarning{
+				Field:   field.Name,
+				Message: fmt.Sprintf("%d of %d records (%.0f%%) do not have a value for the required field", nulls[field.Name], len(records), rate*100),
+			})
+		}
+	}
+}
+
+// fieldAccessIssue returns why the field can not be written by the operation,
+// or an empty string if it can.
+func fieldAccessIssue(options Options, field sobject.Field) string {
+	isID := strings.EqualFold(field.Name, "Id")
+	switch options.Operation {
+	case Insert:
+		if field.Createable == false {
+			return "field is not createable"
+		}
+	case Update:
+		if field.Updateable == false && isID == false {
+			return "field is not updateable"
+		}
+	case Upsert:
+		if strings.EqualFold(field.Name, options.ExternalIDFieldName) {
+			if isID == false && field.ExternalID == false && field.IDLookup == false {
+				return "field is not an external id field"
+			}
+			return ""
+		}
+		if field.Createable == false && field.Updateable == false {
+			return "field is not createable or updateable"
+		}
+	case Delete, HardDelete:
+		if isID == false {
+			return "only the Id field is used by a delete"
+		}
+	}
+	return ""
+}
+
+// requiredOnInsert returns whether an inserted record needs a value for the
+// field.
+func requiredOnInsert(field sobject.Field) bool {
+	return field.Createable && field.Nillable == false && field.DefaultedOnCreate == false && field.Type != "boolean"
+}
+
+// valueIssue returns why the value is rejected by the field, or an empty string
+// if it is not.
+func valueIssue(field sobject.Field, value interface{}) string {
+	text := fmt.Sprintf("%v", value)
+	switch field.Type {
+	case "string", "textarea", "email", "phone", "url", "picklist", "multipicklist", "encryptedstring":
+		if field.Length > 0 && utf8.RuneCountInString(text) > field.Length {
+			return fmt.Sprintf("value of %d characters is longer than %d", utf8.RuneCountInString(text), field.Length)
+		}
+	}
+	if field.Type == "picklist" && field.RestrictedPicklist {
+		for _, picklist := range field.PicklistValues {
+			if picklist.Active && picklist.Value == text {
+				return ""
+			}
+		}
+		return fmt.Sprintf("value %q is not in the restricted picklist", text)
+	}
+	return ""
+}
+
+func lowerCaseFields(fields map[string]interface{}) map[string]interface{} {
+	lower := make(map[string]interface{}, len(fields))
+	for name, value := range fields {
+		lower[strings.ToLower(name)] = value
+	}
+	return lower
+}
+
+// sortRecordIssues orders the issues by record, keeping the order of the
+// issues of a record.
+func sortRecordIssues(issues []RecordIssue) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Index < issues[j].Index
+	})
+}
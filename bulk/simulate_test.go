@@ -0,0 +1,200 @@
+package bulk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/namely/go-sfdc/v3/sobject"
+)
+
+func accountDescribe() *sobject.DescribeValue {
+	return &sobject.DescribeValue{
+		Name: "Account",
+		Fields: []sobject.Field{
+			{Name: "Id", Type: "id", Updateable: false},
+			{Name: "Name", Type: "string", Length: 10, Createable: true, Updateable: true},
+			{Name: "Industry", Type: "picklist", Createable: true, Updateable: true, Nillable: true, RestrictedPicklist: true, PicklistValues: []sobject.PickListValue{
+				{Value: "Tech", Active: true},
+				{Value: "Retail", Active: true},
+				{Value: "Mining"},
+			}},
+			{Name: "OwnerId", Type: "reference", RelationshipName: "Owner", Createable: true, Updateable: true, DefaultedOnCreate: true},
+			{Name: "Score__c", Type: "double", Nillable: true},
+		},
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	options := Options{
+		Object:    "Account",
+		Operation: Insert,
+	}
+	records := []Record{
+		&testRecord{fields: map[string]interface{}{"Name": "Acme", "Industry": "Tech"}},
+		&testRecord{fields: map[string]interface{}{"Name": "Globex", "Industry": "Retail", "Owner.Email": "a@example.com"}},
+	}
+
+	report, err := Simulate(context.Background(), options, nil, records, accountDescribe())
+	if err != nil {
+		t.Fatalf("Simulate() error = %v", err)
+	}
+	if report.Valid() == false {
+		t.Errorf("Simulate() = %+v, want a valid report", report)
+	}
+	data := "Industry,Name,Owner.Email\nTech,Acme,\nRetail,Globex,a@example.com\n"
+	want := SimulationReport{
+		Records: 2,
+		Bytes:   len(data),
+		Chunks: []ChunkPlan{
+			{Index: 0, Records: 2, Bytes: len(data)},
+		},
+	}
+	if !reflect.DeepEqual(report, want) {
+		t.Errorf("Simulate() = %+v, want %+v", report, want)
+	}
+}
+
+func TestSimulate_issues(t *testing.T) {
+	options := Options{
+		Object:    "Account",
+		Operation: Insert,
+	}
+	records := []Record{
+		&testRecord{fields: map[string]interface{}{"Name": "Acme", "Industry": "Mining"}},
+		&testRecord{fields: map[string]interface{}{"Industry": "Tech"}},
+		nil,
+		&testRecord{fields: map[string]interface{}{"Name": "Much Too Long Name"}},
+		&testRecord{fields: map[string]interface{}{"Name": "Initech", "Score__c": 5, "Unknown__c": "x", "Parent.Name": "y"}},
+	}
+
+	report, err := Simulate(context.Background(), options, nil, records, accountDescribe())
+	if err != nil {
+		t.Fatalf("Simulate() error = %v", err)
+	}
+	if report.Valid() {
+		t.Errorf("Simulate() = %+v, want an invalid report", report)
+	}
+	if report.Records != 5 || report.Rejected != 1 {
+		t.Errorf("Simulate() records = %d, rejected = %d, want 5 and 1", report.Records, report.Rejected)
+	}
+	wantIssues := []RecordIssue{
+		{Index: 0, Field: "Industry", Reason: `value "Mining" is not in the restricted picklist`},
+		{Index: 1, Field: "Name", Reason: "required field is missing"},
+		{Index: 2, Reason: "record is nil"},
+		{Index: 3, Field: "Name", Reason: "value of 18 characters is longer than 10"},
+	}
+	if !reflect.DeepEqual(report.Issues, wantIssues) {
+		t.Errorf("Simulate() issues = %+v, want %+v", report.Issues, wantIssues)
+	}
+	wantFieldIssues := []FieldIssue{
+		{Field: "Parent.Name", Reason: "relationship is not a field of the object"},
+		{Field: "Score__c", Reason: "field is not createable"},
+		{Field: "Unknown__c", Reason: "field is not a field of the object"},
+	}
+	if !reflect.DeepEqual(report.FieldIssues, wantFieldIssues) {
+		t.Errorf("Simulate() field issues = %+v, want %+v", report.FieldIssues, wantFieldIssues)
+	}
+	wantWarnings := []SimulationWarning{
+		{Field: "Name", Message: "1 of 5 records (20%) do not have a value for the required field"},
+	}
+	if !reflect.DeepEqual(report.Warnings, wantWarnings) {
+		t.Errorf("Simulate() warnings = %+v, want %+v", report.Warnings, wantWarnings)
+	}
+	if len(report.Chunks) != 1 || report.Chunks[0].Records != 4 {
+		t.Errorf("Simulate() chunks = %+v, want the 4 records that were not rejected", report.Chunks)
+	}
+
+	body, err := json.Marshal(report)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	for _, key := range []string{`"records":5`, `"rejected":1`, `"chunks":[`, `"issues":[`, `"fieldIssues":[`, `"warnings":[`} {
+		if strings.Contains(string(body), key) == false {
+			t.Errorf("json.Marshal() = %s, want %s", body, key)
+		}
+	}
+}
+
+func TestSimulate_chunks(t *testing.T) {
+	options := Options{
+		Object:    "Account",
+		Operation: Update,
+	}
+	records := make([]Record, 10)
+	for idx := range records {
+		records[idx] = &testRecord{fields: map[string]interface{}{"Name": fmt.Sprintf("A%d", idx)}}
+	}
+
+	// the header is 5 bytes and each record 3 bytes, so two records fit in 12
+	report, err := Simulate(context.Background(), options, []string{"Name"}, records, nil, WithMaxChunkBytes(12))
+	if err != nil {
+		t.Fatalf("Simulate() error = %v", err)
+	}
+	if report.Bytes != 5+10*3 {
+		t.Errorf("Simulate() bytes = %d, want %d", report.Bytes, 5+10*3)
+	}
+	if len(report.Chunks) != 5 {
+		t.Fatalf("Simulate() chunks = %+v, want 5", report.Chunks)
+	}
+	for idx, chunk := range report.Chunks {
+		if want := (ChunkPlan{Index: idx, Records: 2, Bytes: 11}); chunk != want {
+			t.Errorf("Simulate() chunk = %+v, want %+v", chunk, want)
+		}
+	}
+
+	report, err = Simulate(context.Background(), options, []string{"Name"}, records, nil, WithMaxChunkRecords(4))
+	if err != nil {
+		t.Fatalf("Simulate() error = %v", err)
+	}
+	var got []int
+	for _, chunk := range report.Chunks {
+		got = append(got, chunk.Records)
+	}
+	if want := []int{4, 4, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Simulate() chunk records = %v, want %v", got, want)
+	}
+}
+
+func TestSimulate_errors(t *testing.T) {
+	records := []Record{
+		&testRecord{fields: map[string]interface{}{"Name": "Acme"}},
+	}
+	tests := []struct {
+		name     string
+		options  Options
+		records  []Record
+		describe *sobject.DescribeValue
+	}{
+		{
+			name:    "no object",
+			options: Options{Operation: Insert},
+			records: records,
+		},
+		{
+			name:    "upsert without external id",
+			options: Options{Object: "Account", Operation: Upsert},
+			records: records,
+		},
+		{
+			name:    "no records",
+			options: Options{Object: "Account", Operation: Insert},
+		},
+		{
+			name:     "describe of another object",
+			options:  Options{Object: "Contact", Operation: Insert},
+			records:  records,
+			describe: accountDescribe(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Simulate(context.Background(), tt.options, nil, tt.records, tt.describe); err == nil {
+				t.Errorf("Simulate() error = %v, want an error", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package bulk
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/namely/go-sfdc/v3/sobject"
+)
+
+const idField = "Id"
+
+type sobjectRecord struct {
+	fields     map[string]interface{}
+	insertNull bool
+}
+
+func (r *sobjectRecord) Fields() map[string]interface{} {
+	return r.fields
+}
+
+func (r *sobjectRecord) InsertNull() bool {
+	return r.insertNull
+}
+
+// RecordsFromInserters will adapt the inserters to bulk records.  The field names
+// are the sorted union of the inserters' fields and can be used to create the
+// formatter.  All of the inserters must be for the same SObject, otherwise an error
+// with the index of the mismatching inserter is returned.
+func RecordsFromInserters(inserters []sobject.Inserter, insertNull bool) ([]Record, []string, error) {
+	if err := sameSObject(len(inserters), func(idx int) string { return inserters[idx].SObject() }); err != nil {
+		return nil, nil, err
+	}
+
+	records := make([]Record, len(inserters))
+	union := make(map[string]struct{})
+	for idx, inserter := range inserters {
+		fields := make(map[string]interface{})
+		for field, value := range inserter.Fields() {
+			fields[field] = value
+			union[field] = struct{}{}
+		}
+		records[idx] = &sobjectRecord{
+			fields:     fields,
+			insertNull: insertNull,
+		}
+	}
+
+	return records, sortedFields(union), nil
+}
+
+// RecordsFromUpdaters will adapt the updaters to bulk records.  The Id field is
+// added to each record from the updater's ID and is the first of the field names,
+// followed by the sorted union of the updaters' fields.  All of the updaters must
+// be for the same SObject, otherwise an error with the index of the mismatching
+// updater is returned.
+func RecordsFromUpdaters(updaters []sobject.Updater, insertNull bool) ([]Record, []string, error) {
+	if err := sameSObject(len(updaters), func(idx int) string { return updaters[idx].SObject() }); err != nil {
+		return nil, nil, err
+	}
+
+	records := make([]Record, len(updaters))
+	union := make(map[string]struct{})
+	for idx, updater := range updaters {
+		if updater.ID() == "" {
+			return nil, nil, fmt.Errorf("bulk records: updater %d has no id", idx)
+		}
+		fields := make(map[string]interface{})
+		for field, value := range updater.Fields() {
+			if field == idField {
+				continue
+			}
+			fields[field] = value
+			union[field] = struct{}{}
+		}
+		fields[idField] = updater.ID()
+		records[idx] = &sobjectRecord{
+			fields:     fields,
+			insertNull: insertNull,
+		}
+	}
+
+	return records, append([]string{idField}, sortedFields(union)...), nil
+}
+
+func sameSObject(count int, sobjectAt func(int) string) error {
+	if count == 0 {
+		return errors.New("bulk records: there must be at least one record")
+	}
+	first := sobjectAt(0)
+	for idx := 1; idx < count; idx++ {
+		if current := sobjectAt(idx); current != first {
+			return fmt.Errorf("bulk records: record %d is %s, want %s", idx, current, first)
+		}
+	}
+	return nil
+}
+
+func sortedFields(union map[string]struct{}) []string {
+	fields := make([]string, 0, len(union))
+	for field := range union {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
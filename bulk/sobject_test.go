@@ -0,0 +1,226 @@
+package bulk
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/namely/go-sfdc/v3/sobject"
+)
+
+type testInserter struct {
+	sobject string
+	id      string
+	fields  map[string]interface{}
+}
+
+func (t *testInserter) SObject() string {
+	return t.sobject
+}
+
+func (t *testInserter) Fields() map[string]interface{} {
+	return t.fields
+}
+
+func (t *testInserter) ID() string {
+	return t.id
+}
+
+func TestRecordsFromInserters(t *testing.T) {
+	type args struct {
+		inserters  []sobject.Inserter
+		insertNull bool
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantFields []string
+		wantBody   string
+		wantErr    bool
+	}{
+		{
+			name: "field union",
+			args: args{
+				inserters: []sobject.Inserter{
+					&testInserter{
+						sobject: "Account",
+						fields: map[string]interface{}{
+							"Site": "good site",
+							"Name": "name 1",
+						},
+					},
+					&testInserter{
+						sobject: "Account",
+						fields: map[string]interface{}{
+							"Name":          "name 2",
+							"AccountNumber": 42,
+						},
+					},
+				},
+			},
+			wantFields: []string{"AccountNumber", "Name", "Site"},
+			wantBody:   "AccountNumber,Name,Site\n,name 1,good site\n42,name 2,\n",
+			wantErr:    false,
+		},
+		{
+			name: "insert null",
+			args: args{
+				inserters: []sobject.Inserter{
+					&testInserter{
+						sobject: "Account",
+						fields: map[string]interface{}{
+							"Name": "name 1",
+						},
+					},
+					&testInserter{
+						sobject: "Account",
+						fields: map[string]interface{}{
+							"Site": "good site",
+						},
+					},
+				},
+				insertNull: true,
+			},
+			wantFields: []string{"Name", "Site"},
+			wantBody:   "Name,Site\nname 1,#N/A\n#N/A,good site\n",
+			wantErr:    false,
+		},
+		{
+			name: "mixed objects",
+			args: args{
+				inserters: []sobject.Inserter{
+					&testInserter{
+						sobject: "Account",
+					},
+					&testInserter{
+						sobject: "Account",
+					},
+					&testInserter{
+						sobject: "Contact",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "no inserters",
+			args:    args{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, fields, err := RecordsFromInserters(tt.args.inserters, tt.args.insertNull)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RecordsFromInserters() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(fields, tt.wantFields) {
+				t.Errorf("RecordsFromInserters() fields = %v, want %v", fields, tt.wantFields)
+			}
+			f, err := NewFormatter(&Job{}, fields)
+			if err != nil {
+				t.Fatalf("NewFormatter() error = %v", err)
+			}
+			if err := f.Add(records...); err != nil {
+				t.Fatalf("Formatter.Add() error = %v", err)
+			}
+			if f.sb.String() != tt.wantBody {
+				t.Errorf("RecordsFromInserters() body = %q, want %q", f.sb.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestRecordsFromUpdaters(t *testing.T) {
+	type args struct {
+		updaters   []sobject.Updater
+		insertNull bool
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantFields []string
+		wantBody   string
+		wantErr    bool
+	}{
+		{
+			name: "id column",
+			args: args{
+				updaters: []sobject.Updater{
+					&testInserter{
+						sobject: "Account",
+						id:      "001D000000K0fXOIAZ",
+						fields: map[string]interface{}{
+							"Site": "good site",
+						},
+					},
+					&testInserter{
+						sobject: "Account",
+						id:      "001D000000K0fXPIAZ",
+						fields: map[string]interface{}{
+							"Name": "name 2",
+						},
+					},
+				},
+			},
+			wantFields: []string{"Id", "Name", "Site"},
+			wantBody:   "Id,Name,Site\n001D000000K0fXOIAZ,,good site\n001D000000K0fXPIAZ,name 2,\n",
+			wantErr:    false,
+		},
+		{
+			name: "no id",
+			args: args{
+				updaters: []sobject.Updater{
+					&testInserter{
+						sobject: "Account",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "mixed objects",
+			args: args{
+				updaters: []sobject.Updater{
+					&testInserter{
+						sobject: "Account",
+						id:      "001D000000K0fXOIAZ",
+					},
+					&testInserter{
+						sobject: "Contact",
+						id:      "003D000000QV9n2IAD",
+					},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, fields, err := RecordsFromUpdaters(tt.args.updaters, tt.args.insertNull)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RecordsFromUpdaters() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(fields, tt.wantFields) {
+				t.Errorf("RecordsFromUpdaters() fields = %v, want %v", fields, tt.wantFields)
+			}
+			f, err := NewFormatter(&Job{}, fields)
+			if err != nil {
+				t.Fatalf("NewFormatter() error = %v", err)
+			}
+			if err := f.Add(records...); err != nil {
+				t.Fatalf("Formatter.Add() error = %v", err)
+			}
+			if f.sb.String() != tt.wantBody {
+				t.Errorf("RecordsFromUpdaters() body = %q, want %q", f.sb.String(), tt.wantBody)
+			}
+		})
+	}
+}
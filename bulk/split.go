@@ -0,0 +1,393 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/namely/go-sfdc/v3"
+)
+
+// splitQueryConcurrency is the number of query jobs that are run at the same time.
+const splitQueryConcurrency = 4
+
+const soqlDateTimeLayout = "2006-01-02T15:04:05Z"
+
+// DateRange is a sub-range of the split query.  From is inclusive and To is
+// exclusive, so the ranges do not overlap or leave gaps.
+type DateRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// QueryPage is a page of the split query results.
+//
+// Range is the date range of the query job, when split by date range.
+//
+// IDRange is the ID range of the query job, when split by ID range.
+//
+// JobID is the ID of the query job.
+//
+// Records are the records of the page.
+//
+// Values are the records with the columns coerced to their field types, when
+// the pages have field types.
+type QueryPage struct {
+	Range   DateRange
+	IDRange IDRange
+	JobID   string
+	Records []map[string]string
+	Values  []map[string]interface{}
+}
+
+// QueryPages iterates over the pages of the split query jobs.  The jobs are
+// iterated in order of their date or ID ranges, while the records
+// within a job are in the order returned by Salesforce.  The pages have at
+// most DefaultQueryResultPageSize records, unless the page size is set.
+type QueryPages struct {
+	ctx      context.Context
+	jobs     []*QueryJob
+	ranges   []DateRange
+	idRanges []IDRange
+	idx      int
+	locator  string
+	page     QueryPage
+	pageSize int
+	progress ProgressFunc
+	fetched  int
+	total    int
+	err      error
+}
+
+// SetPageSize will set the maximum number of records of the next pages.  Zero
+// is DefaultQueryResultPageSize and a negative page size is an error.
+func (p *QueryPages) SetPageSize(pageSize int) error {
+	if pageSize < 0 {
+		return fmt.Errorf("bulk split query: page size %d can not be negative", pageSize)
+	}
+	p.pageSize = pageSize
+	return nil
+}
+
+// SetProgress will call the progress function after each page is downloaded,
+// with the records of all of the query jobs.  The total is the sum of the
+// records processed by the query jobs, which is taken from their job
+// information before the next page.
+func (p *QueryPages) SetProgress(progress ProgressFunc) {
+	p.progress = progress
+}
+
+// SetFieldTypes will coerce the columns of the query jobs' results to the
+// field types, like the query job's SetFieldTypes.
+func (p *QueryPages) SetFieldTypes(types map[string]FieldType) error {
+	for _, job := range p.jobs {
+		if err := job.SetFieldTypes(types); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// processed returns the sum of the records processed by the query jobs, or
+// zero when the information of a job can not be retrieved.
+func (p *QueryPages) processed() int {
+	total := 0
+	for _, job := range p.jobs {
+		info, err := job.Info(p.ctx)
+		if err != nil || info.State != JobComplete {
+			return 0
+		}
+		total += info.NumberRecordsProcessed
+	}
+	return total
+}
+
+// SplitQueryByDateRange will split the query into date ranges of the date field,
+// from inclusive to exclusive, and run a query job for each of the ranges.  The
+// query is split into at most maxJobs ranges of whole seconds.  The query jobs are
+// run with bounded concurrency and the returned pages iterate over the results of
+// all of the jobs once they complete.  The query jobs share the correlation ID
+// of the context, or a new one if the context does not have one.
+func SplitQueryByDateRange(ctx context.Context, resource *Resource, baseQuery string, dateField string, from, to time.Time, maxJobs int) (*QueryPages, error) {
+	if resource == nil {
+		return nil, errors.New("bulk split query: resource can not be nil")
+	}
+	ranges, err := SplitDateRange(from, to, maxJobs)
+	if err != nil {
+		return nil, err
+	}
+	queries := make([]string, len(ranges))
+	for idx, dateRange := range ranges {
+		queries[idx], err = DateRangeQuery(baseQuery, dateField, dateRange)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ctx = sfdc.WithCorrelationID(ctx, correlationID(ctx))
+	jobs, err := runQueryJobs(ctx, resource, queries, func(idx int) string {
+		return fmt.Sprintf("range %s to %s", ranges[idx].From.Format(soqlDateTimeLayout), ranges[idx].To.Format(soqlDateTimeLayout))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &QueryPages{
+		ctx:    ctx,
+		jobs:   jobs,
+		ranges: ranges,
+	}, nil
+}
+
+// runQueryJobs will run a query job for each of the queries, with bounded
+// concurrency, and wait for them to complete.  If a job fails, the other jobs
+// are canceled, the jobs that were created and did not complete are aborted,
+// and the error is described by the query's index.
+func runQueryJobs(ctx context.Context, resource *Resource, queries []string, describe func(int) string) ([]*QueryJob, error) {
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make([]*QueryJob, len(queries))
+	completed := make([]bool, len(queries))
+	errs := make([]error, len(queries))
+	semaphore := make(chan struct{}, splitQueryConcurrency)
+	var wg sync.WaitGroup
+	for idx := range queries {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			select {
+			case semaphore <- struct{}{}:
+			case <-jobCtx.Done():
+				errs[idx] = jobCtx.Err()
+				return
+			}
+			defer func() { <-semaphore }()
+
+			job, err := resource.CreateQueryJob(jobCtx, QueryOptions{
+				Query: queries[idx],
+			})
+			if err == nil {
+				jobs[idx] = job
+				_, err = job.Complete(jobCtx)
+			}
+			if err != nil {
+				errs[idx] = err
+				cancel()
+				return
+			}
+			completed[idx] = true
+		}(idx)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			abortQueryJobs(jobs, completed)
+			break
+		}
+	}
+	for idx, err := range errs {
+		if err != nil && errors.Is(err, context.Canceled) == false {
+			return nil, fmt.Errorf("bulk split query: %s: %w", describe(idx), err)
+		}
+	}
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return jobs, nil
+}
+
+// abortQueryJobs will abort the query jobs that were created and did not
+// complete, so they do not keep running after the split query failed.  The
+// jobs are aborted without the canceled context, and the abort errors are
+// ignored since the split query's error is returned.
+func abortQueryJobs(jobs []*QueryJob, completed []bool) {
+	for idx, job := range jobs {
+		if job == nil || completed[idx] {
+			continue
+		}
+		_, _ = job.Abort(context.Background())
+	}
+}
+
+// SplitDateRange will split the time range into at most count ranges of whole
+// seconds.  The times are truncated to the second since SOQL date time literals
+// do not have fractional seconds.
+func SplitDateRange(from, to time.Time, count int) ([]DateRange, error) {
+	from = from.UTC().Truncate(time.Second)
+	to = to.UTC().Truncate(time.Second)
+	if to.After(from) == false {
+		return nil, errors.New("bulk split query: from must be before to")
+	}
+	if count <= 0 {
+		return nil, errors.New("bulk split query: the number of jobs must be greater than zero")
+	}
+
+	seconds := int64(to.Sub(from) / time.Second)
+	if int64(count) > seconds {
+		count = int(seconds)
+	}
+	ranges := make([]DateRange, count)
+	start := from
+	for idx := range ranges {
+		end := from.Add(time.Duration(seconds*int64(idx+1)/int64(count)) * time.Second)
+		ranges[idx] = DateRange{
+			From: start,
+			To:   end,
+		}
+		start = end
+	}
+	return ranges, nil
+}
+
+// DateRangeQuery will add the date range condition of the date field to the query.
+// If the query has a WHERE clause, the existing condition is kept and combined
+// with the date range.
+func DateRangeQuery(baseQuery string, dateField string, dateRange DateRange) (string, error) {
+	if baseQuery == "" {
+		return "", errors.New("bulk split query: query is required")
+	}
+	if dateField == "" {
+		return "", errors.New("bulk split query: date field is required")
+	}
+
+	condition := fmt.Sprintf("%s >= %s AND %s < %s",
+		dateField, dateRange.From.UTC().Format(soqlDateTimeLayout),
+		dateField, dateRange.To.UTC().Format(soqlDateTimeLayout))
+	return addCondition(baseQuery, condition)
+}
+
+var (
+	// whereClause is the WHERE keyword of the masked query.
+	whereClause = regexp.MustCompile(`\sWHERE\s`)
+	// clausesAfterWhere are the keywords of the clauses that follow the WHERE
+	// clause in the masked query.
+	clausesAfterWhere = regexp.MustCompile(`\s(WITH|GROUP\s+BY|ORDER\s+BY|LIMIT|OFFSET|FOR)\s`)
+)
+
+// addCondition will add the condition to the query's WHERE clause, keeping
+// the existing condition, before the WITH, GROUP BY, ORDER BY, LIMIT, OFFSET
+// and FOR clauses.  Only the clauses of the outer query are matched, so the
+// keywords of a sub-query or of a string literal are left alone.
+func addCondition(baseQuery string, condition string) (string, error) {
+	baseQuery = strings.TrimSpace(baseQuery)
+	masked, err := maskQuery(baseQuery)
+	if err != nil {
+		return "", err
+	}
+	end := len(baseQuery)
+	if loc := clausesAfterWhere.FindStringIndex(masked); loc != nil {
+		end = loc[0]
+	}
+	head := strings.TrimSpace(baseQuery[:end])
+	tail := baseQuery[end:]
+
+	if loc := whereClause.FindStringIndex(masked[:len(head)]); loc != nil {
+		where := strings.TrimSpace(head[loc[1]:])
+		return head[:loc[0]] + " WHERE (" + where + ") AND " + condition + tail, nil
+	}
+	return head + " WHERE " + condition + tail, nil
+}
+
+// maskQuery returns the query in upper case with the string literals and the
+// parenthesized expressions, like sub-queries, masked, so the keywords that
+// are found in it are the outer query's.  The masked query has the same
+// length as the query.  A query with an unterminated literal or unbalanced
+// parentheses is an error.
+func maskQuery(query string) (string, error) {
+	masked := []byte(strings.ToUpper(query))
+	depth := 0
+	quoted := false
+	for idx := 0; idx < len(masked); idx++ {
+		char := query[idx]
+		switch {
+		case quoted:
+			if char == '\\' && idx+1 < len(masked) {
+				masked[idx] = '_'
+				idx++
+			} else if char == '\'' {
+				quoted = false
+			}
+		case char == '\'':
+			quoted = true
+		case char == '(':
+			depth++
+		case char == ')':
+			depth--
+			if depth < 0 {
+				return "", fmt.Errorf("bulk split query: %q has unbalanced parentheses", query)
+			}
+		case depth == 0:
+			continue
+		}
+		masked[idx] = '_'
+	}
+	if quoted {
+		return "", fmt.Errorf("bulk split query: %q has an unterminated string literal", query)
+	}
+	if depth != 0 {
+		return "", fmt.Errorf("bulk split query: %q has unbalanced parentheses", query)
+	}
+	return string(masked), nil
+}
+
+// Next will retrieve the next page of results.  It returns false when there
+// are no more pages or an error occurred.
+func (p *QueryPages) Next() bool {
+	for p.err == nil && p.idx < len(p.jobs) {
+		job := p.jobs[p.idx]
+		pageSize := p.pageSize
+		if pageSize == 0 {
+			pageSize = DefaultQueryResultPageSize
+		}
+		results, err := job.Results(p.ctx, p.locator, pageSize)
+		if err != nil {
+			p.err = err
+			return false
+		}
+		if p.progress != nil {
+			if p.total == 0 {
+				p.total = p.processed()
+			}
+			p.fetched += results.NumberOfRecords
+			p.progress(p.fetched, p.total, progressPercent(p.fetched, p.total))
+		}
+		p.page = QueryPage{
+			JobID:   job.Response().ID,
+			Records: results.Records,
+			Values:  results.Values,
+		}
+		if p.ranges != nil {
+			p.page.Range = p.ranges[p.idx]
+		}
+		if p.idRanges != nil {
+			p.page.IDRange = p.idRanges[p.idx]
+		}
+		p.locator = results.Locator
+		if p.locator == "" {
+			p.idx++
+		}
+		if len(results.Records) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Page returns the current page of results.
+func (p *QueryPages) Page() QueryPage {
+	return p.page
+}
+
+// Err returns the error that stopped the iteration.
+func (p *QueryPages) Err() error {
+	return p.err
+}
@@ -0,0 +1,357 @@
+package bulk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/namely/go-sfdc/v3"
+)
+
+func TestSplitDateRange(t *testing.T) {
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		from    time.Time
+		to      time.Time
+		count   int
+		want    []DateRange
+		wantErr bool
+	}{
+		{
+			name:  "even split",
+			from:  from,
+			to:    from.Add(3 * time.Hour),
+			count: 3,
+			want: []DateRange{
+				{From: from, To: from.Add(time.Hour)},
+				{From: from.Add(time.Hour), To: from.Add(2 * time.Hour)},
+				{From: from.Add(2 * time.Hour), To: from.Add(3 * time.Hour)},
+			},
+		},
+		{
+			name:  "uneven split",
+			from:  from,
+			to:    from.Add(10 * time.Second),
+			count: 3,
+			want: []DateRange{
+				{From: from, To: from.Add(3 * time.Second)},
+				{From: from.Add(3 * time.Second), To: from.Add(6 * time.Second)},
+				{From: from.Add(6 * time.Second), To: from.Add(10 * time.Second)},
+			},
+		},
+		{
+			name:  "fewer seconds than jobs",
+			from:  from.Add(500 * time.Millisecond),
+			to:    from.Add(2 * time.Second),
+			count: 5,
+			want: []DateRange{
+				{From: from, To: from.Add(time.Second)},
+				{From: from.Add(time.Second), To: from.Add(2 * time.Second)},
+			},
+		},
+		{
+			name:    "empty range",
+			from:    from,
+			to:      from,
+			count:   2,
+			wantErr: true,
+		},
+		{
+			name:    "no jobs",
+			from:    from,
+			to:      from.Add(time.Hour),
+			count:   0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SplitDateRange(tt.from, tt.to, tt.count)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SplitDateRange() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitDateRange() = %v, want %v", got, tt.want)
+			}
+			for idx := 1; idx < len(got); idx++ {
+				if got[idx].From.Equal(got[idx-1].To) == false {
+					t.Errorf("SplitDateRange() range %d starts at %v, want %v", idx, got[idx].From, got[idx-1].To)
+				}
+			}
+		})
+	}
+}
+
+func TestDateRangeQuery(t *testing.T) {
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	dateRange := DateRange{
+		From: from,
+		To:   from.Add(time.Hour),
+	}
+	tests := []struct {
+		name    string
+		query   string
+		field   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "no where",
+			query: "SELECT Id FROM Account",
+			field: "CreatedDate",
+			want:  "SELECT Id FROM Account WHERE CreatedDate >= 2020-01-01T00:00:00Z AND CreatedDate < 2020-01-01T01:00:00Z",
+		},
+		{
+			name:  "existing where",
+			query: "SELECT Id FROM Account where Name = 'a' OR Site = 'b'",
+			field: "SystemModstamp",
+			want:  "SELECT Id FROM Account WHERE (Name = 'a' OR Site = 'b') AND SystemModstamp >= 2020-01-01T00:00:00Z AND SystemModstamp < 2020-01-01T01:00:00Z",
+		},
+		{
+			name:  "limit",
+			query: "SELECT Id FROM Account WHERE Name = 'a' LIMIT 10",
+			field: "CreatedDate",
+			want:  "SELECT Id FROM Account WHERE (Name = 'a') AND CreatedDate >= 2020-01-01T00:00:00Z AND CreatedDate < 2020-01-01T01:00:00Z LIMIT 10",
+		},
+		{
+			name:  "sub-query in the select list",
+			query: "SELECT Id, (SELECT Id FROM Contacts WHERE Email != null ORDER BY Name) FROM Account ORDER BY Name",
+			field: "CreatedDate",
+			want:  "SELECT Id, (SELECT Id FROM Contacts WHERE Email != null ORDER BY Name) FROM Account WHERE CreatedDate >= 2020-01-01T00:00:00Z AND CreatedDate < 2020-01-01T01:00:00Z ORDER BY Name",
+		},
+		{
+			name:  "sub-query in the where clause",
+			query: "SELECT Id FROM Account WHERE Id IN (SELECT AccountId FROM Contact LIMIT 5) LIMIT 10",
+			field: "CreatedDate",
+			want:  "SELECT Id FROM Account WHERE (Id IN (SELECT AccountId FROM Contact LIMIT 5)) AND CreatedDate >= 2020-01-01T00:00:00Z AND CreatedDate < 2020-01-01T01:00:00Z LIMIT 10",
+		},
+		{
+			name:  "keywords in a literal",
+			query: "SELECT Id FROM Account WHERE Name = 'x ORDER BY y \\' LIMIT 1' ORDER BY Name",
+			field: "CreatedDate",
+			want:  "SELECT Id FROM Account WHERE (Name = 'x ORDER BY y \\' LIMIT 1') AND CreatedDate >= 2020-01-01T00:00:00Z AND CreatedDate < 2020-01-01T01:00:00Z ORDER BY Name",
+		},
+		{
+			name:  "where keyword in a literal",
+			query: "SELECT Id FROM Account\nwhere\tDescription LIKE '% WHERE %'\nGROUP  BY Id",
+			field: "CreatedDate",
+			want:  "SELECT Id FROM Account WHERE (Description LIKE '% WHERE %') AND CreatedDate >= 2020-01-01T00:00:00Z AND CreatedDate < 2020-01-01T01:00:00Z\nGROUP  BY Id",
+		},
+		{
+			name:    "unterminated literal",
+			query:   "SELECT Id FROM Account WHERE Name = 'a",
+			field:   "CreatedDate",
+			wantErr: true,
+		},
+		{
+			name:    "unbalanced parentheses",
+			query:   "SELECT Id, (SELECT Id FROM Contacts FROM Account",
+			field:   "CreatedDate",
+			wantErr: true,
+		},
+		{
+			name:    "no query",
+			field:   "CreatedDate",
+			wantErr: true,
+		},
+		{
+			name:    "no field",
+			query:   "SELECT Id FROM Account",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DateRangeQuery(tt.query, tt.field, dateRange)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DateRangeQuery() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("DateRangeQuery() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitQueryByDateRange(t *testing.T) {
+	policy := queryPollPolicy
+	queryPollPolicy = sfdc.RetryPolicy{InitialInterval: time.Millisecond, Multiplier: 1}
+	defer func() { queryPollPolicy = policy }()
+
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var mu sync.Mutex
+	var queries []string
+	polls := make(map[string]int)
+	client := mockHTTPClient(func(req *http.Request) *http.Response {
+		mu.Lock()
+		defer mu.Unlock()
+
+		var resp string
+		header := make(http.Header)
+		path := strings.TrimPrefix(req.URL.Path, bulk2QueryEndpoint)
+		switch {
+		case req.Method == http.MethodPost:
+			var options QueryOptions
+			if err := json.NewDecoder(req.Body).Decode(&options); err != nil {
+				t.Errorf("query options error = %v", err)
+			}
+			queries = append(queries, options.Query)
+			var id string
+			for idx := 0; idx < 3; idx++ {
+				if strings.Contains(options.Query, fmt.Sprintf(">= 2020-01-01T0%d:00:00Z", idx)) {
+					id = fmt.Sprintf("750%d", idx)
+				}
+			}
+			resp = `{"id": "` + id + `", "operation": "query", "state": "UploadComplete"}`
+		case strings.HasSuffix(path, "/results"):
+			id := strings.TrimSuffix(strings.TrimPrefix(path, "/"), "/results")
+			switch req.URL.Query().Get("locator") {
+			case "":
+				header.Set("Sforce-Locator", "next")
+				resp = "\"Id\"\n\"" + id + "-1\"\n"
+			default:
+				header.Set("Sforce-Locator", "null")
+				resp = "\"Id\"\n\"" + id + "-2\"\n"
+			}
+		default:
+			id := strings.TrimPrefix(path, "/")
+			polls[id]++
+			state := UpdateComplete
+			if polls[id] > 1 {
+				state = JobComplete
+			}
+			resp = `{"id": "` + id + `", "state": "` + string(state) + `"}`
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Status:     "Good",
+			Body:       io.NopCloser(strings.NewReader(resp)),
+			Header:     header,
+		}
+	})
+	resource := &Resource{
+		session: &mockSessionFormatter{
+			client: client,
+		},
+	}
+
+	pages, err := SplitQueryByDateRange(context.Background(), resource, "SELECT Id FROM Account", "CreatedDate", from, from.Add(3*time.Hour), 3)
+	if err != nil {
+		t.Fatalf("SplitQueryByDateRange() error = %v", err)
+	}
+	if len(queries) != 3 {
+		t.Fatalf("SplitQueryByDateRange() queries = %v, want 3", queries)
+	}
+
+	var got []string
+	for pages.Next() {
+		page := pages.Page()
+		for _, record := range page.Records {
+			got = append(got, record["Id"])
+		}
+		if page.Range.From.Before(from) || page.Range.To.After(from.Add(3*time.Hour)) {
+			t.Errorf("QueryPages.Page() range = %v", page.Range)
+		}
+	}
+	if err := pages.Err(); err != nil {
+		t.Fatalf("QueryPages.Err() = %v", err)
+	}
+	want := []string{"7500-1", "7500-2", "7501-1", "7501-2", "7502-1", "7502-2"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("QueryPages records = %v, want %v", got, want)
+	}
+}
+
+func TestSplitQueryByDateRange_failedJob(t *testing.T) {
+	policy := queryPollPolicy
+	queryPollPolicy = sfdc.RetryPolicy{InitialInterval: time.Millisecond, Multiplier: 1}
+	defer func() { queryPollPolicy = policy }()
+
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	client := mockHTTPClient(func(req *http.Request) *http.Response {
+		resp := `{"id": "7500", "state": "Failed", "errorMessage": "query timeout"}`
+		if req.Method == http.MethodPost {
+			resp = `{"id": "7500", "state": "UploadComplete"}`
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Status:     "Good",
+			Body:       io.NopCloser(strings.NewReader(resp)),
+			Header:     make(http.Header),
+		}
+	})
+	resource := &Resource{
+		session: &mockSessionFormatter{
+			client: client,
+		},
+	}
+
+	_, err := SplitQueryByDateRange(context.Background(), resource, "SELECT Id FROM Account", "CreatedDate", from, from.Add(time.Hour), 2)
+	if err == nil || strings.Contains(err.Error(), "query timeout") == false {
+		t.Errorf("SplitQueryByDateRange() error = %v, want query timeout", err)
+	}
+}
+
+func TestSplitQueryByDateRange_abortsCreatedJobs(t *testing.T) {
+	policy := queryPollPolicy
+	queryPollPolicy = sfdc.RetryPolicy{InitialInterval: time.Millisecond, Multiplier: 1}
+	defer func() { queryPollPolicy = policy }()
+
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	var mu sync.Mutex
+	created := 0
+	var aborted []string
+	client := mockHTTPClient(func(req *http.Request) *http.Response {
+		mu.Lock()
+		defer mu.Unlock()
+
+		status := http.StatusOK
+		path := strings.TrimPrefix(strings.TrimPrefix(req.URL.Path, bulk2QueryEndpoint), "/")
+		var resp string
+		switch req.Method {
+		case http.MethodPost:
+			created++
+			if created > 1 {
+				status = http.StatusBadRequest
+				resp = `[{"errorCode": "INVALID_FIELD", "message": "no such column"}]`
+				break
+			}
+			resp = `{"id": "7500", "state": "UploadComplete"}`
+		case http.MethodPatch:
+			aborted = append(aborted, path)
+			resp = `{"id": "` + path + `", "state": "Aborted"}`
+		default:
+			resp = `{"id": "` + path + `", "state": "InProgress"}`
+		}
+		return &http.Response{
+			StatusCode: status,
+			Status:     http.StatusText(status),
+			Body:       io.NopCloser(strings.NewReader(resp)),
+			Header:     make(http.Header),
+		}
+	})
+	resource := &Resource{
+		session: &mockSessionFormatter{
+			client: client,
+		},
+	}
+
+	_, err := SplitQueryByDateRange(context.Background(), resource, "SELECT Id FROM Account", "CreatedDate", from, from.Add(time.Hour), 2)
+	if err == nil || strings.Contains(err.Error(), "no such column") == false {
+		t.Fatalf("SplitQueryByDateRange() error = %v, want the create error", err)
+	}
+	if !reflect.DeepEqual(aborted, []string{"7500"}) {
+		t.Errorf("SplitQueryByDateRange() aborted = %v, want the created job", aborted)
+	}
+}
@@ -0,0 +1,161 @@
+package bulk
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/namely/go-sfdc/v3"
+)
+
+// QueryResultsStream is a page of the query job results that is decoded one
+// row at a time from the response body, instead of being read into memory.
+//
+// Locator is used to retrieve the next page.  It is empty when there are no
+// more pages.
+//
+// NumberOfRecords is the number of records of the page that Salesforce
+// returned with the Sforce-NumberOfRecords header.  When the header is
+// missing, it is -1 until all of the records are read.
+type QueryResultsStream struct {
+	Locator         string
+	NumberOfRecords int
+
+	ctx      context.Context
+	query    *QueryJob
+	locator  string
+	response *http.Response
+	job      *Job
+	reader   *rowReader
+	fields   []string
+	records  int
+	err      error
+}
+
+// ResultsStream returns a page of the query job results that is decoded one
+// row at a time with Next.  The locator and maxRecords are the same as the
+// Results'.  The stream is closed with Close, which also has to be called when
+// not all of the rows are read, so the connection can be reused.
+func (j *QueryJob) ResultsStream(ctx context.Context, locator string, maxRecords int) (*QueryResultsStream, error) {
+	ctx = j.context(ctx)
+	response, err := j.resultsResponse(ctx, locator, maxRecords)
+	if err != nil {
+		return nil, err
+	}
+	stream := &QueryResultsStream{
+		ctx:      ctx,
+		query:    j,
+		locator:  locator,
+		response: response,
+		job: &Job{
+			info: j.info,
+		},
+	}
+	stream.Locator, stream.NumberOfRecords = resultsHeaders(response)
+
+	stream.reader, stream.fields, err = stream.job.resultReader(response, false)
+	switch {
+	case err == io.EOF:
+		stream.finish()
+	case err != nil:
+		stream.Close()
+		return nil, err
+	}
+	return stream, nil
+}
+
+// ResultsPageInfo is the page information of the raw query job results.
+//
+// Locator is used to retrieve the next page.  It is empty when there are no
+// more pages.
+//
+// NumberOfRecords is the number of records of the page that Salesforce
+// returned with the Sforce-NumberOfRecords header, or -1 when the header is
+// missing.
+type ResultsPageInfo struct {
+	Locator         string
+	NumberOfRecords int
+}
+
+// ResultsRaw returns the body of a page of the query job results, without
+// decoding it, so it can be copied to a file or an object store.  The body is
+// CSV, or JSON for a query job whose content type is JSON.  The locator and
+// maxRecords are the same as the Results'.  The caller closes the body.  When
+// the results can not be retrieved, or their content type is not the job's,
+// the response body is closed and an error is returned.
+func (j *QueryJob) ResultsRaw(ctx context.Context, locator string, maxRecords int) (io.ReadCloser, *ResultsPageInfo, error) {
+	ctx = j.context(ctx)
+	response, err := j.resultsResponse(ctx, locator, maxRecords)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := (&Job{info: j.info}).checkResultContentType(response); err != nil {
+		sfdc.CloseResponse(response)
+		return nil, nil, err
+	}
+	info := &ResultsPageInfo{}
+	info.Locator, info.NumberOfRecords = resultsHeaders(response)
+	return response.Body, info, nil
+}
+
+// Next returns the next record of the page, or io.EOF when there are no more
+// records.  The values of the record are converted, unless the query job has
+// field types, in which case the columns are coerced like the Values of the
+// Results.
+func (s *QueryResultsStream) Next() (map[string]interface{}, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	values, err := s.reader.Read()
+	if err == io.EOF {
+		s.finish()
+		return nil, s.err
+	}
+	if err != nil {
+		s.err = err
+		return nil, err
+	}
+	s.records++
+	record := s.job.record(s.fields, values)
+	if s.query.fieldTypes != nil {
+		coerced, err := coerceRecord(s.records, record, s.query.fieldTypes)
+		if err != nil {
+			s.err = err
+			return nil, err
+		}
+		return coerced, nil
+	}
+	converted := make(map[string]interface{}, len(record))
+	for field, value := range record {
+		converted[field] = value
+	}
+	return converted, nil
+}
+
+// Close will drain and close the response body, like sfdc.CloseResponse.
+func (s *QueryResultsStream) Close() error {
+	if s.response == nil {
+		return nil
+	}
+	response := s.response
+	s.response = nil
+	if s.err == nil {
+		s.err = io.EOF
+	}
+	return sfdc.CloseResponse(response)
+}
+
+// finish ends the stream when all of the rows are read, reporting the
+// progress of the page.
+func (s *QueryResultsStream) finish() {
+	s.err = io.EOF
+	if s.NumberOfRecords < 0 {
+		s.NumberOfRecords = s.records
+	}
+	if s.query.progress != nil {
+		s.query.reportProgress(s.ctx, s.locator, QueryResults{
+			Locator:         s.Locator,
+			NumberOfRecords: s.NumberOfRecords,
+		})
+	}
+}
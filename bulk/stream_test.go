@@ -0,0 +1,214 @@
+package bulk
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func streamJob(body *trackingBody, delimiter ColumnDelimiter, header http.Header) *QueryJob {
+	return &QueryJob{
+		session: &mockSessionFormatter{
+			url: "https://test.salesforce.com/services/data/v42.0",
+			client: mockHTTPClient(func(req *http.Request) *http.Response {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       body,
+					Header:     header,
+				}
+			}),
+		},
+		info: Response{
+			ID:              "750R0000000zhfdIAA",
+			ColumnDelimiter: delimiter,
+		},
+	}
+}
+
+func TestQueryJob_ResultsStream(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		delimiter ColumnDelimiter
+		want      []map[string]interface{}
+	}{
+		{
+			name: "comma and lf",
+			body: "\"Id\",\"Name\"\n\"001A\",\"Acme, Inc.\"\n\"001B\",\"\"\n",
+			want: []map[string]interface{}{
+				{"Id": "001A", "Name": "Acme, Inc."},
+				{"Id": "001B", "Name": ""},
+			},
+		},
+		{
+			name:      "pipe and crlf",
+			body:      "Id|Name\r\n001A|Acme\r\n001B|Globex\r\n",
+			delimiter: Pipe,
+			want: []map[string]interface{}{
+				{"Id": "001A", "Name": "Acme"},
+				{"Id": "001B", "Name": "Globex"},
+			},
+		},
+		{
+			name: "empty body",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := newTrackingBody(tt.body)
+			header := make(http.Header)
+			header.Set("Sforce-Locator", "MTAwMDA")
+			header.Set("Sforce-NumberOfRecords", "2")
+			stream, err := streamJob(body, tt.delimiter, header).ResultsStream(context.Background(), "", 2)
+			if err != nil {
+				t.Fatalf("QueryJob.ResultsStream() error = %v", err)
+			}
+			if stream.Locator != "MTAwMDA" || stream.NumberOfRecords != 2 {
+				t.Errorf("QueryJob.ResultsStream() locator = %s, number of records = %d", stream.Locator, stream.NumberOfRecords)
+			}
+			var got []map[string]interface{}
+			for {
+				record, err := stream.Next()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("QueryResultsStream.Next() error = %v", err)
+				}
+				got = append(got, record)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("QueryResultsStream.Next() = %v, want %v", got, tt.want)
+			}
+			if err := stream.Close(); err != nil {
+				t.Errorf("QueryResultsStream.Close() error = %v", err)
+			}
+			if body.closed == false {
+				t.Error("QueryResultsStream.Close() did not close the body")
+			}
+		})
+	}
+}
+
+func TestQueryResultsStream_Close(t *testing.T) {
+	body := newTrackingBody("\"Id\"\n\"001A\"\n\"001B\"\n\"001C\"\n")
+	stream, err := streamJob(body, "", make(http.Header)).ResultsStream(context.Background(), "", 0)
+	if err != nil {
+		t.Fatalf("QueryJob.ResultsStream() error = %v", err)
+	}
+	if stream.Locator != "" || stream.NumberOfRecords != -1 {
+		t.Errorf("QueryJob.ResultsStream() locator = %s, number of records = %d", stream.Locator, stream.NumberOfRecords)
+	}
+	if _, err := stream.Next(); err != nil {
+		t.Fatalf("QueryResultsStream.Next() error = %v", err)
+	}
+	if err := stream.Close(); err != nil {
+		t.Fatalf("QueryResultsStream.Close() error = %v", err)
+	}
+	if rest, _ := io.ReadAll(body.Reader); body.closed == false || len(rest) != 0 {
+		t.Errorf("QueryResultsStream.Close() closed = %t, left %q", body.closed, rest)
+	}
+	if _, err := stream.Next(); err != io.EOF {
+		t.Errorf("QueryResultsStream.Next() after Close error = %v, want io.EOF", err)
+	}
+}
+
+func TestQueryResultsStream_FieldTypes(t *testing.T) {
+	body := newTrackingBody("\"Id\",\"NumberOfEmployees\"\n\"001A\",\"42\"\n\"001B\",\"forty\"\n")
+	job := streamJob(body, "", make(http.Header))
+	if err := job.SetFieldTypes(map[string]FieldType{"NumberOfEmployees": IntField}); err != nil {
+		t.Fatalf("QueryJob.SetFieldTypes() error = %v", err)
+	}
+	var progress []int
+	job.SetProgress(func(fetched, total int, pct float64) {
+		progress = append(progress, fetched)
+	})
+	stream, err := job.ResultsStream(context.Background(), "", 0)
+	if err != nil {
+		t.Fatalf("QueryJob.ResultsStream() error = %v", err)
+	}
+	defer stream.Close()
+
+	record, err := stream.Next()
+	if err != nil {
+		t.Fatalf("QueryResultsStream.Next() error = %v", err)
+	}
+	if !reflect.DeepEqual(record, map[string]interface{}{"Id": "001A", "NumberOfEmployees": int64(42)}) {
+		t.Errorf("QueryResultsStream.Next() = %#v", record)
+	}
+	_, err = stream.Next()
+	typeErr, ok := err.(*FieldTypeError)
+	if ok == false || typeErr.Row != 2 || strings.Contains(err.Error(), "forty") == false {
+		t.Errorf("QueryResultsStream.Next() error = %v, want a FieldTypeError", err)
+	}
+	if len(progress) != 0 {
+		t.Errorf("QueryResultsStream progress = %v, want none before the end", progress)
+	}
+}
+
+func TestQueryJob_ResultsRaw(t *testing.T) {
+	const csv = "\"Id\",\"Name\"\n\"001A\",\"Acme\"\n"
+	body := newTrackingBody(csv)
+	header := make(http.Header)
+	header.Set("Sforce-Locator", "MTAwMDA")
+	header.Set("Sforce-NumberOfRecords", "1")
+	reader, info, err := streamJob(body, "", header).ResultsRaw(context.Background(), "", 1)
+	if err != nil {
+		t.Fatalf("QueryJob.ResultsRaw() error = %v", err)
+	}
+	if *info != (ResultsPageInfo{Locator: "MTAwMDA", NumberOfRecords: 1}) {
+		t.Errorf("QueryJob.ResultsRaw() info = %+v", info)
+	}
+	if body.closed {
+		t.Error("QueryJob.ResultsRaw() closed the body before returning")
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil || string(data) != csv {
+		t.Errorf("QueryJob.ResultsRaw() body = %q, %v, want %q", data, err, csv)
+	}
+	if err := reader.Close(); err != nil || body.closed == false {
+		t.Errorf("ReadCloser.Close() error = %v, closed = %t", err, body.closed)
+	}
+
+	header = make(http.Header)
+	header.Set("Sforce-Locator", "null")
+	_, info, err = streamJob(newTrackingBody(""), "", header).ResultsRaw(context.Background(), "MTAwMDA", 0)
+	if err != nil {
+		t.Fatalf("QueryJob.ResultsRaw() error = %v", err)
+	}
+	if *info != (ResultsPageInfo{NumberOfRecords: -1}) {
+		t.Errorf("QueryJob.ResultsRaw() last page info = %+v", info)
+	}
+}
+
+func TestQueryJob_ResultsRaw_error(t *testing.T) {
+	body := newTrackingBody(`[{"errorCode":"INVALIDJOBSTATE","message":"job is not complete"}]`)
+	job := &QueryJob{
+		session: &mockSessionFormatter{
+			url: "https://test.salesforce.com/services/data/v42.0",
+			client: mockHTTPClient(func(req *http.Request) *http.Response {
+				return &http.Response{
+					StatusCode: http.StatusBadRequest,
+					Body:       body,
+					Header:     make(http.Header),
+				}
+			}),
+		},
+		info: Response{
+			ID: "750R0000000zhfdIAA",
+		},
+	}
+	reader, info, err := job.ResultsRaw(context.Background(), "", 0)
+	if err == nil || reader != nil || info != nil {
+		t.Fatalf("QueryJob.ResultsRaw() = %v, %v, %v, want an error", reader, info, err)
+	}
+	if body.closed == false {
+		t.Error("QueryJob.ResultsRaw() did not close the body of the error response")
+	}
+	if _, _, err := job.ResultsRaw(context.Background(), "", -1); err == nil {
+		t.Error("QueryJob.ResultsRaw() error = nil, want an error for the negative max records")
+	}
+}
@@ -0,0 +1,241 @@
+package bulk
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/namely/go-sfdc/v3"
+)
+
+// DefaultStructTag is the struct tag read by RecordsFromStructs when the tag
+// is empty.
+const DefaultStructTag = "sfdc"
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// structField is a field of the struct records.  The index is the path to the
+// field from the record's struct.
+type structField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+	date      bool
+}
+
+// structRecord is the Record of a struct.
+type structRecord struct {
+	fields     map[string]interface{}
+	insertNull bool
+}
+
+func (r *structRecord) Fields() map[string]interface{} {
+	return r.fields
+}
+
+func (r *structRecord) InsertNull() bool {
+	return r.insertNull
+}
+
+// RecordsFromStructs returns the records, and their fields, of a slice of
+// structs or struct pointers.  The fields are the struct fields with the tag,
+// DefaultStructTag when the tag is empty, in their declaration order.
+//
+// The tag is the Salesforce field name, which is the Go field name when it is
+// empty, followed by the options.  A field tagged "-" is skipped.  The
+// omitempty option leaves the value blank when it is the zero value, so the
+// record's field is not changed, while an empty string without the option is
+// an explicit empty value.  The date option writes a time as a
+// Salesforce date instead of a date time.
+//
+// A tagged struct field is a relationship whose fields are the external ID
+// references of the related record, like Account.External_Id__c.  Untagged
+// embedded structs have their fields promoted.
+//
+// A nil pointer is a null, so the record's InsertNull is true and the field is
+// written as #N/A by the Formatter.  Times are written in UTC.
+func RecordsFromStructs(values interface{}, tag string) ([]Record, []string, error) {
+	if tag == "" {
+		tag = DefaultStructTag
+	}
+	slice := reflect.ValueOf(values)
+	if slice.Kind() != reflect.Slice && slice.Kind() != reflect.Array {
+		return nil, nil, fmt.Errorf("bulk struct records: %T is not a slice", values)
+	}
+	structType := slice.Type().Elem()
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return nil, nil, fmt.Errorf("bulk struct records: %s is not a struct", structType)
+	}
+
+	fields, err := structFields(structType, tag, "", nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(fields) == 0 {
+		return nil, nil, fmt.Errorf("bulk struct records: %s has no %s tagged fields", structType, tag)
+	}
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = field.name
+	}
+
+	records := make([]Record, slice.Len())
+	for i := 0; i < slice.Len(); i++ {
+		value := slice.Index(i)
+		if value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				return nil, nil, fmt.Errorf("bulk struct records: record %d is nil", i)
+			}
+			value = value.Elem()
+		}
+		record, err := structToRecord(value, fields)
+		if err != nil {
+			return nil, nil, fmt.Errorf("bulk struct records: record %d: %w", i, err)
+		}
+		records[i] = record
+	}
+	return records, names, nil
+}
+
+func structFields(structType reflect.Type, tag, prefix string, index []int, parents []reflect.Type) ([]structField, error) {
+	for _, parent := range parents {
+		if parent == structType {
+			return nil, fmt.Errorf("bulk struct records: %s refers to itself", structType)
+		}
+	}
+	parents = append(parents, structType)
+	var fields []structField
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		fieldIndex := append(append([]int{}, index...), i)
+		value, tagged := field.Tag.Lookup(tag)
+		if value == "-" {
+			continue
+		}
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if tagged == false {
+			if field.Anonymous && fieldType.Kind() == reflect.Struct {
+				embedded, err := structFields(fieldType, tag, prefix, fieldIndex, parents)
+				if err != nil {
+					return nil, err
+				}
+				fields = append(fields, embedded...)
+			}
+			continue
+		}
+		if field.PkgPath != "" {
+			return nil, fmt.Errorf("bulk struct records: %s is not exported", field.Name)
+		}
+
+		options := strings.Split(value, ",")
+		name := options[0]
+		if name == "" {
+			name = field.Name
+		}
+		sf := structField{
+			name:  prefix + name,
+			index: fieldIndex,
+		}
+		for _, option := range options[1:] {
+			switch option {
+			case "omitempty":
+				sf.omitEmpty = true
+			case "date":
+				sf.date = true
+			default:
+				return nil, fmt.Errorf("bulk struct records: %s has an unknown tag option %q", field.Name, option)
+			}
+		}
+
+		if fieldType.Kind() == reflect.Struct && fieldType != timeType {
+			related, err := structFields(fieldType, tag, sf.name+".", fieldIndex, parents)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, related...)
+			continue
+		}
+		fields = append(fields, sf)
+	}
+	return fields, nil
+}
+
+func structToRecord(value reflect.Value, fields []structField) (*structRecord, error) {
+	record := &structRecord{
+		fields: make(map[string]interface{}, len(fields)),
+	}
+	for _, field := range fields {
+		fieldValue, ok := fieldByIndex(value, field.index)
+		if ok == false {
+			record.fields[field.name] = nil
+			record.insertNull = true
+			continue
+		}
+		if field.omitEmpty && fieldValue.IsZero() {
+			record.fields[field.name] = Unchanged
+			continue
+		}
+		if fieldValue.Kind() == reflect.Ptr {
+			if fieldValue.IsNil() {
+				record.fields[field.name] = nil
+				record.insertNull = true
+				continue
+			}
+			fieldValue = fieldValue.Elem()
+		}
+		converted, err := structValue(fieldValue, field)
+		if err != nil {
+			return nil, err
+		}
+		record.fields[field.name] = converted
+	}
+	return record, nil
+}
+
+// fieldByIndex returns the field of the index.  It is not ok when a pointer to
+// a relationship or an embedded struct on the way to the field is nil.
+func fieldByIndex(value reflect.Value, index []int) (reflect.Value, bool) {
+	for i, idx := range index {
+		if i > 0 && value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				return reflect.Value{}, false
+			}
+			value = value.Elem()
+		}
+		value = value.Field(idx)
+	}
+	return value, true
+}
+
+func structValue(value reflect.Value, field structField) (interface{}, error) {
+	if value.Type() == timeType {
+		t := value.Interface().(time.Time).UTC()
+		if field.date {
+			return t.Format(sfdc.SalesforceDate), nil
+		}
+		return t.Format(sfdc.SalesforceDateTime), nil
+	}
+	switch value.Kind() {
+	case reflect.String:
+		return value.String(), nil
+	case reflect.Bool:
+		return value.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return value.Uint(), nil
+	case reflect.Float32:
+		return strconv.FormatFloat(value.Float(), 'f', -1, 32), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'f', -1, 64), nil
+	}
+	return nil, fmt.Errorf("%s of type %s is not supported", field.name, value.Type())
+}
@@ -0,0 +1,161 @@
+package bulk
+
+import (
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type structAccount struct {
+	ExternalID string `sfdc:"External_Id__c"`
+}
+
+type structAudit struct {
+	Source string `sfdc:"Source__c"`
+}
+
+type structContact struct {
+	structAudit
+	LastName  string         `sfdc:"LastName"`
+	Email     *string        `sfdc:"Email"`
+	Phone     string         `sfdc:"Phone,omitempty"`
+	Active    bool           `sfdc:"Active__c"`
+	Score     float64        `sfdc:"Score__c"`
+	Visits    int            `sfdc:"Visits__c,omitempty"`
+	Birthdate time.Time      `sfdc:"Birthdate,date"`
+	Synced    *time.Time     `sfdc:"Synced__c"`
+	Account   *structAccount `sfdc:"Account"`
+	Notes     string         `sfdc:"-"`
+}
+
+func TestRecordsFromStructs(t *testing.T) {
+	email := "jane@example.com"
+	synced := time.Date(2022, 1, 2, 21, 33, 43, 0, time.FixedZone("EST", -5*60*60))
+	contacts := []structContact{
+		{
+			structAudit: structAudit{Source: "web"},
+			LastName:    "Doe",
+			Email:       &email,
+			Phone:       "555-1234",
+			Active:      true,
+			Score:       1234567.5,
+			Visits:      3,
+			Birthdate:   time.Date(1990, 5, 6, 0, 0, 0, 0, time.UTC),
+			Synced:      &synced,
+			Account:     &structAccount{ExternalID: "ACME-1"},
+			Notes:       "skipped",
+		},
+		{
+			LastName:  "Roe",
+			Birthdate: time.Date(1985, 7, 8, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	records, fields, err := RecordsFromStructs(contacts, "")
+	if err != nil {
+		t.Fatalf("RecordsFromStructs() error = %v", err)
+	}
+	wantFields := []string{
+		"Source__c",
+		"LastName",
+		"Email",
+		"Phone",
+		"Active__c",
+		"Score__c",
+		"Visits__c",
+		"Birthdate",
+		"Synced__c",
+		"Account.External_Id__c",
+	}
+	if !reflect.DeepEqual(fields, wantFields) {
+		t.Errorf("RecordsFromStructs() fields = %v, want %v", fields, wantFields)
+	}
+	if records[0].InsertNull() {
+		t.Error("RecordsFromStructs() record 0 InsertNull = true, want false")
+	}
+	if records[1].InsertNull() == false {
+		t.Error("RecordsFromStructs() record 1 InsertNull = false, want true")
+	}
+
+	job := &Job{
+		info: Response{
+			ColumnDelimiter: Comma,
+			LineEnding:      Linefeed,
+		},
+	}
+	formatter, err := NewFormatter(job, fields)
+	if err != nil {
+		t.Fatalf("NewFormatter() error = %v", err)
+	}
+	if err := formatter.Add(records...); err != nil {
+		t.Fatalf("Formatter.Add() error = %v", err)
+	}
+	got, err := io.ReadAll(formatter.Reader())
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	want := "Source__c,LastName,Email,Phone,Active__c,Score__c,Visits__c,Birthdate,Synced__c,Account.External_Id__c\n" +
+		"web,Doe,jane@example.com,555-1234,true,1234567.5,3,1990-05-06,2022-01-03T02:33:43.000+0000,ACME-1\n" +
+		"\"\",Roe,#N/A,,false,0,,1985-07-08,#N/A,#N/A\n"
+	if string(got) != want {
+		t.Errorf("Formatter.Reader() = %q, want %q", got, want)
+	}
+}
+
+func TestRecordsFromStructs_Tag(t *testing.T) {
+	type opportunity struct {
+		Name   string `json:"name" csv:"Name"`
+		Amount int    `csv:",omitempty"`
+		Stage  string `json:"stage"`
+	}
+	records, fields, err := RecordsFromStructs([]*opportunity{{Name: "Big Deal"}}, "csv")
+	if err != nil {
+		t.Fatalf("RecordsFromStructs() error = %v", err)
+	}
+	if want := []string{"Name", "Amount"}; !reflect.DeepEqual(fields, want) {
+		t.Errorf("RecordsFromStructs() fields = %v, want %v", fields, want)
+	}
+	want := map[string]interface{}{
+		"Name":   "Big Deal",
+		"Amount": Unchanged,
+	}
+	if got := records[0].Fields(); !reflect.DeepEqual(got, want) {
+		t.Errorf("RecordsFromStructs() record = %v, want %v", got, want)
+	}
+}
+
+func TestRecordsFromStructs_Errors(t *testing.T) {
+	type badOption struct {
+		Name string `sfdc:"Name,upper"`
+	}
+	type unsupported struct {
+		Tags []string `sfdc:"Tags__c"`
+	}
+	type untagged struct {
+		Name string
+	}
+	type self struct {
+		Name   string `sfdc:"Name"`
+		Parent *self  `sfdc:"Parent"`
+	}
+	tests := []struct {
+		name   string
+		values interface{}
+	}{
+		{name: "not a slice", values: structContact{}},
+		{name: "not a struct", values: []string{"a"}},
+		{name: "nil record", values: []*structContact{nil}},
+		{name: "unknown option", values: []badOption{{}}},
+		{name: "unsupported type", values: []unsupported{{}}},
+		{name: "no tagged fields", values: []untagged{{}}},
+		{name: "self reference", values: []self{{}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := RecordsFromStructs(tt.values, ""); err == nil {
+				t.Error("RecordsFromStructs() expected an error")
+			}
+		})
+	}
+}
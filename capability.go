@@ -0,0 +1,200 @@
+package sfdc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Capability is a feature of the org that depends on the API version or the
+// user's permissions.
+type Capability string
+
+const (
+	// CapabilityBulkV2Ingest is the Bulk 2.0 ingest jobs, from API version 41.0.
+	CapabilityBulkV2Ingest Capability = "BulkV2Ingest"
+	// CapabilityBulkV2Query is the Bulk 2.0 query jobs, from API version 47.0.
+	CapabilityBulkV2Query Capability = "BulkV2Query"
+	// CapabilityCompositeGraph is the Composite Graph API, from API version
+	// 50.0.
+	CapabilityCompositeGraph Capability = "CompositeGraph"
+	// CapabilityHardDelete is the Bulk 2.0 hardDelete jobs, which need the
+	// ingest jobs and the user's Bulk API Hard Delete permission.
+	CapabilityHardDelete Capability = "HardDelete"
+)
+
+// capabilityVersions are the API versions that the capabilities need.
+var capabilityVersions = map[Capability]int{
+	CapabilityBulkV2Ingest:   41,
+	CapabilityBulkV2Query:    47,
+	CapabilityCompositeGraph: 50,
+	CapabilityHardDelete:     41,
+}
+
+// NotPermittedError is returned when the org or the user does not have a
+// capability.
+//
+// Capability is the missing capability.
+//
+// Reason is why the capability is missing, like the API version or the
+// permission that it needs.
+type NotPermittedError struct {
+	Capability Capability
+	Reason     string
+}
+
+func (e *NotPermittedError) Error() string {
+	return fmt.Sprintf("sfdc capability: %s is not permitted: %s", e.Capability, e.Reason)
+}
+
+// CapabilitySet is the capabilities of the org and the user of a session.
+//
+// Version is the API version that the capabilities were probed for.
+//
+// LatestVersion is the latest API version of the org.
+type CapabilitySet struct {
+	Version       int
+	LatestVersion int
+	missing       map[Capability]string
+}
+
+// Has returns whether the capability is granted.  An unknown capability is
+// not granted.
+func (s CapabilitySet) Has(capability Capability) bool {
+	if _, known := capabilityVersions[capability]; known == false {
+		return false
+	}
+	_, missing := s.missing[capability]
+	return missing == false
+}
+
+// Require returns a *NotPermittedError for the first of the capabilities that
+// is not granted.
+func (s CapabilitySet) Require(capabilities ...Capability) error {
+	for _, capability := range capabilities {
+		if s.Has(capability) {
+			continue
+		}
+		reason, has := s.missing[capability]
+		if has == false {
+			reason = "it is not a known capability"
+		}
+		return &NotPermittedError{
+			Capability: capability,
+			Reason:     reason,
+		}
+	}
+	return nil
+}
+
+// CapabilitySession is the session that the capabilities are probed with,
+// which session.ServiceFormatter implements.
+type CapabilitySession interface {
+	InstanceURL() string
+	Version() int
+	AuthorizationHeader(*http.Request)
+	Client() *http.Client
+}
+
+// CapabilityProvider is the interface implemented by sessions that keep the
+// probed capabilities, so they are not probed for every check.
+type CapabilityProvider interface {
+	Capabilities(ctx context.Context) (CapabilitySet, error)
+}
+
+// Capabilities probes the capabilities of the session's org and user with two
+// requests: the org's API versions and the user's permissions.  The API
+// version of the context, if any, is used instead of the session's version.
+func Capabilities(ctx context.Context, session CapabilitySession) (CapabilitySet, error) {
+	version, ok := APIVersion(ctx)
+	if ok == false {
+		version = session.Version()
+	}
+	instanceURL := strings.TrimRight(session.InstanceURL(), "/")
+
+	var versions []struct {
+		Version string `json:"version"`
+	}
+	if err := probe(ctx, session, instanceURL+"/services/data/", &versions); err != nil {
+		return CapabilitySet{}, fmt.Errorf("sfdc capability: api versions: %w", err)
+	}
+	set := CapabilitySet{
+		Version: version,
+		missing: make(map[Capability]string),
+	}
+	for _, v := range versions {
+		number, err := strconv.ParseFloat(v.Version, 64)
+		if err != nil {
+			return CapabilitySet{}, fmt.Errorf("sfdc capability: api version %q: %w", v.Version, err)
+		}
+		if int(number) > set.LatestVersion {
+			set.LatestVersion = int(number)
+		}
+	}
+	for capability, needed := range capabilityVersions {
+		switch {
+		case set.LatestVersion < needed:
+			set.missing[capability] = fmt.Sprintf("the org's latest API version %d is before %d", set.LatestVersion, needed)
+		case version < needed:
+			set.missing[capability] = fmt.Sprintf("API version %d is before %d", version, needed)
+		}
+	}
+
+	var permissions struct {
+		Records []struct {
+			BulkAPIHardDelete bool `json:"PermissionsBulkApiHardDelete"`
+		} `json:"records"`
+	}
+	query := url.Values{}
+	query.Set("q", "SELECT PermissionsBulkApiHardDelete FROM UserPermissionAccess")
+	permissionsURL := fmt.Sprintf("%s/services/data/v%d.0/query/?%s", instanceURL, version, query.Encode())
+	if err := probe(ctx, session, permissionsURL, &permissions); err != nil {
+		return CapabilitySet{}, fmt.Errorf("sfdc capability: user permissions: %w", err)
+	}
+	if len(permissions.Records) == 0 || permissions.Records[0].BulkAPIHardDelete == false {
+		if _, missing := set.missing[CapabilityHardDelete]; missing == false {
+			set.missing[CapabilityHardDelete] = "the user does not have the Bulk API Hard Delete permission"
+		}
+	}
+	return set, nil
+}
+
+// RequireCapabilities returns a *NotPermittedError for the first of the
+// capabilities that the session does not have.  The capabilities of a
+// CapabilityProvider session are its kept ones, and the others are probed.
+func RequireCapabilities(ctx context.Context, session CapabilitySession, capabilities ...Capability) error {
+	var set CapabilitySet
+	var err error
+	if provider, ok := session.(CapabilityProvider); ok {
+		set, err = provider.Capabilities(ctx)
+	} else {
+		set, err = Capabilities(ctx, session)
+	}
+	if err != nil {
+		return err
+	}
+	return set.Require(capabilities...)
+}
+
+func probe(ctx context.Context, session CapabilitySession, url string, value interface{}) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	request.Header.Add("Accept", "application/json")
+	session.AuthorizationHeader(request)
+
+	response, err := session.Client().Do(request)
+	if err != nil {
+		return err
+	}
+	defer CloseResponse(response)
+	if response.StatusCode != http.StatusOK {
+		return HandleError(response)
+	}
+	return json.NewDecoder(response.Body).Decode(value)
+}
@@ -0,0 +1,213 @@
+package sfdc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type capabilityRoundTripper func(request *http.Request) *http.Response
+
+func (f capabilityRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	return f(request), nil
+}
+
+// capabilitySession answers the probes with the org's latest API version and
+// the user's hard delete permission, and counts the probes.
+type capabilitySession struct {
+	version    int
+	latest     string
+	hardDelete bool
+	denied     bool
+	probes     int
+}
+
+func (s *capabilitySession) InstanceURL() string {
+	return "https://test.salesforce.com"
+}
+
+func (s *capabilitySession) Version() int {
+	return s.version
+}
+
+func (s *capabilitySession) AuthorizationHeader(request *http.Request) {
+	request.Header.Add("Authorization", "Bearer token")
+}
+
+func (s *capabilitySession) Client() *http.Client {
+	return &http.Client{
+		Transport: capabilityRoundTripper(func(request *http.Request) *http.Response {
+			s.probes++
+			body := fmt.Sprintf(`[{"label": "Winter '20", "url": "/services/data/v47.0", "version": "47.0"}, {"label": "Latest", "url": "/services/data/v%[1]s", "version": %[1]q}]`, s.latest)
+			status := http.StatusOK
+			if request.URL.Path != "/services/data/" {
+				body = fmt.Sprintf(`{"totalSize": 1, "done": true, "records": [{"PermissionsBulkApiHardDelete": %t}]}`, s.hardDelete)
+				if s.denied {
+					status = http.StatusBadRequest
+					body = `[{"errorCode": "INVALID_TYPE", "message": "sObject type 'UserPermissionAccess' is not supported."}]`
+				}
+			}
+			return &http.Response{
+				StatusCode: status,
+				Status:     fmt.Sprintf("%d %s", status, http.StatusText(status)),
+				Body:       io.NopCloser(strings.NewReader(body)),
+				Header:     make(http.Header),
+				Request:    request,
+			}
+		}),
+	}
+}
+
+func TestCapabilities(t *testing.T) {
+	tests := []struct {
+		name        string
+		session     *capabilitySession
+		ctx         context.Context
+		wantLatest  int
+		wantHas     []Capability
+		wantMissing map[Capability]string
+	}{
+		{
+			name:       "granted",
+			session:    &capabilitySession{version: 58, latest: "58.0", hardDelete: true},
+			ctx:        context.Background(),
+			wantLatest: 58,
+			wantHas:    []Capability{CapabilityBulkV2Ingest, CapabilityBulkV2Query, CapabilityCompositeGraph, CapabilityHardDelete},
+		},
+		{
+			name:       "hard delete permission denied",
+			session:    &capabilitySession{version: 58, latest: "58.0"},
+			ctx:        context.Background(),
+			wantLatest: 58,
+			wantHas:    []Capability{CapabilityBulkV2Ingest, CapabilityBulkV2Query, CapabilityCompositeGraph},
+			wantMissing: map[Capability]string{
+				CapabilityHardDelete: "the user does not have the Bulk API Hard Delete permission",
+			},
+		},
+		{
+			name:       "session version",
+			session:    &capabilitySession{version: 48, latest: "58.0", hardDelete: true},
+			ctx:        context.Background(),
+			wantLatest: 58,
+			wantHas:    []Capability{CapabilityBulkV2Ingest, CapabilityBulkV2Query, CapabilityHardDelete},
+			wantMissing: map[Capability]string{
+				CapabilityCompositeGraph: "API version 48 is before 50",
+			},
+		},
+		{
+			name:       "context version",
+			session:    &capabilitySession{version: 58, latest: "58.0", hardDelete: true},
+			ctx:        WithAPIVersion(context.Background(), 45),
+			wantLatest: 58,
+			wantHas:    []Capability{CapabilityBulkV2Ingest, CapabilityHardDelete},
+			wantMissing: map[Capability]string{
+				CapabilityBulkV2Query:    "API version 45 is before 47",
+				CapabilityCompositeGraph: "API version 45 is before 50",
+			},
+		},
+		{
+			name:       "org version",
+			session:    &capabilitySession{version: 47, latest: "49.0", hardDelete: true},
+			ctx:        context.Background(),
+			wantLatest: 49,
+			wantHas:    []Capability{CapabilityBulkV2Ingest, CapabilityBulkV2Query, CapabilityHardDelete},
+			wantMissing: map[Capability]string{
+				CapabilityCompositeGraph: "the org's latest API version 49 is before 50",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set, err := Capabilities(tt.ctx, tt.session)
+			if err != nil {
+				t.Fatalf("Capabilities() error = %v", err)
+			}
+			if set.LatestVersion != tt.wantLatest {
+				t.Errorf("Capabilities() latest version = %d, want %d", set.LatestVersion, tt.wantLatest)
+			}
+			for _, capability := range tt.wantHas {
+				if set.Has(capability) == false {
+					t.Errorf("CapabilitySet.Has(%s) = false, want true", capability)
+				}
+			}
+			if err := set.Require(tt.wantHas...); err != nil {
+				t.Errorf("CapabilitySet.Require() error = %v, want nil", err)
+			}
+			for capability, reason := range tt.wantMissing {
+				if set.Has(capability) {
+					t.Errorf("CapabilitySet.Has(%s) = true, want false", capability)
+				}
+				var notPermitted *NotPermittedError
+				if err := set.Require(capability); errors.As(err, &notPermitted) == false {
+					t.Errorf("CapabilitySet.Require(%s) error = %v, want a *NotPermittedError", capability, err)
+				} else if notPermitted.Capability != capability || notPermitted.Reason != reason {
+					t.Errorf("CapabilitySet.Require(%s) error = %+v, want %q", capability, notPermitted, reason)
+				}
+			}
+			if tt.session.probes != 2 {
+				t.Errorf("Capabilities() probes = %d, want 2", tt.session.probes)
+			}
+		})
+	}
+}
+
+func TestCapabilities_probeError(t *testing.T) {
+	session := &capabilitySession{version: 58, latest: "58.0", denied: true}
+	_, err := Capabilities(context.Background(), session)
+	if err == nil || strings.Contains(err.Error(), "INVALID_TYPE") == false {
+		t.Errorf("Capabilities() error = %v, want the probe's error", err)
+	}
+}
+
+func TestCapabilitySet_Require_unknown(t *testing.T) {
+	set, err := Capabilities(context.Background(), &capabilitySession{version: 58, latest: "58.0", hardDelete: true})
+	if err != nil {
+		t.Fatalf("Capabilities() error = %v", err)
+	}
+	if set.Has("Telepathy") {
+		t.Error("CapabilitySet.Has(Telepathy) = true, want false")
+	}
+	var notPermitted *NotPermittedError
+	if err := set.Require(CapabilityBulkV2Ingest, "Telepathy"); errors.As(err, &notPermitted) == false || notPermitted.Capability != "Telepathy" {
+		t.Errorf("CapabilitySet.Require() error = %v, want the unknown capability", err)
+	}
+}
+
+// providerSession keeps the capabilities, like session.Session.
+type providerSession struct {
+	capabilitySession
+	set   CapabilitySet
+	calls int
+}
+
+func (s *providerSession) Capabilities(context.Context) (CapabilitySet, error) {
+	s.calls++
+	return s.set, nil
+}
+
+func TestRequireCapabilities(t *testing.T) {
+	session := &capabilitySession{version: 58, latest: "58.0"}
+	var notPermitted *NotPermittedError
+	err := RequireCapabilities(context.Background(), session, CapabilityBulkV2Ingest, CapabilityHardDelete)
+	if errors.As(err, &notPermitted) == false || notPermitted.Capability != CapabilityHardDelete {
+		t.Errorf("RequireCapabilities() error = %v, want the hard delete capability", err)
+	}
+
+	provider := &providerSession{
+		capabilitySession: capabilitySession{version: 58, latest: "58.0"},
+	}
+	provider.set, err = Capabilities(context.Background(), &capabilitySession{version: 58, latest: "58.0", hardDelete: true})
+	if err != nil {
+		t.Fatalf("Capabilities() error = %v", err)
+	}
+	if err := RequireCapabilities(context.Background(), provider, CapabilityHardDelete); err != nil {
+		t.Errorf("RequireCapabilities() error = %v, want nil", err)
+	}
+	if provider.calls != 1 || provider.probes != 0 {
+		t.Errorf("RequireCapabilities() calls = %d, probes = %d, want the kept capabilities", provider.calls, provider.probes)
+	}
+}
@@ -0,0 +1,255 @@
+// Package bulk has the upstream bulk 2.0 API for legacy code.  The resource and
+// job have the upstream method sets, and call the context-based methods of the
+// bulk package with context.Background.
+//
+// Deprecated: use the bulk package.
+package bulk
+
+import (
+	"context"
+	"io"
+
+	"github.com/namely/go-sfdc/v3/bulk"
+	"github.com/namely/go-sfdc/v3/session"
+	"github.com/pkg/errors"
+)
+
+// JobType is the bulk job type.
+//
+// Deprecated: use bulk.JobType.
+type JobType = bulk.JobType
+
+const (
+	// BigObjects is the big objects job.
+	BigObjects = bulk.BigObjects
+	// Classic is the bulk job 1.0.
+	Classic = bulk.Classic
+	// V2Ingest is the bulk job 2.0.
+	V2Ingest = bulk.V2Ingest
+)
+
+// ColumnDelimiter is the column delimiter used for CSV job data.
+//
+// Deprecated: use bulk.ColumnDelimiter.
+type ColumnDelimiter = bulk.ColumnDelimiter
+
+const (
+	// Backquote is the (`) character.
+	Backquote = bulk.Backquote
+	// Caret is the (^) character.
+	Caret = bulk.Caret
+	// Comma is the (,) character.
+	Comma = bulk.Comma
+	// Pipe is the (|) character.
+	Pipe = bulk.Pipe
+	// SemiColon is the (;) character.
+	SemiColon = bulk.SemiColon
+	// Tab is the (\t) character.
+	Tab = bulk.Tab
+)
+
+// ContentType is the format of the data being processed.
+//
+// Deprecated: use bulk.ContentType.
+type ContentType = bulk.ContentType
+
+// CSV is the content data type.
+const CSV = bulk.CSV
+
+// LineEnding is the line ending used for the CSV job data.
+//
+// Deprecated: use bulk.LineEnding.
+type LineEnding = bulk.LineEnding
+
+const (
+	// Linefeed is the (\n) character.
+	Linefeed = bulk.Linefeed
+	// CarriageReturnLinefeed is the (\r\n) character.
+	CarriageReturnLinefeed = bulk.CarriageReturnLinefeed
+)
+
+// Operation is the processing operation for the job.
+//
+// Deprecated: use bulk.Operation.
+type Operation = bulk.Operation
+
+const (
+	// Insert is the object operation for inserting records.
+	Insert = bulk.Insert
+	// Delete is the object operation for deleting records.
+	Delete = bulk.Delete
+	// Update is the object operation for updating records.
+	Update = bulk.Update
+	// Upsert is the object operation for upserting records.
+	Upsert = bulk.Upsert
+)
+
+// State is the current state of processing for the job.
+//
+// Deprecated: use bulk.State.
+type State = bulk.State
+
+const (
+	// Open the job has been created and job data can be uploaded tothe job.
+	Open = bulk.Open
+	// UpdateComplete all data for the job has been uploaded and the job is ready to be queued and processed.
+	UpdateComplete = bulk.UpdateComplete
+	// InProgress the job is being processed by Salesforce.
+	InProgress = bulk.InProgress
+	// Aborted the job has been aborted.
+	Aborted = bulk.Aborted
+	// JobComplete the job was processed by Salesforce.
+	JobComplete = bulk.JobComplete
+	// Failed some records in the job failed.
+	Failed = bulk.Failed
+)
+
+// Options are the options for the job.
+//
+// Deprecated: use bulk.Options.
+type Options = bulk.Options
+
+// Response is the response to job APIs.
+//
+// Deprecated: use bulk.Response.
+type Response = bulk.Response
+
+// Info is the response to the job information API.
+//
+// Deprecated: use bulk.Info.
+type Info = bulk.Info
+
+// Parameters to query all of the bulk jobs.
+//
+// Deprecated: use bulk.Parameters.
+type Parameters = bulk.Parameters
+
+// Jobs presents the response from the all jobs request.
+//
+// Deprecated: use bulk.Jobs.
+type Jobs = bulk.Jobs
+
+// JobRecord is the record from the job.
+//
+// Deprecated: use bulk.JobRecord.
+type JobRecord = bulk.JobRecord
+
+// SuccessfulRecord indicates for the record was created and the data that was uploaded.
+//
+// Deprecated: use bulk.SuccessfulRecord.
+type SuccessfulRecord = bulk.SuccessfulRecord
+
+// FailedRecord indicates why the record failed and the data of the record.
+//
+// Deprecated: use bulk.FailedRecord.
+type FailedRecord = bulk.FailedRecord
+
+// UnprocessedRecord is the unprocessed records from the job.
+//
+// Deprecated: use bulk.UnprocessedRecord.
+type UnprocessedRecord = bulk.UnprocessedRecord
+
+// Resource is the structure that can be used to create bulk 2.0 jobs.
+//
+// Deprecated: use bulk.Resource.
+type Resource struct {
+	resource *bulk.Resource
+}
+
+// NewResource creates a new bulk 2.0 REST resource.  If the session is nil
+// an error will be returned.
+//
+// Deprecated: use bulk.NewResource.
+func NewResource(session session.ServiceFormatter) (*Resource, error) {
+	resource, err := bulk.NewResource(session)
+	if err != nil {
+		return nil, err
+	}
+	return &Resource{resource: resource}, nil
+}
+
+// CreateJob will create a new bulk 2.0 job from the options that where passed.
+// The Job that is returned can be used to upload object data to the Salesforce org.
+//
+// Deprecated: use bulk.Resource.CreateJobContext.
+func (r *Resource) CreateJob(options Options) (*Job, error) {
+	if r.resource == nil {
+		return nil, errors.New("bulk resource: resource may not have been initialized properly")
+	}
+	job, err := r.resource.CreateJobContext(context.Background(), options)
+	if err != nil {
+		return nil, err
+	}
+	return &Job{job: job}, nil
+}
+
+// GetJob will retrieve an existing bulk 2.0 job using the provided ID.
+//
+// Deprecated: use bulk.Resource.GetJob.
+func (r *Resource) GetJob(id string) (*Job, error) {
+	if r.resource == nil {
+		return nil, errors.New("bulk resource: resource may not have been initialized properly")
+	}
+	job, err := r.resource.GetJob(id)
+	if err != nil {
+		return nil, err
+	}
+	return &Job{job: job}, nil
+}
+
+// AllJobs will retrieve all of the bulk 2.0 jobs.
+//
+// Deprecated: use bulk.Resource.AllJobs.
+func (r *Resource) AllJobs(parameters Parameters) (*Jobs, error) {
+	if r.resource == nil {
+		return nil, errors.New("bulk resource: resource may not have been initialized properly")
+	}
+	return r.resource.AllJobs(parameters)
+}
+
+// Job is the bulk job.
+//
+// Deprecated: use bulk.Job.
+type Job struct {
+	job *bulk.Job
+}
+
+// Info returns the current job information.
+func (j *Job) Info() (Info, error) {
+	return j.job.Info()
+}
+
+// Close will close the current job.
+func (j *Job) Close() (Response, error) {
+	return j.job.Close()
+}
+
+// Abort will abort the current job.
+func (j *Job) Abort() (Response, error) {
+	return j.job.Abort()
+}
+
+// Delete will delete the current job.
+func (j *Job) Delete() error {
+	return j.job.Delete()
+}
+
+// Upload will upload data to processing.
+func (j *Job) Upload(body io.Reader) error {
+	return j.job.Upload(body)
+}
+
+// SuccessfulRecords returns the successful records for the job.
+func (j *Job) SuccessfulRecords() ([]SuccessfulRecord, error) {
+	return j.job.SuccessfulRecords(bulk.WithContext(context.Background()))
+}
+
+// FailedRecords returns the failed records for the job.
+func (j *Job) FailedRecords() ([]FailedRecord, error) {
+	return j.job.FailedRecords(bulk.WithContext(context.Background()))
+}
+
+// UnprocessedRecords returns the unprocessed records for the job.
+func (j *Job) UnprocessedRecords() ([]UnprocessedRecord, error) {
+	return j.job.UnprocessedRecords(bulk.WithContext(context.Background()))
+}
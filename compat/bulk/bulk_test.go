@@ -0,0 +1,108 @@
+package bulk
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/namely/go-sfdc/v3/session"
+)
+
+// upstreamJob is the method set of the upstream bulk job, which legacy
+// interfaces and mocks are written against.
+type upstreamJob interface {
+	Info() (Info, error)
+	Upload(body io.Reader) error
+	Close() (Response, error)
+	Abort() (Response, error)
+	Delete() error
+	SuccessfulRecords() ([]SuccessfulRecord, error)
+	FailedRecords() ([]FailedRecord, error)
+	UnprocessedRecords() ([]UnprocessedRecord, error)
+}
+
+var (
+	_ upstreamJob                                               = (*Job)(nil)
+	_ func(session session.ServiceFormatter) (*Resource, error) = NewResource
+)
+
+func TestResource_CreateJob(t *testing.T) {
+	var requests []string
+	client := mockHTTPClient(func(req *http.Request) *http.Response {
+		requests = append(requests, req.Method+" "+req.URL.Path)
+		switch {
+		case req.Method == http.MethodPost:
+			return response(`{"id": "7505fEXAMPLE4C2AAM", "object": "Account", "operation": "insert", "state": "Open"}`)
+		case strings.HasSuffix(req.URL.Path, "/successfulResults"):
+			return response("\"sf__Id\",\"sf__Created\",\"Name\"\n\"001A\",\"true\",\"Acme\"\n")
+		case strings.HasSuffix(req.URL.Path, "/failedResults"):
+			return response("\"sf__Id\",\"sf__Error\",\"Name\"\n\"\",\"REQUIRED_FIELD_MISSING:Name\",\"\"\n")
+		case strings.HasSuffix(req.URL.Path, "/unprocessedrecords"):
+			return response("\"Name\"\n\"Globex\"\n")
+		default:
+			return response(`{"id": "7505fEXAMPLE4C2AAM", "object": "Account", "operation": "insert", "state": "JobComplete"}`)
+		}
+	})
+	resource, err := NewResource(&mockSessionFormatter{
+		url:    "https://test.salesforce.com",
+		client: client,
+	})
+	if err != nil {
+		t.Fatalf("NewResource() error = %v", err)
+	}
+
+	job, err := resource.CreateJob(Options{
+		Object:    "Account",
+		Operation: Insert,
+	})
+	if err != nil {
+		t.Fatalf("Resource.CreateJob() error = %v", err)
+	}
+	info, err := job.Info()
+	if err != nil {
+		t.Fatalf("Job.Info() error = %v", err)
+	}
+	if info.State != JobComplete {
+		t.Errorf("Job.Info() state = %s, want %s", info.State, JobComplete)
+	}
+
+	successful, err := job.SuccessfulRecords()
+	if err != nil || len(successful) != 1 || successful[0].ID != "001A" {
+		t.Errorf("Job.SuccessfulRecords() = %v, %v", successful, err)
+	}
+	failed, err := job.FailedRecords()
+	if err != nil || len(failed) != 1 || failed[0].Error != "REQUIRED_FIELD_MISSING:Name" {
+		t.Errorf("Job.FailedRecords() = %v, %v", failed, err)
+	}
+	unprocessed, err := job.UnprocessedRecords()
+	if err != nil || len(unprocessed) != 1 || unprocessed[0].Fields["Name"] != "Globex" {
+		t.Errorf("Job.UnprocessedRecords() = %v, %v", unprocessed, err)
+	}
+
+	if _, err := resource.GetJob("7505fEXAMPLE4C2AAM"); err != nil {
+		t.Fatalf("Resource.GetJob() error = %v", err)
+	}
+
+	want := []string{
+		"POST /jobs/ingest",
+		"GET /jobs/ingest/7505fEXAMPLE4C2AAM",
+		"GET /jobs/ingest/7505fEXAMPLE4C2AAM/successfulResults",
+		"GET /jobs/ingest/7505fEXAMPLE4C2AAM/failedResults",
+		"GET /jobs/ingest/7505fEXAMPLE4C2AAM/unprocessedrecords",
+		"GET /jobs/ingest/7505fEXAMPLE4C2AAM",
+	}
+	if strings.Join(requests, "\n") != strings.Join(want, "\n") {
+		t.Errorf("requests = %v, want %v", requests, want)
+	}
+}
+
+func TestNewResource(t *testing.T) {
+	if _, err := NewResource(nil); err == nil {
+		t.Error("NewResource() expected an error")
+	}
+	resource := &Resource{}
+	if _, err := resource.CreateJob(Options{}); err == nil {
+		t.Error("Resource.CreateJob() expected an error")
+	}
+}
@@ -0,0 +1,18 @@
+// Package compat has the upstream go-sfdc API for legacy code.
+//
+// The packages keep the upstream entry points, like session.Open, Job.Info and
+// Resource.CreateJob, but some of the constructors and methods have since
+// gained options, which does not change how they are called but does change
+// their signatures, so legacy interfaces, mocks and function values no longer
+// match them.  The subpackages of this package have the upstream identifiers
+// of the bulk, session, soql and sobject packages under their upstream names
+// and signatures, so legacy code only changes its import paths:
+//
+//	import "github.com/namely/go-sfdc/v3/compat/bulk"
+//
+// The subpackages do not take a context, so the cancellation, deadlines and API
+// version overrides of the context-based methods are not available through
+// them.
+//
+// Deprecated: use the context-based methods of the packages.
+package compat
@@ -0,0 +1,36 @@
+// Package session has the upstream session API for legacy code.
+//
+// Deprecated: use the session package.
+package session
+
+import (
+	"github.com/namely/go-sfdc/v3"
+	"github.com/namely/go-sfdc/v3/session"
+)
+
+// Session is the upstream session.
+//
+// Deprecated: use session.Session.
+type Session = session.Session
+
+// Clienter is the upstream HTTP client interface.
+//
+// Deprecated: use session.Clienter.
+type Clienter = session.Clienter
+
+// InstanceFormatter is the upstream instance formatter.
+//
+// Deprecated: use session.InstanceFormatter.
+type InstanceFormatter = session.InstanceFormatter
+
+// ServiceFormatter is the upstream service formatter.
+//
+// Deprecated: use session.ServiceFormatter.
+type ServiceFormatter = session.ServiceFormatter
+
+// Open opens a session with the configuration.
+//
+// Deprecated: use session.Open.
+func Open(config sfdc.Configuration) (*Session, error) {
+	return session.Open(config)
+}
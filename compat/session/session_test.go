@@ -0,0 +1,15 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/namely/go-sfdc/v3"
+)
+
+var _ func(config sfdc.Configuration) (*Session, error) = Open
+
+func TestOpen(t *testing.T) {
+	if _, err := Open(sfdc.Configuration{}); err == nil {
+		t.Error("Open() expected an error")
+	}
+}
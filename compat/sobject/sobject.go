@@ -0,0 +1,23 @@
+// Package sobject has the upstream SObject resources for legacy code.  The
+// inserter, updater and value types are unchanged, so they are used from the
+// sobject package.
+//
+// Deprecated: use the sobject package.
+package sobject
+
+import (
+	"github.com/namely/go-sfdc/v3/session"
+	"github.com/namely/go-sfdc/v3/sobject"
+)
+
+// Resources is the upstream SObject resources.
+//
+// Deprecated: use sobject.Resources.
+type Resources = sobject.Resources
+
+// NewResources returns the SObject resources of the session.
+//
+// Deprecated: use sobject.NewResources.
+func NewResources(session session.ServiceFormatter) (*Resources, error) {
+	return sobject.NewResources(session)
+}
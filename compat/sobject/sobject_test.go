@@ -0,0 +1,35 @@
+package sobject
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/namely/go-sfdc/v3/session"
+)
+
+var _ func(session session.ServiceFormatter) (*Resources, error) = NewResources
+
+func TestResources_Describe(t *testing.T) {
+	var path string
+	client := mockHTTPClient(func(req *http.Request) *http.Response {
+		path = req.URL.Path
+		return response(`{"name": "Account", "fields": [{"name": "Name"}]}`)
+	})
+	resources, err := NewResources(&mockSessionFormatter{
+		url:    "https://test.salesforce.com/services/data/v42.0",
+		client: client,
+	})
+	if err != nil {
+		t.Fatalf("NewResources() error = %v", err)
+	}
+	describe, err := resources.Describe("Account")
+	if err != nil {
+		t.Fatalf("Resources.Describe() error = %v", err)
+	}
+	if path != "/services/data/v42.0/sobjects/Account/describe" || describe.Name != "Account" {
+		t.Errorf("Resources.Describe() = %v, path %s", describe.Name, path)
+	}
+	if _, err := NewResources(nil); err == nil {
+		t.Error("NewResources() expected an error")
+	}
+}
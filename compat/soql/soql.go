@@ -0,0 +1,37 @@
+// Package soql has the upstream SOQL resource for legacy code.  The query
+// builders are unchanged, so they are used from the soql package.
+//
+// Deprecated: use the soql package.
+package soql
+
+import (
+	"github.com/namely/go-sfdc/v3/session"
+	"github.com/namely/go-sfdc/v3/soql"
+)
+
+// Resource is the upstream SOQL resource.
+//
+// Deprecated: use soql.Resource.
+type Resource = soql.Resource
+
+// QueryFormatter is the upstream query formatter.
+//
+// Deprecated: use soql.QueryFormatter.
+type QueryFormatter = soql.QueryFormatter
+
+// QueryResult is the upstream query result.
+//
+// Deprecated: use soql.QueryResult.
+type QueryResult = soql.QueryResult
+
+// QueryRecord is the upstream query record.
+//
+// Deprecated: use soql.QueryRecord.
+type QueryRecord = soql.QueryRecord
+
+// NewResource returns the SOQL resource of the session.
+//
+// Deprecated: use soql.NewResource.
+func NewResource(session session.ServiceFormatter) (*Resource, error) {
+	return soql.NewResource(session)
+}
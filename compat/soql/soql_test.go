@@ -0,0 +1,43 @@
+package soql
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/namely/go-sfdc/v3/session"
+)
+
+var _ func(session session.ServiceFormatter) (*Resource, error) = NewResource
+
+type mockQuerier struct {
+	stmt string
+}
+
+func (mock *mockQuerier) Format() (string, error) {
+	return mock.stmt, nil
+}
+
+func TestResource_Query(t *testing.T) {
+	var query string
+	client := mockHTTPClient(func(req *http.Request) *http.Response {
+		query = req.URL.Query().Get("q")
+		return response(`{"done": true, "totalSize": 1, "records": [{"attributes": {"type": "Account", "url": "/services/data/v42.0/sobjects/Account/001A"}, "Name": "Acme"}]}`)
+	})
+	resource, err := NewResource(&mockSessionFormatter{
+		url:    "https://test.salesforce.com/services/data/v42.0",
+		client: client,
+	})
+	if err != nil {
+		t.Fatalf("NewResource() error = %v", err)
+	}
+	result, err := resource.Query(&mockQuerier{stmt: "SELECT Name FROM Account"}, false)
+	if err != nil {
+		t.Fatalf("Resource.Query() error = %v", err)
+	}
+	if query != "SELECT Name FROM Account" || result.TotalSize() != 1 {
+		t.Errorf("Resource.Query() = %v, query %s", result, query)
+	}
+	if _, err := NewResource(nil); err == nil {
+		t.Error("NewResource() expected an error")
+	}
+}
@@ -2,6 +2,7 @@ package batch
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -49,18 +50,18 @@ type Resource struct {
 
 // NewResource creates a new resourse with the session.  If the session is
 // nil an error will be returned.
-func NewResource(session session.ServiceFormatter) (*Resource, error) {
-	if session == nil {
+func NewResource(formatter session.ServiceFormatter) (*Resource, error) {
+	if formatter == nil {
 		return nil, errors.New("composite: session can not be nil")
 	}
 
-	err := session.Refresh()
+	err := session.RefreshResource(formatter)
 	if err != nil {
 		return nil, errors.Wrap(err, "session refresh")
 	}
 
 	return &Resource{
-		session: session,
+		session: formatter,
 	}, nil
 }
 
@@ -68,6 +69,11 @@ func NewResource(session session.ServiceFormatter) (*Resource, error) {
 // order of the array is the order in which the subrequests are
 // placed in the composite batch body.
 func (r *Resource) Retrieve(haltOnError bool, requesters []Subrequester) (Value, error) {
+	return r.RetrieveContext(context.Background(), haltOnError, requesters)
+}
+
+// RetrieveContext is Retrieve using the context for the request.
+func (r *Resource) RetrieveContext(ctx context.Context, haltOnError bool, requesters []Subrequester) (Value, error) {
 	if requesters == nil {
 		return Value{}, errors.New("composite subrequests: requesters can not nil")
 	}
@@ -83,7 +89,7 @@ func (r *Resource) Retrieve(haltOnError bool, requesters []Subrequester) (Value,
 
 	url := r.session.ServiceURL() + endpoint
 
-	request, err := http.NewRequest(http.MethodPost, url, body)
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
 
 	if err != nil {
 		return Value{}, err
@@ -100,7 +106,7 @@ func (r *Resource) Retrieve(haltOnError bool, requesters []Subrequester) (Value,
 	}
 
 	decoder := json.NewDecoder(response.Body)
-	defer response.Body.Close()
+	defer sfdc.CloseResponse(response)
 
 	if response.StatusCode != http.StatusOK {
 		var insertErrs []sfdc.Error
@@ -2,9 +2,11 @@ package composite
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/namely/go-sfdc/v3"
 	"github.com/namely/go-sfdc/v3/session"
@@ -36,6 +38,42 @@ type Subvalue struct {
 	ReferenceID    string            `json:"referenceId"`
 }
 
+// Header returns the subresponse's HTTP header value.  The header
+// key lookup is case insensitive.
+func (s Subvalue) Header(key string) (string, bool) {
+	if value, has := s.HTTPHeaders[key]; has {
+		return value, true
+	}
+	for header, value := range s.HTTPHeaders {
+		if strings.EqualFold(header, key) {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// Location returns the subresponse's Location header, which is the
+// record URL for created records.
+func (s Subvalue) Location() (string, bool) {
+	return s.Header("Location")
+}
+
+// CreatedID returns the record ID from the Location header of a created
+// record subresponse.  If the subresponse is not 201 Created or there is
+// no Location header, false is returned.
+func (s Subvalue) CreatedID() (string, bool) {
+	if s.HTTPStatusCode != http.StatusCreated {
+		return "", false
+	}
+	location, has := s.Location()
+	if has == false {
+		return "", false
+	}
+	location = strings.TrimRight(location, "/")
+	id := location[strings.LastIndex(location, "/")+1:]
+	return id, id != ""
+}
+
 const endpoint = "/composite"
 
 var invalidHTTPHeader = map[string]struct{}{
@@ -53,28 +91,50 @@ var validMethods = map[string]struct{}{
 
 // Resource is the structure that can be just to call composite APIs.
 type Resource struct {
-	session session.ServiceFormatter
+	session   session.ServiceFormatter
+	useNumber bool
+}
+
+// ResourceOption is an option for the composite resource.
+type ResourceOption func(*Resource)
+
+// WithUseNumber will decode the numbers of the subrequests' bodies as
+// json.Number, instead of float64, so large integers and high precision
+// decimals are not rounded.
+func WithUseNumber() ResourceOption {
+	return func(r *Resource) {
+		r.useNumber = true
+	}
 }
 
 // NewResource creates a new resourse with the session.  If the session is
 // nil an error will be returned.
-func NewResource(session session.ServiceFormatter) (*Resource, error) {
-	if session == nil {
+func NewResource(formatter session.ServiceFormatter, options ...ResourceOption) (*Resource, error) {
+	if formatter == nil {
 		return nil, errors.New("composite: session can not be nil")
 	}
 
-	err := session.Refresh()
+	err := session.RefreshResource(formatter)
 	if err != nil {
 		return nil, errors.Wrap(err, "session refresh")
 	}
 
-	return &Resource{
-		session: session,
-	}, nil
+	resource := &Resource{
+		session: formatter,
+	}
+	for _, option := range options {
+		option(resource)
+	}
+	return resource, nil
 }
 
 // Retrieve will retrieve the responses to a composite requests.
 func (r *Resource) Retrieve(allOrNone bool, requesters []Subrequester) (Value, error) {
+	return r.RetrieveContext(context.Background(), allOrNone, requesters)
+}
+
+// RetrieveContext is Retrieve using the context for the request.
+func (r *Resource) RetrieveContext(ctx context.Context, allOrNone bool, requesters []Subrequester) (Value, error) {
 	if requesters == nil {
 		return Value{}, errors.New("composite subrequests: requesters can not nil")
 	}
@@ -90,7 +150,7 @@ func (r *Resource) Retrieve(allOrNone bool, requesters []Subrequester) (Value, e
 
 	url := r.session.ServiceURL() + endpoint
 
-	request, err := http.NewRequest(http.MethodPost, url, body)
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
 
 	if err != nil {
 		return Value{}, err
@@ -107,7 +167,10 @@ func (r *Resource) Retrieve(allOrNone bool, requesters []Subrequester) (Value, e
 	}
 
 	decoder := json.NewDecoder(response.Body)
-	defer response.Body.Close()
+	defer sfdc.CloseResponse(response)
+	if r.useNumber {
+		decoder.UseNumber()
+	}
 
 	if response.StatusCode != http.StatusOK {
 		var insertErrs []sfdc.Error
@@ -164,7 +227,7 @@ func (r *Resource) payload(allOrNone bool, requesters []Subrequester) (*bytes.Re
 			subRequest["body"] = requester.Body()
 		}
 		if requester.HTTPHeaders() != nil {
-			subRequest["httpHeaders"] = requester.HTTPHeaders()
+			subRequest["httpHeaders"] = subrequestHeaders(requester.HTTPHeaders())
 		}
 		subRequests[idx] = subRequest
 	}
@@ -178,3 +241,13 @@ func (r *Resource) payload(allOrNone bool, requesters []Subrequester) (*bytes.Re
 	}
 	return bytes.NewReader(jsonBody), nil
 }
+
+// subrequestHeaders returns the headers of a subrequest as the string values
+// that the composite API takes, joining the values of a header.
+func subrequestHeaders(header http.Header) map[string]string {
+	headers := make(map[string]string, len(header))
+	for key, values := range header {
+		headers[key] = strings.Join(values, ", ")
+	}
+	return headers
+}
@@ -1,7 +1,7 @@
 package composite
 
 import (
-	"io/ioutil"
+	"io"
 	"net/http"
 	"reflect"
 	"strings"
@@ -277,7 +277,7 @@ func TestResource_Retrieve(t *testing.T) {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "Invalid URL",
-								Body:       ioutil.NopCloser(strings.NewReader(req.URL.String())),
+								Body:       io.NopCloser(strings.NewReader(req.URL.String())),
 								Header:     make(http.Header),
 							}
 						}
@@ -344,7 +344,7 @@ func TestResource_Retrieve(t *testing.T) {
 						return &http.Response{
 							StatusCode: http.StatusOK,
 							Status:     "Good",
-							Body:       ioutil.NopCloser(strings.NewReader(resp)),
+							Body:       io.NopCloser(strings.NewReader(resp)),
 							Header:     make(http.Header),
 						}
 
@@ -439,7 +439,7 @@ func TestResource_Retrieve(t *testing.T) {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "Invalid URL",
-								Body:       ioutil.NopCloser(strings.NewReader(req.URL.String())),
+								Body:       io.NopCloser(strings.NewReader(req.URL.String())),
 								Header:     make(http.Header),
 							}
 						}
@@ -454,7 +454,7 @@ func TestResource_Retrieve(t *testing.T) {
 						return &http.Response{
 							StatusCode: http.StatusBadRequest,
 							Status:     "Bad",
-							Body:       ioutil.NopCloser(strings.NewReader(resp)),
+							Body:       io.NopCloser(strings.NewReader(resp)),
 							Header:     make(http.Header),
 						}
 
@@ -492,3 +492,81 @@ func TestResource_Retrieve(t *testing.T) {
 		})
 	}
 }
+
+func TestSubvalue_Location(t *testing.T) {
+	tests := []struct {
+		name         string
+		subvalue     Subvalue
+		wantLocation string
+		wantHas      bool
+		wantID       string
+		wantCreated  bool
+	}{
+		{
+			name: "created with location",
+			subvalue: Subvalue{
+				HTTPHeaders: map[string]string{
+					"Location": "/services/data/v44.0/sobjects/Account/001D000000K0fXOIAZ",
+				},
+				HTTPStatusCode: http.StatusCreated,
+				ReferenceID:    "NewAccount",
+			},
+			wantLocation: "/services/data/v44.0/sobjects/Account/001D000000K0fXOIAZ",
+			wantHas:      true,
+			wantID:       "001D000000K0fXOIAZ",
+			wantCreated:  true,
+		},
+		{
+			name: "ok without location",
+			subvalue: Subvalue{
+				HTTPHeaders:    map[string]string{},
+				HTTPStatusCode: http.StatusOK,
+				ReferenceID:    "NewAccountInfo",
+			},
+			wantLocation: "",
+			wantHas:      false,
+			wantID:       "",
+			wantCreated:  false,
+		},
+		{
+			name: "mixed header casing",
+			subvalue: Subvalue{
+				HTTPHeaders: map[string]string{
+					"LOCATION": "/services/data/v44.0/sobjects/Contact/003D000000QV9n2IAD/",
+				},
+				HTTPStatusCode: http.StatusCreated,
+				ReferenceID:    "NewContact",
+			},
+			wantLocation: "/services/data/v44.0/sobjects/Contact/003D000000QV9n2IAD/",
+			wantHas:      true,
+			wantID:       "003D000000QV9n2IAD",
+			wantCreated:  true,
+		},
+		{
+			name: "location without created",
+			subvalue: Subvalue{
+				HTTPHeaders: map[string]string{
+					"location": "/services/data/v44.0/sobjects/Account/001D000000K0fXOIAZ",
+				},
+				HTTPStatusCode: http.StatusOK,
+				ReferenceID:    "UpdateAccount",
+			},
+			wantLocation: "/services/data/v44.0/sobjects/Account/001D000000K0fXOIAZ",
+			wantHas:      true,
+			wantID:       "",
+			wantCreated:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			location, has := tt.subvalue.Location()
+			if location != tt.wantLocation || has != tt.wantHas {
+				t.Errorf("Subvalue.Location() = %v, %v, want %v, %v", location, has, tt.wantLocation, tt.wantHas)
+			}
+			id, created := tt.subvalue.CreatedID()
+			if id != tt.wantID || created != tt.wantCreated {
+				t.Errorf("Subvalue.CreatedID() = %v, %v, want %v, %v", id, created, tt.wantID, tt.wantCreated)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package composite
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+type contextKey struct{}
+
+func TestResource_RetrieveContext(t *testing.T) {
+	ctx := context.WithValue(context.Background(), contextKey{}, "retrieve")
+	body := newTrackingBody(`[{"errorCode": "INVALID_FIELD", "message": "bad request"}]`)
+	var got context.Context
+	r := &Resource{
+		session: &mockSessionFormatter{
+			url: "https://test.salesforce.com",
+			client: mockHTTPClient(func(req *http.Request) *http.Response {
+				got = req.Context()
+				return &http.Response{
+					StatusCode: http.StatusBadRequest,
+					Status:     "400 Bad Request",
+					Body:       body,
+					Header:     make(http.Header),
+				}
+			}),
+		},
+	}
+	_, err := r.RetrieveContext(ctx, false, []Subrequester{
+		&mockSubrequester{
+			url:         "www.something.com",
+			referenceID: "someID",
+			method:      http.MethodGet,
+		},
+	})
+	if err == nil {
+		t.Fatalf("Resource.RetrieveContext() error = nil, want error")
+	}
+	if got == nil || got.Value(contextKey{}) != "retrieve" {
+		t.Errorf("Resource.RetrieveContext() request context was not the context")
+	}
+	if body.closed == false {
+		t.Errorf("Resource.RetrieveContext() response body was not closed")
+	}
+}
@@ -0,0 +1,173 @@
+package composite
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/namely/go-sfdc/v3"
+	"github.com/namely/go-sfdc/v3/sobject"
+	"github.com/pkg/errors"
+)
+
+var (
+	sobjectPattern   = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*$`)
+	referencePattern = regexp.MustCompile(`^@\{[a-zA-Z0-9_]+(\.[a-zA-Z0-9_.\[\]]+)+\}$`)
+)
+
+// DMLSubrequest is a subrequest that inserts, updates or deletes a record with
+// the SObject resource.
+type DMLSubrequest struct {
+	url         string
+	referenceID string
+	method      string
+	body        map[string]interface{}
+}
+
+// NewInsertSubrequest returns the subrequest that inserts the record of the
+// SObject with the fields, for the API version.  The field values can refer to
+// the earlier subrequests, like @{NewAccount.id}, and are sent as they are.
+func NewInsertSubrequest(version int, referenceID, sobjectName string, fields map[string]interface{}) (*DMLSubrequest, error) {
+	if err := validateDML("insert", version, referenceID, sobjectName); err != nil {
+		return nil, err
+	}
+	if fields == nil {
+		return nil, errors.New("composite insert subrequest: fields can not be nil")
+	}
+	return &DMLSubrequest{
+		url:         fmt.Sprintf("/services/data/v%d.0/sobjects/%s", version, sobjectName),
+		referenceID: referenceID,
+		method:      http.MethodPost,
+		body:        copyFields(fields),
+	}, nil
+}
+
+// NewUpdateSubrequest returns the subrequest that updates the fields of the
+// SObject's record with the ID, for the API version.  The ID is a 15 or 18
+// character Salesforce ID or a reference to an earlier subrequest, like
+// @{NewAccount.id}, which is not escaped in the URL.
+func NewUpdateSubrequest(version int, referenceID, sobjectName, id string, fields map[string]interface{}) (*DMLSubrequest, error) {
+	if err := validateDML("update", version, referenceID, sobjectName); err != nil {
+		return nil, err
+	}
+	if err := validateRecordID("update", id); err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, errors.New("composite update subrequest: fields can not be empty")
+	}
+	return &DMLSubrequest{
+		url:         fmt.Sprintf("/services/data/v%d.0/sobjects/%s/%s", version, sobjectName, id),
+		referenceID: referenceID,
+		method:      http.MethodPatch,
+		body:        copyFields(fields),
+	}, nil
+}
+
+// NewDeleteSubrequest returns the subrequest that deletes the SObject's record
+// with the ID, for the API version.  The ID is like the one of
+// NewUpdateSubrequest.
+func NewDeleteSubrequest(version int, referenceID, sobjectName, id string) (*DMLSubrequest, error) {
+	if err := validateDML("delete", version, referenceID, sobjectName); err != nil {
+		return nil, err
+	}
+	if err := validateRecordID("delete", id); err != nil {
+		return nil, err
+	}
+	return &DMLSubrequest{
+		url:         fmt.Sprintf("/services/data/v%d.0/sobjects/%s/%s", version, sobjectName, id),
+		referenceID: referenceID,
+		method:      http.MethodDelete,
+	}, nil
+}
+
+// URL returns the URL of the record.
+func (d *DMLSubrequest) URL() string {
+	return d.url
+}
+
+// ReferenceID returns the reference ID of the subrequest.
+func (d *DMLSubrequest) ReferenceID() string {
+	return d.referenceID
+}
+
+// Method returns POST, PATCH or DELETE.
+func (d *DMLSubrequest) Method() string {
+	return d.method
+}
+
+// HTTPHeaders returns nil, as the subrequest does not have headers.
+func (d *DMLSubrequest) HTTPHeaders() http.Header {
+	return nil
+}
+
+// Body returns the fields of an insert or update, and nil for a delete.
+func (d *DMLSubrequest) Body() map[string]interface{} {
+	return d.body
+}
+
+// InsertValue returns the value of a DML subresponse.  The body of a created
+// record is its value, and an updated or deleted record, which does not have a
+// body, is successful.  A failed subresponse is returned with its errors, which
+// are also the sfdc.Errors error.  A failed subresponse without errors is an
+// error with its status code.
+func (s Subvalue) InsertValue() (sobject.InsertValue, error) {
+	if s.HTTPStatusCode >= 400 {
+		if errs, has := s.Errors(); has {
+			return sobject.InsertValue{Errors: errs}, errs
+		}
+		return sobject.InsertValue{}, fmt.Errorf("composite subresponse: %s failed with HTTP %d", s.ReferenceID, s.HTTPStatusCode)
+	}
+	if s.Body == nil {
+		return sobject.InsertValue{Success: true}, nil
+	}
+	body, err := json.Marshal(s.Body)
+	if err != nil {
+		return sobject.InsertValue{}, errors.Wrapf(err, "composite subresponse: %s", s.ReferenceID)
+	}
+	var value sobject.InsertValue
+	if err := json.Unmarshal(body, &value); err != nil {
+		return sobject.InsertValue{}, errors.Wrapf(err, "composite subresponse: %s body is not a record value", s.ReferenceID)
+	}
+	return value, nil
+}
+
+// Subvalue returns the subresponse with the reference ID.  If there is no
+// subresponse with the reference ID, false is returned.
+func (v Value) Subvalue(referenceID string) (Subvalue, bool) {
+	for _, subvalue := range v.Response {
+		if subvalue.ReferenceID == referenceID {
+			return subvalue, true
+		}
+	}
+	return Subvalue{}, false
+}
+
+func validateDML(operation string, version int, referenceID, sobjectName string) error {
+	if version <= 0 {
+		return errors.Errorf("composite %s subrequest: version %d is not valid", operation, version)
+	}
+	if referenceID == "" {
+		return errors.Errorf("composite %s subrequest: reference id can not be empty", operation)
+	}
+	if sobjectPattern.MatchString(sobjectName) == false {
+		return errors.Errorf("composite %s subrequest: %q is not a valid sobject", operation, sobjectName)
+	}
+	return nil
+}
+
+func validateRecordID(operation, id string) error {
+	if sfdc.ValidID(id) == false && referencePattern.MatchString(id) == false {
+		return errors.Errorf("composite %s subrequest: %q is not an id or a reference", operation, id)
+	}
+	return nil
+}
+
+func copyFields(fields map[string]interface{}) map[string]interface{} {
+	body := make(map[string]interface{}, len(fields))
+	for field, value := range fields {
+		body[field] = value
+	}
+	return body
+}
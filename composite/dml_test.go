@@ -0,0 +1,224 @@
+package composite
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/namely/go-sfdc/v3"
+	"github.com/namely/go-sfdc/v3/sobject"
+)
+
+func TestNewDMLSubrequest(t *testing.T) {
+	tests := []struct {
+		name       string
+		subrequest func() (*DMLSubrequest, error)
+		wantURL    string
+		wantMethod string
+		wantBody   map[string]interface{}
+		wantErr    bool
+	}{
+		{
+			name: "insert",
+			subrequest: func() (*DMLSubrequest, error) {
+				return NewInsertSubrequest(58, "NewContact", "Contact", map[string]interface{}{"LastName": "Doe", "AccountId": "@{NewAccount.id}"})
+			},
+			wantURL:    "/services/data/v58.0/sobjects/Contact",
+			wantMethod: "POST",
+			wantBody:   map[string]interface{}{"LastName": "Doe", "AccountId": "@{NewAccount.id}"},
+		},
+		{
+			name: "update",
+			subrequest: func() (*DMLSubrequest, error) {
+				return NewUpdateSubrequest(58, "UpdateAccount", "Account", "001R0000003fSbRIAU", map[string]interface{}{"Rating": "Hot"})
+			},
+			wantURL:    "/services/data/v58.0/sobjects/Account/001R0000003fSbRIAU",
+			wantMethod: "PATCH",
+			wantBody:   map[string]interface{}{"Rating": "Hot"},
+		},
+		{
+			name: "update with a reference",
+			subrequest: func() (*DMLSubrequest, error) {
+				return NewUpdateSubrequest(58, "UpdateAccount", "Account", "@{NewAccount.id}", map[string]interface{}{"ParentId": "@{Parents.records[0].Id}"})
+			},
+			wantURL:    "/services/data/v58.0/sobjects/Account/@{NewAccount.id}",
+			wantMethod: "PATCH",
+			wantBody:   map[string]interface{}{"ParentId": "@{Parents.records[0].Id}"},
+		},
+		{
+			name: "delete",
+			subrequest: func() (*DMLSubrequest, error) {
+				return NewDeleteSubrequest(58, "DeleteAccount", "Account", "@{NewAccount.id}")
+			},
+			wantURL:    "/services/data/v58.0/sobjects/Account/@{NewAccount.id}",
+			wantMethod: "DELETE",
+		},
+		{
+			name: "no version",
+			subrequest: func() (*DMLSubrequest, error) {
+				return NewInsertSubrequest(0, "NewAccount", "Account", map[string]interface{}{})
+			},
+			wantErr: true,
+		},
+		{
+			name: "no reference id",
+			subrequest: func() (*DMLSubrequest, error) {
+				return NewDeleteSubrequest(58, "", "Account", "001R0000003fSbRIAU")
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid sobject",
+			subrequest: func() (*DMLSubrequest, error) {
+				return NewInsertSubrequest(58, "NewAccount", "Account/001", map[string]interface{}{})
+			},
+			wantErr: true,
+		},
+		{
+			name: "nil insert fields",
+			subrequest: func() (*DMLSubrequest, error) {
+				return NewInsertSubrequest(58, "NewAccount", "Account", nil)
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty update fields",
+			subrequest: func() (*DMLSubrequest, error) {
+				return NewUpdateSubrequest(58, "UpdateAccount", "Account", "001R0000003fSbRIAU", map[string]interface{}{})
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid id",
+			subrequest: func() (*DMLSubrequest, error) {
+				return NewDeleteSubrequest(58, "DeleteAccount", "Account", "001R0000003fSbRIAU/x")
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid reference",
+			subrequest: func() (*DMLSubrequest, error) {
+				return NewDeleteSubrequest(58, "DeleteAccount", "Account", "@{NewAccount}")
+			},
+			wantErr: true,
+		},
+		{
+			name: "reference with a path",
+			subrequest: func() (*DMLSubrequest, error) {
+				return NewDeleteSubrequest(58, "DeleteAccount", "Account", "@{NewAccount.id/../x?y=1}")
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subrequest, err := tt.subrequest()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("subrequest error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			r := &Resource{}
+			if err := r.validateSubrequests([]Subrequester{subrequest}); err != nil {
+				t.Fatalf("Resource.validateSubrequests() error = %v", err)
+			}
+			reader, err := r.payload(false, []Subrequester{subrequest})
+			if err != nil {
+				t.Fatalf("Resource.payload() error = %v", err)
+			}
+			body, err := io.ReadAll(reader)
+			if err != nil {
+				t.Fatalf("io.ReadAll() error = %v", err)
+			}
+			var payload struct {
+				CompositeRequest []struct {
+					URL    string                 `json:"url"`
+					Method string                 `json:"method"`
+					Body   map[string]interface{} `json:"body"`
+				} `json:"compositeRequest"`
+			}
+			if err := json.Unmarshal(body, &payload); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			got := payload.CompositeRequest[0]
+			if got.URL != tt.wantURL || got.Method != tt.wantMethod {
+				t.Errorf("subrequest = %s %s, want %s %s", got.Method, got.URL, tt.wantMethod, tt.wantURL)
+			}
+			if !reflect.DeepEqual(got.Body, tt.wantBody) {
+				t.Errorf("subrequest body = %v, want %v", got.Body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestSubvalue_InsertValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		subvalue Subvalue
+		want     sobject.InsertValue
+		wantErr  bool
+	}{
+		{
+			name: "created",
+			subvalue: Subvalue{
+				HTTPStatusCode: 201,
+				Body:           map[string]interface{}{"id": "001R0000003fSbRIAU", "success": true, "errors": []interface{}{}},
+			},
+			want: sobject.InsertValue{Success: true, ID: "001R0000003fSbRIAU", Errors: []sfdc.Error{}},
+		},
+		{
+			name:     "updated",
+			subvalue: Subvalue{HTTPStatusCode: 204},
+			want:     sobject.InsertValue{Success: true},
+		},
+		{
+			name: "failed",
+			subvalue: Subvalue{
+				HTTPStatusCode: 400,
+				Body:           []interface{}{map[string]interface{}{"errorCode": "REQUIRED_FIELD_MISSING", "message": "Required fields are missing: [LastName]", "fields": []interface{}{"LastName"}}},
+			},
+			want: sobject.InsertValue{
+				Errors: []sfdc.Error{{ErrorCode: "REQUIRED_FIELD_MISSING", Message: "Required fields are missing: [LastName]", Fields: []string{"LastName"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name:     "failed without errors",
+			subvalue: Subvalue{HTTPStatusCode: 500},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.subvalue.InsertValue()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Subvalue.InsertValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Subvalue.InsertValue() = %+v, want %+v", got, tt.want)
+			}
+			var errs sfdc.Errors
+			if len(tt.want.Errors) > 0 && errors.As(err, &errs) == false {
+				t.Errorf("Subvalue.InsertValue() error = %v, want sfdc.Errors", err)
+			}
+		})
+	}
+}
+
+func TestValue_Subvalue(t *testing.T) {
+	value := Value{
+		Response: []Subvalue{
+			{ReferenceID: "NewAccount", HTTPStatusCode: 201},
+			{ReferenceID: "NewContact", HTTPStatusCode: 400},
+		},
+	}
+	if subvalue, has := value.Subvalue("NewContact"); has == false || subvalue.HTTPStatusCode != 400 {
+		t.Errorf("Value.Subvalue() = %+v, %t, want NewContact", subvalue, has)
+	}
+	if _, has := value.Subvalue("Missing"); has {
+		t.Error("Value.Subvalue() = true, want false")
+	}
+}
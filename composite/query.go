@@ -0,0 +1,105 @@
+package composite
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/namely/go-sfdc/v3"
+	"github.com/pkg/errors"
+)
+
+// QuerySubrequest is a subrequest of a SOQL query.
+type QuerySubrequest struct {
+	url         string
+	referenceID string
+	header      http.Header
+}
+
+// QueryOption is an option for the query subrequest.
+type QueryOption func(*queryOptions)
+
+type queryOptions struct {
+	all       bool
+	batchSize int
+}
+
+// WithQueryAll will query the queryAll endpoint, which includes the deleted
+// and archived records.
+func WithQueryAll() QueryOption {
+	return func(o *queryOptions) {
+		o.all = true
+	}
+}
+
+// WithQueryBatchSize will ask for the query results in batches of the size,
+// from sfdc.MinQueryBatchSize to sfdc.MaxQueryBatchSize records, with the
+// Sforce-Query-Options header of the subrequest.
+func WithQueryBatchSize(size int) QueryOption {
+	return func(o *queryOptions) {
+		o.batchSize = size
+	}
+}
+
+// NewQuerySubrequest returns the subrequest of the SOQL query with the API
+// version.  The query can refer to the earlier subrequests, like
+// @{NewAccount.id}.
+func NewQuerySubrequest(version int, referenceID, query string, options ...QueryOption) (*QuerySubrequest, error) {
+	if version <= 0 {
+		return nil, errors.Errorf("composite query subrequest: version %d is not valid", version)
+	}
+	if referenceID == "" {
+		return nil, errors.New("composite query subrequest: reference id can not be empty")
+	}
+	if query == "" {
+		return nil, errors.New("composite query subrequest: query can not be empty")
+	}
+	var opts queryOptions
+	for _, option := range options {
+		option(&opts)
+	}
+
+	endpoint := "query"
+	if opts.all {
+		endpoint = "queryAll"
+	}
+	form := url.Values{}
+	form.Add("q", query)
+	subrequest := &QuerySubrequest{
+		url:         fmt.Sprintf("/services/data/v%d.0/%s/?%s", version, endpoint, form.Encode()),
+		referenceID: referenceID,
+	}
+	if opts.batchSize != 0 {
+		value, err := sfdc.QueryBatchSize(opts.batchSize)
+		if err != nil {
+			return nil, errors.Wrap(err, "composite query subrequest")
+		}
+		subrequest.header = http.Header{sfdc.QueryOptionsHeader: []string{value}}
+	}
+	return subrequest, nil
+}
+
+// URL returns the URL of the query.
+func (q *QuerySubrequest) URL() string {
+	return q.url
+}
+
+// ReferenceID returns the reference ID of the subrequest.
+func (q *QuerySubrequest) ReferenceID() string {
+	return q.referenceID
+}
+
+// Method returns GET.
+func (q *QuerySubrequest) Method() string {
+	return http.MethodGet
+}
+
+// HTTPHeaders returns the query options header, if there are options.
+func (q *QuerySubrequest) HTTPHeaders() http.Header {
+	return q.header
+}
+
+// Body returns nil, as a query does not have a body.
+func (q *QuerySubrequest) Body() map[string]interface{} {
+	return nil
+}
@@ -0,0 +1,109 @@
+package composite
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+)
+
+func TestNewQuerySubrequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		version     int
+		referenceID string
+		query       string
+		options     []QueryOption
+		wantURL     string
+		wantHeaders map[string]string
+		wantErr     bool
+	}{
+		{
+			name:        "query",
+			version:     58,
+			referenceID: "Accounts",
+			query:       "SELECT Id FROM Account",
+			wantURL:     "/services/data/v58.0/query/?q=SELECT+Id+FROM+Account",
+		},
+		{
+			name:        "query all with batch size",
+			version:     58,
+			referenceID: "Accounts",
+			query:       "SELECT Id FROM Account WHERE Name = '@{NewAccount.Name}'",
+			options:     []QueryOption{WithQueryAll(), WithQueryBatchSize(500)},
+			wantURL:     "/services/data/v58.0/queryAll/?q=SELECT+Id+FROM+Account+WHERE+Name+%3D+%27%40%7BNewAccount.Name%7D%27",
+			wantHeaders: map[string]string{"Sforce-Query-Options": "batchSize=500"},
+		},
+		{
+			name:        "batch size too small",
+			version:     58,
+			referenceID: "Accounts",
+			query:       "SELECT Id FROM Account",
+			options:     []QueryOption{WithQueryBatchSize(100)},
+			wantErr:     true,
+		},
+		{
+			name:    "no reference id",
+			version: 58,
+			query:   "SELECT Id FROM Account",
+			wantErr: true,
+		},
+		{
+			name:        "no query",
+			version:     58,
+			referenceID: "Accounts",
+			wantErr:     true,
+		},
+		{
+			name:        "no version",
+			referenceID: "Accounts",
+			query:       "SELECT Id FROM Account",
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subrequest, err := NewQuerySubrequest(tt.version, tt.referenceID, tt.query, tt.options...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewQuerySubrequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			r := &Resource{}
+			if err := r.validateSubrequests([]Subrequester{subrequest}); err != nil {
+				t.Fatalf("Resource.validateSubrequests() error = %v", err)
+			}
+			reader, err := r.payload(false, []Subrequester{subrequest})
+			if err != nil {
+				t.Fatalf("Resource.payload() error = %v", err)
+			}
+			body, err := io.ReadAll(reader)
+			if err != nil {
+				t.Fatalf("io.ReadAll() error = %v", err)
+			}
+			var payload struct {
+				CompositeRequest []struct {
+					URL         string            `json:"url"`
+					ReferenceID string            `json:"referenceId"`
+					Method      string            `json:"method"`
+					HTTPHeaders map[string]string `json:"httpHeaders"`
+				} `json:"compositeRequest"`
+			}
+			if err := json.Unmarshal(body, &payload); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			got := payload.CompositeRequest[0]
+			if got.URL != tt.wantURL || got.ReferenceID != tt.referenceID || got.Method != "GET" {
+				t.Errorf("subrequest = %+v, want url %s", got, tt.wantURL)
+			}
+			if len(got.HTTPHeaders) != len(tt.wantHeaders) {
+				t.Fatalf("subrequest httpHeaders = %v, want %v", got.HTTPHeaders, tt.wantHeaders)
+			}
+			for key, value := range tt.wantHeaders {
+				if got.HTTPHeaders[key] != value {
+					t.Errorf("subrequest httpHeaders = %v, want %v", got.HTTPHeaders, tt.wantHeaders)
+				}
+			}
+		})
+	}
+}
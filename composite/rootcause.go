@@ -0,0 +1,104 @@
+package composite
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/namely/go-sfdc/v3"
+)
+
+// ProcessingHalted is the error code of the subrequests that were rolled back
+// because another subrequest of an all or none request failed.
+const ProcessingHalted = "PROCESSING_HALTED"
+
+// Errors returns the errors of a failed subresponse, which has a status code
+// of 400 or more and a body of errors.  If the subresponse did not fail or the
+// body is not errors, false is returned.
+func (s Subvalue) Errors() (sfdc.Errors, bool) {
+	if s.HTTPStatusCode < 400 || s.Body == nil {
+		return nil, false
+	}
+	body, err := json.Marshal(s.Body)
+	if err != nil {
+		return nil, false
+	}
+	var errs sfdc.Errors
+	if err := json.Unmarshal(body, &errs); err != nil {
+		return nil, false
+	}
+	return errs, len(errs) > 0
+}
+
+// Halted returns whether the subresponse failed only because another
+// subrequest of an all or none request failed.
+func (s Subvalue) Halted() bool {
+	errs, has := s.Errors()
+	if has == false {
+		return false
+	}
+	for _, err := range errs {
+		if err.ErrorCode != ProcessingHalted {
+			return false
+		}
+	}
+	return true
+}
+
+// RootCauses returns the failed subresponses that were not halted, in the order
+// of the subrequests.  All of the subresponses that failed for their own
+// reasons are returned, so there can be more than one.  If no subresponse
+// failed, or all of them were halted, the result is empty.
+func (v Value) RootCauses() []Subvalue {
+	var causes []Subvalue
+	for _, subvalue := range v.Response {
+		if subvalue.HTTPStatusCode < 400 || subvalue.Halted() {
+			continue
+		}
+		causes = append(causes, subvalue)
+	}
+	return causes
+}
+
+// FailureSummary returns a single line summary of the root causes for logging,
+// with the reference ID and error codes of each, like
+// "NewContact: REQUIRED_FIELD_MISSING [LastName] (3 halted)".  If there are no
+// failed subresponses, the summary is empty.
+func (v Value) FailureSummary() string {
+	halted := 0
+	for _, subvalue := range v.Response {
+		if subvalue.Halted() {
+			halted++
+		}
+	}
+
+	causes := v.RootCauses()
+	summaries := make([]string, 0, len(causes))
+	for _, cause := range causes {
+		errs, has := cause.Errors()
+		if has == false {
+			summaries = append(summaries, fmt.Sprintf("%s: HTTP %d", cause.ReferenceID, cause.HTTPStatusCode))
+			continue
+		}
+		codes := make([]string, 0, len(errs))
+		for _, err := range errs {
+			code := err.ErrorCode
+			if len(err.Fields) > 0 {
+				code += " [" + strings.Join(err.Fields, ", ") + "]"
+			}
+			codes = append(codes, code)
+		}
+		summaries = append(summaries, cause.ReferenceID+": "+strings.Join(codes, ", "))
+	}
+
+	switch {
+	case len(summaries) == 0 && halted == 0:
+		return ""
+	case len(summaries) == 0:
+		return fmt.Sprintf("no root cause (%d halted)", halted)
+	case halted == 0:
+		return strings.Join(summaries, "; ")
+	default:
+		return fmt.Sprintf("%s (%d halted)", strings.Join(summaries, "; "), halted)
+	}
+}
@@ -0,0 +1,105 @@
+package composite
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/namely/go-sfdc/v3"
+)
+
+const haltedBody = `[{"errorCode": "PROCESSING_HALTED", "message": "The transaction was rolled back since another operation in the same transaction failed."}]`
+
+func compositeValue(t *testing.T, subresponses string) Value {
+	t.Helper()
+	var value Value
+	if err := json.Unmarshal([]byte(`{"compositeResponse": [`+subresponses+`]}`), &value); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	return value
+}
+
+func TestValue_RootCauses(t *testing.T) {
+	tests := []struct {
+		name        string
+		response    string
+		wantRefs    []string
+		wantErrors  sfdc.Errors
+		wantSummary string
+	}{
+		{
+			name: "one failure",
+			response: `
+				{"body": ` + haltedBody + `, "httpHeaders": {}, "httpStatusCode": 400, "referenceId": "NewAccount"},
+				{"body": ` + haltedBody + `, "httpHeaders": {}, "httpStatusCode": 400, "referenceId": "NewAccountInfo"},
+				{"body": [{"errorCode": "REQUIRED_FIELD_MISSING", "message": "Required fields are missing: [LastName]", "fields": ["LastName"]}], "httpHeaders": {}, "httpStatusCode": 400, "referenceId": "NewContact"},
+				{"body": ` + haltedBody + `, "httpHeaders": {}, "httpStatusCode": 400, "referenceId": "NewContactInfo"},
+				{"body": ` + haltedBody + `, "httpHeaders": {}, "httpStatusCode": 400, "referenceId": "NewOpportunity"}`,
+			wantRefs: []string{"NewContact"},
+			wantErrors: sfdc.Errors{
+				{
+					ErrorCode: "REQUIRED_FIELD_MISSING",
+					Message:   "Required fields are missing: [LastName]",
+					Fields:    []string{"LastName"},
+				},
+			},
+			wantSummary: "NewContact: REQUIRED_FIELD_MISSING [LastName] (4 halted)",
+		},
+		{
+			name: "multiple failures",
+			response: `
+				{"body": [{"errorCode": "DUPLICATE_VALUE", "message": "duplicate value found"}], "httpHeaders": {}, "httpStatusCode": 400, "referenceId": "NewAccount"},
+				{"body": ` + haltedBody + `, "httpHeaders": {}, "httpStatusCode": 400, "referenceId": "NewAccountInfo"},
+				{"body": [{"errorCode": "NOT_FOUND", "message": "The requested resource does not exist"}], "httpHeaders": {}, "httpStatusCode": 404, "referenceId": "NewContact"}`,
+			wantRefs: []string{"NewAccount", "NewContact"},
+			wantErrors: sfdc.Errors{
+				{
+					ErrorCode: "DUPLICATE_VALUE",
+					Message:   "duplicate value found",
+				},
+			},
+			wantSummary: "NewAccount: DUPLICATE_VALUE; NewContact: NOT_FOUND (1 halted)",
+		},
+		{
+			name: "all halted",
+			response: `
+				{"body": ` + haltedBody + `, "httpHeaders": {}, "httpStatusCode": 400, "referenceId": "NewAccount"},
+				{"body": ` + haltedBody + `, "httpHeaders": {}, "httpStatusCode": 400, "referenceId": "NewContact"}`,
+			wantSummary: "no root cause (2 halted)",
+		},
+		{
+			name: "no failures",
+			response: `
+				{"body": {"id": "001D000000K0fXOIAZ", "success": true, "errors": []}, "httpHeaders": {"Location": "/services/data/v44.0/sobjects/Account/001D000000K0fXOIAZ"}, "httpStatusCode": 201, "referenceId": "NewAccount"}`,
+		},
+		{
+			name: "failure without errors body",
+			response: `
+				{"body": null, "httpHeaders": {}, "httpStatusCode": 500, "referenceId": "NewAccount"}`,
+			wantRefs:    []string{"NewAccount"},
+			wantSummary: "NewAccount: HTTP 500",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value := compositeValue(t, tt.response)
+			causes := value.RootCauses()
+			var refs []string
+			for _, cause := range causes {
+				refs = append(refs, cause.ReferenceID)
+			}
+			if reflect.DeepEqual(refs, tt.wantRefs) == false {
+				t.Errorf("Value.RootCauses() = %v, want %v", refs, tt.wantRefs)
+			}
+			if tt.wantErrors != nil {
+				errs, has := causes[0].Errors()
+				if has == false || reflect.DeepEqual(errs, tt.wantErrors) == false {
+					t.Errorf("Subvalue.Errors() = %v, %v, want %v", errs, has, tt.wantErrors)
+				}
+			}
+			if got := value.FailureSummary(); got != tt.wantSummary {
+				t.Errorf("Value.FailureSummary() = %q, want %q", got, tt.wantSummary)
+			}
+		})
+	}
+}
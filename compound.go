@@ -0,0 +1,69 @@
+package sfdc
+
+import "encoding/json"
+
+// Address is the value of an address compound field, like BillingAddress, as
+// it is returned by a query.  An address can not be written as a compound
+// field, but is written with its component fields, like BillingStreet.
+//
+// Latitude and Longitude are nil when the address is not geocoded.
+type Address struct {
+	Street          string   `json:"street,omitempty"`
+	City            string   `json:"city,omitempty"`
+	State           string   `json:"state,omitempty"`
+	StateCode       string   `json:"stateCode,omitempty"`
+	PostalCode      string   `json:"postalCode,omitempty"`
+	Country         string   `json:"country,omitempty"`
+	CountryCode     string   `json:"countryCode,omitempty"`
+	Latitude        *float64 `json:"latitude,omitempty"`
+	Longitude       *float64 `json:"longitude,omitempty"`
+	GeocodeAccuracy string   `json:"geocodeAccuracy,omitempty"`
+}
+
+// Geolocation is the value of a geolocation compound field, like a custom
+// Location__c field, as it is returned by a query.  A geolocation is written
+// with its component fields, like Location__Latitude__s.
+type Geolocation struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// Address returns the value of the address compound field.  If the record
+// does not have a value for the field, then false will be returned.
+func (r *Record) Address(field string) (Address, bool) {
+	var address Address
+	if r.compound(field, &address) == false {
+		return Address{}, false
+	}
+	return address, true
+}
+
+// Geolocation returns the value of the geolocation compound field.  If the
+// record does not have a value for the field, or the value does not have a
+// latitude and a longitude, then false will be returned.
+func (r *Record) Geolocation(field string) (Geolocation, bool) {
+	var location struct {
+		Latitude  *float64 `json:"latitude"`
+		Longitude *float64 `json:"longitude"`
+	}
+	if r.compound(field, &location) == false || location.Latitude == nil || location.Longitude == nil {
+		return Geolocation{}, false
+	}
+	return Geolocation{
+		Latitude:  *location.Latitude,
+		Longitude: *location.Longitude,
+	}, true
+}
+
+// compound decodes the compound field's value into the value.
+func (r *Record) compound(field string, value interface{}) bool {
+	jsonMap, has := r.compounds[field]
+	if has == false {
+		return false
+	}
+	data, err := json.Marshal(jsonMap)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, value) == nil
+}
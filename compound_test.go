@@ -0,0 +1,71 @@
+package sfdc
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestRecord_Address(t *testing.T) {
+	data := `{
+		"attributes": {"type": "Account", "url": "/services/data/v42.0/sobjects/Account/001A"},
+		"Name": "Acme",
+		"BillingAddress": {
+			"city": "San Francisco",
+			"country": "United States",
+			"countryCode": "US",
+			"geocodeAccuracy": null,
+			"latitude": 37.7936,
+			"longitude": -122.3953,
+			"postalCode": "94105",
+			"state": "California",
+			"stateCode": "CA",
+			"street": "415 Mission Street"
+		},
+		"ShippingAddress": null,
+		"Location__c": {"latitude": 37.7936, "longitude": -122.3953},
+		"Partial__c": {"latitude": 37.7936, "longitude": null},
+		"Owner": {
+			"attributes": {"type": "User", "url": "/services/data/v42.0/sobjects/User/005A"},
+			"Name": "Jo"
+		}
+	}`
+	var record Record
+	if err := json.Unmarshal([]byte(data), &record); err != nil {
+		t.Fatalf("Record.UnmarshalJSON() error = %v", err)
+	}
+
+	latitude, longitude := 37.7936, -122.3953
+	want := Address{
+		Street:      "415 Mission Street",
+		City:        "San Francisco",
+		State:       "California",
+		StateCode:   "CA",
+		PostalCode:  "94105",
+		Country:     "United States",
+		CountryCode: "US",
+		Latitude:    &latitude,
+		Longitude:   &longitude,
+	}
+	address, has := record.Address("BillingAddress")
+	if has == false || !reflect.DeepEqual(address, want) {
+		t.Errorf("Record.Address() = %+v, %v, want %+v", address, has, want)
+	}
+	if _, has := record.Address("ShippingAddress"); has {
+		t.Error("Record.Address() of a null address has a value")
+	}
+	if _, has := record.Address("Owner"); has {
+		t.Error("Record.Address() of a look up has a value")
+	}
+
+	location, has := record.Geolocation("Location__c")
+	if want := (Geolocation{Latitude: latitude, Longitude: longitude}); has == false || location != want {
+		t.Errorf("Record.Geolocation() = %+v, %v, want %+v", location, has, want)
+	}
+	if _, has := record.Geolocation("Partial__c"); has {
+		t.Error("Record.Geolocation() without a longitude has a value")
+	}
+	if _, has := record.Fields()["BillingAddress"]; has {
+		t.Error("Record.Fields() has the compound field")
+	}
+}
@@ -14,9 +14,65 @@ import (
 // Client is the HTTP client that will be used.
 //
 // Version is the Salesforce version for the APIs.
+//
+// Instrumentation is used to trace the API calls.  This field is optional.
+//
+// CorrelationHeader is the header that the correlation ID of the API calls is
+// sent in.  If empty, DefaultCorrelationHeader is used.  This field is optional.
+//
+// OnInstanceChange is called when a session refresh returns a different instance
+// URL, like when the org is migrated to a new instance.  This field is optional.
+//
+// UserAgentSuffix is appended to the User-Agent header that identifies go-sfdc,
+// so the API calls can be attributed to the application.  This field is
+// optional.
+//
+// LazyRefresh skips the session refresh when the resources are created.  The
+// session is refreshed by its client instead, before a request is sent with an
+// expired session and once after a request is rejected with a 401.  This field
+// is optional.
+//
+// OnRefreshToken is called with the new refresh token when Salesforce returns
+// one with the session, like when the refresh tokens are rotated, so it can be
+// persisted.  The session's credentials are given the new token if they are a
+// credentials.RefreshTokenRotator.  This field is optional.
+//
+// MaxRetries is the number of times the session's client retries a request
+// that is answered with one of the retryable statuses.  The GET, HEAD, OPTIONS
+// and DELETE requests are retried, and the other requests only with the
+// context of WithRequestRetry.  Zero does not retry.  This field is optional.
+//
+// RetryableStatuses are the statuses that are retried.  If empty, the 429 and
+// 503 statuses are retried.  This field is optional.
+//
+// RetryPolicy is the back off of the retries of MaxRetries when the response
+// does not have a Retry-After, and its MaxInterval caps the wait of a
+// Retry-After.  The number of retries is MaxRetries, so the policy's
+// MaxAttempts and MaxElapsedTime are not used.  If nil, NewRetryPolicy is
+// used.  This field is optional.
+//
+// OnRefresh is called after each session refresh with its error, or nil when
+// the refresh succeeded, so the failures of the background renewals of
+// StartAutoRefresh can be reported.  This field is optional.
+//
+// RefreshWindow is the time before the session expires from which it is
+// refreshed, like 5 minutes, so the calls do not use a token that is about to
+// expire.  The session is still used when its refresh in the window fails.
+// Zero refreshes the session once it has expired.  This field is optional.
 type Configuration struct {
-	Credentials     *credentials.Credentials
-	Client          *http.Client
-	Version         int
-	SessionDuration time.Duration
+	Credentials       *credentials.Credentials
+	Client            *http.Client
+	Version           int
+	SessionDuration   time.Duration
+	Instrumentation   Instrumentation
+	CorrelationHeader string
+	OnInstanceChange  func(old, new string)
+	UserAgentSuffix   string
+	LazyRefresh       bool
+	OnRefreshToken    func(token string)
+	MaxRetries        int
+	RetryableStatuses []int
+	RetryPolicy       *RetryPolicy
+	OnRefresh         func(err error)
+	RefreshWindow     time.Duration
 }
@@ -0,0 +1,15 @@
+module github.com/namely/go-sfdc/v3/contrib/oauth2sfdc
+
+go 1.18
+
+require (
+	github.com/namely/go-sfdc/v3 v3.0.0-20261017193342-ee22d5a52b4b
+	golang.org/x/oauth2 v0.19.0
+)
+
+require github.com/pkg/errors v0.9.1 // indirect
+
+// The replace builds against the go-sfdc checkout for local development.  It
+// is ignored by the modules that require this one, which use the version
+// above, the first with credentials.TokenProvider.
+replace github.com/namely/go-sfdc/v3 => ../..
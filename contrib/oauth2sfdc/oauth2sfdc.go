@@ -0,0 +1,110 @@
+// Package oauth2sfdc adapts go-sfdc sessions to the golang.org/x/oauth2
+// TokenSource interface, and token sources to go-sfdc credentials.
+package oauth2sfdc
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/namely/go-sfdc/v3/credentials"
+	"github.com/namely/go-sfdc/v3/session"
+	"golang.org/x/oauth2"
+)
+
+// instanceURLExtra is the token response field with the Salesforce instance.
+const instanceURLExtra = "instance_url"
+
+// TokenSource returns a token source with the session's token.  The session is
+// refreshed when it has expired, so the token is valid when it is returned.
+func TokenSource(s *session.Session) oauth2.TokenSource {
+	return &sessionTokenSource{
+		session: s,
+	}
+}
+
+type sessionTokenSource struct {
+	session *session.Session
+}
+
+func (ts *sessionTokenSource) Token() (*oauth2.Token, error) {
+	if ts.session == nil {
+		return nil, errors.New("oauth2sfdc: session can not be nil")
+	}
+	if err := ts.session.Refresh(); err != nil {
+		return nil, err
+	}
+
+	token := ts.session.Token()
+	return (&oauth2.Token{
+		AccessToken: token.AccessToken,
+		TokenType:   token.TokenType,
+		Expiry:      token.Expiry,
+	}).WithExtra(map[string]interface{}{
+		instanceURLExtra: token.InstanceURL,
+	}), nil
+}
+
+// Provider is a credentials provider with the tokens of a token source, like
+// one that is managed outside of go-sfdc.  The session does not use the OAuth
+// token endpoint, and a refresh gets the token from the source.
+type Provider struct {
+	source      oauth2.TokenSource
+	instanceURL string
+}
+
+// NewProvider creates a provider for the token source.  The instance URL is
+// used when the token does not have the instance_url field.
+func NewProvider(source oauth2.TokenSource, instanceURL string) (*Provider, error) {
+	if source == nil {
+		return nil, errors.New("oauth2sfdc: token source can not be nil")
+	}
+	return &Provider{
+		source:      source,
+		instanceURL: instanceURL,
+	}, nil
+}
+
+// NewCredentials creates the credentials for the token source.
+func NewCredentials(source oauth2.TokenSource, instanceURL string) (*credentials.Credentials, error) {
+	provider, err := NewProvider(source, instanceURL)
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewCredentials(provider)
+}
+
+// Retrieve returns an error, since the tokens come from the token source.
+func (p *Provider) Retrieve() (io.Reader, error) {
+	return nil, errors.New("oauth2sfdc: the token source provider does not use the OAuth token endpoint")
+}
+
+// URL returns the instance URL.
+func (p *Provider) URL() string {
+	return p.instanceURL
+}
+
+// Token returns the session token from the token source.
+func (p *Provider) Token(ctx context.Context) (credentials.Token, error) {
+	token, err := p.source.Token()
+	if err != nil {
+		return credentials.Token{}, err
+	}
+
+	instanceURL := p.instanceURL
+	if extra, ok := token.Extra(instanceURLExtra).(string); ok && extra != "" {
+		instanceURL = extra
+	}
+	if instanceURL == "" {
+		return credentials.Token{}, errors.New("oauth2sfdc: the token does not have an instance URL")
+	}
+	id, _ := token.Extra("id").(string)
+
+	return credentials.Token{
+		AccessToken: token.AccessToken,
+		TokenType:   token.Type(),
+		InstanceURL: instanceURL,
+		ID:          id,
+		Expiry:      token.Expiry,
+	}, nil
+}
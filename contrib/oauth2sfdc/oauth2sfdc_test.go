@@ -0,0 +1,190 @@
+package oauth2sfdc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/namely/go-sfdc/v3"
+	"github.com/namely/go-sfdc/v3/credentials"
+	"github.com/namely/go-sfdc/v3/session"
+	"golang.org/x/oauth2"
+)
+
+type roundTripFunc func(request *http.Request) *http.Response
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req), nil
+}
+
+func TestTokenSource(t *testing.T) {
+	calls := 0
+	client := &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) *http.Response {
+			calls++
+			resp := fmt.Sprintf(`{"access_token": "token %d", "instance_url": "https://na1.salesforce.com", "token_type": "Bearer"}`, calls)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(resp)),
+				Header:     make(http.Header),
+			}
+		}),
+	}
+	creds, err := credentials.NewPasswordCredentials(credentials.PasswordCredentials{
+		URL:          "https://login.salesforce.com",
+		Username:     "myusername",
+		Password:     "12345",
+		ClientID:     "some client id",
+		ClientSecret: "shhhh its a secret",
+	})
+	if err != nil {
+		t.Fatalf("credentials.NewPasswordCredentials() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		duration time.Duration
+		want     []string
+	}{
+		{
+			name:     "not expired",
+			duration: time.Hour,
+			want:     []string{"token 1", "token 1"},
+		},
+		{
+			name:     "expired",
+			duration: -time.Minute,
+			want:     []string{"token 3", "token 4"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sess, err := session.Open(sfdc.Configuration{
+				Credentials:     creds,
+				Client:          client,
+				Version:         45,
+				SessionDuration: tt.duration,
+			})
+			if err != nil {
+				t.Fatalf("session.Open() error = %v", err)
+			}
+			source := TokenSource(sess)
+			for idx, want := range tt.want {
+				token, err := source.Token()
+				if err != nil {
+					t.Fatalf("TokenSource.Token() error = %v", err)
+				}
+				if token.AccessToken != want {
+					t.Errorf("TokenSource.Token() %d = %v, want %v", idx, token.AccessToken, want)
+				}
+				if token.Type() != "Bearer" {
+					t.Errorf("TokenSource.Token() type = %v, want Bearer", token.Type())
+				}
+				if instanceURL := token.Extra("instance_url"); instanceURL != "https://na1.salesforce.com" {
+					t.Errorf("TokenSource.Token() instance_url = %v", instanceURL)
+				}
+				if token.Expiry.Equal(sess.Token().Expiry) == false {
+					t.Errorf("TokenSource.Token() expiry = %v, want %v", token.Expiry, sess.Token().Expiry)
+				}
+			}
+		})
+	}
+}
+
+type countingSource struct {
+	calls  int
+	expiry time.Duration
+}
+
+func (s *countingSource) Token() (*oauth2.Token, error) {
+	s.calls++
+	return &oauth2.Token{
+		AccessToken: fmt.Sprintf("external %d", s.calls),
+		TokenType:   "Bearer",
+		Expiry:      time.Now().Add(s.expiry),
+	}, nil
+}
+
+func TestProvider(t *testing.T) {
+	base := &countingSource{
+		expiry: -time.Minute,
+	}
+	creds, err := NewCredentials(oauth2.ReuseTokenSource(nil, base), "https://na1.salesforce.com")
+	if err != nil {
+		t.Fatalf("NewCredentials() error = %v", err)
+	}
+	client := &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) *http.Response {
+			t.Errorf("unexpected request %s", req.URL)
+			return nil
+		}),
+	}
+
+	sess, err := session.Open(sfdc.Configuration{
+		Credentials: creds,
+		Client:      client,
+		Version:     45,
+	})
+	if err != nil {
+		t.Fatalf("session.Open() error = %v", err)
+	}
+	if got := sess.Token().AccessToken; got != "external 1" {
+		t.Errorf("Session.Token() = %v, want external 1", got)
+	}
+	if got := sess.ServiceURL(); got != "https://na1.salesforce.com/services/data/v45.0" {
+		t.Errorf("Session.ServiceURL() = %v", got)
+	}
+
+	base.expiry = time.Hour
+	if err := sess.Refresh(); err != nil {
+		t.Fatalf("Session.Refresh() error = %v", err)
+	}
+	if got := sess.Token().AccessToken; got != "external 2" {
+		t.Errorf("Session.Token() = %v, want external 2", got)
+	}
+	if err := sess.Refresh(); err != nil {
+		t.Fatalf("Session.Refresh() error = %v", err)
+	}
+	if base.calls != 2 {
+		t.Errorf("token source calls = %d, want 2", base.calls)
+	}
+}
+
+func TestProvider_instanceURL(t *testing.T) {
+	token := (&oauth2.Token{
+		AccessToken: "token",
+		TokenType:   "Bearer",
+	}).WithExtra(map[string]interface{}{
+		"instance_url": "https://na2.salesforce.com",
+		"id":           "https://login.salesforce.com/id/00D50000000IZ3ZEAW/00550000001fg5OAAQ",
+	})
+	provider, err := NewProvider(oauth2.StaticTokenSource(token), "https://na1.salesforce.com")
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+	got, err := provider.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Provider.Token() error = %v", err)
+	}
+	if got.InstanceURL != "https://na2.salesforce.com" || got.ID == "" {
+		t.Errorf("Provider.Token() = %+v", got)
+	}
+
+	provider, err = NewProvider(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"}), "")
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+	if _, err := provider.Token(context.Background()); err == nil {
+		t.Errorf("Provider.Token() expected an error without an instance URL")
+	}
+	if _, err := provider.Retrieve(); err == nil {
+		t.Errorf("Provider.Retrieve() expected an error")
+	}
+	if _, err := NewProvider(nil, ""); err == nil {
+		t.Errorf("NewProvider() expected an error for a nil source")
+	}
+}
@@ -0,0 +1,13 @@
+module github.com/namely/go-sfdc/v3/contrib/otelsfdc
+
+go 1.21
+
+require (
+	github.com/namely/go-sfdc/v3 v3.0.0
+	go.opentelemetry.io/otel v1.28.0
+	go.opentelemetry.io/otel/trace v1.28.0
+)
+
+require github.com/pkg/errors v0.9.1 // indirect
+
+replace github.com/namely/go-sfdc/v3 => ../..
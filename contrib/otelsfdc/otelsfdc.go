@@ -0,0 +1,59 @@
+// Package otelsfdc provides the OpenTelemetry instrumentation for go-sfdc.
+package otelsfdc
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Instrumentation is the go-sfdc instrumentation that starts
+// OpenTelemetry spans.
+type Instrumentation struct {
+	tracer trace.Tracer
+}
+
+// New creates the instrumentation with the tracer.  It can be used as
+// the go-sfdc configuration's instrumentation.
+func New(tracer trace.Tracer) *Instrumentation {
+	return &Instrumentation{
+		tracer: tracer,
+	}
+}
+
+// StartSpan will start the OpenTelemetry span for the operation.
+func (i *Instrumentation) StartSpan(ctx context.Context, op string, attrs map[string]interface{}) (context.Context, func(error)) {
+	ctx, span := i.tracer.Start(ctx, op, trace.WithAttributes(Attributes(attrs)...))
+	return ctx, func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
+
+// Attributes converts the go-sfdc span attributes to OpenTelemetry attributes.
+func Attributes(attrs map[string]interface{}) []attribute.KeyValue {
+	kvs := make([]attribute.KeyValue, 0, len(attrs))
+	for key, value := range attrs {
+		switch v := value.(type) {
+		case string:
+			kvs = append(kvs, attribute.String(key, v))
+		case bool:
+			kvs = append(kvs, attribute.Bool(key, v))
+		case int:
+			kvs = append(kvs, attribute.Int(key, v))
+		case int64:
+			kvs = append(kvs, attribute.Int64(key, v))
+		case float64:
+			kvs = append(kvs, attribute.Float64(key, v))
+		default:
+			kvs = append(kvs, attribute.String(key, fmt.Sprintf("%v", v)))
+		}
+	}
+	return kvs
+}
@@ -0,0 +1,49 @@
+package otelsfdc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/namely/go-sfdc/v3"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestInstrumentationIsInstrumentation(t *testing.T) {
+	var _ sfdc.Instrumentation = &Instrumentation{}
+}
+
+func TestAttributes(t *testing.T) {
+	attrs := Attributes(map[string]interface{}{
+		"job.id":    "7505fEXAMPLE4C2AAM",
+		"query.all": true,
+		"records":   12,
+		"other":     []string{"a"},
+	})
+	want := map[attribute.Key]attribute.Value{
+		"job.id":    attribute.StringValue("7505fEXAMPLE4C2AAM"),
+		"query.all": attribute.BoolValue(true),
+		"records":   attribute.IntValue(12),
+		"other":     attribute.StringValue("[a]"),
+	}
+	if len(attrs) != len(want) {
+		t.Fatalf("Attributes() = %v, want %v", attrs, want)
+	}
+	for _, kv := range attrs {
+		if kv.Value != want[kv.Key] {
+			t.Errorf("Attributes() %s = %v, want %v", kv.Key, kv.Value.Emit(), want[kv.Key].Emit())
+		}
+	}
+}
+
+func TestInstrumentation_StartSpan(t *testing.T) {
+	instrumentation := New(noop.NewTracerProvider().Tracer("test"))
+	ctx, end := instrumentation.StartSpan(context.Background(), "bulk.job.create", map[string]interface{}{
+		"object": "Account",
+	})
+	if ctx == nil {
+		t.Fatal("StartSpan() context is nil")
+	}
+	end(errors.New("failed"))
+}
@@ -0,0 +1,67 @@
+package sfdc
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+)
+
+// DefaultCorrelationHeader is the header used for the correlation ID when the
+// configuration does not have one.
+const DefaultCorrelationHeader = "X-Correlation-Id"
+
+// CallOptionsHeader is the Salesforce call options header.  When it is used as
+// the correlation header, the correlation ID is sent as the client value.
+const CallOptionsHeader = "Sforce-Call-Options"
+
+type correlationKey struct{}
+
+// WithCorrelationID returns a context with the correlation ID.  The API calls
+// made with the context will send the correlation ID instead of generating one,
+// so the related calls can be matched in the Salesforce logs.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationKey{}, id)
+}
+
+// CorrelationID returns the correlation ID of the context.
+func CorrelationID(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	id, ok := ctx.Value(correlationKey{}).(string)
+	return id, ok && id != ""
+}
+
+// NewCorrelationID generates a random (version 4) UUID to be used as the
+// correlation ID.
+func NewCorrelationID() string {
+	var uuid [16]byte
+	if _, err := rand.Read(uuid[:]); err != nil {
+		panic(fmt.Sprintf("correlation id: %v", err))
+	}
+	uuid[6] = (uuid[6] & 0x0f) | 0x40
+	uuid[8] = (uuid[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:])
+}
+
+// CorrelatedError is the error of an API call that was sent with a
+// correlation ID.
+type CorrelatedError struct {
+	CorrelationID string
+	Err           error
+}
+
+// Error fulfills the error interface and includes the correlation ID.
+func (e *CorrelatedError) Error() string {
+	return fmt.Sprintf("%s (correlation id %s)", e.Err.Error(), e.CorrelationID)
+}
+
+// Unwrap returns the error of the API call.
+func (e *CorrelatedError) Unwrap() error {
+	return e.Err
+}
+
+// Cause returns the error of the API call.
+func (e *CorrelatedError) Cause() error {
+	return e.Err
+}
@@ -0,0 +1,68 @@
+package sfdc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+func TestNewCorrelationID(t *testing.T) {
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	first := NewCorrelationID()
+	if uuid.MatchString(first) == false {
+		t.Errorf("NewCorrelationID() = %v, want a version 4 UUID", first)
+	}
+	if second := NewCorrelationID(); second == first {
+		t.Errorf("NewCorrelationID() = %v, want a new id", second)
+	}
+}
+
+func TestCorrelationID(t *testing.T) {
+	if id, ok := CorrelationID(context.Background()); ok {
+		t.Errorf("CorrelationID() = %v, want none", id)
+	}
+	ctx := WithCorrelationID(context.Background(), "1234")
+	if id, ok := CorrelationID(ctx); ok == false || id != "1234" {
+		t.Errorf("CorrelationID() = %v, %v, want 1234", id, ok)
+	}
+}
+
+func TestHandleError_correlated(t *testing.T) {
+	request, err := http.NewRequestWithContext(WithCorrelationID(context.Background(), "1234"), http.MethodGet, "https://test.salesforce.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp := &http.Response{
+		Status:  "400 Bad Request",
+		Body:    io.NopCloser(strings.NewReader(`[{"errorCode": "BAD", "message": "bad request"}]`)),
+		Request: request,
+	}
+	err = HandleError(resp)
+	want := "400 Bad Request: BAD: bad request () (correlation id 1234)"
+	if err == nil || err.Error() != want {
+		t.Errorf("HandleError() = %v, want %v", err, want)
+	}
+
+	var correlated *CorrelatedError
+	if errors.As(err, &correlated) == false || correlated.CorrelationID != "1234" {
+		t.Errorf("HandleError() = %v, want a correlated error", err)
+	}
+	// the type and the cause of the error are the ones of an uncorrelated call
+	plain := HandleError(&http.Response{
+		Status: "400 Bad Request",
+		Body:   io.NopCloser(strings.NewReader(`[{"errorCode": "BAD", "message": "bad request"}]`)),
+	})
+	if reflect.TypeOf(err) != reflect.TypeOf(plain) {
+		t.Errorf("HandleError() type = %T, want %T", err, plain)
+	}
+	if _, ok := pkgerrors.Cause(err).(Errors); ok == false {
+		t.Errorf("HandleError() cause = %T, want Errors", pkgerrors.Cause(err))
+	}
+}
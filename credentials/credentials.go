@@ -25,6 +25,26 @@ type PasswordCredentials struct {
 	ClientSecret string
 }
 
+// RefreshTokenCredentials is a structure for the OAuth credentials of the
+// refresh token flow, like the refresh token that is returned by the web
+// server flow.
+//
+// URL is the login URL used, examples would be https://test.salesforce.com or https://login.salesforce.com
+//
+// ClientID is the client ID from the connected application.
+//
+// ClientSecret is the client secret from the connected application.  It is
+// optional when the connected application does not require it for the
+// refresh token flow.
+//
+// RefreshToken is the refresh token for the user.
+type RefreshTokenCredentials struct {
+	URL          string
+	ClientID     string
+	ClientSecret string
+	RefreshToken string
+}
+
 // Credentials is the structure that contains all of the
 // information for creating a session.
 type Credentials struct {
@@ -42,10 +62,24 @@ type Provider interface {
 	URL() string
 }
 
+// Zeroizer is a provider that can wipe its secret material, like a password
+// or client secret, when the credentials are no longer used.
+type Zeroizer interface {
+	Zeroize()
+}
+
+// RefreshTokenRotator is a provider that is given the new refresh token when
+// Salesforce returns one with the session, so the next session is requested
+// with it.
+type RefreshTokenRotator interface {
+	RotateRefreshToken(token string)
+}
+
 type grantType string
 
 const (
-	passwordGrantType grantType = "password"
+	passwordGrantType     grantType = "password"
+	refreshTokenGrantType grantType = "refresh_token"
 )
 
 // Retrieve will return the reader for the HTTP request body.
@@ -58,6 +92,22 @@ func (creds *Credentials) URL() string {
 	return creds.provider.URL()
 }
 
+// Zeroize will wipe the provider's secret material if the provider is a
+// Zeroizer.  The credentials can not be used to open a session afterwards.
+func (creds *Credentials) Zeroize() {
+	if zeroizer, ok := creds.provider.(Zeroizer); ok {
+		zeroizer.Zeroize()
+	}
+}
+
+// RotateRefreshToken will give the new refresh token to the provider if the
+// provider is a RefreshTokenRotator.
+func (creds *Credentials) RotateRefreshToken(token string) {
+	if rotator, ok := creds.provider.(RefreshTokenRotator); ok {
+		rotator.RotateRefreshToken(token)
+	}
+}
+
 // NewCredentials will create a credential with the custom provider.
 func NewCredentials(provider Provider) (*Credentials, error) {
 	if provider == nil {
@@ -98,3 +148,29 @@ func validatePasswordCredentials(cred PasswordCredentials) error {
 	}
 	return nil
 }
+
+// NewRefreshTokenCredentials will create a credential with the refresh token
+// credentials.
+func NewRefreshTokenCredentials(creds RefreshTokenCredentials) (*Credentials, error) {
+	if err := validateRefreshTokenCredentials(creds); err != nil {
+		return nil, err
+	}
+	return &Credentials{
+		provider: &refreshTokenProvider{
+			creds: creds,
+		},
+	}, nil
+}
+
+func validateRefreshTokenCredentials(cred RefreshTokenCredentials) error {
+	if cred.URL == "" {
+		return errors.New("credentials: refresh token credential's URL can not be empty")
+	}
+	if cred.ClientID == "" {
+		return errors.New("credentials: refresh token credential's client ID can not be empty")
+	}
+	if cred.RefreshToken == "" {
+		return errors.New("credentials: refresh token credential's refresh token can not be empty")
+	}
+	return nil
+}
@@ -24,3 +24,10 @@ func (provider *passwordProvider) Retrieve() (io.Reader, error) {
 func (provider *passwordProvider) URL() string {
 	return provider.creds.URL
 }
+
+// Zeroize drops the password and client secret.  Go strings can not be
+// overwritten, so the secrets are released to the garbage collector.
+func (provider *passwordProvider) Zeroize() {
+	provider.creds.Password = ""
+	provider.creds.ClientSecret = ""
+}
@@ -101,3 +101,25 @@ func Test_passwordProvider_URL(t *testing.T) {
 		})
 	}
 }
+
+func Test_passwordProvider_Zeroize(t *testing.T) {
+	creds, err := NewPasswordCredentials(PasswordCredentials{
+		URL:          "http://test.password.session",
+		Username:     "myusername",
+		Password:     "12345",
+		ClientID:     "some client id",
+		ClientSecret: "shhhh its a secret",
+	})
+	if err != nil {
+		t.Fatalf("NewPasswordCredentials() error = %v", err)
+	}
+	creds.Zeroize()
+
+	provider := creds.provider.(*passwordProvider)
+	if provider.creds.Password != "" || provider.creds.ClientSecret != "" {
+		t.Errorf("passwordProvider.Zeroize() creds = %+v", provider.creds)
+	}
+	if provider.creds.Username != "myusername" || provider.URL() != "http://test.password.session" {
+		t.Errorf("passwordProvider.Zeroize() creds = %+v", provider.creds)
+	}
+}
@@ -0,0 +1,54 @@
+package credentials
+
+import (
+	"io"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+type refreshTokenProvider struct {
+	mu    sync.RWMutex
+	creds RefreshTokenCredentials
+}
+
+func (provider *refreshTokenProvider) Retrieve() (io.Reader, error) {
+	provider.mu.RLock()
+	defer provider.mu.RUnlock()
+
+	form := url.Values{}
+	form.Add("grant_type", string(refreshTokenGrantType))
+	form.Add("refresh_token", provider.creds.RefreshToken)
+	form.Add("client_id", provider.creds.ClientID)
+	if provider.creds.ClientSecret != "" {
+		form.Add("client_secret", provider.creds.ClientSecret)
+	}
+
+	return strings.NewReader(form.Encode()), nil
+}
+
+func (provider *refreshTokenProvider) URL() string {
+	return provider.creds.URL
+}
+
+// RotateRefreshToken replaces the refresh token, so the next session is
+// requested with the token that Salesforce returned.
+func (provider *refreshTokenProvider) RotateRefreshToken(token string) {
+	if token == "" {
+		return
+	}
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+
+	provider.creds.RefreshToken = token
+}
+
+// Zeroize drops the refresh token and client secret.  Go strings can not be
+// overwritten, so the secrets are released to the garbage collector.
+func (provider *refreshTokenProvider) Zeroize() {
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+
+	provider.creds.RefreshToken = ""
+	provider.creds.ClientSecret = ""
+}
@@ -0,0 +1,126 @@
+package credentials
+
+import (
+	"io"
+	"net/url"
+	"testing"
+)
+
+func TestNewRefreshTokenCredentials(t *testing.T) {
+	valid := RefreshTokenCredentials{
+		URL:          "http://test.refresh.session",
+		ClientID:     "some client id",
+		ClientSecret: "shhhh its a secret",
+		RefreshToken: "some refresh token",
+	}
+	tests := []struct {
+		name    string
+		creds   func(RefreshTokenCredentials) RefreshTokenCredentials
+		wantErr bool
+	}{
+		{
+			name:  "Refresh Token Credentials",
+			creds: func(creds RefreshTokenCredentials) RefreshTokenCredentials { return creds },
+		},
+		{
+			name: "No client secret",
+			creds: func(creds RefreshTokenCredentials) RefreshTokenCredentials {
+				creds.ClientSecret = ""
+				return creds
+			},
+		},
+		{
+			name: "No URL",
+			creds: func(creds RefreshTokenCredentials) RefreshTokenCredentials {
+				creds.URL = ""
+				return creds
+			},
+			wantErr: true,
+		},
+		{
+			name: "No client ID",
+			creds: func(creds RefreshTokenCredentials) RefreshTokenCredentials {
+				creds.ClientID = ""
+				return creds
+			},
+			wantErr: true,
+		},
+		{
+			name: "No refresh token",
+			creds: func(creds RefreshTokenCredentials) RefreshTokenCredentials {
+				creds.RefreshToken = ""
+				return creds
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRefreshTokenCredentials(tt.creds(valid))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewRefreshTokenCredentials() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func retrieveForm(t *testing.T, creds *Credentials) url.Values {
+	t.Helper()
+	reader, err := creds.Retrieve()
+	if err != nil {
+		t.Fatalf("refreshTokenProvider.Retrieve() error = %v", err)
+	}
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("refreshTokenProvider.Retrieve() read error = %v", err)
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		t.Fatalf("refreshTokenProvider.Retrieve() form error = %v", err)
+	}
+	return form
+}
+
+func Test_refreshTokenProvider_Retrieve(t *testing.T) {
+	creds, err := NewRefreshTokenCredentials(RefreshTokenCredentials{
+		URL:          "http://test.refresh.session",
+		ClientID:     "some client id",
+		ClientSecret: "shhhh its a secret",
+		RefreshToken: "first",
+	})
+	if err != nil {
+		t.Fatalf("NewRefreshTokenCredentials() error = %v", err)
+	}
+	if creds.URL() != "http://test.refresh.session" {
+		t.Errorf("refreshTokenProvider.URL() = %v", creds.URL())
+	}
+
+	form := retrieveForm(t, creds)
+	want := url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {"first"},
+		"client_id":     {"some client id"},
+		"client_secret": {"shhhh its a secret"},
+	}
+	if form.Encode() != want.Encode() {
+		t.Errorf("refreshTokenProvider.Retrieve() = %v, want %v", form, want)
+	}
+
+	creds.RotateRefreshToken("second")
+	if got := retrieveForm(t, creds).Get("refresh_token"); got != "second" {
+		t.Errorf("refreshTokenProvider.Retrieve() refresh token = %v, want second", got)
+	}
+	creds.RotateRefreshToken("")
+	if got := retrieveForm(t, creds).Get("refresh_token"); got != "second" {
+		t.Errorf("refreshTokenProvider.Retrieve() refresh token = %v, want second", got)
+	}
+
+	creds.Zeroize()
+	form = retrieveForm(t, creds)
+	if form.Get("refresh_token") != "" || form.Get("client_secret") != "" {
+		t.Errorf("refreshTokenProvider.Zeroize() form = %v", form)
+	}
+	if form.Get("client_id") != "some client id" {
+		t.Errorf("refreshTokenProvider.Zeroize() form = %v", form)
+	}
+}
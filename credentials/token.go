@@ -0,0 +1,40 @@
+package credentials
+
+import (
+	"context"
+	"time"
+)
+
+// Token is a session token that was minted outside of go-sfdc.
+//
+// AccessToken is the access token used in the authorization header.
+//
+// TokenType is the type of the token, like Bearer.
+//
+// InstanceURL is the Salesforce instance the token is for.
+//
+// ID is the identity URL of the token's user.  This field is optional.
+//
+// Expiry is when the token expires.  If it is zero, the session duration is used.
+type Token struct {
+	AccessToken string
+	TokenType   string
+	InstanceURL string
+	ID          string
+	Expiry      time.Time
+}
+
+// TokenProvider is a provider that supplies the session token itself, so
+// the session does not use the OAuth token endpoint.  Token is called each
+// time the session is refreshed.
+type TokenProvider interface {
+	Provider
+	Token(ctx context.Context) (Token, error)
+}
+
+// TokenProvider returns the credential's provider if it supplies the session
+// token itself.
+func (creds *Credentials) TokenProvider() (TokenProvider, bool) {
+	provider, ok := creds.provider.(TokenProvider)
+	return provider, ok
+}
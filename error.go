@@ -1,9 +1,10 @@
 package sfdc
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"net/http"
 	"strings"
 
@@ -84,22 +85,117 @@ func (e Errors) Error() string {
 	return strings.Join(msgs, ", ")
 }
 
-// HandleError makes an error from http.Response.
-// It is the caller's responsibility to close resp.Body.
+// DefaultMaxErrorBodyBytes is the number of bytes of the response body that
+// HandleError reads.
+const DefaultMaxErrorBodyBytes = 64 * 1024
+
+// HandleError makes an error from http.Response.  The body is decoded as an
+// array of Salesforce errors, a single Salesforce error or an OAuth error, and
+// is the message of the error otherwise.  If the request was sent
+// with a correlation ID, the error wraps a CorrelatedError, which errors.As
+// finds, and the error's type and cause are the ones without it.  At most
+// DefaultMaxErrorBodyBytes of the body are read.
+// It is the caller's responsibility to close resp.Body, like with CloseResponse.
 func HandleError(resp *http.Response) error {
-	return errors.Wrap(newErrorFromBody(resp), resp.Status)
+	return HandleErrorLimit(resp, DefaultMaxErrorBodyBytes)
 }
 
-func newErrorFromBody(resp *http.Response) error {
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return errors.Wrap(err, "could not read the body with error")
+// HandleErrorLimit is HandleError reading at most maxBytes of the body.  The
+// body is decoded as it is read, so the Salesforce errors are returned when the
+// body is within the limit.  Otherwise, the error message is the body, which is
+// marked as truncated if it is over the limit.  The response status is always
+// part of the error.
+func HandleErrorLimit(resp *http.Response, maxBytes int64) error {
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxErrorBodyBytes
+	}
+	err := newErrorFromBody(resp, maxBytes)
+	if resp.Request != nil {
+		if id, ok := CorrelationID(resp.Request.Context()); ok {
+			err = &CorrelatedError{
+				CorrelationID: id,
+				Err:           err,
+			}
+		}
 	}
+	return errors.Wrap(err, resp.Status)
+}
+
+// errorBodyReader keeps the first error of reading the body, so that it is not
+// mistaken for a decoding error.
+type errorBodyReader struct {
+	reader io.Reader
+	err    error
+}
+
+func (r *errorBodyReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if err != nil && err != io.EOF && r.err == nil {
+		r.err = err
+	}
+	return n, err
+}
+
+func newErrorFromBody(resp *http.Response, maxBytes int64) error {
+	if resp.Body == nil {
+		return errors.New("no body")
+	}
+	body := &errorBodyReader{
+		reader: &io.LimitedReader{R: resp.Body, N: maxBytes + 1},
+	}
+	var raw bytes.Buffer
+	reader := io.TeeReader(body, &raw)
 
 	errs := Errors{}
-	err = json.Unmarshal(body, &errs)
-	if err != nil {
-		return errors.New(string(body))
+	err := json.NewDecoder(reader).Decode(&errs)
+	if err == nil {
+		return errs
+	}
+	if body.err == nil {
+		_, _ = io.Copy(io.Discard, reader)
+	}
+	if body.err != nil {
+		return errors.Wrap(body.err, "could not read the body with error")
+	}
+
+	if int64(raw.Len()) > maxBytes {
+		raw.Truncate(int(maxBytes))
+		return fmt.Errorf("%s... (truncated to %d bytes)", raw.String(), maxBytes)
+	}
+	if errs, ok := errorObject(raw.Bytes()); ok {
+		return errs
+	}
+	return errors.New(raw.String())
+}
+
+// oauthError is the error object of the OAuth endpoints.
+type oauthError struct {
+	Error       string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+// errorObject decodes the error bodies that are a single object instead of an
+// array, like the errors of Apex REST and the OAuth endpoints.  The object is
+// a Salesforce error, with an errorCode or a message, or an OAuth error, whose
+// error is the code and error_description the message.
+func errorObject(data []byte) (Errors, bool) {
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, false
+	}
+	_, hasCode := keys["errorCode"]
+	_, hasMessage := keys["message"]
+	if hasCode || hasMessage {
+		var sfdcErr Error
+		if err := json.Unmarshal(data, &sfdcErr); err == nil {
+			return Errors{sfdcErr}, true
+		}
+	}
+	if _, ok := keys["error"]; ok {
+		var oauthErr oauthError
+		if err := json.Unmarshal(data, &oauthErr); err == nil && oauthErr.Error != "" {
+			return Errors{{ErrorCode: oauthErr.Error, Message: oauthErr.Description}}, true
+		}
 	}
-	return errs
+	return nil, false
 }
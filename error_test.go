@@ -3,7 +3,6 @@ package sfdc
 import (
 	"errors"
 	"io"
-	"io/ioutil"
 	"net/http"
 	"strings"
 	"testing"
@@ -101,7 +100,7 @@ func TestHandleError(t *testing.T) {
 		"single_error": {
 			resp: &http.Response{
 				Status: "400 " + http.StatusText(400),
-				Body:   ioutil.NopCloser(strings.NewReader(singleErrBody)),
+				Body:   io.NopCloser(strings.NewReader(singleErrBody)),
 			},
 			wantErr: `400 Bad Request: INVALID_ID_FIELD: invalid record id (id)`,
 			errors: Errors{
@@ -115,7 +114,7 @@ func TestHandleError(t *testing.T) {
 		"multiple_error": {
 			resp: &http.Response{
 				Status: "400 " + http.StatusText(400),
-				Body:   ioutil.NopCloser(strings.NewReader(multipleErrBody)),
+				Body:   io.NopCloser(strings.NewReader(multipleErrBody)),
 			},
 			wantErr: `400 Bad Request: INVALID_ID_FIELD: invalid record id (id), INVALID_ID_FIELD: invalid record id (id)`,
 			errors: Errors{
@@ -131,10 +130,64 @@ func TestHandleError(t *testing.T) {
 				},
 			},
 		},
+		"single_error_object": {
+			resp: &http.Response{
+				Status: "400 " + http.StatusText(400),
+				Body:   io.NopCloser(strings.NewReader(singleErr)),
+			},
+			wantErr: `400 Bad Request: INVALID_ID_FIELD: invalid record id (id)`,
+			errors: Errors{
+				{
+					Message:   "invalid record id",
+					ErrorCode: "INVALID_ID_FIELD",
+					Fields:    []string{"id"},
+				},
+			},
+		},
+		"apex_rest_error_object": {
+			resp: &http.Response{
+				Status: "500 " + http.StatusText(500),
+				Body:   io.NopCloser(strings.NewReader(`{"message":"Order is locked","errorCode":"APEX_ERROR"}`)),
+			},
+			wantErr: `500 Internal Server Error: APEX_ERROR: Order is locked ()`,
+			errors: Errors{
+				{
+					Message:   "Order is locked",
+					ErrorCode: "APEX_ERROR",
+				},
+			},
+		},
+		"oauth_error_object": {
+			resp: &http.Response{
+				Status: "400 " + http.StatusText(400),
+				Body:   io.NopCloser(strings.NewReader(`{"error":"invalid_grant","error_description":"authentication failure"}`)),
+			},
+			wantErr: `400 Bad Request: invalid_grant: authentication failure ()`,
+			errors: Errors{
+				{
+					Message:   "authentication failure",
+					ErrorCode: "invalid_grant",
+				},
+			},
+		},
+		"unknown_object": {
+			resp: &http.Response{
+				Status: "502 " + http.StatusText(502),
+				Body:   io.NopCloser(strings.NewReader(`{"status":"down"}`)),
+			},
+			wantErr: `502 Bad Gateway: {"status":"down"}`,
+		},
+		"invalid_json": {
+			resp: &http.Response{
+				Status: "503 " + http.StatusText(503),
+				Body:   io.NopCloser(strings.NewReader("<html>Service Unavailable</html>")),
+			},
+			wantErr: `503 Service Unavailable: <html>Service Unavailable</html>`,
+		},
 		"read_body_error": {
 			resp: &http.Response{
 				Status: "500 " + http.StatusText(500),
-				Body:   ioutil.NopCloser(alwaysError{}),
+				Body:   io.NopCloser(alwaysError{}),
 			},
 			wantErr: `500 Internal Server Error: could not read the body with error: unexpected EOF`,
 		},
@@ -154,3 +207,62 @@ func TestHandleError(t *testing.T) {
 		})
 	}
 }
+
+// countingReader is an endless body that counts the bytes read from it.
+type countingReader struct {
+	read int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	for idx := range p {
+		p[idx] = 'x'
+	}
+	r.read += int64(len(p))
+	return len(p), nil
+}
+
+func TestHandleErrorLimit(t *testing.T) {
+	t.Run("oversized_body", func(t *testing.T) {
+		body := &countingReader{}
+		err := HandleErrorLimit(&http.Response{
+			Status: "502 " + http.StatusText(502),
+			Body:   io.NopCloser(body),
+		}, 1024)
+
+		require.LessOrEqual(t, body.read, int64(1025))
+		want := "502 Bad Gateway: " + strings.Repeat("x", 1024) + "... (truncated to 1024 bytes)"
+		require.EqualError(t, err, want)
+	})
+
+	t.Run("default_limit", func(t *testing.T) {
+		body := &countingReader{}
+		err := HandleError(&http.Response{
+			Status: "502 " + http.StatusText(502),
+			Body:   io.NopCloser(body),
+		})
+
+		require.LessOrEqual(t, body.read, int64(DefaultMaxErrorBodyBytes+1))
+		require.Contains(t, err.Error(), "(truncated to 65536 bytes)")
+		require.True(t, strings.HasPrefix(err.Error(), "502 Bad Gateway: "))
+	})
+
+	t.Run("small_text_body", func(t *testing.T) {
+		err := HandleErrorLimit(&http.Response{
+			Status: "503 " + http.StatusText(503),
+			Body:   io.NopCloser(strings.NewReader("<html>Service Unavailable</html>")),
+		}, 1024)
+
+		require.EqualError(t, err, "503 Service Unavailable: <html>Service Unavailable</html>")
+	})
+
+	t.Run("errors_over_limit", func(t *testing.T) {
+		body := `[{"message":"invalid record id","errorCode":"INVALID_ID_FIELD","fields":["id"]}]`
+		err := HandleErrorLimit(&http.Response{
+			Status: "400 " + http.StatusText(400),
+			Body:   io.NopCloser(strings.NewReader(body)),
+		}, 16)
+
+		require.EqualError(t, err, "400 Bad Request: "+body[:16]+"... (truncated to 16 bytes)")
+		require.False(t, errors.As(err, &Errors{}))
+	})
+}
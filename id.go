@@ -0,0 +1,45 @@
+package sfdc
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var idPattern = regexp.MustCompile(`^[a-zA-Z0-9]{15}([a-zA-Z0-9]{3})?$`)
+
+// idSuffixAlphabet is the alphabet of the 18 character ID's checksum suffix.
+const idSuffixAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZ012345"
+
+// ValidID returns whether the ID has the form of a 15 or 18 character
+// Salesforce ID.  The checksum of an 18 character ID is checked by
+// NormalizeID.
+func ValidID(id string) bool {
+	return idPattern.MatchString(id)
+}
+
+// NormalizeID returns the 18 character form of the 15 or 18 character
+// Salesforce ID.  Each character of the suffix encodes which of five
+// characters of the ID are upper case, and the suffix of an 18 character ID
+// must match it, ignoring case.
+func NormalizeID(id string) (string, error) {
+	if ValidID(id) == false {
+		return "", fmt.Errorf("sfdc id: %q is not a valid id", id)
+	}
+	suffix := make([]byte, 3)
+	for chunk := range suffix {
+		flags := 0
+		for idx := 0; idx < 5; idx++ {
+			char := id[chunk*5+idx]
+			if char >= 'A' && char <= 'Z' {
+				flags |= 1 << idx
+			}
+		}
+		suffix[chunk] = idSuffixAlphabet[flags]
+	}
+	normalized := id[:15] + string(suffix)
+	if len(id) == 18 && strings.EqualFold(id[15:], normalized[15:]) == false {
+		return "", fmt.Errorf("sfdc id: %q has an invalid checksum", id)
+	}
+	return normalized, nil
+}
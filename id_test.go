@@ -0,0 +1,37 @@
+package sfdc
+
+import "testing"
+
+func TestNormalizeID(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		want    string
+		wantErr bool
+	}{
+		{name: "15 characters", id: "001A0000006Vm9r", want: "001A0000006Vm9rIAC"},
+		{name: "no upper case", id: "003000000000001", want: "003000000000001AAA"},
+		{name: "mixed case", id: "a0B5e00000ABCde", want: "a0B5e00000ABCdeEAH"},
+		{name: "all upper case", id: "ZZZZZZZZZZZZZZZ", want: "ZZZZZZZZZZZZZZZ555"},
+		{name: "18 characters", id: "001A0000006Vm9rIAC", want: "001A0000006Vm9rIAC"},
+		{name: "lower case suffix", id: "001A0000006Vm9riac", want: "001A0000006Vm9rIAC"},
+		{name: "invalid checksum", id: "001A0000006Vm9rAAA", wantErr: true},
+		{name: "length", id: "001A0000006Vm9", wantErr: true},
+		{name: "quote", id: "001A0000006Vm9'", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeID(tt.id)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NormalizeID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeID() = %v, want %v", got, tt.want)
+			}
+			// the checksum is only checked by NormalizeID
+			if want := tt.wantErr == false || tt.name == "invalid checksum"; ValidID(tt.id) != want {
+				t.Errorf("ValidID(%q) = %t, want %t", tt.id, ValidID(tt.id), want)
+			}
+		})
+	}
+}
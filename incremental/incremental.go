@@ -0,0 +1,400 @@
+// Package incremental extracts the records of an object that changed since the
+// last extraction, by the watermark of a field like SystemModstamp.
+package incremental
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/namely/go-sfdc/v3"
+	"github.com/namely/go-sfdc/v3/bulk"
+	"github.com/namely/go-sfdc/v3/session"
+	"github.com/namely/go-sfdc/v3/soql"
+)
+
+// DefaultWatermarkField is the field of the watermark when the options do not
+// have one.
+const DefaultWatermarkField = "SystemModstamp"
+
+const idField = "Id"
+
+// Options are the options of a cursor.
+//
+// Object is the object to extract, like Account.
+//
+// Fields are the fields of the records.  The Id and the watermark fields are
+// added when they are missing.
+//
+// WatermarkField is the date time field that the records are extracted by.
+// The default is DefaultWatermarkField.
+//
+// Key is the key of the cursor's watermark in the store.  The default is the
+// object.
+//
+// Overlap is the window before the watermark that is queried again, so the
+// records that were committed with an earlier watermark, like because of clock
+// skew, are not missed.  The records of the window are delivered again, unless
+// Dedup is set.
+//
+// Dedup will skip the records that were already delivered with the same
+// watermark, by their Id, so each change is delivered once.
+//
+// AtLeastOnce will save the watermark after each page is delivered, instead of
+// after all of the pages.  The records of the watermark are queried again, so
+// a run that fails is continued by the next run, and the records at the
+// watermark are delivered again, unless Dedup is set.
+//
+// BulkThreshold is the number of changed records at which the records are
+// extracted with a bulk query job, instead of a SOQL query.  The default is
+// sfdc.DefaultBulkThreshold, and a negative threshold always uses the SOQL
+// query.
+//
+// PageSize is the maxRecords of the pages of the bulk query job's results.
+// Zero lets Salesforce pick the page size.
+type Options struct {
+	Object         string
+	Fields         []string
+	WatermarkField string
+	Key            string
+	Overlap        time.Duration
+	Dedup          bool
+	AtLeastOnce    bool
+	BulkThreshold  int
+	PageSize       int
+}
+
+// Cursor extracts the records of an object that changed since its watermark.
+// A cursor is not safe for concurrent runs.
+type Cursor struct {
+	options Options
+	store   WatermarkStore
+	soql    *soql.Resource
+	bulk    *bulk.Resource
+}
+
+// NewCursor returns the cursor of the options, whose watermark is kept in the
+// store.
+func NewCursor(session session.ServiceFormatter, store WatermarkStore, options Options) (*Cursor, error) {
+	if session == nil {
+		return nil, errors.New("incremental cursor: session can not be nil")
+	}
+	if store == nil {
+		return nil, errors.New("incremental cursor: watermark store can not be nil")
+	}
+	if options.Object == "" {
+		return nil, errors.New("incremental cursor: object can not be empty")
+	}
+	if len(options.Fields) == 0 {
+		return nil, errors.New("incremental cursor: fields can not be empty")
+	}
+	if options.Overlap < 0 {
+		return nil, fmt.Errorf("incremental cursor: overlap %v can not be negative", options.Overlap)
+	}
+	if options.PageSize < 0 {
+		return nil, fmt.Errorf("incremental cursor: page size %d can not be negative", options.PageSize)
+	}
+	if options.WatermarkField == "" {
+		options.WatermarkField = DefaultWatermarkField
+	}
+	if options.Key == "" {
+		options.Key = options.Object
+	}
+	if options.BulkThreshold == 0 {
+		options.BulkThreshold = sfdc.DefaultBulkThreshold
+	}
+	options.Fields = withField(withField(options.Fields, idField), options.WatermarkField)
+
+	soqlResource, err := soql.NewResource(session)
+	if err != nil {
+		return nil, err
+	}
+	bulkResource, err := bulk.NewResource(session)
+	if err != nil {
+		return nil, err
+	}
+	return &Cursor{
+		options: options,
+		store:   store,
+		soql:    soqlResource,
+		bulk:    bulkResource,
+	}, nil
+}
+
+// Run will query the records whose watermark field is after the stored
+// watermark, less the overlap, and deliver them to the function a page at a
+// time, in the order of the watermark field.  The greatest watermark of the
+// records is saved after the function succeeds for all of the pages, or for
+// each page with AtLeastOnce.  The watermark is not saved when the function
+// fails, and its error is returned.
+//
+// The values of the records are the ones of the query: with a SOQL query they
+// are the JSON values, like float64 numbers and bool checkboxes, and with a
+// bulk query job they are the CSV text, like "12" and "true".  The null values
+// are nil with both.  A function that handles the records of both queries
+// converts the values it needs by the field's type.
+func (c *Cursor) Run(ctx context.Context, fn func(records []map[string]interface{}) error) error {
+	if fn == nil {
+		return errors.New("incremental cursor: function can not be nil")
+	}
+	watermark, err := c.store.Load(ctx, c.options.Key)
+	if err != nil {
+		return fmt.Errorf("incremental cursor: load watermark: %w", err)
+	}
+
+	r := &run{
+		cursor:    c,
+		fn:        fn,
+		watermark: watermark,
+		seen:      make(map[string]time.Time),
+	}
+	if watermark.Time.IsZero() == false {
+		r.from = watermark.Time.Add(-c.options.Overlap)
+	}
+
+	useBulk := false
+	if c.options.BulkThreshold > 0 {
+		count, err := c.count(ctx, r.from)
+		if err != nil {
+			return err
+		}
+		useBulk = count >= c.options.BulkThreshold
+	}
+	query, err := c.query(r.from, false)
+	if err != nil {
+		return err
+	}
+	if useBulk {
+		err = c.bulkPages(ctx, query, r.page)
+	} else {
+		err = c.soqlPages(ctx, query, r.page)
+	}
+	if err != nil {
+		return err
+	}
+	if c.options.AtLeastOnce {
+		return nil
+	}
+	return r.save(ctx)
+}
+
+// query returns the query of the records after the time, or their count.  The
+// time is truncated to the second of the SOQL date time, and the records
+// before it are skipped by the run.
+func (c *Cursor) query(from time.Time, count bool) (string, error) {
+	input := soql.QueryInput{
+		ObjectType: c.options.Object,
+	}
+	if count {
+		input.Fields = []soql.Field{{Function: "COUNT"}}
+	} else {
+		input.FieldList = c.options.Fields
+		order, err := soql.NewOrderBy(soql.OrderAsc)
+		if err != nil {
+			return "", err
+		}
+		order.FieldOrder(c.options.WatermarkField)
+		input.Order = order
+	}
+	if from.IsZero() == false {
+		where, err := soql.WhereGreaterThan(c.options.WatermarkField, from.UTC().Truncate(time.Second), true)
+		if err != nil {
+			return "", err
+		}
+		input.Where = where
+	}
+	query, err := soql.NewQuery(input)
+	if err != nil {
+		return "", fmt.Errorf("incremental cursor: %w", err)
+	}
+	return query.Format()
+}
+
+// count returns the number of records after the time.
+func (c *Cursor) count(ctx context.Context, from time.Time) (int, error) {
+	query, err := c.query(from, true)
+	if err != nil {
+		return 0, err
+	}
+	result, err := c.soql.QueryContext(ctx, statement(query), false)
+	if err != nil {
+		return 0, fmt.Errorf("incremental cursor: count: %w", err)
+	}
+	return result.TotalSize(), nil
+}
+
+// soqlPages delivers the pages of the SOQL query.
+func (c *Cursor) soqlPages(ctx context.Context, query string, page func(context.Context, []map[string]interface{}) error) error {
+	result, err := c.soql.QueryContext(ctx, statement(query), false)
+	for {
+		if err != nil {
+			return fmt.Errorf("incremental cursor: query: %w", err)
+		}
+		records := make([]map[string]interface{}, len(result.Records()))
+		for idx, record := range result.Records() {
+			records[idx] = record.Record().Fields()
+		}
+		if err := page(ctx, records); err != nil {
+			return err
+		}
+		if result.MoreRecords() == false {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		result, err = result.Next()
+	}
+}
+
+// bulkPages delivers the pages of the bulk query job.  The values are the
+// CSV text, and the empty values of the results are nil, like the null values
+// of a SOQL query.
+func (c *Cursor) bulkPages(ctx context.Context, query string, page func(context.Context, []map[string]interface{}) error) error {
+	job, err := c.bulk.CreateQueryJob(ctx, bulk.QueryOptions{
+		Operation: bulk.Query,
+		Query:     query,
+	})
+	if err != nil {
+		return fmt.Errorf("incremental cursor: bulk query: %w", err)
+	}
+	if _, err := job.Complete(ctx); err != nil {
+		return fmt.Errorf("incremental cursor: bulk query: %w", err)
+	}
+	locator := ""
+	for {
+		results, err := job.Results(ctx, locator, c.options.PageSize)
+		if err != nil {
+			return fmt.Errorf("incremental cursor: bulk query results: %w", err)
+		}
+		records := make([]map[string]interface{}, len(results.Records))
+		for idx, record := range results.Records {
+			values := make(map[string]interface{}, len(record))
+			for field, value := range record {
+				if value == "" {
+					values[field] = nil
+					continue
+				}
+				values[field] = value
+			}
+			records[idx] = values
+		}
+		if err := page(ctx, records); err != nil {
+			return err
+		}
+		if results.Locator == "" {
+			return nil
+		}
+		locator = results.Locator
+	}
+}
+
+// run is the state of a cursor's run.
+type run struct {
+	cursor    *Cursor
+	fn        func(records []map[string]interface{}) error
+	watermark Watermark
+	from      time.Time
+	max       time.Time
+	seen      map[string]time.Time
+}
+
+// page delivers the records of a page that were not delivered before, and
+// saves the watermark with AtLeastOnce.
+func (r *run) page(ctx context.Context, records []map[string]interface{}) error {
+	options := r.cursor.options
+	deliver := make([]map[string]interface{}, 0, len(records))
+	for _, record := range records {
+		id, _ := fieldValue(record, idField).(string)
+		value, _ := fieldValue(record, options.WatermarkField).(string)
+		at, err := sfdc.ParseTime(value)
+		if err != nil {
+			return fmt.Errorf("incremental cursor: record %s %s: %w", id, options.WatermarkField, err)
+		}
+		if r.from.IsZero() == false && (at.Before(r.from) || (at.Equal(r.from) && options.AtLeastOnce == false)) {
+			continue
+		}
+		if at.After(r.max) {
+			r.max = at
+		}
+		if options.Dedup {
+			if seenAt, ok := r.watermark.Seen[id]; ok && at.After(seenAt) == false {
+				continue
+			}
+			r.seen[id] = at
+		}
+		deliver = append(deliver, record)
+	}
+	if len(deliver) > 0 {
+		if err := r.fn(deliver); err != nil {
+			return err
+		}
+	}
+	if options.AtLeastOnce {
+		return r.save(ctx)
+	}
+	return nil
+}
+
+// save persists the greatest watermark of the delivered records, with the
+// records of the overlap when the cursor deduplicates them.
+func (r *run) save(ctx context.Context) error {
+	options := r.cursor.options
+	watermark := Watermark{
+		Time: r.watermark.Time,
+	}
+	if r.max.After(watermark.Time) {
+		watermark.Time = r.max
+	}
+	if watermark.Time.IsZero() {
+		return nil
+	}
+	if options.Dedup {
+		since := watermark.Time.Add(-options.Overlap)
+		watermark.Seen = make(map[string]time.Time)
+		for _, seen := range []map[string]time.Time{r.watermark.Seen, r.seen} {
+			for id, at := range seen {
+				if at.Before(since) == false && at.After(watermark.Seen[id]) {
+					watermark.Seen[id] = at
+				}
+			}
+		}
+	}
+	if err := r.cursor.store.Save(ctx, options.Key, watermark); err != nil {
+		return fmt.Errorf("incremental cursor: save watermark: %w", err)
+	}
+	return nil
+}
+
+// statement is a SOQL query that is already formed.
+type statement string
+
+func (s statement) Format() (string, error) {
+	return string(s), nil
+}
+
+// withField adds the field to the fields when it is missing.
+func withField(fields []string, field string) []string {
+	for _, name := range fields {
+		if strings.EqualFold(name, field) {
+			return fields
+		}
+	}
+	return append(append([]string{}, fields...), field)
+}
+
+// fieldValue returns the value of the field, matching its name without
+// regard to case.
+func fieldValue(record map[string]interface{}, field string) interface{} {
+	if value, ok := record[field]; ok {
+		return value
+	}
+	for name, value := range record {
+		if strings.EqualFold(name, field) {
+			return value
+		}
+	}
+	return nil
+}
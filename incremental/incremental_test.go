@@ -0,0 +1,497 @@
+package incremental
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/namely/go-sfdc/v3/bulk"
+)
+
+const testModstamp = "2006-01-02T15:04:05.000-0700"
+
+var fromPattern = regexp.MustCompile(`SystemModstamp >= (\S+)`)
+
+type fakeRecord struct {
+	id        string
+	name      string
+	employees int
+	modstamp  time.Time
+}
+
+// fakeOrg answers the SOQL and bulk queries of the cursor from its records,
+// filtered by the watermark of the query.
+type fakeOrg struct {
+	t        *testing.T
+	records  map[string]fakeRecord
+	pageSize int
+	pending  []fakeRecord
+	bulk     int
+}
+
+func newFakeOrg(t *testing.T, pageSize int) *fakeOrg {
+	return &fakeOrg{
+		t:        t,
+		records:  make(map[string]fakeRecord),
+		pageSize: pageSize,
+	}
+}
+
+func (o *fakeOrg) put(id, name string, modstamp time.Time) {
+	o.records[id] = fakeRecord{id: id, name: name, modstamp: modstamp}
+}
+
+func (o *fakeOrg) cursor(store WatermarkStore, options Options) *Cursor {
+	o.t.Helper()
+	cursor, err := NewCursor(&mockSessionFormatter{
+		url:    "https://test.salesforce.com",
+		client: mockHTTPClient(o.roundTrip),
+	}, store, options)
+	if err != nil {
+		o.t.Fatalf("NewCursor() error = %v", err)
+	}
+	return cursor
+}
+
+// matching returns the records of the query, ordered by their watermark.
+func (o *fakeOrg) matching(query string) []fakeRecord {
+	var from time.Time
+	if match := fromPattern.FindStringSubmatch(query); match != nil {
+		var err error
+		if from, err = time.Parse(time.RFC3339, match[1]); err != nil {
+			o.t.Fatalf("query %q watermark error = %v", query, err)
+		}
+	}
+	var records []fakeRecord
+	for _, record := range o.records {
+		if record.modstamp.Before(from) == false {
+			records = append(records, record)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].modstamp.Equal(records[j].modstamp) {
+			return records[i].id < records[j].id
+		}
+		return records[i].modstamp.Before(records[j].modstamp)
+	})
+	return records
+}
+
+func (o *fakeOrg) roundTrip(req *http.Request) *http.Response {
+	switch {
+	case strings.HasSuffix(req.URL.Path, "/query/"):
+		query := req.URL.Query().Get("q")
+		o.pending = o.matching(query)
+		if strings.Contains(query, "COUNT()") {
+			return jsonResponse(map[string]interface{}{"totalSize": len(o.pending), "done": true, "records": []interface{}{}})
+		}
+		return o.restPage(0)
+	case strings.Contains(req.URL.Path, "/query/next-"):
+		offset, _ := strconv.Atoi(req.URL.Path[strings.LastIndex(req.URL.Path, "-")+1:])
+		return o.restPage(offset)
+	case req.Method == http.MethodPost && strings.HasSuffix(req.URL.Path, "/jobs/query"):
+		var options bulk.QueryOptions
+		if err := json.NewDecoder(req.Body).Decode(&options); err != nil {
+			o.t.Fatalf("bulk query body error = %v", err)
+		}
+		o.bulk++
+		o.pending = o.matching(options.Query)
+		return jsonResponse(map[string]interface{}{"id": "750A", "state": "UploadComplete"})
+	case strings.HasSuffix(req.URL.Path, "/jobs/query/750A"):
+		return jsonResponse(map[string]interface{}{"id": "750A", "state": "JobComplete"})
+	case strings.HasSuffix(req.URL.Path, "/jobs/query/750A/results"):
+		offset, _ := strconv.Atoi(req.URL.Query().Get("locator"))
+		size, _ := strconv.Atoi(req.URL.Query().Get("maxRecords"))
+		end := len(o.pending)
+		locator := "null"
+		if size > 0 && offset+size < end {
+			end = offset + size
+			locator = strconv.Itoa(end)
+		}
+		var body strings.Builder
+		body.WriteString("\"Id\",\"Name\",\"NumberOfEmployees\",\"SystemModstamp\"\n")
+		for _, record := range o.pending[offset:end] {
+			employees := ""
+			if record.employees != 0 {
+				employees = strconv.Itoa(record.employees)
+			}
+			fmt.Fprintf(&body, "\"%s\",\"%s\",\"%s\",\"%s\"\n", record.id, record.name, employees, record.modstamp.Format(testModstamp))
+		}
+		header := make(http.Header)
+		header.Set("Sforce-Locator", locator)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(body.String())),
+			Header:     header,
+		}
+	}
+	o.t.Errorf("unexpected request %s %s", req.Method, req.URL)
+	return &http.Response{
+		StatusCode: http.StatusNotFound,
+		Body:       io.NopCloser(strings.NewReader("[]")),
+		Header:     make(http.Header),
+	}
+}
+
+func (o *fakeOrg) restPage(offset int) *http.Response {
+	end := len(o.pending)
+	value := map[string]interface{}{
+		"totalSize": len(o.pending),
+		"done":      true,
+	}
+	if offset+o.pageSize < end {
+		end = offset + o.pageSize
+		value["done"] = false
+		value["nextRecordsUrl"] = fmt.Sprintf("/services/data/v42.0/query/next-%d", end)
+	}
+	records := []interface{}{}
+	for _, record := range o.pending[offset:end] {
+		var name, employees interface{}
+		if record.name != "" {
+			name = record.name
+		}
+		if record.employees != 0 {
+			employees = record.employees
+		}
+		records = append(records, map[string]interface{}{
+			"attributes":        map[string]interface{}{"type": "Account", "url": "/services/data/v42.0/sobjects/Account/" + record.id},
+			"Id":                record.id,
+			"Name":              name,
+			"NumberOfEmployees": employees,
+			"SystemModstamp":    record.modstamp.Format(testModstamp),
+		})
+	}
+	value["records"] = records
+	return jsonResponse(value)
+}
+
+func jsonResponse(value interface{}) *http.Response {
+	body, _ := json.Marshal(value)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(string(body))),
+		Header:     make(http.Header),
+	}
+}
+
+// collect returns the function of a run that appends the IDs of the records
+// to the pages.
+func collect(pages *[][]string) func([]map[string]interface{}) error {
+	return func(records []map[string]interface{}) error {
+		var ids []string
+		for _, record := range records {
+			ids = append(ids, record["Id"].(string))
+		}
+		*pages = append(*pages, ids)
+		return nil
+	}
+}
+
+func TestCursor_Run_Dedup(t *testing.T) {
+	base := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		dedup bool
+		want  [][]string
+	}{
+		{
+			name:  "exactly once",
+			dedup: true,
+			want:  [][]string{{"001C"}, {"001A"}},
+		},
+		{
+			name: "overlap delivered again",
+			want: [][]string{{"001C", "001B"}, {"001A"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			org := newFakeOrg(t, 2)
+			org.put("001A", "Acme", base)
+			org.put("001B", "Globex", base.Add(5*time.Second))
+			store := NewMemoryStore()
+			cursor := org.cursor(store, Options{
+				Object:        "Account",
+				Fields:        []string{"Name"},
+				Overlap:       time.Minute,
+				Dedup:         tt.dedup,
+				BulkThreshold: -1,
+			})
+
+			var first [][]string
+			if err := cursor.Run(context.Background(), collect(&first)); err != nil {
+				t.Fatalf("Cursor.Run() error = %v", err)
+			}
+			if want := [][]string{{"001A", "001B"}}; !reflect.DeepEqual(first, want) {
+				t.Errorf("Cursor.Run() first pages = %v, want %v", first, want)
+			}
+
+			// 001C was committed late with an earlier watermark, and 001A changed
+			org.put("001C", "Initech", base.Add(3*time.Second))
+			org.put("001A", "Acme Corp", base.Add(6*time.Second))
+			var second [][]string
+			if err := cursor.Run(context.Background(), collect(&second)); err != nil {
+				t.Fatalf("Cursor.Run() error = %v", err)
+			}
+			if !reflect.DeepEqual(second, tt.want) {
+				t.Errorf("Cursor.Run() second pages = %v, want %v", second, tt.want)
+			}
+
+			watermark, err := store.Load(context.Background(), "Account")
+			if err != nil {
+				t.Fatalf("MemoryStore.Load() error = %v", err)
+			}
+			if watermark.Time.Equal(base.Add(6*time.Second)) == false {
+				t.Errorf("Cursor.Run() watermark = %v, want %v", watermark.Time, base.Add(6*time.Second))
+			}
+			if tt.dedup && len(watermark.Seen) != 3 {
+				t.Errorf("Cursor.Run() seen = %v, want the 3 records of the overlap", watermark.Seen)
+			}
+
+			var third [][]string
+			if err := cursor.Run(context.Background(), collect(&third)); err != nil {
+				t.Fatalf("Cursor.Run() error = %v", err)
+			}
+			if tt.dedup && len(third) != 0 {
+				t.Errorf("Cursor.Run() third pages = %v, want none", third)
+			}
+		})
+	}
+}
+
+func TestCursor_Run_Bulk(t *testing.T) {
+	base := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+	org := newFakeOrg(t, 2)
+	org.put("001A", "Acme", base)
+	org.put("001B", "", base.Add(time.Second))
+	org.put("001C", "Initech", base.Add(2*time.Second))
+	store := NewMemoryStore()
+	cursor := org.cursor(store, Options{
+		Object:        "Account",
+		Fields:        []string{"Id", "Name", "SystemModstamp"},
+		BulkThreshold: 3,
+		PageSize:      2,
+	})
+
+	var pages [][]string
+	var name interface{} = "unset"
+	err := cursor.Run(context.Background(), func(records []map[string]interface{}) error {
+		for _, record := range records {
+			if record["Id"] == "001B" {
+				name = record["Name"]
+			}
+		}
+		return collect(&pages)(records)
+	})
+	if err != nil {
+		t.Fatalf("Cursor.Run() error = %v", err)
+	}
+	if org.bulk != 1 {
+		t.Errorf("Cursor.Run() bulk query jobs = %d, want 1", org.bulk)
+	}
+	if want := [][]string{{"001A", "001B"}, {"001C"}}; !reflect.DeepEqual(pages, want) {
+		t.Errorf("Cursor.Run() pages = %v, want %v", pages, want)
+	}
+	if name != nil {
+		t.Errorf("Cursor.Run() empty name = %v, want nil", name)
+	}
+
+	// below the threshold the records are queried with SOQL
+	org.put("001D", "Umbrella", base.Add(3*time.Second))
+	pages = nil
+	if err := cursor.Run(context.Background(), collect(&pages)); err != nil {
+		t.Fatalf("Cursor.Run() error = %v", err)
+	}
+	if org.bulk != 1 {
+		t.Errorf("Cursor.Run() bulk query jobs = %d, want 1", org.bulk)
+	}
+	if want := [][]string{{"001D"}}; !reflect.DeepEqual(pages, want) {
+		t.Errorf("Cursor.Run() pages = %v, want %v", pages, want)
+	}
+}
+
+func TestCursor_Run_bulkAndSOQLValues(t *testing.T) {
+	base := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+	org := newFakeOrg(t, 10)
+	org.records["001A"] = fakeRecord{id: "001A", name: "Acme", employees: 12, modstamp: base}
+	org.put("001B", "", base.Add(time.Second))
+
+	run := func(threshold int) map[string]map[string]interface{} {
+		cursor := org.cursor(NewMemoryStore(), Options{
+			Object:        "Account",
+			Fields:        []string{"Id", "Name", "NumberOfEmployees", "SystemModstamp"},
+			BulkThreshold: threshold,
+		})
+		records := make(map[string]map[string]interface{})
+		err := cursor.Run(context.Background(), func(page []map[string]interface{}) error {
+			for _, record := range page {
+				records[record["Id"].(string)] = record
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Cursor.Run() error = %v", err)
+		}
+		return records
+	}
+	soqlRecords := run(-1)
+	bulkRecords := run(1)
+	if org.bulk != 1 {
+		t.Fatalf("Cursor.Run() bulk query jobs = %d, want 1", org.bulk)
+	}
+
+	// the string and null values are the same, while a number is a JSON
+	// number with SOQL and the CSV text with a bulk query job
+	for _, id := range []string{"001A", "001B"} {
+		for _, field := range []string{"Id", "Name", "SystemModstamp"} {
+			if soqlRecords[id][field] != bulkRecords[id][field] {
+				t.Errorf("%s %s soql = %#v, bulk = %#v", id, field, soqlRecords[id][field], bulkRecords[id][field])
+			}
+		}
+	}
+	if soqlRecords["001B"]["Name"] != nil || soqlRecords["001B"]["NumberOfEmployees"] != nil || bulkRecords["001B"]["NumberOfEmployees"] != nil {
+		t.Errorf("null values soql = %v, bulk = %v, want nil", soqlRecords["001B"], bulkRecords["001B"])
+	}
+	if got := soqlRecords["001A"]["NumberOfEmployees"]; got != float64(12) {
+		t.Errorf("soql NumberOfEmployees = %#v, want float64(12)", got)
+	}
+	if got := bulkRecords["001A"]["NumberOfEmployees"]; got != "12" {
+		t.Errorf("bulk NumberOfEmployees = %#v, want \"12\"", got)
+	}
+}
+
+func TestCursor_Run_AtLeastOnce(t *testing.T) {
+	base := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+	errDelivery := errors.New("delivery failed")
+	tests := []struct {
+		name        string
+		atLeastOnce bool
+		watermark   time.Time
+		want        [][]string
+	}{
+		{
+			name:        "saved per page",
+			atLeastOnce: true,
+			watermark:   base.Add(time.Second),
+			want:        [][]string{{"001B", "001C"}},
+		},
+		{
+			name: "saved after all pages",
+			want: [][]string{{"001A", "001B"}, {"001C"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			org := newFakeOrg(t, 2)
+			org.put("001A", "Acme", base)
+			org.put("001B", "Globex", base.Add(time.Second))
+			org.put("001C", "Initech", base.Add(2*time.Second))
+			store := NewMemoryStore()
+			cursor := org.cursor(store, Options{
+				Object:        "Account",
+				Fields:        []string{"Name"},
+				Key:           "accounts",
+				AtLeastOnce:   tt.atLeastOnce,
+				BulkThreshold: -1,
+			})
+
+			calls := 0
+			err := cursor.Run(context.Background(), func(records []map[string]interface{}) error {
+				calls++
+				if calls == 2 {
+					return errDelivery
+				}
+				return nil
+			})
+			if err != errDelivery {
+				t.Fatalf("Cursor.Run() error = %v, want %v", err, errDelivery)
+			}
+			watermark, err := store.Load(context.Background(), "accounts")
+			if err != nil {
+				t.Fatalf("MemoryStore.Load() error = %v", err)
+			}
+			if watermark.Time.Equal(tt.watermark) == false {
+				t.Errorf("Cursor.Run() watermark = %v, want %v", watermark.Time, tt.watermark)
+			}
+
+			var pages [][]string
+			if err := cursor.Run(context.Background(), collect(&pages)); err != nil {
+				t.Fatalf("Cursor.Run() error = %v", err)
+			}
+			if !reflect.DeepEqual(pages, tt.want) {
+				t.Errorf("Cursor.Run() pages = %v, want %v", pages, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewCursor(t *testing.T) {
+	session := &mockSessionFormatter{url: "https://test.salesforce.com"}
+	store := NewMemoryStore()
+	tests := []struct {
+		name    string
+		store   WatermarkStore
+		options Options
+		wantErr bool
+	}{
+		{
+			name:    "defaults",
+			store:   store,
+			options: Options{Object: "Account", Fields: []string{"Name"}},
+		},
+		{
+			name:    "no store",
+			options: Options{Object: "Account", Fields: []string{"Name"}},
+			wantErr: true,
+		},
+		{
+			name:    "no object",
+			store:   store,
+			options: Options{Fields: []string{"Name"}},
+			wantErr: true,
+		},
+		{
+			name:    "no fields",
+			store:   store,
+			options: Options{Object: "Account"},
+			wantErr: true,
+		},
+		{
+			name:    "negative overlap",
+			store:   store,
+			options: Options{Object: "Account", Fields: []string{"Name"}, Overlap: -time.Second},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor, err := NewCursor(session, tt.store, tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewCursor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			want := Options{
+				Object:         "Account",
+				Fields:         []string{"Name", "Id", DefaultWatermarkField},
+				WatermarkField: DefaultWatermarkField,
+				Key:            "Account",
+				BulkThreshold:  2000,
+			}
+			if !reflect.DeepEqual(cursor.options, want) {
+				t.Errorf("NewCursor() options = %+v, want %+v", cursor.options, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package incremental
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Watermark is the position of a cursor.
+//
+// Time is the greatest watermark field value of the delivered records.  It is
+// zero before the first run.
+//
+// Seen are the IDs of the records delivered within the overlap before Time,
+// with their watermark field values, so the records are not delivered again
+// by the next run.  It is only kept when the cursor deduplicates the records.
+type Watermark struct {
+	Time time.Time            `json:"time"`
+	Seen map[string]time.Time `json:"seen,omitempty"`
+}
+
+// WatermarkStore persists the watermarks of the cursors.
+//
+// Load returns the watermark of the key, or a zero watermark when it does not
+// have one.
+//
+// Save persists the watermark of the key.  It is called after the records up
+// to the watermark are delivered, so it is persisted with the data.
+type WatermarkStore interface {
+	Load(ctx context.Context, key string) (Watermark, error)
+	Save(ctx context.Context, key string, watermark Watermark) error
+}
+
+// MemoryStore is a WatermarkStore that keeps the watermarks in memory.  It is
+// safe for concurrent use.
+type MemoryStore struct {
+	mu         sync.Mutex
+	watermarks map[string]Watermark
+}
+
+// NewMemoryStore returns an empty memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		watermarks: make(map[string]Watermark),
+	}
+}
+
+// Load returns the watermark of the key.
+func (s *MemoryStore) Load(ctx context.Context, key string) (Watermark, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return copyWatermark(s.watermarks[key]), nil
+}
+
+// Save keeps the watermark of the key.
+func (s *MemoryStore) Save(ctx context.Context, key string, watermark Watermark) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.watermarks[key] = copyWatermark(watermark)
+	return nil
+}
+
+func copyWatermark(watermark Watermark) Watermark {
+	if watermark.Seen == nil {
+		return watermark
+	}
+	seen := make(map[string]time.Time, len(watermark.Seen))
+	for id, at := range watermark.Seen {
+		seen[id] = at
+	}
+	watermark.Seen = seen
+	return watermark
+}
@@ -0,0 +1,12 @@
+package sfdc
+
+import "context"
+
+// Instrumentation is used to trace the Salesforce API calls.
+//
+// StartSpan will start a span for the operation with the attributes.  The
+// returned context carries the span, so spans started with it are nested, and
+// the returned function ends the span with the operation's error.
+type Instrumentation interface {
+	StartSpan(ctx context.Context, op string, attrs map[string]interface{}) (context.Context, func(err error))
+}
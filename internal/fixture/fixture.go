@@ -0,0 +1,205 @@
+// Package fixture loads the recorded Salesforce responses of the tests and
+// checks that they decode into the package's structs.
+//
+// The fixtures are sanitized responses of API versions 42.0, 50.0 and 58.0,
+// and are in the package's testdata directory:
+//
+//	testdata/<version>/<name>.json
+package fixture
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// Load returns the fixture of the API version.
+func Load(t testing.TB, version, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", version, name+".json"))
+	if err != nil {
+		t.Fatalf("fixture %s %s: %v", version, name, err)
+	}
+	return data
+}
+
+// Decode will decode the fixture into the value.  The test fails when the
+// fixture does not decode or has a field that the value maps to a different
+// type.
+func Decode(t testing.TB, data []byte, value interface{}) {
+	t.Helper()
+	report, err := Check(data, reflect.TypeOf(value))
+	if err != nil {
+		t.Fatalf("fixture: %v", err)
+	}
+	for _, mismatch := range report.Mismatches {
+		t.Errorf("fixture: %s", mismatch)
+	}
+	if err := json.Unmarshal(data, value); err != nil {
+		t.Fatalf("fixture: decode %T: %v", value, err)
+	}
+}
+
+// Report is the result of checking a fixture against a type.
+//
+// Mismatches are the fields that the type maps to a different type, like a
+// number that is mapped to a string.
+//
+// Unknown are the fields that the type does not map, which are ignored when
+// the fixture is decoded.
+type Report struct {
+	Mismatches []string
+	Unknown    []string
+}
+
+// Check will walk the fixture and the type together and report the fields
+// that the type maps to a different type and the fields it does not map.
+// Fields of an interface type or a type that unmarshals itself are not
+// checked.  Nulls match every type.
+func Check(data []byte, typ reflect.Type) (Report, error) {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return Report{}, err
+	}
+	report := &Report{}
+	check(report, "$", value, typ)
+	sort.Strings(report.Mismatches)
+	sort.Strings(report.Unknown)
+	return *report, nil
+}
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+func check(report *Report, path string, value interface{}, typ reflect.Type) {
+	if value == nil {
+		return
+	}
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() == reflect.Interface || reflect.PtrTo(typ).Implements(unmarshalerType) {
+		return
+	}
+
+	mismatch := func() {
+		report.Mismatches = append(report.Mismatches, fmt.Sprintf("%s is %s, mapped to %s", path, kind(value), typ))
+	}
+	switch v := value.(type) {
+	case bool:
+		if typ.Kind() != reflect.Bool {
+			mismatch()
+		}
+	case string:
+		if typ.Kind() != reflect.String {
+			mismatch()
+		}
+	case float64:
+		switch typ.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if v != float64(int64(v)) {
+				mismatch()
+			}
+		case reflect.Float32, reflect.Float64:
+		default:
+			mismatch()
+		}
+	case []interface{}:
+		if typ.Kind() != reflect.Slice && typ.Kind() != reflect.Array {
+			mismatch()
+			return
+		}
+		for idx, element := range v {
+			check(report, fmt.Sprintf("%s[%d]", path, idx), element, typ.Elem())
+		}
+	case map[string]interface{}:
+		switch typ.Kind() {
+		case reflect.Map:
+			for key, element := range v {
+				check(report, path+"."+key, element, typ.Elem())
+			}
+		case reflect.Struct:
+			fields := structFields(typ)
+			for key, element := range v {
+				field, has := lookupField(fields, key)
+				if has == false {
+					report.Unknown = append(report.Unknown, path+"."+key)
+					continue
+				}
+				check(report, path+"."+key, element, field)
+			}
+		default:
+			mismatch()
+		}
+	}
+}
+
+func kind(value interface{}) string {
+	switch value.(type) {
+	case bool:
+		return "a bool"
+	case string:
+		return "a string"
+	case float64:
+		return "a number"
+	case []interface{}:
+		return "an array"
+	default:
+		return "an object"
+	}
+}
+
+// structFields returns the types of the struct's JSON fields by name, with
+// the fields of the embedded structs.
+func structFields(typ reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for idx := 0; idx < typ.NumField(); idx++ {
+		field := typ.Field(idx)
+		tag := field.Tag.Get("json")
+		name := strings.Split(tag, ",")[0]
+		if name == "-" && tag == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for name, typ := range structFields(embedded) {
+					if _, has := fields[name]; has == false {
+						fields[name] = typ
+					}
+				}
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+// lookupField matches the key to a field like encoding/json, which prefers an
+// exact match and then matches case insensitively.
+func lookupField(fields map[string]reflect.Type, key string) (reflect.Type, bool) {
+	if typ, has := fields[key]; has {
+		return typ, true
+	}
+	for name, typ := range fields {
+		if strings.EqualFold(name, key) {
+			return typ, true
+		}
+	}
+	return nil, false
+}
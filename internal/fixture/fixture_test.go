@@ -0,0 +1,96 @@
+package fixture
+
+import (
+	"reflect"
+	"testing"
+)
+
+type level int
+
+func (l *level) UnmarshalJSON(data []byte) error {
+	*l = level(len(data))
+	return nil
+}
+
+type embedded struct {
+	Created bool `json:"created"`
+}
+
+type child struct {
+	Name string `json:"name"`
+}
+
+type value struct {
+	embedded
+	ID       string            `json:"id"`
+	Size     int               `json:"size"`
+	Version  float32           `json:"version"`
+	Children []child           `json:"children"`
+	Labels   map[string]string `json:"labels"`
+	Level    level             `json:"level"`
+	Any      interface{}       `json:"any"`
+	Ignored  string            `json:"-"`
+	Untagged string
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name           string
+		data           string
+		wantMismatches []string
+		wantUnknown    []string
+		wantErr        bool
+	}{
+		{
+			name: "matching",
+			data: `{"id": "001A", "size": 2, "version": 58.0, "created": true, "children": [{"name": "a"}],
+				"labels": {"en": "Account"}, "level": "high", "any": [1], "untagged": "x"}`,
+		},
+		{
+			name: "nulls",
+			data: `{"id": null, "size": null, "children": [null], "labels": null}`,
+		},
+		{
+			name: "wrong types",
+			data: `{"id": 1, "size": "2", "version": "58.0", "created": "true", "children": {"name": "a"},
+				"labels": {"en": 1}}`,
+			wantMismatches: []string{
+				"$.children is an object, mapped to []fixture.child",
+				"$.created is a string, mapped to bool",
+				"$.id is a number, mapped to string",
+				"$.labels.en is a number, mapped to string",
+				"$.size is a string, mapped to int",
+				"$.version is a string, mapped to float32",
+			},
+		},
+		{
+			name:           "fractional integer",
+			data:           `{"size": 2.5}`,
+			wantMismatches: []string{"$.size is a number, mapped to int"},
+		},
+		{
+			name:        "unknown fields",
+			data:        `{"id": "001A", "Ignored": "x", "children": [{"name": "a", "label": "b"}]}`,
+			wantUnknown: []string{"$.Ignored", "$.children[0].label"},
+		},
+		{
+			name:    "invalid",
+			data:    `{"id":`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Check([]byte(tt.data), reflect.TypeOf(&value{}))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got.Mismatches, tt.wantMismatches) {
+				t.Errorf("Check() mismatches = %v, want %v", got.Mismatches, tt.wantMismatches)
+			}
+			if !reflect.DeepEqual(got.Unknown, tt.wantUnknown) {
+				t.Errorf("Check() unknown = %v, want %v", got.Unknown, tt.wantUnknown)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package sfdc
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const servicesPrefix = "services/"
+
+// ResolveServicePath will resolve the service path returned by Salesforce, like a
+// next records URL or a content URL, against the instance URL.  The path can have
+// a leading slash or not, and must start with the services prefix.  If the path
+// is already an absolute URL, it is returned as is when it has the instance's
+// origin, and is an error otherwise, since the session's token is sent with it.
+func ResolveServicePath(instanceURL, path string) (string, error) {
+	if path == "" {
+		return "", errors.New("service path: path can not be empty")
+	}
+
+	instance, err := url.Parse(instanceURL)
+	if err != nil {
+		return "", err
+	}
+	if instance.IsAbs() == false || instance.Host == "" {
+		return "", fmt.Errorf("service path: %s is not an absolute instance url", instanceURL)
+	}
+
+	if resolved, err := url.Parse(path); err == nil && resolved.IsAbs() && resolved.Host != "" {
+		if SameOrigin(resolved, instance) == false {
+			return "", fmt.Errorf("service path: %s is not the instance's origin", resolved.Redacted())
+		}
+		return path, nil
+	}
+
+	trimmed := strings.TrimLeft(path, "/")
+	if strings.HasPrefix(trimmed, servicesPrefix) == false {
+		return "", fmt.Errorf("service path: %s is not a service path", path)
+	}
+
+	return strings.TrimRight(instanceURL, "/") + "/" + trimmed, nil
+}
+
+// SameOrigin returns whether the URLs have the same scheme and host, so a
+// request to one can carry the credentials of the other.
+func SameOrigin(a, b *url.URL) bool {
+	return strings.EqualFold(a.Scheme, b.Scheme) && strings.EqualFold(a.Host, b.Host)
+}
+
+// RebaseServicePath will resolve the service path like ResolveServicePath, except
+// that an absolute URL of a service path is moved to the instance URL.  It is used
+// for the URLs that were returned before the session was refreshed, since the
+// org's instance can change when it is migrated.  An absolute URL that is not a
+// service path must have the instance's origin.
+func RebaseServicePath(instanceURL, path string) (string, error) {
+	if resolved, err := url.Parse(path); err == nil && resolved.IsAbs() && resolved.Host != "" &&
+		strings.HasPrefix(strings.TrimLeft(resolved.Path, "/"), servicesPrefix) {
+		resolved.Scheme = ""
+		resolved.Host = ""
+		resolved.User = nil
+		path = resolved.String()
+	}
+	return ResolveServicePath(instanceURL, path)
+}
+
+// EscapePath will join the path segments with slashes, escaping each of them,
+// so identifiers, like an external ID with a slash, space or non-ASCII
+// character, are a single segment of the URL path.  A plus is escaped, since
+// some servers decode it as a space, and a segment of dots is escaped so it
+// can not move the path to a different resource.
+func EscapePath(segments ...string) string {
+	escaped := make([]string, len(segments))
+	for idx, segment := range segments {
+		switch segment {
+		case ".", "..":
+			escaped[idx] = strings.Repeat("%2E", len(segment))
+		default:
+			escaped[idx] = strings.ReplaceAll(url.PathEscape(segment), "+", "%2B")
+		}
+	}
+	return strings.Join(escaped, "/")
+}
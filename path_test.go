@@ -0,0 +1,172 @@
+package sfdc
+
+import "testing"
+
+func TestResolveServicePath(t *testing.T) {
+	tests := []struct {
+		name        string
+		instanceURL string
+		path        string
+		want        string
+		wantErr     bool
+	}{
+		{
+			name:        "leading slash",
+			instanceURL: "https://na1.salesforce.com",
+			path:        "/services/data/v20.0/query/01gD0000002HU6KIAW-2000",
+			want:        "https://na1.salesforce.com/services/data/v20.0/query/01gD0000002HU6KIAW-2000",
+		},
+		{
+			name:        "no leading slash",
+			instanceURL: "https://na1.salesforce.com/",
+			path:        "services/data/v44.0/jobs/ingest/7505fEXAMPLE4C2AAM/batches",
+			want:        "https://na1.salesforce.com/services/data/v44.0/jobs/ingest/7505fEXAMPLE4C2AAM/batches",
+		},
+		{
+			name:        "absolute url",
+			instanceURL: "https://na1.salesforce.com",
+			path:        "https://NA1.salesforce.com/services/data/v44.0/jobs/ingest/7505fEXAMPLE4C2AAM/batches",
+			want:        "https://NA1.salesforce.com/services/data/v44.0/jobs/ingest/7505fEXAMPLE4C2AAM/batches",
+		},
+		{
+			name:        "absolute url on another host",
+			instanceURL: "https://na1.salesforce.com",
+			path:        "https://evil.example.com/services/data/v44.0/jobs/ingest/7505fEXAMPLE4C2AAM/batches",
+			wantErr:     true,
+		},
+		{
+			name:        "absolute url with another scheme",
+			instanceURL: "https://na1.salesforce.com",
+			path:        "http://na1.salesforce.com/services/data/v44.0/query",
+			wantErr:     true,
+		},
+		{
+			name:        "not a service path",
+			instanceURL: "https://na1.salesforce.com",
+			path:        "/data/v44.0/query",
+			wantErr:     true,
+		},
+		{
+			name:        "empty path",
+			instanceURL: "https://na1.salesforce.com",
+			wantErr:     true,
+		},
+		{
+			name:        "relative instance url",
+			instanceURL: "na1.salesforce.com",
+			path:        "/services/data/v44.0/query",
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveServicePath(tt.instanceURL, tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ResolveServicePath() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ResolveServicePath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRebaseServicePath(t *testing.T) {
+	tests := []struct {
+		name        string
+		instanceURL string
+		path        string
+		want        string
+		wantErr     bool
+	}{
+		{
+			name:        "relative path",
+			instanceURL: "https://na2.salesforce.com",
+			path:        "/services/data/v44.0/query/01gD0000002HU6KIAW-2000",
+			want:        "https://na2.salesforce.com/services/data/v44.0/query/01gD0000002HU6KIAW-2000",
+		},
+		{
+			name:        "absolute url on the old instance",
+			instanceURL: "https://na2.salesforce.com",
+			path:        "https://na1.salesforce.com/services/data/v44.0/jobs/ingest/7505fEXAMPLE4C2AAM/batches?locator=abc",
+			want:        "https://na2.salesforce.com/services/data/v44.0/jobs/ingest/7505fEXAMPLE4C2AAM/batches?locator=abc",
+		},
+		{
+			name:        "absolute url that is not a service path",
+			instanceURL: "https://na2.salesforce.com",
+			path:        "https://na2.salesforce.com/content/export.csv",
+			want:        "https://na2.salesforce.com/content/export.csv",
+		},
+		{
+			name:        "absolute url on another host",
+			instanceURL: "https://na2.salesforce.com",
+			path:        "https://files.example.com/export.csv",
+			wantErr:     true,
+		},
+		{
+			name:        "not a service path",
+			instanceURL: "https://na2.salesforce.com",
+			path:        "/data/v44.0/query",
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RebaseServicePath(tt.instanceURL, tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RebaseServicePath() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("RebaseServicePath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEscapePath(t *testing.T) {
+	tests := []struct {
+		name     string
+		segments []string
+		want     string
+	}{
+		{
+			name:     "plain segments",
+			segments: []string{"Account", "001D000000K0fXOIAZ"},
+			want:     "Account/001D000000K0fXOIAZ",
+		},
+		{
+			name:     "slash",
+			segments: []string{"Account", "External__c", "EXT/1"},
+			want:     "Account/External__c/EXT%2F1",
+		},
+		{
+			name:     "space plus and percent",
+			segments: []string{"A b+c%d"},
+			want:     "A%20b%2Bc%25d",
+		},
+		{
+			name:     "question mark and hash",
+			segments: []string{"a?b#c"},
+			want:     "a%3Fb%23c",
+		},
+		{
+			name:     "non ascii",
+			segments: []string{"café"},
+			want:     "caf%C3%A9",
+		},
+		{
+			name:     "dot segments",
+			segments: []string{"Account", "..", "."},
+			want:     "Account/%2E%2E/%2E",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EscapePath(tt.segments...); got != tt.want {
+				t.Errorf("EscapePath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
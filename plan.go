@@ -0,0 +1,121 @@
+package sfdc
+
+import "fmt"
+
+// Pathway is the API recommended for a DML operation.
+type Pathway string
+
+const (
+	// PathwayCollections is the SObject Collections API.
+	PathwayCollections Pathway = "collections"
+	// PathwayBulk is the Bulk 2.0 API.
+	PathwayBulk Pathway = "bulk"
+)
+
+const (
+	// CollectionsMaxRecords is the maximum number of records in a SObject
+	// Collections request.
+	CollectionsMaxRecords = 200
+	// BulkMaxUploadBytes is the maximum size of the data uploaded to a Bulk 2.0
+	// job.
+	BulkMaxUploadBytes = 150 * 1024 * 1024
+	// BulkMaxChunkBytes is the most CSV data of a Bulk 2.0 job whose base64
+	// encoding, which the upload limit applies to, fits in BulkMaxUploadBytes.
+	BulkMaxChunkBytes = BulkMaxUploadBytes / 4 * 3
+	// BulkCallsPerJob is the number of API calls for a Bulk 2.0 job, which are
+	// create, upload, close, at least one status check and the results.
+	BulkCallsPerJob = 5
+	// DefaultBulkThreshold is the number of records at which the Bulk 2.0 API
+	// is recommended.  At 2,000 records, the SObject Collections API takes 10
+	// calls, twice the calls of a bulk job.
+	DefaultBulkThreshold = 2000
+	// DefaultAvgRecordBytes is the record size used when it is not known.
+	DefaultAvgRecordBytes = 512
+)
+
+// Plan is the recommended API for a DML operation, along with the estimates
+// it is based on.
+//
+// Pathway is the recommended API.
+//
+// Reason explains the recommendation.
+//
+// Records is the number of records.
+//
+// PayloadBytes is the estimated size of the records.
+//
+// CollectionsCalls is the number of SObject Collections API calls.
+//
+// BulkJobs is the number of Bulk 2.0 jobs, since a job's data is limited to
+// BulkMaxChunkBytes.
+//
+// BulkCalls is the number of Bulk 2.0 API calls.
+type Plan struct {
+	Pathway          Pathway
+	Reason           string
+	Records          int
+	PayloadBytes     int
+	CollectionsCalls int
+	BulkJobs         int
+	BulkCalls        int
+}
+
+// PlanOption is an option for the DML plan.
+type PlanOption func(*planOptions)
+
+type planOptions struct {
+	bulkThreshold int
+}
+
+// WithBulkThreshold will set the number of records at which the Bulk 2.0 API
+// is recommended.
+func WithBulkThreshold(threshold int) PlanOption {
+	return func(opts *planOptions) {
+		opts.bulkThreshold = threshold
+	}
+}
+
+// PlanDML recommends the API for a DML operation of the records.  The Bulk 2.0
+// API is recommended at or above the bulk threshold, which is DefaultBulkThreshold
+// unless it is set with WithBulkThreshold.  If the average record size is zero
+// or less, DefaultAvgRecordBytes is used.  The plan is advisory and does not
+// take the org's API limits into account.
+func PlanDML(recordCount int, avgRecordBytes int, options ...PlanOption) Plan {
+	opts := planOptions{
+		bulkThreshold: DefaultBulkThreshold,
+	}
+	for _, option := range options {
+		option(&opts)
+	}
+	if recordCount < 0 {
+		recordCount = 0
+	}
+	if avgRecordBytes <= 0 {
+		avgRecordBytes = DefaultAvgRecordBytes
+	}
+
+	plan := Plan{
+		Records:          recordCount,
+		PayloadBytes:     recordCount * avgRecordBytes,
+		CollectionsCalls: divideRoundUp(recordCount, CollectionsMaxRecords),
+	}
+	if recordCount > 0 {
+		plan.BulkJobs = divideRoundUp(plan.PayloadBytes, BulkMaxChunkBytes)
+		plan.BulkCalls = plan.BulkJobs * BulkCallsPerJob
+	}
+
+	if recordCount >= opts.bulkThreshold {
+		plan.Pathway = PathwayBulk
+		plan.Reason = fmt.Sprintf("%d records is at or above the bulk threshold of %d: %d bulk calls in %d jobs instead of %d collections calls",
+			recordCount, opts.bulkThreshold, plan.BulkCalls, plan.BulkJobs, plan.CollectionsCalls)
+	} else {
+		plan.Pathway = PathwayCollections
+		plan.Reason = fmt.Sprintf("%d records is below the bulk threshold of %d: %d collections calls without the bulk job overhead",
+			recordCount, opts.bulkThreshold, plan.CollectionsCalls)
+	}
+	return plan
+}
+
+func divideRoundUp(value, divisor int) int {
+	return (value + divisor - 1) / divisor
+}
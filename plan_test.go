@@ -0,0 +1,136 @@
+package sfdc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPlanDML(t *testing.T) {
+	type args struct {
+		recordCount    int
+		avgRecordBytes int
+		options        []PlanOption
+	}
+	tests := []struct {
+		name string
+		args args
+		want Plan
+	}{
+		{
+			name: "No Records",
+			args: args{},
+			want: Plan{
+				Pathway: PathwayCollections,
+			},
+		},
+		{
+			name: "Single Collections Call",
+			args: args{
+				recordCount:    50,
+				avgRecordBytes: 100,
+			},
+			want: Plan{
+				Pathway:          PathwayCollections,
+				Records:          50,
+				PayloadBytes:     5000,
+				CollectionsCalls: 1,
+				BulkJobs:         1,
+				BulkCalls:        BulkCallsPerJob,
+			},
+		},
+		{
+			name: "Below Threshold",
+			args: args{
+				recordCount: DefaultBulkThreshold - 1,
+			},
+			want: Plan{
+				Pathway:          PathwayCollections,
+				Records:          DefaultBulkThreshold - 1,
+				PayloadBytes:     (DefaultBulkThreshold - 1) * DefaultAvgRecordBytes,
+				CollectionsCalls: 10,
+				BulkJobs:         1,
+				BulkCalls:        BulkCallsPerJob,
+			},
+		},
+		{
+			name: "At Threshold",
+			args: args{
+				recordCount: DefaultBulkThreshold,
+			},
+			want: Plan{
+				Pathway:          PathwayBulk,
+				Records:          DefaultBulkThreshold,
+				PayloadBytes:     DefaultBulkThreshold * DefaultAvgRecordBytes,
+				CollectionsCalls: 10,
+				BulkJobs:         1,
+				BulkCalls:        BulkCallsPerJob,
+			},
+		},
+		{
+			name: "Custom Threshold",
+			args: args{
+				recordCount:    201,
+				avgRecordBytes: 1000,
+				options:        []PlanOption{WithBulkThreshold(201)},
+			},
+			want: Plan{
+				Pathway:          PathwayBulk,
+				Records:          201,
+				PayloadBytes:     201000,
+				CollectionsCalls: 2,
+				BulkJobs:         1,
+				BulkCalls:        BulkCallsPerJob,
+			},
+		},
+		{
+			name: "Multiple Bulk Jobs",
+			args: args{
+				recordCount:    100000,
+				avgRecordBytes: 2000,
+			},
+			want: Plan{
+				Pathway:          PathwayBulk,
+				Records:          100000,
+				PayloadBytes:     200000000,
+				CollectionsCalls: 500,
+				BulkJobs:         2,
+				BulkCalls:        2 * BulkCallsPerJob,
+			},
+		},
+		{
+			name: "Base64 Upload Limit",
+			args: args{
+				recordCount:    130000,
+				avgRecordBytes: 1000,
+			},
+			want: Plan{
+				Pathway:          PathwayBulk,
+				Records:          130000,
+				PayloadBytes:     130000000,
+				CollectionsCalls: 650,
+				BulkJobs:         2,
+				BulkCalls:        2 * BulkCallsPerJob,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PlanDML(tt.args.recordCount, tt.args.avgRecordBytes, tt.args.options...)
+			if got.Reason == "" {
+				t.Errorf("PlanDML() reason is empty")
+			}
+			got.Reason = ""
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PlanDML() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanDML_reason(t *testing.T) {
+	plan := PlanDML(10000, 0)
+	want := "10000 records is at or above the bulk threshold of 2000: 5 bulk calls in 1 jobs instead of 50 collections calls"
+	if plan.Reason != want {
+		t.Errorf("PlanDML() reason = %v, want %v", plan.Reason, want)
+	}
+}
@@ -0,0 +1,24 @@
+package sfdc
+
+import "fmt"
+
+// QueryOptionsHeader is the header of the options of a query, like the batch
+// size of its results.
+const QueryOptionsHeader = "Sforce-Query-Options"
+
+// The batch size of the query results is between MinQueryBatchSize and
+// MaxQueryBatchSize records.
+const (
+	MinQueryBatchSize = 200
+	MaxQueryBatchSize = 2000
+)
+
+// QueryBatchSize returns the QueryOptionsHeader value of the batch size of the
+// query results, like "batchSize=500".  Salesforce may return fewer records
+// than the batch size, to optimize the query.
+func QueryBatchSize(size int) (string, error) {
+	if size < MinQueryBatchSize || size > MaxQueryBatchSize {
+		return "", fmt.Errorf("query options: batch size %d is not between %d and %d", size, MinQueryBatchSize, MaxQueryBatchSize)
+	}
+	return fmt.Sprintf("batchSize=%d", size), nil
+}
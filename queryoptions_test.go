@@ -0,0 +1,44 @@
+package sfdc
+
+import "testing"
+
+func TestQueryBatchSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    int
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "minimum",
+			size: 200,
+			want: "batchSize=200",
+		},
+		{
+			name: "maximum",
+			size: 2000,
+			want: "batchSize=2000",
+		},
+		{
+			name:    "too small",
+			size:    199,
+			wantErr: true,
+		},
+		{
+			name:    "too large",
+			size:    2001,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := QueryBatchSize(tt.size)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("QueryBatchSize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("QueryBatchSize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,6 +3,10 @@ package sfdc
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
 )
 
 const (
@@ -15,10 +19,11 @@ const (
 // Record is a representation of a Salesforce
 // record.
 type Record struct {
-	sobject string
-	url     string
-	fields  map[string]interface{}
-	lookUps map[string]*Record
+	sobject   string
+	url       string
+	fields    map[string]interface{}
+	lookUps   map[string]*Record
+	compounds map[string]map[string]interface{}
 }
 
 // RecordFromJSONMap creates a recrod from a JSON map.
@@ -53,6 +58,7 @@ func (r *Record) UnmarshalJSON(data []byte) error {
 func (r *Record) fromJSONMap(jsonMap map[string]interface{}) {
 	r.fields = make(map[string]interface{})
 	r.lookUps = make(map[string]*Record)
+	r.compounds = nil
 
 	for k, v := range jsonMap {
 		if k == RecordAttributes {
@@ -77,6 +83,11 @@ func (r *Record) fromJSONMap(jsonMap map[string]interface{}) {
 						if rec, err := RecordFromJSONMap(obj); err == nil {
 							r.lookUps[k] = rec
 						}
+					} else if r.isSubresult(obj) == false {
+						if r.compounds == nil {
+							r.compounds = make(map[string]map[string]interface{})
+						}
+						r.compounds[k] = obj
 					}
 				}
 			}
@@ -89,6 +100,13 @@ func (r *Record) isLookUp(jsonMap map[string]interface{}) bool {
 	return has
 }
 
+// isSubresult returns whether the map is the result of an inner query.
+func (r *Record) isSubresult(jsonMap map[string]interface{}) bool {
+	_, hasRecords := jsonMap["records"]
+	_, hasTotalSize := jsonMap["totalSize"]
+	return hasRecords && hasTotalSize
+}
+
 // LookUps returns all of the record's look ups
 func (r *Record) LookUps() []*Record {
 	records := make([]*Record, len(r.lookUps))
@@ -100,6 +118,16 @@ func (r *Record) LookUps() []*Record {
 	return records
 }
 
+// LookUpFields returns the sorted fields of the record's look ups.
+func (r *Record) LookUpFields() []string {
+	fields := make([]string, 0, len(r.lookUps))
+	for field := range r.lookUps {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
 // LookUp returns the look up record
 func (r *Record) LookUp(lookUp string) (*Record, bool) {
 	if len(r.lookUps) == 0 {
@@ -135,3 +163,48 @@ func (r *Record) Fields() map[string]interface{} {
 	}
 	return fields
 }
+
+// Int64Value returns the field's integer value.  The value is exact when it
+// was decoded as a json.Number, like by the SOQL resource with WithUseNumber.
+// A float64 value was already rounded when it was decoded, so an integer of
+// more than 15 digits may not be the value that Salesforce returned.  An
+// error is returned when the field is not present or is not an integer.
+func (r *Record) Int64Value(field string) (int64, error) {
+	value, has := r.fields[field]
+	if has == false {
+		return 0, fmt.Errorf("record: field %s is not present", field)
+	}
+	switch v := value.(type) {
+	case json.Number:
+		return v.Int64()
+	case float64:
+		if v != math.Trunc(v) || v < math.MinInt64 || v >= math.MaxInt64 {
+			return 0, fmt.Errorf("record: field %s value %v is not an integer", field, v)
+		}
+		return int64(v), nil
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	default:
+		return 0, fmt.Errorf("record: field %s is a %T, not a number", field, value)
+	}
+}
+
+// DecimalValue returns the field's number as a decimal string, like
+// "1234.5678", that can be parsed by a decimal package without the rounding
+// of a float64.  The value is exact when it was decoded as a json.Number, like
+// by the SOQL resource with WithUseNumber.  An error is returned when the
+// field is not present or is not a number.
+func (r *Record) DecimalValue(field string) (string, error) {
+	value, has := r.fields[field]
+	if has == false {
+		return "", fmt.Errorf("record: field %s is not present", field)
+	}
+	switch v := value.(type) {
+	case json.Number:
+		return v.String(), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	default:
+		return "", fmt.Errorf("record: field %s is a %T, not a number", field, value)
+	}
+}
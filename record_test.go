@@ -1,6 +1,7 @@
 package sfdc
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
 )
@@ -494,3 +495,93 @@ func TestRecord_LookUps(t *testing.T) {
 		})
 	}
 }
+
+func TestRecord_LookUpFields(t *testing.T) {
+	r := &Record{
+		lookUps: map[string]*Record{
+			"Owner":  {sobject: "User"},
+			"Parent": {sobject: "Account"},
+			"Contact": {
+				sobject: "Contact",
+			},
+		},
+	}
+	if got, want := r.LookUpFields(), []string{"Contact", "Owner", "Parent"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Record.LookUpFields() = %v, want %v", got, want)
+	}
+	if got := (&Record{}).LookUpFields(); len(got) != 0 {
+		t.Errorf("Record.LookUpFields() = %v, want none", got)
+	}
+}
+
+func TestRecord_Int64Value(t *testing.T) {
+	r := &Record{
+		fields: map[string]interface{}{
+			"ExternalId__c":     json.Number("123456789012345678"),
+			"RoundedId__c":      float64(123456789012345678),
+			"NumberOfEmployees": float64(42),
+			"Ratio__c":          1.5,
+			"Name":              "Acme",
+			"Legacy__c":         "987654321",
+		},
+	}
+	tests := []struct {
+		field   string
+		want    int64
+		wantErr bool
+	}{
+		{field: "ExternalId__c", want: 123456789012345678},
+		// the float64 was rounded when it was decoded
+		{field: "RoundedId__c", want: 123456789012345680},
+		{field: "NumberOfEmployees", want: 42},
+		{field: "Legacy__c", want: 987654321},
+		{field: "Ratio__c", wantErr: true},
+		{field: "Name", wantErr: true},
+		{field: "Missing__c", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			got, err := r.Int64Value(tt.field)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Record.Int64Value() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Record.Int64Value() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecord_DecimalValue(t *testing.T) {
+	r := &Record{
+		fields: map[string]interface{}{
+			"Amount":        json.Number("12345678901234.5678"),
+			"RoundedAmount": 12345678901234.5678,
+			"Ratio__c":      1.5,
+			"Name":          "Acme",
+		},
+	}
+	tests := []struct {
+		field   string
+		want    string
+		wantErr bool
+	}{
+		{field: "Amount", want: "12345678901234.5678"},
+		// the float64 was rounded when it was decoded
+		{field: "RoundedAmount", want: "12345678901234.568"},
+		{field: "Ratio__c", want: "1.5"},
+		{field: "Name", wantErr: true},
+		{field: "Missing__c", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			got, err := r.DecimalValue(tt.field)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Record.DecimalValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Record.DecimalValue() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package sfdc
+
+import (
+	"io"
+	"net/http"
+)
+
+// MaxDrainBytes is the most of a response body that CloseResponse reads
+// before closing it.  A larger body is closed without being read to the end,
+// which drops its connection instead of waiting for the body.
+const MaxDrainBytes = 256 << 10
+
+// CloseResponse will read the rest of the response body, up to MaxDrainBytes,
+// and close it.  The HTTP transport only reuses the connection of a body that
+// was read to the end, so every response, including the errors, is closed with
+// it.  A nil response or body is ignored.
+func CloseResponse(resp *http.Response) error {
+	if resp == nil || resp.Body == nil {
+		return nil
+	}
+	_, err := io.Copy(io.Discard, io.LimitReader(resp.Body, MaxDrainBytes))
+	if closeErr := resp.Body.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
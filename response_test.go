@@ -0,0 +1,54 @@
+package sfdc
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type trackingBody struct {
+	io.Reader
+	closed bool
+}
+
+func (b *trackingBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestCloseResponse(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantLeft int
+	}{
+		{
+			name: "drained",
+			body: `[{"errorCode": "NOT_FOUND", "message": "The requested resource does not exist"}]`,
+		},
+		{
+			name:     "over the limit",
+			body:     strings.Repeat("x", MaxDrainBytes+10),
+			wantLeft: 10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := strings.NewReader(tt.body)
+			body := &trackingBody{Reader: reader}
+			if err := CloseResponse(&http.Response{Body: body}); err != nil {
+				t.Fatalf("CloseResponse() error = %v", err)
+			}
+			if body.closed == false {
+				t.Error("CloseResponse() did not close the body")
+			}
+			if reader.Len() != tt.wantLeft {
+				t.Errorf("CloseResponse() left %d bytes, want %d", reader.Len(), tt.wantLeft)
+			}
+		})
+	}
+	if err := CloseResponse(nil); err != nil {
+		t.Errorf("CloseResponse() error = %v for a nil response", err)
+	}
+}
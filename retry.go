@@ -0,0 +1,237 @@
+package sfdc
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"syscall"
+	"time"
+)
+
+// StopBackOff is returned by the back off when no more retries are made.
+const StopBackOff time.Duration = -1
+
+// RetryPolicy defines how an operation is retried.
+//
+// InitialInterval is the wait before the first retry.
+//
+// Multiplier is the factor the wait grows by after each retry.
+//
+// MaxInterval caps the wait between retries.
+//
+// MaxAttempts is the number of attempts, including the first one.  Zero is no
+// limit.
+//
+// MaxElapsedTime is the time after which no more retries are made.  Zero is
+// no limit.
+//
+// Jitter is the randomization factor of the wait, from zero to one.  A wait is
+// chosen from the interval plus or minus the factor of the interval.
+//
+// Seed is the seed of the jitter's random source, so that the waits can be
+// repeated.  Zero seeds the source from the time.
+//
+// Retryable classifies whether the error of an attempt is retried.  If it is
+// nil, IsTransient is used.
+type RetryPolicy struct {
+	InitialInterval time.Duration
+	Multiplier      float64
+	MaxInterval     time.Duration
+	MaxAttempts     int
+	MaxElapsedTime  time.Duration
+	Jitter          float64
+	Seed            int64
+	Retryable       func(error) bool
+}
+
+// NewRetryPolicy returns a retry policy with the defaults: five attempts
+// starting at half a second, growing by half up to thirty seconds, within two
+// minutes and with a jitter of half the wait.
+func NewRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		InitialInterval: 500 * time.Millisecond,
+		Multiplier:      1.5,
+		MaxInterval:     30 * time.Second,
+		MaxAttempts:     5,
+		MaxElapsedTime:  2 * time.Minute,
+		Jitter:          0.5,
+		Retryable:       IsTransient,
+	}
+}
+
+// ShouldRetry returns whether the error is retried by the policy.
+func (p RetryPolicy) ShouldRetry(err error) bool {
+	if err == nil {
+		return false
+	}
+	var permanent *PermanentError
+	if errors.As(err, &permanent) {
+		return false
+	}
+	if p.Retryable == nil {
+		return IsTransient(err)
+	}
+	return p.Retryable(err)
+}
+
+// BackOff returns the waits of the policy for an operation.  Its methods match
+// the BackOff interface of github.com/cenkalti/backoff, so it can be used with
+// that package.
+func (p RetryPolicy) BackOff() *BackOff {
+	seed := p.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	b := &BackOff{
+		policy: p,
+		random: rand.New(rand.NewSource(seed)),
+	}
+	b.Reset()
+	return b
+}
+
+// BackOff is the state of the policy's waits for an operation.  It is not safe
+// for concurrent use.
+type BackOff struct {
+	policy   RetryPolicy
+	random   *rand.Rand
+	interval time.Duration
+	attempts int
+	start    time.Time
+}
+
+// Reset starts the waits over.
+func (b *BackOff) Reset() {
+	b.interval = b.policy.InitialInterval
+	b.attempts = 0
+	b.start = time.Now()
+}
+
+// NextBackOff returns the wait before the next attempt, or StopBackOff if the
+// attempts or the elapsed time are exhausted.
+func (b *BackOff) NextBackOff() time.Duration {
+	b.attempts++
+	if b.policy.MaxAttempts > 0 && b.attempts >= b.policy.MaxAttempts {
+		return StopBackOff
+	}
+	if b.policy.MaxElapsedTime > 0 && time.Since(b.start) > b.policy.MaxElapsedTime {
+		return StopBackOff
+	}
+
+	wait := b.interval
+	if b.policy.Jitter > 0 {
+		delta := b.policy.Jitter * float64(wait)
+		wait = time.Duration(float64(wait) - delta + b.random.Float64()*(2*delta+1))
+	}
+
+	next := time.Duration(float64(b.interval) * b.policy.Multiplier)
+	if b.policy.MaxInterval > 0 && next > b.policy.MaxInterval {
+		next = b.policy.MaxInterval
+	}
+	b.interval = next
+	return wait
+}
+
+// PermanentError is an error that is not retried, whatever the policy's
+// classifier returns.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the error that is not retried.
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// Permanent wraps the error so that it is not retried.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{Err: err}
+}
+
+// transientErrorCodes are the Salesforce error codes of the failures that can
+// succeed when retried.
+var transientErrorCodes = map[string]struct{}{
+	"UNABLE_TO_LOCK_ROW":       {},
+	"SERVER_UNAVAILABLE":       {},
+	"REQUEST_RUNNING_TOO_LONG": {},
+}
+
+// IsTransient returns whether the error is a failure that can succeed when
+// retried, like a network timeout, a reset connection or a row lock.  The
+// context's errors are not transient.
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	var errs Errors
+	if errors.As(err, &errs) {
+		for _, e := range errs {
+			if _, has := transientErrorCodes[e.ErrorCode]; has {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Retry will call the function until it succeeds, returns an error that the
+// policy does not retry, or the policy's attempts are exhausted.  The waits are
+// interrupted by the context.  The last error is returned.
+func Retry(ctx context.Context, policy RetryPolicy, fn func(context.Context) error) error {
+	if fn == nil {
+		return errors.New("retry: function can not be nil")
+	}
+	backOff := policy.BackOff()
+	for {
+		err := fn(ctx)
+		if err == nil || policy.ShouldRetry(err) == false {
+			return err
+		}
+		wait := backOff.NextBackOff()
+		if wait == StopBackOff {
+			return err
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+type requestRetryKey struct{}
+
+// WithRequestRetry will have a session that retries its requests, like with
+// the MaxRetries of the Configuration, also retry the requests of the context
+// that are not idempotent, like a POST or a PATCH.  The requests are only
+// retried when their body can be read again.
+func WithRequestRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, requestRetryKey{}, true)
+}
+
+// RequestRetry returns whether the requests of the context are retried
+// whatever their method.
+func RequestRetry(ctx context.Context) bool {
+	retry, _ := ctx.Value(requestRetryKey{}).(bool)
+	return retry
+}
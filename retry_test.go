@@ -0,0 +1,223 @@
+package sfdc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func backOffSchedule(policy RetryPolicy) []time.Duration {
+	var schedule []time.Duration
+	backOff := policy.BackOff()
+	for {
+		wait := backOff.NextBackOff()
+		if wait == StopBackOff {
+			return schedule
+		}
+		schedule = append(schedule, wait)
+	}
+}
+
+func TestRetryPolicy_BackOff(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy func() RetryPolicy
+		want   []time.Duration
+	}{
+		{
+			name: "no jitter",
+			policy: func() RetryPolicy {
+				policy := NewRetryPolicy()
+				policy.Jitter = 0
+				policy.MaxAttempts = 8
+				policy.MaxInterval = 3 * time.Second
+				return policy
+			},
+			want: []time.Duration{
+				500 * time.Millisecond,
+				750 * time.Millisecond,
+				1125 * time.Millisecond,
+				1687500 * time.Microsecond,
+				2531250 * time.Microsecond,
+				3 * time.Second,
+				3 * time.Second,
+			},
+		},
+		{
+			name: "seeded jitter",
+			policy: func() RetryPolicy {
+				policy := NewRetryPolicy()
+				policy.Seed = 42
+				policy.MaxAttempts = 8
+				policy.MaxInterval = 3 * time.Second
+				return policy
+			},
+			want: []time.Duration{
+				436514180,
+				424500372,
+				1242105583,
+				1196131561,
+				1376540473,
+				2649579900,
+				3938631408,
+			},
+		},
+		{
+			name: "single attempt",
+			policy: func() RetryPolicy {
+				policy := NewRetryPolicy()
+				policy.MaxAttempts = 1
+				return policy
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := backOffSchedule(tt.policy())
+			if reflect.DeepEqual(got, tt.want) == false {
+				t.Errorf("RetryPolicy.BackOff() schedule = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_BackOff_reset(t *testing.T) {
+	policy := NewRetryPolicy()
+	policy.Jitter = 0
+	backOff := policy.BackOff()
+	backOff.NextBackOff()
+	backOff.NextBackOff()
+	backOff.Reset()
+	if got := backOff.NextBackOff(); got != policy.InitialInterval {
+		t.Errorf("BackOff.NextBackOff() after Reset() = %v, want %v", got, policy.InitialInterval)
+	}
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+var _ net.Error = timeoutError{}
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "nil",
+		},
+		{
+			name: "network timeout",
+			err:  fmt.Errorf("post: %w", timeoutError{}),
+			want: true,
+		},
+		{
+			name: "connection reset",
+			err:  &net.OpError{Op: "read", Err: syscall.ECONNRESET},
+			want: true,
+		},
+		{
+			name: "row lock",
+			err:  fmt.Errorf("400 Bad Request: %w", Errors{{ErrorCode: "UNABLE_TO_LOCK_ROW", Message: "unable to obtain exclusive access to this record"}}),
+			want: true,
+		},
+		{
+			name: "invalid field",
+			err:  Errors{{ErrorCode: "INVALID_FIELD", Message: "No such column 'Foo'"}},
+		},
+		{
+			name: "context canceled",
+			err:  fmt.Errorf("post: %w", context.Canceled),
+		},
+		{
+			name: "plain error",
+			err:  errors.New("bulk job: object is required"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTransient(tt.err); got != tt.want {
+				t.Errorf("IsTransient() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetry(t *testing.T) {
+	policy := NewRetryPolicy()
+	policy.InitialInterval = time.Millisecond
+	policy.Jitter = 0
+	transient := Errors{{ErrorCode: "SERVER_UNAVAILABLE", Message: "server unavailable"}}
+	permanent := Errors{{ErrorCode: "INVALID_FIELD", Message: "No such column 'Foo'"}}
+
+	tests := []struct {
+		name         string
+		errs         []error
+		wantErr      error
+		wantAttempts int
+	}{
+		{
+			name:         "success after transient errors",
+			errs:         []error{transient, transient, nil},
+			wantAttempts: 3,
+		},
+		{
+			name:         "permanent error",
+			errs:         []error{transient, permanent, nil},
+			wantErr:      permanent,
+			wantAttempts: 2,
+		},
+		{
+			name:         "marked permanent",
+			errs:         []error{Permanent(transient), nil},
+			wantErr:      transient,
+			wantAttempts: 1,
+		},
+		{
+			name:         "attempts exhausted",
+			errs:         []error{transient, transient, transient, transient, transient, nil},
+			wantErr:      transient,
+			wantAttempts: 5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			err := Retry(context.Background(), policy, func(context.Context) error {
+				err := tt.errs[attempts]
+				attempts++
+				return err
+			})
+			if fmt.Sprint(err) != fmt.Sprint(tt.wantErr) {
+				t.Errorf("Retry() error = %v, want %v", err, tt.wantErr)
+			}
+			if attempts != tt.wantAttempts {
+				t.Errorf("Retry() attempts = %d, want %d", attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestRetry_contextCanceled(t *testing.T) {
+	policy := NewRetryPolicy()
+	policy.InitialInterval = time.Hour
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts := 0
+	err := Retry(ctx, policy, func(context.Context) error {
+		attempts++
+		cancel()
+		return Errors{{ErrorCode: "SERVER_UNAVAILABLE"}}
+	})
+	if err == nil || attempts != 1 {
+		t.Errorf("Retry() = %v after %d attempts, want the error after 1 attempt", err, attempts)
+	}
+}
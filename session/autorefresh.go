@@ -0,0 +1,132 @@
+package session
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/namely/go-sfdc/v3"
+	"github.com/pkg/errors"
+)
+
+// autoRefreshJitter is the fraction of the lead time that a renewal is moved
+// earlier by, at random, so the replicas of a service do not renew at once.
+const autoRefreshJitter = 0.1
+
+// autoRefreshPolicy is the back off of the renewals after a failed renewal.
+var autoRefreshPolicy = sfdc.RetryPolicy{
+	InitialInterval: time.Second,
+	Multiplier:      2,
+	MaxInterval:     time.Minute,
+	Jitter:          0.5,
+}
+
+// StartAutoRefresh will renew the session in the background, the lead time
+// before it expires, so the API calls do not wait for a refresh.  Each renewal
+// is moved earlier by up to a tenth of the lead time, at random.  A failed
+// renewal is reported to the OnRefresh hook and retried with a back off, and
+// the current token is used until it expires.  A refresh by ForceRefresh or
+// Refresh reschedules the renewal.  The renewals stop when the context is
+// canceled or the session is closed.
+func (s *Session) StartAutoRefresh(ctx context.Context, leadTime time.Duration) error {
+	if leadTime < 0 {
+		return errors.New("session: auto refresh lead time can not be negative")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrSessionClosed
+	}
+	if s.autoRefreshDone != nil {
+		return errors.New("session: auto refresh is already started")
+	}
+	if s.stopped == nil {
+		s.stopped = make(chan struct{})
+	}
+	if s.renewed == nil {
+		s.renewed = make(chan struct{}, 1)
+	}
+	done := make(chan struct{})
+	s.autoRefreshDone = done
+
+	go s.autoRefresh(ctx, leadTime, done)
+	return nil
+}
+
+// autoRefresh renews the session until the context is canceled or the session
+// is closed.
+func (s *Session) autoRefresh(ctx context.Context, leadTime time.Duration, done chan struct{}) {
+	defer func() {
+		s.mu.Lock()
+		s.autoRefreshDone = nil
+		s.mu.Unlock()
+		close(done)
+	}()
+
+	random := rand.New(rand.NewSource(time.Now().UnixNano()))
+	backOff := autoRefreshPolicy.BackOff()
+	timer := time.NewTimer(renewalDelay(s.expiry(), time.Now(), leadTime, random))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopped:
+			return
+		case <-s.renewed:
+			if timer.Stop() == false {
+				<-timer.C
+			}
+			backOff.Reset()
+			timer.Reset(renewalDelay(s.expiry(), time.Now(), leadTime, random))
+			continue
+		case <-timer.C:
+		}
+
+		err := s.refresh(ctx)
+		switch {
+		case errors.Is(err, ErrSessionClosed) || ctx.Err() != nil:
+			return
+		case err != nil:
+			timer.Reset(backOff.NextBackOff())
+			continue
+		}
+		// the renewal was signaled by this refresh
+		select {
+		case <-s.renewed:
+		default:
+		}
+		// a token that is valid for less than the lead time is renewed with
+		// the back off, instead of at once
+		delay := renewalDelay(s.expiry(), time.Now(), leadTime, random)
+		if delay == 0 {
+			delay = backOff.NextBackOff()
+		} else {
+			backOff.Reset()
+		}
+		timer.Reset(delay)
+	}
+}
+
+// renewalDelay returns the wait before the session that expires at the time
+// is renewed, the lead time before it expires less up to a tenth of the lead
+// time.
+func renewalDelay(expiresAt, now time.Time, leadTime time.Duration, random *rand.Rand) time.Duration {
+	jitter := time.Duration(random.Float64() * autoRefreshJitter * float64(leadTime))
+	delay := expiresAt.Sub(now) - leadTime - jitter
+	if delay < 0 {
+		return 0
+	}
+	return delay
+}
+
+// expiry returns when the session expires.
+func (s *Session) expiry() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.expiresAt
+}
@@ -0,0 +1,233 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/namely/go-sfdc/v3"
+	"github.com/namely/go-sfdc/v3/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// renewingProvider issues tokens that are valid for the validity, and fails
+// the calls in failures.
+type renewingProvider struct {
+	mu       sync.Mutex
+	validity time.Duration
+	failures map[int]bool
+	calls    []time.Time
+	expiries []time.Time
+}
+
+func (p *renewingProvider) Retrieve() (io.Reader, error) {
+	return nil, errors.New("token provider does not use the OAuth exchange")
+}
+
+func (p *renewingProvider) URL() string {
+	return ""
+}
+
+func (p *renewingProvider) Token(ctx context.Context) (credentials.Token, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	p.calls = append(p.calls, now)
+	if p.failures[len(p.calls)] {
+		return credentials.Token{}, errors.New("token endpoint unavailable")
+	}
+	p.expiries = append(p.expiries, now.Add(p.validity))
+	return credentials.Token{
+		AccessToken: fmt.Sprintf("token %d", len(p.calls)),
+		TokenType:   "Bearer",
+		InstanceURL: "https://na1.salesforce.com",
+		Expiry:      now.Add(p.validity),
+	}, nil
+}
+
+func (p *renewingProvider) numCalls() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.calls)
+}
+
+func (p *renewingProvider) waitCalls(t *testing.T, calls int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for p.numCalls() < calls {
+		if time.Now().After(deadline) {
+			t.Fatalf("token calls = %d, want %d", p.numCalls(), calls)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func openRenewing(t *testing.T, provider *renewingProvider, onRefresh func(error)) *Session {
+	t.Helper()
+	creds, err := credentials.NewCredentials(provider)
+	require.NoError(t, err)
+	session, err := Open(sfdc.Configuration{
+		Credentials: creds,
+		Client: mockHTTPClient(func(req *http.Request) *http.Response {
+			if strings.HasSuffix(req.URL.Path, revokeEndpoint) == false {
+				t.Errorf("unexpected request %s", req.URL)
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader("")),
+				Header:     make(http.Header),
+			}
+		}),
+		Version:   45,
+		OnRefresh: onRefresh,
+	})
+	require.NoError(t, err)
+	return session
+}
+
+func autoRefreshDone(session *Session) chan struct{} {
+	session.mu.RLock()
+	defer session.mu.RUnlock()
+
+	return session.autoRefreshDone
+}
+
+func waitAutoRefresh(t *testing.T, done chan struct{}) {
+	t.Helper()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("auto refresh did not stop")
+	}
+}
+
+func Test_renewalDelay(t *testing.T) {
+	now := time.Now()
+	random := rand.New(rand.NewSource(1))
+	leadTime := time.Minute
+	for idx := 0; idx < 1000; idx++ {
+		delay := renewalDelay(now.Add(time.Hour), now, leadTime, random)
+		if delay > 59*time.Minute || delay < 59*time.Minute-leadTime/10 {
+			t.Fatalf("renewalDelay() = %v, want within a tenth of the lead time before %v", delay, 59*time.Minute)
+		}
+	}
+	assert.Equal(t, time.Duration(0), renewalDelay(now.Add(30*time.Second), now, leadTime, random))
+	assert.Equal(t, time.Duration(0), renewalDelay(now.Add(-time.Hour), now, leadTime, random))
+	assert.Equal(t, time.Hour, renewalDelay(now.Add(time.Hour), now, 0, random))
+}
+
+func TestSession_StartAutoRefresh(t *testing.T) {
+	provider := &renewingProvider{validity: 200 * time.Millisecond}
+	session := openRenewing(t, provider, nil)
+	leadTime := 100 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, session.StartAutoRefresh(ctx, leadTime))
+	assert.Error(t, session.StartAutoRefresh(ctx, leadTime))
+
+	provider.waitCalls(t, 3)
+	done := autoRefreshDone(session)
+	cancel()
+	waitAutoRefresh(t, done)
+
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+	for idx := 1; idx < 3; idx++ {
+		due := provider.expiries[idx-1].Add(-leadTime)
+		renewed := provider.calls[idx]
+		if renewed.Before(due.Add(-leadTime/10)) || renewed.After(provider.expiries[idx-1]) {
+			t.Errorf("renewal %d at %v, want near %v", idx, renewed.Sub(due), due)
+		}
+	}
+	assert.Equal(t, "token 3", session.Token().AccessToken)
+}
+
+func TestSession_StartAutoRefresh_failure(t *testing.T) {
+	policy := autoRefreshPolicy
+	autoRefreshPolicy.InitialInterval = 20 * time.Millisecond
+	defer func() { autoRefreshPolicy = policy }()
+
+	var mu sync.Mutex
+	var refreshErrs []error
+	provider := &renewingProvider{
+		validity: 200 * time.Millisecond,
+		failures: map[int]bool{2: true},
+	}
+	session := openRenewing(t, provider, func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		refreshErrs = append(refreshErrs, err)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, session.StartAutoRefresh(ctx, 100*time.Millisecond))
+
+	provider.waitCalls(t, 2)
+	assert.Equal(t, "token 1", session.Token().AccessToken)
+	provider.waitCalls(t, 3)
+	done := autoRefreshDone(session)
+	cancel()
+	waitAutoRefresh(t, done)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.True(t, len(refreshErrs) >= 3)
+	assert.NoError(t, refreshErrs[0])
+	assert.Error(t, refreshErrs[1])
+	assert.NoError(t, refreshErrs[2])
+	assert.Equal(t, "token 3", session.Token().AccessToken)
+}
+
+func TestSession_StartAutoRefresh_forceRefresh(t *testing.T) {
+	provider := &renewingProvider{validity: 200 * time.Millisecond}
+	session := openRenewing(t, provider, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, session.StartAutoRefresh(ctx, 100*time.Millisecond))
+
+	// the manual refresh reschedules the renewal from the new expiry
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, session.ForceRefresh(ctx))
+	provider.waitCalls(t, 3)
+	done := autoRefreshDone(session)
+	cancel()
+	waitAutoRefresh(t, done)
+
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+	if renewed := provider.calls[2]; renewed.Before(provider.expiries[1].Add(-110 * time.Millisecond)) {
+		t.Errorf("renewal at %v, want after the forced refresh's expiry less the lead time", renewed.Sub(provider.calls[1]))
+	}
+}
+
+func TestSession_StartAutoRefresh_close(t *testing.T) {
+	provider := &renewingProvider{validity: time.Hour}
+	session := openRenewing(t, provider, nil)
+
+	require.NoError(t, session.StartAutoRefresh(context.Background(), time.Minute))
+	done := autoRefreshDone(session)
+	require.NoError(t, session.Close(context.Background()))
+	waitAutoRefresh(t, done)
+	assert.Equal(t, 1, provider.numCalls())
+	assert.Equal(t, ErrSessionClosed, session.StartAutoRefresh(context.Background(), time.Minute))
+}
+
+func TestSession_StartAutoRefresh_negativeLeadTime(t *testing.T) {
+	provider := &renewingProvider{validity: time.Hour}
+	session := openRenewing(t, provider, nil)
+
+	assert.Error(t, session.StartAutoRefresh(context.Background(), -time.Second))
+}
@@ -0,0 +1,30 @@
+package session
+
+import (
+	"context"
+
+	"github.com/namely/go-sfdc/v3"
+)
+
+// Capabilities returns the capabilities of the session's org and user.  They
+// are probed with sfdc.Capabilities the first time and kept for the session,
+// so the checks of the resources do not probe again.  A failed probe is not
+// kept.  A context with another API version is probed every time, since the
+// capabilities depend on the version.
+func (s *Session) Capabilities(ctx context.Context) (sfdc.CapabilitySet, error) {
+	if version, ok := sfdc.APIVersion(ctx); ok && version != s.Version() {
+		return sfdc.Capabilities(ctx, s)
+	}
+
+	s.capabilitiesMu.Lock()
+	defer s.capabilitiesMu.Unlock()
+	if s.capabilities != nil {
+		return *s.capabilities, nil
+	}
+	set, err := sfdc.Capabilities(ctx, s)
+	if err != nil {
+		return sfdc.CapabilitySet{}, err
+	}
+	s.capabilities = &set
+	return set, nil
+}
@@ -0,0 +1,75 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/namely/go-sfdc/v3"
+)
+
+func TestSession_Capabilities(t *testing.T) {
+	var requests []string
+	fail := true
+	session := &Session{
+		response: &sessionPasswordResponse{
+			InstanceURL: "https://test.salesforce.com",
+			AccessToken: "token",
+			TokenType:   "Bearer",
+		},
+		config: sfdc.Configuration{
+			Version: 58,
+			Client: mockHTTPClient(func(req *http.Request) *http.Response {
+				requests = append(requests, req.URL.Path+" "+req.Header.Get("Authorization"))
+				body := `[{"version": "58.0"}]`
+				status := http.StatusOK
+				if req.URL.Path != "/services/data/" {
+					body = `{"records": [{"PermissionsBulkApiHardDelete": false}]}`
+					if fail {
+						status = http.StatusInternalServerError
+						body = `[{"errorCode": "UNKNOWN_EXCEPTION", "message": "try again"}]`
+					}
+				}
+				return &http.Response{
+					StatusCode: status,
+					Status:     http.StatusText(status),
+					Body:       io.NopCloser(strings.NewReader(body)),
+					Header:     make(http.Header),
+				}
+			}),
+		},
+	}
+
+	if _, err := session.Capabilities(context.Background()); err == nil {
+		t.Fatal("Session.Capabilities() error = nil, want the probe's error")
+	}
+	fail = false
+	for idx := 0; idx < 3; idx++ {
+		set, err := session.Capabilities(context.Background())
+		if err != nil {
+			t.Fatalf("Session.Capabilities() error = %v", err)
+		}
+		var notPermitted *sfdc.NotPermittedError
+		if err := set.Require(sfdc.CapabilityBulkV2Query, sfdc.CapabilityHardDelete); errors.As(err, &notPermitted) == false || notPermitted.Capability != sfdc.CapabilityHardDelete {
+			t.Errorf("CapabilitySet.Require() error = %v, want the hard delete capability", err)
+		}
+	}
+	if len(requests) != 4 {
+		t.Errorf("Session.Capabilities() requests = %v, want the failed probe and one kept probe", requests)
+	}
+	for _, request := range requests {
+		if strings.HasSuffix(request, " Bearer token") == false {
+			t.Errorf("Session.Capabilities() request = %s, want the authorization header", request)
+		}
+	}
+
+	if _, err := session.Capabilities(sfdc.WithAPIVersion(context.Background(), 50)); err != nil {
+		t.Fatalf("Session.Capabilities() error = %v", err)
+	}
+	if len(requests) != 6 {
+		t.Errorf("Session.Capabilities() requests = %d, want another version to be probed", len(requests))
+	}
+}
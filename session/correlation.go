@@ -0,0 +1,56 @@
+package session
+
+import (
+	"net/http"
+
+	"github.com/namely/go-sfdc/v3"
+)
+
+type correlatedTransport struct {
+	header string
+	base   http.RoundTripper
+}
+
+func correlatedClient(client *http.Client, header string) *http.Client {
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if header == "" {
+		header = sfdc.DefaultCorrelationHeader
+	}
+	correlated := *client
+	correlated.Transport = &correlatedTransport{
+		header: http.CanonicalHeaderKey(header),
+		base:   base,
+	}
+	return &correlated
+}
+
+// RoundTrip will send the request with the correlation ID of the request's
+// context, or a new one if the context does not have one.  The response's
+// request carries the correlation ID so the errors can include it.
+func (t *correlatedTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	id, ok := sfdc.CorrelationID(request.Context())
+	if ok == false {
+		id = sfdc.NewCorrelationID()
+	}
+
+	correlated := request.Clone(sfdc.WithCorrelationID(request.Context(), id))
+	if correlated.Header.Get(t.header) == "" {
+		if t.header == sfdc.CallOptionsHeader {
+			correlated.Header.Set(t.header, "client="+id)
+		} else {
+			correlated.Header.Set(t.header, id)
+		}
+	}
+
+	response, err := t.base.RoundTrip(correlated)
+	if err != nil {
+		return nil, err
+	}
+	if response.Request == nil {
+		response.Request = correlated
+	}
+	return response, nil
+}
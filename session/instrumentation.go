@@ -0,0 +1,73 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/namely/go-sfdc/v3"
+)
+
+// Instrumenter is the interface implemented by sessions that
+// instrument the API calls.
+type Instrumenter interface {
+	Instrumentation() sfdc.Instrumentation
+}
+
+// StartSpan will start a span for the operation using the session's
+// instrumentation.  The attributes function is only called when the session is
+// instrumented.  If it is not, the context is returned along with a no-op end
+// function.
+func StartSpan(ctx context.Context, formatter InstanceFormatter, op string, attrs func() map[string]interface{}) (context.Context, func(error)) {
+	instrumenter, ok := formatter.(Instrumenter)
+	if ok == false {
+		return ctx, noopEnd
+	}
+	instrumentation := instrumenter.Instrumentation()
+	if instrumentation == nil {
+		return ctx, noopEnd
+	}
+	var attributes map[string]interface{}
+	if attrs != nil {
+		attributes = attrs()
+	}
+	return instrumentation.StartSpan(ctx, op, attributes)
+}
+
+func noopEnd(error) {}
+
+type instrumentedTransport struct {
+	instrumentation sfdc.Instrumentation
+	base            http.RoundTripper
+}
+
+func instrumentedClient(client *http.Client, instrumentation sfdc.Instrumentation) *http.Client {
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	instrumented := *client
+	instrumented.Transport = &instrumentedTransport{
+		instrumentation: instrumentation,
+		base:            base,
+	}
+	return &instrumented
+}
+
+func (t *instrumentedTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	ctx, end := t.instrumentation.StartSpan(request.Context(), "http.request", map[string]interface{}{
+		"http.method": request.Method,
+		"http.path":   request.URL.Path,
+	})
+	response, err := t.base.RoundTrip(request.WithContext(ctx))
+	if err != nil {
+		end(err)
+		return nil, err
+	}
+	if response.StatusCode >= http.StatusBadRequest {
+		end(fmt.Errorf("http request: %s", response.Status))
+	} else {
+		end(nil)
+	}
+	return response, nil
+}
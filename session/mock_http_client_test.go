@@ -1,6 +1,10 @@
 package session
 
-import "net/http"
+import (
+	"io"
+	"net/http"
+	"strings"
+)
 
 type roundTripFunc func(request *http.Request) *http.Response
 
@@ -13,3 +17,20 @@ func mockHTTPClient(fn roundTripFunc) *http.Client {
 		Transport: fn,
 	}
 }
+
+// trackingBody is a response body that records whether it was closed.
+type trackingBody struct {
+	io.Reader
+	closed bool
+}
+
+func newTrackingBody(body string) *trackingBody {
+	return &trackingBody{
+		Reader: strings.NewReader(body),
+	}
+}
+
+func (b *trackingBody) Close() error {
+	b.closed = true
+	return nil
+}
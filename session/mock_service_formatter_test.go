@@ -0,0 +1,36 @@
+package session
+
+import (
+	"fmt"
+	"net/http"
+)
+
+type mockServiceFormatter struct {
+	url     string
+	version int
+	client  *http.Client
+}
+
+func (mock *mockServiceFormatter) InstanceURL() string {
+	return mock.url
+}
+
+func (mock *mockServiceFormatter) ServiceURL() string {
+	return fmt.Sprintf("%s/services/data/v%d.0", mock.url, mock.version)
+}
+
+func (mock *mockServiceFormatter) Version() int {
+	return mock.version
+}
+
+func (mock *mockServiceFormatter) AuthorizationHeader(request *http.Request) {
+	request.Header.Set("Authorization", "Bearer token")
+}
+
+func (mock *mockServiceFormatter) Client() *http.Client {
+	return mock.client
+}
+
+func (mock *mockServiceFormatter) Refresh() error {
+	return nil
+}
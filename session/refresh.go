@@ -0,0 +1,92 @@
+package session
+
+import (
+	"net/http"
+
+	"github.com/namely/go-sfdc/v3"
+)
+
+// LazyRefresher is the interface implemented by sessions that can be
+// refreshed when they are used, instead of when the resources are created.
+type LazyRefresher interface {
+	LazyRefresh() bool
+}
+
+// RefreshResource will refresh the session when a resource is created.  A
+// LazyRefresher session that is refreshed lazily is not refreshed, so the
+// resources can be created per request.
+func RefreshResource(formatter InstanceFormatter) error {
+	if lazy, ok := formatter.(LazyRefresher); ok && lazy.LazyRefresh() {
+		return nil
+	}
+	return formatter.Refresh()
+}
+
+// LazyRefresh returns whether the session is refreshed by its client, instead
+// of when the resources are created.
+func (s *Session) LazyRefresh() bool {
+	return s.config.LazyRefresh
+}
+
+type refreshTransport struct {
+	session *Session
+	base    http.RoundTripper
+}
+
+func refreshClient(client *http.Client, session *Session) *http.Client {
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	refreshed := *client
+	refreshed.Transport = &refreshTransport{
+		session: session,
+		base:    base,
+	}
+	return &refreshed
+}
+
+// RoundTrip will refresh an expired session, or one within the refresh window,
+// before the request is sent, and
+// will force a refresh and send the request again when it is rejected with a
+// 401.  The request is sent again only when its body can be read again.  The
+// requests without an authorization header are sent as is.
+func (t *refreshTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	if request.Header.Get("Authorization") == "" {
+		return t.base.RoundTrip(request)
+	}
+	if t.session.isDue() {
+		if err := t.session.refreshDue(request.Context()); err != nil {
+			return nil, err
+		}
+		request = t.authorize(request)
+	}
+
+	response, err := t.base.RoundTrip(request)
+	if err != nil || response.StatusCode != http.StatusUnauthorized {
+		return response, err
+	}
+	if request.Body != nil && request.GetBody == nil {
+		return response, nil
+	}
+	if err := t.session.refresh(request.Context()); err != nil {
+		return response, nil
+	}
+	retry := t.authorize(request)
+	if request.GetBody != nil {
+		body, err := request.GetBody()
+		if err != nil {
+			return response, nil
+		}
+		retry.Body = body
+	}
+	sfdc.CloseResponse(response)
+	return t.base.RoundTrip(retry)
+}
+
+// authorize returns the request with the session's authorization header.
+func (t *refreshTransport) authorize(request *http.Request) *http.Request {
+	authorized := request.Clone(request.Context())
+	t.session.AuthorizationHeader(authorized)
+	return authorized
+}
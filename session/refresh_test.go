@@ -0,0 +1,127 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/namely/go-sfdc/v3"
+	"github.com/namely/go-sfdc/v3/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockOrg issues a new token for each session request and only accepts the
+// latest token.
+type mockOrg struct {
+	tokens int
+	bodies []string
+}
+
+func (org *mockOrg) roundTrip(req *http.Request) *http.Response {
+	if strings.HasSuffix(req.URL.Path, oauthEndpoint) {
+		org.tokens++
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(fmt.Sprintf(`{"access_token": "token %d", "instance_url": "https://na1.salesforce.com", "token_type": "Bearer"}`, org.tokens))),
+			Header:     make(http.Header),
+		}
+	}
+	if req.Body != nil {
+		body, _ := io.ReadAll(req.Body)
+		org.bodies = append(org.bodies, string(body))
+	}
+	if req.Header.Get("Authorization") != fmt.Sprintf("Bearer token %d", org.tokens) {
+		return &http.Response{
+			StatusCode: http.StatusUnauthorized,
+			Status:     "401 Unauthorized",
+			Body:       io.NopCloser(strings.NewReader(`[{"errorCode": "INVALID_SESSION_ID", "message": "Session expired or invalid"}]`)),
+			Header:     make(http.Header),
+		}
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(`{}`)),
+		Header:     make(http.Header),
+	}
+}
+
+func (org *mockOrg) open(t *testing.T, lazy bool) *Session {
+	t.Helper()
+	session, err := Open(sfdc.Configuration{
+		Credentials: testNewPasswordCredentials(t, credentials.PasswordCredentials{
+			URL:          "http://test.password.session",
+			Username:     "myusername",
+			Password:     "12345",
+			ClientID:     "some client id",
+			ClientSecret: "shhhh its a secret",
+		}),
+		Client:          mockHTTPClient(org.roundTrip),
+		Version:         45,
+		SessionDuration: time.Hour,
+		LazyRefresh:     lazy,
+	})
+	require.NoError(t, err)
+	return session
+}
+
+func (org *mockOrg) call(t *testing.T, session *Session, body string) *http.Response {
+	t.Helper()
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	request, err := http.NewRequest(http.MethodPost, session.ServiceURL()+"/sobjects/Account", reader)
+	require.NoError(t, err)
+	session.AuthorizationHeader(request)
+	response, err := session.Client().Do(request)
+	require.NoError(t, err)
+	response.Body.Close()
+	return response
+}
+
+func TestRefreshResource(t *testing.T) {
+	org := &mockOrg{}
+	eager := org.open(t, false)
+	eager.expiresAt = time.Now().Add(-time.Minute).UTC()
+	require.NoError(t, RefreshResource(eager))
+	assert.Equal(t, 2, org.tokens, "the session is refreshed when the resource is created")
+
+	org = &mockOrg{}
+	lazy := org.open(t, true)
+	lazy.expiresAt = time.Now().Add(-time.Minute).UTC()
+	require.NoError(t, RefreshResource(lazy))
+	assert.Equal(t, 1, org.tokens, "the lazy session is not refreshed when the resource is created")
+}
+
+func TestSession_lazyRefresh(t *testing.T) {
+	org := &mockOrg{}
+	session := org.open(t, true)
+	session.expiresAt = time.Now().Add(-time.Minute).UTC()
+
+	response := org.call(t, session, "")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, 2, org.tokens, "the expired session is refreshed before the request")
+
+	// the org revokes the token before the session expires
+	org.tokens++
+	response = org.call(t, session, `{"Name": "Acme"}`)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, 4, org.tokens, "the session is refreshed after a 401")
+	assert.Equal(t, []string{`{"Name": "Acme"}`, `{"Name": "Acme"}`}, org.bodies, "the request is sent again with its body")
+}
+
+func TestSession_lazyRefresh_default(t *testing.T) {
+	org := &mockOrg{}
+	session := org.open(t, false)
+	org.tokens++
+
+	response := org.call(t, session, "")
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	assert.Equal(t, 2, org.tokens, "the session is not refreshed without lazy refresh")
+	assert.NoError(t, session.ForceRefresh(context.Background()))
+}
@@ -0,0 +1,142 @@
+package session
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/namely/go-sfdc/v3"
+)
+
+// defaultRetryableStatuses are the statuses that Salesforce answers with when
+// the request can succeed later.
+var defaultRetryableStatuses = []int{
+	http.StatusTooManyRequests,
+	http.StatusServiceUnavailable,
+}
+
+type retryTransport struct {
+	maxRetries int
+	statuses   map[int]bool
+	policy     sfdc.RetryPolicy
+	base       http.RoundTripper
+}
+
+func retryClient(client *http.Client, maxRetries int, statuses []int, retryPolicy *sfdc.RetryPolicy) *http.Client {
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if len(statuses) == 0 {
+		statuses = defaultRetryableStatuses
+	}
+	retryable := make(map[int]bool, len(statuses))
+	for _, status := range statuses {
+		retryable[status] = true
+	}
+	// the retries are limited by the maximum, not the policy
+	policy := sfdc.NewRetryPolicy()
+	if retryPolicy != nil {
+		policy = *retryPolicy
+	}
+	policy.MaxAttempts = 0
+	policy.MaxElapsedTime = 0
+
+	retried := *client
+	retried.Transport = &retryTransport{
+		maxRetries: maxRetries,
+		statuses:   retryable,
+		policy:     policy,
+		base:       base,
+	}
+	return &retried
+}
+
+// RoundTrip will send the request again when it is answered with a retryable
+// status, up to the maximum number of retries.  The wait before a retry is the
+// Retry-After of the response, capped at the retry policy's MaxInterval, or
+// the policy's back off when there is none.  The last response is returned,
+// so its error can be inspected.
+func (t *retryTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	if t.retryable(request) == false {
+		return t.base.RoundTrip(request)
+	}
+
+	backOff := t.policy.BackOff()
+	for retries := 0; ; retries++ {
+		response, err := t.base.RoundTrip(request)
+		if err != nil || t.statuses[response.StatusCode] == false || retries >= t.maxRetries {
+			return response, err
+		}
+
+		wait, ok := retryAfter(response.Header.Get("Retry-After"), time.Now())
+		if ok && t.policy.MaxInterval > 0 && wait > t.policy.MaxInterval {
+			wait = t.policy.MaxInterval
+		}
+		if ok == false {
+			wait = backOff.NextBackOff()
+			if wait == sfdc.StopBackOff {
+				return response, nil
+			}
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-request.Context().Done():
+			timer.Stop()
+			return response, nil
+		case <-timer.C:
+		}
+
+		if request.GetBody != nil {
+			body, err := request.GetBody()
+			if err != nil {
+				return response, nil
+			}
+			retry := request.Clone(request.Context())
+			retry.Body = body
+			request = retry
+		}
+		sfdc.CloseResponse(response)
+	}
+}
+
+// retryable returns whether the request can be sent again.  The requests that
+// are not idempotent are retried only when their context allows it, and the
+// requests with a body only when the body can be read again.
+func (t *retryTransport) retryable(request *http.Request) bool {
+	if t.maxRetries <= 0 {
+		return false
+	}
+	if request.Body != nil && request.Body != http.NoBody && request.GetBody == nil {
+		return false
+	}
+	switch request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodDelete:
+		return true
+	default:
+		return sfdc.RequestRetry(request.Context())
+	}
+}
+
+// retryAfter returns the wait of the Retry-After header, which is a number of
+// seconds or a date.
+func retryAfter(header string, now time.Time) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	date, err := http.ParseTime(header)
+	if err != nil {
+		return 0, false
+	}
+	wait := date.Sub(now)
+	if wait < 0 {
+		wait = 0
+	}
+	return wait, true
+}
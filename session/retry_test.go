@@ -0,0 +1,231 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/namely/go-sfdc/v3"
+	"github.com/namely/go-sfdc/v3/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// limitedOrg answers the API requests with the statuses in order, and then
+// with 200.
+type limitedOrg struct {
+	statuses   []int
+	retryAfter string
+	bodies     []string
+}
+
+func (org *limitedOrg) roundTrip(req *http.Request) *http.Response {
+	if strings.HasSuffix(req.URL.Path, oauthEndpoint) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"access_token": "token", "instance_url": "https://na1.salesforce.com", "token_type": "Bearer"}`)),
+			Header:     make(http.Header),
+		}
+	}
+	body := ""
+	if req.Body != nil {
+		data, _ := io.ReadAll(req.Body)
+		body = string(data)
+	}
+	org.bodies = append(org.bodies, body)
+	if len(org.bodies) > len(org.statuses) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{}`)),
+			Header:     make(http.Header),
+		}
+	}
+	header := make(http.Header)
+	header.Set("Retry-After", org.retryAfter)
+	return &http.Response{
+		StatusCode: org.statuses[len(org.bodies)-1],
+		Status:     "429 Too Many Requests",
+		Body:       io.NopCloser(strings.NewReader(`[{"errorCode": "REQUEST_LIMIT_EXCEEDED", "message": "TotalRequests Limit exceeded."}]`)),
+		Header:     header,
+	}
+}
+
+func (org *limitedOrg) open(t *testing.T, maxRetries int) *Session {
+	t.Helper()
+	session, err := Open(sfdc.Configuration{
+		Credentials: testNewPasswordCredentials(t, credentials.PasswordCredentials{
+			URL:          "http://test.password.session",
+			Username:     "myusername",
+			Password:     "12345",
+			ClientID:     "some client id",
+			ClientSecret: "shhhh its a secret",
+		}),
+		Client:     mockHTTPClient(org.roundTrip),
+		Version:    45,
+		MaxRetries: maxRetries,
+	})
+	require.NoError(t, err)
+	return session
+}
+
+func (org *limitedOrg) call(t *testing.T, ctx context.Context, session *Session, method, body string) *http.Response {
+	t.Helper()
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	request, err := http.NewRequestWithContext(ctx, method, session.ServiceURL()+"/sobjects/Account", reader)
+	require.NoError(t, err)
+	session.AuthorizationHeader(request)
+	response, err := session.Client().Do(request)
+	require.NoError(t, err)
+	return response
+}
+
+func TestSession_retry(t *testing.T) {
+	org := &limitedOrg{
+		statuses:   []int{http.StatusTooManyRequests, http.StatusServiceUnavailable},
+		retryAfter: "0",
+	}
+	session := org.open(t, 2)
+
+	response := org.call(t, context.Background(), session, http.MethodGet, "")
+	sfdc.CloseResponse(response)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Len(t, org.bodies, 3, "the GET request is retried")
+}
+
+func TestSession_retry_exhausted(t *testing.T) {
+	org := &limitedOrg{
+		statuses:   []int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusTooManyRequests},
+		retryAfter: "0",
+	}
+	session := org.open(t, 2)
+
+	response := org.call(t, context.Background(), session, http.MethodDelete, "")
+	defer sfdc.CloseResponse(response)
+	assert.Equal(t, http.StatusTooManyRequests, response.StatusCode)
+	assert.Len(t, org.bodies, 3, "the DELETE request is retried twice")
+
+	var sfdcErrs sfdc.Errors
+	require.True(t, errors.As(sfdc.HandleError(response), &sfdcErrs), "the last response has the Salesforce errors")
+	assert.Equal(t, "REQUEST_LIMIT_EXCEEDED", sfdcErrs[0].ErrorCode)
+}
+
+func TestSession_retry_notIdempotent(t *testing.T) {
+	org := &limitedOrg{
+		statuses:   []int{http.StatusServiceUnavailable},
+		retryAfter: "0",
+	}
+	session := org.open(t, 2)
+
+	response := org.call(t, context.Background(), session, http.MethodPost, `{"Name": "Acme"}`)
+	sfdc.CloseResponse(response)
+	assert.Equal(t, http.StatusServiceUnavailable, response.StatusCode)
+	assert.Len(t, org.bodies, 1, "the POST request is not retried")
+
+	org.bodies = nil
+	response = org.call(t, sfdc.WithRequestRetry(context.Background()), session, http.MethodPost, `{"Name": "Acme"}`)
+	sfdc.CloseResponse(response)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, []string{`{"Name": "Acme"}`, `{"Name": "Acme"}`}, org.bodies, "the request is sent again with its body")
+}
+
+func TestSession_retry_default(t *testing.T) {
+	org := &limitedOrg{
+		statuses:   []int{http.StatusTooManyRequests},
+		retryAfter: "0",
+	}
+	session := org.open(t, 0)
+
+	response := org.call(t, context.Background(), session, http.MethodGet, "")
+	sfdc.CloseResponse(response)
+	assert.Equal(t, http.StatusTooManyRequests, response.StatusCode)
+	assert.Len(t, org.bodies, 1, "the request is not retried without retries")
+}
+
+func TestRetryTransport_backOff(t *testing.T) {
+	org := &limitedOrg{
+		statuses: []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable},
+	}
+	client := retryClient(mockHTTPClient(org.roundTrip), 3, nil, nil)
+	transport := client.Transport.(*retryTransport)
+	transport.policy.InitialInterval = time.Millisecond
+	transport.policy.Jitter = 0
+
+	response, err := client.Get("https://na1.salesforce.com/services/data/v45.0/limits")
+	require.NoError(t, err)
+	sfdc.CloseResponse(response)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Len(t, org.bodies, 3, "the request is retried with the back off without a Retry-After")
+
+	org.bodies = nil
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	transport.policy.InitialInterval = time.Hour
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://na1.salesforce.com/services/data/v45.0/limits", nil)
+	require.NoError(t, err)
+	response, err = transport.RoundTrip(request)
+	require.NoError(t, err)
+	sfdc.CloseResponse(response)
+	assert.Equal(t, http.StatusServiceUnavailable, response.StatusCode, "the wait is interrupted by the context")
+}
+
+func TestSession_retry_policy(t *testing.T) {
+	org := &limitedOrg{
+		statuses:   []int{http.StatusTooManyRequests},
+		retryAfter: "3600",
+	}
+	policy := sfdc.NewRetryPolicy()
+	policy.MaxInterval = time.Millisecond
+	session, err := Open(sfdc.Configuration{
+		Credentials: testNewPasswordCredentials(t, credentials.PasswordCredentials{
+			URL:          "http://test.password.session",
+			Username:     "myusername",
+			Password:     "12345",
+			ClientID:     "some client id",
+			ClientSecret: "shhhh its a secret",
+		}),
+		Client:      mockHTTPClient(org.roundTrip),
+		Version:     45,
+		MaxRetries:  1,
+		RetryPolicy: &policy,
+	})
+	require.NoError(t, err)
+
+	start := time.Now()
+	response := org.call(t, context.Background(), session, http.MethodGet, "")
+	sfdc.CloseResponse(response)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Len(t, org.bodies, 2, "the GET request is retried")
+	assert.Less(t, int64(time.Since(start)), int64(time.Minute), "the Retry-After is capped at the policy's MaxInterval")
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name   string
+		header string
+		want   time.Duration
+		wantOk bool
+	}{
+		{name: "empty"},
+		{name: "seconds", header: "120", want: 2 * time.Minute, wantOk: true},
+		{name: "negative", header: "-1"},
+		{name: "date", header: "Tue, 02 Jan 2024 03:04:35 GMT", want: 30 * time.Second, wantOk: true},
+		{name: "past date", header: "Tue, 02 Jan 2024 03:00:00 GMT", want: 0, wantOk: true},
+		{name: "invalid", header: "soon"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := retryAfter(tt.header, now)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("retryAfter() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
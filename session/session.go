@@ -2,9 +2,12 @@
 package session
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 	"sync"
 	"time"
 
@@ -18,13 +21,31 @@ import (
 type Session struct {
 	// thread safe:
 	config sfdc.Configuration
+	client *http.Client
 
 	// thread unsafe:
-	mu        sync.RWMutex
-	response  *sessionPasswordResponse
-	expiresAt time.Time
+	mu          sync.RWMutex
+	credentials *credentials.Credentials
+	response    *sessionPasswordResponse
+	expiresAt   time.Time
+	closed      bool
+	// the auto refresh is stopped when the session is closed, and is
+	// rescheduled when the session is renewed
+	stopped         chan struct{}
+	renewed         chan struct{}
+	autoRefreshDone chan struct{}
+	// the refresh in flight, which the concurrent refreshes share
+	refreshing *refreshCall
+
+	// the probed capabilities have their own lock, so the probe does not
+	// hold the session's lock
+	capabilitiesMu sync.Mutex
+	capabilities   *sfdc.CapabilitySet
 }
 
+// ErrSessionClosed is returned by the session's calls after it is closed.
+var ErrSessionClosed = errors.New("session: session is closed")
+
 // Clienter interface provides the HTTP client used by the
 // the resources.
 type Clienter interface {
@@ -59,6 +80,19 @@ type ServiceFormatter interface {
 	ServiceURL() string
 }
 
+// Identity is the identity of the session's user.
+//
+// URL is the identity URL returned when the session was opened.
+//
+// OrganizationID is the Salesforce ID of the org.
+//
+// UserID is the Salesforce ID of the user.
+type Identity struct {
+	URL            string
+	OrganizationID string
+	UserID         string
+}
+
 type sessionPasswordResponse struct {
 	AccessToken string `json:"access_token"`
 	InstanceURL string `json:"instance_url"`
@@ -66,10 +100,13 @@ type sessionPasswordResponse struct {
 	TokenType   string `json:"token_type"`
 	IssuedAt    string `json:"issued_at"`
 	Signature   string `json:"signature"`
+	// RefreshToken is only kept until it is given to the credentials.
+	RefreshToken string `json:"refresh_token"`
 }
 
 const (
 	oauthEndpoint          = "/services/oauth2/token"
+	revokeEndpoint         = "/services/oauth2/revoke"
 	defaultSessionDuration = 24 * time.Hour
 )
 
@@ -88,12 +125,28 @@ func Open(config sfdc.Configuration) (*Session, error) {
 	if config.SessionDuration == 0 {
 		config.SessionDuration = defaultSessionDuration
 	}
+	if config.RefreshWindow < 0 {
+		return nil, errors.New("session: configuration refresh window can not be negative")
+	}
+	config.Client = userAgentClient(config.Client, config.UserAgentSuffix)
 
 	session := &Session{
-		config: config,
+		config:      config,
+		credentials: config.Credentials,
+	}
+	client := config.Client
+	if config.MaxRetries > 0 {
+		client = retryClient(client, config.MaxRetries, config.RetryableStatuses, config.RetryPolicy)
+	}
+	if config.LazyRefresh {
+		client = refreshClient(client, session)
+	}
+	session.client = correlatedClient(client, config.CorrelationHeader)
+	if config.Instrumentation != nil {
+		session.client = instrumentedClient(session.client, config.Instrumentation)
 	}
 
-	err := session.refresh()
+	err := session.refresh(context.Background())
 	if err != nil {
 		return nil, err
 	}
@@ -101,7 +154,7 @@ func Open(config sfdc.Configuration) (*Session, error) {
 	return session, nil
 }
 
-func passwordSessionRequest(creds *credentials.Credentials) (*http.Request, error) {
+func passwordSessionRequest(ctx context.Context, creds *credentials.Credentials) (*http.Request, error) {
 	oauthURL := creds.URL() + oauthEndpoint
 
 	body, err := creds.Retrieve()
@@ -109,7 +162,7 @@ func passwordSessionRequest(creds *credentials.Credentials) (*http.Request, erro
 		return nil, err
 	}
 
-	request, err := http.NewRequest(http.MethodPost, oauthURL, body)
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, oauthURL, body)
 	if err != nil {
 		return nil, err
 	}
@@ -124,7 +177,7 @@ func passwordSessionResponse(request *http.Request, client *http.Client) (*sessi
 	if err != nil {
 		return nil, err
 	}
-	defer response.Body.Close()
+	defer sfdc.CloseResponse(response)
 
 	if response.StatusCode != http.StatusOK {
 		return nil, errors.Wrap(sfdc.HandleError(response), "session response")
@@ -163,29 +216,193 @@ func (s *Session) ServiceURL() string {
 }
 
 // AuthorizationHeader will add the authorization to the
-// HTTP request's header.
+// HTTP request's header.  A closed session does not add the authorization.
 func (s *Session) AuthorizationHeader(req *http.Request) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
+	if s.closed {
+		req.Header.Del("Authorization")
+		return
+	}
 	auth := s.response.TokenType + " " + s.response.AccessToken
-	req.Header.Add("Authorization", auth)
+	req.Header.Set("Authorization", auth)
+}
+
+// Identity will return the identity of the session's user from the
+// identity URL, which has the form https://login.salesforce.com/id/orgID/userID.
+func (s *Session) Identity() (Identity, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return Identity{}, ErrSessionClosed
+	}
+	identityURL, err := url.Parse(s.response.ID)
+	if err != nil {
+		return Identity{}, errors.Wrap(err, "session identity")
+	}
+	segments := strings.Split(strings.Trim(identityURL.Path, "/"), "/")
+	if len(segments) < 3 || segments[len(segments)-3] != "id" {
+		return Identity{}, fmt.Errorf("session identity: %s is not an identity URL", s.response.ID)
+	}
+
+	return Identity{
+		URL:            s.response.ID,
+		OrganizationID: segments[len(segments)-2],
+		UserID:         segments[len(segments)-1],
+	}, nil
+}
+
+// Token returns the session's token and when it expires.  A closed session's
+// token is empty.
+func (s *Session) Token() credentials.Token {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return credentials.Token{}
+	}
+	return credentials.Token{
+		AccessToken: s.response.AccessToken,
+		TokenType:   s.response.TokenType,
+		InstanceURL: s.response.InstanceURL,
+		ID:          s.response.ID,
+		Expiry:      s.expiresAt,
+	}
 }
 
-// Client returns the HTTP client to be used in APIs calls.
+// Client returns the HTTP client to be used in APIs calls.  Each request made
+// with the client is sent with a correlation ID and the go-sfdc User-Agent and,
+// if the session is instrumented, is traced.
 func (s *Session) Client() *http.Client {
+	if s.client != nil {
+		return s.client
+	}
 	return s.config.Client
 }
 
-// Refresh check if session is expired and refresh it if needed.
+// Instrumentation returns the instrumentation used to trace the API calls.
+func (s *Session) Instrumentation() sfdc.Instrumentation {
+	return s.config.Instrumentation
+}
+
+// Refresh check if session is expired, or within the configuration's refresh
+// window, and refresh it if needed.  The concurrent calls that find the
+// session due share a single refresh.  A closed session returns
+// ErrSessionClosed.
 func (s *Session) Refresh() error {
-	if s.isExpired() {
-		return s.refresh()
+	if s.isClosed() {
+		return ErrSessionClosed
+	}
+	if s.isDue() {
+		return s.refreshDue(context.Background())
+	}
+
+	return nil
+}
+
+// UpdateCredentials will replace the credentials used to refresh the session.
+// The session is not refreshed until it expires or ForceRefresh is called, so
+// the current token is used until then.
+func (s *Session) UpdateCredentials(creds *credentials.Credentials) error {
+	if creds == nil {
+		return errors.New("session: credentials can not be nil")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrSessionClosed
+	}
+	s.credentials = creds
+	return nil
+}
+
+// ForceRefresh will refresh the session even if it has not expired.  If the
+// refresh fails, the current token is kept until it expires.
+func (s *Session) ForceRefresh(ctx context.Context) error {
+	return s.refresh(ctx)
+}
+
+// Close will revoke the session's access token and close the session.  The
+// session's calls return ErrSessionClosed afterwards, and its token and the
+// secrets of the credentials that are a credentials.Zeroizer are wiped.  The
+// session is closed even if the revoke fails, and closing a closed session
+// does nothing.
+func (s *Session) Close(ctx context.Context) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	if s.stopped != nil {
+		close(s.stopped)
+	}
+	call := s.refreshing
+	s.mu.Unlock()
+
+	// the credentials are wiped once the refresh in flight is done with them
+	if call != nil {
+		<-call.done
+	}
+
+	s.mu.Lock()
+	var token, instanceURL string
+	if s.response != nil {
+		token = s.response.AccessToken
+		instanceURL = s.response.InstanceURL
+		// the URLs are kept for the resources, which are not closed
+		s.response = &sessionPasswordResponse{
+			InstanceURL: s.response.InstanceURL,
+			ID:          s.response.ID,
+			TokenType:   s.response.TokenType,
+		}
+	}
+	if s.credentials != nil {
+		s.credentials.Zeroize()
 	}
+	if s.config.Credentials != nil && s.config.Credentials != s.credentials {
+		s.config.Credentials.Zeroize()
+	}
+	s.mu.Unlock()
+
+	if token == "" {
+		return nil
+	}
+	return s.revoke(ctx, instanceURL, token)
+}
 
+func (s *Session) revoke(ctx context.Context, instanceURL, token string) error {
+	form := url.Values{}
+	form.Add("token", token)
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, instanceURL+revokeEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+	response, err := s.config.Client.Do(request)
+	if err != nil {
+		return errors.Wrap(err, "session revoke")
+	}
+	defer sfdc.CloseResponse(response)
+
+	if response.StatusCode != http.StatusOK {
+		return errors.Wrap(sfdc.HandleError(response), "session revoke")
+	}
 	return nil
 }
 
+func (s *Session) isClosed() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.closed
+}
+
 func (s *Session) isExpired() bool {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -193,23 +410,199 @@ func (s *Session) isExpired() bool {
 	return s.expiresAt.Before(time.Now().UTC())
 }
 
-// refresh the session
-func (s *Session) refresh() error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+// isDue returns whether the session is expired or within the refresh window.
+func (s *Session) isDue() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.isDueLocked(time.Now().UTC())
+}
 
-	req, err := passwordSessionRequest(s.config.Credentials)
+func (s *Session) isDueLocked(now time.Time) bool {
+	return s.expiresAt.Before(now.Add(s.config.RefreshWindow))
+}
+
+// refreshOutcome is the outcome of a session refresh.
+//
+// skipped is whether the session was not due anymore, like when a concurrent
+// call refreshed it.
+//
+// shared is whether the refresh was done by a concurrent call, which has
+// already called the hooks.
+//
+// valid is whether the session was not expired yet, so it can still be used
+// when the refresh of a due session fails.
+type refreshOutcome struct {
+	oldURL       string
+	newURL       string
+	refreshToken string
+	skipped      bool
+	shared       bool
+	valid        bool
+}
+
+// refreshCall is a refresh in flight.  The concurrent refreshes wait for it to
+// be done and share its error, instead of exchanging a token each.
+type refreshCall struct {
+	done  chan struct{}
+	creds *credentials.Credentials
+	err   error
+}
+
+// refresh the session.  The refresh hook is called with the outcome, and the
+// instance change hook is called after the new session is in place, so the
+// hook can use the new instance URL.
+func (s *Session) refresh(ctx context.Context) error {
+	return s.renew(ctx, false)
+}
+
+// refreshDue refreshes the session if it is still due, so the calls that
+// were waiting for a concurrent refresh do not refresh it again.  A failed refresh of a session that is not expired yet is
+// only reported to the refresh hook.
+func (s *Session) refreshDue(ctx context.Context) error {
+	return s.renew(ctx, true)
+}
+
+func (s *Session) renew(ctx context.Context, due bool) error {
+	outcome, err := s.refreshShared(ctx, due)
+	if outcome.skipped {
+		return nil
+	}
+	if outcome.shared {
+		if err != nil && outcome.valid == false {
+			return err
+		}
+		return nil
+	}
+	if s.config.OnRefresh != nil {
+		s.config.OnRefresh(err)
+	}
 	if err != nil {
+		if outcome.valid {
+			return nil
+		}
 		return err
 	}
 
-	resp, err := passwordSessionResponse(req, s.config.Client)
-	if err != nil {
-		return err
+	if s.config.OnInstanceChange != nil && outcome.oldURL != "" && outcome.oldURL != outcome.newURL {
+		s.config.OnInstanceChange(outcome.oldURL, outcome.newURL)
+	}
+	if s.config.OnRefreshToken != nil && outcome.refreshToken != "" {
+		s.config.OnRefreshToken(outcome.refreshToken)
 	}
+	return nil
+}
 
-	s.response = resp
-	s.expiresAt = time.Now().Add(s.config.SessionDuration).UTC()
+// refreshShared refreshes the session and returns the previous and the new
+// instance URLs, and the new refresh token if Salesforce returned one.  When
+// due, the session is only refreshed if it is still due.  The token is
+// exchanged without the session's lock, so the calls that read the session
+// are not blocked by the round trip, and the concurrent refreshes share the
+// one in flight.  A refresh started with credentials that were updated since
+// is waited for and then done again with the new credentials.
+func (s *Session) refreshShared(ctx context.Context, due bool) (refreshOutcome, error) {
+	for {
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			return refreshOutcome{}, ErrSessionClosed
+		}
+		var outcome refreshOutcome
+		if due {
+			now := time.Now().UTC()
+			if s.isDueLocked(now) == false {
+				s.mu.Unlock()
+				return refreshOutcome{skipped: true}, nil
+			}
+			outcome.valid = s.response != nil && s.expiresAt.After(now)
+		}
+		creds := s.credentials
+		if creds == nil {
+			creds = s.config.Credentials
+		}
+
+		if call := s.refreshing; call != nil {
+			s.mu.Unlock()
+			select {
+			case <-call.done:
+			case <-ctx.Done():
+				return outcome, ctx.Err()
+			}
+			if call.creds != creds {
+				continue
+			}
+			outcome.shared = true
+			return outcome, call.err
+		}
+		call := &refreshCall{
+			done:  make(chan struct{}),
+			creds: creds,
+		}
+		s.refreshing = call
+		s.mu.Unlock()
+
+		resp, expiresAt, err := s.exchange(ctx, creds)
+
+		s.mu.Lock()
+		s.refreshing = nil
+		switch {
+		case err != nil:
+		case s.closed:
+			err = ErrSessionClosed
+		default:
+			// the refresh token is a secret, so it is not kept with the session
+			outcome.refreshToken = resp.RefreshToken
+			resp.RefreshToken = ""
+			if outcome.refreshToken != "" {
+				creds.RotateRefreshToken(outcome.refreshToken)
+			}
+			if s.response != nil {
+				outcome.oldURL = s.response.InstanceURL
+			}
+			s.response = resp
+			s.expiresAt = expiresAt
+			if s.renewed != nil {
+				select {
+				case s.renewed <- struct{}{}:
+				default:
+				}
+			}
+			outcome.newURL = resp.InstanceURL
+		}
+		call.err = err
+		close(call.done)
+		s.mu.Unlock()
+		return outcome, err
+	}
+}
 
-	return nil
+// exchange gets a new token with the credentials and returns it with its
+// expiry.  It is called without the session's lock.
+func (s *Session) exchange(ctx context.Context, creds *credentials.Credentials) (*sessionPasswordResponse, time.Time, error) {
+	expiresAt := time.Now().Add(s.config.SessionDuration).UTC()
+	if provider, ok := creds.TokenProvider(); ok {
+		token, err := provider.Token(ctx)
+		if err != nil {
+			return nil, time.Time{}, errors.Wrap(err, "session token")
+		}
+		if token.Expiry.IsZero() == false {
+			expiresAt = token.Expiry.UTC()
+		}
+		return &sessionPasswordResponse{
+			AccessToken: token.AccessToken,
+			InstanceURL: token.InstanceURL,
+			ID:          token.ID,
+			TokenType:   token.TokenType,
+		}, expiresAt, nil
+	}
+
+	req, err := passwordSessionRequest(ctx, creds)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	resp, err := passwordSessionResponse(req, s.config.Client)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return resp, expiresAt, nil
 }
@@ -1,12 +1,16 @@
 package session
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"net/http"
+	"net/url"
 	"reflect"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -56,7 +60,7 @@ func Test_passwordSessionRequest(t *testing.T) {
 		if err != nil {
 			t.Fatal("password credentials can not return an error for these tests")
 		}
-		request, err := passwordSessionRequest(passwordCreds)
+		request, err := passwordSessionRequest(context.Background(), passwordCreds)
 
 		if err != nil && scenario.err == nil {
 			t.Errorf("%s Error was not expected %s", scenario.desc, err.Error())
@@ -76,7 +80,7 @@ func Test_passwordSessionRequest(t *testing.T) {
 					t.Errorf("%s URL not matching %s :: %s", scenario.desc, scenario.creds.URL+oauthEndpoint, request.URL.String())
 				}
 
-				buf, err := ioutil.ReadAll(request.Body)
+				buf, err := io.ReadAll(request.Body)
 				request.Body.Close()
 				if err != nil {
 					t.Fatal(err.Error())
@@ -85,7 +89,7 @@ func Test_passwordSessionRequest(t *testing.T) {
 				if err != nil {
 					t.Fatal(err.Error())
 				}
-				body, err := ioutil.ReadAll(reader)
+				body, err := io.ReadAll(reader)
 				if err != nil {
 					t.Fatal(err.Error())
 				}
@@ -123,7 +127,7 @@ func Test_passwordSessionResponse(t *testing.T) {
 
 				return &http.Response{
 					StatusCode: 200,
-					Body:       ioutil.NopCloser(strings.NewReader(resp)),
+					Body:       io.NopCloser(strings.NewReader(resp)),
 					Header:     make(http.Header),
 				}
 			}),
@@ -143,11 +147,11 @@ func Test_passwordSessionResponse(t *testing.T) {
 			client: mockHTTPClient(func(req *http.Request) *http.Response {
 				return &http.Response{
 					Status: "400 Bad Request",
-					Body:   ioutil.NopCloser(strings.NewReader(`{"error":"invalid_grant","error_description":"authentication failure"}`)),
+					Body:   io.NopCloser(strings.NewReader(`{"error":"invalid_grant","error_description":"authentication failure"}`)),
 					Header: make(http.Header),
 				}
 			}),
-			wantErr: fmt.Errorf(`session response: 400 Bad Request: {"error":"invalid_grant","error_description":"authentication failure"}`),
+			wantErr: fmt.Errorf(`session response: 400 Bad Request: invalid_grant: authentication failure ()`),
 		},
 		{
 			name: "ResponseDecodeError",
@@ -165,7 +169,7 @@ func Test_passwordSessionResponse(t *testing.T) {
 
 				return &http.Response{
 					StatusCode: 200,
-					Body:       ioutil.NopCloser(strings.NewReader(resp)),
+					Body:       io.NopCloser(strings.NewReader(resp)),
 					Header:     make(http.Header),
 				}
 			}),
@@ -231,7 +235,7 @@ func TestNewPasswordSession(t *testing.T) {
 
 					return &http.Response{
 						StatusCode: 200,
-						Body:       ioutil.NopCloser(strings.NewReader(resp)),
+						Body:       io.NopCloser(strings.NewReader(resp)),
 						Header:     make(http.Header),
 					}
 				}),
@@ -283,13 +287,13 @@ func TestNewPasswordSession(t *testing.T) {
 				Client: mockHTTPClient(func(req *http.Request) *http.Response {
 					return &http.Response{
 						Status: "400 Bad Request",
-						Body:   ioutil.NopCloser(strings.NewReader(`{"error":"invalid_grant","error_description":"authentication failure"}`)),
+						Body:   io.NopCloser(strings.NewReader(`{"error":"invalid_grant","error_description":"authentication failure"}`)),
 						Header: make(http.Header),
 					}
 				}),
 				Version: 45,
 			},
-			wantErr: fmt.Errorf(`session response: 400 Bad Request: {"error":"invalid_grant","error_description":"authentication failure"}`),
+			wantErr: fmt.Errorf(`session response: 400 Bad Request: invalid_grant: authentication failure ()`),
 		},
 	}
 
@@ -506,7 +510,7 @@ func TestSession_Refresh(t *testing.T) {
 		resp := `{"access_token":"nEw:ToKeN"}`
 		return &http.Response{
 			StatusCode: http.StatusOK,
-			Body:       ioutil.NopCloser(strings.NewReader(resp)),
+			Body:       io.NopCloser(strings.NewReader(resp)),
 		}
 	})
 	config := sfdc.Configuration{
@@ -541,11 +545,11 @@ func TestSession_Refresh(t *testing.T) {
 	})
 
 	t.Run("failed_to_refresh_expired", func(t *testing.T) {
-		const wantErr = `session response: 400 Bad Request: {"error":"invalid_grant","error_description":"authentication failure"}`
+		const wantErr = `session response: 400 Bad Request: invalid_grant: authentication failure ()`
 		client := mockHTTPClient(func(req *http.Request) *http.Response {
 			return &http.Response{
 				Status: "400 Bad Request",
-				Body:   ioutil.NopCloser(strings.NewReader(`{"error":"invalid_grant","error_description":"authentication failure"}`)),
+				Body:   io.NopCloser(strings.NewReader(`{"error":"invalid_grant","error_description":"authentication failure"}`)),
 				Header: make(http.Header),
 			}
 		})
@@ -563,3 +567,618 @@ func TestSession_Refresh(t *testing.T) {
 		assert.EqualError(t, err, wantErr)
 	})
 }
+
+func TestSession_Refresh_concurrent(t *testing.T) {
+	var requests int32
+	client := mockHTTPClient(func(req *http.Request) *http.Response {
+		atomic.AddInt32(&requests, 1)
+		// hold the refresh so the other calls wait for it
+		time.Sleep(10 * time.Millisecond)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"access_token":"nEw:ToKeN"}`)),
+			Header:     make(http.Header),
+		}
+	})
+	s := &Session{
+		response:  &sessionPasswordResponse{AccessToken: "oLd:ToKeN"},
+		expiresAt: time.Now().Add(-time.Minute).UTC(),
+		config: sfdc.Configuration{
+			SessionDuration: defaultSessionDuration,
+			Client:          client,
+			Credentials: testNewPasswordCredentials(t, credentials.PasswordCredentials{
+				URL:          "http://test.password.session",
+				Username:     "myusername",
+				Password:     "12345",
+				ClientID:     "some client id",
+				ClientSecret: "shhhh its a secret",
+			}),
+		},
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 50)
+	for idx := 0; idx < 50; idx++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- s.Refresh()
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	assert.Equal(t, "nEw:ToKeN", s.Token().AccessToken)
+}
+
+// blockingTokenProvider holds the token exchange until it is released.
+type blockingTokenProvider struct {
+	mockTokenProvider
+	started chan struct{}
+	release chan struct{}
+}
+
+func (mock *blockingTokenProvider) Token(ctx context.Context) (credentials.Token, error) {
+	close(mock.started)
+	<-mock.release
+	return mock.mockTokenProvider.Token(ctx)
+}
+
+func TestSession_Refresh_unlocked(t *testing.T) {
+	provider := &blockingTokenProvider{
+		mockTokenProvider: mockTokenProvider{
+			tokens: []credentials.Token{
+				{
+					AccessToken: "nEw:ToKeN",
+					TokenType:   "Bearer",
+					InstanceURL: "https://na1.salesforce.com",
+					Expiry:      time.Now().Add(time.Hour),
+				},
+			},
+		},
+		started: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	creds, err := credentials.NewCredentials(provider)
+	require.NoError(t, err)
+	s := &Session{
+		response: &sessionPasswordResponse{
+			AccessToken: "oLd:ToKeN",
+			TokenType:   "Bearer",
+			InstanceURL: "https://na1.salesforce.com",
+		},
+		expiresAt: time.Now().Add(time.Minute).UTC(),
+		config: sfdc.Configuration{
+			Credentials:   creds,
+			RefreshWindow: 5 * time.Minute,
+			Version:       45,
+		},
+	}
+
+	errs := make(chan error, 10)
+	for idx := 0; idx < 10; idx++ {
+		go func() {
+			errs <- s.Refresh()
+		}()
+	}
+	<-provider.started
+
+	// the session is read while the token is exchanged
+	read := make(chan string)
+	go func() {
+		req, _ := http.NewRequest(http.MethodGet, s.ServiceURL(), nil)
+		s.AuthorizationHeader(req)
+		read <- req.Header.Get("Authorization")
+	}()
+	select {
+	case header := <-read:
+		assert.Equal(t, "Bearer oLd:ToKeN", header)
+	case <-time.After(time.Second):
+		t.Fatal("Session.AuthorizationHeader() is blocked by the refresh")
+	}
+
+	close(provider.release)
+	for idx := 0; idx < 10; idx++ {
+		require.NoError(t, <-errs)
+	}
+	assert.Equal(t, 1, provider.calls)
+	assert.Equal(t, "nEw:ToKeN", s.Token().AccessToken)
+}
+
+func TestSession_Refresh_window(t *testing.T) {
+	creds := testNewPasswordCredentials(t, credentials.PasswordCredentials{
+		URL:          "http://test.password.session",
+		Username:     "myusername",
+		Password:     "12345",
+		ClientID:     "some client id",
+		ClientSecret: "shhhh its a secret",
+	})
+	status := http.StatusOK
+	client := mockHTTPClient(func(req *http.Request) *http.Response {
+		if status != http.StatusOK {
+			return &http.Response{
+				StatusCode: status,
+				Status:     "400 Bad Request",
+				Body:       io.NopCloser(strings.NewReader(`{"error":"invalid_grant","error_description":"authentication failure"}`)),
+				Header:     make(http.Header),
+			}
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"access_token":"nEw:ToKeN"}`)),
+			Header:     make(http.Header),
+		}
+	})
+	var refreshErrs []error
+	config := sfdc.Configuration{
+		SessionDuration: defaultSessionDuration,
+		Client:          client,
+		Credentials:     creds,
+		RefreshWindow:   5 * time.Minute,
+		OnRefresh: func(err error) {
+			refreshErrs = append(refreshErrs, err)
+		},
+	}
+
+	s := &Session{
+		response:  &sessionPasswordResponse{AccessToken: "oLd:ToKeN"},
+		expiresAt: time.Now().Add(10 * time.Minute).UTC(),
+		config:    config,
+	}
+	require.NoError(t, s.Refresh())
+	assert.Equal(t, "oLd:ToKeN", s.Token().AccessToken)
+
+	s.expiresAt = time.Now().Add(time.Minute).UTC()
+	require.NoError(t, s.Refresh())
+	assert.Equal(t, "nEw:ToKeN", s.Token().AccessToken)
+	assert.Equal(t, []error{nil}, refreshErrs)
+
+	// a failed refresh in the window keeps the token that is still valid
+	status = http.StatusBadRequest
+	s.response = &sessionPasswordResponse{AccessToken: "oLd:ToKeN"}
+	s.expiresAt = time.Now().Add(time.Minute).UTC()
+	require.NoError(t, s.Refresh())
+	assert.Equal(t, "oLd:ToKeN", s.Token().AccessToken)
+	require.Len(t, refreshErrs, 2)
+	assert.Error(t, refreshErrs[1])
+
+	s.expiresAt = time.Now().Add(-time.Minute).UTC()
+	assert.Error(t, s.Refresh())
+
+	config.Version = 45
+	config.RefreshWindow = -time.Minute
+	_, err := Open(config)
+	assert.EqualError(t, err, "session: configuration refresh window can not be negative")
+}
+
+func TestSession_Identity(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		want    Identity
+		wantErr bool
+	}{
+		{
+			name: "Passing",
+			id:   "https://test.salesforce.com/id/00D50000000IZ3ZEAW/00550000001fg5OAAQ",
+			want: Identity{
+				URL:            "https://test.salesforce.com/id/00D50000000IZ3ZEAW/00550000001fg5OAAQ",
+				OrganizationID: "00D50000000IZ3ZEAW",
+				UserID:         "00550000001fg5OAAQ",
+			},
+			wantErr: false,
+		},
+		{
+			name:    "Not Identity URL",
+			id:      "https://test.salesforce.com/services/data",
+			want:    Identity{},
+			wantErr: true,
+		},
+		{
+			name:    "Empty",
+			id:      "",
+			want:    Identity{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := &Session{
+				response: &sessionPasswordResponse{
+					ID: tt.id,
+				},
+			}
+			got, err := session.Identity()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Session.Identity() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Session.Identity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSession_UpdateCredentials(t *testing.T) {
+	client := mockHTTPClient(func(req *http.Request) *http.Response {
+		if err := req.ParseForm(); err != nil {
+			t.Fatalf("request form error = %v", err)
+		}
+		switch req.PostForm.Get("username") {
+		case "old":
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"access_token": "old token", "instance_url": "https://test.salesforce.com", "token_type": "Bearer"}`)),
+				Header:     make(http.Header),
+			}
+		case "new":
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"access_token": "new token", "instance_url": "https://test.salesforce.com", "token_type": "Bearer"}`)),
+				Header:     make(http.Header),
+			}
+		default:
+			return &http.Response{
+				StatusCode: http.StatusBadRequest,
+				Status:     "400 Bad Request",
+				Body:       io.NopCloser(strings.NewReader(`{"error":"invalid_grant","error_description":"authentication failure"}`)),
+				Header:     make(http.Header),
+			}
+		}
+	})
+	creds := func(username string) *credentials.Credentials {
+		return testNewPasswordCredentials(t, credentials.PasswordCredentials{
+			URL:          "http://test.password.session",
+			Username:     username,
+			Password:     "12345",
+			ClientID:     "some client id",
+			ClientSecret: "shhhh its a secret",
+		})
+	}
+	authorization := func(session *Session) string {
+		req, err := http.NewRequest(http.MethodGet, "https://test.salesforce.com", nil)
+		require.NoError(t, err)
+		session.AuthorizationHeader(req)
+		return req.Header.Get("Authorization")
+	}
+
+	session, err := Open(sfdc.Configuration{
+		Credentials:     creds("old"),
+		Client:          client,
+		Version:         45,
+		SessionDuration: time.Hour,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer old token", authorization(session))
+
+	assert.EqualError(t, session.UpdateCredentials(nil), "session: credentials can not be nil")
+
+	require.NoError(t, session.UpdateCredentials(creds("new")))
+	assert.Equal(t, "Bearer old token", authorization(session))
+	require.NoError(t, session.ForceRefresh(context.Background()))
+	assert.Equal(t, "Bearer new token", authorization(session))
+
+	expiresAt := session.expiresAt
+	require.NoError(t, session.UpdateCredentials(creds("revoked")))
+	assert.Error(t, session.ForceRefresh(context.Background()))
+	assert.Equal(t, "Bearer new token", authorization(session))
+	assert.Equal(t, expiresAt, session.expiresAt)
+	assert.False(t, session.isExpired())
+}
+
+func TestSession_correlationHeader(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{
+			name:   "default header",
+			header: "",
+			want:   "X-Correlation-Id: 1234",
+		},
+		{
+			name:   "call options",
+			header: sfdc.CallOptionsHeader,
+			want:   "Sforce-Call-Options: client=1234",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			client := correlatedClient(mockHTTPClient(func(req *http.Request) *http.Response {
+				for header := range req.Header {
+					got = header + ": " + req.Header.Get(header)
+				}
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader("")),
+					Header:     make(http.Header),
+				}
+			}), tt.header)
+			req, err := http.NewRequestWithContext(sfdc.WithCorrelationID(context.Background(), "1234"), http.MethodGet, "https://test.salesforce.com", nil)
+			require.NoError(t, err)
+			_, err = client.Do(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSession_refreshContext(t *testing.T) {
+	type contextKey struct{}
+	ctx := context.WithValue(context.Background(), contextKey{}, "refresh")
+	body := newTrackingBody(`{"error":"invalid_grant","error_description":"authentication failure"}`)
+	var got context.Context
+	session := &Session{
+		config: sfdc.Configuration{
+			Credentials: testNewPasswordCredentials(t, credentials.PasswordCredentials{
+				URL:          "http://test.password.session",
+				Username:     "myusername",
+				Password:     "12345",
+				ClientID:     "some client id",
+				ClientSecret: "shhhh its a secret",
+			}),
+			Client: mockHTTPClient(func(req *http.Request) *http.Response {
+				got = req.Context()
+				return &http.Response{
+					StatusCode: http.StatusBadRequest,
+					Status:     "400 Bad Request",
+					Body:       body,
+					Header:     make(http.Header),
+				}
+			}),
+		},
+	}
+	require.Error(t, session.ForceRefresh(ctx))
+	require.NotNil(t, got)
+	assert.Equal(t, "refresh", got.Value(contextKey{}))
+	assert.True(t, body.closed)
+}
+
+func TestSession_instanceChange(t *testing.T) {
+	instanceURL := "https://na1.salesforce.com"
+	client := mockHTTPClient(func(req *http.Request) *http.Response {
+		resp := fmt.Sprintf(`{"access_token": "token", "instance_url": %q, "token_type": "Bearer"}`, instanceURL)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(resp)),
+			Header:     make(http.Header),
+		}
+	})
+	var changes [][2]string
+	session, err := Open(sfdc.Configuration{
+		Credentials: testNewPasswordCredentials(t, credentials.PasswordCredentials{
+			URL:          "http://test.password.session",
+			Username:     "myusername",
+			Password:     "12345",
+			ClientID:     "some client id",
+			ClientSecret: "shhhh its a secret",
+		}),
+		Client:  client,
+		Version: 45,
+		OnInstanceChange: func(old, new string) {
+			changes = append(changes, [2]string{old, new})
+		},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	require.NoError(t, session.ForceRefresh(context.Background()))
+	assert.Empty(t, changes)
+
+	instanceURL = "https://na2.salesforce.com"
+	require.NoError(t, session.ForceRefresh(context.Background()))
+	assert.Equal(t, [][2]string{{"https://na1.salesforce.com", "https://na2.salesforce.com"}}, changes)
+	assert.Equal(t, "https://na2.salesforce.com", session.InstanceURL())
+	assert.Equal(t, "https://na2.salesforce.com/services/data/v45.0", session.ServiceURL())
+}
+
+func TestSession_refreshToken(t *testing.T) {
+	var forms []url.Values
+	refreshToken := ""
+	client := mockHTTPClient(func(req *http.Request) *http.Response {
+		body, _ := io.ReadAll(req.Body)
+		form, _ := url.ParseQuery(string(body))
+		forms = append(forms, form)
+		resp := fmt.Sprintf(`{"access_token": "token", "instance_url": "https://na1.salesforce.com", "token_type": "Bearer", "refresh_token": %q}`, refreshToken)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(resp)),
+			Header:     make(http.Header),
+		}
+	})
+	creds, err := credentials.NewRefreshTokenCredentials(credentials.RefreshTokenCredentials{
+		URL:          "http://test.refresh.session",
+		ClientID:     "some client id",
+		ClientSecret: "shhhh its a secret",
+		RefreshToken: "first",
+	})
+	require.NoError(t, err)
+	var rotated []string
+	session, err := Open(sfdc.Configuration{
+		Credentials: creds,
+		Client:      client,
+		Version:     45,
+		OnRefreshToken: func(token string) {
+			rotated = append(rotated, token)
+		},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, rotated)
+
+	refreshToken = "second"
+	require.NoError(t, session.ForceRefresh(context.Background()))
+	assert.Equal(t, []string{"second"}, rotated)
+
+	refreshToken = ""
+	require.NoError(t, session.ForceRefresh(context.Background()))
+	assert.Equal(t, []string{"second"}, rotated)
+
+	require.Len(t, forms, 3)
+	assert.Equal(t, "refresh_token", forms[0].Get("grant_type"))
+	assert.Equal(t, "first", forms[0].Get("refresh_token"))
+	assert.Equal(t, "first", forms[1].Get("refresh_token"))
+	assert.Equal(t, "second", forms[2].Get("refresh_token"))
+	assert.Equal(t, "", forms[2].Get("password"))
+	assert.Equal(t, "Bearer token", session.Token().TokenType+" "+session.Token().AccessToken)
+}
+
+type mockTokenProvider struct {
+	tokens []credentials.Token
+	calls  int
+}
+
+func (mock *mockTokenProvider) Retrieve() (io.Reader, error) {
+	return nil, errors.New("token provider does not use the OAuth exchange")
+}
+
+func (mock *mockTokenProvider) URL() string {
+	return ""
+}
+
+func (mock *mockTokenProvider) Token(ctx context.Context) (credentials.Token, error) {
+	token := mock.tokens[mock.calls]
+	mock.calls++
+	return token, nil
+}
+
+func TestSession_tokenProvider(t *testing.T) {
+	provider := &mockTokenProvider{
+		tokens: []credentials.Token{
+			{
+				AccessToken: "first",
+				TokenType:   "Bearer",
+				InstanceURL: "https://na1.salesforce.com",
+				Expiry:      time.Now().Add(-time.Minute),
+			},
+			{
+				AccessToken: "second",
+				TokenType:   "Bearer",
+				InstanceURL: "https://na1.salesforce.com",
+				Expiry:      time.Now().Add(time.Hour),
+			},
+		},
+	}
+	creds, err := credentials.NewCredentials(provider)
+	require.NoError(t, err)
+	client := mockHTTPClient(func(req *http.Request) *http.Response {
+		t.Errorf("unexpected request %s", req.URL)
+		return nil
+	})
+
+	session, err := Open(sfdc.Configuration{
+		Credentials: creds,
+		Client:      client,
+		Version:     45,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "first", session.Token().AccessToken)
+	assert.True(t, session.isExpired())
+
+	require.NoError(t, session.Refresh())
+	assert.Equal(t, "second", session.Token().AccessToken)
+	assert.Equal(t, provider.tokens[1].Expiry.UTC(), session.Token().Expiry)
+
+	require.NoError(t, session.Refresh())
+	assert.Equal(t, 2, provider.calls)
+	assert.Equal(t, "https://na1.salesforce.com/services/data/v45.0", session.ServiceURL())
+}
+
+func TestSession_Close(t *testing.T) {
+	var requests []*http.Request
+	var bodies []string
+	client := mockHTTPClient(func(req *http.Request) *http.Response {
+		body, _ := io.ReadAll(req.Body)
+		requests = append(requests, req)
+		bodies = append(bodies, string(body))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader("")),
+			Header:     make(http.Header),
+		}
+	})
+	creds := testNewPasswordCredentials(t, credentials.PasswordCredentials{
+		URL:          "http://test.password.session",
+		Username:     "myusername",
+		Password:     "12345",
+		ClientID:     "some client id",
+		ClientSecret: "shhhh its a secret",
+	})
+	s := &Session{
+		response: &sessionPasswordResponse{
+			AccessToken: "token+/=",
+			InstanceURL: "https://na1.salesforce.com",
+			TokenType:   "Bearer",
+		},
+		expiresAt:   time.Now().Add(-time.Minute).UTC(),
+		credentials: creds,
+		config: sfdc.Configuration{
+			SessionDuration: defaultSessionDuration,
+			Client:          client,
+			Credentials:     creds,
+			Version:         45,
+		},
+	}
+
+	require.NoError(t, s.Close(context.Background()))
+	require.Len(t, requests, 1)
+	assert.Equal(t, http.MethodPost, requests[0].Method)
+	assert.Equal(t, "https://na1.salesforce.com/services/oauth2/revoke", requests[0].URL.String())
+	assert.Equal(t, "application/x-www-form-urlencoded", requests[0].Header.Get("Content-Type"))
+	assert.Equal(t, "token=token%2B%2F%3D", bodies[0])
+
+	request, err := http.NewRequest(http.MethodGet, "https://na1.salesforce.com", nil)
+	require.NoError(t, err)
+	request.Header.Set("Authorization", "Bearer stale")
+	s.AuthorizationHeader(request)
+	assert.Empty(t, request.Header.Get("Authorization"))
+	assert.Empty(t, s.Token().AccessToken)
+	assert.Equal(t, "https://na1.salesforce.com/services/data/v45.0", s.ServiceURL())
+
+	assert.Equal(t, ErrSessionClosed, s.Refresh())
+	assert.Equal(t, ErrSessionClosed, s.ForceRefresh(context.Background()))
+	assert.Equal(t, ErrSessionClosed, s.UpdateCredentials(creds))
+	_, err = s.Identity()
+	assert.Equal(t, ErrSessionClosed, err)
+
+	body, err := creds.Retrieve()
+	require.NoError(t, err)
+	retrieved, err := io.ReadAll(body)
+	require.NoError(t, err)
+	assert.NotContains(t, string(retrieved), "12345")
+	assert.NotContains(t, string(retrieved), "shhhh")
+
+	require.NoError(t, s.Close(context.Background()))
+	assert.Len(t, requests, 1, "a closed session is not revoked again")
+}
+
+func TestSession_Close_revokeFailed(t *testing.T) {
+	client := mockHTTPClient(func(req *http.Request) *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusBadRequest,
+			Status:     "400 Bad Request",
+			Body:       io.NopCloser(strings.NewReader(`{"error":"unsupported_token_type","error_description":"this token type is not supported"}`)),
+			Header:     make(http.Header),
+		}
+	})
+	s := &Session{
+		response: &sessionPasswordResponse{
+			AccessToken: "token",
+			InstanceURL: "https://na1.salesforce.com",
+			TokenType:   "Bearer",
+		},
+		config: sfdc.Configuration{
+			Client: client,
+		},
+	}
+
+	err := s.Close(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, ErrSessionClosed, s.Refresh())
+	assert.NoError(t, s.Close(context.Background()))
+}
@@ -0,0 +1,140 @@
+// Package sessiontest has the conformance test of the session.ServiceFormatter
+// implementations.
+package sessiontest
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/namely/go-sfdc/v3/session"
+)
+
+// conformanceRoutines is the number of goroutines used to check the
+// concurrency of the formatter.
+const conformanceRoutines = 8
+
+// TestServiceFormatter is a conformance test for ServiceFormatter implementations.
+// It checks that the implementation meets the contract the resources rely on:
+//
+// Refresh does not return an error for a valid session and can be called
+// concurrently with the other methods.
+//
+// Client does not return nil.
+//
+// InstanceURL is an absolute URL without a trailing slash.
+//
+// Version is greater than zero and ServiceURL is the instance URL with the
+// /services/data/vXX.0 path.
+//
+// AuthorizationHeader sets a single Authorization header, even when it is
+// called more than once for a request.
+//
+// newImpl returns a new, valid implementation for each subtest.  Run it from a
+// test with the race detector to check the concurrency.
+func TestServiceFormatter(t *testing.T, newImpl func() session.ServiceFormatter) {
+	t.Helper()
+
+	t.Run("Refresh", func(t *testing.T) {
+		formatter := newImpl()
+		if err := formatter.Refresh(); err != nil {
+			t.Fatalf("Refresh() error = %v", err)
+		}
+		if err := formatter.Refresh(); err != nil {
+			t.Errorf("Refresh() second call error = %v", err)
+		}
+	})
+
+	t.Run("Client", func(t *testing.T) {
+		formatter := newImpl()
+		if formatter.Client() == nil {
+			t.Errorf("Client() is nil")
+		}
+	})
+
+	t.Run("InstanceURL", func(t *testing.T) {
+		formatter := newImpl()
+		checkInstanceURL(t, formatter.InstanceURL())
+	})
+
+	t.Run("ServiceURL", func(t *testing.T) {
+		formatter := newImpl()
+		if formatter.Version() <= 0 {
+			t.Fatalf("Version() = %d, must be greater than zero", formatter.Version())
+		}
+		want := strings.TrimSuffix(formatter.InstanceURL(), "/") + fmt.Sprintf("/services/data/v%d.0", formatter.Version())
+		if got := formatter.ServiceURL(); got != want {
+			t.Errorf("ServiceURL() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("AuthorizationHeader", func(t *testing.T) {
+		formatter := newImpl()
+		request, err := http.NewRequest(http.MethodGet, formatter.ServiceURL(), nil)
+		if err != nil {
+			t.Fatalf("http.NewRequest() error = %v", err)
+		}
+		formatter.AuthorizationHeader(request)
+		formatter.AuthorizationHeader(request)
+
+		values := request.Header.Values("Authorization")
+		if len(values) != 1 {
+			t.Fatalf("AuthorizationHeader() set %d Authorization headers, want 1", len(values))
+		}
+		if strings.TrimSpace(values[0]) == "" {
+			t.Errorf("AuthorizationHeader() set an empty Authorization header")
+		}
+	})
+
+	t.Run("Concurrency", func(t *testing.T) {
+		formatter := newImpl()
+		errs := make(chan error, conformanceRoutines)
+		var wg sync.WaitGroup
+		for idx := 0; idx < conformanceRoutines; idx++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := formatter.Refresh(); err != nil {
+					errs <- fmt.Errorf("Refresh() error = %w", err)
+					return
+				}
+				request, err := http.NewRequest(http.MethodGet, formatter.ServiceURL(), nil)
+				if err != nil {
+					errs <- err
+					return
+				}
+				formatter.AuthorizationHeader(request)
+				if request.Header.Get("Authorization") == "" {
+					errs <- fmt.Errorf("AuthorizationHeader() did not set the Authorization header")
+					return
+				}
+				if formatter.Client() == nil {
+					errs <- fmt.Errorf("Client() is nil")
+				}
+			}()
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			t.Error(err)
+		}
+	})
+}
+
+func checkInstanceURL(t *testing.T, instanceURL string) {
+	t.Helper()
+
+	parsed, err := url.Parse(instanceURL)
+	if err != nil {
+		t.Fatalf("InstanceURL() %q is not a URL: %v", instanceURL, err)
+	}
+	if parsed.IsAbs() == false || parsed.Host == "" {
+		t.Errorf("InstanceURL() %q is not an absolute URL", instanceURL)
+	}
+	if strings.HasSuffix(instanceURL, "/") {
+		t.Errorf("InstanceURL() %q has a trailing slash", instanceURL)
+	}
+}
@@ -0,0 +1,91 @@
+package sessiontest
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/namely/go-sfdc/v3"
+	"github.com/namely/go-sfdc/v3/credentials"
+	"github.com/namely/go-sfdc/v3/session"
+)
+
+type mockServiceFormatter struct {
+	url     string
+	version int
+	client  *http.Client
+}
+
+func (mock *mockServiceFormatter) InstanceURL() string {
+	return mock.url
+}
+
+func (mock *mockServiceFormatter) ServiceURL() string {
+	return fmt.Sprintf("%s/services/data/v%d.0", mock.url, mock.version)
+}
+
+func (mock *mockServiceFormatter) Version() int {
+	return mock.version
+}
+
+func (mock *mockServiceFormatter) AuthorizationHeader(request *http.Request) {
+	request.Header.Set("Authorization", "Bearer token")
+}
+
+func (mock *mockServiceFormatter) Client() *http.Client {
+	return mock.client
+}
+
+func (mock *mockServiceFormatter) Refresh() error {
+	return nil
+}
+
+func TestServiceFormatter_session(t *testing.T) {
+	creds, err := credentials.NewPasswordCredentials(credentials.PasswordCredentials{
+		URL:          "https://login.salesforce.com",
+		Username:     "myusername",
+		Password:     "12345",
+		ClientID:     "some client id",
+		ClientSecret: "shhhh its a secret",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := mockHTTPClient(func(req *http.Request) *http.Response {
+		resp := `{
+			"access_token": "token",
+			"instance_url": "https://na42.salesforce.com",
+			"id": "https://login.salesforce.com/id/00D50000000IZ3ZEAW/00550000001fg5OAAQ",
+			"token_type": "Bearer"
+		}`
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(resp)),
+			Header:     make(http.Header),
+		}
+	})
+
+	TestServiceFormatter(t, func() session.ServiceFormatter {
+		formatter, err := session.Open(sfdc.Configuration{
+			Credentials: creds,
+			Client:      client,
+			Version:     45,
+		})
+		if err != nil {
+			t.Fatalf("Open() error = %v", err)
+		}
+		return formatter
+	})
+}
+
+func TestServiceFormatter_mock(t *testing.T) {
+	TestServiceFormatter(t, func() session.ServiceFormatter {
+		return &mockServiceFormatter{
+			url:     "https://na42.salesforce.com",
+			version: 45,
+			client:  http.DefaultClient,
+		}
+	})
+}
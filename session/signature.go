@@ -0,0 +1,67 @@
+package session
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// IdentityURL returns the identity URL of the session's user, which has the
+// form https://login.salesforce.com/id/orgID/userID.
+func (s *Session) IdentityURL() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.response.ID
+}
+
+// IssuedAt returns when the session's token was issued.  The sessions from a
+// token provider do not have the issue time.
+func (s *Session) IssuedAt() (time.Time, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.response.IssuedAt == "" {
+		return time.Time{}, errors.New("session issued at: the login response does not have issued_at")
+	}
+	millis, err := strconv.ParseInt(s.response.IssuedAt, 10, 64)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "session issued at")
+	}
+	return time.Unix(0, millis*int64(time.Millisecond)).UTC(), nil
+}
+
+// VerifySignature will verify the signature of the login response with the
+// client secret.  The signature is the Base64 encoded HMAC-SHA256 of the
+// identity URL and the issue time, so a login response that was tampered with
+// returns an error.
+func (s *Session) VerifySignature(clientSecret string) error {
+	if clientSecret == "" {
+		return errors.New("session signature: client secret can not be empty")
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.response.Signature == "" {
+		return errors.New("session signature: the login response does not have a signature")
+	}
+	if s.response.ID == "" || s.response.IssuedAt == "" {
+		return errors.New("session signature: the login response does not have id and issued_at")
+	}
+	signature, err := base64.StdEncoding.DecodeString(s.response.Signature)
+	if err != nil {
+		return errors.Wrap(err, "session signature")
+	}
+
+	mac := hmac.New(sha256.New, []byte(clientSecret))
+	mac.Write([]byte(s.response.ID + s.response.IssuedAt))
+	if hmac.Equal(signature, mac.Sum(nil)) == false {
+		return errors.New("session signature: the signature does not match the login response")
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testIdentityURL = "https://login.salesforce.com/id/00D50000000IZ3ZEAW/00550000001fg5OAAQ"
+	testIssuedAt    = "1278448832702"
+	testSignature   = "nmxqvUtuDt/hcZoAgFZSYJBKCJn7rW7ITCQo+vetbs0="
+	testSecret      = "shhhh its a secret"
+)
+
+func TestSession_IdentityURL(t *testing.T) {
+	session := &Session{
+		response: &sessionPasswordResponse{
+			ID: testIdentityURL,
+		},
+	}
+	assert.Equal(t, testIdentityURL, session.IdentityURL())
+}
+
+func TestSession_IssuedAt(t *testing.T) {
+	scenarios := []struct {
+		desc     string
+		issuedAt string
+		want     time.Time
+		wantErr  bool
+	}{
+		{
+			desc:     "milliseconds",
+			issuedAt: testIssuedAt,
+			want:     time.Date(2010, time.July, 6, 20, 40, 32, 702*int(time.Millisecond), time.UTC),
+		},
+		{
+			desc:    "missing",
+			wantErr: true,
+		},
+		{
+			desc:     "not a number",
+			issuedAt: "yesterday",
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range scenarios {
+		t.Run(tc.desc, func(t *testing.T) {
+			session := &Session{
+				response: &sessionPasswordResponse{
+					IssuedAt: tc.issuedAt,
+				},
+			}
+			got, err := session.IssuedAt()
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tc.want.Equal(got), "IssuedAt() = %v, want %v", got, tc.want)
+		})
+	}
+}
+
+func TestSession_VerifySignature(t *testing.T) {
+	scenarios := []struct {
+		desc     string
+		response sessionPasswordResponse
+		secret   string
+		wantErr  bool
+	}{
+		{
+			desc: "valid signature",
+			response: sessionPasswordResponse{
+				ID:        testIdentityURL,
+				IssuedAt:  testIssuedAt,
+				Signature: testSignature,
+			},
+			secret: testSecret,
+		},
+		{
+			desc: "tampered identity url",
+			response: sessionPasswordResponse{
+				ID:        "https://login.salesforce.com/id/00D50000000IZ3ZEAW/00550000001fg5OAAR",
+				IssuedAt:  testIssuedAt,
+				Signature: testSignature,
+			},
+			secret:  testSecret,
+			wantErr: true,
+		},
+		{
+			desc: "tampered issued at",
+			response: sessionPasswordResponse{
+				ID:        testIdentityURL,
+				IssuedAt:  "1278448832703",
+				Signature: testSignature,
+			},
+			secret:  testSecret,
+			wantErr: true,
+		},
+		{
+			desc: "wrong secret",
+			response: sessionPasswordResponse{
+				ID:        testIdentityURL,
+				IssuedAt:  testIssuedAt,
+				Signature: testSignature,
+			},
+			secret:  "not the secret",
+			wantErr: true,
+		},
+		{
+			desc: "missing signature",
+			response: sessionPasswordResponse{
+				ID:       testIdentityURL,
+				IssuedAt: testIssuedAt,
+			},
+			secret:  testSecret,
+			wantErr: true,
+		},
+		{
+			desc: "missing issued at",
+			response: sessionPasswordResponse{
+				ID:        testIdentityURL,
+				Signature: testSignature,
+			},
+			secret:  testSecret,
+			wantErr: true,
+		},
+		{
+			desc: "invalid signature encoding",
+			response: sessionPasswordResponse{
+				ID:        testIdentityURL,
+				IssuedAt:  testIssuedAt,
+				Signature: "not base64!",
+			},
+			secret:  testSecret,
+			wantErr: true,
+		},
+		{
+			desc: "empty secret",
+			response: sessionPasswordResponse{
+				ID:        testIdentityURL,
+				IssuedAt:  testIssuedAt,
+				Signature: testSignature,
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range scenarios {
+		t.Run(tc.desc, func(t *testing.T) {
+			response := tc.response
+			session := &Session{
+				response: &response,
+			}
+			err := session.VerifySignature(tc.secret)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
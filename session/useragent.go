@@ -0,0 +1,36 @@
+package session
+
+import (
+	"net/http"
+
+	"github.com/namely/go-sfdc/v3"
+)
+
+type userAgentTransport struct {
+	userAgent string
+	base      http.RoundTripper
+}
+
+func userAgentClient(client *http.Client, suffix string) *http.Client {
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	identified := *client
+	identified.Transport = &userAgentTransport{
+		userAgent: sfdc.UserAgent(suffix),
+		base:      base,
+	}
+	return &identified
+}
+
+// RoundTrip will send the request with the go-sfdc User-Agent header.  A
+// User-Agent header that is set on the request is kept.
+func (t *userAgentTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	if request.Header.Get("User-Agent") != "" {
+		return t.base.RoundTrip(request)
+	}
+	identified := request.Clone(request.Context())
+	identified.Header.Set("User-Agent", t.userAgent)
+	return t.base.RoundTrip(identified)
+}
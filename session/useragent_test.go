@@ -0,0 +1,75 @@
+package session
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/namely/go-sfdc/v3"
+	"github.com/namely/go-sfdc/v3/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpen_userAgent(t *testing.T) {
+	tests := []struct {
+		name   string
+		suffix string
+		header string
+		want   string
+	}{
+		{
+			name: "default",
+			want: "go-sfdc/" + sfdc.Version + " (+github.com/namely/go-sfdc)",
+		},
+		{
+			name:   "suffix",
+			suffix: "acme-sync/1.2",
+			want:   "go-sfdc/" + sfdc.Version + " (+github.com/namely/go-sfdc) acme-sync/1.2",
+		},
+		{
+			name:   "explicit header",
+			suffix: "acme-sync/1.2",
+			header: "acme/2.0",
+			want:   "acme/2.0",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			userAgents := map[string]string{}
+			session, err := Open(sfdc.Configuration{
+				Credentials: testNewPasswordCredentials(t, credentials.PasswordCredentials{
+					URL:          "http://test.password.session",
+					Username:     "myusername",
+					Password:     "12345",
+					ClientID:     "some client id",
+					ClientSecret: "shhhh its a secret",
+				}),
+				Client: mockHTTPClient(func(req *http.Request) *http.Response {
+					userAgents[req.URL.Path] = req.Header.Get("User-Agent")
+					resp := `{"access_token":"token","instance_url":"https://some.salesforce.instance.com","token_type":"Bearer"}`
+					return &http.Response{
+						StatusCode: 200,
+						Body:       io.NopCloser(strings.NewReader(resp)),
+						Header:     make(http.Header),
+					}
+				}),
+				Version:         45,
+				UserAgentSuffix: tc.suffix,
+			})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodGet, session.ServiceURL()+"/limits", nil)
+			require.NoError(t, err)
+			if tc.header != "" {
+				request.Header.Set("User-Agent", tc.header)
+			}
+			_, err = session.Client().Do(request)
+			require.NoError(t, err)
+
+			assert.Equal(t, sfdc.UserAgent(tc.suffix), userAgents[oauthEndpoint])
+			assert.Equal(t, tc.want, userAgents["/services/data/v45.0/limits"])
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/namely/go-sfdc/v3"
+)
+
+// VersionLister is the interface implemented by sessions that know the API
+// versions supported by the org.
+type VersionLister interface {
+	SupportedVersions() []int
+}
+
+// ServiceURLContext will return the formatter's service URL using the API version
+// of the context.  If the context does not have a version, the formatter's service
+// URL is returned.  The version is checked against the supported versions when the
+// formatter is a VersionLister.
+func ServiceURLContext(ctx context.Context, formatter ServiceFormatter) (string, error) {
+	version, ok := sfdc.APIVersion(ctx)
+	if ok == false {
+		return formatter.ServiceURL(), nil
+	}
+	if err := checkVersion(formatter, version); err != nil {
+		return "", err
+	}
+	return strings.TrimRight(formatter.InstanceURL(), "/") + fmt.Sprintf("/services/data/v%d.0", version), nil
+}
+
+// ServicePathContext will replace the API version of the service path, like a next
+// records URL, with the API version of the context.  If the context does not have a
+// version, the path is returned as is.
+func ServicePathContext(ctx context.Context, formatter ServiceFormatter, path string) (string, error) {
+	version, ok := sfdc.APIVersion(ctx)
+	if ok == false {
+		return path, nil
+	}
+	if err := checkVersion(formatter, version); err != nil {
+		return "", err
+	}
+	return sfdc.VersionServicePath(path, version), nil
+}
+
+func checkVersion(formatter ServiceFormatter, version int) error {
+	if version <= 0 {
+		return fmt.Errorf("session: api version %d must be greater than zero", version)
+	}
+	lister, ok := formatter.(VersionLister)
+	if ok == false {
+		return nil
+	}
+	supported := lister.SupportedVersions()
+	if len(supported) == 0 {
+		return nil
+	}
+	for _, v := range supported {
+		if v == version {
+			return nil
+		}
+	}
+	return fmt.Errorf("session: api version %d is not supported by the org", version)
+}
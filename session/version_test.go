@@ -0,0 +1,96 @@
+package session
+
+import (
+	"context"
+	"testing"
+
+	"github.com/namely/go-sfdc/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockVersionLister struct {
+	mockServiceFormatter
+	versions []int
+}
+
+func (mock *mockVersionLister) SupportedVersions() []int {
+	return mock.versions
+}
+
+func TestServiceURLContext(t *testing.T) {
+	formatter := &mockServiceFormatter{
+		url:     "https://na42.salesforce.com",
+		version: 45,
+	}
+	lister := &mockVersionLister{
+		mockServiceFormatter: *formatter,
+		versions:             []int{45, 57, 58},
+	}
+	scenarios := []struct {
+		desc      string
+		ctx       context.Context
+		formatter ServiceFormatter
+		want      string
+		wantErr   string
+	}{
+		{
+			desc:      "session version",
+			ctx:       context.Background(),
+			formatter: formatter,
+			want:      "https://na42.salesforce.com/services/data/v45.0",
+		},
+		{
+			desc:      "context version",
+			ctx:       sfdc.WithAPIVersion(context.Background(), 58),
+			formatter: formatter,
+			want:      "https://na42.salesforce.com/services/data/v58.0",
+		},
+		{
+			desc:      "supported version",
+			ctx:       sfdc.WithAPIVersion(context.Background(), 57),
+			formatter: lister,
+			want:      "https://na42.salesforce.com/services/data/v57.0",
+		},
+		{
+			desc:      "unsupported version",
+			ctx:       sfdc.WithAPIVersion(context.Background(), 99),
+			formatter: lister,
+			wantErr:   "session: api version 99 is not supported by the org",
+		},
+		{
+			desc:      "invalid version",
+			ctx:       sfdc.WithAPIVersion(context.Background(), 0),
+			formatter: formatter,
+			wantErr:   "session: api version 0 must be greater than zero",
+		},
+	}
+
+	for _, tc := range scenarios {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := ServiceURLContext(tc.ctx, tc.formatter)
+			if tc.wantErr != "" {
+				require.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestServicePathContext(t *testing.T) {
+	formatter := &mockServiceFormatter{
+		url:     "https://na42.salesforce.com",
+		version: 45,
+	}
+	path := "https://na42.salesforce.com/services/data/v45.0/query/01gD0000002HU6KIAW-2000"
+
+	got, err := ServicePathContext(context.Background(), formatter, path)
+	require.NoError(t, err)
+	assert.Equal(t, path, got)
+
+	got, err = ServicePathContext(sfdc.WithAPIVersion(context.Background(), 58), formatter, path)
+	require.NoError(t, err)
+	assert.Equal(t, "https://na42.salesforce.com/services/data/v58.0/query/01gD0000002HU6KIAW-2000", got)
+}
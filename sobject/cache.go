@@ -0,0 +1,119 @@
+package sobject
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/namely/go-sfdc/v3"
+	"github.com/namely/go-sfdc/v3/session"
+)
+
+// ResourcesOption is an option for the SObject resources.
+type ResourcesOption func(*Resources)
+
+// WithDescribeCache will cache the responses of List, Metadata and Describe,
+// per URL, so per SObject and API version.  A cached response is returned
+// without a request for the TTL.  After the TTL, the request is sent with the
+// If-Modified-Since header, and the cached response is returned again when
+// Salesforce answers 304 Not Modified.  A TTL of zero or less checks with
+// Salesforce on every call.
+//
+// The cache belongs to the Resources, so the resources of different orgs do
+// not share their describes, and is safe for concurrent use.
+func WithDescribeCache(ttl time.Duration) ResourcesOption {
+	return func(r *Resources) {
+		cache := &describeCache{
+			ttl:     ttl,
+			entries: make(map[string]describeEntry),
+			now:     time.Now,
+		}
+		r.describe.cache = cache
+		r.metadata.cache = cache
+		r.list.cache = cache
+	}
+}
+
+type describeEntry struct {
+	body     []byte
+	modified string
+	expires  time.Time
+}
+
+type describeCache struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]describeEntry
+	now     func() time.Time
+}
+
+// do sends the request, or answers it from the cache.  The successful
+// responses are cached, and a 304 response to a cached request is answered
+// with the cached body.  A nil cache only sends the request.
+func (c *describeCache) do(formatter session.ServiceFormatter, request *http.Request) (*http.Response, error) {
+	if c == nil {
+		return formatter.Client().Do(request)
+	}
+	key := request.URL.String()
+
+	c.mu.Lock()
+	entry, cached := c.entries[key]
+	c.mu.Unlock()
+	if cached && c.now().Before(entry.expires) {
+		return cachedResponse(request, entry.body), nil
+	}
+	if cached {
+		request.Header.Set("If-Modified-Since", entry.modified)
+	}
+
+	sent := c.now()
+	response, err := formatter.Client().Do(request)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case cached && response.StatusCode == http.StatusNotModified:
+		sfdc.CloseResponse(response)
+		entry.expires = c.now().Add(c.ttl)
+		c.store(key, entry)
+		return cachedResponse(request, entry.body), nil
+	case response.StatusCode == http.StatusOK:
+		defer sfdc.CloseResponse(response)
+		body, err := io.ReadAll(response.Body)
+		if err != nil {
+			return nil, err
+		}
+		modified := response.Header.Get("Last-Modified")
+		if modified == "" {
+			modified = sent.UTC().Format(http.TimeFormat)
+		}
+		c.store(key, describeEntry{
+			body:     body,
+			modified: modified,
+			expires:  c.now().Add(c.ttl),
+		})
+		return cachedResponse(request, body), nil
+	default:
+		return response, nil
+	}
+}
+
+func (c *describeCache) store(key string, entry describeEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = entry
+}
+
+// cachedResponse returns a 200 response with the body, which the callers
+// decode like the one from Salesforce.
+func cachedResponse(request *http.Request, body []byte) *http.Response {
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Request:    request,
+	}
+}
@@ -0,0 +1,181 @@
+package sobject
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const describeLastModified = "Mon, 05 Oct 2026 10:00:00 GMT"
+
+// describeServer answers the describe, metadata and list calls with the label,
+// and with 304 when the If-Modified-Since header is the last modified time
+// and the label has not changed.
+type describeServer struct {
+	mu       sync.Mutex
+	label    string
+	changed  bool
+	requests []string
+	since    []string
+}
+
+func (s *describeServer) session() *mockSessionFormatter {
+	return &mockSessionFormatter{
+		url: "https://test.salesforce.com/services/data/v42.0",
+		client: mockHTTPClient(func(req *http.Request) *http.Response {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.requests = append(s.requests, req.URL.Path)
+			since := req.Header.Get("If-Modified-Since")
+			s.since = append(s.since, since)
+			header := make(http.Header)
+			header.Set("Last-Modified", describeLastModified)
+			if since == describeLastModified && s.changed == false {
+				return &http.Response{
+					StatusCode: http.StatusNotModified,
+					Status:     "304 Not Modified",
+					Body:       io.NopCloser(strings.NewReader("")),
+					Header:     header,
+				}
+			}
+			s.changed = false
+			var body string
+			switch {
+			case strings.HasSuffix(req.URL.Path, "/describe"):
+				body = fmt.Sprintf(`{"name": "Account", "label": %q}`, s.label)
+			case strings.HasSuffix(req.URL.Path, "/sobjects/"):
+				body = fmt.Sprintf(`{"sobjects": [{"name": "Account", "label": %q}]}`, s.label)
+			default:
+				body = fmt.Sprintf(`{"objectDescribe": {"name": "Account", "label": %q}}`, s.label)
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Status:     "200 OK",
+				Body:       io.NopCloser(strings.NewReader(body)),
+				Header:     header,
+			}
+		}),
+	}
+}
+
+func (s *describeServer) change(label string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.label = label
+	s.changed = true
+}
+
+func TestResources_Describe_cache(t *testing.T) {
+	server := &describeServer{label: "Account"}
+	resources, err := NewResources(server.session(), WithDescribeCache(time.Hour))
+	if err != nil {
+		t.Fatalf("NewResources() error = %v", err)
+	}
+	now := time.Date(2026, time.October, 5, 12, 0, 0, 0, time.UTC)
+	resources.describe.cache.now = func() time.Time { return now }
+
+	describe := func() string {
+		t.Helper()
+		value, err := resources.Describe("Account")
+		if err != nil {
+			t.Fatalf("Resources.Describe() error = %v", err)
+		}
+		return value.Label
+	}
+
+	if label := describe(); label != "Account" || len(server.requests) != 1 {
+		t.Fatalf("Resources.Describe() = %s with %d requests, want Account with 1", label, len(server.requests))
+	}
+	if label := describe(); label != "Account" || len(server.requests) != 1 {
+		t.Errorf("Resources.Describe() = %s with %d requests, want the cached value within the ttl", label, len(server.requests))
+	}
+
+	now = now.Add(2 * time.Hour)
+	if label := describe(); label != "Account" || len(server.requests) != 2 {
+		t.Errorf("Resources.Describe() = %s with %d requests, want the cached value after a 304", label, len(server.requests))
+	}
+	if server.since[1] != describeLastModified {
+		t.Errorf("Resources.Describe() If-Modified-Since = %q, want %q", server.since[1], describeLastModified)
+	}
+	if label := describe(); label != "Account" || len(server.requests) != 2 {
+		t.Errorf("Resources.Describe() = %s with %d requests, want the 304 to renew the ttl", label, len(server.requests))
+	}
+
+	server.change("Customer")
+	now = now.Add(2 * time.Hour)
+	if label := describe(); label != "Customer" || len(server.requests) != 3 {
+		t.Errorf("Resources.Describe() = %s with %d requests, want the changed value", label, len(server.requests))
+	}
+}
+
+func TestResources_cache_perResource(t *testing.T) {
+	server := &describeServer{label: "Account"}
+	first, err := NewResources(server.session(), WithDescribeCache(time.Hour))
+	if err != nil {
+		t.Fatalf("NewResources() error = %v", err)
+	}
+	second, err := NewResources(server.session(), WithDescribeCache(time.Hour))
+	if err != nil {
+		t.Fatalf("NewResources() error = %v", err)
+	}
+	uncached, err := NewResources(server.session())
+	if err != nil {
+		t.Fatalf("NewResources() error = %v", err)
+	}
+
+	for _, resources := range []*Resources{first, first, second, uncached, uncached} {
+		if _, err := resources.Describe("Account"); err != nil {
+			t.Fatalf("Resources.Describe() error = %v", err)
+		}
+	}
+	if len(server.requests) != 4 {
+		t.Errorf("Resources.Describe() requests = %d, want 4", len(server.requests))
+	}
+	for _, since := range server.since {
+		if since != "" {
+			t.Errorf("Resources.Describe() If-Modified-Since = %q, want none", since)
+		}
+	}
+
+	if _, err := first.Metadata("Account"); err != nil {
+		t.Fatalf("Resources.Metadata() error = %v", err)
+	}
+	list, err := first.List()
+	if err != nil {
+		t.Fatalf("Resources.List() error = %v", err)
+	}
+	if _, err := first.List(); err != nil {
+		t.Fatalf("Resources.List() error = %v", err)
+	}
+	if len(server.requests) != 6 || len(list.SObjects) != 1 {
+		t.Errorf("requests = %v, want the metadata and the list once", server.requests)
+	}
+}
+
+func TestResources_cache_concurrent(t *testing.T) {
+	server := &describeServer{label: "Account"}
+	resources, err := NewResources(server.session(), WithDescribeCache(0))
+	if err != nil {
+		t.Fatalf("NewResources() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for idx := 0; idx < 20; idx++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := resources.Describe("Account")
+			if err != nil || value.Label != "Account" {
+				t.Errorf("Resources.Describe() = %s, %v, want Account", value.Label, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if len(server.requests) != 20 {
+		t.Errorf("Resources.Describe() requests = %d, want 20 without a ttl", len(server.requests))
+	}
+}
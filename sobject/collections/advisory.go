@@ -0,0 +1,74 @@
+package collections
+
+import (
+	"sync"
+
+	"github.com/namely/go-sfdc/v3"
+)
+
+// VolumeWarning is passed to the volume warning when the records sent with a
+// resource exceed the threshold.
+//
+// Operation is the collections operation, like insert, that exceeded the threshold.
+//
+// Records is the number of records sent with the resource since the last
+// warning, including the operation's records.
+//
+// Threshold is the volume warning threshold.
+//
+// Plan is the DML plan for the records.
+type VolumeWarning struct {
+	Operation string
+	Records   int
+	Threshold int
+	Plan      sfdc.Plan
+}
+
+// Option is an option for the collections resource.
+type Option func(*Resource)
+
+// WithVolumeWarning will call the warning function when the number of records
+// sent by Insert, Update, Upsert and Delete with the resource exceeds the
+// threshold.  The records are counted again from zero after each warning, so
+// every operation that sends more records than the threshold is warned about,
+// and the smaller operations are warned about each time their records add up
+// to more than the threshold.  Sending many records through the SObject
+// Collections API 200 at a time usually costs more API calls than a bulk job.
+// The warning is advisory and the operation is still sent.
+func WithVolumeWarning(threshold int, warning func(VolumeWarning)) Option {
+	return func(r *Resource) {
+		r.volume = &volume{
+			threshold: threshold,
+			warning:   warning,
+		}
+	}
+}
+
+type volume struct {
+	threshold int
+	warning   func(VolumeWarning)
+	mu        sync.Mutex
+	records   int
+}
+
+func (v *volume) add(operation string, count int) {
+	if v == nil || v.warning == nil || v.threshold <= 0 {
+		return
+	}
+	v.mu.Lock()
+	v.records += count
+	records := v.records
+	if records > v.threshold {
+		v.records = 0
+	}
+	v.mu.Unlock()
+	if records <= v.threshold {
+		return
+	}
+	v.warning(VolumeWarning{
+		Operation: operation,
+		Records:   records,
+		Threshold: v.threshold,
+		Plan:      sfdc.PlanDML(records, 0, sfdc.WithBulkThreshold(v.threshold)),
+	})
+}
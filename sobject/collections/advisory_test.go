@@ -0,0 +1,91 @@
+package collections
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/namely/go-sfdc/v3"
+)
+
+func TestResource_volumeWarning(t *testing.T) {
+	session := &mockSessionFormatter{
+		url: "https://test.salesforce.com",
+		client: mockHTTPClient(func(req *http.Request) *http.Response {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`[]`)),
+				Header:     make(http.Header),
+			}
+		}),
+	}
+	var warnings []VolumeWarning
+	r, err := NewResources(session, WithVolumeWarning(3, func(warning VolumeWarning) {
+		warnings = append(warnings, warning)
+	}))
+	if err != nil {
+		t.Fatalf("NewResources() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		records  []string
+		warnings int
+	}{
+		{
+			name:     "below threshold",
+			records:  []string{"001D000000INjVeIAL", "001D000000INjVfIAL"},
+			warnings: 0,
+		},
+		{
+			name:     "at threshold",
+			records:  []string{"001D000000INjVgIAL"},
+			warnings: 0,
+		},
+		{
+			name:     "above threshold",
+			records:  []string{"001D000000INjVhIAL"},
+			warnings: 1,
+		},
+		{
+			name:     "counted again after the warning",
+			records:  []string{"001D000000INjViIAL"},
+			warnings: 1,
+		},
+		{
+			name:     "second large call",
+			records:  []string{"001D000000INjVjIAL", "001D000000INjVkIAL", "001D000000INjVlIAL", "001D000000INjVmIAL"},
+			warnings: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := r.Delete(true, tt.records); err != nil {
+				t.Fatalf("Resource.Delete() error = %v", err)
+			}
+			if len(warnings) != tt.warnings {
+				t.Errorf("Resource.Delete() warnings = %d, want %d", len(warnings), tt.warnings)
+			}
+		})
+	}
+
+	warning := warnings[0]
+	if warning.Operation != "delete" || warning.Records != 4 || warning.Threshold != 3 {
+		t.Errorf("VolumeWarning = %+v", warning)
+	}
+	if warning.Plan.Pathway != sfdc.PathwayBulk {
+		t.Errorf("VolumeWarning plan pathway = %v, want %v", warning.Plan.Pathway, sfdc.PathwayBulk)
+	}
+	if warning := warnings[1]; warning.Records != 5 {
+		t.Errorf("second VolumeWarning records = %d, want 5", warning.Records)
+	}
+}
+
+func TestResource_noVolumeWarning(t *testing.T) {
+	var v *volume
+	v.add("insert", 1000)
+
+	v = &volume{threshold: 1}
+	v.add("insert", 1000)
+}
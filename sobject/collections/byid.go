@@ -0,0 +1,208 @@
+package collections
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/namely/go-sfdc/v3"
+	"github.com/namely/go-sfdc/v3/sobject"
+	"github.com/pkg/errors"
+)
+
+// IDsError is returned by UpdateByIDs and DeleteByIDs when some of the records
+// are not updated or deleted.  Errors has the error of each of the IDs, keyed
+// by the ID as it was given.  The error of an ID is its sfdc.Error, or the
+// error of the call when the call of its chunk failed.
+type IDsError struct {
+	Errors map[string]error
+}
+
+func (e *IDsError) Error() string {
+	ids := make([]string, 0, len(e.Errors))
+	for id := range e.Errors {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return fmt.Sprintf("collections resource: %d ids failed, first %s: %v", len(ids), ids[0], e.Errors[ids[0]])
+}
+
+// byIDUpdater is the sobject.Updater of UpdateByIDs.
+type byIDUpdater struct {
+	sobject string
+	id      string
+	fields  map[string]interface{}
+}
+
+func (u *byIDUpdater) SObject() string {
+	return u.sobject
+}
+
+func (u *byIDUpdater) ID() string {
+	return u.id
+}
+
+func (u *byIDUpdater) Fields() map[string]interface{} {
+	return u.fields
+}
+
+// UpdateByIDs will set the fields of the records of the SObject with the IDs.
+// The IDs must be 15 or 18 character Salesforce IDs, and the same record is
+// only updated once, when its ID is repeated or given in both forms.  The
+// records are sent in calls of the chunk size, which is capped to MaxRecords,
+// and up to the WithChunkConcurrency limit of the calls are sent at once.  An
+// all or none update can not span calls, so it is an error when it has more
+// records than the chunk size.
+//
+// A value is returned for each of the IDs, in their order.  Unlike the auto
+// chunking of Update, a failed call does not stop the other calls: when
+// records are not updated, their values are not successful and an *IDsError
+// with the error of each of their IDs is returned with the values.
+func (r *Resource) UpdateByIDs(ctx context.Context, sobjectName string, ids []string, fields map[string]interface{}, chunkSize int, allOrNone bool) ([]UpdateValue, error) {
+	if r.update == nil {
+		return nil, errors.New("collections resource: collections may not have been initialized properly")
+	}
+	if sobjectName == "" {
+		return nil, errors.New("collections resource: update sobject can not be empty")
+	}
+	if len(fields) == 0 {
+		return nil, errors.New("collections resource: update fields can not be empty")
+	}
+	values, err := callByIDs(ctx, r, "update", ids, chunkSize, allOrNone, func(ctx context.Context, chunk []string) ([]sobject.InsertValue, error) {
+		records := make([]sobject.Updater, len(chunk))
+		for idx, id := range chunk {
+			records[idx] = &byIDUpdater{
+				sobject: sobjectName,
+				id:      id,
+				fields:  fields,
+			}
+		}
+		updated, err := r.update.callout(ctx, allOrNone, records)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]sobject.InsertValue, len(updated))
+		for idx, value := range updated {
+			values[idx] = value.InsertValue
+		}
+		return values, nil
+	})
+	if values == nil {
+		return nil, err
+	}
+	updated := make([]UpdateValue, len(values))
+	for idx, value := range values {
+		updated[idx] = UpdateValue{InsertValue: value}
+	}
+	return updated, err
+}
+
+// DeleteByIDs will remove the records with the IDs, which are chunked, called
+// and returned like the ones of UpdateByIDs.
+func (r *Resource) DeleteByIDs(ctx context.Context, ids []string, chunkSize int, allOrNone bool) ([]DeleteValue, error) {
+	if r.remove == nil {
+		return nil, errors.New("collections resource: collections may not have been initialized properly")
+	}
+	values, err := callByIDs(ctx, r, "delete", ids, chunkSize, allOrNone, func(ctx context.Context, chunk []string) ([]sobject.InsertValue, error) {
+		removed, err := r.remove.callout(ctx, allOrNone, chunk)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]sobject.InsertValue, len(removed))
+		for idx, value := range removed {
+			values[idx] = value.InsertValue
+		}
+		return values, nil
+	})
+	if values == nil {
+		return nil, err
+	}
+	removed := make([]DeleteValue, len(values))
+	for idx, value := range values {
+		removed[idx] = DeleteValue{InsertValue: value}
+	}
+	return removed, err
+}
+
+// callByIDs validates and dedups the IDs, sends the unique 18 character IDs in
+// calls of the chunk size, and returns a value for each of the IDs in their
+// order.  The values of a failed call are not successful and have the ID.
+func callByIDs(ctx context.Context, r *Resource, operation string, ids []string, chunkSize int, allOrNone bool, call func(context.Context, []string) ([]sobject.InsertValue, error)) ([]sobject.InsertValue, error) {
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("collections resource: %s ids can not be empty", operation)
+	}
+	positions := make(map[string]int, len(ids))
+	inputs := make([]int, len(ids))
+	var unique []string
+	for idx, id := range ids {
+		normalized, err := sfdc.NormalizeID(id)
+		if err != nil {
+			return nil, fmt.Errorf("collections resource: %s id %d: %w", operation, idx, err)
+		}
+		position, has := positions[normalized]
+		if has == false {
+			position = len(unique)
+			positions[normalized] = position
+			unique = append(unique, normalized)
+		}
+		inputs[idx] = position
+	}
+
+	if chunkSize <= 0 || chunkSize > MaxRecords {
+		chunkSize = MaxRecords
+	}
+	if allOrNone && len(unique) > chunkSize {
+		return nil, fmt.Errorf("collections resource: %s of %d records with all or none can not be split into calls of %d records", operation, len(unique), chunkSize)
+	}
+	r.volume.add(operation, len(unique))
+
+	values, err := sendChunks(ctx, operation, chunkSize, r.chunkConcurrency, false, unique, call)
+	callErrs := make([]error, len(unique))
+	if err != nil {
+		var chunkErr *ChunkError
+		if errors.As(err, &chunkErr) == false {
+			return nil, err
+		}
+		for _, failure := range chunkErr.Chunks {
+			failed := fmt.Errorf("collections resource: %s of records %d to %d: %w", operation, failure.Start, failure.End, failure.Err)
+			for idx := failure.Start; idx <= failure.End; idx++ {
+				values[idx] = sobject.InsertValue{ID: unique[idx]}
+				callErrs[idx] = failed
+			}
+		}
+	}
+
+	all := make([]sobject.InsertValue, len(ids))
+	failed := make(map[string]error)
+	for idx, position := range inputs {
+		value := values[position]
+		all[idx] = value
+		switch {
+		case callErrs[position] != nil:
+			failed[ids[idx]] = callErrs[position]
+		case value.Success == false:
+			failed[ids[idx]] = recordError(value.Errors)
+		}
+	}
+	if len(failed) > 0 {
+		return all, &IDsError{Errors: failed}
+	}
+	return all, nil
+}
+
+// recordError returns the error of a record that was not successful.
+func recordError(errs []sfdc.Error) error {
+	switch len(errs) {
+	case 0:
+		return errors.New("collections resource: record was not successful")
+	case 1:
+		return errs[0]
+	default:
+		messages := make([]string, len(errs))
+		for idx, err := range errs {
+			messages[idx] = err.Error()
+		}
+		return errors.New(strings.Join(messages, "; "))
+	}
+}
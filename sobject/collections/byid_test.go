@@ -0,0 +1,240 @@
+package collections
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/namely/go-sfdc/v3"
+	"github.com/namely/go-sfdc/v3/sobject"
+)
+
+// byIDServer answers the update and delete calls with a value for each ID,
+// fails the calls with the failCall ID and the records with the failRecord ID,
+// and records the IDs of the calls.
+type byIDServer struct {
+	mu         sync.Mutex
+	calls      [][]string
+	failCall   string
+	failRecord string
+}
+
+func (s *byIDServer) client(t *testing.T) *http.Client {
+	return mockHTTPClient(func(req *http.Request) *http.Response {
+		var ids []string
+		if req.Method == http.MethodDelete {
+			ids = strings.Split(req.URL.Query().Get("ids"), ",")
+		} else {
+			var payload struct {
+				Records []map[string]interface{} `json:"records"`
+			}
+			if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+				t.Errorf("payload error = %v", err)
+			}
+			for _, record := range payload.Records {
+				if record["Name"] != "Acme" {
+					t.Errorf("record = %v, want the fields", record)
+				}
+				ids = append(ids, record["id"].(string))
+			}
+		}
+		s.mu.Lock()
+		s.calls = append(s.calls, ids)
+		s.mu.Unlock()
+
+		values := make([]sobject.InsertValue, len(ids))
+		for idx, id := range ids {
+			switch id {
+			case s.failCall:
+				return &http.Response{
+					StatusCode: http.StatusBadRequest,
+					Status:     "400 Bad Request",
+					Body:       io.NopCloser(strings.NewReader(`[{"errorCode":"INVALID_ID_FIELD","message":"bad id"}]`)),
+					Header:     make(http.Header),
+				}
+			case s.failRecord:
+				values[idx] = sobject.InsertValue{
+					ID:     id,
+					Errors: []sfdc.Error{{ErrorCode: "ENTITY_IS_DELETED", Message: "entity is deleted", Fields: []string{}}},
+				}
+			default:
+				values[idx] = sobject.InsertValue{Success: true, ID: id}
+			}
+		}
+		body, _ := json.Marshal(values)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(string(body))),
+			Header:     make(http.Header),
+		}
+	})
+}
+
+func byIDResource(t *testing.T, server *byIDServer, options ...Option) *Resource {
+	t.Helper()
+	resource, err := NewResources(&mockSessionFormatter{
+		url:    "https://test.salesforce.com",
+		client: server.client(t),
+	}, options...)
+	if err != nil {
+		t.Fatalf("NewResources() error = %v", err)
+	}
+	return resource
+}
+
+// byIDs returns the count of 15 character IDs, whose 18 character forms end
+// with AAA.
+func byIDs(count int) []string {
+	ids := make([]string, count)
+	for idx := range ids {
+		ids[idx] = fmt.Sprintf("001000000%06d", idx)
+	}
+	return ids
+}
+
+func TestResource_UpdateByIDs(t *testing.T) {
+	for _, concurrency := range []int{1, 3} {
+		t.Run(fmt.Sprint(concurrency), func(t *testing.T) {
+			server := &byIDServer{}
+			resource := byIDResource(t, server, WithChunkConcurrency(concurrency))
+
+			ids := byIDs(450)
+			values, err := resource.UpdateByIDs(context.Background(), "Account", ids, map[string]interface{}{"Name": "Acme"}, 0, false)
+			if err != nil {
+				t.Fatalf("Resource.UpdateByIDs() error = %v", err)
+			}
+			if len(values) != len(ids) {
+				t.Fatalf("Resource.UpdateByIDs() = %d values, want %d", len(values), len(ids))
+			}
+			for idx, value := range values {
+				if value.Success == false || value.ID != ids[idx]+"AAA" {
+					t.Fatalf("Resource.UpdateByIDs() value %d = %+v, want %sAAA", idx, value, ids[idx])
+				}
+			}
+			sizes := make(map[int]int)
+			for _, call := range server.calls {
+				sizes[len(call)]++
+			}
+			if len(server.calls) != 3 || sizes[200] != 2 || sizes[50] != 1 {
+				t.Errorf("Resource.UpdateByIDs() calls = %d, sizes %v, want 200, 200 and 50", len(server.calls), sizes)
+			}
+		})
+	}
+}
+
+func TestResource_DeleteByIDs_failures(t *testing.T) {
+	ids := byIDs(25)
+	server := &byIDServer{
+		failCall:   ids[12] + "AAA",
+		failRecord: ids[3] + "AAA",
+	}
+	resource := byIDResource(t, server, WithChunkConcurrency(2))
+
+	values, err := resource.DeleteByIDs(context.Background(), ids, 10, false)
+	var idsErr *IDsError
+	if errors.As(err, &idsErr) == false {
+		t.Fatalf("Resource.DeleteByIDs() error = %v, want an *IDsError", err)
+	}
+	if len(server.calls) != 3 {
+		t.Errorf("Resource.DeleteByIDs() calls = %d, want every call after the failure", len(server.calls))
+	}
+	if len(values) != len(ids) {
+		t.Fatalf("Resource.DeleteByIDs() = %d values, want %d", len(values), len(ids))
+	}
+	if len(idsErr.Errors) != 11 {
+		t.Errorf("Resource.DeleteByIDs() errors = %d, want 11", len(idsErr.Errors))
+	}
+	var recordErr sfdc.Error
+	if errors.As(idsErr.Errors[ids[3]], &recordErr) == false || recordErr.ErrorCode != "ENTITY_IS_DELETED" {
+		t.Errorf("Resource.DeleteByIDs() error of %s = %v, want the record error", ids[3], idsErr.Errors[ids[3]])
+	}
+	for idx, value := range values {
+		callFailed := idx >= 10 && idx < 20
+		if callFailed {
+			if err := idsErr.Errors[ids[idx]]; err == nil || strings.Contains(err.Error(), "records 10 to 19") == false {
+				t.Errorf("Resource.DeleteByIDs() error of %s = %v, want the error of the second call", ids[idx], err)
+			}
+		}
+		if want := callFailed == false && idx != 3; value.Success != want || value.ID != ids[idx]+"AAA" {
+			t.Errorf("Resource.DeleteByIDs() value %d = %+v, want success %t", idx, value, want)
+		}
+	}
+}
+
+func TestResource_DeleteByIDs_duplicates(t *testing.T) {
+	server := &byIDServer{failRecord: "001000000000001AAA"}
+	resource := byIDResource(t, server)
+
+	ids := []string{
+		"001000000000000",
+		"001000000000001",
+		"001000000000000AAA",
+		"001000000000000",
+		"001000000000001aaa",
+	}
+	values, err := resource.DeleteByIDs(context.Background(), ids, 0, true)
+	var idsErr *IDsError
+	if errors.As(err, &idsErr) == false {
+		t.Fatalf("Resource.DeleteByIDs() error = %v, want an *IDsError", err)
+	}
+	if len(server.calls) != 1 || strings.Join(server.calls[0], ",") != "001000000000000AAA,001000000000001AAA" {
+		t.Errorf("Resource.DeleteByIDs() calls = %v, want the unique IDs once", server.calls)
+	}
+	want := []bool{true, false, true, true, false}
+	for idx, value := range values {
+		if value.Success != want[idx] {
+			t.Errorf("Resource.DeleteByIDs() value %d = %+v, want success %t", idx, value, want[idx])
+		}
+	}
+	if len(idsErr.Errors) != 2 || idsErr.Errors["001000000000001"] == nil || idsErr.Errors["001000000000001aaa"] == nil {
+		t.Errorf("Resource.DeleteByIDs() errors = %v, want the errors of both forms of the failed ID", idsErr.Errors)
+	}
+}
+
+func TestResource_ByIDs_validation(t *testing.T) {
+	tests := []struct {
+		name      string
+		ids       []string
+		chunkSize int
+		allOrNone bool
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "invalid id",
+			ids:  []string{"001000000000000", "001-00000000000"},
+		},
+		{
+			name: "invalid checksum",
+			ids:  []string{"001000000000000ABC"},
+		},
+		{
+			name:      "all or none over the chunk size",
+			ids:       byIDs(11),
+			chunkSize: 10,
+			allOrNone: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &byIDServer{}
+			resource := byIDResource(t, server)
+			if _, err := resource.UpdateByIDs(context.Background(), "Account", tt.ids, map[string]interface{}{"Name": "Acme"}, tt.chunkSize, tt.allOrNone); err == nil {
+				t.Error("Resource.UpdateByIDs() error = nil, want an error")
+			}
+			if _, err := resource.DeleteByIDs(context.Background(), tt.ids, tt.chunkSize, tt.allOrNone); err == nil {
+				t.Error("Resource.DeleteByIDs() error = nil, want an error")
+			}
+			if len(server.calls) != 0 {
+				t.Errorf("calls = %v, want none", server.calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package collections
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// MaxRecords is the most records of a call of the SObject Collections API.
+const MaxRecords = 200
+
+// WithAutoChunk will split the records of Insert, Update, Upsert and Delete
+// into calls of at most the size, which is capped to MaxRecords, and return
+// the values of the calls in the order of the records.  The calls are sent one
+// at a time, unless WithChunkConcurrency is used.  An all or none operation
+// can not span calls, so it is an error when it has more records than the
+// size.  Without the option, the records are sent in a single call.
+//
+// When a call fails, the calls that are not sent are canceled, and a
+// *ChunkError is returned with the values.  The calls that were already sent
+// are not canceled, since their records may have been written.  The records of
+// the calls that succeeded are not rolled back, so their values are kept, and
+// the values of the records of the failed and canceled calls are zero values.
+func WithAutoChunk(size int) Option {
+	return func(r *Resource) {
+		if size <= 0 || size > MaxRecords {
+			size = MaxRecords
+		}
+		r.chunkSize = size
+	}
+}
+
+// WithChunkConcurrency will send up to the limit of the calls of WithAutoChunk
+// at once.  A limit of one or less sends them one at a time.
+func WithChunkConcurrency(limit int) Option {
+	return func(r *Resource) {
+		r.chunkConcurrency = limit
+	}
+}
+
+// ChunkError is returned with the values of the chunked calls when some of
+// the calls failed or were not sent.
+//
+// Operation is the operation of the calls, like insert.
+//
+// Chunks are the calls that failed or were not sent, in the order of the
+// records.
+type ChunkError struct {
+	Operation string
+	Chunks    []ChunkFailure
+}
+
+// ChunkFailure is a chunked call that failed or was not sent.
+//
+// Start and End are the indexes of the call's first and last records.
+//
+// Err is the error of the call, or of the context when the call was not sent.
+type ChunkFailure struct {
+	Start int
+	End   int
+	Err   error
+}
+
+func (e *ChunkError) Error() string {
+	first := e.Chunks[0]
+	msg := fmt.Sprintf("collections resource: %s of records %d to %d: %s", e.Operation, first.Start, first.End, first.Err.Error())
+	if len(e.Chunks) > 1 {
+		msg += fmt.Sprintf(" (and %d more calls)", len(e.Chunks)-1)
+	}
+	return msg
+}
+
+// Unwrap returns the error of the first failed call.
+func (e *ChunkError) Unwrap() error {
+	return e.Chunks[0].Err
+}
+
+// chunkCalls sends the records in calls of the resource's chunk size, and
+// returns the values of the calls in the order of the records.
+func chunkCalls[R, V any](ctx context.Context, r *Resource, operation string, allOrNone bool, records []R, call func(context.Context, []R) ([]V, error)) ([]V, error) {
+	size := r.chunkSize
+	if size == 0 || len(records) <= size {
+		return call(ctx, records)
+	}
+	if allOrNone {
+		return nil, fmt.Errorf("collections resource: %s of %d records with all or none can not be split into calls of %d records", operation, len(records), size)
+	}
+	return sendChunks(ctx, operation, size, r.chunkConcurrency, true, records, call)
+}
+
+// sendChunks sends the records in calls of the size, up to the concurrency
+// at once, and returns a value for each of the records in their order.  When
+// calls fail or are not sent, a *ChunkError is returned with the values, and
+// the values of their records are zero values.  A call that returns a value
+// count other than its record count fails.  If stopOnError, the calls that are
+// not sent yet are canceled once a call fails.  The calls in flight keep the
+// caller's context, so they finish and their values are kept.
+func sendChunks[R, V any](ctx context.Context, operation string, size, concurrency int, stopOnError bool, records []R, call func(context.Context, []R) ([]V, error)) ([]V, error) {
+	dispatch, stop := context.WithCancel(ctx)
+	defer stop()
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	values := make([]V, len(records))
+	chunkErrs := make([]error, (len(records)+size-1)/size)
+	limit := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for idx := range chunkErrs {
+		start := idx * size
+		end := start + size
+		if end > len(records) {
+			end = len(records)
+		}
+		select {
+		case limit <- struct{}{}:
+		case <-dispatch.Done():
+		}
+		if err := dispatch.Err(); err != nil {
+			chunkErrs[idx] = err
+			continue
+		}
+		wg.Add(1)
+		go func(idx, start int, chunk []R) {
+			defer wg.Done()
+			defer func() { <-limit }()
+			chunkValues, err := call(ctx, chunk)
+			if err == nil && len(chunkValues) != len(chunk) {
+				err = fmt.Errorf("%d values for %d records", len(chunkValues), len(chunk))
+			}
+			if err != nil {
+				chunkErrs[idx] = err
+				if stopOnError {
+					stop()
+				}
+				return
+			}
+			copy(values[start:], chunkValues)
+		}(idx, start, records[start:end])
+	}
+	wg.Wait()
+
+	var failures []ChunkFailure
+	for idx, err := range chunkErrs {
+		if err == nil {
+			continue
+		}
+		start := idx * size
+		end := start + size
+		if end > len(records) {
+			end = len(records)
+		}
+		failures = append(failures, ChunkFailure{
+			Start: start,
+			End:   end - 1,
+			Err:   err,
+		})
+	}
+	if len(failures) > 0 {
+		return values, &ChunkError{
+			Operation: operation,
+			Chunks:    failures,
+		}
+	}
+	return values, nil
+}
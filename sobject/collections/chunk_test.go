@@ -0,0 +1,259 @@
+package collections
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/namely/go-sfdc/v3/sobject"
+)
+
+// chunkServer answers the insert and delete calls with a value for each record,
+// whose ID is the record's Name or ID, and records the sizes of the calls.
+type chunkServer struct {
+	mu      sync.Mutex
+	sizes   []int
+	active  int
+	maxSeen int
+	fail    string
+}
+
+func (s *chunkServer) client(t *testing.T) *http.Client {
+	return mockHTTPClient(func(req *http.Request) *http.Response {
+		s.mu.Lock()
+		s.active++
+		if s.active > s.maxSeen {
+			s.maxSeen = s.active
+		}
+		s.mu.Unlock()
+		time.Sleep(time.Millisecond)
+		defer func() {
+			s.mu.Lock()
+			s.active--
+			s.mu.Unlock()
+		}()
+
+		var ids []string
+		if req.Method == http.MethodDelete {
+			ids = strings.Split(req.URL.Query().Get("ids"), ",")
+		} else {
+			var payload struct {
+				Records []map[string]interface{} `json:"records"`
+			}
+			if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+				t.Errorf("payload error = %v", err)
+			}
+			for _, record := range payload.Records {
+				ids = append(ids, record["Name"].(string))
+			}
+		}
+		s.mu.Lock()
+		s.sizes = append(s.sizes, len(ids))
+		s.mu.Unlock()
+
+		values := make([]sobject.InsertValue, len(ids))
+		for idx, id := range ids {
+			if id == s.fail {
+				return &http.Response{
+					StatusCode: http.StatusBadRequest,
+					Status:     "400 Bad Request",
+					Body:       io.NopCloser(strings.NewReader(`[{"errorCode":"INVALID_ID_FIELD","message":"bad id"}]`)),
+					Header:     make(http.Header),
+				}
+			}
+			values[idx] = sobject.InsertValue{Success: true, ID: id}
+		}
+		body, _ := json.Marshal(values)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(string(body))),
+			Header:     make(http.Header),
+		}
+	})
+}
+
+func chunkResource(t *testing.T, server *chunkServer, options ...Option) *Resource {
+	t.Helper()
+	resource, err := NewResources(&mockSessionFormatter{
+		url:    "https://test.salesforce.com",
+		client: server.client(t),
+	}, options...)
+	if err != nil {
+		t.Fatalf("NewResources() error = %v", err)
+	}
+	return resource
+}
+
+func chunkInserters(count int) []sobject.Inserter {
+	records := make([]sobject.Inserter, count)
+	for idx := range records {
+		records[idx] = &mockInserter{
+			sobject: "Account",
+			fields:  map[string]interface{}{"Name": fmt.Sprintf("A%03d", idx)},
+		}
+	}
+	return records
+}
+
+func TestResource_InsertContext_autoChunk(t *testing.T) {
+	tests := []struct {
+		name           string
+		options        []Option
+		records        int
+		wantSizes      []int
+		maxConcurrency int
+	}{
+		{
+			name:      "one call without the option",
+			records:   250,
+			wantSizes: []int{250},
+		},
+		{
+			name:      "sequential chunks",
+			options:   []Option{WithAutoChunk(200)},
+			records:   450,
+			wantSizes: []int{200, 200, 50},
+		},
+		{
+			name:      "size capped to the maximum",
+			options:   []Option{WithAutoChunk(500)},
+			records:   401,
+			wantSizes: []int{200, 200, 1},
+		},
+		{
+			name:           "concurrent chunks",
+			options:        []Option{WithAutoChunk(10), WithChunkConcurrency(3)},
+			records:        95,
+			maxConcurrency: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &chunkServer{}
+			resource := chunkResource(t, server, tt.options...)
+
+			values, err := resource.InsertContext(context.Background(), false, chunkInserters(tt.records))
+			if err != nil {
+				t.Fatalf("Resource.InsertContext() error = %v", err)
+			}
+			if len(values) != tt.records {
+				t.Fatalf("Resource.InsertContext() = %d values, want %d", len(values), tt.records)
+			}
+			for idx, value := range values {
+				if want := fmt.Sprintf("A%03d", idx); value.ID != want {
+					t.Fatalf("Resource.InsertContext() value %d = %s, want %s", idx, value.ID, want)
+				}
+			}
+			if tt.wantSizes != nil && fmt.Sprint(server.sizes) != fmt.Sprint(tt.wantSizes) {
+				t.Errorf("Resource.InsertContext() calls = %v, want %v", server.sizes, tt.wantSizes)
+			}
+			if tt.maxConcurrency == 0 && server.maxSeen > 1 {
+				t.Errorf("Resource.InsertContext() sent %d calls at once, want 1", server.maxSeen)
+			}
+			if tt.maxConcurrency > 0 && server.maxSeen > tt.maxConcurrency {
+				t.Errorf("Resource.InsertContext() sent %d calls at once, want at most %d", server.maxSeen, tt.maxConcurrency)
+			}
+		})
+	}
+}
+
+func TestResource_autoChunk_allOrNone(t *testing.T) {
+	server := &chunkServer{}
+	resource := chunkResource(t, server, WithAutoChunk(200))
+
+	if _, err := resource.InsertContext(context.Background(), true, chunkInserters(201)); err == nil {
+		t.Error("Resource.InsertContext() error = nil, want an error for all or none")
+	}
+	if len(server.sizes) != 0 {
+		t.Errorf("Resource.InsertContext() sent %v, want no calls", server.sizes)
+	}
+	values, err := resource.InsertContext(context.Background(), true, chunkInserters(200))
+	if err != nil || len(values) != 200 {
+		t.Errorf("Resource.InsertContext() = %d values, %v, want 200 in one call", len(values), err)
+	}
+}
+
+func TestResource_DeleteContext_autoChunkError(t *testing.T) {
+	ids := make([]string, 25)
+	for idx := range ids {
+		ids[idx] = fmt.Sprintf("001%03d", idx)
+	}
+	for _, concurrency := range []int{1, 4} {
+		t.Run(fmt.Sprint(concurrency), func(t *testing.T) {
+			server := &chunkServer{fail: "001012"}
+			resource := chunkResource(t, server, WithAutoChunk(10), WithChunkConcurrency(concurrency))
+
+			values, err := resource.DeleteContext(context.Background(), false, ids)
+			if err == nil || strings.Contains(err.Error(), "records 10 to 19") == false {
+				t.Fatalf("Resource.DeleteContext() error = %v, want the error of the second call", err)
+			}
+			var chunkErr *ChunkError
+			if errors.As(err, &chunkErr) == false || chunkErr.Chunks[0].Start != 10 || chunkErr.Chunks[0].End != 19 {
+				t.Fatalf("Resource.DeleteContext() error = %#v, want a *ChunkError of the second call", err)
+			}
+			if len(values) != len(ids) {
+				t.Fatalf("Resource.DeleteContext() = %d values, want %d", len(values), len(ids))
+			}
+			failed := make(map[int]bool)
+			for _, chunk := range chunkErr.Chunks {
+				for idx := chunk.Start; idx <= chunk.End; idx++ {
+					failed[idx] = true
+				}
+			}
+			for idx, value := range values {
+				if failed[idx] == (value.Success || value.ID == ids[idx]) {
+					t.Errorf("Resource.DeleteContext() value %d = %+v, failed %t", idx, value, failed[idx])
+				}
+			}
+			if concurrency == 1 {
+				if len(server.sizes) != 2 {
+					t.Errorf("Resource.DeleteContext() calls = %v, want the calls up to the error", server.sizes)
+				}
+				if len(chunkErr.Chunks) != 2 || errors.Is(chunkErr.Chunks[1].Err, context.Canceled) == false {
+					t.Errorf("Resource.DeleteContext() chunks = %+v, want the canceled third call", chunkErr.Chunks)
+				}
+			}
+		})
+	}
+}
+
+func TestSendChunks_inFlightNotCanceled(t *testing.T) {
+	failed := make(chan struct{})
+	records := []int{0, 1, 2, 3, 4, 5}
+	values, err := sendChunks(context.Background(), "insert", 2, 2, true, records, func(ctx context.Context, chunk []int) ([]int, error) {
+		switch chunk[0] {
+		case 0:
+			select {
+			case <-failed:
+			case <-time.After(time.Second):
+				return nil, errors.New("the second call did not fail")
+			}
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			return []int{10, 11}, nil
+		case 2:
+			close(failed)
+			return nil, errors.New("failed")
+		default:
+			return chunk, nil
+		}
+	})
+	var chunkErr *ChunkError
+	if errors.As(err, &chunkErr) == false {
+		t.Fatalf("sendChunks() error = %v, want a *ChunkError", err)
+	}
+	if len(chunkErr.Chunks) != 2 || chunkErr.Chunks[0].Start != 2 || errors.Is(chunkErr.Chunks[1].Err, context.Canceled) == false {
+		t.Errorf("sendChunks() chunks = %+v, want the failed second call and the canceled third call", chunkErr.Chunks)
+	}
+	if values[0] != 10 || values[1] != 11 {
+		t.Errorf("sendChunks() values = %v, want the values of the call in flight", values)
+	}
+}
@@ -3,12 +3,14 @@ package collections
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"regexp"
+	"strings"
 
 	"github.com/namely/go-sfdc/v3"
 	"github.com/namely/go-sfdc/v3/session"
@@ -32,6 +34,7 @@ type collection struct {
 	values      *url.Values
 	body        io.Reader
 	contentType string
+	header      http.Header
 }
 
 // Resource is the structure for the SObject Collections API.
@@ -39,77 +42,156 @@ type Resource struct {
 	update *update
 	query  *query
 	insert *insert
+	upsert *upsert
 	remove *remove
+	volume *volume
+
+	chunkSize        int
+	chunkConcurrency int
 }
 
 // NewResources forms the Salesforce SObject Collections resource structure.  The
 // session formatter is required to form the proper URLs and authorization
-// header.
-func NewResources(session session.ServiceFormatter) (*Resource, error) {
-	if session == nil {
+// header.  The options are optional.
+func NewResources(formatter session.ServiceFormatter, options ...Option) (*Resource, error) {
+	if formatter == nil {
 		return nil, errors.New("collections: session can not be nil")
 	}
 
-	err := session.Refresh()
+	err := session.RefreshResource(formatter)
 	if err != nil {
 		return nil, errors.Wrap(err, "session refresh")
 	}
 
-	return &Resource{
+	resource := &Resource{
 		update: &update{
-			session: session,
+			session: formatter,
 		},
 		query: &query{
-			session: session,
+			session: formatter,
 		},
 		insert: &insert{
-			session: session,
+			session: formatter,
+		},
+		upsert: &upsert{
+			session: formatter,
 		},
 		remove: &remove{
-			session: session,
+			session: formatter,
 		},
-	}, nil
+	}
+	for _, option := range options {
+		option(resource)
+	}
+	return resource, nil
 }
 
 // Insert will create a group of records in the Salesforce org.  The records do not need to be
 // the same SObject.  It is the responsibility of the caller to properly chunck the records.
 func (r *Resource) Insert(allOrNone bool, records []sobject.Inserter) ([]sobject.InsertValue, error) {
+	return r.InsertContext(context.Background(), allOrNone, records)
+}
+
+// InsertContext is Insert using the context for the request.
+func (r *Resource) InsertContext(ctx context.Context, allOrNone bool, records []sobject.Inserter) ([]sobject.InsertValue, error) {
 	if r.insert == nil {
 		return nil, errors.New("collections resource: collections may not have been initialized properly")
 	}
 	if records == nil {
 		return nil, errors.New("collections resource: insert records can not be nil")
 	}
-	return r.insert.callout(allOrNone, records)
+	r.volume.add("insert", len(records))
+	return chunkCalls(ctx, r, "insert", allOrNone, records, func(ctx context.Context, records []sobject.Inserter) ([]sobject.InsertValue, error) {
+		return r.insert.callout(ctx, allOrNone, records)
+	})
+}
+
+// Upsert will insert or update a group of records in the Salesforce org, matching them with
+// the external ID field.  The records must be the same SObject, and the ID of each record is
+// its external ID.  It is the responsibility of the caller to properly chunck the records.
+func (r *Resource) Upsert(allOrNone bool, sobjectName, externalIDField string, records []sobject.Upserter) ([]UpsertValue, error) {
+	return r.UpsertContext(context.Background(), allOrNone, sobjectName, externalIDField, records)
+}
+
+// UpsertContext is Upsert using the context for the request.
+func (r *Resource) UpsertContext(ctx context.Context, allOrNone bool, sobjectName, externalIDField string, records []sobject.Upserter) ([]UpsertValue, error) {
+	if r.upsert == nil {
+		return nil, errors.New("collections resource: collections may not have been initialized properly")
+	}
+	if records == nil {
+		return nil, errors.New("collections resource: upsert records can not be nil")
+	}
+	if sobjectName == "" {
+		return nil, errors.New("collections resource: upsert sobject can not be empty")
+	}
+	if externalIDField == "" {
+		return nil, errors.New("collections resource: upsert external ID field can not be empty")
+	}
+	for idx, record := range records {
+		if record == nil {
+			return nil, fmt.Errorf("collections resource: upsert record %d can not be nil", idx)
+		}
+		if strings.EqualFold(record.SObject(), sobjectName) == false {
+			return nil, fmt.Errorf("collections resource: upsert record %d is %s, not %s", idx, record.SObject(), sobjectName)
+		}
+		if field := record.ExternalField(); field != "" && strings.EqualFold(field, externalIDField) == false {
+			return nil, fmt.Errorf("collections resource: upsert record %d external ID field is %s, not %s", idx, field, externalIDField)
+		}
+	}
+	r.volume.add("upsert", len(records))
+	return chunkCalls(ctx, r, "upsert", allOrNone, records, func(ctx context.Context, records []sobject.Upserter) ([]UpsertValue, error) {
+		return r.upsert.callout(ctx, allOrNone, sobjectName, externalIDField, records)
+	})
 }
 
 // Delete will remove a group of records in the Salesforce org.  The records do not need to
 // be the same SObject.
 func (r *Resource) Delete(allOrNone bool, records []string) ([]DeleteValue, error) {
+	return r.DeleteContext(context.Background(), allOrNone, records)
+}
+
+// DeleteContext is Delete using the context for the request.
+func (r *Resource) DeleteContext(ctx context.Context, allOrNone bool, records []string) ([]DeleteValue, error) {
 	if r.remove == nil {
 		return nil, errors.New("collections resource: collections may not have been initialized properly")
 	}
 	if records == nil {
 		return nil, errors.New("collections resource: delete records can not be nil")
 	}
-	return r.remove.callout(allOrNone, records)
+	r.volume.add("delete", len(records))
+	return chunkCalls(ctx, r, "delete", allOrNone, records, func(ctx context.Context, records []string) ([]DeleteValue, error) {
+		return r.remove.callout(ctx, allOrNone, records)
+	})
 }
 
 // Update will update a group of records in the Salesforce org.  The records do not need to be
 // the same SObject.  It is the responsibility of the caller to properly chunck the records.
 func (r *Resource) Update(allOrNone bool, records []sobject.Updater) ([]UpdateValue, error) {
+	return r.UpdateContext(context.Background(), allOrNone, records)
+}
+
+// UpdateContext is Update using the context for the request.
+func (r *Resource) UpdateContext(ctx context.Context, allOrNone bool, records []sobject.Updater) ([]UpdateValue, error) {
 	if r.update == nil {
 		return nil, errors.New("collections resource: collections may not have been initialized properly")
 	}
 	if records == nil {
 		return nil, errors.New("collections resource: update records can not be nil")
 	}
-	return r.update.callout(allOrNone, records)
+	r.volume.add("update", len(records))
+	return chunkCalls(ctx, r, "update", allOrNone, records, func(ctx context.Context, records []sobject.Updater) ([]UpdateValue, error) {
+		return r.update.callout(ctx, allOrNone, records)
+	})
 }
 
 // Query will retrieve a group of records from the Salesforce org.  The records to retrieve must
 // be the same SObject.
-func (r *Resource) Query(sobject string, records []sobject.Querier) ([]*sfdc.Record, error) {
+func (r *Resource) Query(sobject string, records []sobject.Querier, options ...QueryOption) ([]*sfdc.Record, error) {
+	return r.QueryContext(context.Background(), sobject, records, options...)
+}
+
+// QueryContext is Query using the context for the request.
+func (r *Resource) QueryContext(ctx context.Context, sobject string, records []sobject.Querier, options ...QueryOption) ([]*sfdc.Record, error) {
 	if r.query == nil {
 		return nil, errors.New("collections resource: collections may not have been initialized properly")
 	}
@@ -126,15 +208,19 @@ func (r *Resource) Query(sobject string, records []sobject.Querier) ([]*sfdc.Rec
 		return nil, fmt.Errorf("collection resource: %s is not a valid sobject", sobject)
 	}
 
-	return r.query.callout(sobject, records)
+	var opts queryOptions
+	for _, option := range options {
+		option(&opts)
+	}
+	return r.query.callout(ctx, sobject, records, opts)
 }
 
-func (c *collection) send(session session.ServiceFormatter, value interface{}) error {
+func (c *collection) send(ctx context.Context, session session.ServiceFormatter, value interface{}) error {
 	collectionURL := session.ServiceURL() + c.endpoint
 	if c.values != nil {
 		collectionURL += "?" + c.values.Encode()
 	}
-	request, err := http.NewRequest(c.method, collectionURL, c.body)
+	request, err := http.NewRequestWithContext(ctx, c.method, collectionURL, c.body)
 	if err != nil {
 		return err
 	}
@@ -143,6 +229,11 @@ func (c *collection) send(session session.ServiceFormatter, value interface{}) e
 	if c.contentType != "" {
 		request.Header.Add("Content-Type", c.contentType)
 	}
+	for key, values := range c.header {
+		for _, value := range values {
+			request.Header.Add(key, value)
+		}
+	}
 	session.AuthorizationHeader(request)
 
 	response, err := session.Client().Do(request)
@@ -151,7 +242,7 @@ func (c *collection) send(session session.ServiceFormatter, value interface{}) e
 	}
 
 	decoder := json.NewDecoder(response.Body)
-	defer response.Body.Close()
+	defer sfdc.CloseResponse(response)
 
 	if response.StatusCode != http.StatusOK {
 		var insertErrs []sfdc.Error
@@ -1,8 +1,8 @@
 package collections
 
 import (
+	"context"
 	"io"
-	"io/ioutil"
 	"net/http"
 	"net/url"
 	"reflect"
@@ -51,7 +51,7 @@ func Test_collection_send(t *testing.T) {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "Bad URL: " + req.URL.String(),
-								Body:       ioutil.NopCloser(strings.NewReader("resp")),
+								Body:       io.NopCloser(strings.NewReader("resp")),
 								Header:     make(http.Header),
 							}
 						}
@@ -60,7 +60,7 @@ func Test_collection_send(t *testing.T) {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "Bad Method",
-								Body:       ioutil.NopCloser(strings.NewReader("resp")),
+								Body:       io.NopCloser(strings.NewReader("resp")),
 								Header:     make(http.Header),
 							}
 						}
@@ -71,7 +71,7 @@ func Test_collection_send(t *testing.T) {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "No one value",
-								Body:       ioutil.NopCloser(strings.NewReader("resp")),
+								Body:       io.NopCloser(strings.NewReader("resp")),
 								Header:     make(http.Header),
 							}
 						}
@@ -80,7 +80,7 @@ func Test_collection_send(t *testing.T) {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "No two value",
-								Body:       ioutil.NopCloser(strings.NewReader("resp")),
+								Body:       io.NopCloser(strings.NewReader("resp")),
 								Header:     make(http.Header),
 							}
 						}
@@ -94,7 +94,7 @@ func Test_collection_send(t *testing.T) {
 						return &http.Response{
 							StatusCode: http.StatusOK,
 							Status:     "Some Status",
-							Body:       ioutil.NopCloser(strings.NewReader(resp)),
+							Body:       io.NopCloser(strings.NewReader(resp)),
 							Header:     make(http.Header),
 						}
 					}),
@@ -123,7 +123,7 @@ func Test_collection_send(t *testing.T) {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "Bad URL: " + req.URL.String(),
-								Body:       ioutil.NopCloser(strings.NewReader("resp")),
+								Body:       io.NopCloser(strings.NewReader("resp")),
 								Header:     make(http.Header),
 							}
 						}
@@ -132,7 +132,7 @@ func Test_collection_send(t *testing.T) {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "Bad Method",
-								Body:       ioutil.NopCloser(strings.NewReader("resp")),
+								Body:       io.NopCloser(strings.NewReader("resp")),
 								Header:     make(http.Header),
 							}
 						}
@@ -143,7 +143,7 @@ func Test_collection_send(t *testing.T) {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "No one value",
-								Body:       ioutil.NopCloser(strings.NewReader("resp")),
+								Body:       io.NopCloser(strings.NewReader("resp")),
 								Header:     make(http.Header),
 							}
 						}
@@ -152,7 +152,7 @@ func Test_collection_send(t *testing.T) {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "No two value",
-								Body:       ioutil.NopCloser(strings.NewReader("resp")),
+								Body:       io.NopCloser(strings.NewReader("resp")),
 								Header:     make(http.Header),
 							}
 						}
@@ -168,7 +168,7 @@ func Test_collection_send(t *testing.T) {
 						return &http.Response{
 							StatusCode: http.StatusConflict,
 							Status:     "Some Status",
-							Body:       ioutil.NopCloser(strings.NewReader(resp)),
+							Body:       io.NopCloser(strings.NewReader(resp)),
 							Header:     make(http.Header),
 						}
 					}),
@@ -186,7 +186,7 @@ func Test_collection_send(t *testing.T) {
 				values:   tt.fields.values,
 				body:     tt.fields.body,
 			}
-			if err := c.send(tt.args.session, tt.args.value); (err != nil) != tt.wantErr {
+			if err := c.send(context.Background(), tt.args.session, tt.args.value); (err != nil) != tt.wantErr {
 				t.Errorf("collection.send() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -226,6 +226,11 @@ func TestNewResource(t *testing.T) {
 						url: "some.url.com",
 					},
 				},
+				upsert: &upsert{
+					session: &mockSessionFormatter{
+						url: "some.url.com",
+					},
+				},
 				remove: &remove{
 					session: &mockSessionFormatter{
 						url: "some.url.com",
@@ -282,7 +287,7 @@ func TestResource_Update(t *testing.T) {
 								return &http.Response{
 									StatusCode: 500,
 									Status:     "Bad URL: " + req.URL.String(),
-									Body:       ioutil.NopCloser(strings.NewReader("resp")),
+									Body:       io.NopCloser(strings.NewReader("resp")),
 									Header:     make(http.Header),
 								}
 							}
@@ -291,7 +296,7 @@ func TestResource_Update(t *testing.T) {
 								return &http.Response{
 									StatusCode: 500,
 									Status:     "Bad Method",
-									Body:       ioutil.NopCloser(strings.NewReader("resp")),
+									Body:       io.NopCloser(strings.NewReader("resp")),
 									Header:     make(http.Header),
 								}
 							}
@@ -318,7 +323,7 @@ func TestResource_Update(t *testing.T) {
 							return &http.Response{
 								StatusCode: http.StatusOK,
 								Status:     "Some Status",
-								Body:       ioutil.NopCloser(strings.NewReader(resp)),
+								Body:       io.NopCloser(strings.NewReader(resp)),
 								Header:     make(http.Header),
 							}
 						}),
@@ -428,7 +433,7 @@ func TestResource_Query(t *testing.T) {
 								return &http.Response{
 									StatusCode: 500,
 									Status:     "Bad URL: " + req.URL.String(),
-									Body:       ioutil.NopCloser(strings.NewReader("resp")),
+									Body:       io.NopCloser(strings.NewReader("resp")),
 									Header:     make(http.Header),
 								}
 							}
@@ -437,7 +442,7 @@ func TestResource_Query(t *testing.T) {
 								return &http.Response{
 									StatusCode: 500,
 									Status:     "Bad Method",
-									Body:       ioutil.NopCloser(strings.NewReader("resp")),
+									Body:       io.NopCloser(strings.NewReader("resp")),
 									Header:     make(http.Header),
 								}
 							}
@@ -466,7 +471,7 @@ func TestResource_Query(t *testing.T) {
 							return &http.Response{
 								StatusCode: http.StatusOK,
 								Status:     "Some Status",
-								Body:       ioutil.NopCloser(strings.NewReader(resp)),
+								Body:       io.NopCloser(strings.NewReader(resp)),
 								Header:     make(http.Header),
 							}
 						}),
@@ -570,7 +575,7 @@ func TestResource_Insert(t *testing.T) {
 								return &http.Response{
 									StatusCode: 500,
 									Status:     "Bad URL: " + req.URL.String(),
-									Body:       ioutil.NopCloser(strings.NewReader("resp")),
+									Body:       io.NopCloser(strings.NewReader("resp")),
 									Header:     make(http.Header),
 								}
 							}
@@ -579,7 +584,7 @@ func TestResource_Insert(t *testing.T) {
 								return &http.Response{
 									StatusCode: 500,
 									Status:     "Bad Method",
-									Body:       ioutil.NopCloser(strings.NewReader("resp")),
+									Body:       io.NopCloser(strings.NewReader("resp")),
 									Header:     make(http.Header),
 								}
 							}
@@ -606,7 +611,7 @@ func TestResource_Insert(t *testing.T) {
 							return &http.Response{
 								StatusCode: http.StatusOK,
 								Status:     "Some Status",
-								Body:       ioutil.NopCloser(strings.NewReader(resp)),
+								Body:       io.NopCloser(strings.NewReader(resp)),
 								Header:     make(http.Header),
 							}
 						}),
@@ -718,7 +723,7 @@ func TestResource_Delete(t *testing.T) {
 								return &http.Response{
 									StatusCode: 500,
 									Status:     "Bad URL: " + req.URL.String(),
-									Body:       ioutil.NopCloser(strings.NewReader("resp")),
+									Body:       io.NopCloser(strings.NewReader("resp")),
 									Header:     make(http.Header),
 								}
 							}
@@ -727,7 +732,7 @@ func TestResource_Delete(t *testing.T) {
 								return &http.Response{
 									StatusCode: 500,
 									Status:     "Bad Method",
-									Body:       ioutil.NopCloser(strings.NewReader("resp")),
+									Body:       io.NopCloser(strings.NewReader("resp")),
 									Header:     make(http.Header),
 								}
 							}
@@ -738,7 +743,7 @@ func TestResource_Delete(t *testing.T) {
 								return &http.Response{
 									StatusCode: 500,
 									Status:     "allOrNone",
-									Body:       ioutil.NopCloser(strings.NewReader("resp")),
+									Body:       io.NopCloser(strings.NewReader("resp")),
 									Header:     make(http.Header),
 								}
 							}
@@ -747,7 +752,7 @@ func TestResource_Delete(t *testing.T) {
 								return &http.Response{
 									StatusCode: 500,
 									Status:     "ids",
-									Body:       ioutil.NopCloser(strings.NewReader("resp")),
+									Body:       io.NopCloser(strings.NewReader("resp")),
 									Header:     make(http.Header),
 								}
 							}
@@ -773,7 +778,7 @@ func TestResource_Delete(t *testing.T) {
 							return &http.Response{
 								StatusCode: http.StatusOK,
 								Status:     "Some Status",
-								Body:       ioutil.NopCloser(strings.NewReader(resp)),
+								Body:       io.NopCloser(strings.NewReader(resp)),
 								Header:     make(http.Header),
 							}
 						}),
@@ -0,0 +1,50 @@
+package collections
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/namely/go-sfdc/v3/sobject"
+)
+
+type contextKey struct{}
+
+func TestResource_InsertContext(t *testing.T) {
+	ctx := context.WithValue(context.Background(), contextKey{}, "insert")
+	body := newTrackingBody(`[{"errorCode": "INVALID_FIELD", "message": "bad request"}]`)
+	var got context.Context
+	r := &Resource{
+		insert: &insert{
+			session: &mockSessionFormatter{
+				url: "https://test.salesforce.com",
+				client: mockHTTPClient(func(req *http.Request) *http.Response {
+					got = req.Context()
+					return &http.Response{
+						StatusCode: http.StatusBadRequest,
+						Status:     "400 Bad Request",
+						Body:       body,
+						Header:     make(http.Header),
+					}
+				}),
+			},
+		},
+	}
+	_, err := r.InsertContext(ctx, true, []sobject.Inserter{
+		&mockInserter{
+			sobject: "Account",
+			fields: map[string]interface{}{
+				"Name": "name",
+			},
+		},
+	})
+	if err == nil {
+		t.Fatalf("Resource.InsertContext() error = nil, want error")
+	}
+	if got == nil || got.Value(contextKey{}) != "insert" {
+		t.Errorf("Resource.InsertContext() request context was not the context")
+	}
+	if body.closed == false {
+		t.Errorf("Resource.InsertContext() response body was not closed")
+	}
+}
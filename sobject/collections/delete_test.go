@@ -1,7 +1,8 @@
 package collections
 
 import (
-	"io/ioutil"
+	"context"
+	"io"
 	"net/http"
 	"net/url"
 	"reflect"
@@ -81,7 +82,7 @@ func TestDelete_Callout(t *testing.T) {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "Bad URL: " + req.URL.String(),
-								Body:       ioutil.NopCloser(strings.NewReader("resp")),
+								Body:       io.NopCloser(strings.NewReader("resp")),
 								Header:     make(http.Header),
 							}
 						}
@@ -90,7 +91,7 @@ func TestDelete_Callout(t *testing.T) {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "Bad Method",
-								Body:       ioutil.NopCloser(strings.NewReader("resp")),
+								Body:       io.NopCloser(strings.NewReader("resp")),
 								Header:     make(http.Header),
 							}
 						}
@@ -101,7 +102,7 @@ func TestDelete_Callout(t *testing.T) {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "allOrNone",
-								Body:       ioutil.NopCloser(strings.NewReader("resp")),
+								Body:       io.NopCloser(strings.NewReader("resp")),
 								Header:     make(http.Header),
 							}
 						}
@@ -110,7 +111,7 @@ func TestDelete_Callout(t *testing.T) {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "ids",
-								Body:       ioutil.NopCloser(strings.NewReader("resp")),
+								Body:       io.NopCloser(strings.NewReader("resp")),
 								Header:     make(http.Header),
 							}
 						}
@@ -136,7 +137,7 @@ func TestDelete_Callout(t *testing.T) {
 						return &http.Response{
 							StatusCode: http.StatusOK,
 							Status:     "Some Status",
-							Body:       ioutil.NopCloser(strings.NewReader(resp)),
+							Body:       io.NopCloser(strings.NewReader(resp)),
 							Header:     make(http.Header),
 						}
 					}),
@@ -171,7 +172,7 @@ func TestDelete_Callout(t *testing.T) {
 			d := &remove{
 				session: tt.fields.session,
 			}
-			got, err := d.callout(tt.args.allOrNone, tt.args.records)
+			got, err := d.callout(context.Background(), tt.args.allOrNone, tt.args.records)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Delete.Callout() error = %v, wantErr %v", err, tt.wantErr)
 				return
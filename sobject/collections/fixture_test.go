@@ -0,0 +1,73 @@
+package collections
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/namely/go-sfdc/v3/internal/fixture"
+	"github.com/namely/go-sfdc/v3/sobject"
+)
+
+func TestInsertValue_fixtures(t *testing.T) {
+	tests := []struct {
+		version string
+	}{
+		{
+			version: "v42.0",
+		},
+		{
+			version: "v50.0",
+		},
+		{
+			version: "v58.0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			data := fixture.Load(t, tt.version, "insert")
+			report, err := fixture.Check(data, reflect.TypeOf([]sobject.InsertValue{}))
+			if err != nil {
+				t.Fatalf("fixture.Check() error = %v", err)
+			}
+			if len(report.Mismatches) != 0 {
+				t.Errorf("fixture.Check() mismatches = %v", report.Mismatches)
+			}
+			// sfdc.Error decodes itself, so the error's keys are not checked
+			if len(report.Unknown) != 0 {
+				t.Errorf("fixture.Check() unknown = %v", report.Unknown)
+			}
+			var want []sobject.InsertValue
+			fixture.Decode(t, data, &want)
+
+			i := &insert{
+				session: &mockSessionFormatter{
+					url: "https://test.salesforce.com/services/data/" + tt.version,
+					client: mockHTTPClient(func(req *http.Request) *http.Response {
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Body:       io.NopCloser(bytes.NewReader(data)),
+							Header:     make(http.Header),
+						}
+					}),
+				},
+			}
+			got, err := i.callout(context.Background(), false, []sobject.Inserter{
+				&mockInserter{sobject: "Account", fields: map[string]interface{}{"Name": "Acme"}},
+				&mockInserter{sobject: "Account", fields: map[string]interface{}{}},
+			})
+			if err != nil {
+				t.Fatalf("insert.callout() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("insert.callout() = %+v, want %+v", got, want)
+			}
+			if len(got) != 2 || got[0].Success == false || got[1].Success || got[1].Errors[0].ErrorCode != "REQUIRED_FIELD_MISSING" {
+				t.Errorf("insert.callout() = %+v", got)
+			}
+		})
+	}
+}
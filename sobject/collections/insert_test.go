@@ -1,7 +1,8 @@
 package collections
 
 import (
-	"io/ioutil"
+	"context"
+	"io"
 	"net/http"
 	"reflect"
 	"strings"
@@ -103,7 +104,7 @@ func TestInsert_Callout(t *testing.T) {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "Bad URL: " + req.URL.String(),
-								Body:       ioutil.NopCloser(strings.NewReader("resp")),
+								Body:       io.NopCloser(strings.NewReader("resp")),
 								Header:     make(http.Header),
 							}
 						}
@@ -112,7 +113,7 @@ func TestInsert_Callout(t *testing.T) {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "Bad Method",
-								Body:       ioutil.NopCloser(strings.NewReader("resp")),
+								Body:       io.NopCloser(strings.NewReader("resp")),
 								Header:     make(http.Header),
 							}
 						}
@@ -139,7 +140,7 @@ func TestInsert_Callout(t *testing.T) {
 						return &http.Response{
 							StatusCode: http.StatusOK,
 							Status:     "Some Status",
-							Body:       ioutil.NopCloser(strings.NewReader(resp)),
+							Body:       io.NopCloser(strings.NewReader(resp)),
 							Header:     make(http.Header),
 						}
 					}),
@@ -189,7 +190,7 @@ func TestInsert_Callout(t *testing.T) {
 			i := &insert{
 				session: tt.fields.session,
 			}
-			got, err := i.callout(tt.args.allOrNone, tt.args.records)
+			got, err := i.callout(context.Background(), tt.args.allOrNone, tt.args.records)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Insert.Callout() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -200,3 +201,32 @@ func TestInsert_Callout(t *testing.T) {
 		})
 	}
 }
+
+func TestInsert_payloadIsDeterministic(t *testing.T) {
+	records := []sobject.Inserter{
+		&mockInserter{
+			sobject: "Account",
+			fields: map[string]interface{}{
+				"Website":       "www.salesforce.com",
+				"Name":          "test name",
+				"Industry":      "Banking",
+				"AccountNumber": 42,
+			},
+		},
+	}
+	want := `{"allOrNone":true,"records":[{"AccountNumber":42,"Industry":"Banking","Name":"test name","Website":"www.salesforce.com","attributes":{"type":"Account"}}]}`
+	i := &insert{}
+	for run := 0; run < 10; run++ {
+		payload, err := i.payload(true, records)
+		if err != nil {
+			t.Fatalf("insert.payload() error = %v", err)
+		}
+		body, err := io.ReadAll(payload)
+		if err != nil {
+			t.Fatalf("payload error = %v", err)
+		}
+		if string(body) != want {
+			t.Fatalf("insert.payload() = %s, want %s", body, want)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package collections
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -20,7 +21,23 @@ type query struct {
 	session session.ServiceFormatter
 }
 
-func (q *query) callout(sobject string, records []sobject.Querier) ([]*sfdc.Record, error) {
+// QueryOption is an option for the collections query.
+type QueryOption func(*queryOptions)
+
+type queryOptions struct {
+	batchSize int
+}
+
+// WithQueryBatchSize will ask for the records in batches of the size, from
+// sfdc.MinQueryBatchSize to sfdc.MaxQueryBatchSize records, with the
+// Sforce-Query-Options header.
+func WithQueryBatchSize(size int) QueryOption {
+	return func(o *queryOptions) {
+		o.batchSize = size
+	}
+}
+
+func (q *query) callout(ctx context.Context, sobject string, records []sobject.Querier, options queryOptions) ([]*sfdc.Record, error) {
 	if q == nil {
 		panic("collections: Collection Query can not be nil")
 	}
@@ -31,11 +48,18 @@ func (q *query) callout(sobject string, records []sobject.Querier) ([]*sfdc.Reco
 	c := &collection{
 		method:      http.MethodPost,
 		body:        payload,
-		endpoint:    endpoint + "/" + sobject,
+		endpoint:    endpoint + "/" + sfdc.EscapePath(sobject),
 		contentType: jsonContentType,
 	}
+	if options.batchSize != 0 {
+		value, err := sfdc.QueryBatchSize(options.batchSize)
+		if err != nil {
+			return nil, fmt.Errorf("sobject collections: %w", err)
+		}
+		c.header = http.Header{sfdc.QueryOptionsHeader: []string{value}}
+	}
 	var values []*sfdc.Record
-	err = c.send(q.session, &values)
+	err = c.send(ctx, q.session, &values)
 	if err != nil {
 		return nil, err
 	}
@@ -1,7 +1,8 @@
 package collections
 
 import (
-	"io/ioutil"
+	"context"
+	"io"
 	"net/http"
 	"strings"
 	"testing"
@@ -196,7 +197,7 @@ func TestQuery_Callout(t *testing.T) {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "Bad URL: " + req.URL.String(),
-								Body:       ioutil.NopCloser(strings.NewReader("resp")),
+								Body:       io.NopCloser(strings.NewReader("resp")),
 								Header:     make(http.Header),
 							}
 						}
@@ -205,7 +206,7 @@ func TestQuery_Callout(t *testing.T) {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "Bad Method",
-								Body:       ioutil.NopCloser(strings.NewReader("resp")),
+								Body:       io.NopCloser(strings.NewReader("resp")),
 								Header:     make(http.Header),
 							}
 						}
@@ -234,7 +235,7 @@ func TestQuery_Callout(t *testing.T) {
 						return &http.Response{
 							StatusCode: http.StatusOK,
 							Status:     "Some Status",
-							Body:       ioutil.NopCloser(strings.NewReader(resp)),
+							Body:       io.NopCloser(strings.NewReader(resp)),
 							Header:     make(http.Header),
 						}
 					}),
@@ -248,7 +249,7 @@ func TestQuery_Callout(t *testing.T) {
 			q := &query{
 				session: tt.fields.session,
 			}
-			_, err := q.callout(tt.args.sobject, tt.args.records)
+			_, err := q.callout(context.Background(), tt.args.sobject, tt.args.records, queryOptions{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Query.Callout() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -256,3 +257,92 @@ func TestQuery_Callout(t *testing.T) {
 		})
 	}
 }
+
+func TestResource_QueryContext_batchSize(t *testing.T) {
+	records := []sobject.Querier{
+		&mockQuery{
+			sobject: "Account",
+			id:      "001xx000003DGb1AAG",
+			fields:  []string{"Name"},
+		},
+	}
+	tests := []struct {
+		name       string
+		options    []QueryOption
+		wantHeader string
+		wantErr    bool
+	}{
+		{
+			name: "no batch size",
+		},
+		{
+			name:       "batch size",
+			options:    []QueryOption{WithQueryBatchSize(500)},
+			wantHeader: "batchSize=500",
+		},
+		{
+			name:    "batch size too large",
+			options: []QueryOption{WithQueryBatchSize(5000)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var header string
+			resource, err := NewResources(&mockSessionFormatter{
+				url: "https://test.salesforce.com",
+				client: mockHTTPClient(func(req *http.Request) *http.Response {
+					header = req.Header.Get("Sforce-Query-Options")
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`[{"attributes":{"type":"Account"},"Id":"001xx000003DGb1AAG","Name":"Acme"}]`)),
+						Header:     make(http.Header),
+					}
+				}),
+			})
+			if err != nil {
+				t.Fatalf("NewResources() error = %v", err)
+			}
+
+			_, err = resource.QueryContext(context.Background(), "Account", records, tt.options...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resource.QueryContext() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if header != tt.wantHeader {
+				t.Errorf("Resource.QueryContext() Sforce-Query-Options = %q, want %q", header, tt.wantHeader)
+			}
+		})
+	}
+}
+
+func TestResource_QueryContext_escapesSObject(t *testing.T) {
+	var path string
+	resource, err := NewResources(&mockSessionFormatter{
+		url: "https://test.salesforce.com",
+		client: mockHTTPClient(func(req *http.Request) *http.Response {
+			path = req.URL.EscapedPath()
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`[]`)),
+				Header:     make(http.Header),
+			}
+		}),
+	})
+	if err != nil {
+		t.Fatalf("NewResources() error = %v", err)
+	}
+
+	records := []sobject.Querier{
+		&mockQuery{
+			sobject: "Account/../x?y",
+			id:      "001xx000003DGb1AAG",
+			fields:  []string{"Name"},
+		},
+	}
+	if _, err := resource.QueryContext(context.Background(), "Account/../x?y", records); err != nil {
+		t.Fatalf("Resource.QueryContext() error = %v", err)
+	}
+	if want := "/composite/sobjects/Account%2F..%2Fx%3Fy"; strings.HasSuffix(path, want) == false {
+		t.Errorf("Resource.QueryContext() path = %v, want suffix %v", path, want)
+	}
+}
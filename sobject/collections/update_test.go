@@ -1,7 +1,8 @@
 package collections
 
 import (
-	"io/ioutil"
+	"context"
+	"io"
 	"net/http"
 	"reflect"
 	"strings"
@@ -107,7 +108,7 @@ func TestUpdate_Callout(t *testing.T) {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "Bad URL: " + req.URL.String(),
-								Body:       ioutil.NopCloser(strings.NewReader("resp")),
+								Body:       io.NopCloser(strings.NewReader("resp")),
 								Header:     make(http.Header),
 							}
 						}
@@ -116,7 +117,7 @@ func TestUpdate_Callout(t *testing.T) {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "Bad Method",
-								Body:       ioutil.NopCloser(strings.NewReader("resp")),
+								Body:       io.NopCloser(strings.NewReader("resp")),
 								Header:     make(http.Header),
 							}
 						}
@@ -143,7 +144,7 @@ func TestUpdate_Callout(t *testing.T) {
 						return &http.Response{
 							StatusCode: http.StatusOK,
 							Status:     "Some Status",
-							Body:       ioutil.NopCloser(strings.NewReader(resp)),
+							Body:       io.NopCloser(strings.NewReader(resp)),
 							Header:     make(http.Header),
 						}
 					}),
@@ -197,7 +198,7 @@ func TestUpdate_Callout(t *testing.T) {
 			u := &update{
 				session: tt.fields.session,
 			}
-			got, err := u.callout(tt.args.allOrNone, tt.args.records)
+			got, err := u.callout(context.Background(), tt.args.allOrNone, tt.args.records)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Update.Callout() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -0,0 +1,59 @@
+package collections
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+
+	"github.com/namely/go-sfdc/v3"
+	"github.com/namely/go-sfdc/v3/session"
+	"github.com/namely/go-sfdc/v3/sobject"
+)
+
+// UpsertValue is the return value from the
+// Salesforce API.  Created is true when the
+// record was inserted.
+type UpsertValue struct {
+	sobject.UpsertValue
+}
+
+type upsert struct {
+	session session.ServiceFormatter
+}
+
+func (u *upsert) callout(ctx context.Context, allOrNone bool, sobjectName, externalIDField string, records []sobject.Upserter) ([]UpsertValue, error) {
+	payload, err := u.payload(allOrNone, externalIDField, records)
+	if err != nil {
+		return nil, err
+	}
+	c := &collection{
+		method:      http.MethodPatch,
+		body:        payload,
+		endpoint:    endpoint + "/" + sfdc.EscapePath(sobjectName, externalIDField),
+		contentType: jsonContentType,
+	}
+	var values []UpsertValue
+	err = c.send(ctx, u.session, &values)
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+func (u *upsert) payload(allOrNone bool, externalIDField string, recs []sobject.Upserter) (*bytes.Reader, error) {
+	records := make([]interface{}, len(recs))
+	for idx, upserter := range recs {
+		rec := map[string]interface{}{
+			"attributes": map[string]string{
+				"type": upserter.SObject(),
+			},
+		}
+		for field, value := range upserter.Fields() {
+			rec[field] = value
+		}
+		if upserter.ID() != "" {
+			rec[externalIDField] = upserter.ID()
+		}
+		records[idx] = rec
+	}
+	return dmlpayload(allOrNone, records)
+}
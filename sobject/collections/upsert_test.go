@@ -0,0 +1,219 @@
+package collections
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/namely/go-sfdc/v3"
+	"github.com/namely/go-sfdc/v3/sobject"
+)
+
+type mockUpserter struct {
+	mockUpdater
+	externalField string
+}
+
+func (mock *mockUpserter) ExternalField() string {
+	return mock.externalField
+}
+
+func TestUpsert_payload(t *testing.T) {
+	u := &upsert{}
+	payload, err := u.payload(true, "External__c", []sobject.Upserter{
+		&mockUpserter{
+			mockUpdater: mockUpdater{
+				sobject: "Account",
+				fields: map[string]interface{}{
+					"Name": "Acme",
+				},
+				id: "A-1",
+			},
+			externalField: "External__c",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Upsert.payload() error = %v", err)
+	}
+	var got map[string]interface{}
+	if err := json.NewDecoder(payload).Decode(&got); err != nil {
+		t.Fatalf("Upsert.payload() decode error = %v", err)
+	}
+	want := map[string]interface{}{
+		"allOrNone": true,
+		"records": []interface{}{
+			map[string]interface{}{
+				"attributes": map[string]interface{}{
+					"type": "Account",
+				},
+				"Name":        "Acme",
+				"External__c": "A-1",
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Upsert.payload() = %v, want %v", got, want)
+	}
+}
+
+func TestResource_Upsert(t *testing.T) {
+	account := func(id string) sobject.Upserter {
+		return &mockUpserter{
+			mockUpdater: mockUpdater{
+				sobject: "Account",
+				fields: map[string]interface{}{
+					"Name": "Acme " + id,
+				},
+				id: id,
+			},
+			externalField: "External__c",
+		}
+	}
+	client := mockHTTPClient(func(req *http.Request) *http.Response {
+		if req.URL.String() != "something.com/composite/sobjects/Account/External__c" {
+			return &http.Response{
+				StatusCode: 500,
+				Status:     "Bad URL: " + req.URL.String(),
+				Body:       io.NopCloser(strings.NewReader("resp")),
+				Header:     make(http.Header),
+			}
+		}
+		if req.Method != http.MethodPatch {
+			return &http.Response{
+				StatusCode: 500,
+				Status:     "Bad Method",
+				Body:       io.NopCloser(strings.NewReader("resp")),
+				Header:     make(http.Header),
+			}
+		}
+		resp := `
+		[
+			{
+				"id" : "001RM000003oLrHYAU",
+				"success" : true,
+				"errors" : [ ],
+				"created" : true
+			},
+			{
+				"id" : "001RM000003oLrIYAU",
+				"success" : true,
+				"errors" : [ ],
+				"created" : false
+			}
+		]`
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Status:     "Some Status",
+			Body:       io.NopCloser(strings.NewReader(resp)),
+			Header:     make(http.Header),
+		}
+	})
+	tests := []struct {
+		name            string
+		upsert          *upsert
+		sobject         string
+		externalIDField string
+		records         []sobject.Upserter
+		want            []UpsertValue
+		wantErr         bool
+	}{
+		{
+			name: "success",
+			upsert: &upsert{
+				session: &mockSessionFormatter{
+					url:    "something.com",
+					client: client,
+				},
+			},
+			sobject:         "Account",
+			externalIDField: "External__c",
+			records:         []sobject.Upserter{account("A-1"), account("A-2")},
+			want: []UpsertValue{
+				{
+					UpsertValue: sobject.UpsertValue{
+						Created: true,
+						InsertValue: sobject.InsertValue{
+							Success: true,
+							ID:      "001RM000003oLrHYAU",
+							Errors:  make([]sfdc.Error, 0),
+						},
+					},
+				},
+				{
+					UpsertValue: sobject.UpsertValue{
+						InsertValue: sobject.InsertValue{
+							Success: true,
+							ID:      "001RM000003oLrIYAU",
+							Errors:  make([]sfdc.Error, 0),
+						},
+					},
+				},
+			},
+		},
+		{
+			name:            "not initialized",
+			sobject:         "Account",
+			externalIDField: "External__c",
+			records:         []sobject.Upserter{account("A-1")},
+			wantErr:         true,
+		},
+		{
+			name:            "no records",
+			upsert:          &upsert{},
+			sobject:         "Account",
+			externalIDField: "External__c",
+			wantErr:         true,
+		},
+		{
+			name:            "no external ID field",
+			upsert:          &upsert{},
+			sobject:         "Account",
+			externalIDField: "",
+			records:         []sobject.Upserter{account("A-1")},
+			wantErr:         true,
+		},
+		{
+			name:            "mixed sobjects",
+			upsert:          &upsert{},
+			sobject:         "Account",
+			externalIDField: "External__c",
+			records: []sobject.Upserter{
+				account("A-1"),
+				&mockUpserter{
+					mockUpdater: mockUpdater{
+						sobject: "Contact",
+						id:      "C-1",
+					},
+					externalField: "External__c",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:            "different external ID field",
+			upsert:          &upsert{},
+			sobject:         "Account",
+			externalIDField: "Other__c",
+			records:         []sobject.Upserter{account("A-1")},
+			wantErr:         true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Resource{
+				upsert: tt.upsert,
+			}
+			got, err := r.Upsert(true, tt.sobject, tt.externalIDField, tt.records)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Resource.Upsert() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Resource.Upsert() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package sobject
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/namely/go-sfdc/v3"
+)
+
+// addressCompoundFields are the standard address compound fields, which can
+// only be written with their component fields.
+var addressCompoundFields = map[string]bool{
+	"address":              true,
+	"billingaddress":       true,
+	"shippingaddress":      true,
+	"mailingaddress":       true,
+	"otheraddress":         true,
+	"personmailingaddress": true,
+	"personotheraddress":   true,
+}
+
+// ExpandCompound returns the fields with the sfdc.Address and
+// sfdc.Geolocation values, or their pointers, of the compound fields replaced
+// by the values of their component fields, so the fields can be inserted or
+// updated.  The other fields are kept as they are.
+//
+// An address of a standard field, like BillingAddress, is written to the
+// fields with the same prefix, like BillingStreet and BillingCity, and the
+// one of a custom field, like Home__c, to the fields like Home__Street__s.
+// The empty components of an address are not written.  A geolocation is only
+// a custom field, like Location__c, which is written to Location__Latitude__s
+// and Location__Longitude__s.
+//
+// An error is returned when a standard address compound field has a value
+// that is not an address, or a value is a map, like the compound value of a
+// query, since Salesforce does not write the compound fields.  It is also an
+// error when a component field is in the fields with its compound field.
+func ExpandCompound(fields map[string]interface{}) (map[string]interface{}, error) {
+	expanded := make(map[string]interface{}, len(fields))
+	for field, value := range fields {
+		var parts map[string]interface{}
+		var err error
+		switch v := value.(type) {
+		case sfdc.Address:
+			parts, err = addressComponents(field, v)
+		case *sfdc.Address:
+			if v == nil {
+				return nil, fmt.Errorf("sobject compound field: %s address can not be nil, write its component fields to clear it", field)
+			}
+			parts, err = addressComponents(field, *v)
+		case sfdc.Geolocation:
+			parts, err = geolocationComponents(field, v)
+		case *sfdc.Geolocation:
+			if v == nil {
+				return nil, fmt.Errorf("sobject compound field: %s geolocation can not be nil, write its component fields to clear it", field)
+			}
+			parts, err = geolocationComponents(field, *v)
+		case map[string]interface{}:
+			return nil, fmt.Errorf("sobject compound field: %s can not be written directly, use an sfdc.Address or sfdc.Geolocation", field)
+		default:
+			if addressCompoundFields[strings.ToLower(field)] {
+				return nil, fmt.Errorf("sobject compound field: %s can not be written directly, use an sfdc.Address", field)
+			}
+			expanded[field] = value
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for component, componentValue := range parts {
+			if hasField(fields, component) {
+				return nil, fmt.Errorf("sobject compound field: %s is a component of %s", component, field)
+			}
+			expanded[component] = componentValue
+		}
+	}
+	return expanded, nil
+}
+
+// addressComponents returns the component fields of the address.
+func addressComponents(field string, address sfdc.Address) (map[string]interface{}, error) {
+	var name func(component string) string
+	switch {
+	case strings.HasSuffix(field, "__c"):
+		base := strings.TrimSuffix(field, "__c")
+		name = func(component string) string {
+			return base + "__" + component + "__s"
+		}
+	case strings.HasSuffix(field, "Address"):
+		prefix := strings.TrimSuffix(field, "Address")
+		name = func(component string) string {
+			return prefix + component
+		}
+	default:
+		return nil, fmt.Errorf("sobject compound field: %s is not an address compound field", field)
+	}
+
+	parts := make(map[string]interface{})
+	for component, value := range map[string]string{
+		"Street":          address.Street,
+		"City":            address.City,
+		"State":           address.State,
+		"StateCode":       address.StateCode,
+		"PostalCode":      address.PostalCode,
+		"Country":         address.Country,
+		"CountryCode":     address.CountryCode,
+		"GeocodeAccuracy": address.GeocodeAccuracy,
+	} {
+		if value != "" {
+			parts[name(component)] = value
+		}
+	}
+	if address.Latitude != nil {
+		parts[name("Latitude")] = *address.Latitude
+	}
+	if address.Longitude != nil {
+		parts[name("Longitude")] = *address.Longitude
+	}
+	return parts, nil
+}
+
+// geolocationComponents returns the component fields of the geolocation.
+func geolocationComponents(field string, location sfdc.Geolocation) (map[string]interface{}, error) {
+	if strings.HasSuffix(field, "__c") == false {
+		return nil, fmt.Errorf("sobject compound field: %s is not a custom geolocation field", field)
+	}
+	base := strings.TrimSuffix(field, "__c")
+	return map[string]interface{}{
+		base + "__Latitude__s":  location.Latitude,
+		base + "__Longitude__s": location.Longitude,
+	}, nil
+}
+
+// hasField returns whether the fields have the field, matching its name
+// without regard to case.
+func hasField(fields map[string]interface{}, field string) bool {
+	for name := range fields {
+		if strings.EqualFold(name, field) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package sobject
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/namely/go-sfdc/v3"
+)
+
+func TestExpandCompound(t *testing.T) {
+	latitude, longitude := 37.7936, -122.3953
+	tests := []struct {
+		name   string
+		fields map[string]interface{}
+		want   map[string]interface{}
+	}{
+		{
+			name: "standard address",
+			fields: map[string]interface{}{
+				"Name": "Acme",
+				"BillingAddress": sfdc.Address{
+					Street:     "415 Mission Street",
+					City:       "San Francisco",
+					PostalCode: "94105",
+					Latitude:   &latitude,
+					Longitude:  &longitude,
+				},
+			},
+			want: map[string]interface{}{
+				"Name":              "Acme",
+				"BillingStreet":     "415 Mission Street",
+				"BillingCity":       "San Francisco",
+				"BillingPostalCode": "94105",
+				"BillingLatitude":   latitude,
+				"BillingLongitude":  longitude,
+			},
+		},
+		{
+			name: "address pointer without prefix",
+			fields: map[string]interface{}{
+				"Address": &sfdc.Address{StateCode: "CA", CountryCode: "US"},
+			},
+			want: map[string]interface{}{
+				"StateCode":   "CA",
+				"CountryCode": "US",
+			},
+		},
+		{
+			name: "custom address",
+			fields: map[string]interface{}{
+				"Home__c": sfdc.Address{City: "Oakland"},
+			},
+			want: map[string]interface{}{
+				"Home__City__s": "Oakland",
+			},
+		},
+		{
+			name: "custom geolocation",
+			fields: map[string]interface{}{
+				"Location__c": sfdc.Geolocation{Latitude: latitude, Longitude: longitude},
+				"Store__c":    &sfdc.Geolocation{Latitude: 1, Longitude: 2},
+			},
+			want: map[string]interface{}{
+				"Location__Latitude__s":  latitude,
+				"Location__Longitude__s": longitude,
+				"Store__Latitude__s":     float64(1),
+				"Store__Longitude__s":    float64(2),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandCompound(tt.fields)
+			if err != nil {
+				t.Fatalf("ExpandCompound() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExpandCompound() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandCompound_errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields map[string]interface{}
+	}{
+		{
+			name:   "direct address write",
+			fields: map[string]interface{}{"BillingAddress": "415 Mission Street"},
+		},
+		{
+			name:   "direct compound map write",
+			fields: map[string]interface{}{"Location__c": map[string]interface{}{"latitude": 1.0, "longitude": 2.0}},
+		},
+		{
+			name:   "address of another field",
+			fields: map[string]interface{}{"Name": sfdc.Address{City: "Oakland"}},
+		},
+		{
+			name:   "standard geolocation",
+			fields: map[string]interface{}{"BillingAddress": sfdc.Geolocation{}},
+		},
+		{
+			name:   "nil address",
+			fields: map[string]interface{}{"BillingAddress": (*sfdc.Address)(nil)},
+		},
+		{
+			name: "component with its compound",
+			fields: map[string]interface{}{
+				"BillingAddress": sfdc.Address{City: "Oakland"},
+				"billingcity":    "San Francisco",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ExpandCompound(tt.fields); err == nil {
+				t.Errorf("ExpandCompound() error = %v, want an error", err)
+			}
+		})
+	}
+}
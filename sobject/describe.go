@@ -1,9 +1,11 @@
 package sobject
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/namely/go-sfdc/v3"
 	"github.com/namely/go-sfdc/v3/session"
@@ -70,6 +72,52 @@ type ChildRelationship struct {
 	JunctionReferenceTo []string `json:"junctionReferenceTo"`
 }
 
+// Queryable indicates if the child relationship can be used in a
+// SOQL child query.  Child relationships without a relationship name
+// can not be queried.
+func (c ChildRelationship) Queryable() bool {
+	return c.RelationshipName != ""
+}
+
+// QueryableChildRelationships returns the child relationships that can
+// be used in a SOQL child query.
+func (d DescribeValue) QueryableChildRelationships() []ChildRelationship {
+	var relationships []ChildRelationship
+	for _, relationship := range d.ChildRelationships {
+		if relationship.Queryable() {
+			relationships = append(relationships, relationship)
+		}
+	}
+	return relationships
+}
+
+// ChildRelationshipByName returns the child relationship with the
+// relationship name.  The name is matched case insensitively, like
+// SOQL does.
+func (d DescribeValue) ChildRelationshipByName(relName string) (ChildRelationship, bool) {
+	if relName == "" {
+		return ChildRelationship{}, false
+	}
+	for _, relationship := range d.ChildRelationships {
+		if strings.EqualFold(relationship.RelationshipName, relName) {
+			return relationship, true
+		}
+	}
+	return ChildRelationship{}, false
+}
+
+// ParentReferences returns the lookup and master-detail fields, which
+// reference the parent SObjects listed in the field's ReferenceTo.
+func (d DescribeValue) ParentReferences() []Field {
+	var fields []Field
+	for _, field := range d.Fields {
+		if field.Type == "reference" && len(field.ReferenceTo) > 0 {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
 // PickListValue describes the SObject's field picklist values.
 type PickListValue struct {
 	Active       bool   `json:"active"`
@@ -167,11 +215,16 @@ const describeEndpoint = "/describe"
 
 type describe struct {
 	session session.ServiceFormatter
+	cache   *describeCache
 }
 
 func (d *describe) callout(sobject string) (DescribeValue, error) {
+	return d.calloutContext(context.Background(), sobject)
+}
+
+func (d *describe) calloutContext(ctx context.Context, sobject string) (DescribeValue, error) {
 
-	request, err := d.request(sobject)
+	request, err := d.request(ctx, sobject)
 
 	if err != nil {
 		return DescribeValue{}, err
@@ -186,10 +239,14 @@ func (d *describe) callout(sobject string) (DescribeValue, error) {
 	return value, nil
 }
 
-func (d *describe) request(sobject string) (*http.Request, error) {
-	url := d.session.ServiceURL() + objectEndpoint + sobject + describeEndpoint
+func (d *describe) request(ctx context.Context, sobject string) (*http.Request, error) {
+	serviceURL, err := session.ServiceURLContext(ctx, d.session)
+	if err != nil {
+		return nil, err
+	}
+	url := serviceURL + objectEndpoint + sfdc.EscapePath(sobject) + describeEndpoint
 
-	request, err := http.NewRequest(http.MethodGet, url, nil)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 
 	if err != nil {
 		return nil, err
@@ -202,14 +259,14 @@ func (d *describe) request(sobject string) (*http.Request, error) {
 }
 
 func (d *describe) response(request *http.Request) (DescribeValue, error) {
-	response, err := d.session.Client().Do(request)
+	response, err := d.cache.do(d.session, request)
 
 	if err != nil {
 		return DescribeValue{}, err
 	}
 
 	decoder := json.NewDecoder(response.Body)
-	defer response.Body.Close()
+	defer sfdc.CloseResponse(response)
 
 	if response.StatusCode != http.StatusOK {
 		var respErrs []sfdc.Error
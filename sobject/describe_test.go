@@ -1,12 +1,15 @@
 package sobject
 
 import (
-	"io/ioutil"
+	"context"
+	"encoding/json"
+	"io"
 	"net/http"
 	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/namely/go-sfdc/v3"
 	"github.com/namely/go-sfdc/v3/session"
 )
 
@@ -54,7 +57,7 @@ func Test_describe_Describe(t *testing.T) {
 						return &http.Response{
 							StatusCode: 500,
 							Status:     "Some Status",
-							Body:       ioutil.NopCloser(strings.NewReader(resp)),
+							Body:       io.NopCloser(strings.NewReader(resp)),
 							Header:     make(http.Header),
 						}
 					}),
@@ -77,7 +80,7 @@ func Test_describe_Describe(t *testing.T) {
 
 						return &http.Response{
 							StatusCode: 200,
-							Body:       ioutil.NopCloser(strings.NewReader(resp)),
+							Body:       io.NopCloser(strings.NewReader(resp)),
 							Header:     make(http.Header),
 						}
 					}),
@@ -254,7 +257,7 @@ func Test_describe_Describe(t *testing.T) {
 
 						return &http.Response{
 							StatusCode: 200,
-							Body:       ioutil.NopCloser(strings.NewReader(resp)),
+							Body:       io.NopCloser(strings.NewReader(resp)),
 							Header:     make(http.Header),
 						}
 					}),
@@ -399,3 +402,157 @@ func Test_describe_Describe(t *testing.T) {
 		})
 	}
 }
+
+func TestResources_DescribeContext_apiVersion(t *testing.T) {
+	var path string
+	resources, err := NewResources(&mockSessionFormatter{
+		url: "https://test.salesforce.com",
+		client: mockHTTPClient(func(req *http.Request) *http.Response {
+			path = req.URL.Path
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"name": "Account"}`)),
+				Header:     make(http.Header),
+			}
+		}),
+	})
+	if err != nil {
+		t.Fatalf("NewResources() error = %v", err)
+	}
+
+	ctx := sfdc.WithAPIVersion(context.Background(), 58)
+	if _, err := resources.DescribeContext(ctx, "Account"); err != nil {
+		t.Fatalf("Resources.DescribeContext() error = %v", err)
+	}
+	if want := "/services/data/v58.0/sobjects/Account/describe"; path != want {
+		t.Errorf("Resources.DescribeContext() path = %v, want %v", path, want)
+	}
+}
+
+func TestDescribeValue_relationships(t *testing.T) {
+	fixture := `{
+		"name": "Account",
+		"childRelationships": [
+			{
+				"cascadeDelete": true,
+				"childSObject": "Contact",
+				"deprecatedAndHidden": false,
+				"field": "AccountId",
+				"junctionIdListNames": [],
+				"junctionReferenceTo": [],
+				"relationshipName": "Contacts",
+				"restrictedDelete": false
+			},
+			{
+				"cascadeDelete": false,
+				"childSObject": "AccountHistory",
+				"deprecatedAndHidden": false,
+				"field": "AccountId",
+				"junctionIdListNames": [],
+				"junctionReferenceTo": [],
+				"relationshipName": null,
+				"restrictedDelete": false
+			},
+			{
+				"cascadeDelete": true,
+				"childSObject": "Opportunity",
+				"deprecatedAndHidden": false,
+				"field": "AccountId",
+				"junctionIdListNames": [],
+				"junctionReferenceTo": [],
+				"relationshipName": "Opportunities",
+				"restrictedDelete": true
+			}
+		],
+		"fields": [
+			{
+				"name": "Id",
+				"type": "id",
+				"referenceTo": []
+			},
+			{
+				"name": "OwnerId",
+				"type": "reference",
+				"relationshipName": "Owner",
+				"referenceTo": ["User"]
+			},
+			{
+				"name": "ParentId",
+				"type": "reference",
+				"relationshipName": "Parent",
+				"referenceTo": ["Account"]
+			}
+		]
+	}`
+	var value DescribeValue
+	if err := json.Unmarshal([]byte(fixture), &value); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	if len(value.ChildRelationships) != 3 {
+		t.Fatalf("ChildRelationships = %d, want 3", len(value.ChildRelationships))
+	}
+	if value.ChildRelationships[1].Queryable() {
+		t.Errorf("ChildRelationship.Queryable() = true for %s without a relationship name", value.ChildRelationships[1].ChildSObject)
+	}
+
+	queryable := value.QueryableChildRelationships()
+	var names []string
+	for _, relationship := range queryable {
+		names = append(names, relationship.RelationshipName)
+	}
+	if !reflect.DeepEqual(names, []string{"Contacts", "Opportunities"}) {
+		t.Errorf("DescribeValue.QueryableChildRelationships() = %v", names)
+	}
+
+	tests := []struct {
+		name    string
+		relName string
+		want    string
+		wantHas bool
+	}{
+		{
+			name:    "exact",
+			relName: "Contacts",
+			want:    "Contact",
+			wantHas: true,
+		},
+		{
+			name:    "case insensitive",
+			relName: "opportunities",
+			want:    "Opportunity",
+			wantHas: true,
+		},
+		{
+			name:    "unqueryable",
+			relName: "",
+			want:    "",
+			wantHas: false,
+		},
+		{
+			name:    "missing",
+			relName: "Cases",
+			want:    "",
+			wantHas: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, has := value.ChildRelationshipByName(tt.relName)
+			if has != tt.wantHas || got.ChildSObject != tt.want {
+				t.Errorf("DescribeValue.ChildRelationshipByName() = %v, %v, want %v, %v", got.ChildSObject, has, tt.want, tt.wantHas)
+			}
+		})
+	}
+
+	references := value.ParentReferences()
+	if len(references) != 2 {
+		t.Fatalf("DescribeValue.ParentReferences() = %d, want 2", len(references))
+	}
+	if references[0].Name != "OwnerId" || !reflect.DeepEqual(references[0].ReferenceTo, []string{"User"}) {
+		t.Errorf("DescribeValue.ParentReferences() = %+v", references[0])
+	}
+	if references[1].Name != "ParentId" || !reflect.DeepEqual(references[1].ReferenceTo, []string{"Account"}) {
+		t.Errorf("DescribeValue.ParentReferences() = %+v", references[1])
+	}
+}
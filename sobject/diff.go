@@ -0,0 +1,187 @@
+package sobject
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/namely/go-sfdc/v3"
+)
+
+// DiffOption is an option for the comparison of the record's fields.
+type DiffOption func(*diffOptions)
+
+type diffOptions struct {
+	trimStrings bool
+	tolerance   float64
+}
+
+// WithTrimmedStrings will compare the strings without their leading and
+// trailing white space.
+func WithTrimmedStrings() DiffOption {
+	return func(opts *diffOptions) {
+		opts.trimStrings = true
+	}
+}
+
+// WithNumericTolerance will consider two numbers equal when they differ by at
+// most the tolerance, like 0.005 for the currencies with two decimals.
+func WithNumericTolerance(tolerance float64) DiffOption {
+	return func(opts *diffOptions) {
+		opts.tolerance = math.Abs(tolerance)
+	}
+}
+
+// Diff returns the desired fields that are different from the current record,
+// and whether there is any.  Only the desired fields are compared, so the
+// fields that are not desired are not changed.  A nil desired value clears the
+// field, and it is a change only when the current record has a value, since the
+// records do not keep their null fields.
+//
+// The numbers are compared by value, whatever their type, like a float64 or a
+// json.Number.  A time.Time is compared at second precision with the current
+// value parsed with sfdc.ParseTime.  The other values are compared as they
+// are.
+func Diff(current *sfdc.Record, desired map[string]interface{}, opts ...DiffOption) (map[string]interface{}, bool) {
+	options := diffOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	changes := make(map[string]interface{})
+	for field, value := range desired {
+		var currentValue interface{}
+		has := false
+		if current != nil {
+			currentValue, has = current.FieldValue(field)
+		}
+		if has == false || currentValue == nil {
+			if value != nil {
+				changes[field] = value
+			}
+			continue
+		}
+		if value == nil || options.equal(currentValue, value) == false {
+			changes[field] = value
+		}
+	}
+	return changes, len(changes) > 0
+}
+
+func (opts diffOptions) equal(current, desired interface{}) bool {
+	switch d := desired.(type) {
+	case string:
+		c, ok := current.(string)
+		if ok == false {
+			return false
+		}
+		if opts.trimStrings {
+			return strings.TrimSpace(c) == strings.TrimSpace(d)
+		}
+		return c == d
+	case time.Time:
+		c, ok := current.(string)
+		if ok == false {
+			return false
+		}
+		parsed, err := sfdc.ParseTime(c)
+		if err != nil {
+			return false
+		}
+		return parsed.Truncate(time.Second).Equal(d.Truncate(time.Second))
+	case *time.Time:
+		if d == nil {
+			return false
+		}
+		return opts.equal(current, *d)
+	}
+	if c, ok := number(current); ok {
+		d, ok := number(desired)
+		if ok == false {
+			return false
+		}
+		return opts.equalNumbers(c, d)
+	}
+	return reflect.DeepEqual(current, desired)
+}
+
+// equalNumbers compares the integers exactly, so the large ones are not
+// rounded, and the other numbers within the tolerance.
+func (opts diffOptions) equalNumbers(current, desired string) bool {
+	c, cErr := strconv.ParseInt(current, 10, 64)
+	d, dErr := strconv.ParseInt(desired, 10, 64)
+	if cErr == nil && dErr == nil && opts.tolerance == 0 {
+		return c == d
+	}
+	cf, err := strconv.ParseFloat(current, 64)
+	if err != nil {
+		return false
+	}
+	df, err := strconv.ParseFloat(desired, 64)
+	if err != nil {
+		return false
+	}
+	return math.Abs(cf-df) <= opts.tolerance
+}
+
+// number returns the value as a decimal string when it is a number.
+func number(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case json.Number:
+		return v.String(), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), true
+	case int:
+		return strconv.Itoa(v), true
+	case int8, int16, int32, int64:
+		return strconv.FormatInt(reflect.ValueOf(v).Int(), 10), true
+	case uint, uint8, uint16, uint32, uint64:
+		return strconv.FormatUint(reflect.ValueOf(v).Uint(), 10), true
+	default:
+		return "", false
+	}
+}
+
+type changedUpdater struct {
+	Updater
+	fields map[string]interface{}
+}
+
+func (u *changedUpdater) Fields() map[string]interface{} {
+	return u.fields
+}
+
+// UpdateIfChanged will update the record with only the updater's fields that
+// are different from the current record, comparing them like Diff.  The
+// record is not updated when nothing changed, and false is returned.
+func UpdateIfChanged(ctx context.Context, resources *Resources, updater Updater, current *sfdc.Record, opts ...DiffOption) (bool, error) {
+	if resources == nil || resources.dml == nil {
+		return false, errors.New("salesforce api is not initialized properly")
+	}
+	if updater == nil {
+		return false, errors.New("updater can not be nil")
+	}
+
+	changes, changed := Diff(current, updater.Fields(), opts...)
+	if changed == false {
+		return false, nil
+	}
+	request, err := resources.dml.updateRequest(&changedUpdater{
+		Updater: updater,
+		fields:  changes,
+	})
+	if err != nil {
+		return false, err
+	}
+	if err := resources.dml.updateResponse(request.WithContext(ctx)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,214 @@
+package sobject
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/namely/go-sfdc/v3"
+)
+
+func diffRecord(t *testing.T) *sfdc.Record {
+	t.Helper()
+	var record sfdc.Record
+	err := json.Unmarshal([]byte(`{
+		"attributes": {"type": "Account", "url": "/services/data/v42.0/sobjects/Account/001D000000K0fXOIAZ"},
+		"Name": "Acme ",
+		"NumberOfEmployees": 42,
+		"AnnualRevenue": 1250000.50,
+		"External__c": 123456789012345678,
+		"IsActive__c": true,
+		"LastReviewed__c": "2023-06-14T08:12:53.000+0000",
+		"Description": null
+	}`), &record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &record
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name    string
+		desired map[string]interface{}
+		opts    []DiffOption
+		want    map[string]interface{}
+	}{
+		{
+			name: "no changes",
+			desired: map[string]interface{}{
+				"Name":              "Acme ",
+				"NumberOfEmployees": 42,
+				"AnnualRevenue":     1250000.5,
+				"IsActive__c":       true,
+			},
+			want: map[string]interface{}{},
+		},
+		{
+			name: "strings",
+			desired: map[string]interface{}{
+				"Name": "Acme",
+			},
+			want: map[string]interface{}{
+				"Name": "Acme",
+			},
+		},
+		{
+			name: "trimmed strings",
+			desired: map[string]interface{}{
+				"Name": " Acme",
+			},
+			opts: []DiffOption{WithTrimmedStrings()},
+			want: map[string]interface{}{},
+		},
+		{
+			name: "numbers",
+			desired: map[string]interface{}{
+				"NumberOfEmployees": int64(43),
+				"AnnualRevenue":     json.Number("1250000.501"),
+			},
+			want: map[string]interface{}{
+				"NumberOfEmployees": int64(43),
+				"AnnualRevenue":     json.Number("1250000.501"),
+			},
+		},
+		{
+			name: "numeric tolerance",
+			desired: map[string]interface{}{
+				"NumberOfEmployees": int64(43),
+				"AnnualRevenue":     json.Number("1250000.501"),
+			},
+			opts: []DiffOption{WithNumericTolerance(0.005)},
+			want: map[string]interface{}{
+				"NumberOfEmployees": int64(43),
+			},
+		},
+		{
+			name: "number and string",
+			desired: map[string]interface{}{
+				"NumberOfEmployees": "42",
+			},
+			want: map[string]interface{}{
+				"NumberOfEmployees": "42",
+			},
+		},
+		{
+			name: "times at second precision",
+			desired: map[string]interface{}{
+				"LastReviewed__c": time.Date(2023, 6, 14, 10, 12, 53, 900000000, time.FixedZone("CEST", 2*60*60)),
+			},
+			want: map[string]interface{}{},
+		},
+		{
+			name: "times",
+			desired: map[string]interface{}{
+				"LastReviewed__c": time.Date(2023, 6, 14, 8, 12, 54, 0, time.UTC),
+			},
+			want: map[string]interface{}{
+				"LastReviewed__c": time.Date(2023, 6, 14, 8, 12, 54, 0, time.UTC),
+			},
+		},
+		{
+			name: "null and missing",
+			desired: map[string]interface{}{
+				"Description": nil,
+				"Website":     nil,
+				"Phone":       "555-0100",
+				"IsActive__c": nil,
+			},
+			want: map[string]interface{}{
+				"Phone":       "555-0100",
+				"IsActive__c": nil,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := Diff(diffRecord(t), tt.desired, tt.opts...)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %v, want %v", got, tt.want)
+			}
+			if changed != (len(tt.want) > 0) {
+				t.Errorf("Diff() changed = %t, want %t", changed, len(tt.want) > 0)
+			}
+		})
+	}
+}
+
+func TestDiff_largeIntegers(t *testing.T) {
+	var fields map[string]interface{}
+	decoder := json.NewDecoder(strings.NewReader(`{"External__c": 123456789012345678}`))
+	decoder.UseNumber()
+	if err := decoder.Decode(&fields); err != nil {
+		t.Fatal(err)
+	}
+	record, err := sfdc.RecordFromJSONMap(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, changed := Diff(record, map[string]interface{}{"External__c": int64(123456789012345678)}); changed {
+		t.Error("Diff() changed = true, want the same integer")
+	}
+	if _, changed := Diff(record, map[string]interface{}{"External__c": int64(123456789012345679)}); changed == false {
+		t.Error("Diff() changed = false, want a different integer")
+	}
+}
+
+func TestUpdateIfChanged(t *testing.T) {
+	var bodies []string
+	resources := &Resources{
+		dml: &dml{
+			session: &mockSessionFormatter{
+				url: "https://test.salesforce.com",
+				client: mockHTTPClient(func(req *http.Request) *http.Response {
+					body, _ := io.ReadAll(req.Body)
+					bodies = append(bodies, string(body))
+					return &http.Response{
+						StatusCode: http.StatusNoContent,
+						Body:       io.NopCloser(strings.NewReader("")),
+						Header:     make(http.Header),
+					}
+				}),
+			},
+		},
+	}
+
+	changed, err := UpdateIfChanged(context.Background(), resources, &mockUpdate{
+		sobject: "Account",
+		id:      "001D000000K0fXOIAZ",
+		fields: map[string]interface{}{
+			"Name":              "Acme ",
+			"NumberOfEmployees": 42,
+		},
+	}, diffRecord(t))
+	if err != nil {
+		t.Fatalf("UpdateIfChanged() error = %v", err)
+	}
+	if changed || len(bodies) != 0 {
+		t.Errorf("UpdateIfChanged() = %t, requests %v, want no callout", changed, bodies)
+	}
+
+	changed, err = UpdateIfChanged(context.Background(), resources, &mockUpdate{
+		sobject: "Account",
+		id:      "001D000000K0fXOIAZ",
+		fields: map[string]interface{}{
+			"Name":              "Acme Inc.",
+			"NumberOfEmployees": 42,
+		},
+	}, diffRecord(t))
+	if err != nil {
+		t.Fatalf("UpdateIfChanged() error = %v", err)
+	}
+	if changed == false || len(bodies) != 1 || bodies[0] != `{"Name":"Acme Inc."}` {
+		t.Errorf("UpdateIfChanged() = %t, requests %v, want only the changed field", changed, bodies)
+	}
+
+	if _, err := UpdateIfChanged(context.Background(), resources, nil, diffRecord(t)); err == nil {
+		t.Error("UpdateIfChanged() expected an error for a nil updater")
+	}
+}
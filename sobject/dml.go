@@ -3,11 +3,12 @@ package sobject
 import (
 	"bytes"
 	"encoding/json"
-	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/namely/go-sfdc/v3"
 	"github.com/namely/go-sfdc/v3/session"
+	"github.com/pkg/errors"
 )
 
 // InsertValue is the value that is returned when a
@@ -59,7 +60,9 @@ type Updater interface {
 //
 // ID is the External ID that will be updated.
 //
-// ExternalField is the external ID field.
+// ExternalField is the external ID field.  If it is the Id field, the record
+// is updated, since an Id can only match an existing record, and the
+// UpsertValue is not created.
 //
 // Fields are the fields of the record that are to be inserted.  It is the
 // callers responsibility to provide value fields and values.
@@ -99,7 +102,7 @@ func (d *dml) insertCallout(inserter Inserter) (InsertValue, error) {
 }
 func (d *dml) insertRequest(inserter Inserter) (*http.Request, error) {
 
-	url := d.session.ServiceURL() + objectEndpoint + inserter.SObject()
+	url := d.session.ServiceURL() + objectEndpoint + sfdc.EscapePath(inserter.SObject())
 
 	body, err := json.Marshal(inserter.Fields())
 	if err != nil {
@@ -127,21 +130,10 @@ func (d *dml) insertResponse(request *http.Request) (InsertValue, error) {
 	}
 
 	decoder := json.NewDecoder(response.Body)
-	defer response.Body.Close()
+	defer sfdc.CloseResponse(response)
 
 	if response.StatusCode != http.StatusCreated {
-		var insertErrs []sfdc.Error
-		err = decoder.Decode(&insertErrs)
-		var errMsg error
-		if err == nil {
-			for _, insertErr := range insertErrs {
-				errMsg = fmt.Errorf("insert response err: %s: %s", insertErr.ErrorCode, insertErr.Message)
-			}
-		} else {
-			errMsg = fmt.Errorf("insert response err: %d %s", response.StatusCode, response.Status)
-		}
-
-		return InsertValue{}, errMsg
+		return InsertValue{}, sfdc.HandleError(response)
 	}
 
 	var value InsertValue
@@ -166,7 +158,7 @@ func (d *dml) updateCallout(updater Updater) error {
 
 func (d *dml) updateRequest(updater Updater) (*http.Request, error) {
 
-	url := d.session.ServiceURL() + objectEndpoint + updater.SObject() + "/" + updater.ID()
+	url := d.session.ServiceURL() + objectEndpoint + sfdc.EscapePath(updater.SObject(), updater.ID())
 
 	body, err := json.Marshal(updater.Fields())
 	if err != nil {
@@ -191,29 +183,20 @@ func (d *dml) updateResponse(request *http.Request) error {
 	if err != nil {
 		return err
 	}
+	defer sfdc.CloseResponse(response)
 
 	if response.StatusCode != http.StatusNoContent {
-		decoder := json.NewDecoder(response.Body)
-		defer response.Body.Close()
-
-		var updateErrs []sfdc.Error
-		err = decoder.Decode(&updateErrs)
-		var errMsg error
-		if err == nil {
-			for _, updateErr := range updateErrs {
-				errMsg = fmt.Errorf("insert response err: %s: %s", updateErr.ErrorCode, updateErr.Message)
-			}
-		} else {
-			errMsg = fmt.Errorf("insert response err: %d %s", response.StatusCode, response.Status)
-		}
-
-		return errMsg
+		return sfdc.HandleError(response)
 	}
 
 	return nil
 }
 
 func (d *dml) upsertCallout(upserter Upserter) (UpsertValue, error) {
+	if isIDField(upserter.ExternalField()) {
+		return d.upsertByIDCallout(upserter)
+	}
+
 	request, err := d.upsertRequest(upserter)
 
 	if err != nil {
@@ -229,8 +212,34 @@ func (d *dml) upsertCallout(upserter Upserter) (UpsertValue, error) {
 	return value, nil
 }
 
+// upsertByIDCallout upserts with the Id field as the external field.  Since an
+// Id only matches an existing record, the record is updated and the value is
+// never created.
+func (d *dml) upsertByIDCallout(upserter Upserter) (UpsertValue, error) {
+	if upserter.ID() == "" {
+		return UpsertValue{}, errors.New("upserter: id is required when the external field is Id")
+	}
+
+	err := d.updateCallout(upserter)
+	if err != nil {
+		return UpsertValue{}, err
+	}
+
+	return UpsertValue{
+		Created: false,
+		InsertValue: InsertValue{
+			Success: true,
+			ID:      upserter.ID(),
+		},
+	}, nil
+}
+
+func isIDField(field string) bool {
+	return strings.EqualFold(field, "Id")
+}
+
 func (d *dml) upsertRequest(upserter Upserter) (*http.Request, error) {
-	url := d.session.ServiceURL() + objectEndpoint + upserter.SObject() + "/" + upserter.ExternalField() + "/" + upserter.ID()
+	url := d.session.ServiceURL() + objectEndpoint + sfdc.EscapePath(upserter.SObject(), upserter.ExternalField(), upserter.ID())
 
 	// TODO: switch to json.NewEncoder():
 	body, err := json.Marshal(upserter.Fields())
@@ -255,7 +264,7 @@ func (d *dml) upsertResponse(request *http.Request) (UpsertValue, error) {
 		return UpsertValue{}, err
 	}
 
-	defer response.Body.Close()
+	defer sfdc.CloseResponse(response)
 	decoder := json.NewDecoder(response.Body)
 
 	var value UpsertValue
@@ -289,7 +298,7 @@ func (d *dml) deleteCallout(deleter Deleter) error {
 
 func (d *dml) deleteRequest(deleter Deleter) (*http.Request, error) {
 
-	url := d.session.ServiceURL() + objectEndpoint + deleter.SObject() + "/" + deleter.ID()
+	url := d.session.ServiceURL() + objectEndpoint + sfdc.EscapePath(deleter.SObject(), deleter.ID())
 
 	request, err := http.NewRequest(http.MethodDelete, url, nil)
 
@@ -308,9 +317,10 @@ func (d *dml) deleteResponse(request *http.Request) error {
 	if err != nil {
 		return err
 	}
+	defer sfdc.CloseResponse(response)
 
 	if response.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("delete has failed %d %s", response.StatusCode, response.Status)
+		return sfdc.HandleError(response)
 	}
 
 	return nil
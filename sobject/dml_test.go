@@ -2,7 +2,8 @@ package sobject
 
 import (
 	"bytes"
-	"io/ioutil"
+	"errors"
+	"io"
 	"net/http"
 	"reflect"
 	"strings"
@@ -67,7 +68,7 @@ func Test_dml_Insert(t *testing.T) {
 						return &http.Response{
 							StatusCode: 500,
 							Status:     "Some Status",
-							Body:       ioutil.NopCloser(strings.NewReader("resp")),
+							Body:       io.NopCloser(strings.NewReader("resp")),
 							Header:     make(http.Header),
 						}
 					}),
@@ -103,7 +104,7 @@ func Test_dml_Insert(t *testing.T) {
 						return &http.Response{
 							StatusCode: 500,
 							Status:     "Some Status",
-							Body:       ioutil.NopCloser(strings.NewReader(resp)),
+							Body:       io.NopCloser(strings.NewReader(resp)),
 							Header:     make(http.Header),
 						}
 					}),
@@ -132,7 +133,7 @@ func Test_dml_Insert(t *testing.T) {
 
 						return &http.Response{
 							StatusCode: http.StatusCreated,
-							Body:       ioutil.NopCloser(strings.NewReader(resp)),
+							Body:       io.NopCloser(strings.NewReader(resp)),
 							Header:     make(http.Header),
 						}
 					}),
@@ -165,7 +166,7 @@ func Test_dml_Insert(t *testing.T) {
 
 						return &http.Response{
 							StatusCode: http.StatusCreated,
-							Body:       ioutil.NopCloser(strings.NewReader(resp)),
+							Body:       io.NopCloser(strings.NewReader(resp)),
 							Header:     make(http.Header),
 						}
 					}),
@@ -270,7 +271,7 @@ func Test_dml_Update(t *testing.T) {
 						return &http.Response{
 							StatusCode: 500,
 							Status:     "Some Status",
-							Body:       ioutil.NopCloser(strings.NewReader(resp)),
+							Body:       io.NopCloser(strings.NewReader(resp)),
 							Header:     make(http.Header),
 						}
 					}),
@@ -397,7 +398,7 @@ func Test_dml_Upsert(t *testing.T) {
 						return &http.Response{
 							StatusCode: 500,
 							Status:     "Some Status",
-							Body:       ioutil.NopCloser(strings.NewReader(resp)),
+							Body:       io.NopCloser(strings.NewReader(resp)),
 							Header:     make(http.Header),
 						}
 					}),
@@ -435,7 +436,7 @@ func Test_dml_Upsert(t *testing.T) {
 						return &http.Response{
 							StatusCode: 500,
 							Status:     "Some Status",
-							Body:       ioutil.NopCloser(strings.NewReader(resp)),
+							Body:       io.NopCloser(strings.NewReader(resp)),
 							Header:     make(http.Header),
 						}
 					}),
@@ -466,7 +467,7 @@ func Test_dml_Upsert(t *testing.T) {
 
 						return &http.Response{
 							StatusCode: http.StatusCreated,
-							Body:       ioutil.NopCloser(strings.NewReader(resp)),
+							Body:       io.NopCloser(strings.NewReader(resp)),
 							Header:     make(http.Header),
 						}
 					}),
@@ -502,7 +503,7 @@ func Test_dml_Upsert(t *testing.T) {
 
 						return &http.Response{
 							StatusCode: http.StatusCreated,
-							Body:       ioutil.NopCloser(strings.NewReader(resp)),
+							Body:       io.NopCloser(strings.NewReader(resp)),
 							Header:     make(http.Header),
 						}
 					}),
@@ -544,7 +545,7 @@ func Test_dml_Upsert(t *testing.T) {
 
 						return &http.Response{
 							StatusCode: http.StatusOK,
-							Body:       ioutil.NopCloser(strings.NewReader(resp)),
+							Body:       io.NopCloser(strings.NewReader(resp)),
 							Header:     make(http.Header),
 						}
 					}),
@@ -580,7 +581,7 @@ func Test_dml_Upsert(t *testing.T) {
 						return &http.Response{
 							StatusCode: http.StatusNoContent,
 							Header:     make(http.Header),
-							Body:       ioutil.NopCloser(&bytes.Buffer{}),
+							Body:       io.NopCloser(&bytes.Buffer{}),
 						}
 					}),
 				},
@@ -601,6 +602,66 @@ func Test_dml_Upsert(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Upsert By Id",
+			fields: fields{
+				session: &mockSessionFormatter{
+					url: "https://test.salesforce.com",
+					client: mockHTTPClient(func(req *http.Request) *http.Response {
+						if req.Method != http.MethodPatch || req.URL.String() != "https://test.salesforce.com/sobjects/Account/001D000000IqhSLIAZ" {
+							return &http.Response{
+								StatusCode: 500,
+								Status:     "Invalid URL",
+								Body:       io.NopCloser(strings.NewReader(req.URL.String())),
+								Header:     make(http.Header),
+							}
+						}
+						return &http.Response{
+							StatusCode: http.StatusNoContent,
+							Header:     make(http.Header),
+							Body:       io.NopCloser(&bytes.Buffer{}),
+						}
+					}),
+				},
+			},
+			args: args{
+				upserter: &mockUpsert{
+					sobject:  "Account",
+					id:       "001D000000IqhSLIAZ",
+					external: "Id",
+					fields: map[string]interface{}{
+						"Name": "Some Test Name",
+					},
+				},
+			},
+			want: UpsertValue{
+				Created: false,
+				InsertValue: InsertValue{
+					Success: true,
+					ID:      "001D000000IqhSLIAZ",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Upsert By Id No Id",
+			fields: fields{
+				session: &mockSessionFormatter{
+					url: "https://test.salesforce.com",
+				},
+			},
+			args: args{
+				upserter: &mockUpsert{
+					sobject:  "Account",
+					external: "Id",
+					fields: map[string]interface{}{
+						"Name": "Some Test Name",
+					},
+				},
+			},
+			want:    UpsertValue{},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -716,3 +777,169 @@ func Test_dml_Delete(t *testing.T) {
 		})
 	}
 }
+
+func Test_dml_requestBodyIsDeterministic(t *testing.T) {
+	fields := map[string]interface{}{
+		"Website":       "www.salesforce.com",
+		"Name":          "test name",
+		"Industry":      "Banking",
+		"Phone":         "1234567890",
+		"AccountNumber": 42,
+		"Address": map[string]interface{}{
+			"Street": "1 Market St",
+			"City":   "San Francisco",
+		},
+	}
+	want := `{"AccountNumber":42,"Address":{"City":"San Francisco","Street":"1 Market St"},"Industry":"Banking","Name":"test name","Phone":"1234567890","Website":"www.salesforce.com"}`
+	d := &dml{
+		session: &mockSessionFormatter{
+			url: "https://test.salesforce.com",
+		},
+	}
+	requests := map[string]func() (*http.Request, error){
+		"insert": func() (*http.Request, error) {
+			return d.insertRequest(&mockInserter{sobject: "Account", fields: fields})
+		},
+		"update": func() (*http.Request, error) {
+			return d.updateRequest(&mockUpdate{sobject: "Account", id: "001D000000K0fXOIAZ", fields: fields})
+		},
+		"upsert": func() (*http.Request, error) {
+			return d.upsertRequest(&mockUpsert{sobject: "Account", id: "12345", external: "External__c", fields: fields})
+		},
+	}
+	for name, request := range requests {
+		t.Run(name, func(t *testing.T) {
+			for run := 0; run < 10; run++ {
+				req, err := request()
+				if err != nil {
+					t.Fatalf("request error = %v", err)
+				}
+				body, err := io.ReadAll(req.Body)
+				if err != nil {
+					t.Fatalf("request body error = %v", err)
+				}
+				if string(body) != want {
+					t.Fatalf("request body = %s, want %s", body, want)
+				}
+			}
+		})
+	}
+}
+
+func Test_dml_DeleteClosesBody(t *testing.T) {
+	body := newTrackingBody(`[{"errorCode": "ENTITY_IS_DELETED", "message": "entity is deleted"}]`)
+	d := &dml{
+		session: &mockSessionFormatter{
+			url: "https://test.salesforce.com",
+			client: mockHTTPClient(func(req *http.Request) *http.Response {
+				return &http.Response{
+					StatusCode: http.StatusNotFound,
+					Status:     "404 Not Found",
+					Body:       body,
+					Header:     make(http.Header),
+				}
+			}),
+		},
+	}
+	err := d.deleteCallout(&mockDelete{
+		sobject: "Account",
+		id:      "001D000000K0fXOIAZ",
+	})
+	if err == nil {
+		t.Fatalf("dml.deleteCallout() error = nil, want error")
+	}
+	if body.closed == false {
+		t.Errorf("dml.deleteCallout() response body was not closed")
+	}
+}
+
+func Test_dml_ErrorFields(t *testing.T) {
+	d := &dml{
+		session: &mockSessionFormatter{
+			url: "https://test.salesforce.com",
+			client: mockHTTPClient(func(req *http.Request) *http.Response {
+				return &http.Response{
+					StatusCode: http.StatusBadRequest,
+					Status:     "400 Bad Request",
+					Body:       io.NopCloser(strings.NewReader(`[{"fields": ["Name", "Industry"], "message": "Required fields are missing", "errorCode": "REQUIRED_FIELD_MISSING"}]`)),
+					Header:     make(http.Header),
+				}
+			}),
+		},
+	}
+	want := sfdc.Errors{
+		{
+			ErrorCode: "REQUIRED_FIELD_MISSING",
+			Message:   "Required fields are missing",
+			Fields:    []string{"Name", "Industry"},
+		},
+	}
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{
+			name: "update",
+			call: func() error {
+				return d.updateCallout(&mockUpdate{sobject: "Account", id: "001D000000K0fXOIAZ"})
+			},
+		},
+		{
+			name: "delete",
+			call: func() error {
+				return d.deleteCallout(&mockDelete{sobject: "Account", id: "001D000000K0fXOIAZ"})
+			},
+		},
+		{
+			name: "insert",
+			call: func() error {
+				_, err := d.insertCallout(&mockInserter{sobject: "Account"})
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sfdcErrs sfdc.Errors
+			if err := tt.call(); errors.As(err, &sfdcErrs) == false {
+				t.Fatalf("dml error = %v, want sfdc.Errors", err)
+			}
+			if !reflect.DeepEqual(sfdcErrs, want) {
+				t.Errorf("dml error = %+v, want %+v", sfdcErrs, want)
+			}
+		})
+	}
+}
+
+func Test_dml_requestEscapesIdentifiers(t *testing.T) {
+	d := &dml{
+		session: &mockSessionFormatter{
+			url: "https://test.salesforce.com",
+		},
+	}
+	upsert, err := d.upsertRequest(&mockUpsert{
+		sobject:  "Account",
+		external: "External__c",
+		id:       "EXT/1 +%é",
+		fields:   map[string]interface{}{},
+	})
+	if err != nil {
+		t.Fatalf("dml.upsertRequest() error = %v", err)
+	}
+	want := "https://test.salesforce.com/sobjects/Account/External__c/EXT%2F1%20%2B%25%C3%A9"
+	if got := upsert.URL.String(); got != want {
+		t.Errorf("dml.upsertRequest() url = %v, want %v", got, want)
+	}
+
+	del, err := d.deleteRequest(&mockDelete{
+		sobject: "Account",
+		id:      "../001?x=1",
+	})
+	if err != nil {
+		t.Fatalf("dml.deleteRequest() error = %v", err)
+	}
+	want = "https://test.salesforce.com/sobjects/Account/..%2F001%3Fx=1"
+	if got := del.URL.String(); got != want {
+		t.Errorf("dml.deleteRequest() url = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,111 @@
+package sobject
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/namely/go-sfdc/v3"
+	"github.com/namely/go-sfdc/v3/session"
+)
+
+// FetchOption is an option for fetching a record by its URL.
+type FetchOption func(*fetchOptions)
+
+type fetchOptions struct {
+	fields           []string
+	normalizeVersion bool
+}
+
+// WithFetchFields will only return the fields of the record.
+func WithFetchFields(fields ...string) FetchOption {
+	return func(opts *fetchOptions) {
+		opts.fields = fields
+	}
+}
+
+// WithSessionVersion will replace the API version in the record URL with the
+// session's version.  The record URLs have the version of the request that
+// returned them, which can be older than the session's version.
+func WithSessionVersion() FetchOption {
+	return func(opts *fetchOptions) {
+		opts.normalizeVersion = true
+	}
+}
+
+var versionPattern = regexp.MustCompile(`/services/data/v\d+\.\d+/`)
+
+type fetch struct {
+	session session.ServiceFormatter
+}
+
+func (f *fetch) callout(ctx context.Context, recordURL string, options ...FetchOption) (*sfdc.Record, error) {
+	request, err := f.request(ctx, recordURL, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.response(request)
+}
+
+func (f *fetch) request(ctx context.Context, recordURL string, options ...FetchOption) (*http.Request, error) {
+	opts := fetchOptions{}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	resolved, err := sfdc.RebaseServicePath(f.session.InstanceURL(), recordURL)
+	if err != nil {
+		return nil, err
+	}
+	if opts.normalizeVersion {
+		resolved = versionPattern.ReplaceAllString(resolved, fmt.Sprintf("/services/data/v%d.0/", f.session.Version()))
+	}
+	resolved, err = session.ServicePathContext(ctx, f.session, resolved)
+	if err != nil {
+		return nil, err
+	}
+
+	fetchURL, err := url.Parse(resolved)
+	if err != nil {
+		return nil, err
+	}
+	if len(opts.fields) > 0 {
+		query := fetchURL.Query()
+		query.Set("fields", strings.Join(opts.fields, ","))
+		fetchURL.RawQuery = query.Encode()
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, fetchURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Add("Accept", "application/json")
+	f.session.AuthorizationHeader(request)
+	return request, nil
+}
+
+func (f *fetch) response(request *http.Request) (*sfdc.Record, error) {
+	response, err := f.session.Client().Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer sfdc.CloseResponse(response)
+
+	if response.StatusCode != http.StatusOK {
+		return nil, sfdc.HandleError(response)
+	}
+
+	var record sfdc.Record
+	err = json.NewDecoder(response.Body).Decode(&record)
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
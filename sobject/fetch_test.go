@@ -0,0 +1,150 @@
+package sobject
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/namely/go-sfdc/v3"
+)
+
+func TestResources_FetchRecord(t *testing.T) {
+	var parent sfdc.Record
+	err := parent.UnmarshalJSON([]byte(`{
+		"attributes": {
+			"type": "Account",
+			"url": "/services/data/v20.0/sobjects/Account/001D000000INjVeIAL"
+		},
+		"Name": "Burlington Textiles"
+	}`))
+	if err != nil {
+		t.Fatalf("Record.UnmarshalJSON() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		options []FetchOption
+		wantURL string
+	}{
+		{
+			name:    "record url",
+			wantURL: "https://test.salesforce.com/services/data/v20.0/sobjects/Account/001D000000INjVeIAL",
+		},
+		{
+			name:    "session version",
+			options: []FetchOption{WithSessionVersion()},
+			wantURL: "https://test.salesforce.com/services/data/v42.0/sobjects/Account/001D000000INjVeIAL",
+		},
+		{
+			name:    "fields",
+			options: []FetchOption{WithSessionVersion(), WithFetchFields("Id", "Name", "Industry")},
+			wantURL: "https://test.salesforce.com/services/data/v42.0/sobjects/Account/001D000000INjVeIAL?fields=Id%2CName%2CIndustry",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotURL string
+			r := &Resources{
+				fetch: &fetch{
+					session: &mockSessionFormatter{
+						url: "https://test.salesforce.com",
+						client: mockHTTPClient(func(req *http.Request) *http.Response {
+							gotURL = req.URL.String()
+							resp := `{
+								"attributes": {
+									"type": "Account",
+									"url": "/services/data/v42.0/sobjects/Account/001D000000INjVeIAL"
+								},
+								"Id": "001D000000INjVeIAL",
+								"Name": "Burlington Textiles",
+								"Industry": "Apparel"
+							}`
+							return &http.Response{
+								StatusCode: http.StatusOK,
+								Body:       io.NopCloser(strings.NewReader(resp)),
+								Header:     make(http.Header),
+							}
+						}),
+					},
+				},
+			}
+
+			got, err := r.FetchRecord(context.Background(), &parent, tt.options...)
+			if err != nil {
+				t.Fatalf("Resources.FetchRecord() error = %v", err)
+			}
+			if gotURL != tt.wantURL {
+				t.Errorf("Resources.FetchRecord() url = %v, want %v", gotURL, tt.wantURL)
+			}
+			if got.SObject() != "Account" {
+				t.Errorf("Resources.FetchRecord() sobject = %v, want Account", got.SObject())
+			}
+			if industry, _ := got.FieldValue("Industry"); industry != "Apparel" {
+				t.Errorf("Resources.FetchRecord() Industry = %v, want Apparel", industry)
+			}
+		})
+	}
+}
+
+func TestResources_FetchByURL_errors(t *testing.T) {
+	var hosts []string
+	r := &Resources{
+		fetch: &fetch{
+			session: &mockSessionFormatter{
+				url: "https://test.salesforce.com",
+				client: mockHTTPClient(func(req *http.Request) *http.Response {
+					hosts = append(hosts, req.URL.Host)
+					resp := `[
+						{
+							"message": "The requested resource does not exist",
+							"errorCode": "NOT_FOUND"
+						}
+					]`
+					return &http.Response{
+						StatusCode: http.StatusNotFound,
+						Status:     "404 Not Found",
+						Body:       io.NopCloser(strings.NewReader(resp)),
+						Header:     make(http.Header),
+					}
+				}),
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		recordURL string
+	}{
+		{
+			name: "empty url",
+		},
+		{
+			name:      "not a service path",
+			recordURL: "/sobjects/Account/001D000000INjVeIAL",
+		},
+		{
+			name:      "another host",
+			recordURL: "https://evil.example.com/sobjects/Account/001D000000INjVeIAL",
+		},
+		{
+			name:      "not found",
+			recordURL: "/services/data/v42.0/sobjects/Account/001D000000INjVeIAL",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := r.FetchByURL(context.Background(), tt.recordURL); err == nil {
+				t.Errorf("Resources.FetchByURL() expected an error")
+			}
+		})
+	}
+	if len(hosts) != 1 || hosts[0] != "test.salesforce.com" {
+		t.Errorf("Resources.FetchByURL() sent requests to %v, want only the instance", hosts)
+	}
+
+	if _, err := r.FetchRecord(context.Background(), &sfdc.Record{}); err == nil {
+		t.Errorf("Resources.FetchRecord() expected an error for a record without a url")
+	}
+}
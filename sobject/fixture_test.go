@@ -0,0 +1,72 @@
+package sobject
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/namely/go-sfdc/v3/internal/fixture"
+)
+
+func TestDescribeValue_fixtures(t *testing.T) {
+	tests := []struct {
+		version     string
+		wantUnknown int
+	}{
+		{
+			version: "v42.0",
+		},
+		{
+			version:     "v50.0",
+			wantUnknown: 9,
+		},
+		{
+			version:     "v58.0",
+			wantUnknown: 11,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			data := fixture.Load(t, tt.version, "describe")
+			report, err := fixture.Check(data, reflect.TypeOf(DescribeValue{}))
+			if err != nil {
+				t.Fatalf("fixture.Check() error = %v", err)
+			}
+			if len(report.Mismatches) != 0 {
+				t.Errorf("fixture.Check() mismatches = %v", report.Mismatches)
+			}
+			if len(report.Unknown) != tt.wantUnknown {
+				t.Errorf("fixture.Check() unknown = %v, want %d fields", report.Unknown, tt.wantUnknown)
+			}
+			var want DescribeValue
+			fixture.Decode(t, data, &want)
+
+			resources, err := NewResources(&mockSessionFormatter{
+				url: "https://test.salesforce.com/services/data/" + tt.version,
+				client: mockHTTPClient(func(req *http.Request) *http.Response {
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(bytes.NewReader(data)),
+						Header:     make(http.Header),
+					}
+				}),
+			})
+			if err != nil {
+				t.Fatalf("NewResources() error = %v", err)
+			}
+			got, err := resources.DescribeContext(context.Background(), "Account")
+			if err != nil {
+				t.Fatalf("Resources.DescribeContext() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Resources.DescribeContext() = %+v, want %+v", got, want)
+			}
+			if got.Name != "Account" || len(got.Fields) != 2 || len(got.QueryableChildRelationships()) != 1 {
+				t.Errorf("Resources.DescribeContext() = %+v", got)
+			}
+		})
+	}
+}
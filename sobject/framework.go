@@ -1,6 +1,7 @@
 package sobject
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"time"
@@ -33,40 +34,52 @@ type Resources struct {
 	list     *list
 	dml      *dml
 	query    *query
+	recent   *recent
+	fetch    *fetch
 }
 
 const objectEndpoint = "/sobjects/"
 
 // NewResources forms the Salesforce SObject resource structure.  The
 // session formatter is required to form the proper URLs and authorization
-// header.
-func NewResources(session session.ServiceFormatter) (*Resources, error) {
-	if session == nil {
+// header.  The options are optional.
+func NewResources(formatter session.ServiceFormatter, options ...ResourcesOption) (*Resources, error) {
+	if formatter == nil {
 		return nil, errors.New("sobject resource: session can not be nil")
 	}
 
-	err := session.Refresh()
+	err := session.RefreshResource(formatter)
 	if err != nil {
 		return nil, errors.Wrap(err, "session refresh")
 	}
 
-	return &Resources{
+	resources := &Resources{
 		metadata: &metadata{
-			session: session,
+			session: formatter,
 		},
 		describe: &describe{
-			session: session,
+			session: formatter,
 		},
 		list: &list{
-			session: session,
+			session: formatter,
 		},
 		dml: &dml{
-			session: session,
+			session: formatter,
 		},
 		query: &query{
-			session: session,
+			session: formatter,
 		},
-	}, nil
+		recent: &recent{
+			session: formatter,
+		},
+		fetch: &fetch{
+			session: formatter,
+		},
+	}
+	for _, option := range options {
+		option(resources)
+	}
+	return resources, nil
 }
 
 // List returns the list of sObjects available
@@ -78,6 +91,47 @@ func (r *Resources) List() (ListValue, error) {
 	return r.list.callout()
 }
 
+// RecentlyViewed returns the records that were recently viewed by the user, of
+// any SObject.  The limit must be between 1 and MaxRecentLimit.
+func (r *Resources) RecentlyViewed(ctx context.Context, limit int) ([]*sfdc.Record, error) {
+	if r.recent == nil {
+		return nil, errors.New("salesforce api is not initialized properly")
+	}
+	if err := validateRecentLimit(limit); err != nil {
+		return nil, err
+	}
+
+	return r.recent.callout(ctx, limit)
+}
+
+// RecentlyViewedSObject returns the SObject's records that were recently viewed
+// by the user, from the recent items of the SObject's metadata.  The limit must
+// be between 1 and MaxRecentLimit.
+func (r *Resources) RecentlyViewedSObject(ctx context.Context, sobject string, limit int) ([]*sfdc.Record, error) {
+	if r.metadata == nil {
+		return nil, errors.New("salesforce api is not initialized properly")
+	}
+	if err := validateRecentLimit(limit); err != nil {
+		return nil, err
+	}
+
+	matching, err := regexp.MatchString(`\w`, sobject)
+	if err != nil {
+		return nil, err
+	}
+
+	if matching == false {
+		return nil, fmt.Errorf("sobject salesforce api: %s is not a valid sobject", sobject)
+	}
+
+	value, err := r.metadata.calloutContext(ctx, sobject)
+	if err != nil {
+		return nil, err
+	}
+
+	return recentRecords(value.RecentItems, limit)
+}
+
 // Metadata retrieves the SObject's metadata.
 func (r *Resources) Metadata(sobject string) (MetadataValue, error) {
 	if r.metadata == nil {
@@ -98,6 +152,12 @@ func (r *Resources) Metadata(sobject string) (MetadataValue, error) {
 
 // Describe retrieves the SObject's describe.
 func (r *Resources) Describe(sobject string) (DescribeValue, error) {
+	return r.DescribeContext(context.Background(), sobject)
+}
+
+// DescribeContext retrieves the SObject's describe using the context.  The
+// context's API version, if any, is used instead of the session's version.
+func (r *Resources) DescribeContext(ctx context.Context, sobject string) (DescribeValue, error) {
 	if r.describe == nil {
 		return DescribeValue{}, errors.New("salesforce api is not initialized properly")
 	}
@@ -111,7 +171,7 @@ func (r *Resources) Describe(sobject string) (DescribeValue, error) {
 		return DescribeValue{}, fmt.Errorf("sobject salesforce api: %s is not a valid sobject", sobject)
 	}
 
-	return r.describe.callout(sobject)
+	return r.describe.calloutContext(ctx, sobject)
 }
 
 // Insert will create a new Salesforce record.
@@ -182,6 +242,35 @@ func (r *Resources) Query(querier Querier) (*sfdc.Record, error) {
 	return r.query.callout(querier)
 }
 
+// FetchByURL returns the record at the record URL, like the url attribute of a
+// record or of a parent relationship from a query.  The URL can be relative to
+// the instance URL.  The context's API version, if any, replaces the version of
+// the URL.
+func (r *Resources) FetchByURL(ctx context.Context, recordURL string, options ...FetchOption) (*sfdc.Record, error) {
+	if r.fetch == nil {
+		return nil, errors.New("salesforce api is not initialized properly")
+	}
+
+	if recordURL == "" {
+		return nil, errors.New("sobject salesforce api: record url can not be empty")
+	}
+
+	return r.fetch.callout(ctx, recordURL, options...)
+}
+
+// FetchRecord returns the full record from the URL attribute of the record,
+// which can be a partial record, like a parent relationship from a query.
+func (r *Resources) FetchRecord(ctx context.Context, record *sfdc.Record, options ...FetchOption) (*sfdc.Record, error) {
+	if record == nil {
+		return nil, errors.New("sobject salesforce api: record can not be nil")
+	}
+	if record.URL() == "" {
+		return nil, errors.New("sobject salesforce api: record does not have a url attribute")
+	}
+
+	return r.FetchByURL(ctx, record.URL(), options...)
+}
+
 // ExternalQuery returns a SObject record using an external ID field.
 func (r *Resources) ExternalQuery(querier ExternalQuerier) (*sfdc.Record, error) {
 	if r.query == nil {
@@ -658,6 +658,16 @@ func TestNewResources(t *testing.T) {
 						url: "https://test.salesforce.com",
 					},
 				},
+				recent: &recent{
+					session: &mockSessionFormatter{
+						url: "https://test.salesforce.com",
+					},
+				},
+				fetch: &fetch{
+					session: &mockSessionFormatter{
+						url: "https://test.salesforce.com",
+					},
+				},
 			},
 			wantErr: false,
 		},
@@ -15,6 +15,7 @@ type ListValue struct {
 
 type list struct {
 	session session.ServiceFormatter
+	cache   *describeCache
 }
 
 func (l *list) callout() (ListValue, error) {
@@ -45,13 +46,13 @@ func (l *list) request() (*http.Request, error) {
 }
 
 func (l *list) response(request *http.Request) (ListValue, error) {
-	response, err := l.session.Client().Do(request)
+	response, err := l.cache.do(l.session, request)
 	if err != nil {
 		return ListValue{}, err
 	}
 
 	decoder := json.NewDecoder(response.Body)
-	defer response.Body.Close()
+	defer sfdc.CloseResponse(response)
 
 	if response.StatusCode != http.StatusOK {
 		return ListValue{}, sfdc.HandleError(response)
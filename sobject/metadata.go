@@ -1,6 +1,7 @@
 package sobject
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -45,11 +46,16 @@ type ObjectDescribe struct {
 
 type metadata struct {
 	session session.ServiceFormatter
+	cache   *describeCache
 }
 
 func (md *metadata) callout(sobject string) (MetadataValue, error) {
+	return md.calloutContext(context.Background(), sobject)
+}
+
+func (md *metadata) calloutContext(ctx context.Context, sobject string) (MetadataValue, error) {
 
-	request, err := md.request(sobject)
+	request, err := md.request(ctx, sobject)
 
 	if err != nil {
 		return MetadataValue{}, err
@@ -64,10 +70,14 @@ func (md *metadata) callout(sobject string) (MetadataValue, error) {
 	return value, nil
 }
 
-func (md *metadata) request(sobject string) (*http.Request, error) {
-	url := md.session.ServiceURL() + objectEndpoint + sobject
+func (md *metadata) request(ctx context.Context, sobject string) (*http.Request, error) {
+	serviceURL, err := session.ServiceURLContext(ctx, md.session)
+	if err != nil {
+		return nil, err
+	}
+	url := serviceURL + objectEndpoint + sfdc.EscapePath(sobject)
 
-	request, err := http.NewRequest(http.MethodGet, url, nil)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 
 	if err != nil {
 		return nil, err
@@ -80,14 +90,14 @@ func (md *metadata) request(sobject string) (*http.Request, error) {
 }
 
 func (md *metadata) response(request *http.Request) (MetadataValue, error) {
-	response, err := md.session.Client().Do(request)
+	response, err := md.cache.do(md.session, request)
 
 	if err != nil {
 		return MetadataValue{}, err
 	}
 
 	decoder := json.NewDecoder(response.Body)
-	defer response.Body.Close()
+	defer sfdc.CloseResponse(response)
 
 	if response.StatusCode != http.StatusOK {
 		var respErrs []sfdc.Error
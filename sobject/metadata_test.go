@@ -1,7 +1,7 @@
 package sobject
 
 import (
-	"io/ioutil"
+	"io"
 	"net/http"
 	"reflect"
 	"strings"
@@ -55,7 +55,7 @@ func Test_metadata_Metadata(t *testing.T) {
 						return &http.Response{
 							StatusCode: 500,
 							Status:     "Some Status",
-							Body:       ioutil.NopCloser(strings.NewReader(resp)),
+							Body:       io.NopCloser(strings.NewReader(resp)),
 							Header:     make(http.Header),
 						}
 					}),
@@ -78,7 +78,7 @@ func Test_metadata_Metadata(t *testing.T) {
 
 						return &http.Response{
 							StatusCode: 200,
-							Body:       ioutil.NopCloser(strings.NewReader(resp)),
+							Body:       io.NopCloser(strings.NewReader(resp)),
 							Header:     make(http.Header),
 						}
 					}),
@@ -139,7 +139,7 @@ func Test_metadata_Metadata(t *testing.T) {
 
 						return &http.Response{
 							StatusCode: 200,
-							Body:       ioutil.NopCloser(strings.NewReader(resp)),
+							Body:       io.NopCloser(strings.NewReader(resp)),
 							Header:     make(http.Header),
 						}
 					}),
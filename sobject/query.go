@@ -3,7 +3,7 @@ package sobject
 import (
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"net/http"
 	"net/url"
 	"strings"
@@ -101,7 +101,7 @@ func (q *query) callout(querier Querier) (*sfdc.Record, error) {
 }
 func (q *query) queryRequest(querier Querier) (*http.Request, error) {
 
-	queryURL := q.session.ServiceURL() + objectEndpoint + querier.SObject() + "/" + querier.ID()
+	queryURL := q.session.ServiceURL() + objectEndpoint + sfdc.EscapePath(querier.SObject(), querier.ID())
 
 	if len(querier.Fields()) > 0 {
 		fields := strings.Join(querier.Fields(), ",")
@@ -130,7 +130,7 @@ func (q *query) queryResponse(request *http.Request) (*sfdc.Record, error) {
 	}
 
 	decoder := json.NewDecoder(response.Body)
-	defer response.Body.Close()
+	defer sfdc.CloseResponse(response)
 
 	if response.StatusCode != http.StatusOK {
 		var queryErrs []sfdc.Error
@@ -174,7 +174,7 @@ func (q *query) externalCallout(querier ExternalQuerier) (*sfdc.Record, error) {
 
 func (q *query) externalQueryRequest(querier ExternalQuerier) (*http.Request, error) {
 
-	queryURL := q.session.ServiceURL() + objectEndpoint + querier.SObject() + "/" + querier.ExternalField() + "/" + querier.ID()
+	queryURL := q.session.ServiceURL() + objectEndpoint + sfdc.EscapePath(querier.SObject(), querier.ExternalField(), querier.ID())
 
 	if len(querier.Fields()) > 0 {
 		fields := strings.Join(querier.Fields(), ",")
@@ -218,7 +218,7 @@ func (q *query) deletedRecordsResponse(request *http.Request) (DeletedRecords, e
 	}
 
 	decoder := json.NewDecoder(response.Body)
-	defer response.Body.Close()
+	defer sfdc.CloseResponse(response)
 
 	if response.StatusCode != http.StatusOK {
 		return DeletedRecords{}, fmt.Errorf("deleted records response err: %d %s", response.StatusCode, response.Status)
@@ -276,7 +276,7 @@ func (q *query) updatedRecordsResponse(request *http.Request) (UpdatedRecords, e
 	}
 
 	decoder := json.NewDecoder(response.Body)
-	defer response.Body.Close()
+	defer sfdc.CloseResponse(response)
 
 	if response.StatusCode != http.StatusOK {
 		return UpdatedRecords{}, fmt.Errorf("deleted records response err: %d %s", response.StatusCode, response.Status)
@@ -304,7 +304,7 @@ func (q *query) operationRequest(sobject, operation string, startDate, endDate t
 	form.Add("end", endDate.Format(time.RFC3339))
 	dateRange := "?" + form.Encode()
 
-	queryURL := q.session.ServiceURL() + objectEndpoint + sobject + "/" + operation + "/" + dateRange
+	queryURL := q.session.ServiceURL() + objectEndpoint + sfdc.EscapePath(sobject, operation) + "/" + dateRange
 
 	request, err := http.NewRequest(http.MethodGet, queryURL, nil)
 
@@ -329,7 +329,7 @@ func (q *query) contentCallout(id string, content ContentType) ([]byte, error) {
 }
 func (q *query) contentRequest(id string, content ContentType) (*http.Request, error) {
 
-	queryURL := q.session.ServiceURL() + objectEndpoint + string(content) + "/" + id + "/" + contentBody
+	queryURL := q.session.ServiceURL() + objectEndpoint + sfdc.EscapePath(string(content), id, contentBody)
 
 	request, err := http.NewRequest(http.MethodGet, queryURL, nil)
 
@@ -347,13 +347,13 @@ func (q *query) contentResponse(request *http.Request) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	defer sfdc.CloseResponse(response)
 
 	if response.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("deleted records response err: %d %s", response.StatusCode, response.Status)
 	}
 
-	body, err := ioutil.ReadAll(response.Body)
-	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
 
 	return body, err
 }
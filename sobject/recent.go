@@ -0,0 +1,91 @@
+package sobject
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/namely/go-sfdc/v3"
+	"github.com/namely/go-sfdc/v3/session"
+)
+
+const (
+	recentEndpoint = "/recent"
+	// MaxRecentLimit is the maximum number of recently viewed records that can be requested.
+	MaxRecentLimit = 200
+)
+
+type recent struct {
+	session session.ServiceFormatter
+}
+
+func (rc *recent) callout(ctx context.Context, limit int) ([]*sfdc.Record, error) {
+	request, err := rc.request(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return rc.response(request)
+}
+
+func (rc *recent) request(ctx context.Context, limit int) (*http.Request, error) {
+	serviceURL, err := session.ServiceURLContext(ctx, rc.session)
+	if err != nil {
+		return nil, err
+	}
+	url := serviceURL + recentEndpoint + "?limit=" + strconv.Itoa(limit)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Add("Accept", "application/json")
+	rc.session.AuthorizationHeader(request)
+	return request, nil
+}
+
+func (rc *recent) response(request *http.Request) ([]*sfdc.Record, error) {
+	response, err := rc.session.Client().Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer sfdc.CloseResponse(response)
+
+	if response.StatusCode != http.StatusOK {
+		return nil, sfdc.HandleError(response)
+	}
+
+	var items []map[string]interface{}
+	err = json.NewDecoder(response.Body).Decode(&items)
+	if err != nil {
+		return nil, err
+	}
+
+	return recentRecords(items, len(items))
+}
+
+// recentRecords decodes the recent items, along with their attributes, into
+// at most limit records.
+func recentRecords(items []map[string]interface{}, limit int) ([]*sfdc.Record, error) {
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	records := make([]*sfdc.Record, len(items))
+	for idx, item := range items {
+		record, err := sfdc.RecordFromJSONMap(item)
+		if err != nil {
+			return nil, err
+		}
+		records[idx] = record
+	}
+	return records, nil
+}
+
+func validateRecentLimit(limit int) error {
+	if limit < 1 || limit > MaxRecentLimit {
+		return fmt.Errorf("sobject salesforce api: recent limit %d must be between 1 and %d", limit, MaxRecentLimit)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package sobject
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/namely/go-sfdc/v3"
+)
+
+func TestResources_RecentlyViewed(t *testing.T) {
+	tests := []struct {
+		name     string
+		limit    int
+		response string
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name:  "mixed objects",
+			limit: 2,
+			response: `[
+				{
+					"attributes": {
+						"type": "Account",
+						"url": "/services/data/v44.0/sobjects/Account/001D000000INjVeIAL"
+					},
+					"Id": "001D000000INjVeIAL",
+					"Name": "Burlington Textiles"
+				},
+				{
+					"attributes": {
+						"type": "Contact",
+						"url": "/services/data/v44.0/sobjects/Contact/003D000000QV9n2IAD"
+					},
+					"Id": "003D000000QV9n2IAD",
+					"Name": "Jack Rogers"
+				}
+			]`,
+			want:    []string{"Account:001D000000INjVeIAL:Burlington Textiles", "Contact:003D000000QV9n2IAD:Jack Rogers"},
+			wantErr: false,
+		},
+		{
+			name:    "limit too small",
+			limit:   0,
+			wantErr: true,
+		},
+		{
+			name:    "limit too large",
+			limit:   MaxRecentLimit + 1,
+			wantErr: true,
+		},
+		{
+			name:     "max limit",
+			limit:    MaxRecentLimit,
+			response: `[]`,
+			want:     []string{},
+			wantErr:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Resources{
+				recent: &recent{
+					session: &mockSessionFormatter{
+						url: "https://test.salesforce.com",
+						client: mockHTTPClient(func(req *http.Request) *http.Response {
+							if req.URL.Path != "/recent" || req.URL.Query().Get("limit") != "200" && req.URL.Query().Get("limit") != "2" {
+								return &http.Response{
+									StatusCode: 500,
+									Status:     "Invalid URL",
+									Body:       io.NopCloser(strings.NewReader(req.URL.String())),
+									Header:     make(http.Header),
+								}
+							}
+							return &http.Response{
+								StatusCode: http.StatusOK,
+								Status:     "Good",
+								Body:       io.NopCloser(strings.NewReader(tt.response)),
+								Header:     make(http.Header),
+							}
+						}),
+					},
+				},
+			}
+			records, err := r.RecentlyViewed(context.Background(), tt.limit)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resources.RecentlyViewed() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := recentSummary(records); strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("Resources.RecentlyViewed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResources_RecentlyViewedSObject(t *testing.T) {
+	r := &Resources{
+		metadata: &metadata{
+			session: &mockSessionFormatter{
+				url: "https://test.salesforce.com",
+				client: mockHTTPClient(func(req *http.Request) *http.Response {
+					resp := `{
+						"objectDescribe": {
+							"name": "Account"
+						},
+						"recentItems": [
+							{
+								"attributes": {
+									"type": "Account",
+									"url": "/services/data/v44.0/sobjects/Account/001D000000INjVeIAL"
+								},
+								"Id": "001D000000INjVeIAL",
+								"Name": "Burlington Textiles"
+							},
+							{
+								"attributes": {
+									"type": "Account",
+									"url": "/services/data/v44.0/sobjects/Account/001D000000INjVfIAL"
+								},
+								"Id": "001D000000INjVfIAL",
+								"Name": "Dickenson plc"
+							}
+						]
+					}`
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Status:     "Good",
+						Body:       io.NopCloser(strings.NewReader(resp)),
+						Header:     make(http.Header),
+					}
+				}),
+			},
+		},
+	}
+	records, err := r.RecentlyViewedSObject(context.Background(), "Account", 1)
+	if err != nil {
+		t.Fatalf("Resources.RecentlyViewedSObject() error = %v", err)
+	}
+	want := []string{"Account:001D000000INjVeIAL:Burlington Textiles"}
+	if got := recentSummary(records); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("Resources.RecentlyViewedSObject() = %v, want %v", got, want)
+	}
+	if url := records[0].URL(); url != "/services/data/v44.0/sobjects/Account/001D000000INjVeIAL" {
+		t.Errorf("Resources.RecentlyViewedSObject() url = %v", url)
+	}
+
+	if _, err := r.RecentlyViewedSObject(context.Background(), "Account", 0); err == nil {
+		t.Errorf("Resources.RecentlyViewedSObject() error = nil, want limit error")
+	}
+}
+
+func recentSummary(records []*sfdc.Record) []string {
+	summary := make([]string, len(records))
+	for idx, record := range records {
+		id, _ := record.FieldValue("Id")
+		name, _ := record.FieldValue("Name")
+		summary[idx] = record.SObject() + ":" + id.(string) + ":" + name.(string)
+	}
+	return summary
+}
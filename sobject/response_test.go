@@ -0,0 +1,55 @@
+package sobject
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestResources_errorsReuseConnections(t *testing.T) {
+	// each call reads or drains the error body, so the connection is reused
+	body := `[{"errorCode": "ENTITY_IS_DELETED", "message": "` + strings.Repeat("x", 16<<10) + `"}]`
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(body))
+	}))
+	var connections int32
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&connections, 1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	session := &mockSessionFormatter{
+		url:    server.URL,
+		client: server.Client(),
+	}
+	resources := &Resources{
+		dml: &dml{
+			session: session,
+		},
+		query: &query{
+			session: session,
+		},
+	}
+	for idx := 0; idx < 5; idx++ {
+		if err := resources.Delete(&mockDelete{sobject: "Account", id: "001D000000K0fXOIAZ"}); err == nil {
+			t.Fatal("Resources.Delete() expected an error")
+		}
+		if _, err := resources.GetContent("00PD000000K0fXOIAZ", AttachmentType); err == nil {
+			t.Fatal("Resources.GetContent() expected an error")
+		}
+		if err := resources.Update(&mockUpdate{sobject: "Account", id: "001D000000K0fXOIAZ"}); err == nil {
+			t.Fatal("Resources.Update() expected an error")
+		}
+	}
+	if got := atomic.LoadInt32(&connections); got != 1 {
+		t.Errorf("Resources calls opened %d connections, want 1", got)
+	}
+}
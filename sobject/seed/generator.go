@@ -0,0 +1,38 @@
+package seed
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Generator returns the value of a field of the record at the index, which
+// counts the records of the object from zero, across all of their parents.
+// The random source is seeded by the plan, so a plan generates the same
+// values each time it is executed.  A generator can be a callback, like a
+// faker that uses the random source.
+type Generator func(index int, rnd *rand.Rand) interface{}
+
+// Static generates the same value for every record.
+func Static(value interface{}) Generator {
+	return func(int, *rand.Rand) interface{} {
+		return value
+	}
+}
+
+// Sequence generates the format with the record's number, which starts at
+// one, like Sequence("Account %03d") for Account 001, Account 002 and so on.
+func Sequence(format string) Generator {
+	return func(index int, _ *rand.Rand) interface{} {
+		return fmt.Sprintf(format, index+1)
+	}
+}
+
+// OneOf generates one of the values, picked with the random source.
+func OneOf(values ...interface{}) Generator {
+	return func(_ int, rnd *rand.Rand) interface{} {
+		if len(values) == 0 {
+			return nil
+		}
+		return values[rnd.Intn(len(values))]
+	}
+}
@@ -0,0 +1,379 @@
+// Package seed creates related test records, like accounts with their
+// contacts and opportunities, from a declarative plan.
+package seed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"github.com/namely/go-sfdc/v3"
+	"github.com/namely/go-sfdc/v3/session"
+	"github.com/namely/go-sfdc/v3/sobject"
+	"github.com/namely/go-sfdc/v3/sobject/collections"
+	"github.com/namely/go-sfdc/v3/sobject/tree"
+)
+
+// maxCollectionRecords is the maximum number of records of a SObject
+// Collections request.
+const maxCollectionRecords = 200
+
+// Plan is the records to create.
+//
+// Seed seeds the random source of the generators, so the plan creates the same
+// records each time it is executed.
+//
+// Objects are the top level objects, which are created in order.
+type Plan struct {
+	Seed    int64
+	Objects []Object
+}
+
+// Object is the records of a SObject to create.
+//
+// SObject is the Salesforce table name.  An example would be Account or Custom__c.
+//
+// Count is the number of records.  A child object has Count records for each
+// of its parent's records.
+//
+// Fields are the generators of the records' fields.
+//
+// Relationship is the parent's child relationship name of a child object, like
+// Contacts.  It is not used by the top level objects.
+//
+// Children are the objects that are created with each of the records.
+type Object struct {
+	SObject      string
+	Count        int
+	Fields       map[string]Generator
+	Relationship string
+	Children     []Object
+}
+
+// Resources are the APIs used to create and delete the records.
+type Resources struct {
+	tree        *tree.Resource
+	collections *collections.Resource
+}
+
+// NewResources forms the seed resources from the session.  The session
+// formatter is required to form the proper URLs and authorization header.
+func NewResources(formatter session.ServiceFormatter) (*Resources, error) {
+	if formatter == nil {
+		return nil, errors.New("seed: session can not be nil")
+	}
+	treeResource, err := tree.NewResource(formatter)
+	if err != nil {
+		return nil, err
+	}
+	collectionsResource, err := collections.NewResources(formatter)
+	if err != nil {
+		return nil, err
+	}
+	return &Resources{
+		tree:        treeResource,
+		collections: collectionsResource,
+	}, nil
+}
+
+// Seeded is the records that a plan created.
+//
+// IDs are the Salesforce IDs of the records, by SObject, in the order they
+// were created.
+type Seeded struct {
+	IDs map[string][]string
+
+	resources *Resources
+	records   []seededRecord
+}
+
+type seededRecord struct {
+	id    string
+	depth int
+}
+
+func (s *Seeded) add(sobject, id string, depth int) {
+	s.IDs[sobject] = append(s.IDs[sobject], id)
+	s.records = append(s.records, seededRecord{id: id, depth: depth})
+}
+
+// Execute will create the plan's records.  The objects with children are
+// created with the SObject Tree API, so the children are linked to their
+// parents, and the other objects with the SObject Collections API.  If the
+// creation fails, the records that were created are returned with the error,
+// so they can be deleted with Teardown.
+func (p Plan) Execute(ctx context.Context, resources *Resources) (*Seeded, error) {
+	if resources == nil {
+		return nil, errors.New("seed: resources can not be nil")
+	}
+	for _, object := range p.Objects {
+		if err := object.validate(false); err != nil {
+			return nil, err
+		}
+	}
+
+	generator := &planGenerator{
+		rnd:     rand.New(rand.NewSource(p.Seed)),
+		indexes: make(map[*Object]int),
+		refs:    make(map[string]treeRef),
+	}
+	seeded := &Seeded{
+		IDs:       make(map[string][]string),
+		resources: resources,
+	}
+	for idx := range p.Objects {
+		object := &p.Objects[idx]
+		var err error
+		if len(object.Children) > 0 {
+			err = generator.insertTree(ctx, resources, seeded, object)
+		} else {
+			err = generator.insertCollection(ctx, resources, seeded, object)
+		}
+		if err != nil {
+			return seeded, err
+		}
+	}
+	return seeded, nil
+}
+
+func (o Object) validate(child bool) error {
+	if o.SObject == "" {
+		return errors.New("seed: object's SObject can not be empty")
+	}
+	if o.Count <= 0 {
+		return fmt.Errorf("seed: %s count must be greater than zero", o.SObject)
+	}
+	if child && o.Relationship == "" {
+		return fmt.Errorf("seed: child %s relationship can not be empty", o.SObject)
+	}
+	for _, child := range o.Children {
+		if err := child.validate(true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type treeRef struct {
+	sobject string
+	depth   int
+}
+
+// planGenerator generates the records in the plan's order, so the random
+// source gives the same values each time.
+type planGenerator struct {
+	rnd     *rand.Rand
+	indexes map[*Object]int
+	refs    map[string]treeRef
+}
+
+// fields generates the fields of the object's next record.  The fields are
+// generated in the order of their names.
+func (g *planGenerator) fields(object *Object) map[string]interface{} {
+	index := g.indexes[object]
+	g.indexes[object]++
+
+	names := make([]string, 0, len(object.Fields))
+	for name := range object.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fields := make(map[string]interface{}, len(names))
+	for _, name := range names {
+		if generate := object.Fields[name]; generate != nil {
+			fields[name] = generate(index, g.rnd)
+		}
+	}
+	return fields
+}
+
+// treeRecord generates the object's next record with its children.
+func (g *planGenerator) treeRecord(object *Object, depth int) *tree.Record {
+	referenceID := fmt.Sprintf("ref%d", len(g.refs)+1)
+	g.refs[referenceID] = treeRef{sobject: object.SObject, depth: depth}
+	record := &tree.Record{
+		Attributes: tree.Attributes{
+			Type:        object.SObject,
+			ReferenceID: referenceID,
+		},
+		Fields: g.fields(object),
+	}
+	for idx := range object.Children {
+		child := &object.Children[idx]
+		if record.Records == nil {
+			record.Records = make(map[string][]*tree.Record)
+		}
+		for i := 0; i < child.Count; i++ {
+			record.Records[child.Relationship] = append(record.Records[child.Relationship], g.treeRecord(child, depth+1))
+		}
+	}
+	return record
+}
+
+type treeInserter struct {
+	sobject string
+	records []*tree.Record
+}
+
+func (t *treeInserter) SObject() string {
+	return t.sobject
+}
+
+func (t *treeInserter) Records() []*tree.Record {
+	return t.records
+}
+
+func (g *planGenerator) insertTree(ctx context.Context, resources *Resources, seeded *Seeded, object *Object) error {
+	inserter := &treeInserter{
+		sobject: object.SObject,
+	}
+	for i := 0; i < object.Count; i++ {
+		inserter.records = append(inserter.records, g.treeRecord(object, 0))
+	}
+
+	value, err := resources.tree.InsertChunked(ctx, inserter, tree.MaxRecords)
+	if value != nil {
+		for _, result := range value.Results {
+			ref, ok := g.refs[result.ReferenceID]
+			if ok == false || result.ID == "" {
+				continue
+			}
+			seeded.add(ref.sobject, result.ID, ref.depth)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("seed: %s tree: %w", object.SObject, err)
+	}
+	return nil
+}
+
+type collectionRecord struct {
+	sobject string
+	fields  map[string]interface{}
+}
+
+func (c *collectionRecord) SObject() string {
+	return c.sobject
+}
+
+func (c *collectionRecord) Fields() map[string]interface{} {
+	return c.fields
+}
+
+func (g *planGenerator) insertCollection(ctx context.Context, resources *Resources, seeded *Seeded, object *Object) error {
+	records := make([]sobject.Inserter, object.Count)
+	for idx := range records {
+		records[idx] = &collectionRecord{
+			sobject: object.SObject,
+			fields:  g.fields(object),
+		}
+	}
+
+	for start := 0; start < len(records); start += maxCollectionRecords {
+		end := start + maxCollectionRecords
+		if end > len(records) {
+			end = len(records)
+		}
+		values, err := resources.collections.InsertContext(ctx, true, records[start:end])
+		if err != nil {
+			return fmt.Errorf("seed: %s collection: %w", object.SObject, err)
+		}
+		var failures []string
+		for idx, value := range values {
+			if value.Success == false {
+				failures = append(failures, fmt.Sprintf("record %d %s", start+idx, errorCodes(value.Errors)))
+				continue
+			}
+			seeded.add(object.SObject, value.ID, 0)
+		}
+		if len(failures) > 0 {
+			return fmt.Errorf("seed: %s collection: %s", object.SObject, strings.Join(failures, "; "))
+		}
+	}
+	return nil
+}
+
+// Teardown will delete the records in the reverse order of their
+// dependencies, so the children are deleted before their parents.  The records
+// of each level are deleted in the reverse order they were created.  The
+// records that are deleted are removed from the IDs, so a failed teardown can
+// be called again for the records that are left.
+func (s *Seeded) Teardown(ctx context.Context) error {
+	if s.resources == nil {
+		return errors.New("seed: seeded records were not created by a plan")
+	}
+
+	levels := make(map[int][]string)
+	var depths []int
+	for idx := len(s.records) - 1; idx >= 0; idx-- {
+		record := s.records[idx]
+		if _, ok := levels[record.depth]; ok == false {
+			depths = append(depths, record.depth)
+		}
+		levels[record.depth] = append(levels[record.depth], record.id)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(depths)))
+
+	deleted := make(map[string]bool)
+	defer s.remove(deleted)
+	for _, depth := range depths {
+		ids := levels[depth]
+		for start := 0; start < len(ids); start += maxCollectionRecords {
+			end := start + maxCollectionRecords
+			if end > len(ids) {
+				end = len(ids)
+			}
+			values, err := s.resources.collections.DeleteContext(ctx, false, ids[start:end])
+			if err != nil {
+				return fmt.Errorf("seed: teardown: %w", err)
+			}
+			var failures []string
+			for idx, value := range values {
+				if value.Success == false {
+					failures = append(failures, fmt.Sprintf("%s %s", ids[start+idx], errorCodes(value.Errors)))
+					continue
+				}
+				deleted[ids[start+idx]] = true
+			}
+			if len(failures) > 0 {
+				return fmt.Errorf("seed: teardown: %s", strings.Join(failures, "; "))
+			}
+		}
+	}
+	return nil
+}
+
+// remove removes the deleted records from the seeded records.
+func (s *Seeded) remove(deleted map[string]bool) {
+	records := s.records[:0]
+	for _, record := range s.records {
+		if deleted[record.id] == false {
+			records = append(records, record)
+		}
+	}
+	s.records = records
+	for sobject, ids := range s.IDs {
+		left := ids[:0]
+		for _, id := range ids {
+			if deleted[id] == false {
+				left = append(left, id)
+			}
+		}
+		if len(left) == 0 {
+			delete(s.IDs, sobject)
+			continue
+		}
+		s.IDs[sobject] = left
+	}
+}
+
+func errorCodes(errs []sfdc.Error) string {
+	codes := make([]string, 0, len(errs))
+	for _, err := range errs {
+		codes = append(codes, err.ErrorCode)
+	}
+	return strings.Join(codes, ", ")
+}
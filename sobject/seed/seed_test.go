@@ -0,0 +1,245 @@
+package seed
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// mockOrg creates the records of the tree and collections requests, with an
+// ID from their reference ID or position, and records the requests.
+type mockOrg struct {
+	created  int
+	requests []string
+	bodies   []map[string]interface{}
+	deletes  [][]string
+}
+
+func (org *mockOrg) roundTrip(req *http.Request) *http.Response {
+	org.requests = append(org.requests, req.Method+" "+req.URL.Path)
+	var body map[string]interface{}
+	if req.Body != nil {
+		json.NewDecoder(req.Body).Decode(&body)
+		org.bodies = append(org.bodies, body)
+	}
+
+	var resp interface{}
+	status := http.StatusOK
+	switch {
+	case req.Method == http.MethodPost && strings.HasPrefix(req.URL.Path, "/composite/tree/"):
+		var results []map[string]interface{}
+		var walk func(records []interface{})
+		walk = func(records []interface{}) {
+			for _, record := range records {
+				rec := record.(map[string]interface{})
+				ref := rec["attributes"].(map[string]interface{})["referenceId"].(string)
+				results = append(results, map[string]interface{}{"referenceId": ref, "id": "id-" + ref})
+				names := make([]string, 0, len(rec))
+				for name := range rec {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+				for _, name := range names {
+					if children, ok := rec[name].(map[string]interface{}); ok && children["records"] != nil {
+						walk(children["records"].([]interface{}))
+					}
+				}
+			}
+		}
+		walk(body["records"].([]interface{}))
+		resp = map[string]interface{}{"hasErrors": false, "results": results}
+		status = http.StatusCreated
+	case req.Method == http.MethodPost:
+		var values []map[string]interface{}
+		for range body["records"].([]interface{}) {
+			org.created++
+			values = append(values, map[string]interface{}{"id": fmt.Sprintf("flat%d", org.created), "success": true, "errors": []interface{}{}})
+		}
+		resp = values
+	case req.Method == http.MethodDelete:
+		ids := strings.Split(req.URL.Query().Get("ids"), ",")
+		org.deletes = append(org.deletes, ids)
+		var values []map[string]interface{}
+		for _, id := range ids {
+			values = append(values, map[string]interface{}{"id": id, "success": true, "errors": []interface{}{}})
+		}
+		resp = values
+	}
+	data, _ := json.Marshal(resp)
+	return &http.Response{
+		StatusCode: status,
+		Status:     http.StatusText(status),
+		Body:       io.NopCloser(strings.NewReader(string(data))),
+		Header:     make(http.Header),
+	}
+}
+
+func testPlan(seed int64) Plan {
+	return Plan{
+		Seed: seed,
+		Objects: []Object{
+			{
+				SObject: "Account",
+				Count:   2,
+				Fields: map[string]Generator{
+					"Name":     Sequence("Account %02d"),
+					"Industry": OneOf("Banking", "Energy", "Retail", "Media"),
+				},
+				Children: []Object{
+					{
+						SObject:      "Contact",
+						Relationship: "Contacts",
+						Count:        2,
+						Fields: map[string]Generator{
+							"LastName": Sequence("Contact %d"),
+							"Rating": func(index int, rnd *rand.Rand) interface{} {
+								return rnd.Intn(100)
+							},
+						},
+					},
+					{
+						SObject:      "Opportunity",
+						Relationship: "Opportunities",
+						Count:        1,
+						Fields: map[string]Generator{
+							"Name":      Sequence("Opportunity %d"),
+							"StageName": Static("Prospecting"),
+						},
+					},
+				},
+			},
+			{
+				SObject: "Lead",
+				Count:   3,
+				Fields: map[string]Generator{
+					"LastName": Sequence("Lead %d"),
+					"Company":  Static("Acme"),
+				},
+			},
+		},
+	}
+}
+
+func testResources(t *testing.T, org *mockOrg) *Resources {
+	t.Helper()
+	resources, err := NewResources(&mockSessionFormatter{
+		url:    "",
+		client: mockHTTPClient(org.roundTrip),
+	})
+	if err != nil {
+		t.Fatalf("NewResources() error = %v", err)
+	}
+	return resources
+}
+
+func TestPlan_Execute(t *testing.T) {
+	org := &mockOrg{}
+	seeded, err := testPlan(42).Execute(context.Background(), testResources(t, org))
+	if err != nil {
+		t.Fatalf("Plan.Execute() error = %v", err)
+	}
+
+	wantRequests := []string{"POST /composite/tree/Account", "POST /composite/sobjects"}
+	if !reflect.DeepEqual(org.requests, wantRequests) {
+		t.Errorf("Plan.Execute() requests = %v, want %v", org.requests, wantRequests)
+	}
+	wantIDs := map[string][]string{
+		"Account":     {"id-ref1", "id-ref5"},
+		"Contact":     {"id-ref2", "id-ref3", "id-ref6", "id-ref7"},
+		"Opportunity": {"id-ref4", "id-ref8"},
+		"Lead":        {"flat1", "flat2", "flat3"},
+	}
+	if !reflect.DeepEqual(seeded.IDs, wantIDs) {
+		t.Errorf("Plan.Execute() IDs = %v, want %v", seeded.IDs, wantIDs)
+	}
+
+	accounts := org.bodies[0]["records"].([]interface{})
+	if len(accounts) != 2 {
+		t.Fatalf("Plan.Execute() tree records = %v", accounts)
+	}
+	account := accounts[1].(map[string]interface{})
+	if account["Name"] != "Account 02" || account["attributes"].(map[string]interface{})["type"] != "Account" {
+		t.Errorf("Plan.Execute() account = %v", account)
+	}
+	contacts := account["Contacts"].(map[string]interface{})["records"].([]interface{})
+	if len(contacts) != 2 || contacts[1].(map[string]interface{})["LastName"] != "Contact 4" {
+		t.Errorf("Plan.Execute() contacts = %v", contacts)
+	}
+	opportunities := account["Opportunities"].(map[string]interface{})["records"].([]interface{})
+	if len(opportunities) != 1 || opportunities[0].(map[string]interface{})["StageName"] != "Prospecting" {
+		t.Errorf("Plan.Execute() opportunities = %v", opportunities)
+	}
+	leads := org.bodies[1]["records"].([]interface{})
+	lead := leads[2].(map[string]interface{})
+	if org.bodies[1]["allOrNone"] != true || lead["LastName"] != "Lead 3" || lead["Company"] != "Acme" {
+		t.Errorf("Plan.Execute() leads = %v", org.bodies[1])
+	}
+
+	again := &mockOrg{}
+	if _, err := testPlan(42).Execute(context.Background(), testResources(t, again)); err != nil {
+		t.Fatalf("Plan.Execute() error = %v", err)
+	}
+	if !reflect.DeepEqual(org.bodies, again.bodies) {
+		t.Errorf("Plan.Execute() with the same seed = %v, want %v", again.bodies, org.bodies)
+	}
+}
+
+func TestPlan_Execute_invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		plan Plan
+	}{
+		{
+			name: "no sobject",
+			plan: Plan{Objects: []Object{{Count: 1}}},
+		},
+		{
+			name: "no count",
+			plan: Plan{Objects: []Object{{SObject: "Account"}}},
+		},
+		{
+			name: "no relationship",
+			plan: Plan{Objects: []Object{{SObject: "Account", Count: 1, Children: []Object{{SObject: "Contact", Count: 1}}}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			org := &mockOrg{}
+			if _, err := tt.plan.Execute(context.Background(), testResources(t, org)); err == nil {
+				t.Errorf("Plan.Execute() error = nil, want error")
+			}
+			if len(org.requests) != 0 {
+				t.Errorf("Plan.Execute() requests = %v, want none", org.requests)
+			}
+		})
+	}
+}
+
+func TestSeeded_Teardown(t *testing.T) {
+	org := &mockOrg{}
+	seeded, err := testPlan(42).Execute(context.Background(), testResources(t, org))
+	if err != nil {
+		t.Fatalf("Plan.Execute() error = %v", err)
+	}
+
+	if err := seeded.Teardown(context.Background()); err != nil {
+		t.Fatalf("Seeded.Teardown() error = %v", err)
+	}
+	want := [][]string{
+		{"id-ref8", "id-ref7", "id-ref6", "id-ref4", "id-ref3", "id-ref2"},
+		{"flat3", "flat2", "flat1", "id-ref5", "id-ref1"},
+	}
+	if !reflect.DeepEqual(org.deletes, want) {
+		t.Errorf("Seeded.Teardown() deletes = %v, want %v", org.deletes, want)
+	}
+	if len(seeded.IDs) != 0 {
+		t.Errorf("Seeded.Teardown() IDs = %v, want none", seeded.IDs)
+	}
+}
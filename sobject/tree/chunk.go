@@ -0,0 +1,124 @@
+package tree
+
+import (
+	"context"
+	"fmt"
+)
+
+// MaxRecords is the maximum number of records, including the nested children,
+// that the composite tree API accepts in a request.
+const MaxRecords = 200
+
+// TooManyRecordsError is returned when the records exceed the limit of a request.
+//
+// Count is the number of records, including the nested children.
+//
+// Limit is the maximum number of records.
+//
+// ReferenceID is the reference ID of the top level record when its own subtree
+// exceeds the limit and it can not be split.  It is empty otherwise.
+type TooManyRecordsError struct {
+	Count       int
+	Limit       int
+	ReferenceID string
+}
+
+func (e *TooManyRecordsError) Error() string {
+	if e.ReferenceID != "" {
+		return fmt.Sprintf("sobject tree: record %s has %d records with its children, which exceeds the limit of %d and can not be split", e.ReferenceID, e.Count, e.Limit)
+	}
+	return fmt.Sprintf("sobject tree: %d records exceeds the limit of %d", e.Count, e.Limit)
+}
+
+// Count returns the number of records, including the nested children.
+func Count(records []*Record) int {
+	count := 0
+	for _, record := range records {
+		if record == nil {
+			continue
+		}
+		count++
+		for _, children := range record.Records {
+			count += Count(children)
+		}
+	}
+	return count
+}
+
+type chunkInserter struct {
+	sobject string
+	records []*Record
+}
+
+func (c *chunkInserter) SObject() string {
+	return c.sobject
+}
+
+func (c *chunkInserter) Records() []*Record {
+	return c.records
+}
+
+// InsertChunked will call the composite tree API with chunks of at most chunkSize
+// records, including the nested children.  The top level records are split across
+// the chunks, keeping each record with all of its children in the same chunk.  If
+// chunkSize is zero or more than MaxRecords, MaxRecords is used.  The values of the
+// chunks are merged.  If a chunk fails, the merged value of the inserted chunks and
+// the failed chunk is returned with the error, since the earlier chunks have
+// already been inserted.
+func (r *Resource) InsertChunked(ctx context.Context, inserter Inserter, chunkSize int) (*Value, error) {
+	if err := validateInserter(inserter); err != nil {
+		return nil, err
+	}
+	chunks, err := chunk(inserter, chunkSize)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := &Value{}
+	for idx, chunk := range chunks {
+		value, err := r.callout(ctx, chunk)
+		if value != nil {
+			merged.HasErrors = merged.HasErrors || value.HasErrors
+			merged.Results = append(merged.Results, value.Results...)
+		}
+		if err != nil {
+			return merged, fmt.Errorf("sobject tree: chunk %d of %d: %w", idx+1, len(chunks), err)
+		}
+	}
+	return merged, nil
+}
+
+func chunk(inserter Inserter, chunkSize int) ([]*chunkInserter, error) {
+	if chunkSize <= 0 || chunkSize > MaxRecords {
+		chunkSize = MaxRecords
+	}
+
+	var chunks []*chunkInserter
+	current := &chunkInserter{
+		sobject: inserter.SObject(),
+	}
+	size := 0
+	for _, record := range inserter.Records() {
+		count := Count([]*Record{record})
+		if count > chunkSize {
+			return nil, &TooManyRecordsError{
+				Count:       count,
+				Limit:       chunkSize,
+				ReferenceID: record.Attributes.ReferenceID,
+			}
+		}
+		if size+count > chunkSize {
+			chunks = append(chunks, current)
+			current = &chunkInserter{
+				sobject: inserter.SObject(),
+			}
+			size = 0
+		}
+		current.records = append(current.records, record)
+		size += count
+	}
+	if len(current.records) > 0 {
+		chunks = append(chunks, current)
+	}
+	return chunks, nil
+}
@@ -0,0 +1,195 @@
+package tree
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testTreeRecord(referenceID string, children int) *Record {
+	record := &Record{
+		Attributes: Attributes{
+			Type:        "Account",
+			ReferenceID: referenceID,
+		},
+		Fields: map[string]interface{}{
+			"Name": referenceID,
+		},
+		Records: make(map[string][]*Record),
+	}
+	for idx := 0; idx < children; idx++ {
+		record.Records["Contacts"] = append(record.Records["Contacts"], &Record{
+			Attributes: Attributes{
+				Type:        "Contact",
+				ReferenceID: fmt.Sprintf("%s-%d", referenceID, idx),
+			},
+			Fields: map[string]interface{}{
+				"LastName": "Smith",
+			},
+		})
+	}
+	return record
+}
+
+func TestCount(t *testing.T) {
+	nested := testTreeRecord("ref1", 2)
+	nested.Records["Contacts"][0].Records = map[string][]*Record{
+		"Cases": {
+			testTreeRecord("ref1-0-case", 0),
+		},
+	}
+	tests := []struct {
+		name    string
+		records []*Record
+		want    int
+	}{
+		{
+			name: "no records",
+			want: 0,
+		},
+		{
+			name:    "children",
+			records: []*Record{testTreeRecord("ref1", 3), testTreeRecord("ref2", 0)},
+			want:    5,
+		},
+		{
+			name:    "grandchildren",
+			records: []*Record{nested},
+			want:    4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Count(tt.records); got != tt.want {
+				t.Errorf("Count() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResource_InsertTooManyRecords(t *testing.T) {
+	r := &Resource{
+		session: &mockSessionFormatter{
+			url: "https://test.salesforce.com",
+			client: mockHTTPClient(func(req *http.Request) *http.Response {
+				t.Errorf("request was sent for too many records")
+				return nil
+			}),
+		},
+	}
+	_, err := r.Insert(&mockInserter{
+		sobject: "Account",
+		records: []*Record{testTreeRecord("ref1", 150), testTreeRecord("ref2", 49)},
+	})
+	var tooMany *TooManyRecordsError
+	if errors.As(err, &tooMany) == false {
+		t.Fatalf("Resource.Insert() error = %v, want TooManyRecordsError", err)
+	}
+	if tooMany.Count != 201 || tooMany.Limit != MaxRecords {
+		t.Errorf("Resource.Insert() error = %+v", tooMany)
+	}
+}
+
+func TestResource_InsertChunked(t *testing.T) {
+	tests := []struct {
+		name       string
+		records    []*Record
+		chunkSize  int
+		wantChunks [][]string
+		wantErr    bool
+	}{
+		{
+			name:       "single chunk",
+			records:    []*Record{testTreeRecord("ref1", 1), testTreeRecord("ref2", 1)},
+			chunkSize:  0,
+			wantChunks: [][]string{{"ref1", "ref2"}},
+		},
+		{
+			name:       "parents with children",
+			records:    []*Record{testTreeRecord("ref1", 2), testTreeRecord("ref2", 1), testTreeRecord("ref3", 3), testTreeRecord("ref4", 0)},
+			chunkSize:  5,
+			wantChunks: [][]string{{"ref1", "ref2"}, {"ref3", "ref4"}},
+		},
+		{
+			name:       "max records",
+			records:    []*Record{testTreeRecord("ref1", 150), testTreeRecord("ref2", 49), testTreeRecord("ref3", 0)},
+			chunkSize:  500,
+			wantChunks: [][]string{{"ref1"}, {"ref2", "ref3"}},
+		},
+		{
+			name:      "unsplittable subtree",
+			records:   []*Record{testTreeRecord("ref1", 1), testTreeRecord("ref2", 5)},
+			chunkSize: 5,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotChunks [][]string
+			r := &Resource{
+				session: &mockSessionFormatter{
+					url: "https://test.salesforce.com",
+					client: mockHTTPClient(func(req *http.Request) *http.Response {
+						var body struct {
+							Records []map[string]interface{} `json:"records"`
+						}
+						if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+							t.Errorf("request body error = %v", err)
+						}
+						var refs []string
+						var results []string
+						for _, record := range body.Records {
+							ref := record["attributes"].(map[string]interface{})["referenceId"].(string)
+							refs = append(refs, ref)
+							results = append(results, `{"referenceId": "`+ref+`", "id": "`+ref+`-id"}`)
+						}
+						gotChunks = append(gotChunks, refs)
+						resp := `{"hasErrors": false, "results": [` + strings.Join(results, ",") + `]}`
+						return &http.Response{
+							StatusCode: http.StatusCreated,
+							Status:     "Created",
+							Body:       io.NopCloser(strings.NewReader(resp)),
+							Header:     make(http.Header),
+						}
+					}),
+				},
+			}
+			got, err := r.InsertChunked(context.Background(), &mockInserter{
+				sobject: "Account",
+				records: tt.records,
+			}, tt.chunkSize)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resource.InsertChunked() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				var tooMany *TooManyRecordsError
+				if errors.As(err, &tooMany) == false || tooMany.ReferenceID != "ref2" || tooMany.Count != 6 {
+					t.Errorf("Resource.InsertChunked() error = %v, want TooManyRecordsError for ref2", err)
+				}
+				if gotChunks != nil {
+					t.Errorf("Resource.InsertChunked() sent %v before the error", gotChunks)
+				}
+				return
+			}
+			if !reflect.DeepEqual(gotChunks, tt.wantChunks) {
+				t.Errorf("Resource.InsertChunked() chunks = %v, want %v", gotChunks, tt.wantChunks)
+			}
+			var gotRefs, wantRefs []string
+			for _, result := range got.Results {
+				gotRefs = append(gotRefs, result.ReferenceID)
+			}
+			for _, chunk := range tt.wantChunks {
+				wantRefs = append(wantRefs, chunk...)
+			}
+			if !reflect.DeepEqual(gotRefs, wantRefs) {
+				t.Errorf("Resource.InsertChunked() results = %v, want %v", gotRefs, wantRefs)
+			}
+		})
+	}
+}
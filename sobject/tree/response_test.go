@@ -0,0 +1,128 @@
+package tree
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/namely/go-sfdc/v3"
+)
+
+func TestResource_Insert_responses(t *testing.T) {
+	inserter := &mockInserter{
+		sobject: "Account",
+		records: []*Record{
+			{
+				Attributes: Attributes{Type: "Account", ReferenceID: "ref1"},
+				Fields:     map[string]interface{}{"name": "SampleAccount1"},
+			},
+			{
+				Attributes: Attributes{Type: "Account", ReferenceID: "ref2"},
+				Fields:     map[string]interface{}{"name": "SampleAccount2"},
+			},
+		},
+	}
+	tests := []struct {
+		name        string
+		statusCode  int
+		body        string
+		want        *Value
+		wantRefs    []string
+		wantErr     string
+		wantErrType bool
+	}{
+		{
+			name:       "success",
+			statusCode: http.StatusCreated,
+			body: `{
+				"hasErrors": false,
+				"results": [
+					{"referenceId": "ref1", "id": "001D000000K0fXOIAZ"},
+					{"referenceId": "ref2", "id": "001D000000K0fXPIAZ"}
+				]
+			}`,
+			want: &Value{
+				Results: []InsertValue{
+					{ReferenceID: "ref1", ID: "001D000000K0fXOIAZ"},
+					{ReferenceID: "ref2", ID: "001D000000K0fXPIAZ"},
+				},
+			},
+		},
+		{
+			name:       "bad request with results",
+			statusCode: http.StatusBadRequest,
+			body: `{
+				"hasErrors": true,
+				"results": [
+					{
+						"referenceId": "ref2",
+						"errors": [
+							{"statusCode": "INVALID_EMAIL_ADDRESS", "message": "Email: invalid email address: 123", "fields": ["Email"]}
+						]
+					}
+				]
+			}`,
+			want: &Value{
+				HasErrors: true,
+				Results: []InsertValue{
+					{
+						ReferenceID: "ref2",
+						Errors: []sfdc.Error{
+							{ErrorCode: "INVALID_EMAIL_ADDRESS", Message: "Email: invalid email address: 123", Fields: []string{"Email"}},
+						},
+					},
+				},
+			},
+			wantRefs:    []string{"ref2"},
+			wantErr:     "sobject tree: 400 Bad Request: 1 records failed: ref2 INVALID_EMAIL_ADDRESS",
+			wantErrType: true,
+		},
+		{
+			name:       "bad request with error array",
+			statusCode: http.StatusBadRequest,
+			body:       `[{"errorCode": "JSON_PARSER_ERROR", "message": "Unexpected character"}]`,
+			wantErr:    "400 Bad Request: JSON_PARSER_ERROR: Unexpected character ()",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Resource{
+				session: &mockSessionFormatter{
+					url: "https://test.salesforce.com",
+					client: mockHTTPClient(func(req *http.Request) *http.Response {
+						return &http.Response{
+							StatusCode: tt.statusCode,
+							Status:     fmt.Sprintf("%d %s", tt.statusCode, http.StatusText(tt.statusCode)),
+							Body:       io.NopCloser(strings.NewReader(tt.body)),
+							Header:     make(http.Header),
+						}
+					}),
+				},
+			}
+			got, err := r.Insert(inserter)
+			if reflect.DeepEqual(got, tt.want) == false {
+				t.Errorf("Resource.Insert() = %+v, want %+v", got, tt.want)
+			}
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Resource.Insert() error = %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Resource.Insert() error = %v, want %v", err, tt.wantErr)
+			}
+			var insertErr *TreeInsertError
+			if errors.As(err, &insertErr) != tt.wantErrType {
+				t.Fatalf("Resource.Insert() error type = %T", err)
+			}
+			if tt.wantErrType && reflect.DeepEqual(insertErr.FailedReferences(), tt.wantRefs) == false {
+				t.Errorf("TreeInsertError.FailedReferences() = %v, want %v", insertErr.FailedReferences(), tt.wantRefs)
+			}
+		})
+	}
+}
@@ -2,10 +2,13 @@ package tree
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"regexp"
+	"strings"
 
 	"github.com/namely/go-sfdc/v3"
 	"github.com/namely/go-sfdc/v3/session"
@@ -40,40 +43,55 @@ type Resource struct {
 const objectEndpoint = "/composite/tree/"
 
 // NewResource creates a new composite tree resource from the session.
-func NewResource(session session.ServiceFormatter) (*Resource, error) {
-	if session == nil {
+func NewResource(formatter session.ServiceFormatter) (*Resource, error) {
+	if formatter == nil {
 		return nil, errors.New("sobject tree: session can not be nil")
 	}
 
-	err := session.Refresh()
+	err := session.RefreshResource(formatter)
 	if err != nil {
 		return nil, errors.Wrap(err, "session refresh")
 	}
 
 	return &Resource{
-		session: session,
+		session: formatter,
 	}, nil
 }
 
-// Insert will call the composite tree API.
+// Insert will call the composite tree API.  If the inserter has more than
+// MaxRecords records, including the nested children, a TooManyRecordsError
+// is returned.  If the API rejects the records, the value with the errors of
+// each record is returned with a TreeInsertError.
 func (r *Resource) Insert(inserter Inserter) (*Value, error) {
+	if err := validateInserter(inserter); err != nil {
+		return nil, err
+	}
+	if count := Count(inserter.Records()); count > MaxRecords {
+		return nil, &TooManyRecordsError{
+			Count: count,
+			Limit: MaxRecords,
+		}
+	}
+
+	return r.callout(context.Background(), inserter)
+}
+func validateInserter(inserter Inserter) error {
 	if inserter == nil {
-		return nil, errors.New("tree resourse: inserter can not be nil")
+		return errors.New("tree resourse: inserter can not be nil")
 	}
 	sobject := inserter.SObject()
 	matching, err := regexp.MatchString(`\w`, sobject)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	if matching == false {
-		return nil, fmt.Errorf("tree resourse: %s is not a valid sobject", sobject)
+		return fmt.Errorf("tree resourse: %s is not a valid sobject", sobject)
 	}
-
-	return r.callout(inserter)
+	return nil
 }
-func (r *Resource) callout(inserter Inserter) (*Value, error) {
+func (r *Resource) callout(ctx context.Context, inserter Inserter) (*Value, error) {
 
-	request, err := r.request(inserter)
+	request, err := r.request(ctx, inserter)
 
 	if err != nil {
 		return nil, err
@@ -81,22 +99,26 @@ func (r *Resource) callout(inserter Inserter) (*Value, error) {
 
 	value, err := r.response(request)
 
+	var insertErr *TreeInsertError
+	if errors.As(err, &insertErr) {
+		return &value, err
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	return &value, nil
 }
-func (r *Resource) request(inserter Inserter) (*http.Request, error) {
+func (r *Resource) request(ctx context.Context, inserter Inserter) (*http.Request, error) {
 
-	url := r.session.ServiceURL() + objectEndpoint + inserter.SObject()
+	url := r.session.ServiceURL() + objectEndpoint + sfdc.EscapePath(inserter.SObject())
 
 	body, err := r.payload(inserter)
 	if err != nil {
 		return nil, err
 	}
 
-	request, err := http.NewRequest(http.MethodPost, url, body)
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
 
 	if err != nil {
 		return nil, err
@@ -127,18 +149,74 @@ func (r *Resource) response(request *http.Request) (Value, error) {
 		return Value{}, err
 	}
 
-	decoder := json.NewDecoder(response.Body)
-	defer response.Body.Close()
-
-	var value Value
-	err = decoder.Decode(&value)
-	if err != nil {
-		return Value{}, err
+	defer sfdc.CloseResponse(response)
+
+	switch response.StatusCode {
+	case http.StatusCreated:
+		var value Value
+		err = json.NewDecoder(response.Body).Decode(&value)
+		if err != nil {
+			return Value{}, err
+		}
+		return value, nil
+
+	case http.StatusBadRequest:
+		body, err := io.ReadAll(response.Body)
+		if err != nil {
+			return Value{}, errors.Wrap(err, response.Status)
+		}
+		var value Value
+		if err := json.Unmarshal(body, &value); err == nil && value.HasErrors {
+			return value, &TreeInsertError{
+				StatusCode: response.StatusCode,
+				Status:     response.Status,
+				Value:      value,
+			}
+		}
+		response.Body = io.NopCloser(bytes.NewReader(body))
+		return Value{}, sfdc.HandleError(response)
+
+	default:
+		return Value{}, sfdc.HandleError(response)
 	}
+}
 
-	if response.StatusCode != http.StatusCreated {
-		return value, fmt.Errorf("insert response err: %d %s", response.StatusCode, response.Status)
+// TreeInsertError is returned when the composite tree API rejects the request
+// with the results of each record, so the records that failed can be found by
+// their reference IDs.  None of the records are inserted.
+//
+// StatusCode and Status are the HTTP status of the response.
+//
+// Value is the decoded response with the errors of each record.
+type TreeInsertError struct {
+	StatusCode int
+	Status     string
+	Value      Value
+}
+
+func (e *TreeInsertError) Error() string {
+	failed := e.FailedReferences()
+	parts := make([]string, 0, len(failed))
+	for _, result := range e.Value.Results {
+		if len(result.Errors) == 0 {
+			continue
+		}
+		codes := make([]string, 0, len(result.Errors))
+		for _, err := range result.Errors {
+			codes = append(codes, err.ErrorCode)
+		}
+		parts = append(parts, result.ReferenceID+" "+strings.Join(codes, ", "))
 	}
+	return fmt.Sprintf("sobject tree: %s: %d records failed: %s", e.Status, len(failed), strings.Join(parts, "; "))
+}
 
-	return value, nil
+// FailedReferences returns the reference IDs of the records with errors.
+func (e *TreeInsertError) FailedReferences() []string {
+	var failed []string
+	for _, result := range e.Value.Results {
+		if len(result.Errors) > 0 {
+			failed = append(failed, result.ReferenceID)
+		}
+	}
+	return failed
 }
@@ -0,0 +1,193 @@
+package soql
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/namely/go-sfdc/v3"
+	"github.com/namely/go-sfdc/v3/session"
+	"github.com/namely/go-sfdc/v3/sobject"
+	"github.com/pkg/errors"
+)
+
+// ColumnMetadata is the metadata of a query column, which is returned when the
+// query is sent with the columns parameter.  A relationship column has the
+// columns of the related object as its join columns.
+type ColumnMetadata struct {
+	Aggregate      bool             `json:"aggregate"`
+	ApexType       string           `json:"apexType"`
+	BooleanType    bool             `json:"booleanType"`
+	ColumnName     string           `json:"columnName"`
+	Custom         bool             `json:"custom"`
+	DisplayName    string           `json:"displayName"`
+	ForeignKeyName string           `json:"foreignKeyName"`
+	Insertable     bool             `json:"insertable"`
+	JoinColumns    []ColumnMetadata `json:"joinColumns"`
+	NumberType     bool             `json:"numberType"`
+	TextType       bool             `json:"textType"`
+	Updatable      bool             `json:"updatable"`
+}
+
+type columnsResponse struct {
+	ColumnMetadata []ColumnMetadata `json:"columnMetadata"`
+	EntityName     string           `json:"entityName"`
+}
+
+// FieldAccess is the combined column metadata and describe of a queried field.
+//
+// Name is the field's name.  The fields of a relationship are dotted, like
+// Account.Name.
+//
+// Field is the field's describe.  It is nil when the query was not given the
+// object's describe, the field is a relationship field, or the describe does
+// not have the field.
+//
+// Inaccessible is true when the field's values can not be trusted, so a null
+// may not be a real null.  A field is inaccessible when the describe is given
+// and it does not have the field, which is the case when the field level
+// security hides the field, or when the field is encrypted and the column can
+// neither be inserted nor updated by the user, so its values are masked.
+type FieldAccess struct {
+	Name         string
+	Column       ColumnMetadata
+	Field        *sobject.Field
+	Inaccessible bool
+}
+
+// QueryOption is an option for the query.
+type QueryOption func(*queryOptions)
+
+type queryOptions struct {
+	fieldAccess bool
+	describe    *sobject.DescribeValue
+	paths       DescribeSource
+	batchSize   int
+}
+
+// WithFieldAccess will query the column metadata before the query and annotate
+// the records with the access of the fields, so the records' Inaccessible can
+// be used to skip the fields instead of treating their values as nulls.  The
+// describe is the describe of the query's object and can be nil, in which case
+// the access is only the column metadata.
+func WithFieldAccess(describe *sobject.DescribeValue) QueryOption {
+	return func(o *queryOptions) {
+		o.fieldAccess = true
+		o.describe = describe
+	}
+}
+
+// WithPathValidation will validate the query's field paths with the describes
+// of the source before the query is sent, and fail with a PathError when a
+// path has an unknown segment.  The querier must be built by NewQuery.  The
+// source can be wrapped by CachedDescribes to describe each object once.
+func WithPathValidation(source DescribeSource) QueryOption {
+	return func(o *queryOptions) {
+		o.paths = source
+	}
+}
+
+// ColumnMetadata will return the column metadata of the query, without
+// querying the records.
+func (r *Resource) ColumnMetadata(ctx context.Context, querier QueryFormatter) (_ []ColumnMetadata, err error) {
+	if querier == nil {
+		return nil, errors.New("soql resource column metadata: querier can not be nil")
+	}
+	ctx, end := session.StartSpan(ctx, r.session, "soql.query.columns", nil)
+	defer func() { end(err) }()
+
+	query, err := querier.Format()
+	if err != nil {
+		return nil, err
+	}
+	serviceURL, err := session.ServiceURLContext(ctx, r.session)
+	if err != nil {
+		return nil, err
+	}
+	form := url.Values{}
+	form.Add("columns", "true")
+	form.Add("q", query)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, serviceURL+r.endpoint(false)+"/?"+form.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Add("Accept", "application/json")
+	r.session.AuthorizationHeader(request)
+
+	response, err := r.session.Client().Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer sfdc.CloseResponse(response)
+
+	if response.StatusCode != http.StatusOK {
+		return nil, sfdc.HandleError(response)
+	}
+
+	var columns columnsResponse
+	if err := json.NewDecoder(response.Body).Decode(&columns); err != nil {
+		return nil, err
+	}
+	return columns.ColumnMetadata, nil
+}
+
+// NewFieldAccess combines the column metadata and the describe of the query's
+// object into the access of the fields, keyed by the field names.  The
+// describe can be nil.
+func NewFieldAccess(columns []ColumnMetadata, describe *sobject.DescribeValue) map[string]FieldAccess {
+	var described map[string]*sobject.Field
+	if describe != nil {
+		described = make(map[string]*sobject.Field, len(describe.Fields))
+		for idx := range describe.Fields {
+			field := &describe.Fields[idx]
+			described[strings.ToLower(field.Name)] = field
+		}
+	}
+
+	access := make(map[string]FieldAccess)
+	var add func(prefix string, columns []ColumnMetadata)
+	add = func(prefix string, columns []ColumnMetadata) {
+		for _, column := range columns {
+			name := prefix + column.ColumnName
+			if len(column.JoinColumns) > 0 {
+				add(name+".", column.JoinColumns)
+				continue
+			}
+			fa := FieldAccess{
+				Name:   name,
+				Column: column,
+			}
+			if described != nil && prefix == "" && column.Aggregate == false {
+				field, has := described[strings.ToLower(name)]
+				switch {
+				case has == false:
+					fa.Inaccessible = true
+				case field.Encrypted && column.Insertable == false && column.Updatable == false:
+					fa.Field = field
+					fa.Inaccessible = true
+				default:
+					fa.Field = field
+				}
+			}
+			access[name] = fa
+		}
+	}
+	add("", columns)
+	return access
+}
+
+// fieldAccess returns the access of the field.  The field names are not case
+// sensitive.
+func fieldAccess(access map[string]FieldAccess, field string) (FieldAccess, bool) {
+	if fa, has := access[field]; has {
+		return fa, true
+	}
+	for name, fa := range access {
+		if strings.EqualFold(name, field) {
+			return fa, true
+		}
+	}
+	return FieldAccess{}, false
+}
@@ -0,0 +1,233 @@
+package soql
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/namely/go-sfdc/v3/sobject"
+)
+
+const accessColumnsResponse = `
+{
+	"columnMetadata": [
+		{
+			"aggregate": false,
+			"apexType": "Id",
+			"columnName": "Id",
+			"displayName": "Id",
+			"insertable": false,
+			"joinColumns": [],
+			"updatable": false
+		},
+		{
+			"aggregate": false,
+			"apexType": "String",
+			"columnName": "Name",
+			"displayName": "Name",
+			"insertable": true,
+			"joinColumns": [],
+			"textType": true,
+			"updatable": true
+		},
+		{
+			"aggregate": false,
+			"apexType": "String",
+			"columnName": "SSN__c",
+			"custom": true,
+			"displayName": "SSN__c",
+			"insertable": false,
+			"joinColumns": [],
+			"textType": true,
+			"updatable": false
+		},
+		{
+			"aggregate": false,
+			"apexType": "String",
+			"columnName": "Salary__c",
+			"custom": true,
+			"displayName": "Salary__c",
+			"insertable": false,
+			"joinColumns": [],
+			"textType": true,
+			"updatable": false
+		},
+		{
+			"aggregate": false,
+			"columnName": "Owner",
+			"displayName": "Owner",
+			"foreignKeyName": "OwnerId",
+			"insertable": false,
+			"joinColumns": [
+				{
+					"aggregate": false,
+					"apexType": "String",
+					"columnName": "Name",
+					"displayName": "Owner.Name",
+					"insertable": false,
+					"joinColumns": [],
+					"textType": true,
+					"updatable": false
+				}
+			],
+			"updatable": false
+		}
+	],
+	"entityName": "Contact",
+	"groupBy": false,
+	"idSelected": true,
+	"keyPrefix": "003"
+}`
+
+const accessQueryResponse = `
+{
+	"done": true,
+	"totalSize": 2,
+	"records": [
+		{
+			"attributes": {
+				"type": "Contact",
+				"url": "/services/data/v42.0/sobjects/Contact/003000000000001AAA"
+			},
+			"Id": "003000000000001AAA",
+			"Name": "Jane Doe",
+			"SSN__c": "***-**-1234",
+			"Salary__c": null,
+			"Owner": {
+				"attributes": {
+					"type": "User",
+					"url": "/services/data/v42.0/sobjects/User/005000000000001AAA"
+				},
+				"Name": "Admin"
+			}
+		},
+		{
+			"attributes": {
+				"type": "Contact",
+				"url": "/services/data/v42.0/sobjects/Contact/003000000000002AAA"
+			},
+			"Id": "003000000000002AAA",
+			"Name": null,
+			"SSN__c": null,
+			"Salary__c": null,
+			"Owner": null
+		}
+	]
+}`
+
+func TestResource_QueryContext_FieldAccess(t *testing.T) {
+	describe := &sobject.DescribeValue{
+		Name: "Contact",
+		Fields: []sobject.Field{
+			{Name: "Id"},
+			{Name: "Name", Createable: true, Updateable: true},
+			{Name: "SSN__c", Encrypted: true, Permissionable: true, Createable: true, Updateable: true},
+		},
+	}
+	var columnsRequested bool
+	client := mockHTTPClient(func(req *http.Request) *http.Response {
+		body := accessQueryResponse
+		if req.URL.Query().Get("columns") == "true" {
+			columnsRequested = true
+			body = accessColumnsResponse
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Header:     make(http.Header),
+		}
+	})
+	resource := &Resource{
+		session: &mockSessionFormatter{
+			url:    "https://test.salesforce.com",
+			client: client,
+		},
+	}
+	querier := &mockQuerier{
+		stmt: "SELECT Id, Name, SSN__c, Salary__c, Owner.Name FROM Contact",
+	}
+
+	result, err := resource.QueryContext(context.Background(), querier, false, WithFieldAccess(describe))
+	if err != nil {
+		t.Fatalf("Resource.QueryContext() error = %v", err)
+	}
+	if columnsRequested == false {
+		t.Error("Resource.QueryContext() did not request the column metadata")
+	}
+
+	access := result.FieldAccess()
+	if len(access) != 5 {
+		t.Errorf("QueryResult.FieldAccess() = %v, want 5 fields", access)
+	}
+	if fa := access["Owner.Name"]; fa.Name != "Owner.Name" || fa.Column.DisplayName != "Owner.Name" || fa.Field != nil {
+		t.Errorf("QueryResult.FieldAccess() Owner.Name = %+v", fa)
+	}
+	if fa := access["SSN__c"]; fa.Field == nil || fa.Field.Encrypted == false {
+		t.Errorf("QueryResult.FieldAccess() SSN__c = %+v", fa)
+	}
+
+	want := map[string]bool{
+		"Id":         false,
+		"Name":       false,
+		"name":       false,
+		"SSN__c":     true,
+		"Salary__c":  true,
+		"Owner.Name": false,
+		"Unknown":    false,
+	}
+	for _, record := range result.Records() {
+		for field, inaccessible := range want {
+			if got := record.Inaccessible(field); got != inaccessible {
+				t.Errorf("QueryRecord(%s).Inaccessible(%s) = %v, want %v", record.Record().URL(), field, got, inaccessible)
+			}
+		}
+	}
+}
+
+func TestNewFieldAccess_WithoutDescribe(t *testing.T) {
+	columns := []ColumnMetadata{
+		{ColumnName: "Id"},
+		{ColumnName: "Name", Insertable: true, Updatable: true},
+		{ColumnName: "expr0", Aggregate: true},
+	}
+	access := NewFieldAccess(columns, nil)
+	if len(access) != 3 {
+		t.Fatalf("NewFieldAccess() = %v, want 3 fields", access)
+	}
+	for name, fa := range access {
+		if fa.Inaccessible || fa.Field != nil {
+			t.Errorf("NewFieldAccess() %s = %+v", name, fa)
+		}
+	}
+	if access["Name"].Column.Updatable == false {
+		t.Errorf("NewFieldAccess() Name = %+v", access["Name"])
+	}
+}
+
+func TestResource_Query_NoFieldAccess(t *testing.T) {
+	client := mockHTTPClient(func(req *http.Request) *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(accessQueryResponse)),
+			Header:     make(http.Header),
+		}
+	})
+	resource := &Resource{
+		session: &mockSessionFormatter{
+			url:    "https://test.salesforce.com",
+			client: client,
+		},
+	}
+	result, err := resource.Query(&mockQuerier{stmt: "SELECT Id FROM Contact"}, false)
+	if err != nil {
+		t.Fatalf("Resource.Query() error = %v", err)
+	}
+	if result.FieldAccess() != nil {
+		t.Errorf("QueryResult.FieldAccess() = %v, want nil", result.FieldAccess())
+	}
+	if result.Records()[0].Inaccessible("SSN__c") {
+		t.Error("QueryRecord.Inaccessible() = true, want false")
+	}
+}
@@ -0,0 +1,110 @@
+package soql
+
+import (
+	"strings"
+)
+
+const (
+	operatorAnd = "AND"
+	operatorOr  = "OR"
+	operatorNot = "NOT"
+)
+
+// And forms the logical AND of the expressions without changing them.  An
+// expression is grouped in parentheses when its top level operator is OR, since
+// SOQL does not allow AND and OR to be mixed without grouping.  The nil and
+// empty expressions are skipped, and if there are none, nil is returned.
+func And(clauses ...WhereExpression) *WhereClause {
+	return combine(operatorAnd, clauses)
+}
+
+// Or forms the logical OR of the expressions without changing them.  An
+// expression is grouped in parentheses when its top level operator is AND,
+// since SOQL does not allow AND and OR to be mixed without grouping.  The nil
+// and empty expressions are skipped, and if there are none, nil is returned.
+func Or(clauses ...WhereExpression) *WhereClause {
+	return combine(operatorOr, clauses)
+}
+
+// Not forms the logical NOT of the expression without changing it.  The
+// expression is grouped in parentheses when it has a top level operator.  If
+// the expression is nil or empty, nil is returned.
+func Not(clause WhereExpression) *WhereClause {
+	expression := expressionOf(clause)
+	if expression == "" {
+		return nil
+	}
+	if topLevelOperator(expression) != "" {
+		expression = "(" + expression + ")"
+	}
+	return &WhereClause{
+		expression: operatorNot + " " + expression,
+	}
+}
+
+func combine(operator string, clauses []WhereExpression) *WhereClause {
+	expressions := make([]string, 0, len(clauses))
+	for _, clause := range clauses {
+		expression := expressionOf(clause)
+		if expression == "" {
+			continue
+		}
+		switch topLevelOperator(expression) {
+		case "", operator, operatorNot:
+		default:
+			expression = "(" + expression + ")"
+		}
+		expressions = append(expressions, expression)
+	}
+	if len(expressions) == 0 {
+		return nil
+	}
+	return &WhereClause{
+		expression: strings.Join(expressions, " "+operator+" "),
+	}
+}
+
+// expressionOf returns the expression, or empty if the expression is nil.
+func expressionOf(clause WhereExpression) string {
+	if clause == nil {
+		return ""
+	}
+	if wc, is := clause.(*WhereClause); is && wc == nil {
+		return ""
+	}
+	return strings.TrimSpace(clause.Expression())
+}
+
+// topLevelOperator returns the lowest precedence logical operator of the
+// expression outside of parentheses and string literals: OR, AND, then NOT.  If
+// there is none, empty is returned.
+func topLevelOperator(expression string) string {
+	depth := 0
+	quoted := false
+	operator := ""
+	upper := strings.ToUpper(expression)
+	for i := 0; i < len(upper); i++ {
+		switch c := upper[i]; {
+		case quoted && c == '\\':
+			i++
+		case c == '\'':
+			quoted = quoted == false
+		case quoted:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case depth == 0 && c == ' ':
+			switch {
+			case strings.HasPrefix(upper[i:], " OR "):
+				return operatorOr
+			case strings.HasPrefix(upper[i:], " AND "):
+				operator = operatorAnd
+			}
+		}
+	}
+	if operator == "" && strings.HasPrefix(upper, operatorNot+" ") {
+		return operatorNot
+	}
+	return operator
+}
@@ -0,0 +1,123 @@
+package soql
+
+import (
+	"testing"
+)
+
+func TestAnd_Or_Not(t *testing.T) {
+	a := &WhereClause{expression: "Name = 'Acme'"}
+	b := &WhereClause{expression: "NumberOfEmployees > 10"}
+	c := &WhereClause{expression: "Industry LIKE 'Tech%'"}
+	d := &WhereClause{expression: "Type != 'Partner'"}
+
+	tests := []struct {
+		name  string
+		where *WhereClause
+		want  string
+	}{
+		{
+			name:  "single",
+			where: And(a),
+			want:  "Name = 'Acme'",
+		},
+		{
+			name:  "and",
+			where: And(a, b, c),
+			want:  "Name = 'Acme' AND NumberOfEmployees > 10 AND Industry LIKE 'Tech%'",
+		},
+		{
+			name:  "or",
+			where: Or(a, b, c),
+			want:  "Name = 'Acme' OR NumberOfEmployees > 10 OR Industry LIKE 'Tech%'",
+		},
+		{
+			name:  "and of ors",
+			where: And(Or(a, b), Or(c, d)),
+			want:  "(Name = 'Acme' OR NumberOfEmployees > 10) AND (Industry LIKE 'Tech%' OR Type != 'Partner')",
+		},
+		{
+			name:  "or of ands",
+			where: Or(And(a, b), And(c, d)),
+			want:  "(Name = 'Acme' AND NumberOfEmployees > 10) OR (Industry LIKE 'Tech%' AND Type != 'Partner')",
+		},
+		{
+			name:  "nested and is flattened",
+			where: And(a, And(b, c)),
+			want:  "Name = 'Acme' AND NumberOfEmployees > 10 AND Industry LIKE 'Tech%'",
+		},
+		{
+			name:  "deep",
+			where: Or(a, And(b, Or(c, Not(d)))),
+			want:  "Name = 'Acme' OR (NumberOfEmployees > 10 AND (Industry LIKE 'Tech%' OR NOT Type != 'Partner'))",
+		},
+		{
+			name:  "not",
+			where: Not(a),
+			want:  "NOT Name = 'Acme'",
+		},
+		{
+			name:  "not of and",
+			where: Not(And(a, b)),
+			want:  "NOT (Name = 'Acme' AND NumberOfEmployees > 10)",
+		},
+		{
+			name:  "not of not",
+			where: Not(Not(a)),
+			want:  "NOT (NOT Name = 'Acme')",
+		},
+		{
+			name:  "and of nots",
+			where: And(Not(a), Not(Or(b, c))),
+			want:  "NOT Name = 'Acme' AND NOT (NumberOfEmployees > 10 OR Industry LIKE 'Tech%')",
+		},
+		{
+			name:  "operators in literals",
+			where: And(&WhereClause{expression: "Name = 'Smith OR Jones'"}, &WhereClause{expression: "Name LIKE 'O\\'Brien AND (Sons'"}, a),
+			want:  "Name = 'Smith OR Jones' AND Name LIKE 'O\\'Brien AND (Sons' AND Name = 'Acme'",
+		},
+		{
+			name:  "grouped operand",
+			where: And(&WhereClause{expression: "(Name = null OR Name != null)"}, a),
+			want:  "(Name = null OR Name != null) AND Name = 'Acme'",
+		},
+		{
+			name:  "nil and empty skipped",
+			where: And(nil, a, (*WhereClause)(nil), &WhereClause{}, b),
+			want:  "Name = 'Acme' AND NumberOfEmployees > 10",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.where.Expression(); got != tt.want {
+				t.Errorf("Expression() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if a.Expression() != "Name = 'Acme'" || b.Expression() != "NumberOfEmployees > 10" {
+		t.Errorf("operands were changed: %v, %v", a.Expression(), b.Expression())
+	}
+}
+
+func TestAnd_empty(t *testing.T) {
+	if got := And(); got != nil {
+		t.Errorf("And() = %v, want nil", got)
+	}
+	if got := Or(nil); got != nil {
+		t.Errorf("Or() = %v, want nil", got)
+	}
+	if got := Not(nil); got != nil {
+		t.Errorf("Not() = %v, want nil", got)
+	}
+}
+
+func TestAnd_Clause(t *testing.T) {
+	a := &WhereClause{expression: "Name = 'Acme'"}
+	b := &WhereClause{expression: "Type = 'Partner'"}
+	c := &WhereClause{expression: "Type = 'Customer'"}
+
+	want := "WHERE Name = 'Acme' AND (Type = 'Partner' OR Type = 'Customer')"
+	if got := And(a, Or(b, c)).Clause(); got != want {
+		t.Errorf("Clause() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,159 @@
+package soql
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/namely/go-sfdc/v3"
+	"github.com/namely/go-sfdc/v3/bulk"
+)
+
+// ChildRecordsError is returned when a record that is written as CSV has the
+// records of a child relationship, which can not be a CSV value.
+//
+// Index is the position of the record.
+//
+// Field is the child relationship, with the dotted path of its parents.
+type ChildRecordsError struct {
+	Index int
+	Field string
+}
+
+func (e *ChildRecordsError) Error() string {
+	return fmt.Sprintf("soql csv: record %d has the child records of %s, which can not be written as CSV", e.Index, e.Field)
+}
+
+// ToCSV will write the records as CSV data for a bulk ingest job, with the
+// header of the fields and the delimiter.  The parent records are flattened to
+// dotted fields, like Owner.Name, and their attributes are dropped.  A field
+// that is not in a record, or is null, is an empty value.  The values are
+// quoted like the bulk formatter's.  A record with the results of a child
+// query is a ChildRecordsError, since the child records can not be a CSV
+// value.  The query records do not have the compound fields, like
+// BillingAddress, which are written by MapsToCSV.
+func ToCSV(records []*QueryRecord, fields []string, delimiter bulk.ColumnDelimiter, w io.Writer) error {
+	flattened := make([]map[string]interface{}, len(records))
+	for idx, record := range records {
+		if record == nil {
+			return fmt.Errorf("soql csv: record %d is nil", idx)
+		}
+		if len(record.subresults) > 0 {
+			return &ChildRecordsError{Index: idx, Field: firstKey(record.subresults)}
+		}
+		values := make(map[string]interface{})
+		flattenRecord(values, "", record.record)
+		flattened[idx] = values
+	}
+	return writeCSV(flattened, fields, delimiter, w)
+}
+
+// MapsToCSV will write the records, as decoded from the JSON of a query
+// response, as CSV data for a bulk ingest job like ToCSV.  The nested maps,
+// like the parent records and compound fields, are flattened to dotted
+// fields.  A record with a child query's results or an array is a
+// ChildRecordsError.
+func MapsToCSV(records []map[string]interface{}, fields []string, delimiter bulk.ColumnDelimiter, w io.Writer) error {
+	flattened := make([]map[string]interface{}, len(records))
+	for idx, record := range records {
+		values := make(map[string]interface{})
+		if child := flattenMap(values, "", record); child != "" {
+			return &ChildRecordsError{Index: idx, Field: child}
+		}
+		flattened[idx] = values
+	}
+	return writeCSV(flattened, fields, delimiter, w)
+}
+
+func flattenRecord(values map[string]interface{}, prefix string, record *sfdc.Record) {
+	if record == nil {
+		return
+	}
+	for field, value := range record.Fields() {
+		values[prefix+field] = value
+	}
+	for _, field := range record.LookUpFields() {
+		lookUp, _ := record.LookUp(field)
+		flattenRecord(values, prefix+field+".", lookUp)
+	}
+}
+
+// flattenMap returns the dotted field of the first child query's results or
+// array, or an empty string when the record has none.
+func flattenMap(values map[string]interface{}, prefix string, record map[string]interface{}) string {
+	fields := make([]string, 0, len(record))
+	for field := range record {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		if field == sfdc.RecordAttributes {
+			continue
+		}
+		switch value := record[field].(type) {
+		case map[string]interface{}:
+			if isSubQuery(value) {
+				return prefix + field
+			}
+			if child := flattenMap(values, prefix+field+".", value); child != "" {
+				return child
+			}
+		case []interface{}:
+			return prefix + field
+		default:
+			values[prefix+field] = value
+		}
+	}
+	return ""
+}
+
+func writeCSV(records []map[string]interface{}, fields []string, delimiter bulk.ColumnDelimiter, w io.Writer) error {
+	if len(fields) == 0 {
+		return errors.New("soql csv: fields are required")
+	}
+	if w == nil {
+		return errors.New("soql csv: writer can not be nil")
+	}
+	writer := csv.NewWriter(w)
+	writer.Comma = delimiter.Rune()
+	if err := writer.Write(fields); err != nil {
+		return err
+	}
+	row := make([]string, len(fields))
+	for _, record := range records {
+		for idx, field := range fields {
+			row[idx] = csvValue(record[field])
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func csvValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		// JSON numbers are written without an exponent
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func firstKey(subresults map[string]*QueryResult) string {
+	keys := make([]string, 0, len(subresults))
+	for key := range subresults {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys[0]
+}
@@ -0,0 +1,155 @@
+package soql
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/namely/go-sfdc/v3/bulk"
+)
+
+const csvRecords = `[
+	{
+		"attributes": {"type": "Account", "url": "/services/data/v42.0/sobjects/Account/001A"},
+		"Id": "001A",
+		"Name": "Acme, \"The\" Company",
+		"AnnualRevenue": 1250000.0,
+		"Active__c": true,
+		"Owner": {
+			"attributes": {"type": "User", "url": "/services/data/v42.0/sobjects/User/005A"},
+			"Name": "Ada",
+			"Manager": {
+				"attributes": {"type": "User", "url": "/services/data/v42.0/sobjects/User/005B"},
+				"Name": "Grace"
+			}
+		}
+	},
+	{
+		"attributes": {"type": "Account", "url": "/services/data/v42.0/sobjects/Account/001B"},
+		"Id": "001B",
+		"Name": "Globex\nEast",
+		"AnnualRevenue": null,
+		"Owner": null
+	}
+]`
+
+func csvMaps(t *testing.T, data string) []map[string]interface{} {
+	t.Helper()
+	var records []map[string]interface{}
+	if err := json.Unmarshal([]byte(data), &records); err != nil {
+		t.Fatal(err)
+	}
+	return records
+}
+
+func csvQueryRecords(t *testing.T, data string) []*QueryRecord {
+	t.Helper()
+	maps := csvMaps(t, data)
+	records := make([]*QueryRecord, len(maps))
+	for idx, m := range maps {
+		record, err := newQueryRecord(m, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		records[idx] = record
+	}
+	return records
+}
+
+func TestToCSV(t *testing.T) {
+	fields := []string{"Id", "Name", "AnnualRevenue", "Active__c", "Owner.Name", "Owner.Manager.Name", "Missing__c"}
+	tests := []struct {
+		name      string
+		delimiter bulk.ColumnDelimiter
+		want      string
+	}{
+		{
+			name:      "comma",
+			delimiter: bulk.Comma,
+			want: "Id,Name,AnnualRevenue,Active__c,Owner.Name,Owner.Manager.Name,Missing__c\n" +
+				"001A,\"Acme, \"\"The\"\" Company\",1250000,true,Ada,Grace,\n" +
+				"001B,\"Globex\nEast\",,,,,\n",
+		},
+		{
+			name:      "pipe",
+			delimiter: bulk.Pipe,
+			want: "Id|Name|AnnualRevenue|Active__c|Owner.Name|Owner.Manager.Name|Missing__c\n" +
+				"001A|\"Acme, \"\"The\"\" Company\"|1250000|true|Ada|Grace|\n" +
+				"001B|\"Globex\nEast\"|||||\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			if err := ToCSV(csvQueryRecords(t, csvRecords), fields, tt.delimiter, &sb); err != nil {
+				t.Fatalf("ToCSV() error = %v", err)
+			}
+			if sb.String() != tt.want {
+				t.Errorf("ToCSV() = %q, want %q", sb.String(), tt.want)
+			}
+
+			sb.Reset()
+			if err := MapsToCSV(csvMaps(t, csvRecords), fields, tt.delimiter, &sb); err != nil {
+				t.Fatalf("MapsToCSV() error = %v", err)
+			}
+			if sb.String() != tt.want {
+				t.Errorf("MapsToCSV() = %q, want %q", sb.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestMapsToCSV_CompoundFields(t *testing.T) {
+	records := csvMaps(t, `[{"attributes": {"type": "Account"}, "BillingAddress": {"city": "Springfield", "postalCode": "12345"}}]`)
+	var sb strings.Builder
+	if err := MapsToCSV(records, []string{"BillingAddress.city", "BillingAddress.postalCode"}, bulk.Comma, &sb); err != nil {
+		t.Fatalf("MapsToCSV() error = %v", err)
+	}
+	if want := "BillingAddress.city,BillingAddress.postalCode\nSpringfield,12345\n"; sb.String() != want {
+		t.Errorf("MapsToCSV() = %q, want %q", sb.String(), want)
+	}
+}
+
+func TestToCSV_ChildRecords(t *testing.T) {
+	data := `[
+		{"attributes": {"type": "Account"}, "Name": "Acme"},
+		{
+			"attributes": {"type": "Account"},
+			"Name": "Globex",
+			"Contacts": {"totalSize": 1, "done": true, "records": [{"attributes": {"type": "Contact"}, "LastName": "Doe"}]}
+		}
+	]`
+	var sb strings.Builder
+	err := ToCSV(csvQueryRecords(t, data), []string{"Name"}, bulk.Comma, &sb)
+	var childErr *ChildRecordsError
+	if errors.As(err, &childErr) == false || childErr.Index != 1 || childErr.Field != "Contacts" {
+		t.Errorf("ToCSV() error = %v, want a ChildRecordsError", err)
+	}
+
+	err = MapsToCSV(csvMaps(t, data), []string{"Name"}, bulk.Comma, &sb)
+	if errors.As(err, &childErr) == false || childErr.Index != 1 || childErr.Field != "Contacts" {
+		t.Errorf("MapsToCSV() error = %v, want a ChildRecordsError", err)
+	}
+
+	err = MapsToCSV(csvMaps(t, `[{"Owner": {"Tags": ["a", "b"]}}]`), []string{"Owner.Tags"}, bulk.Comma, &sb)
+	if errors.As(err, &childErr) == false || childErr.Field != "Owner.Tags" {
+		t.Errorf("MapsToCSV() error = %v, want a ChildRecordsError", err)
+	}
+	if err.Error() != "soql csv: record 0 has the child records of Owner.Tags, which can not be written as CSV" {
+		t.Errorf("ChildRecordsError.Error() = %s", err.Error())
+	}
+}
+
+func TestToCSV_Errors(t *testing.T) {
+	var sb strings.Builder
+	if err := ToCSV(nil, nil, bulk.Comma, &sb); err == nil {
+		t.Error("ToCSV() expected an error without fields")
+	}
+	if err := ToCSV(nil, []string{"Id"}, bulk.Comma, nil); err == nil {
+		t.Error("ToCSV() expected an error without a writer")
+	}
+	if err := ToCSV([]*QueryRecord{nil}, []string{"Id"}, bulk.Comma, &sb); err == nil {
+		t.Error("ToCSV() expected an error for a nil record")
+	}
+}
@@ -0,0 +1,204 @@
+package soql
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	identifierPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+	fieldPattern      = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*(\.[A-Za-z][A-Za-z0-9_]*)*(\s+([A-Za-z][A-Za-z0-9_]*))?$`)
+	pathPattern       = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*(\.[A-Za-z][A-Za-z0-9_]*)*$`)
+)
+
+// Field is a typed entry of the query's field list.
+//
+// Name is the field or the relationship path to a parent's field, like
+// Account.Owner.Name.  It can be empty only for COUNT().
+//
+// Function is the function applied to the field, like COUNT or
+// CALENDAR_YEAR.  This field is optional.
+//
+// Alias is the alias of the field or function, which is how the aggregate
+// results are keyed.  This field is optional.
+//
+// The fields formed by UnsafeField have a raw expression instead, which only
+// this package can set, so a field list entry can not pass as trusted.
+type Field struct {
+	Name     string
+	Function string
+	Alias    string
+	raw      string
+}
+
+var reservedWords = map[string]struct{}{
+	"AND":      {},
+	"ASC":      {},
+	"BY":       {},
+	"DESC":     {},
+	"EXCLUDES": {},
+	"FIRST":    {},
+	"FROM":     {},
+	"GROUP":    {},
+	"HAVING":   {},
+	"IN":       {},
+	"INCLUDES": {},
+	"LAST":     {},
+	"LIKE":     {},
+	"LIMIT":    {},
+	"NOT":      {},
+	"NULL":     {},
+	"NULLS":    {},
+	"OFFSET":   {},
+	"OR":       {},
+	"ORDER":    {},
+	"SELECT":   {},
+	"USING":    {},
+	"WHERE":    {},
+	"WITH":     {},
+}
+
+// UnsafeField will return the raw field expression so that it is placed in the
+// SOQL query without any validation.  This is intended for advanced cases, like
+// toLabel(Status) or FORMAT(Amount), that the builder does not support.  It is
+// the caller's responsibility to make sure the expression is not formed from
+// untrusted input.
+func UnsafeField(raw string) Field {
+	return Field{raw: raw}
+}
+
+// Count forms the COUNT aggregate function for the field.  If the field is
+// empty, COUNT() is returned.
+func Count(field string) (Field, error) {
+	if field == "" {
+		return Field{Function: "COUNT"}, nil
+	}
+	return aggregate("COUNT", field)
+}
+
+// CountDistinct forms the COUNT_DISTINCT aggregate function for the field.
+func CountDistinct(field string) (Field, error) {
+	return aggregate("COUNT_DISTINCT", field)
+}
+
+// Sum forms the SUM aggregate function for the field.
+func Sum(field string) (Field, error) {
+	return aggregate("SUM", field)
+}
+
+// Avg forms the AVG aggregate function for the field.
+func Avg(field string) (Field, error) {
+	return aggregate("AVG", field)
+}
+
+// Min forms the MIN aggregate function for the field.
+func Min(field string) (Field, error) {
+	return aggregate("MIN", field)
+}
+
+// Max forms the MAX aggregate function for the field.
+func Max(field string) (Field, error) {
+	return aggregate("MAX", field)
+}
+
+// Alias will add the alias to the field or aggregate function.
+func Alias(field Field, alias string) (Field, error) {
+	if validAlias(alias) == false {
+		return Field{}, fmt.Errorf("soql alias: %q is not a valid alias", alias)
+	}
+	field.Alias = alias
+	if _, err := field.format(); err != nil {
+		return Field{}, err
+	}
+	return field, nil
+}
+
+func aggregate(function, field string) (Field, error) {
+	aggregated := Field{Name: field, Function: function}
+	if _, err := aggregated.format(); err != nil {
+		return Field{}, err
+	}
+	return aggregated, nil
+}
+
+// format returns the field as it is placed in the field list, like
+// "COUNT(Id) cnt".  The name, function and alias must be identifiers.
+func (f Field) format() (string, error) {
+	if f.raw != "" {
+		if f.Alias == "" {
+			return f.raw, nil
+		}
+		if validAlias(f.Alias) == false {
+			return "", fmt.Errorf("builder: field %q has an invalid alias %q", f.raw, f.Alias)
+		}
+		return f.raw + " " + f.Alias, nil
+	}
+	if f.Name == "" && strings.EqualFold(f.Function, "COUNT") == false {
+		return "", errors.New("builder: field name can not be empty")
+	}
+	if f.Name != "" && pathPattern.MatchString(f.Name) == false {
+		return "", fmt.Errorf("builder: field %q is not a valid identifier", f.Name)
+	}
+	formatted := f.Name
+	if f.Function != "" {
+		if identifierPattern.MatchString(f.Function) == false {
+			return "", fmt.Errorf("builder: function %q is not a valid identifier", f.Function)
+		}
+		formatted = fmt.Sprintf("%s(%s)", f.Function, f.Name)
+	}
+	if f.Alias != "" {
+		if validAlias(f.Alias) == false {
+			return "", fmt.Errorf("builder: field %q has an invalid alias %q", f.Name, f.Alias)
+		}
+		formatted += " " + f.Alias
+	}
+	return formatted, nil
+}
+
+func formatFields(fields []Field) ([]string, error) {
+	formatted := make([]string, len(fields))
+	for idx, field := range fields {
+		var err error
+		if formatted[idx], err = field.format(); err != nil {
+			return nil, err
+		}
+	}
+	return formatted, nil
+}
+
+func validateObjectType(objectType string) error {
+	if identifierPattern.MatchString(objectType) == false {
+		return fmt.Errorf("builder: object type %q is not a valid identifier", objectType)
+	}
+	return nil
+}
+
+func validateField(field string) error {
+	matches := fieldPattern.FindStringSubmatch(field)
+	if matches == nil {
+		return fmt.Errorf("builder: field %q is not a valid identifier", field)
+	}
+	if alias := matches[3]; alias != "" && validAlias(alias) == false {
+		return fmt.Errorf("builder: field %q has an invalid alias", field)
+	}
+	return nil
+}
+
+func validAlias(alias string) bool {
+	if identifierPattern.MatchString(alias) == false {
+		return false
+	}
+	_, reserved := reservedWords[strings.ToUpper(alias)]
+	return reserved == false
+}
+
+func validateFieldList(fields []string) error {
+	for _, field := range fields {
+		if err := validateField(field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,380 @@
+package soql
+
+import (
+	"testing"
+)
+
+func TestQuery_FormatIdentifiers(t *testing.T) {
+	count, err := Count("Id")
+	if err != nil {
+		t.Fatalf("Count() error = %v", err)
+	}
+	countAlias, err := Alias(count, "total")
+	if err != nil {
+		t.Fatalf("Alias() error = %v", err)
+	}
+	tests := []struct {
+		name       string
+		objectType string
+		fieldList  []string
+		fields     []Field
+		want       string
+		wantErr    bool
+	}{
+		{
+			name:       "dotted relationship path",
+			objectType: "Contact",
+			fieldList: []string{
+				"Name",
+				"Account.Owner.Name",
+				"Custom__r.Field__c",
+			},
+			want:    "SELECT Name,Account.Owner.Name,Custom__r.Field__c FROM Contact",
+			wantErr: false,
+		},
+		{
+			name:       "aggregate with alias",
+			objectType: "Account",
+			fieldList: []string{
+				"Industry",
+			},
+			fields: []Field{
+				countAlias,
+			},
+			want:    "SELECT Industry,COUNT(Id) total FROM Account",
+			wantErr: false,
+		},
+		{
+			name:       "unsafe field",
+			objectType: "Case",
+			fields: []Field{
+				UnsafeField("toLabel(Status)"),
+			},
+			want:    "SELECT toLabel(Status) FROM Case",
+			wantErr: false,
+		},
+		{
+			name:       "field faking a trusted entry",
+			objectType: "Contact",
+			fieldList: []string{
+				"\x00Id FROM User--",
+			},
+			wantErr: true,
+		},
+		{
+			name:       "injected from clause",
+			objectType: "Account",
+			fieldList: []string{
+				"Name FROM Account--",
+			},
+			wantErr: true,
+		},
+		{
+			name:       "injected sub query",
+			objectType: "Account",
+			fieldList: []string{
+				"Id,(SELECT Id FROM Contacts)",
+			},
+			wantErr: true,
+		},
+		{
+			name:       "raw aggregate",
+			objectType: "Account",
+			fieldList: []string{
+				"COUNT(Id)",
+			},
+			wantErr: true,
+		},
+		{
+			name:       "reserved word alias",
+			objectType: "Account",
+			fieldList: []string{
+				"Name FROM",
+			},
+			wantErr: true,
+		},
+		{
+			name:       "trailing dot",
+			objectType: "Account",
+			fieldList: []string{
+				"Owner.",
+			},
+			wantErr: true,
+		},
+		{
+			name:       "injected object type",
+			objectType: "Account WHERE Name != null",
+			fieldList: []string{
+				"Name",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &Query{
+				objectType: tt.objectType,
+				fieldList:  tt.fieldList,
+				fields:     tt.fields,
+			}
+			got, err := b.Format()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Query.Format() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Query.Format() = %v, want %v", got, tt.want)
+			}
+
+			_, err = NewQuery(QueryInput{
+				ObjectType: tt.objectType,
+				FieldList:  tt.fieldList,
+				Fields:     tt.fields,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewQuery() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAggregates(t *testing.T) {
+	tests := []struct {
+		name     string
+		function func(string) (Field, error)
+		field    string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "count all",
+			function: Count,
+			field:    "",
+			want:     "COUNT()",
+			wantErr:  false,
+		},
+		{
+			name:     "count distinct",
+			function: CountDistinct,
+			field:    "Owner.Name",
+			want:     "COUNT_DISTINCT(Owner.Name)",
+			wantErr:  false,
+		},
+		{
+			name:     "sum",
+			function: Sum,
+			field:    "Amount",
+			want:     "SUM(Amount)",
+			wantErr:  false,
+		},
+		{
+			name:     "avg",
+			function: Avg,
+			field:    "Amount",
+			want:     "AVG(Amount)",
+			wantErr:  false,
+		},
+		{
+			name:     "min",
+			function: Min,
+			field:    "CreatedDate",
+			want:     "MIN(CreatedDate)",
+			wantErr:  false,
+		},
+		{
+			name:     "max",
+			function: Max,
+			field:    "CreatedDate",
+			want:     "MAX(CreatedDate)",
+			wantErr:  false,
+		},
+		{
+			name:     "invalid field",
+			function: Max,
+			field:    "Amount) FROM Opportunity--",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.function(tt.field)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("aggregate error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if formatted, _ := got.format(); formatted != tt.want {
+				t.Errorf("aggregate = %v, want %v", formatted, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlias(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   Field
+		alias   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "field",
+			field:   Field{Name: "Industry"},
+			alias:   "ind",
+			want:    "Industry ind",
+			wantErr: false,
+		},
+		{
+			name:    "unsafe field",
+			field:   UnsafeField("toLabel(Status)"),
+			alias:   "status",
+			want:    "toLabel(Status) status",
+			wantErr: false,
+		},
+		{
+			name:    "invalid alias",
+			field:   Field{Name: "Industry"},
+			alias:   "ind FROM Account",
+			wantErr: true,
+		},
+		{
+			name:    "reserved alias",
+			field:   Field{Name: "Industry"},
+			alias:   "where",
+			wantErr: true,
+		},
+		{
+			name:    "invalid field",
+			field:   Field{Name: "Industry,Name"},
+			alias:   "ind",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field, err := Alias(tt.field, tt.alias)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Alias() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got, _ := field.format(); got != tt.want {
+				t.Errorf("Alias() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuery_FormatFields(t *testing.T) {
+	tests := []struct {
+		name       string
+		objectType string
+		fieldList  []string
+		fields     []Field
+		want       string
+		wantErr    bool
+	}{
+		{
+			name:       "aggregate with alias",
+			objectType: "Opportunity",
+			fieldList:  []string{"StageName"},
+			fields: []Field{
+				{Name: "Id", Function: "COUNT", Alias: "cnt"},
+				{Name: "Amount", Function: "SUM", Alias: "total"},
+			},
+			want:    "SELECT StageName,COUNT(Id) cnt,SUM(Amount) total FROM Opportunity",
+			wantErr: false,
+		},
+		{
+			name:       "relationship path",
+			objectType: "Contact",
+			fields: []Field{
+				{Name: "Account.Owner.Name", Alias: "ownerName"},
+				{Name: "Account.Name"},
+			},
+			want:    "SELECT Account.Owner.Name ownerName,Account.Name FROM Contact",
+			wantErr: false,
+		},
+		{
+			name:       "count without a field",
+			objectType: "Account",
+			fields: []Field{
+				{Function: "COUNT"},
+			},
+			want:    "SELECT COUNT() FROM Account",
+			wantErr: false,
+		},
+		{
+			name:       "alias with a space",
+			objectType: "Account",
+			fields: []Field{
+				{Name: "Id", Function: "COUNT", Alias: "cnt FROM Account"},
+			},
+			wantErr: true,
+		},
+		{
+			name:       "alias with a quote",
+			objectType: "Account",
+			fields: []Field{
+				{Name: "Id", Function: "COUNT", Alias: "cnt'"},
+			},
+			wantErr: true,
+		},
+		{
+			name:       "reserved alias",
+			objectType: "Account",
+			fields: []Field{
+				{Name: "Id", Function: "COUNT", Alias: "limit"},
+			},
+			wantErr: true,
+		},
+		{
+			name:       "invalid function",
+			objectType: "Account",
+			fields: []Field{
+				{Name: "Id", Function: "COUNT(Id),MAX"},
+			},
+			wantErr: true,
+		},
+		{
+			name:       "invalid name",
+			objectType: "Account",
+			fields: []Field{
+				{Name: "Owner..Name"},
+			},
+			wantErr: true,
+		},
+		{
+			name:       "empty name",
+			objectType: "Account",
+			fields: []Field{
+				{Function: "SUM"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := NewQuery(QueryInput{
+				ObjectType: tt.objectType,
+				FieldList:  tt.fieldList,
+				Fields:     tt.fields,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewQuery() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			got, err := query.Format()
+			if err != nil {
+				t.Fatalf("Query.Format() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Query.Format() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package soql
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/namely/go-sfdc/v3/internal/fixture"
+)
+
+func TestQueryResponse_fixtures(t *testing.T) {
+	tests := []struct {
+		version         string
+		wantTotalSize   int
+		wantMoreRecords bool
+		wantUnknown     []string
+	}{
+		{
+			version:       "v42.0",
+			wantTotalSize: 2,
+		},
+		{
+			version:         "v50.0",
+			wantTotalSize:   3,
+			wantMoreRecords: true,
+		},
+		{
+			version:       "v58.0",
+			wantTotalSize: 2,
+			wantUnknown:   []string{"$.queryLocator"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			data := fixture.Load(t, tt.version, "query")
+			report, err := fixture.Check(data, reflect.TypeOf(queryResponse{}))
+			if err != nil {
+				t.Fatalf("fixture.Check() error = %v", err)
+			}
+			if len(report.Mismatches) != 0 {
+				t.Errorf("fixture.Check() mismatches = %v", report.Mismatches)
+			}
+			if !reflect.DeepEqual(report.Unknown, tt.wantUnknown) {
+				t.Errorf("fixture.Check() unknown = %v, want %v", report.Unknown, tt.wantUnknown)
+			}
+
+			r := &Resource{
+				session: &mockSessionFormatter{
+					url: "https://test.salesforce.com/services/data/" + tt.version,
+					client: mockHTTPClient(func(req *http.Request) *http.Response {
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Body:       io.NopCloser(bytes.NewReader(data)),
+							Header:     make(http.Header),
+						}
+					}),
+				},
+			}
+			got, err := r.Query(&mockQuerier{stmt: "SELECT Id, Name FROM Account"}, false)
+			if err != nil {
+				t.Fatalf("Resource.Query() error = %v", err)
+			}
+			if got.TotalSize() != tt.wantTotalSize || got.MoreRecords() != tt.wantMoreRecords {
+				t.Errorf("Resource.Query() total size = %d, more records = %v", got.TotalSize(), got.MoreRecords())
+			}
+			records := got.Records()
+			if len(records) != 2 {
+				t.Fatalf("Resource.Query() records = %d, want 2", len(records))
+			}
+			name, _ := records[0].Record().FieldValue("Name")
+			if records[0].Record().SObject() != "Account" || name != "Acme" {
+				t.Errorf("Resource.Query() record = %+v", records[0].Record())
+			}
+			contacts, has := records[0].Subresults()["Contacts"]
+			if has == false || len(contacts.Records()) != 1 {
+				t.Errorf("Resource.Query() subresults = %v", records[0].Subresults())
+			}
+			if len(records[1].Subresults()) != 0 {
+				t.Errorf("Resource.Query() null subresults = %v", records[1].Subresults())
+			}
+		})
+	}
+}
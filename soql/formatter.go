@@ -9,38 +9,57 @@ import (
 
 // QueryInput is used to provide SOQL inputs.
 //
-// ObjectType is the Salesforce Object, like Account
+// # ObjectType is the Salesforce Object, like Account
 //
-// FieldList is the Salesforce Object's fields to query
+// # FieldList is the Salesforce Object's fields to query
 //
-// SubQuery is the inner query
+// Fields are the typed fields to query, with their function and alias, which
+// are queried after the FieldList
 //
-// Where is the SOQL where cause
+// # SubQuery is the inner query
 //
-// Order is the SOQL ordering
+// # Where is the SOQL where cause
 //
-// Limit is the SOQL record limit
+// # GroupBy is the SOQL grouping fields
+//
+// GroupByFields are the typed grouping fields, like an UnsafeField, which are
+// grouped after the GroupBy
+//
+// Having is the SOQL having condition of the grouping, formed like the where
+// clauses
+//
+// # Order is the SOQL ordering
+//
+// # Limit is the SOQL record limit
 //
 // Offset is the SOQL record offset
 type QueryInput struct {
-	FieldList  []string
-	ObjectType string
-	SubQuery   []QueryFormatter
-	Where      WhereClauser
-	Order      Orderer
-	Limit      int
-	Offset     int
+	FieldList     []string
+	Fields        []Field
+	ObjectType    string
+	SubQuery      []QueryFormatter
+	Where         WhereClauser
+	GroupBy       []string
+	GroupByFields []Field
+	Having        WhereExpression
+	Order         Orderer
+	Limit         int
+	Offset        int
 }
 
 // Query is the struture used to build a SOQL query.
 type Query struct {
-	fieldList  []string
-	objectType string
-	subQuery   []QueryFormatter
-	where      WhereClauser
-	order      Orderer
-	limit      int
-	offset     int
+	fieldList     []string
+	fields        []Field
+	objectType    string
+	subQuery      []QueryFormatter
+	where         WhereClauser
+	groupBy       []string
+	groupByFields []Field
+	having        WhereExpression
+	order         Orderer
+	limit         int
+	offset        int
 }
 
 // QueryFormatter is the interface to return the SOQL query.
@@ -51,37 +70,72 @@ type QueryFormatter interface {
 }
 
 // NewQuery creates a new builder.  If the object is an
-// empty string, then an error is returned.
+// empty string, then an error is returned.  The object type and the
+// field list entries must be valid identifiers, otherwise an error
+// is returned.  Aggregate functions must be typed fields, like the ones
+// formed by Count, and other expressions UnsafeField fields.  The
+// group by fields must be valid identifiers too, and a having condition
+// without group by fields returns an error.
 func NewQuery(input QueryInput) (*Query, error) {
 	if input.ObjectType == "" {
 		return nil, errors.New("builder: object type can not be an empty string")
 	}
-	if len(input.FieldList) == 0 {
+	if len(input.FieldList) == 0 && len(input.Fields) == 0 {
 		return nil, errors.New("builder: field list can not be empty")
 	}
+	if err := validateObjectType(input.ObjectType); err != nil {
+		return nil, err
+	}
+	if err := validateFieldList(input.FieldList); err != nil {
+		return nil, err
+	}
+	if _, err := formatFields(input.Fields); err != nil {
+		return nil, err
+	}
+	if err := validateGrouping(input.GroupBy, input.GroupByFields, input.Having); err != nil {
+		return nil, err
+	}
 
 	return &Query{
-		objectType: input.ObjectType,
-		fieldList:  input.FieldList,
-		subQuery:   input.SubQuery,
-		where:      input.Where,
-		order:      input.Order,
-		limit:      input.Limit,
-		offset:     input.Offset,
+		objectType:    input.ObjectType,
+		fieldList:     input.FieldList,
+		fields:        input.Fields,
+		subQuery:      input.SubQuery,
+		where:         input.Where,
+		groupBy:       input.GroupBy,
+		groupByFields: input.GroupByFields,
+		having:        input.Having,
+		order:         input.Order,
+		limit:         input.Limit,
+		offset:        input.Offset,
 	}, nil
 }
 
-// Format will return the SOQL query.  If the builder has an empty string or
-// the field list is zero, an error is returned.
+// Format will return the SOQL query.  If the builder has an empty string,
+// the field list is zero or an entry is not a valid identifier, an error
+// is returned.
 func (b *Query) Format() (string, error) {
 	if b.objectType == "" {
 		return "", errors.New("builder: object type can not be an empty string")
 	}
-	if len(b.fieldList) == 0 {
+	if len(b.fieldList) == 0 && len(b.fields) == 0 {
 		return "", errors.New("builder: field list must be have fields present")
 	}
+	if err := validateObjectType(b.objectType); err != nil {
+		return "", err
+	}
+	if err := validateFieldList(b.fieldList); err != nil {
+		return "", err
+	}
+	fields, err := formatFields(b.fields)
+	if err != nil {
+		return "", err
+	}
+	if err := validateGrouping(b.groupBy, b.groupByFields, b.having); err != nil {
+		return "", err
+	}
 
-	soql := "SELECT " + strings.Join(b.fieldList, ",")
+	soql := "SELECT " + strings.Join(append(append([]string(nil), b.fieldList...), fields...), ",")
 	if b.subQuery != nil {
 		for _, query := range b.subQuery {
 			var sub string
@@ -97,6 +151,16 @@ func (b *Query) Format() (string, error) {
 	if b.where != nil {
 		soql += " " + b.where.Clause()
 	}
+	if len(b.groupBy) > 0 || len(b.groupByFields) > 0 {
+		groupBy, err := formatFields(b.groupByFields)
+		if err != nil {
+			return "", err
+		}
+		soql += " GROUP BY " + strings.Join(append(append([]string(nil), b.groupBy...), groupBy...), ",")
+	}
+	if b.having != nil {
+		soql += " HAVING " + b.having.Expression()
+	}
 	if b.order != nil {
 		order, err := b.order.Order()
 		if err == nil {
@@ -114,6 +178,23 @@ func (b *Query) Format() (string, error) {
 	return soql, nil
 }
 
+// validateGrouping returns an error if a grouping field is not a valid
+// identifier, or the having condition is without grouping fields.
+func validateGrouping(groupBy []string, groupByFields []Field, having WhereExpression) error {
+	for _, field := range groupBy {
+		if pathPattern.MatchString(field) == false {
+			return fmt.Errorf("builder: group by field %q is not a valid identifier", field)
+		}
+	}
+	if _, err := formatFields(groupByFields); err != nil {
+		return err
+	}
+	if having != nil && len(groupBy) == 0 && len(groupByFields) == 0 {
+		return errors.New("builder: having condition requires group by fields")
+	}
+	return nil
+}
+
 // WhereClause is the structure that will contain a SOQL where clause.
 type WhereClause struct {
 	expression string
@@ -252,7 +333,8 @@ func WhereNotEquals(field string, value interface{}) (*WhereClause, error) {
 	}, nil
 }
 
-// WhereIn forms the field in a set expression.
+// WhereIn forms the field in a set expression.  If the values are empty, the
+// expression matches no records.
 func WhereIn(field string, values []interface{}) (*WhereClause, error) {
 	if field == "" {
 		return nil, errors.New("soql where: field can not be empty")
@@ -260,6 +342,9 @@ func WhereIn(field string, values []interface{}) (*WhereClause, error) {
 	if values == nil {
 		return nil, errors.New("soql where: value array can not be nil")
 	}
+	if len(values) == 0 {
+		return emptyIn(field, nil)
+	}
 	set := make([]string, len(values))
 	for idx, value := range values {
 		switch value.(type) {
@@ -280,7 +365,8 @@ func WhereIn(field string, values []interface{}) (*WhereClause, error) {
 	}, nil
 }
 
-// WhereNotIn forms the field is not in a set expression.
+// WhereNotIn forms the field is not in a set expression.  If the values are
+// empty, the expression matches all records.
 func WhereNotIn(field string, values []interface{}) (*WhereClause, error) {
 	if field == "" {
 		return nil, errors.New("soql where: field can not be empty")
@@ -288,6 +374,11 @@ func WhereNotIn(field string, values []interface{}) (*WhereClause, error) {
 	if values == nil {
 		return nil, errors.New("soql where: value array can not be nil")
 	}
+	if len(values) == 0 {
+		return &WhereClause{
+			expression: fmt.Sprintf("(%s = null OR %s != null)", field, field),
+		}, nil
+	}
 	set := make([]string, len(values))
 	for idx, value := range values {
 		switch value.(type) {
@@ -314,16 +405,24 @@ func (wc *WhereClause) Clause() string {
 }
 
 // Group will form a grouping around the expression.
+//
+// Deprecated: And, Or and Not group the expressions when needed.
 func (wc *WhereClause) Group() {
 	wc.expression = fmt.Sprintf("(%s)", wc.expression)
 }
 
 // And will logical AND the expressions.
+//
+// Deprecated: the receiver is changed and the expressions are not grouped.  Use
+// the And function instead.
 func (wc *WhereClause) And(where WhereExpression) {
 	wc.expression = fmt.Sprintf("%s AND %s", wc.expression, where.Expression())
 }
 
 // Or will logical OR the expressions.
+//
+// Deprecated: the receiver is changed and the expressions are not grouped.  Use
+// the Or function instead.
 func (wc *WhereClause) Or(where WhereExpression) {
 	wc.expression = fmt.Sprintf("%s OR %s", wc.expression, where.Expression())
 }
@@ -969,13 +969,17 @@ func TestWhereClause_Expression(t *testing.T) {
 
 func TestBuilder_Query(t *testing.T) {
 	type fields struct {
-		fieldList  []string
-		objectType string
-		subQuery   []QueryFormatter
-		where      WhereClauser
-		order      Orderer
-		limit      int
-		offset     int
+		fieldList     []string
+		fields        []Field
+		objectType    string
+		subQuery      []QueryFormatter
+		where         WhereClauser
+		groupBy       []string
+		groupByFields []Field
+		having        WhereExpression
+		order         Orderer
+		limit         int
+		offset        int
 	}
 	tests := []struct {
 		name    string
@@ -1088,17 +1092,95 @@ func TestBuilder_Query(t *testing.T) {
 			want:    "SELECT Name,CreatedBy FROM Account OFFSET 150",
 			wantErr: false,
 		},
+		{
+			name: "Group By and Having",
+			fields: fields{
+				objectType: "Opportunity",
+				fieldList: []string{
+					"StageName",
+				},
+				fields: []Field{
+					UnsafeField("CALENDAR_YEAR(CloseDate)"),
+				},
+				where: &WhereClause{
+					expression: "IsClosed = true",
+				},
+				groupBy: []string{
+					"StageName",
+				},
+				groupByFields: []Field{
+					UnsafeField("CALENDAR_YEAR(CloseDate)"),
+				},
+				having: &WhereClause{
+					expression: "COUNT(Id) > 1",
+				},
+				order: &OrderBy{
+					fieldOrder: []string{"StageName"},
+					result:     OrderAsc,
+				},
+				limit:  10,
+				offset: 5,
+			},
+			want:    "SELECT StageName,CALENDAR_YEAR(CloseDate) FROM Opportunity WHERE IsClosed = true GROUP BY StageName,CALENDAR_YEAR(CloseDate) HAVING COUNT(Id) > 1 ORDER BY StageName ASC LIMIT 10 OFFSET 5",
+			wantErr: false,
+		},
+		{
+			name: "Group By Relationship",
+			fields: fields{
+				objectType: "Contact",
+				fieldList: []string{
+					"Account.Name",
+				},
+				groupBy: []string{
+					"Account.Name",
+				},
+			},
+			want:    "SELECT Account.Name FROM Contact GROUP BY Account.Name",
+			wantErr: false,
+		},
+		{
+			name: "Having Without Group By",
+			fields: fields{
+				objectType: "Opportunity",
+				fieldList: []string{
+					"StageName",
+				},
+				having: &WhereClause{
+					expression: "COUNT(Id) > 1",
+				},
+			},
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name: "Invalid Group By",
+			fields: fields{
+				objectType: "Opportunity",
+				fieldList: []string{
+					"StageName",
+				},
+				groupBy: []string{
+					"StageName HAVING COUNT(Id) > 1",
+				},
+			},
+			want:    "",
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			b := &Query{
-				fieldList:  tt.fields.fieldList,
-				objectType: tt.fields.objectType,
-				subQuery:   tt.fields.subQuery,
-				where:      tt.fields.where,
-				order:      tt.fields.order,
-				limit:      tt.fields.limit,
-				offset:     tt.fields.offset,
+				fieldList:     tt.fields.fieldList,
+				fields:        tt.fields.fields,
+				objectType:    tt.fields.objectType,
+				subQuery:      tt.fields.subQuery,
+				where:         tt.fields.where,
+				groupBy:       tt.fields.groupBy,
+				groupByFields: tt.fields.groupByFields,
+				having:        tt.fields.having,
+				order:         tt.fields.order,
+				limit:         tt.fields.limit,
+				offset:        tt.fields.offset,
 			}
 			got, err := b.Format()
 			if (err != nil) != tt.wantErr {
@@ -1179,3 +1261,36 @@ func TestNewBuilder(t *testing.T) {
 		})
 	}
 }
+
+func TestNewQuery_grouping(t *testing.T) {
+	having, err := WhereGreaterThan("COUNT(Id)", 1, false)
+	if err != nil {
+		t.Fatalf("WhereGreaterThan() error = %v", err)
+	}
+	input := QueryInput{
+		ObjectType: "Opportunity",
+		FieldList:  []string{"StageName"},
+		Fields: []Field{
+			{Name: "Id", Function: "COUNT", Alias: "cnt"},
+		},
+		GroupBy: []string{"StageName"},
+		Having:  having,
+	}
+	query, err := NewQuery(input)
+	if err != nil {
+		t.Fatalf("NewQuery() error = %v", err)
+	}
+	got, err := query.Format()
+	if err != nil {
+		t.Fatalf("Query.Format() error = %v", err)
+	}
+	want := "SELECT StageName,COUNT(Id) cnt FROM Opportunity GROUP BY StageName HAVING COUNT(Id) > 1"
+	if got != want {
+		t.Errorf("Query.Format() = %v, want %v", got, want)
+	}
+
+	input.GroupBy = nil
+	if _, err := NewQuery(input); err == nil {
+		t.Errorf("NewQuery() error = %v, want having without group by error", err)
+	}
+}
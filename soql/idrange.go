@@ -0,0 +1,70 @@
+package soql
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/namely/go-sfdc/v3/bulk"
+)
+
+// IDRangePartitions will sample the lowest and highest Id of the object and
+// split them into at most partitions evenly spaced Id ranges, for running
+// bulk query jobs in parallel with bulk.SplitQueryByIDRange.  If the object has
+// no records, a single unbounded range is returned.
+func (r *Resource) IDRangePartitions(ctx context.Context, objectType string, partitions int) ([]bulk.IDRange, error) {
+	if partitions < 1 {
+		return nil, errors.New("soql id range partitions: partitions must be greater than zero")
+	}
+	min, err := r.boundaryID(ctx, objectType, OrderAsc)
+	if err != nil {
+		return nil, err
+	}
+	if min == "" {
+		return []bulk.IDRange{{}}, nil
+	}
+	max, err := r.boundaryID(ctx, objectType, OrderDesc)
+	if err != nil {
+		return nil, err
+	}
+	if max == "" {
+		return []bulk.IDRange{{}}, nil
+	}
+	return bulk.SplitIDRange(min, max, partitions)
+}
+
+// boundaryID returns the first Id of the object in the order, or an empty
+// string if the object has no records.
+func (r *Resource) boundaryID(ctx context.Context, objectType string, result OrderResult) (string, error) {
+	order, err := NewOrderBy(result)
+	if err != nil {
+		return "", err
+	}
+	order.FieldOrder("Id")
+	query, err := NewQuery(QueryInput{
+		FieldList:  []string{"Id"},
+		ObjectType: objectType,
+		Order:      order,
+		Limit:      1,
+	})
+	if err != nil {
+		return "", err
+	}
+	queryResult, err := r.QueryContext(ctx, query, false)
+	if err != nil {
+		return "", err
+	}
+	records := queryResult.Records()
+	if len(records) == 0 {
+		return "", nil
+	}
+	id, ok := records[0].Record().FieldValue("Id")
+	if ok == false {
+		return "", errors.Errorf("soql id range partitions: %s record has no Id", objectType)
+	}
+	value, ok := id.(string)
+	if ok == false || value == "" {
+		return "", errors.Errorf("soql id range partitions: %s record has an invalid Id %v", objectType, id)
+	}
+	return value, nil
+}
@@ -0,0 +1,97 @@
+package soql
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/namely/go-sfdc/v3/bulk"
+)
+
+func TestResource_IDRangePartitions(t *testing.T) {
+	idResponse := func(ids ...string) string {
+		records := make([]string, len(ids))
+		for idx, id := range ids {
+			records[idx] = `{"attributes": {"type": "Account"}, "Id": "` + id + `"}`
+		}
+		return `{"done": true, "totalSize": ` + strconv.Itoa(len(ids)) + `, "records": [` + strings.Join(records, ",") + `]}`
+	}
+	tests := []struct {
+		name       string
+		min        string
+		max        string
+		partitions int
+		want       []bulk.IDRange
+		wantErr    bool
+	}{
+		{
+			name:       "partitions",
+			min:        "001000000000000AAA",
+			max:        "001000000000020AAA",
+			partitions: 2,
+			want: []bulk.IDRange{
+				{To: "001000000000010AAA"},
+				{From: "001000000000010AAA"},
+			},
+		},
+		{
+			name:       "single partition",
+			min:        "001000000000000AAA",
+			max:        "001000000000020AAA",
+			partitions: 1,
+			want:       []bulk.IDRange{{}},
+		},
+		{
+			name:       "no records",
+			partitions: 4,
+			want:       []bulk.IDRange{{}},
+		},
+		{
+			name:       "no partitions",
+			partitions: 0,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var queries []string
+			resource := &Resource{
+				session: &mockSessionFormatter{
+					url: "https://test.salesforce.com",
+					client: mockHTTPClient(func(req *http.Request) *http.Response {
+						query := req.URL.Query().Get("q")
+						queries = append(queries, query)
+						resp := idResponse()
+						switch {
+						case tt.min == "":
+						case strings.HasSuffix(query, "ORDER BY Id ASC LIMIT 1"):
+							resp = idResponse(tt.min)
+						case strings.HasSuffix(query, "ORDER BY Id DESC LIMIT 1"):
+							resp = idResponse(tt.max)
+						}
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Status:     "Good",
+							Body:       io.NopCloser(strings.NewReader(resp)),
+							Header:     make(http.Header),
+						}
+					}),
+				},
+			}
+			got, err := resource.IDRangePartitions(context.Background(), "Account", tt.partitions)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resource.IDRangePartitions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Resource.IDRangePartitions() = %v, want %v", got, tt.want)
+			}
+			if tt.min != "" && len(queries) != 2 {
+				t.Errorf("Resource.IDRangePartitions() queries = %v, want min and max", queries)
+			}
+		})
+	}
+}
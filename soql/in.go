@@ -0,0 +1,108 @@
+package soql
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/namely/go-sfdc/v3"
+)
+
+// EmptySet is how the IN helpers form the expression when there are no values,
+// since "IN ()" is not valid SOQL.
+type EmptySet int
+
+const (
+	// EmptySetMatchNone forms an expression that matches no records.
+	EmptySetMatchNone EmptySet = iota
+	// EmptySetError returns ErrEmptySet.
+	EmptySetError
+)
+
+// ErrEmptySet is returned by the IN helpers for an empty set of values when
+// EmptySetError is used.
+var ErrEmptySet = errors.New("soql where: value set can not be empty")
+
+var stringEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`'`, `\'`,
+	`"`, `\"`,
+	"\n", `\n`,
+	"\r", `\r`,
+	"\t", `\t`,
+	"\b", `\b`,
+	"\f", `\f`,
+)
+
+// WhereInStrings forms the field in a set expression from the strings.  The
+// strings are quoted and escaped.
+func WhereInStrings(field string, values []string, empty ...EmptySet) (*WhereClause, error) {
+	return WhereInSlice(field, values, empty...)
+}
+
+// WhereInIDs forms the field in a set expression from the Salesforce IDs.  Each
+// ID must be a 15 or 18 character Salesforce ID.
+func WhereInIDs(field string, ids []string, empty ...EmptySet) (*WhereClause, error) {
+	for _, id := range ids {
+		if sfdc.ValidID(id) == false {
+			return nil, fmt.Errorf("soql where: %q is not a valid id", id)
+		}
+	}
+	return WhereInSlice(field, ids, empty...)
+}
+
+// WhereInSlice forms the field in a set expression from the values without
+// boxing them in an []interface{}.  String values are quoted and escaped, and
+// numbers are formatted by their kind, so the String method of a named type is
+// not used.  Float values are formatted without an exponent, which SOQL does
+// not accept, and NaN and infinite values are an error.
+func WhereInSlice[T ~string | ~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~float32 | ~float64](field string, values []T, empty ...EmptySet) (*WhereClause, error) {
+	if field == "" {
+		return nil, errors.New("soql where: field can not be empty")
+	}
+	if values == nil {
+		return nil, errors.New("soql where: value array can not be nil")
+	}
+	if len(values) == 0 {
+		return emptyIn(field, empty)
+	}
+
+	set := make([]string, len(values))
+	for idx, value := range values {
+		v := reflect.ValueOf(value)
+		switch v.Kind() {
+		case reflect.String:
+			set[idx] = quote(v.String())
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			set[idx] = strconv.FormatInt(v.Int(), 10)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			set[idx] = strconv.FormatUint(v.Uint(), 10)
+		case reflect.Float32, reflect.Float64:
+			f := v.Float()
+			if math.IsNaN(f) || math.IsInf(f, 0) {
+				return nil, fmt.Errorf("soql where: %v is not a valid number", f)
+			}
+			set[idx] = strconv.FormatFloat(f, 'f', -1, v.Type().Bits())
+		}
+	}
+
+	return &WhereClause{
+		expression: fmt.Sprintf("%s IN (%s)", field, strings.Join(set, ",")),
+	}, nil
+}
+
+func emptyIn(field string, empty []EmptySet) (*WhereClause, error) {
+	if len(empty) > 0 && empty[0] == EmptySetError {
+		return nil, ErrEmptySet
+	}
+	return &WhereClause{
+		expression: fmt.Sprintf("(%s = null AND %s != null)", field, field),
+	}, nil
+}
+
+func quote(value string) string {
+	return "'" + stringEscaper.Replace(value) + "'"
+}
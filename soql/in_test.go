@@ -0,0 +1,204 @@
+package soql
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+)
+
+type accountName string
+
+// rating is a numeric enum whose String method is its name.
+type rating int
+
+func (r rating) String() string {
+	return [...]string{"Cold", "Warm", "Hot"}[r]
+}
+
+// revenue is a named float whose String method is formatted for display.
+type revenue float32
+
+func (r revenue) String() string {
+	return "$" + fmt.Sprint(float32(r))
+}
+
+func TestWhereInStrings(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   string
+		values  []string
+		empty   []EmptySet
+		want    string
+		wantErr error
+	}{
+		{
+			name:   "strings",
+			field:  "Name",
+			values: []string{"Acme", "Global Media"},
+			want:   "Name IN ('Acme','Global Media')",
+		},
+		{
+			name:   "escaped",
+			field:  "Name",
+			values: []string{"O'Brien", `back\slash`, "new\nline"},
+			want:   `Name IN ('O\'Brien','back\\slash','new\nline')`,
+		},
+		{
+			name:   "empty match none",
+			field:  "Name",
+			values: []string{},
+			want:   "(Name = null AND Name != null)",
+		},
+		{
+			name:    "empty error",
+			field:   "Name",
+			values:  []string{},
+			empty:   []EmptySet{EmptySetError},
+			wantErr: ErrEmptySet,
+		},
+		{
+			name:    "no field",
+			values:  []string{"Acme"},
+			wantErr: errors.New("soql where: field can not be empty"),
+		},
+		{
+			name:    "nil values",
+			field:   "Name",
+			wantErr: errors.New("soql where: value array can not be nil"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := WhereInStrings(tt.field, tt.values, tt.empty...)
+			if tt.wantErr != nil {
+				if err == nil || err.Error() != tt.wantErr.Error() {
+					t.Errorf("WhereInStrings() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("WhereInStrings() error = %v", err)
+			}
+			if got.Expression() != tt.want {
+				t.Errorf("WhereInStrings() = %v, want %v", got.Expression(), tt.want)
+			}
+		})
+	}
+}
+
+func TestWhereInIDs(t *testing.T) {
+	tests := []struct {
+		name    string
+		ids     []string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "15 and 18 character ids",
+			ids:  []string{"001D000000INjVe", "001D000000INjVeIAL"},
+			want: "Id IN ('001D000000INjVe','001D000000INjVeIAL')",
+		},
+		{
+			name:    "invalid id",
+			ids:     []string{"001D000000INjVeIAL", "' OR Name != '"},
+			wantErr: true,
+		},
+		{
+			name: "empty",
+			ids:  []string{},
+			want: "(Id = null AND Id != null)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := WhereInIDs("Id", tt.ids)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WhereInIDs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.Expression() != tt.want {
+				t.Errorf("WhereInIDs() = %v, want %v", got.Expression(), tt.want)
+			}
+		})
+	}
+}
+
+func TestWhereInSlice(t *testing.T) {
+	ints, err := WhereInSlice("NumberOfEmployees", []int{10, 20})
+	if err != nil {
+		t.Fatalf("WhereInSlice() error = %v", err)
+	}
+	if want := "NumberOfEmployees IN (10,20)"; ints.Expression() != want {
+		t.Errorf("WhereInSlice() = %v, want %v", ints.Expression(), want)
+	}
+
+	floats, err := WhereInSlice("AnnualRevenue", []float64{1.5, 2, 1e21, 0.0000001})
+	if err != nil {
+		t.Fatalf("WhereInSlice() error = %v", err)
+	}
+	if want := "AnnualRevenue IN (1.5,2,1000000000000000000000,0.0000001)"; floats.Expression() != want {
+		t.Errorf("WhereInSlice() = %v, want %v", floats.Expression(), want)
+	}
+
+	names, err := WhereInSlice("Name", []accountName{"Acme", "O'Brien"})
+	if err != nil {
+		t.Fatalf("WhereInSlice() error = %v", err)
+	}
+	if want := `Name IN ('Acme','O\'Brien')`; names.Expression() != want {
+		t.Errorf("WhereInSlice() = %v, want %v", names.Expression(), want)
+	}
+
+	ratings, err := WhereInSlice("Rating__c", []rating{0, 2})
+	if err != nil {
+		t.Fatalf("WhereInSlice() error = %v", err)
+	}
+	if want := "Rating__c IN (0,2)"; ratings.Expression() != want {
+		t.Errorf("WhereInSlice() = %v, want %v", ratings.Expression(), want)
+	}
+
+	revenues, err := WhereInSlice("AnnualRevenue", []revenue{1.25})
+	if err != nil {
+		t.Fatalf("WhereInSlice() error = %v", err)
+	}
+	if want := "AnnualRevenue IN (1.25)"; revenues.Expression() != want {
+		t.Errorf("WhereInSlice() = %v, want %v", revenues.Expression(), want)
+	}
+
+	uints, err := WhereInSlice("NumberOfEmployees", []uint64{math.MaxUint64})
+	if err != nil {
+		t.Fatalf("WhereInSlice() error = %v", err)
+	}
+	if want := "NumberOfEmployees IN (18446744073709551615)"; uints.Expression() != want {
+		t.Errorf("WhereInSlice() = %v, want %v", uints.Expression(), want)
+	}
+
+	for _, invalid := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		if _, err := WhereInSlice("AnnualRevenue", []float64{1, invalid}); err == nil {
+			t.Errorf("WhereInSlice() of %v expected an error", invalid)
+		}
+	}
+
+	if _, err := WhereInSlice("NumberOfEmployees", []int{}, EmptySetError); err != ErrEmptySet {
+		t.Errorf("WhereInSlice() error = %v, want %v", err, ErrEmptySet)
+	}
+}
+
+func TestWhereIn_empty(t *testing.T) {
+	in, err := WhereIn("Name", []interface{}{})
+	if err != nil {
+		t.Fatalf("WhereIn() error = %v", err)
+	}
+	if want := "(Name = null AND Name != null)"; in.Expression() != want {
+		t.Errorf("WhereIn() = %v, want %v", in.Expression(), want)
+	}
+	notIn, err := WhereNotIn("Name", []interface{}{})
+	if err != nil {
+		t.Fatalf("WhereNotIn() error = %v", err)
+	}
+	if want := "(Name = null OR Name != null)"; notIn.Expression() != want {
+		t.Errorf("WhereNotIn() = %v, want %v", notIn.Expression(), want)
+	}
+}
@@ -0,0 +1,210 @@
+package soql
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/namely/go-sfdc/v3"
+	"github.com/namely/go-sfdc/v3/credentials"
+	"github.com/namely/go-sfdc/v3/session"
+)
+
+func TestQueryResult_instanceChange(t *testing.T) {
+	instanceURL := "https://na1.salesforce.com"
+	var hosts []string
+	client := mockHTTPClient(func(req *http.Request) *http.Response {
+		if req.URL.Path == "/services/oauth2/token" {
+			resp := fmt.Sprintf(`{"access_token": "token", "instance_url": %q, "token_type": "Bearer"}`, instanceURL)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(resp)),
+				Header:     make(http.Header),
+			}
+		}
+		hosts = append(hosts, req.URL.Host)
+		resp := `{
+			"done": true,
+			"totalSize": 2,
+			"records": [
+				{
+					"attributes": {"type": "Account", "url": "/services/data/v45.0/sobjects/Account/001D000000IRFmaIAH"},
+					"Name": "Test 2"
+				}
+			]
+		}`
+		if req.URL.RawQuery != "" {
+			resp = `{
+				"done": false,
+				"totalSize": 2,
+				"nextRecordsUrl": "https://na1.salesforce.com/services/data/v45.0/query/01gD0000002HU6KIAW-1",
+				"records": [
+					{
+						"attributes": {"type": "Account", "url": "/services/data/v45.0/sobjects/Account/001D000000IRFmaIAG"},
+						"Name": "Test 1"
+					}
+				]
+			}`
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(resp)),
+			Header:     make(http.Header),
+		}
+	})
+	creds, err := credentials.NewPasswordCredentials(credentials.PasswordCredentials{
+		URL:          "https://login.salesforce.com",
+		Username:     "myusername",
+		Password:     "12345",
+		ClientID:     "some client id",
+		ClientSecret: "shhhh its a secret",
+	})
+	if err != nil {
+		t.Fatalf("credentials.NewPasswordCredentials() error = %v", err)
+	}
+	sess, err := session.Open(sfdc.Configuration{
+		Credentials: creds,
+		Client:      client,
+		Version:     45,
+	})
+	if err != nil {
+		t.Fatalf("session.Open() error = %v", err)
+	}
+
+	resource, err := NewResource(sess)
+	if err != nil {
+		t.Fatalf("NewResource() error = %v", err)
+	}
+	result, err := resource.Query(&mockQuerier{stmt: "SELECT Name FROM Account"}, false)
+	if err != nil {
+		t.Fatalf("Resource.Query() error = %v", err)
+	}
+
+	instanceURL = "https://na2.salesforce.com"
+	if err := sess.ForceRefresh(context.Background()); err != nil {
+		t.Fatalf("Session.ForceRefresh() error = %v", err)
+	}
+
+	next, err := result.Next()
+	if err != nil {
+		t.Fatalf("QueryResult.Next() error = %v", err)
+	}
+	if next.Done() == false {
+		t.Errorf("QueryResult.Next() is not done")
+	}
+
+	want := []string{"na1.salesforce.com", "na2.salesforce.com"}
+	if strings.Join(hosts, ",") != strings.Join(want, ",") {
+		t.Errorf("hosts = %v, want %v", hosts, want)
+	}
+}
+
+func TestQuery_userAgent(t *testing.T) {
+	var userAgent string
+	client := mockHTTPClient(func(req *http.Request) *http.Response {
+		resp := `{"done": true, "totalSize": 0, "records": []}`
+		if req.URL.Path == "/services/oauth2/token" {
+			resp = `{"access_token": "token", "instance_url": "https://na1.salesforce.com", "token_type": "Bearer"}`
+		} else {
+			userAgent = req.Header.Get("User-Agent")
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(resp)),
+			Header:     make(http.Header),
+		}
+	})
+	creds, err := credentials.NewPasswordCredentials(credentials.PasswordCredentials{
+		URL:          "https://login.salesforce.com",
+		Username:     "myusername",
+		Password:     "12345",
+		ClientID:     "some client id",
+		ClientSecret: "shhhh its a secret",
+	})
+	if err != nil {
+		t.Fatalf("credentials.NewPasswordCredentials() error = %v", err)
+	}
+	sess, err := session.Open(sfdc.Configuration{
+		Credentials:     creds,
+		Client:          client,
+		Version:         45,
+		UserAgentSuffix: "acme-sync/1.2",
+	})
+	if err != nil {
+		t.Fatalf("session.Open() error = %v", err)
+	}
+
+	resource, err := NewResource(sess)
+	if err != nil {
+		t.Fatalf("NewResource() error = %v", err)
+	}
+	if _, err := resource.Query(&mockQuerier{stmt: "SELECT Name FROM Account"}, false); err != nil {
+		t.Fatalf("Resource.Query() error = %v", err)
+	}
+
+	if want := sfdc.UserAgent("acme-sync/1.2"); userAgent != want {
+		t.Errorf("User-Agent = %v, want %v", userAgent, want)
+	}
+}
+
+func TestNewResource_lazyRefresh(t *testing.T) {
+	var tokens int
+	var authorizations []string
+	client := mockHTTPClient(func(req *http.Request) *http.Response {
+		if req.URL.Path == "/services/oauth2/token" {
+			tokens++
+			resp := fmt.Sprintf(`{"access_token": "token %d", "instance_url": "https://na1.salesforce.com", "token_type": "Bearer"}`, tokens)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(resp)),
+				Header:     make(http.Header),
+			}
+		}
+		authorizations = append(authorizations, req.Header.Get("Authorization"))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"done": true, "totalSize": 0, "records": []}`)),
+			Header:     make(http.Header),
+		}
+	})
+	creds, err := credentials.NewPasswordCredentials(credentials.PasswordCredentials{
+		URL:          "https://login.salesforce.com",
+		Username:     "myusername",
+		Password:     "12345",
+		ClientID:     "some client id",
+		ClientSecret: "shhhh its a secret",
+	})
+	if err != nil {
+		t.Fatalf("credentials.NewPasswordCredentials() error = %v", err)
+	}
+	// the session expires as soon as it is opened
+	sess, err := session.Open(sfdc.Configuration{
+		Credentials:     creds,
+		Client:          client,
+		Version:         45,
+		SessionDuration: time.Nanosecond,
+		LazyRefresh:     true,
+	})
+	if err != nil {
+		t.Fatalf("session.Open() error = %v", err)
+	}
+
+	resource, err := NewResource(sess)
+	if err != nil {
+		t.Fatalf("NewResource() error = %v", err)
+	}
+	if tokens != 1 {
+		t.Errorf("NewResource() requested %d tokens, want no refresh", tokens-1)
+	}
+
+	if _, err := resource.Query(&mockQuerier{stmt: "SELECT Id FROM Account"}, false); err != nil {
+		t.Fatalf("Resource.Query() error = %v", err)
+	}
+	if tokens != 2 || len(authorizations) != 1 || authorizations[0] != "Bearer token 2" {
+		t.Errorf("Resource.Query() tokens = %d, authorizations = %v, want a lazy refresh", tokens, authorizations)
+	}
+}
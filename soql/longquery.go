@@ -0,0 +1,159 @@
+package soql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/namely/go-sfdc/v3"
+	"github.com/namely/go-sfdc/v3/composite"
+	"github.com/pkg/errors"
+)
+
+// MaxQueryURLLength is the longest URL of a query that is sent to the query
+// endpoint.  A longer query is sent in a composite subrequest, whose URL is in
+// the request body.
+const MaxQueryURLLength = 16384
+
+// querySubrequestID is the reference ID of the composite query subrequest.
+const querySubrequestID = "query"
+
+// QueryTooLongError is returned when the URL of a query is too long for the
+// query endpoint and the composite fallback is disabled.
+//
+// Length is the length of the query's URL.
+//
+// Limit is the longest URL that is sent.
+//
+// StatusCode is the status that Salesforce rejected the URL with, like 414 or
+// 431.  It is zero when the URL was not sent.
+type QueryTooLongError struct {
+	Length     int
+	Limit      int
+	StatusCode int
+}
+
+func (e *QueryTooLongError) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("soql query: url of %d bytes was rejected with status %d", e.Length, e.StatusCode)
+	}
+	return fmt.Sprintf("soql query: url of %d bytes is longer than %d bytes", e.Length, e.Limit)
+}
+
+// WithoutCompositeFallback will return a QueryTooLongError for the queries
+// whose URL is too long, instead of sending them in a composite subrequest.
+func WithoutCompositeFallback() ResourceOption {
+	return func(r *Resource) {
+		r.noCompositeFallback = true
+	}
+}
+
+// query sends the query request.  A request whose URL is longer than
+// MaxQueryURLLength, or that is rejected with a 414 or 431, is sent in a
+// composite subrequest unless the fallback is disabled.
+func (r *Resource) query(ctx context.Context, request *http.Request) (queryResponse, error) {
+	length := len(request.URL.String())
+	if length > MaxQueryURLLength {
+		return r.queryTooLong(ctx, request, &QueryTooLongError{
+			Length: length,
+			Limit:  MaxQueryURLLength,
+		})
+	}
+
+	response, err := r.queryResponse(request)
+	var tooLong *QueryTooLongError
+	if errors.As(err, &tooLong) {
+		return r.queryTooLong(ctx, request, tooLong)
+	}
+	return response, err
+}
+
+// queryTooLong sends the query request in a composite subrequest, or returns
+// the error if the fallback is disabled.  The next records URL of the response
+// is followed like the one of the query endpoint.
+func (r *Resource) queryTooLong(ctx context.Context, request *http.Request, tooLong *QueryTooLongError) (queryResponse, error) {
+	if r.noCompositeFallback {
+		return queryResponse{}, tooLong
+	}
+
+	var options []composite.ResourceOption
+	if r.useNumber {
+		options = append(options, composite.WithUseNumber())
+	}
+	resource, err := composite.NewResource(r.session, options...)
+	if err != nil {
+		return queryResponse{}, err
+	}
+	value, err := resource.RetrieveContext(ctx, false, []composite.Subrequester{
+		newQuerySubrequest(request),
+	})
+	if err != nil {
+		return queryResponse{}, errors.Wrap(err, "soql composite query")
+	}
+	if len(value.Response) != 1 {
+		return queryResponse{}, errors.Errorf("soql composite query: %d subresponses, want 1", len(value.Response))
+	}
+
+	subvalue := value.Response[0]
+	body, err := json.Marshal(subvalue.Body)
+	if err != nil {
+		return queryResponse{}, err
+	}
+	if subvalue.HTTPStatusCode != http.StatusOK {
+		var sfdcErrs sfdc.Errors
+		if err := json.Unmarshal(body, &sfdcErrs); err != nil || len(sfdcErrs) == 0 {
+			return queryResponse{}, errors.Errorf("soql composite query: status %d: %s", subvalue.HTTPStatusCode, string(body))
+		}
+		return queryResponse{}, errors.Wrapf(sfdcErrs, "soql composite query: status %d", subvalue.HTTPStatusCode)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	if r.useNumber {
+		decoder.UseNumber()
+	}
+	var resp queryResponse
+	if err := decoder.Decode(&resp); err != nil {
+		return queryResponse{}, err
+	}
+	return resp, nil
+}
+
+// querySubrequest is the composite subrequest of a query.
+type querySubrequest struct {
+	url    string
+	header http.Header
+}
+
+// newQuerySubrequest returns the subrequest of the query request, with its
+// query options.
+func newQuerySubrequest(request *http.Request) *querySubrequest {
+	subrequest := &querySubrequest{
+		url: request.URL.RequestURI(),
+	}
+	if options := request.Header.Get(sfdc.QueryOptionsHeader); options != "" {
+		subrequest.header = http.Header{sfdc.QueryOptionsHeader: []string{options}}
+	}
+	return subrequest
+}
+
+func (q *querySubrequest) URL() string {
+	return q.url
+}
+
+func (q *querySubrequest) ReferenceID() string {
+	return querySubrequestID
+}
+
+func (q *querySubrequest) Method() string {
+	return http.MethodGet
+}
+
+func (q *querySubrequest) HTTPHeaders() http.Header {
+	return q.header
+}
+
+func (q *querySubrequest) Body() map[string]interface{} {
+	return nil
+}
@@ -0,0 +1,188 @@
+package soql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func longQuerier() *mockQuerier {
+	ids := make([]string, 1000)
+	for idx := range ids {
+		ids[idx] = "'001000000000000AAA'"
+	}
+	return &mockQuerier{stmt: "SELECT Name FROM Account WHERE Id IN (" + strings.Join(ids, ",") + ")"}
+}
+
+func TestResource_QueryContext_tooLong(t *testing.T) {
+	const page = `{
+		"done": false,
+		"totalSize": 2,
+		"nextRecordsUrl": "/services/data/v42.0/query/01gD0000002HU6KIAW-2000",
+		"records": [
+			{"attributes": {"type": "Account"}, "Name": "Acme"}
+		]
+	}`
+	const nextPage = `{
+		"done": true,
+		"totalSize": 2,
+		"records": [
+			{"attributes": {"type": "Account"}, "Name": "Globex"}
+		]
+	}`
+	tests := []struct {
+		name    string
+		querier QueryFormatter
+		reject  int
+	}{
+		{
+			name:    "url too long",
+			querier: longQuerier(),
+		},
+		{
+			name:    "uri too long status",
+			querier: &mockQuerier{stmt: "SELECT Name FROM Account"},
+			reject:  http.StatusRequestURITooLong,
+		},
+		{
+			name:    "header fields too large status",
+			querier: &mockQuerier{stmt: "SELECT Name FROM Account"},
+			reject:  http.StatusRequestHeaderFieldsTooLarge,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			client := mockHTTPClient(func(req *http.Request) *http.Response {
+				requests = append(requests, req.Method+" "+req.URL.Path)
+				var body string
+				switch {
+				case req.Method == http.MethodPost && req.URL.Path == "/composite":
+					var composite struct {
+						CompositeRequest []struct {
+							Method string `json:"method"`
+							URL    string `json:"url"`
+						} `json:"compositeRequest"`
+					}
+					if err := json.NewDecoder(req.Body).Decode(&composite); err != nil {
+						t.Fatalf("composite request error = %v", err)
+					}
+					if len(composite.CompositeRequest) != 1 ||
+						composite.CompositeRequest[0].Method != http.MethodGet ||
+						strings.HasPrefix(composite.CompositeRequest[0].URL, "/query/?q=SELECT+Name+FROM+Account") == false {
+						t.Errorf("composite request = %+v, want the query", composite.CompositeRequest)
+					}
+					body = `{"compositeResponse": [{"body": ` + page + `, "httpHeaders": {}, "httpStatusCode": 200, "referenceId": "query"}]}`
+				case req.URL.Path == "/services/data/v42.0/query/01gD0000002HU6KIAW-2000":
+					body = nextPage
+				case tt.reject != 0:
+					return &http.Response{
+						StatusCode: tt.reject,
+						Body:       io.NopCloser(strings.NewReader("")),
+						Header:     make(http.Header),
+					}
+				default:
+					t.Errorf("unexpected request %s %s", req.Method, req.URL)
+				}
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(body)),
+					Header:     make(http.Header),
+				}
+			})
+			resource, err := NewResource(&mockSessionFormatter{
+				url:    "https://test.salesforce.com",
+				client: client,
+			})
+			if err != nil {
+				t.Fatalf("NewResource() error = %v", err)
+			}
+
+			result, err := resource.QueryContext(context.Background(), tt.querier, false)
+			if err != nil {
+				t.Fatalf("Resource.QueryContext() error = %v", err)
+			}
+			if name := result.Records()[0].Record().Fields()["Name"]; name != "Acme" {
+				t.Errorf("QueryResult.Records() name = %v, want Acme", name)
+			}
+			result, err = result.Next()
+			if err != nil {
+				t.Fatalf("QueryResult.Next() error = %v", err)
+			}
+			if name := result.Records()[0].Record().Fields()["Name"]; name != "Globex" {
+				t.Errorf("QueryResult.Next() name = %v, want Globex", name)
+			}
+
+			want := []string{
+				"POST /composite",
+				"GET /services/data/v42.0/query/01gD0000002HU6KIAW-2000",
+			}
+			if tt.reject != 0 {
+				want = append([]string{"GET /query/"}, want...)
+			}
+			if strings.Join(requests, "\n") != strings.Join(want, "\n") {
+				t.Errorf("requests = %v, want %v", requests, want)
+			}
+		})
+	}
+}
+
+func TestResource_QueryContext_withoutCompositeFallback(t *testing.T) {
+	tests := []struct {
+		name       string
+		querier    QueryFormatter
+		reject     int
+		wantStatus int
+	}{
+		{
+			name:    "url too long",
+			querier: longQuerier(),
+		},
+		{
+			name:       "uri too long status",
+			querier:    &mockQuerier{stmt: "SELECT Name FROM Account"},
+			reject:     http.StatusRequestURITooLong,
+			wantStatus: http.StatusRequestURITooLong,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := mockHTTPClient(func(req *http.Request) *http.Response {
+				if tt.reject == 0 {
+					t.Errorf("unexpected request %s %s", req.Method, req.URL)
+				}
+				return &http.Response{
+					StatusCode: tt.reject,
+					Body:       io.NopCloser(strings.NewReader("")),
+					Header:     make(http.Header),
+				}
+			})
+			resource, err := NewResource(&mockSessionFormatter{
+				url:    "https://test.salesforce.com",
+				client: client,
+			}, WithoutCompositeFallback())
+			if err != nil {
+				t.Fatalf("NewResource() error = %v", err)
+			}
+
+			_, err = resource.QueryContext(context.Background(), tt.querier, false)
+			var tooLong *QueryTooLongError
+			if errors.As(err, &tooLong) == false {
+				t.Fatalf("Resource.QueryContext() error = %v, want a QueryTooLongError", err)
+			}
+			if tooLong.StatusCode != tt.wantStatus {
+				t.Errorf("QueryTooLongError.StatusCode = %d, want %d", tooLong.StatusCode, tt.wantStatus)
+			}
+			if tooLong.Limit != MaxQueryURLLength {
+				t.Errorf("QueryTooLongError.Limit = %d, want %d", tooLong.Limit, MaxQueryURLLength)
+			}
+			if tt.reject == 0 && tooLong.Length <= MaxQueryURLLength {
+				t.Errorf("QueryTooLongError.Length = %d, want more than %d", tooLong.Length, MaxQueryURLLength)
+			}
+		})
+	}
+}
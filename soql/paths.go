@@ -0,0 +1,297 @@
+package soql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/namely/go-sfdc/v3/sobject"
+)
+
+// maxSuggestionDistance is the most edits a name can be from an unknown
+// segment to be suggested.
+const maxSuggestionDistance = 2
+
+// DescribeSource returns the describe of an object, like the sobject
+// resources.
+type DescribeSource interface {
+	DescribeContext(ctx context.Context, sobject string) (sobject.DescribeValue, error)
+}
+
+var _ DescribeSource = (*sobject.Resources)(nil)
+
+// CachedDescribes returns the describe source that describes each object once,
+// so the paths of many queries can be validated without describing the same
+// objects again.  The failed describes are not cached.  It is safe for
+// concurrent use.
+func CachedDescribes(source DescribeSource) DescribeSource {
+	return &describeCache{
+		source:    source,
+		describes: make(map[string]sobject.DescribeValue),
+	}
+}
+
+type describeCache struct {
+	source    DescribeSource
+	mutex     sync.Mutex
+	describes map[string]sobject.DescribeValue
+}
+
+func (c *describeCache) DescribeContext(ctx context.Context, object string) (sobject.DescribeValue, error) {
+	key := strings.ToLower(object)
+	c.mutex.Lock()
+	describe, has := c.describes[key]
+	c.mutex.Unlock()
+	if has {
+		return describe, nil
+	}
+	describe, err := c.source.DescribeContext(ctx, object)
+	if err != nil {
+		return sobject.DescribeValue{}, err
+	}
+	c.mutex.Lock()
+	c.describes[key] = describe
+	c.mutex.Unlock()
+	return describe, nil
+}
+
+// PathIssue is an unknown segment of a field path.
+//
+// Path is the field path, like Account.Owner.Name.
+//
+// Segment is the unknown segment of the path, like Ownr.
+//
+// Object is the object that does not have the segment.
+//
+// Suggestion is the object's field or relationship name that is closest to
+// the segment.  It is empty when no name is within two edits of the segment.
+type PathIssue struct {
+	Path       string
+	Segment    string
+	Object     string
+	Suggestion string
+}
+
+func (i PathIssue) String() string {
+	msg := fmt.Sprintf("%s: %s has no field or relationship %s", i.Path, i.Object, i.Segment)
+	if i.Suggestion != "" {
+		msg += fmt.Sprintf(", did you mean %s?", i.Suggestion)
+	}
+	return msg
+}
+
+// PathError is returned by the query when the path validation finds unknown
+// segments.
+type PathError struct {
+	Issues []PathIssue
+}
+
+func (e *PathError) Error() string {
+	issues := make([]string, len(e.Issues))
+	for idx, issue := range e.Issues {
+		issues[idx] = issue.String()
+	}
+	return "soql paths: " + strings.Join(issues, "; ")
+}
+
+// ValidatePaths will walk each dotted field path from the object through the
+// describes of the source and return the unknown segments.  The segments
+// before the last are the parent relationships, like Owner, or, for the first
+// segment, a child relationship, like Contacts, whose fields are the rest of
+// the path.  The last segment is a field.  The names are matched case
+// insensitively, like SOQL does.  A polymorphic relationship, like the Owner of
+// a Case, can be more than one object, so the rest of its path is not
+// validated.  The fields with an alias are validated without the alias.
+func ValidatePaths(ctx context.Context, source DescribeSource, objectType string, fields []string) ([]PathIssue, error) {
+	if source == nil {
+		return nil, errors.New("soql paths: describe source can not be nil")
+	}
+	if objectType == "" {
+		return nil, errors.New("soql paths: object type can not be empty")
+	}
+	describe, err := source.DescribeContext(ctx, objectType)
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []PathIssue
+	for _, field := range fields {
+		path := strings.Fields(field)
+		if len(path) == 0 {
+			continue
+		}
+		issue, err := validatePath(ctx, source, describe, path[0])
+		if err != nil {
+			return nil, err
+		}
+		if issue != nil {
+			issues = append(issues, *issue)
+		}
+	}
+	return issues, nil
+}
+
+func validatePath(ctx context.Context, source DescribeSource, describe sobject.DescribeValue, path string) (*PathIssue, error) {
+	segments := strings.Split(path, ".")
+	for idx, segment := range segments {
+		if idx == len(segments)-1 {
+			if describeField(describe, segment) != nil {
+				return nil, nil
+			}
+			return newPathIssue(path, segment, describe, fieldNames(describe)), nil
+		}
+
+		var next string
+		if field := parentRelationship(describe, segment); field != nil {
+			if len(field.ReferenceTo) != 1 {
+				return nil, nil
+			}
+			next = field.ReferenceTo[0]
+		} else if relationship, has := describe.ChildRelationshipByName(segment); has && idx == 0 {
+			next = relationship.ChildSObject
+		} else {
+			candidates := relationshipNames(describe)
+			if idx == 0 {
+				for _, relationship := range describe.QueryableChildRelationships() {
+					candidates = append(candidates, relationship.RelationshipName)
+				}
+			}
+			return newPathIssue(path, segment, describe, candidates), nil
+		}
+
+		var err error
+		describe, err = source.DescribeContext(ctx, next)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+func newPathIssue(path, segment string, describe sobject.DescribeValue, candidates []string) *PathIssue {
+	return &PathIssue{
+		Path:       path,
+		Segment:    segment,
+		Object:     describe.Name,
+		Suggestion: suggestName(segment, candidates),
+	}
+}
+
+func describeField(describe sobject.DescribeValue, name string) *sobject.Field {
+	for idx := range describe.Fields {
+		if strings.EqualFold(describe.Fields[idx].Name, name) {
+			return &describe.Fields[idx]
+		}
+	}
+	return nil
+}
+
+func parentRelationship(describe sobject.DescribeValue, name string) *sobject.Field {
+	for idx := range describe.Fields {
+		field := &describe.Fields[idx]
+		if field.RelationshipName != "" && strings.EqualFold(field.RelationshipName, name) {
+			return field
+		}
+	}
+	return nil
+}
+
+func fieldNames(describe sobject.DescribeValue) []string {
+	names := make([]string, len(describe.Fields))
+	for idx, field := range describe.Fields {
+		names[idx] = field.Name
+	}
+	return names
+}
+
+func relationshipNames(describe sobject.DescribeValue) []string {
+	var names []string
+	for _, field := range describe.Fields {
+		if field.RelationshipName != "" {
+			names = append(names, field.RelationshipName)
+		}
+	}
+	return names
+}
+
+// suggestName returns the candidate with the fewest edits from the name, if it
+// is within the maximum distance.  The first of the closest candidates is
+// returned.
+func suggestName(name string, candidates []string) string {
+	suggestion := ""
+	best := maxSuggestionDistance + 1
+	for _, candidate := range candidates {
+		distance := levenshtein(strings.ToLower(name), strings.ToLower(candidate))
+		if distance < best {
+			suggestion = candidate
+			best = distance
+		}
+	}
+	return suggestion
+}
+
+// levenshtein returns the number of single character insertions, deletions
+// and substitutions that change a into b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = previous[j-1] + cost
+			if previous[j]+1 < current[j] {
+				current[j] = previous[j] + 1
+			}
+			if current[j-1]+1 < current[j] {
+				current[j] = current[j-1] + 1
+			}
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
+// paths returns the field paths of the query, with the fields of the child
+// queries prefixed by their child relationship, like Contacts.LastName.  The
+// child queries that are not built by NewQuery are not included.
+func (b *Query) paths() []string {
+	paths := append([]string(nil), b.fieldList...)
+	paths = append(paths, fieldPaths(b.fields)...)
+	paths = append(paths, b.groupBy...)
+	paths = append(paths, fieldPaths(b.groupByFields)...)
+	for _, query := range b.subQuery {
+		sub, ok := query.(*Query)
+		if ok == false {
+			continue
+		}
+		for _, field := range sub.fieldList {
+			paths = append(paths, sub.objectType+"."+field)
+		}
+		for _, name := range fieldPaths(sub.fields) {
+			paths = append(paths, sub.objectType+"."+name)
+		}
+	}
+	return paths
+}
+
+// fieldPaths returns the names of the typed fields, without their function
+// and alias, so they are validated like the field list.
+func fieldPaths(fields []Field) []string {
+	var paths []string
+	for _, field := range fields {
+		if field.Name != "" {
+			paths = append(paths, field.Name)
+		}
+	}
+	return paths
+}
@@ -0,0 +1,212 @@
+package soql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/namely/go-sfdc/v3/sobject"
+)
+
+type mockDescribeSource struct {
+	describes map[string]sobject.DescribeValue
+	calls     map[string]int
+}
+
+func (mock *mockDescribeSource) DescribeContext(ctx context.Context, object string) (sobject.DescribeValue, error) {
+	if mock.calls == nil {
+		mock.calls = make(map[string]int)
+	}
+	mock.calls[object]++
+	describe, has := mock.describes[object]
+	if has == false {
+		return sobject.DescribeValue{}, fmt.Errorf("describe %s: not found", object)
+	}
+	return describe, nil
+}
+
+func pathDescribes() *mockDescribeSource {
+	return &mockDescribeSource{
+		describes: map[string]sobject.DescribeValue{
+			"Contact": {
+				Name: "Contact",
+				Fields: []sobject.Field{
+					{Name: "Id"},
+					{Name: "LastName"},
+					{Name: "AccountId", Type: "reference", RelationshipName: "Account", ReferenceTo: []string{"Account"}},
+				},
+			},
+			"Account": {
+				Name: "Account",
+				Fields: []sobject.Field{
+					{Name: "Id"},
+					{Name: "Name"},
+					{Name: "OwnerId", Type: "reference", RelationshipName: "Owner", ReferenceTo: []string{"User"}},
+					{Name: "LastModifiedById", Type: "reference", RelationshipName: "LastModifiedBy", ReferenceTo: []string{"User"}},
+				},
+				ChildRelationships: []sobject.ChildRelationship{
+					{ChildSObject: "Contact", Field: "AccountId", RelationshipName: "Contacts"},
+				},
+			},
+			"Case": {
+				Name: "Case",
+				Fields: []sobject.Field{
+					{Name: "Id"},
+					{Name: "OwnerId", Type: "reference", RelationshipName: "Owner", ReferenceTo: []string{"Group", "User"}},
+				},
+			},
+			"User": {
+				Name: "User",
+				Fields: []sobject.Field{
+					{Name: "Id"},
+					{Name: "Name"},
+					{Name: "Email"},
+				},
+			},
+		},
+	}
+}
+
+func TestValidatePaths(t *testing.T) {
+	tests := []struct {
+		name       string
+		objectType string
+		fields     []string
+		want       []PathIssue
+		wantErr    bool
+	}{
+		{
+			name:       "multi-hop paths",
+			objectType: "Contact",
+			fields:     []string{"Id", "lastname", "Account.Name", "Account.Owner.Email", "Account.Owner.Name ownerName"},
+		},
+		{
+			name:       "typo in the middle segment",
+			objectType: "Contact",
+			fields:     []string{"Account.Ownr.Name"},
+			want: []PathIssue{
+				{Path: "Account.Ownr.Name", Segment: "Ownr", Object: "Account", Suggestion: "Owner"},
+			},
+		},
+		{
+			name:       "typo in the field",
+			objectType: "Contact",
+			fields:     []string{"Account.Owner.Emial", "Account.Owner.Phone"},
+			want: []PathIssue{
+				{Path: "Account.Owner.Emial", Segment: "Emial", Object: "User", Suggestion: "Email"},
+				{Path: "Account.Owner.Phone", Segment: "Phone", Object: "User"},
+			},
+		},
+		{
+			name:       "child relationship",
+			objectType: "Account",
+			fields:     []string{"Contacts.LastName", "Contacts.Account.Name", "Contact.LastName"},
+			want: []PathIssue{
+				{Path: "Contact.LastName", Segment: "Contact", Object: "Account", Suggestion: "Contacts"},
+			},
+		},
+		{
+			name:       "polymorphic relationship",
+			objectType: "Case",
+			fields:     []string{"Owner.Name", "Owner.Anything"},
+		},
+		{
+			name:       "unsafe fields",
+			objectType: "Account",
+			fields: (&Query{fields: []Field{UnsafeField("toLabel(Name)"), UnsafeField("COUNT()")},
+				groupByFields: []Field{UnsafeField("CALENDAR_YEAR(CreatedDate)")}}).paths(),
+		},
+		{
+			name:       "typed fields",
+			objectType: "Contact",
+			fields: (&Query{fields: []Field{
+				{Name: "Account.Owner.Emial", Function: "MAX", Alias: "email"},
+				{Function: "COUNT"},
+			}}).paths(),
+			want: []PathIssue{
+				{Path: "Account.Owner.Emial", Segment: "Emial", Object: "User", Suggestion: "Email"},
+			},
+		},
+		{
+			name:       "describe error",
+			objectType: "Lead",
+			fields:     []string{"Id"},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ValidatePaths(context.Background(), pathDescribes(), tt.objectType, tt.fields)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidatePaths() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidatePaths() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCachedDescribes(t *testing.T) {
+	source := pathDescribes()
+	cached := CachedDescribes(source)
+	for idx := 0; idx < 2; idx++ {
+		if _, err := ValidatePaths(context.Background(), cached, "Contact", []string{"Account.Owner.Name", "Account.LastModifiedBy.Name"}); err != nil {
+			t.Fatalf("ValidatePaths() error = %v", err)
+		}
+	}
+	want := map[string]int{"Contact": 1, "Account": 1, "User": 1}
+	if !reflect.DeepEqual(source.calls, want) {
+		t.Errorf("DescribeContext() calls = %v, want %v", source.calls, want)
+	}
+}
+
+func TestResource_QueryContext_PathValidation(t *testing.T) {
+	var requests int
+	resource := &Resource{
+		session: &mockSessionFormatter{
+			url: "https://test.salesforce.com/services/data/v42.0",
+			client: mockHTTPClient(func(req *http.Request) *http.Response {
+				requests++
+				return nil
+			}),
+		},
+	}
+	child, err := NewQuery(QueryInput{
+		ObjectType: "Contacts",
+		FieldList:  []string{"LastNme"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	query, err := NewQuery(QueryInput{
+		ObjectType: "Account",
+		FieldList:  []string{"Name", "Owner.Name"},
+		SubQuery:   []QueryFormatter{child},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = resource.QueryContext(context.Background(), query, false, WithPathValidation(pathDescribes()))
+	var pathErr *PathError
+	if errors.As(err, &pathErr) == false {
+		t.Fatalf("Resource.QueryContext() error = %v, want a PathError", err)
+	}
+	want := []PathIssue{
+		{Path: "Contacts.LastNme", Segment: "LastNme", Object: "Contact", Suggestion: "LastName"},
+	}
+	if !reflect.DeepEqual(pathErr.Issues, want) {
+		t.Errorf("PathError.Issues = %+v, want %+v", pathErr.Issues, want)
+	}
+	if requests != 0 {
+		t.Errorf("Resource.QueryContext() sent %d requests, want none", requests)
+	}
+
+	if _, err := resource.QueryContext(context.Background(), &mockQuerier{stmt: "SELECT Id FROM Account"}, false, WithPathValidation(pathDescribes())); err == nil {
+		t.Error("Resource.QueryContext() expected an error for a querier not built by NewQuery")
+	}
+}
@@ -1,68 +1,213 @@
 package soql
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/url"
+	"strings"
 
 	"github.com/namely/go-sfdc/v3"
 	"github.com/namely/go-sfdc/v3/session"
 	"github.com/pkg/errors"
 )
 
+const (
+	queryEndpoint    = "/query"
+	queryAllEndpoint = "/queryAll"
+	toolingEndpoint  = "/tooling"
+)
+
 // Resource is the structure for the Salesforce
 // SOQL API resource.
 type Resource struct {
-	session session.ServiceFormatter
+	session             session.ServiceFormatter
+	version             int
+	useNumber           bool
+	noCompositeFallback bool
+	tooling             bool
+}
+
+// ResourceOption is an option for the SOQL resource.
+type ResourceOption func(*Resource)
+
+// WithUseNumber will decode the numbers of the query responses as json.Number,
+// instead of float64, so large integers, like 18 digit external IDs, and high
+// precision decimals, like currencies, are not rounded.  The records' number
+// fields are json.Number values, which can be read with the record's
+// Int64Value and DecimalValue.
+func WithUseNumber() ResourceOption {
+	return func(r *Resource) {
+		r.useNumber = true
+	}
+}
+
+// WithTooling will query the Tooling API, like the ApexClass and
+// EntityDefinition objects, with the tooling/query and tooling/queryAll
+// endpoints.  The next records of a result are queried with the Tooling API
+// too.
+func WithTooling() ResourceOption {
+	return func(r *Resource) {
+		r.tooling = true
+	}
 }
 
 // NewResource forms the Salesforce SOQL resource. The
 // session formatter is required to form the proper URLs and authorization
 // header.
-func NewResource(session session.ServiceFormatter) (*Resource, error) {
-	if session == nil {
+func NewResource(formatter session.ServiceFormatter, options ...ResourceOption) (*Resource, error) {
+	if formatter == nil {
 		return nil, errors.New("soql: session can not be nil")
 	}
 
-	err := session.Refresh()
+	err := session.RefreshResource(formatter)
 	if err != nil {
 		return nil, errors.Wrap(err, "session refresh")
 	}
 
-	return &Resource{
-		session: session,
-	}, nil
+	resource := &Resource{
+		session: formatter,
+	}
+	for _, option := range options {
+		option(resource)
+	}
+	return resource, nil
+}
+
+// WithQueryBatchSize will ask for the query results in batches of the size,
+// from sfdc.MinQueryBatchSize to sfdc.MaxQueryBatchSize records, with the
+// Sforce-Query-Options header.  The next records of the result are in batches
+// of the same size.
+func WithQueryBatchSize(size int) QueryOption {
+	return func(o *queryOptions) {
+		o.batchSize = size
+	}
 }
 
 // Query will call out to the Salesforce org for a SOQL.  The results will
 // be the result of the query.  The all parameter is for querying all records,
 // which include deleted records that are in the recycle bin.
 func (r *Resource) Query(querier QueryFormatter, all bool) (*QueryResult, error) {
+	return r.QueryContext(context.Background(), querier, all)
+}
+
+// QueryContext will call out to the Salesforce org for a SOQL using the context.
+// If the context has an API version, the next records of the result are queried
+// with the same version.
+func (r *Resource) QueryContext(ctx context.Context, querier QueryFormatter, all bool, options ...QueryOption) (_ *QueryResult, err error) {
 	if querier == nil {
 		return nil, errors.New("soql resource query: querier can not be nil")
 	}
+	var opts queryOptions
+	for _, option := range options {
+		option(&opts)
+	}
+	var queryOptionsHeader string
+	if opts.batchSize != 0 {
+		header, err := sfdc.QueryBatchSize(opts.batchSize)
+		if err != nil {
+			return nil, errors.Wrap(err, "soql resource query")
+		}
+		queryOptionsHeader = header
+	}
+
+	ctx, end := session.StartSpan(ctx, r.session, "soql.query", func() map[string]interface{} {
+		return map[string]interface{}{
+			"query.all": all,
+		}
+	})
+	defer func() { end(err) }()
 
-	request, err := r.queryRequest(querier, all)
+	if opts.paths != nil {
+		query, ok := querier.(*Query)
+		if ok == false {
+			return nil, errors.Errorf("soql resource query: path validation needs a query built by NewQuery, not %T", querier)
+		}
+		issues, err := ValidatePaths(ctx, opts.paths, query.objectType, query.paths())
+		if err != nil {
+			return nil, err
+		}
+		if len(issues) > 0 {
+			return nil, &PathError{Issues: issues}
+		}
+	}
+
+	var access map[string]FieldAccess
+	if opts.fieldAccess {
+		columns, err := r.ColumnMetadata(ctx, querier)
+		if err != nil {
+			return nil, err
+		}
+		access = NewFieldAccess(columns, opts.describe)
+	}
+
+	request, err := r.queryRequest(ctx, querier, all)
 	if err != nil {
 		return nil, err
 	}
+	if queryOptionsHeader != "" {
+		request.Header.Set(sfdc.QueryOptionsHeader, queryOptionsHeader)
+	}
 
-	response, err := r.queryResponse(request)
+	response, err := r.query(ctx, request)
 	if err != nil {
 		return nil, err
 	}
 
-	result, err := newQueryResult(response, r)
+	resource := r
+	if version, ok := sfdc.APIVersion(ctx); ok {
+		resource = &Resource{
+			session:             r.session,
+			version:             version,
+			useNumber:           r.useNumber,
+			noCompositeFallback: r.noCompositeFallback,
+			tooling:             r.tooling,
+		}
+	}
+	result, err := newQueryResult(response, resource)
 	if err != nil {
 		return nil, err
 	}
+	result.all = all
+	result.setFieldAccess(access)
 
 	return result, nil
 }
 
-func (r *Resource) next(recordURL string) (*QueryResult, error) {
-	queryURL := r.session.InstanceURL() + recordURL
-	request, err := http.NewRequest(http.MethodGet, queryURL, nil)
+// QueryAll will call out to the Salesforce org for a SOQL using the queryAll
+// endpoint.  The results will include deleted and archived records for every
+// page of the result.
+func (r *Resource) QueryAll(querier QueryFormatter) (*QueryResult, error) {
+	return r.Query(querier, true)
+}
+
+func (r *Resource) next(recordURL string, all bool) (_ *QueryResult, err error) {
+	ctx := context.Background()
+	if r.version != 0 {
+		ctx = sfdc.WithAPIVersion(ctx, r.version)
+	}
+	ctx, end := session.StartSpan(ctx, r.session, "soql.query.next", func() map[string]interface{} {
+		return map[string]interface{}{
+			"query.all": all,
+		}
+	})
+	defer func() { end(err) }()
+
+	if r.tooling && strings.Contains(recordURL, toolingEndpoint+"/") == false {
+		recordURL = strings.Replace(recordURL, queryEndpoint+"/", toolingEndpoint+queryEndpoint+"/", 1)
+	}
+	if all {
+		recordURL = strings.Replace(recordURL, queryEndpoint+"/", queryAllEndpoint+"/", 1)
+	}
+	queryURL, err := sfdc.RebaseServicePath(r.session.InstanceURL(), recordURL)
+	if err != nil {
+		return nil, err
+	}
+	queryURL, err = session.ServicePathContext(ctx, r.session, queryURL)
+	if err != nil {
+		return nil, err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, queryURL, nil)
 
 	if err != nil {
 		return nil, err
@@ -80,27 +225,43 @@ func (r *Resource) next(recordURL string) (*QueryResult, error) {
 	if err != nil {
 		return nil, err
 	}
+	result.all = all
 
 	return result, nil
 }
-func (r *Resource) queryRequest(querier QueryFormatter, all bool) (*http.Request, error) {
+
+// endpoint returns the endpoint of the query, under the Tooling API if the
+// resource queries it.
+func (r *Resource) endpoint(all bool) string {
+	endpoint := queryEndpoint
+	if all {
+		endpoint = queryAllEndpoint
+	}
+	if r.tooling {
+		return toolingEndpoint + endpoint
+	}
+	return endpoint
+}
+
+func (r *Resource) queryRequest(ctx context.Context, querier QueryFormatter, all bool) (*http.Request, error) {
 	query, err := querier.Format()
 	if err != nil {
 		return nil, err
 	}
 
-	endpoint := "/query"
-	if all {
-		endpoint += "All"
-	}
+	endpoint := r.endpoint(all)
 
-	queryURL := r.session.ServiceURL() + endpoint + "/"
+	serviceURL, err := session.ServiceURLContext(ctx, r.session)
+	if err != nil {
+		return nil, err
+	}
+	queryURL := serviceURL + endpoint + "/"
 
 	form := url.Values{}
 	form.Add("q", query)
 	queryURL += "?" + form.Encode()
 
-	request, err := http.NewRequest(http.MethodGet, queryURL, nil)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, queryURL, nil)
 
 	if err != nil {
 		return nil, err
@@ -119,9 +280,20 @@ func (r *Resource) queryResponse(request *http.Request) (queryResponse, error) {
 	}
 
 	decoder := json.NewDecoder(response.Body)
-	defer response.Body.Close()
+	defer sfdc.CloseResponse(response)
+	if r.useNumber {
+		decoder.UseNumber()
+	}
 
-	if response.StatusCode != http.StatusOK {
+	switch response.StatusCode {
+	case http.StatusOK:
+	case http.StatusRequestURITooLong, http.StatusRequestHeaderFieldsTooLarge:
+		return queryResponse{}, &QueryTooLongError{
+			Length:     len(request.URL.String()),
+			Limit:      MaxQueryURLLength,
+			StatusCode: response.StatusCode,
+		}
+	default:
 		return queryResponse{}, sfdc.HandleError(response)
 	}
 
@@ -1,12 +1,15 @@
 package soql
 
 import (
-	"io/ioutil"
+	"context"
+	"encoding/json"
+	"io"
 	"net/http"
 	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/namely/go-sfdc/v3"
 	"github.com/namely/go-sfdc/v3/session"
 )
 
@@ -102,7 +105,7 @@ func TestResource_Query(t *testing.T) {
 						return &http.Response{
 							StatusCode: 500,
 							Status:     "Some Status",
-							Body:       ioutil.NopCloser(strings.NewReader("Error")),
+							Body:       io.NopCloser(strings.NewReader("Error")),
 							Header:     make(http.Header),
 						}
 					}),
@@ -125,7 +128,7 @@ func TestResource_Query(t *testing.T) {
 
 						return &http.Response{
 							StatusCode: 200,
-							Body:       ioutil.NopCloser(strings.NewReader(resp)),
+							Body:       io.NopCloser(strings.NewReader(resp)),
 							Header:     make(http.Header),
 						}
 					}),
@@ -147,7 +150,7 @@ func TestResource_Query(t *testing.T) {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "Some Status",
-								Body:       ioutil.NopCloser(strings.NewReader("Error")),
+								Body:       io.NopCloser(strings.NewReader("Error")),
 								Header:     make(http.Header),
 							}
 						}
@@ -178,7 +181,7 @@ func TestResource_Query(t *testing.T) {
 
 						return &http.Response{
 							StatusCode: 200,
-							Body:       ioutil.NopCloser(strings.NewReader(resp)),
+							Body:       io.NopCloser(strings.NewReader(resp)),
 							Header:     make(http.Header),
 						}
 					}),
@@ -256,6 +259,7 @@ func TestResource_next(t *testing.T) {
 	}
 	type args struct {
 		recordURL string
+		all       bool
 	}
 	tests := []struct {
 		name    string
@@ -274,7 +278,7 @@ func TestResource_next(t *testing.T) {
 							return &http.Response{
 								StatusCode: 500,
 								Status:     "Some Status",
-								Body:       ioutil.NopCloser(strings.NewReader("Error")),
+								Body:       io.NopCloser(strings.NewReader("Error")),
 								Header:     make(http.Header),
 							}
 						}
@@ -305,7 +309,7 @@ func TestResource_next(t *testing.T) {
 
 						return &http.Response{
 							StatusCode: 200,
-							Body:       ioutil.NopCloser(strings.NewReader(resp)),
+							Body:       io.NopCloser(strings.NewReader(resp)),
 							Header:     make(http.Header),
 						}
 					}),
@@ -360,7 +364,7 @@ func TestResource_next(t *testing.T) {
 			r := &Resource{
 				session: tt.fields.session,
 			}
-			got, err := r.next(tt.args.recordURL)
+			got, err := r.next(tt.args.recordURL, tt.args.all)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Resource.next() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -374,3 +378,134 @@ func TestResource_next(t *testing.T) {
 		})
 	}
 }
+
+func TestResource_QueryContext_UseNumber(t *testing.T) {
+	const body = `{
+		"done": false,
+		"totalSize": 2,
+		"nextRecordsUrl": "/services/data/v42.0/query/01gD0000002HU6KIAW-2000",
+		"records": [
+			{
+				"attributes": {"type": "Account", "url": "/services/data/v42.0/sobjects/Account/001A"},
+				"ExternalId__c": 123456789012345678,
+				"AnnualRevenue": 12345678901234.5678
+			}
+		]
+	}`
+	client := mockHTTPClient(func(req *http.Request) *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Header:     make(http.Header),
+		}
+	})
+	session := &mockSessionFormatter{
+		url:    "https://test.salesforce.com/services/data/v42.0",
+		client: client,
+	}
+	querier := &mockQuerier{stmt: "SELECT ExternalId__c, AnnualRevenue FROM Account"}
+	tests := []struct {
+		name        string
+		options     []ResourceOption
+		wantID      int64
+		wantRevenue string
+	}{
+		{
+			name:        "use number",
+			options:     []ResourceOption{WithUseNumber()},
+			wantID:      123456789012345678,
+			wantRevenue: "12345678901234.5678",
+		},
+		{
+			// the default float64 decoding rounds the values
+			name:        "float64",
+			wantID:      123456789012345680,
+			wantRevenue: "12345678901234.568",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resource, err := NewResource(session, tt.options...)
+			if err != nil {
+				t.Fatalf("NewResource() error = %v", err)
+			}
+			result, err := resource.QueryContext(sfdc.WithAPIVersion(context.Background(), 42), querier, false)
+			if err != nil {
+				t.Fatalf("Resource.QueryContext() error = %v", err)
+			}
+			for _, page := range []string{"first", "next"} {
+				if page == "next" {
+					if result, err = result.Next(); err != nil {
+						t.Fatalf("QueryResult.Next() error = %v", err)
+					}
+				}
+				record := result.Records()[0]
+				id, err := record.Int64Value("ExternalId__c")
+				if err != nil || id != tt.wantID {
+					t.Errorf("%s QueryRecord.Int64Value() = %d, %v, want %d", page, id, err, tt.wantID)
+				}
+				revenue, err := record.DecimalValue("AnnualRevenue")
+				if err != nil || revenue != tt.wantRevenue {
+					t.Errorf("%s QueryRecord.DecimalValue() = %s, %v, want %s", page, revenue, err, tt.wantRevenue)
+				}
+			}
+			if result.TotalSize() != 2 {
+				t.Errorf("QueryResult.TotalSize() = %d, want 2", result.TotalSize())
+			}
+		})
+	}
+}
+
+func TestResource_QueryContext_batchSize(t *testing.T) {
+	const page = `{"done": true, "totalSize": 1, "records": [{"attributes": {"type": "Account"}, "Name": "Acme"}]}`
+	var header, subrequestHeader string
+	client := mockHTTPClient(func(req *http.Request) *http.Response {
+		body := page
+		if req.URL.Path == "/composite" {
+			var composite struct {
+				CompositeRequest []struct {
+					HTTPHeaders map[string]string `json:"httpHeaders"`
+				} `json:"compositeRequest"`
+			}
+			if err := json.NewDecoder(req.Body).Decode(&composite); err != nil {
+				t.Fatalf("composite request error = %v", err)
+			}
+			subrequestHeader = composite.CompositeRequest[0].HTTPHeaders["Sforce-Query-Options"]
+			body = `{"compositeResponse": [{"body": ` + page + `, "httpHeaders": {}, "httpStatusCode": 200, "referenceId": "query"}]}`
+		} else {
+			header = req.Header.Get("Sforce-Query-Options")
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Header:     make(http.Header),
+		}
+	})
+	resource, err := NewResource(&mockSessionFormatter{
+		url:    "https://test.salesforce.com",
+		client: client,
+	})
+	if err != nil {
+		t.Fatalf("NewResource() error = %v", err)
+	}
+
+	_, err = resource.QueryContext(context.Background(), &mockQuerier{stmt: "SELECT Name FROM Account"}, false, WithQueryBatchSize(500))
+	if err != nil {
+		t.Fatalf("Resource.QueryContext() error = %v", err)
+	}
+	if header != "batchSize=500" {
+		t.Errorf("Resource.QueryContext() Sforce-Query-Options = %q, want batchSize=500", header)
+	}
+
+	_, err = resource.QueryContext(context.Background(), longQuerier(), false, WithQueryBatchSize(2000))
+	if err != nil {
+		t.Fatalf("Resource.QueryContext() error = %v", err)
+	}
+	if subrequestHeader != "batchSize=2000" {
+		t.Errorf("Resource.QueryContext() subrequest Sforce-Query-Options = %q, want batchSize=2000", subrequestHeader)
+	}
+
+	if _, err := resource.QueryContext(context.Background(), &mockQuerier{stmt: "SELECT Name FROM Account"}, false, WithQueryBatchSize(100)); err == nil {
+		t.Error("Resource.QueryContext() error = nil, want an error for the batch size")
+	}
+}
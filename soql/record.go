@@ -8,6 +8,7 @@ import "github.com/namely/go-sfdc/v3"
 type QueryRecord struct {
 	record     *sfdc.Record
 	subresults map[string]*QueryResult
+	access     map[string]FieldAccess
 }
 
 func newQueryRecord(jsonMap map[string]interface{}, resource *Resource) (*QueryRecord, error) {
@@ -43,6 +44,37 @@ func (rec *QueryRecord) Record() *sfdc.Record {
 	return rec.record
 }
 
+// Int64Value returns the field's integer value, like the record's Int64Value.
+func (rec *QueryRecord) Int64Value(field string) (int64, error) {
+	return rec.record.Int64Value(field)
+}
+
+// DecimalValue returns the field's number as a decimal string, like the
+// record's DecimalValue.
+func (rec *QueryRecord) DecimalValue(field string) (string, error) {
+	return rec.record.DecimalValue(field)
+}
+
+// Address returns the value of the address compound field, like the record's
+// Address.
+func (rec *QueryRecord) Address(field string) (sfdc.Address, bool) {
+	return rec.record.Address(field)
+}
+
+// Geolocation returns the value of the geolocation compound field, like the
+// record's Geolocation.
+func (rec *QueryRecord) Geolocation(field string) (sfdc.Geolocation, bool) {
+	return rec.record.Geolocation(field)
+}
+
+// Inaccessible will indicate if the field's value can not be trusted, because
+// the field is hidden or masked for the user, so a null may not be a real null.
+// It is false unless the query was sent with WithFieldAccess.
+func (rec *QueryRecord) Inaccessible(field string) bool {
+	fa, has := fieldAccess(rec.access, field)
+	return has && fa.Inaccessible
+}
+
 // Subresults returns all of the inner query results.
 func (rec *QueryRecord) Subresults() map[string]*QueryResult {
 	return rec.subresults
@@ -1,6 +1,7 @@
 package soql
 
 import (
+	"encoding/json"
 	"errors"
 )
 
@@ -23,9 +24,16 @@ func newQueryResponseJSON(jsonMap map[string]interface{}) (queryResponse, error)
 		return queryResponse{}, errors.New("query response: done is not present")
 	}
 	if ts, has := jsonMap["totalSize"]; has {
-		if totalSize, ok := ts.(float64); ok {
+		switch totalSize := ts.(type) {
+		case float64:
 			response.TotalSize = int(totalSize)
-		} else {
+		case json.Number:
+			size, err := totalSize.Int64()
+			if err != nil {
+				return queryResponse{}, errors.New("query response: totalSize is not an integer")
+			}
+			response.TotalSize = int(size)
+		default:
 			return queryResponse{}, errors.New("query response: totalSize is not a number")
 		}
 	} else {
@@ -9,6 +9,8 @@ type QueryResult struct {
 	response queryResponse
 	records  []*QueryRecord
 	resource *Resource
+	all      bool
+	access   map[string]FieldAccess
 }
 
 func newQueryResult(response queryResponse, resource *Resource) (*QueryResult, error) {
@@ -45,15 +47,41 @@ func (result *QueryResult) MoreRecords() bool {
 	return result.response.NextRecordsURL != ""
 }
 
+// All will indicate if the result was queried with the queryAll endpoint,
+// which includes the deleted and archived records.
+func (result *QueryResult) All() bool {
+	return result.all
+}
+
 // Records returns the records from the query request.
 func (result *QueryResult) Records() []*QueryRecord {
 	return result.records
 }
 
-// Next will query the next set of records.
+// FieldAccess returns the access of the queried fields, keyed by the field
+// names.  It is nil unless the query was sent with WithFieldAccess.
+func (result *QueryResult) FieldAccess() map[string]FieldAccess {
+	return result.access
+}
+
+// Next will query the next set of records.  If the result was queried
+// with the queryAll endpoint, the next set of records will be as well.
+// The next records have the same field access as the result.
 func (result *QueryResult) Next() (*QueryResult, error) {
 	if result.MoreRecords() == false {
 		return nil, errors.New("soql query result: no more records to query")
 	}
-	return result.resource.next(result.response.NextRecordsURL)
+	next, err := result.resource.next(result.response.NextRecordsURL, result.all)
+	if err != nil {
+		return nil, err
+	}
+	next.setFieldAccess(result.access)
+	return next, nil
+}
+
+func (result *QueryResult) setFieldAccess(access map[string]FieldAccess) {
+	result.access = access
+	for _, record := range result.records {
+		record.access = access
+	}
 }
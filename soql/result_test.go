@@ -1,7 +1,7 @@
 package soql
 
 import (
-	"io/ioutil"
+	"io"
 	"net/http"
 	"reflect"
 	"strings"
@@ -308,7 +308,7 @@ func TestQueryResult_Next(t *testing.T) {
 								return &http.Response{
 									StatusCode: 500,
 									Status:     "Some Status",
-									Body:       ioutil.NopCloser(strings.NewReader("Error")),
+									Body:       io.NopCloser(strings.NewReader("Error")),
 									Header:     make(http.Header),
 								}
 							}
@@ -339,7 +339,7 @@ func TestQueryResult_Next(t *testing.T) {
 
 							return &http.Response{
 								StatusCode: 200,
-								Body:       ioutil.NopCloser(strings.NewReader(resp)),
+								Body:       io.NopCloser(strings.NewReader(resp)),
 								Header:     make(http.Header),
 							}
 						}),
@@ -408,3 +408,78 @@ func TestQueryResult_Next(t *testing.T) {
 		})
 	}
 }
+
+func TestQueryResult_NextAll(t *testing.T) {
+	tests := []struct {
+		name        string
+		all         bool
+		nextURL     string
+		wantQuery   string
+		wantNextURL string
+	}{
+		{
+			name:        "query all with query locator",
+			all:         true,
+			nextURL:     "/services/data/v20.0/query/01gD0000002HU6KIAW-2000",
+			wantQuery:   "/queryAll/",
+			wantNextURL: "/services/data/v20.0/queryAll/01gD0000002HU6KIAW-2000",
+		},
+		{
+			name:        "query all with query all locator",
+			all:         true,
+			nextURL:     "/services/data/v20.0/queryAll/01gD0000002HU6KIAW-2000",
+			wantQuery:   "/queryAll/",
+			wantNextURL: "/services/data/v20.0/queryAll/01gD0000002HU6KIAW-2000",
+		},
+		{
+			name:        "query",
+			all:         false,
+			nextURL:     "/services/data/v20.0/query/01gD0000002HU6KIAW-2000",
+			wantQuery:   "/query/",
+			wantNextURL: "/services/data/v20.0/query/01gD0000002HU6KIAW-2000",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var paths []string
+			resource := &Resource{
+				session: &mockSessionFormatter{
+					url: "https://test.salesforce.com",
+					client: mockHTTPClient(func(req *http.Request) *http.Response {
+						paths = append(paths, req.URL.Path)
+						resp := `{"done": true, "totalSize": 1, "records": []}`
+						if len(paths) == 1 {
+							resp = `{"done": false, "totalSize": 1, "nextRecordsUrl": "` + tt.nextURL + `", "records": []}`
+						}
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Body:       io.NopCloser(strings.NewReader(resp)),
+							Header:     make(http.Header),
+						}
+					}),
+				},
+			}
+			result, err := resource.Query(&mockQuerier{stmt: "SELECT Id FROM Account"}, tt.all)
+			if err != nil {
+				t.Fatalf("Resource.Query() error = %v", err)
+			}
+			if result.All() != tt.all {
+				t.Errorf("QueryResult.All() = %v, want %v", result.All(), tt.all)
+			}
+			next, err := result.Next()
+			if err != nil {
+				t.Fatalf("QueryResult.Next() error = %v", err)
+			}
+			if next.All() != tt.all {
+				t.Errorf("QueryResult.Next().All() = %v, want %v", next.All(), tt.all)
+			}
+			want := []string{
+				tt.wantQuery,
+				tt.wantNextURL,
+			}
+			if !reflect.DeepEqual(paths, want) {
+				t.Errorf("request paths = %v, want %v", paths, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package soql
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestResource_QueryContext_tooling(t *testing.T) {
+	var paths []string
+	var next string
+	client := mockHTTPClient(func(req *http.Request) *http.Response {
+		paths = append(paths, req.URL.Path)
+		body := `{"done": true, "totalSize": 2, "records": [{"attributes": {"type": "ApexClass"}, "Name": "Second"}]}`
+		if strings.HasSuffix(req.URL.Path, "/") {
+			body = `{
+				"done": false,
+				"totalSize": 2,
+				"nextRecordsUrl": "` + next + `",
+				"records": [{"attributes": {"type": "ApexClass"}, "Name": "First"}]
+			}`
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Header:     make(http.Header),
+		}
+	})
+	session := &mockSessionFormatter{
+		url:    "https://test.salesforce.com",
+		client: client,
+	}
+	tooling, err := NewResource(session, WithTooling())
+	if err != nil {
+		t.Fatalf("NewResource() error = %v", err)
+	}
+	regular, err := NewResource(session)
+	if err != nil {
+		t.Fatalf("NewResource() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		resource  *Resource
+		all       bool
+		next      string
+		wantPaths []string
+	}{
+		{
+			name:     "tooling query",
+			resource: tooling,
+			next:     "/services/data/v42.0/tooling/query/01gD0000002HU6KIAW-2000",
+			wantPaths: []string{
+				"/tooling/query/",
+				"/services/data/v42.0/tooling/query/01gD0000002HU6KIAW-2000",
+			},
+		},
+		{
+			name:     "tooling query all",
+			resource: tooling,
+			all:      true,
+			next:     "/services/data/v42.0/tooling/query/01gD0000002HU6KIAW-2000",
+			wantPaths: []string{
+				"/tooling/queryAll/",
+				"/services/data/v42.0/tooling/queryAll/01gD0000002HU6KIAW-2000",
+			},
+		},
+		{
+			name:     "tooling next records url without the prefix",
+			resource: tooling,
+			next:     "/services/data/v42.0/query/01gD0000002HU6KIAW-2000",
+			wantPaths: []string{
+				"/tooling/query/",
+				"/services/data/v42.0/tooling/query/01gD0000002HU6KIAW-2000",
+			},
+		},
+		{
+			name:     "regular query",
+			resource: regular,
+			next:     "/services/data/v42.0/query/01gD0000002HU6KIAW-2000",
+			wantPaths: []string{
+				"/query/",
+				"/services/data/v42.0/query/01gD0000002HU6KIAW-2000",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paths = nil
+			next = tt.next
+			result, err := tt.resource.QueryContext(context.Background(), &mockQuerier{stmt: "SELECT Name FROM ApexClass"}, tt.all)
+			if err != nil {
+				t.Fatalf("Resource.QueryContext() error = %v", err)
+			}
+			if _, err := result.Next(); err != nil {
+				t.Fatalf("QueryResult.Next() error = %v", err)
+			}
+			if strings.Join(paths, " ") != strings.Join(tt.wantPaths, " ") {
+				t.Errorf("requests = %v, want %v", paths, tt.wantPaths)
+			}
+		})
+	}
+}
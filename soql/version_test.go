@@ -0,0 +1,70 @@
+package soql
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/namely/go-sfdc/v3"
+)
+
+func TestResource_QueryContext_apiVersion(t *testing.T) {
+	var paths []string
+	client := mockHTTPClient(func(req *http.Request) *http.Response {
+		paths = append(paths, req.URL.Path)
+		resp := `{
+			"done": true,
+			"totalSize": 2,
+			"records": [
+				{
+					"attributes": {"type": "Account", "url": "/services/data/v58.0/sobjects/Account/001D000000IRFmaIAH"},
+					"Name": "Test 2"
+				}
+			]
+		}`
+		if req.URL.RawQuery != "" {
+			resp = `{
+				"done": false,
+				"totalSize": 2,
+				"nextRecordsUrl": "/services/data/v42.0/query/01gD0000002HU6KIAW-1",
+				"records": [
+					{
+						"attributes": {"type": "Account", "url": "/services/data/v58.0/sobjects/Account/001D000000IRFmaIAG"},
+						"Name": "Test 1"
+					}
+				]
+			}`
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(resp)),
+			Header:     make(http.Header),
+		}
+	})
+	resource, err := NewResource(&mockSessionFormatter{
+		url:    "https://na1.salesforce.com",
+		client: client,
+	})
+	if err != nil {
+		t.Fatalf("NewResource() error = %v", err)
+	}
+
+	ctx := sfdc.WithAPIVersion(context.Background(), 58)
+	result, err := resource.QueryContext(ctx, &mockQuerier{stmt: "SELECT Name FROM Account"}, false)
+	if err != nil {
+		t.Fatalf("Resource.QueryContext() error = %v", err)
+	}
+	if _, err := result.Next(); err != nil {
+		t.Fatalf("QueryResult.Next() error = %v", err)
+	}
+
+	want := []string{
+		"/services/data/v58.0/query/",
+		"/services/data/v58.0/query/01gD0000002HU6KIAW-1",
+	}
+	if strings.Join(paths, ",") != strings.Join(want, ",") {
+		t.Errorf("paths = %v, want %v", paths, want)
+	}
+}
@@ -0,0 +1,48 @@
+package sfdc
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+)
+
+// Version is the version of go-sfdc.
+const Version = "3.0.0"
+
+// UserAgent returns the User-Agent header that identifies go-sfdc, like
+// "go-sfdc/3.0.0 (+github.com/namely/go-sfdc)".  The suffix, like the name and
+// version of the application, is appended when it is not empty.
+func UserAgent(suffix string) string {
+	userAgent := "go-sfdc/" + Version + " (+github.com/namely/go-sfdc)"
+	if suffix == "" {
+		return userAgent
+	}
+	return userAgent + " " + suffix
+}
+
+type apiVersionKey struct{}
+
+// WithAPIVersion returns a context with the API version.  The API calls made
+// with the context use the version instead of the session's version, so a
+// single call can use a newer version without a new session.
+func WithAPIVersion(ctx context.Context, version int) context.Context {
+	return context.WithValue(ctx, apiVersionKey{}, version)
+}
+
+// APIVersion returns the API version of the context.
+func APIVersion(ctx context.Context) (int, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	version, ok := ctx.Value(apiVersionKey{}).(int)
+	return version, ok
+}
+
+var servicesVersionPattern = regexp.MustCompile(`/services/data/v\d+\.\d+(/|$)`)
+
+// VersionServicePath will replace the API version of the service path, like a
+// next records URL, with the version.  A path without an API version is
+// returned as is.
+func VersionServicePath(path string, version int) string {
+	return servicesVersionPattern.ReplaceAllString(path, fmt.Sprintf("/services/data/v%d.0$1", version))
+}
@@ -0,0 +1,66 @@
+package sfdc
+
+import (
+	"context"
+	"testing"
+)
+
+func TestAPIVersion(t *testing.T) {
+	if _, ok := APIVersion(context.Background()); ok {
+		t.Errorf("APIVersion() ok = true for a context without a version")
+	}
+	version, ok := APIVersion(WithAPIVersion(context.Background(), 58))
+	if ok == false || version != 58 {
+		t.Errorf("APIVersion() = %v, %v, want 58, true", version, ok)
+	}
+}
+
+func TestVersionServicePath(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		version int
+		want    string
+	}{
+		{
+			name:    "next records url",
+			path:    "/services/data/v45.0/query/01gD0000002HU6KIAW-2000",
+			version: 58,
+			want:    "/services/data/v58.0/query/01gD0000002HU6KIAW-2000",
+		},
+		{
+			name:    "absolute url",
+			path:    "https://na1.salesforce.com/services/data/v45.0/jobs/ingest/7505fEXAMPLE4C2AAM/batches",
+			version: 58,
+			want:    "https://na1.salesforce.com/services/data/v58.0/jobs/ingest/7505fEXAMPLE4C2AAM/batches",
+		},
+		{
+			name:    "service url",
+			path:    "https://na1.salesforce.com/services/data/v45.0",
+			version: 58,
+			want:    "https://na1.salesforce.com/services/data/v58.0",
+		},
+		{
+			name:    "no version",
+			path:    "/services/oauth2/token",
+			version: 58,
+			want:    "/services/oauth2/token",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VersionServicePath(tt.path, tt.version); got != tt.want {
+				t.Errorf("VersionServicePath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	if got, want := UserAgent(""), "go-sfdc/"+Version+" (+github.com/namely/go-sfdc)"; got != want {
+		t.Errorf("UserAgent() = %v, want %v", got, want)
+	}
+	if got, want := UserAgent("acme-sync/1.2"), "go-sfdc/"+Version+" (+github.com/namely/go-sfdc) acme-sync/1.2"; got != want {
+		t.Errorf("UserAgent() = %v, want %v", got, want)
+	}
+}